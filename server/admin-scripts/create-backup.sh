@@ -0,0 +1,3 @@
+#!/bin/bash
+
+curl --unix-socket /var/tmp/focalboard_local.socket http://localhost/api/v2/admin/backups -X POST
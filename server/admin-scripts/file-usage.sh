@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# file-usage.sh [team id]
+curl --unix-socket /var/tmp/focalboard_local.socket "http://localhost/api/v2/admin/files/usage?team_id=$1" -X GET
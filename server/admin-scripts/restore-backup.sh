@@ -0,0 +1,8 @@
+#!/bin/bash
+
+if [[ $# < 1 ]] ; then
+    echo 'restore-backup.sh <backup name> [passphrase]'
+    exit 1
+fi
+
+curl --unix-socket /var/tmp/focalboard_local.socket http://localhost/api/v2/admin/backups/$1/restore -X POST -H 'Content-Type: application/json' -d '{ "passphrase": "'$2'" }'
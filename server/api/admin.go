@@ -2,9 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
@@ -38,7 +39,7 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
 	auditRec.AddMeta("username", username)
 
-	if !strings.Contains(requestData.Password, "") {
+	if requestData.Password == "" {
 		a.errorResponse(w, r, model.NewErrBadRequest("password is required"))
 		return
 	}
@@ -54,3 +55,354 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	jsonStringResponse(w, http.StatusOK, "{}")
 	auditRec.Success()
 }
+
+func (a *API) handleAdminGetUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if strPage == "" {
+		strPage = defaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = defaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage < 1 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	users, err := a.app.GetUsersForAdmin(model.UserQueryOptions{
+		Page:               page,
+		PerPage:            perPage,
+		IncludeDeactivated: query.Get("include_deactivated") == "true",
+	})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(users)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleAdminUpdateUserActive(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.AdminUserActivePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminUpdateUserActive", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+	auditRec.AddMeta("active", patch.Active)
+
+	if err = a.app.UpdateUserActive(getUserID(r), userID, patch.Active); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Info("AdminUpdateUserActive", mlog.String("userID", userID), mlog.Bool("active", patch.Active))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "adminDeleteUser", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	report, err := a.app.DeleteUser(getUserID(r), userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("anonymousID", report.AnonymousID)
+	auditRec.AddMeta("anonymized", report.Anonymized)
+	auditRec.AddMeta("removed", report.Removed)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "adminExportUserData", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	if err := a.writeUserDataExport(w, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "adminRevokeUserSessions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	if err := a.app.RevokeSessionsForUser(getUserID(r), userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminUnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "adminUnlockUser", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	if err := a.app.UnlockUser(getUserID(r), userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminUpdateUserRoles(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.AdminUserRolesPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminUpdateUserRoles", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+	auditRec.AddMeta("systemAdmin", patch.SystemAdmin)
+
+	if err = a.app.UpdateUserSystemAdmin(userID, patch.SystemAdmin); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Info("AdminUpdateUserRoles", mlog.String("userID", userID), mlog.Bool("systemAdmin", patch.SystemAdmin))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminUpdateUserGuest(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.AdminUserGuestPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminUpdateUserGuest", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("userID", userID)
+	auditRec.AddMeta("guest", patch.Guest)
+	auditRec.AddMeta("guestExpireAt", patch.GuestExpireAt)
+
+	if err = a.app.UpdateUserGuest(userID, patch.Guest, patch.GuestExpireAt); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Info("AdminUpdateUserGuest", mlog.String("userID", userID), mlog.Bool("guest", patch.Guest))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetStatistics(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.app.GetSystemStatistics()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+type AdminRestoreBackupData struct {
+	Passphrase string `json:"passphrase"`
+}
+
+func (a *API) handleAdminGetBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := a.app.GetBackups()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(backups)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleAdminCreateBackup(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminCreateBackup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	backup, err := a.app.CreateBackup()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("name", backup.Name)
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRestoreBackup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// the passphrase is optional
+	var requestData AdminRestoreBackupData
+	if len(requestBody) > 0 {
+		if err = json.Unmarshal(requestBody, &requestData); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminRestoreBackup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("name", name)
+
+	result, err := a.app.RestoreBackup(name, requestData.Passphrase)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Info("AdminRestoreBackup", mlog.String("name", name), mlog.Int("boards", result.Boards))
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetFileUsage(w http.ResponseWriter, r *http.Request) {
+	// the usage of all the teams is returned if no team is given
+	teamID := r.URL.Query().Get("team_id")
+
+	usage, err := a.app.GetFileUsage(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleAdminCleanupOrphanedFiles(w http.ResponseWriter, r *http.Request) {
+	auditRec := a.makeAuditRecord(r, "adminCleanupOrphanedFiles", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	result, err := a.app.CleanupOrphanedFiles()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("files", result.Files)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Info("AdminCleanupOrphanedFiles", mlog.Int("files", result.Files), mlog.Int("size", result.Size))
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/app"
@@ -19,6 +21,7 @@ import (
 const (
 	HeaderRequestedWith    = "X-Requested-With"
 	HeaderRequestedWithXML = "XMLHttpRequest"
+	HeaderForwardedFor     = "X-Forwarded-For"
 	UploadFormFileKey      = "file"
 	True                   = "true"
 
@@ -75,6 +78,7 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	// V2 routes (ToDo: migrate these to V3 when ready to ship V3)
 	a.registerUsersRoutes(apiv2)
 	a.registerAuthRoutes(apiv2)
+	a.registerSessionsRoutes(apiv2)
 	a.registerMembersRoutes(apiv2)
 	a.registerInvitationRoutes(apiv2)
 	a.registerCategoriesRoutes(apiv2)
@@ -83,6 +87,10 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerAchivesRoutes(apiv2)
 	a.registerSubscriptionsRoutes(apiv2)
 	a.registerFilesRoutes(apiv2)
+	a.registerUploadsRoutes(apiv2)
+	a.registerShareLinksRoutes(apiv2)
+	a.registerBoardRolesRoutes(apiv2)
+	a.registerUserGroupsRoutes(apiv2)
 	a.registerOnboardingRoutes(apiv2)
 	a.registerSearchRoutes(apiv2)
 	a.registerConfigRoutes(apiv2)
@@ -94,6 +102,11 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerContentBlocksRoutes(apiv2)
 	a.registerStatisticsRoutes(apiv2)
 	a.registerComplianceRoutes(apiv2)
+	a.registerHistoryRoutes(apiv2)
+	a.registerTrashRoutes(apiv2)
+	a.registerCardsSpreadsheetRoutes(apiv2)
+	a.registerArchiveJobsRoutes(apiv2)
+	a.registerAdminRoutes(apiv2)
 
 	// V3 routes
 	a.registerCardsRoutes(apiv2)
@@ -102,8 +115,30 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerSystemRoutes(r)
 }
 
+// RegisterAdminRoutes registers the admin APIs served over the local unix
+// socket.
 func (a *API) RegisterAdminRoutes(r *mux.Router) {
-	r.HandleFunc("/api/v2/admin/users/{username}/password", a.adminRequired(a.handleAdminSetPassword)).Methods("POST")
+	a.registerAdminRoutes(r.PathPrefix("/api/v2").Subrouter())
+}
+
+// registerAdminRoutes registers the admin APIs, available to the system
+// admins and to the local unix connections.
+func (a *API) registerAdminRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/users", a.adminRequired(a.handleAdminGetUsers)).Methods("GET")
+	r.HandleFunc("/admin/users/{username}/password", a.adminRequired(a.handleAdminSetPassword)).Methods("POST")
+	r.HandleFunc("/admin/users/{userID}", a.adminRequired(a.handleAdminDeleteUser)).Methods("DELETE")
+	r.HandleFunc("/admin/users/{userID}/export", a.adminRequired(a.handleAdminExportUserData)).Methods("GET")
+	r.HandleFunc("/admin/users/{userID}/sessions", a.adminRequired(a.localSessionsRequired(a.handleAdminRevokeUserSessions))).Methods("DELETE")
+	r.HandleFunc("/admin/users/{userID}/unlock", a.adminRequired(a.localSessionsRequired(a.handleAdminUnlockUser))).Methods("POST")
+	r.HandleFunc("/admin/users/{userID}/active", a.adminRequired(a.handleAdminUpdateUserActive)).Methods("PUT")
+	r.HandleFunc("/admin/users/{userID}/roles", a.adminRequired(a.handleAdminUpdateUserRoles)).Methods("PUT")
+	r.HandleFunc("/admin/users/{userID}/guest", a.adminRequired(a.handleAdminUpdateUserGuest)).Methods("PUT")
+	r.HandleFunc("/admin/statistics", a.adminRequired(a.handleAdminGetStatistics)).Methods("GET")
+	r.HandleFunc("/admin/backups", a.adminRequired(a.handleAdminGetBackups)).Methods("GET")
+	r.HandleFunc("/admin/backups", a.adminRequired(a.handleAdminCreateBackup)).Methods("POST")
+	r.HandleFunc("/admin/backups/{name}/restore", a.adminRequired(a.handleAdminRestoreBackup)).Methods("POST")
+	r.HandleFunc("/admin/files/usage", a.adminRequired(a.handleAdminGetFileUsage)).Methods("GET")
+	r.HandleFunc("/admin/files/cleanup", a.adminRequired(a.handleAdminCleanupOrphanedFiles)).Methods("POST")
 }
 
 func getUserID(r *http.Request) string {
@@ -145,25 +180,49 @@ func (a *API) requireCSRFToken(next http.Handler) http.Handler {
 }
 
 func (a *API) checkCSRFToken(r *http.Request) bool {
+	// signed file URLs are authenticated by their signature instead of
+	// the session, and can be opened from emails or external tools
+	if isSignedFileRequest(r) {
+		return true
+	}
+
 	token := r.Header.Get(HeaderRequestedWith)
 	return token == HeaderRequestedWithXML
 }
 
-func (a *API) hasValidReadTokenForBoard(r *http.Request, boardID string) bool {
+func isSignedFileRequest(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		strings.Contains(r.URL.Path, "/files/teams/") &&
+		r.URL.Query().Get(model.SignedURLSignatureParam) != ""
+}
+
+// getReadTokenAccessForBoard validates the read token of a request, and
+// returns the share link it opens, if any. Share links may restrict what
+// is shared of the board.
+func (a *API) getReadTokenAccessForBoard(r *http.Request, boardID string) (bool, *model.ShareLink) {
 	query := r.URL.Query()
 	readToken := query.Get("read_token")
 
 	if len(readToken) < 1 {
-		return false
+		return false, nil
+	}
+
+	link, err := a.app.GetShareLinkForReadToken(boardID, readToken)
+	if err != nil {
+		a.logger.Error("GetShareLinkForReadToken ERROR", mlog.Err(err))
+		return false, nil
+	}
+	if link != nil {
+		return true, link
 	}
 
 	isValid, err := a.app.IsValidReadToken(boardID, readToken)
 	if err != nil {
 		a.logger.Error("IsValidReadTokenForBoard ERROR", mlog.Err(err))
-		return false
+		return false, nil
 	}
 
-	return isValid
+	return isValid, nil
 }
 
 func (a *API) userIsGuest(userID string) (bool, error) {
@@ -188,10 +247,18 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 		errorResponse.ErrorCode = http.StatusForbidden
 	case model.IsErrNotFound(err):
 		errorResponse.ErrorCode = http.StatusNotFound
+	case model.IsErrConflict(err):
+		errorResponse.ErrorCode = http.StatusConflict
 	case model.IsErrRequestEntityTooLarge(err):
 		errorResponse.ErrorCode = http.StatusRequestEntityTooLarge
 	case model.IsErrNotImplemented(err):
 		errorResponse.ErrorCode = http.StatusNotImplemented
+	case model.IsErrTooManyRequests(err):
+		errorResponse.ErrorCode = http.StatusTooManyRequests
+		var tmr *model.ErrTooManyRequests
+		if errors.As(err, &tmr) && tmr.RetryAfter > 0 {
+			setResponseHeader(w, "Retry-After", strconv.FormatInt(tmr.RetryAfter, 10))
+		}
 	default:
 		a.logger.Error("API ERROR",
 			mlog.Int("code", http.StatusInternalServerError),
@@ -75,3 +75,35 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestClientIP(t *testing.T) {
+	trustedProxies := []string{"10.0.0.1", "192.168.0.0/16", "invalid"}
+
+	testCases := []struct {
+		Name           string
+		RemoteAddr     string
+		ForwardedFor   []string
+		ExpectedIP     string
+		TrustedProxies []string
+	}{
+		{"no proxy", "203.0.113.1:1234", nil, "203.0.113.1", trustedProxies},
+		{"the header of an untrusted peer is ignored", "203.0.113.1:1234", []string{"198.51.100.1"}, "203.0.113.1", trustedProxies},
+		{"the client behind a trusted proxy", "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1", trustedProxies},
+		{"the addresses set by the client are ignored", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1", trustedProxies},
+		{"a chain of trusted proxies", "10.0.0.1:1234", []string{"198.51.100.1, 192.168.1.1", "192.168.1.2"}, "198.51.100.1", trustedProxies},
+		{"only trusted proxies", "10.0.0.1:1234", []string{"192.168.1.1"}, "192.168.1.1", trustedProxies},
+		{"an invalid forwarded address", "10.0.0.1:1234", []string{"not-an-ip"}, "10.0.0.1", trustedProxies},
+		{"no trusted proxies", "10.0.0.1:1234", []string{"198.51.100.1"}, "10.0.0.1", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.RemoteAddr
+			for _, header := range tc.ForwardedFor {
+				r.Header.Add(HeaderForwardedFor, header)
+			}
+			require.Equal(t, tc.ExpectedIP, clientIP(r, tc.TrustedProxies))
+		})
+	}
+}
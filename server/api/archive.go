@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/importer"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -22,6 +24,7 @@ func (a *API) registerAchivesRoutes(r *mux.Router) {
 	r.HandleFunc("/boards/{boardID}/archive/export", a.sessionRequired(a.handleArchiveExportBoard)).Methods("GET")
 	r.HandleFunc("/teams/{teamID}/archive/import", a.sessionRequired(a.handleArchiveImport)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/archive/export", a.sessionRequired(a.handleArchiveExportTeam)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/import/{source}", a.sessionRequired(a.handleImportExternal)).Methods("POST")
 }
 
 func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
@@ -56,7 +59,9 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 	boardID := vars["boardID"]
 	userID := getUserID(r)
 
-	// check user has permission to board
+	// check user has permission to board, the compliance exports include
+	// all the cards of the board
+	exportUserID := userID
 	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
 		// if this user has `manage_system` permission and there is a license with the compliance
 		// feature enabled, then we will allow the export.
@@ -65,6 +70,7 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 			a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
 			return
 		}
+		exportUserID = ""
 	}
 
 	auditRec := a.makeAuditRecord(r, "archiveExportBoard", audit.Fail)
@@ -80,6 +86,7 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 	opts := model.ExportArchiveOptions{
 		TeamID:   board.TeamID,
 		BoardIDs: []string{board.ID},
+		UserID:   exportUserID,
 	}
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
@@ -97,7 +104,12 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/archive/import archiveImport
 	//
-	// Import an archive of boards.
+	// Import an archive of boards. The mode defines what happens to the
+	// boards of the archive that already exist: create imports them as new
+	// boards, overwrite replaces the existing boards and merge only updates
+	// what is older than the archive, reporting the conflicts. With async,
+	// the archive is imported in the background and the import job is
+	// returned.
 	//
 	// ---
 	// produces:
@@ -110,6 +122,16 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	//   description: Team ID
 	//   required: true
 	//   type: string
+	// - name: mode
+	//   in: query
+	//   description: The import mode, create (default), overwrite or merge
+	//   required: false
+	//   type: string
+	// - name: async
+	//   in: query
+	//   description: Import the archive in a background job
+	//   required: false
+	//   type: boolean
 	// - name: file
 	//   in: formData
 	//   description: archive file to import
@@ -119,7 +141,9 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	// - BearerAuth: []
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success, the import result or the job with async
+	//     schema:
+	//       "$ref": "#/definitions/ImportArchiveResult"
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -147,6 +171,12 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mode, err := model.ParseImportMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
 	file, handle, err := r.FormFile(UploadFormFileKey)
 	if err != nil {
 		fmt.Fprintf(w, "%v", err)
@@ -158,13 +188,35 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("filename", handle.Filename)
 	auditRec.AddMeta("size", handle.Size)
+	auditRec.AddMeta("mode", mode)
+
+	if r.URL.Query().Get("async") == "true" {
+		job, err := a.app.StartArchiveImportJob(teamID, userID, mode, file)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+
+		data, err := json.Marshal(job)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+
+		jsonBytesResponse(w, http.StatusOK, data)
+		auditRec.AddMeta("jobID", job.ID)
+		auditRec.Success()
+		return
+	}
 
 	opt := model.ImportArchiveOptions{
 		TeamID:     teamID,
 		ModifiedBy: userID,
+		Mode:       mode,
 	}
 
-	if err := a.app.ImportArchive(file, opt); err != nil {
+	result, err := a.app.ImportArchive(file, opt)
+	if err != nil {
 		a.logger.Debug("Error importing archive",
 			mlog.String("team_id", teamID),
 			mlog.Err(err),
@@ -173,7 +225,14 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonStringResponse(w, http.StatusOK, "{}")
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("conflicts", len(result.Conflicts))
 	auditRec.Success()
 }
 
@@ -226,7 +285,7 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID, !isGuest)
+	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID, !isGuest, true)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -239,6 +298,7 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	opts := model.ExportArchiveOptions{
 		TeamID:   teamID,
 		BoardIDs: ids,
+		UserID:   userID,
 	}
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
@@ -252,3 +312,107 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+func (a *API) handleImportExternal(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/import/{source} importExternal
+	//
+	// Import the export of another tool as new boards. Trello JSON, Jira
+	// XML or CSV, Asana JSON and Notion CSV or zip exports are supported.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: source
+	//   in: path
+	//   description: The tool the export was generated by, one of trello, jira, asana or notion
+	//   required: true
+	//   type: string
+	// - name: file
+	//   in: formData
+	//   description: export file to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ImportReport"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	source := vars["source"]
+
+	if !importer.IsValidSource(source) {
+		a.errorResponse(w, r, model.NewErrBadRequest("unsupported import source "+source))
+		return
+	}
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board"))
+		return
+	}
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if isGuest {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board"))
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	auditRec := a.makeAuditRecord(r, "importExternal", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("source", source)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("size", handle.Size)
+
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: userID,
+	}
+
+	report, err := a.app.ImportExternal(importer.Source(source), file, handle.Filename, opt)
+	if err != nil {
+		a.logger.Debug("Error importing external export",
+			mlog.String("team_id", teamID),
+			mlog.String("source", source),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("boardCount", len(report.BoardIDs))
+	auditRec.Success()
+}
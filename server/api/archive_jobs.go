@@ -0,0 +1,293 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerArchiveJobsRoutes(r *mux.Router) {
+	// Archive jobs APIs
+	r.HandleFunc("/teams/{teamID}/archive/export", a.sessionRequired(a.handleStartArchiveExportJob)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/archive/jobs", a.sessionRequired(a.handleGetArchiveJobs)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/jobs/{jobID}", a.sessionRequired(a.handleGetArchiveJob)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/archive/jobs/{jobID}/download", a.sessionRequired(a.handleDownloadArchiveJob)).Methods("GET")
+}
+
+func (a *API) handleStartArchiveExportJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/archive/export startArchiveExportJob
+	//
+	// Starts exporting an archive of all the boards in a team in the
+	// background. The archive can be downloaded once the job succeeded.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ArchiveJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "startArchiveExportJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("TeamID", teamID)
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID, !isGuest, true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	ids := []string{}
+	for _, board := range boards {
+		ids = append(ids, board.ID)
+	}
+
+	job, err := a.app.StartArchiveExportJob(teamID, userID, ids)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(archiveJobResponse(job))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("jobID", job.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetArchiveJobs(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/jobs getArchiveJobs
+	//
+	// Returns the archive jobs started by the current user in a team,
+	// latest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/ArchiveJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	jobs, err := a.app.GetArchiveJobsForUser(teamID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	for _, job := range jobs {
+		archiveJobResponse(job)
+	}
+
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetArchiveJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/jobs/{jobID} getArchiveJob
+	//
+	// Returns the status of an archive job.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Job ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ArchiveJob"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	job, err := a.getArchiveJobForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(archiveJobResponse(job))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleDownloadArchiveJob(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/archive/jobs/{jobID}/download downloadArchiveJob
+	//
+	// Downloads the archive exported by a job.
+	//
+	// ---
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: jobID
+	//   in: path
+	//   description: Job ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: string
+	//       format: binary
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	job, err := a.getArchiveJobForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "downloadArchiveJob", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("jobID", job.ID)
+
+	file, err := a.app.GetArchiveJobFile(job)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	filename := fmt.Sprintf("archive-%s%s", job.ID, archiveExtension)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+
+	if _, err := io.Copy(w, file); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+// getArchiveJobForUser returns the job of the request. Jobs are only
+// visible to the user that started them.
+func (a *API) getArchiveJobForUser(r *http.Request) (*model.ArchiveJob, error) {
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	jobID := vars["jobID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		return nil, model.NewErrPermission("access denied to team")
+	}
+
+	job, err := a.app.GetArchiveJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.TeamID != teamID {
+		return nil, model.NewErrNotFound("archive job ID=" + jobID)
+	}
+	if job.CreatedBy != userID {
+		return nil, model.NewErrPermission("access denied to archive job")
+	}
+	return job, nil
+}
+
+// archiveJobResponse sets the download URL of finished export jobs.
+func archiveJobResponse(job *model.ArchiveJob) *model.ArchiveJob {
+	if job.Type == model.ArchiveJobTypeExport && job.Status == model.ArchiveJobStatusSuccess {
+		job.DownloadURL = fmt.Sprintf("/api/v2/teams/%s/archive/jobs/%s/download", job.TeamID, job.ID)
+	}
+	return job
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net"
 	"net/http"
@@ -20,6 +21,7 @@ import (
 func (a *API) registerAuthRoutes(r *mux.Router) {
 	// personal-server specific routes. These are not needed in plugin mode.
 	r.HandleFunc("/login", a.handleLogin).Methods("POST")
+	r.HandleFunc("/login/unlock", a.handleUnlockAccount).Methods("POST")
 	r.HandleFunc("/logout", a.sessionRequired(a.handleLogout)).Methods("POST")
 	r.HandleFunc("/register", a.handleRegister).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/regenerate_signup_token", a.sessionRequired(a.handlePostTeamRegenerateSignupToken)).Methods("POST")
@@ -50,6 +52,10 @@ func (a *API) handleLogin(w http.ResponseWriter, r *http.Request) {
 	//     description: invalid login
 	//     schema:
 	//       "$ref": "#/definitions/ErrorResponse"
+	//   '429':
+	//     description: too many failed login attempts
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
 	//   '500':
 	//     description: internal error
 	//     schema:
@@ -84,8 +90,18 @@ func (a *API) handleLogin(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("type", loginData.Type)
 
 	if loginData.Type == "normal" {
-		token, err := a.app.Login(loginData.Username, loginData.Email, loginData.Password, loginData.MfaToken)
+		client := model.SessionClient{UserAgent: r.UserAgent(), IPAddress: a.getClientIP(r)}
+		token, err := a.app.Login(loginData.Username, loginData.Email, loginData.Password, loginData.MfaToken, client)
 		if err != nil {
+			var lockout *model.ErrLoginLockout
+			if errors.As(err, &lockout) {
+				a.auditLoginLockout(r, lockout)
+			}
+			if model.IsErrTooManyRequests(err) {
+				auditRec.AddMeta("throttled", true)
+				a.errorResponse(w, r, err)
+				return
+			}
 			a.errorResponse(w, r, model.NewErrUnauthorized("incorrect login"))
 			return
 		}
@@ -103,6 +119,86 @@ func (a *API) handleLogin(w http.ResponseWriter, r *http.Request) {
 	a.errorResponse(w, r, model.NewErrBadRequest("invalid login type"))
 }
 
+// auditLoginLockout logs an audit record for each account or client IP
+// address locked out by a failed login.
+func (a *API) auditLoginLockout(r *http.Request, lockout *model.ErrLoginLockout) {
+	for _, attempt := range lockout.Attempts {
+		rec := a.makeAuditRecord(r, "loginLockout", audit.Success)
+		rec.AddMeta("kind", attempt.Kind)
+		rec.AddMeta("identifier", attempt.Identifier)
+		rec.AddMeta("failedCount", attempt.FailedCount)
+		rec.AddMeta("lockedUntil", attempt.LockedUntil)
+		a.audit.LogRecord(audit.LevelAuth, rec)
+	}
+}
+
+func (a *API) handleUnlockAccount(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /login/unlock unlockAccount
+	//
+	// Unlocks an account locked out after too many failed logins, with the
+	// token sent by email. The link of the email opens the unlock page of
+	// the webapp, which sends this request
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   description: Unlock request
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AccountUnlockRequest"
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: invalid unlock token
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	//   '500':
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	if len(a.singleUserToken) > 0 {
+		// Not permitted in single-user mode
+		a.errorResponse(w, r, model.NewErrUnauthorized("not permitted in single-user mode"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var unlockData model.AccountUnlockRequest
+	if err = json.Unmarshal(requestBody, &unlockData); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "unlockAccount", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	attempt, err := a.app.UnlockAccount(unlockData.Token)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.AddMeta("kind", attempt.Kind)
+	auditRec.AddMeta("identifier", attempt.Identifier)
+	auditRec.Success()
+}
+
 func (a *API) handleLogout(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /logout logout
 	//
@@ -200,17 +296,25 @@ func (a *API) handleRegister(w http.ResponseWriter, r *http.Request) {
 	registerData.Email = strings.TrimSpace(registerData.Email)
 	registerData.Username = strings.TrimSpace(registerData.Username)
 
-	// Validate token
-	if len(registerData.Token) > 0 {
-		team, err2 := a.app.GetRootTeam()
-		if err2 != nil {
-			a.errorResponse(w, r, err2)
-			return
-		}
+	// Validate token, the signup token of a team registers the new user
+	// to the team, and the first user is registered to the root team
+	team, err := a.app.GetRootTeam()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
+	if len(registerData.Token) > 0 {
 		if registerData.Token != team.SignupToken {
-			a.errorResponse(w, r, model.NewErrUnauthorized("invalid token"))
-			return
+			team, err = a.app.GetTeamBySignupToken(registerData.Token)
+			if model.IsErrNotFound(err) {
+				a.errorResponse(w, r, model.NewErrUnauthorized("invalid token"))
+				return
+			}
+			if err != nil {
+				a.errorResponse(w, r, err)
+				return
+			}
 		}
 	} else {
 		// No signup token, check if no active users
@@ -233,8 +337,9 @@ func (a *API) handleRegister(w http.ResponseWriter, r *http.Request) {
 	auditRec := a.makeAuditRecord(r, "register", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
 	auditRec.AddMeta("username", registerData.Username)
+	auditRec.AddMeta("teamID", team.ID)
 
-	err = a.app.RegisterUser(registerData.Username, registerData.Email, registerData.Password)
+	err = a.app.RegisterUser(registerData.Username, registerData.Email, registerData.Password, team.ID)
 	if err != nil {
 		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
 		return
@@ -397,14 +502,100 @@ func (a *API) attachSession(handler func(w http.ResponseWriter, r *http.Request)
 }
 
 func (a *API) adminRequired(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	systemAdminHandler := a.systemAdminRequired(handler)
+
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Currently, admin APIs require local unix connections
+		// The local unix connections are trusted, as a break-glass option
+		// to administer the server without a system admin session
 		conn := GetContextConn(r)
-		if _, isUnix := conn.(*net.UnixConn); !isUnix {
-			a.errorResponse(w, r, model.NewErrUnauthorized("not a local unix connection"))
+		if _, isUnix := conn.(*net.UnixConn); isUnix {
+			handler(w, r)
+			return
+		}
+
+		systemAdminHandler(w, r)
+	}
+}
+
+func (a *API) systemAdminRequired(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return a.sessionRequired(func(w http.ResponseWriter, r *http.Request) {
+		if !a.permissions.HasPermissionTo(getUserID(r), model.PermissionManageSystem) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to system administration"))
 			return
 		}
 
 		handler(w, r)
+	})
+}
+
+// getClientIP returns the IP address of the client of a request, without
+// its port. Behind the trusted proxies, the client is the last address of
+// the X-Forwarded-For header that isn't a trusted proxy, as the addresses
+// before it can be set by the client.
+func (a *API) getClientIP(r *http.Request) string {
+	return clientIP(r, a.app.GetConfig().TrustedProxies)
+}
+
+func clientIP(r *http.Request, proxies []string) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	trustedProxies := parseTrustedProxies(proxies)
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values(HeaderForwardedFor) {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(forwardedIP) == nil {
+			break
+		}
+		ip = forwardedIP
+		if !isTrustedProxy(ip, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// parseTrustedProxies parses the IPs and CIDRs of the trusted proxies,
+// ignoring the invalid ones.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip = ip.To4()
+					bits = 8 * net.IPv4len
+				}
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
 	}
+	return false
 }
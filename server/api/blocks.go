@@ -74,7 +74,7 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 
 	userID := getUserID(r)
 
-	hasValidReadToken := a.hasValidReadTokenForBoard(r, boardID)
+	hasValidReadToken, shareLink := a.getReadTokenAccessForBoard(r, boardID)
 	if userID == "" && !hasValidReadToken {
 		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to board"))
 		return
@@ -151,6 +151,23 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if hasValidReadToken && shareLink != nil {
+		auditRec.AddMeta("shareLinkID", shareLink.ID)
+		blocks, err = a.app.GetBlocksForShareLink(board, shareLink, blocks)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
+	// the private cards and the restricted properties are hidden to the
+	// users that can't view them, including the share link visitors
+	blocks, err = a.app.FilterBlocksForUser(board, userID, blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetBlocks",
 		mlog.String("boardID", boardID),
 		mlog.String("parentID", parentID),
@@ -282,6 +299,16 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.CheckBlocksForUser(board, userID, blocks); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	blocks = model.GenerateBlockIDs(blocks, a.logger)
 
 	auditRec := a.makeAuditRecord(r, "postBlocks", audit.Fail)
@@ -384,6 +411,10 @@ func (a *API) handleDeleteBlock(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, model.NewErrNotFound(message))
 		return
 	}
+	if !a.app.CanViewBlock(userID, block) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to private card"))
+		return
+	}
 
 	auditRec := a.makeAuditRecord(r, "deleteBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
@@ -466,6 +497,15 @@ func (a *API) handleUndeleteBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the block is checked as restored, as the deleted blocks aren't
+	// filtered
+	restoredBlock := *block
+	restoredBlock.DeleteAt = 0
+	if !a.app.CanViewBlock(userID, &restoredBlock) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to private card"))
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "undeleteBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("blockID", blockID)
@@ -476,6 +516,10 @@ func (a *API) handleUndeleteBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if undeletedBlock != nil {
+		undeletedBlock = a.app.FilterBlockForUser(userID, undeletedBlock)
+	}
+
 	undeletedBlockData, err := json.Marshal(undeletedBlock)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -538,7 +582,8 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	val := r.URL.Query().Get("disable_notify")
 	disableNotify := val == True
 
-	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) &&
+		!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionMoveBoardCards) {
 		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
 		return
 	}
@@ -567,6 +612,15 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !a.hasPermissionToPatchBlock(userID, block, patch) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+	if err = a.app.PrepareBlockPatchForUser(userID, block, patch); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "patchBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("boardID", boardID)
@@ -647,17 +701,25 @@ func (a *API) handlePatchBlocks(w http.ResponseWriter, r *http.Request) {
 		auditRec.AddMeta("block_"+strconv.FormatInt(int64(i), 10), patches.BlockIDs[i])
 	}
 
-	for _, blockID := range patches.BlockIDs {
+	for i, blockID := range patches.BlockIDs {
 		var block *model.Block
 		block, err = a.app.GetBlockByID(blockID)
 		if err != nil {
 			a.errorResponse(w, r, model.NewErrForbidden("access denied to make board changes"))
 			return
 		}
-		if !a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionManageBoardCards) {
+		var patch *model.BlockPatch
+		if i < len(patches.BlockPatches) {
+			patch = &patches.BlockPatches[i]
+		}
+		if !a.hasPermissionToPatchBlock(userID, block, patch) {
 			a.errorResponse(w, r, model.NewErrPermission("access denied to make board changesa"))
 			return
 		}
+		if err = a.app.PrepareBlockPatchForUser(userID, block, patch); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
 	}
 
 	err = a.app.PatchBlocksAndNotify(teamID, patches, userID, disableNotify)
@@ -747,6 +809,10 @@ func (a *API) handleDuplicateBlock(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if !a.app.CanViewBlock(userID, block) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to private card"))
+		return
+	}
 
 	auditRec := a.makeAuditRecord(r, "duplicateBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
@@ -764,6 +830,12 @@ func (a *API) handleDuplicateBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	blocks, err = a.app.FilterBlocksForUser(board, userID, blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	data, err := json.Marshal(blocks)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -775,3 +847,14 @@ func (a *API) handleDuplicateBlock(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+// hasPermissionToPatchBlock checks that a user can apply a patch to a
+// block. Moving a card, ie. only changing its property values, requires
+// the permission to move cards instead of the permission to manage them.
+func (a *API) hasPermissionToPatchBlock(userID string, block *model.Block, patch *model.BlockPatch) bool {
+	if a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionManageBoardCards) {
+		return true
+	}
+	return block.Type == model.TypeCard && patch != nil && patch.OnlyUpdatesProperties() &&
+		a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionMoveBoardCards)
+}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerBoardRolesRoutes(r *mux.Router) {
+	// Custom board roles APIs
+	r.HandleFunc("/teams/{teamID}/board-roles", a.sessionRequired(a.handleGetCustomBoardRoles)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/board-roles", a.sessionRequired(a.handleCreateCustomBoardRole)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/board-roles/{roleID}", a.sessionRequired(a.handlePatchCustomBoardRole)).Methods("PATCH")
+	r.HandleFunc("/teams/{teamID}/board-roles/{roleID}", a.sessionRequired(a.handleDeleteCustomBoardRole)).Methods("DELETE")
+}
+
+func (a *API) handleGetCustomBoardRoles(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/board-roles getCustomBoardRoles
+	//
+	// Returns the custom board roles of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/CustomBoardRole"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCustomBoardRoles", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	roles, err := a.app.GetCustomBoardRolesForTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(roles)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("roleCount", len(roles))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateCustomBoardRole(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/board-roles createCustomBoardRole
+	//
+	// Creates a custom board role, a named set of board permissions that
+	// can be assigned to the members of the boards of the team.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the name and the permissions of the role
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CustomBoardRole"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CustomBoardRole"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the board roles of the team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var role model.CustomBoardRole
+	if err = json.Unmarshal(requestBody, &role); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	role.TeamID = teamID
+
+	auditRec := a.makeAuditRecord(r, "createCustomBoardRole", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("name", role.Name)
+
+	created, err := a.app.CreateCustomBoardRole(&role, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("CreateCustomBoardRole", mlog.String("teamID", teamID), mlog.String("roleID", created.ID))
+	auditRec.AddMeta("roleID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchCustomBoardRole(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /teams/{teamID}/board-roles/{roleID} patchCustomBoardRole
+	//
+	// Updates a custom board role. The changes apply to the board members
+	// the role is assigned to.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: roleID
+	//   in: path
+	//   description: Custom board role ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the custom board role patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CustomBoardRolePatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CustomBoardRole"
+	//   '404':
+	//     description: custom board role not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	role, err := a.getCustomBoardRoleOfTeam(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.CustomBoardRolePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchCustomBoardRole", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", role.TeamID)
+	auditRec.AddMeta("roleID", role.ID)
+
+	updated, err := a.app.PatchCustomBoardRole(role, &patch)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCustomBoardRole(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/board-roles/{roleID} deleteCustomBoardRole
+	//
+	// Deletes a custom board role. The board members it was assigned to
+	// keep their scheme roles.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: roleID
+	//   in: path
+	//   description: Custom board role ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: custom board role not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	role, err := a.getCustomBoardRoleOfTeam(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCustomBoardRole", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", role.TeamID)
+	auditRec.AddMeta("roleID", role.ID)
+
+	if err = a.app.DeleteCustomBoardRole(role.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("DeleteCustomBoardRole", mlog.String("teamID", role.TeamID), mlog.String("roleID", role.ID))
+	auditRec.Success()
+}
+
+// getCustomBoardRoleOfTeam returns the custom board role of a request,
+// checking that the user can manage the team and that the role belongs
+// to it.
+func (a *API) getCustomBoardRoleOfTeam(r *http.Request) (*model.CustomBoardRole, error) {
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(getUserID(r), teamID, model.PermissionManageTeam) {
+		return nil, model.NewErrPermission("access denied to manage the board roles of the team")
+	}
+
+	role, err := a.app.GetCustomBoardRole(vars["roleID"])
+	if err != nil {
+		return nil, err
+	}
+	if role.TeamID != teamID {
+		return nil, model.NewErrNotFound("custom board role ID=" + role.ID)
+	}
+	return role, nil
+}
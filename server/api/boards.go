@@ -14,12 +14,15 @@ import (
 
 func (a *API) registerBoardsRoutes(r *mux.Router) {
 	r.HandleFunc("/teams/{teamID}/boards", a.sessionRequired(a.handleGetBoards)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/boards/archived", a.sessionRequired(a.handleGetArchivedBoards)).Methods("GET")
 	r.HandleFunc("/boards", a.sessionRequired(a.handleCreateBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}", a.attachSession(a.handleGetBoard, false)).Methods("GET")
 	r.HandleFunc("/boards/{boardID}", a.sessionRequired(a.handlePatchBoard)).Methods("PATCH")
 	r.HandleFunc("/boards/{boardID}", a.sessionRequired(a.handleDeleteBoard)).Methods("DELETE")
 	r.HandleFunc("/boards/{boardID}/duplicate", a.sessionRequired(a.handleDuplicateBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/undelete", a.sessionRequired(a.handleUndeleteBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/archive", a.sessionRequired(a.handleArchiveBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/unarchive", a.sessionRequired(a.handleUnarchiveBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/metadata", a.sessionRequired(a.handleGetBoardMetadata)).Methods("GET")
 }
 
@@ -37,6 +40,11 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 	//   description: Team ID
 	//   required: true
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Include the archived boards (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -70,7 +78,8 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// retrieve boards list
-	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID, !isGuest)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	boards, err := a.app.GetBoardsForUserAndTeam(userID, teamID, !isGuest, includeArchived)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -94,6 +103,75 @@ func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleGetArchivedBoards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/boards/archived getArchivedBoards
+	//
+	// Returns the archived boards of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getArchivedBoards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	boards, err := a.app.GetArchivedBoardsForUserAndTeam(userID, teamID, !isGuest)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetArchivedBoards",
+		mlog.String("teamID", teamID),
+		mlog.Int("boardsCount", len(boards)),
+	)
+
+	data, err := json.Marshal(boards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardsCount", len(boards))
+	auditRec.Success()
+}
+
 func (a *API) handleCreateBoard(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /boards createBoard
 	//
@@ -224,7 +302,7 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 	boardID := mux.Vars(r)["boardID"]
 	userID := getUserID(r)
 
-	hasValidReadToken := a.hasValidReadTokenForBoard(r, boardID)
+	hasValidReadToken, shareLink := a.getReadTokenAccessForBoard(r, boardID)
 	if userID == "" && !hasValidReadToken {
 		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to board"))
 		return
@@ -271,6 +349,14 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 		mlog.String("boardID", boardID),
 	)
 
+	if hasValidReadToken && shareLink != nil {
+		auditRec.AddMeta("shareLinkID", shareLink.ID)
+		board = a.app.GetBoardForShareLink(board, shareLink)
+		if err = a.app.RecordShareLinkView(shareLink); err != nil {
+			a.logger.Warn("Cannot record the view of a share link", mlog.String("linkID", shareLink.ID), mlog.Err(err))
+		}
+	}
+
 	data, err := json.Marshal(board)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -318,7 +404,8 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//       "$ref": "#/definitions/ErrorResponse"
 
 	boardID := mux.Vars(r)["boardID"]
-	if _, err := a.app.GetBoard(boardID); err != nil {
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
@@ -359,6 +446,12 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if patch.ChangesRestrictedProperties(board) {
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewRestrictedProps) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to modifying restricted card properties"))
+			return
+		}
+	}
 
 	auditRec := a.makeAuditRecord(r, "patchBoard", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
@@ -603,6 +696,124 @@ func (a *API) handleUndeleteBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleArchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/archive archiveBoard
+	//
+	// Archives a board. Archived boards are read-only and are not listed with the rest of the team boards
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionArchiveBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to archive board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "archiveBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	board, err := a.app.ArchiveBoard(boardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("ArchiveBoard", mlog.String("boardID", boardID))
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleUnarchiveBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/unarchive unarchiveBoard
+	//
+	// Restores an archived board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Board"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionArchiveBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to unarchive board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "unarchiveBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	board, err := a.app.UnarchiveBoard(boardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("UnarchiveBoard", mlog.String("boardID", boardID))
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handleGetBoardMetadata(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /boards/{boardID}/metadata getBoardMetadata
 	//
@@ -245,6 +245,12 @@ func (a *API) handlePatchBoardsAndBlocks(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		if patch.ChangesRestrictedProperties(board) &&
+			!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewRestrictedProps) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to modifying restricted card properties"))
+			return
+		}
+
 		if teamID == "" {
 			teamID = board.TeamID
 		}
@@ -254,7 +260,7 @@ func (a *API) handlePatchBoardsAndBlocks(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	for _, blockID := range pbab.BlockIDs {
+	for i, blockID := range pbab.BlockIDs {
 		block, err2 := a.app.GetBlockByID(blockID)
 		if err2 != nil {
 			a.errorResponse(w, r, err2)
@@ -270,6 +276,15 @@ func (a *API) handlePatchBoardsAndBlocks(w http.ResponseWriter, r *http.Request)
 			a.errorResponse(w, r, model.NewErrPermission("access denied to modifying cards"))
 			return
 		}
+
+		var patch *model.BlockPatch
+		if i < len(pbab.BlockPatches) {
+			patch = pbab.BlockPatches[i]
+		}
+		if err2 = a.app.PrepareBlockPatchForUser(userID, block, patch); err2 != nil {
+			a.errorResponse(w, r, err2)
+			return
+		}
 	}
 
 	auditRec := a.makeAuditRecord(r, "patchBoardsAndBlocks", audit.Fail)
@@ -382,6 +397,11 @@ func (a *API) handleDeleteBoardsAndBlocks(w http.ResponseWriter, r *http.Request
 			a.errorResponse(w, r, model.NewErrPermission("access denied to modifying cards"))
 			return
 		}
+
+		if !a.app.CanViewBlock(userID, block) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to private card"))
+			return
+		}
 	}
 
 	if err := dbab.IsValid(); err != nil {
@@ -98,6 +98,16 @@ func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.CheckBlocksForUser(board, userID, []*model.Block{model.Card2Block(newCard)}); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "createCard", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("boardID", boardID)
@@ -201,12 +211,24 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("page", page)
 	auditRec.AddMeta("per_page", perPage)
 
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	cards, err := a.app.GetCardsForBoard(boardID, page, perPage)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
+	cards, err = a.app.FilterCardsForUser(board, userID, cards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetCards",
 		mlog.String("boardID", boardID),
 		mlog.String("userID", userID),
@@ -283,14 +305,29 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+	var patch *model.CardPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// moving a card only changes its property values, which requires the
+	// permission to move cards instead of the permission to manage them
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) &&
+		!(patch != nil && patch.OnlyUpdatesProperties() &&
+			a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionMoveBoardCards)) {
 		a.errorResponse(w, r, model.NewErrPermission("access denied to patch card"))
 		return
 	}
 
-	var patch *model.CardPatch
-	if err = json.Unmarshal(requestBody, &patch); err != nil {
-		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+	// the card patch replaces all the property values of the card, like
+	// the block patch it is converted to
+	propertiesPatch := &model.BlockPatch{}
+	if patch != nil && len(patch.UpdatedProperties) > 0 {
+		propertiesPatch.UpdatedFields = map[string]interface{}{"properties": patch.UpdatedProperties}
+	}
+	if err = a.app.PrepareBlockPatchForUser(userID, model.Card2Block(card), propertiesPatch); err != nil {
+		a.errorResponse(w, r, err)
 		return
 	}
 
@@ -312,7 +349,24 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		mlog.String("userID", userID),
 	)
 
-	data, err := json.Marshal(cardPatched)
+	board, err := a.app.GetBoard(cardPatched.BoardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	filteredCards, err := a.app.FilterCardsForUser(board, userID, []*model.Card{cardPatched})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if len(filteredCards) == 0 {
+		// the user made private a card it is not assigned to
+		jsonStringResponse(w, http.StatusOK, "{}")
+		auditRec.Success()
+		return
+	}
+
+	data, err := json.Marshal(filteredCards[0])
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -365,6 +419,22 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	board, err := a.app.GetBoard(card.BoardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	filteredCards, err := a.app.FilterCardsForUser(board, userID, []*model.Card{card})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if len(filteredCards) == 0 {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to private card"))
+		return
+	}
+	card = filteredCards[0]
+
 	auditRec := a.makeAuditRecord(r, "getCard", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("boardID", card.BoardID)
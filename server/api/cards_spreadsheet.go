@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/spreadsheet"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerCardsSpreadsheetRoutes(r *mux.Router) {
+	// Cards spreadsheet APIs
+	r.HandleFunc("/boards/{boardID}/export", a.sessionRequired(a.handleExportBoardCards)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/import", a.sessionRequired(a.handleImportBoardCards)).Methods("POST")
+}
+
+func (a *API) handleExportBoardCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/export exportBoardCards
+	//
+	// Exports the cards of a board as a spreadsheet, with a row per card
+	// and a column per card property.
+	//
+	// ---
+	// produces:
+	// - text/csv
+	// - application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: The format of the spreadsheet, either csv (default) or xlsx
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: string
+	//       format: binary
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	format, err := spreadsheetFormat(r.URL.Query().Get("format"), "")
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "exportBoardCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("format", format)
+
+	// the board is checked before writing the headers, so a missing
+	// board is reported as such
+	if _, err = a.app.GetBoard(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("cards-%s.%s", time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+
+	if err := a.app.ExportBoardCards(w, boardID, userID, format); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+func (a *API) handleImportBoardCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/import importBoardCards
+	//
+	// Creates and updates the cards of a board from a spreadsheet. Rows
+	// with the "Card ID" of an existing card update it, the others create
+	// new cards. Columns are matched by name to the card properties, and
+	// unknown columns are created as text properties.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// consumes:
+	// - multipart/form-data
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: format
+	//   in: query
+	//   description: The format of the spreadsheet, csv or xlsx. Defaults to the file extension
+	//   required: false
+	//   type: string
+	// - name: dry_run
+	//   in: query
+	//   description: Only return the changes that would be made
+	//   required: false
+	//   type: boolean
+	// - name: file
+	//   in: formData
+	//   description: spreadsheet to import
+	//   required: true
+	//   type: file
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardsImportResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	file, handle, err := r.FormFile(UploadFormFileKey)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	format, err := spreadsheetFormat(r.URL.Query().Get("format"), handle.Filename)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "importBoardCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("filename", handle.Filename)
+	auditRec.AddMeta("dryRun", dryRun)
+
+	// users that can't manage the card properties can only import
+	// spreadsheets that don't add properties or options
+	if !dryRun && !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		preview, err := a.app.ImportBoardCards(boardID, file, format, model.ImportCardsOptions{ModifiedBy: userID, DryRun: true})
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		if len(preview.NewProperties) != 0 || len(preview.NewOptions) != 0 {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to modify board properties"))
+			return
+		}
+		if _, err = file.Seek(0, 0); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
+	result, err := a.app.ImportBoardCards(boardID, file, format, model.ImportCardsOptions{ModifiedBy: userID, DryRun: dryRun})
+	if err != nil {
+		a.logger.Debug("Error importing cards",
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("changes", len(result.Changes))
+	auditRec.Success()
+}
+
+// spreadsheetFormat returns the format from the query parameter or,
+// failing that, the extension of the filename. It defaults to CSV.
+func spreadsheetFormat(param, filename string) (spreadsheet.Format, error) {
+	if param == "" {
+		param = strings.ToLower(filepath.Ext(filename))
+	}
+	if param == "" {
+		return spreadsheet.FormatCSV, nil
+	}
+
+	format, err := spreadsheet.ParseFormat(param)
+	if err != nil {
+		return "", model.NewErrBadRequest(err.Error())
+	}
+	return format, nil
+}
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -55,6 +57,40 @@ type FileUploadResponse struct {
 	FileID string `json:"fileId"`
 }
 
+// RiskyContentTypes are always downloaded rather than displayed, as they
+// can run scripts once opened by the browser.
+var RiskyContentTypes = [...]string{
+	"text/html",
+	"application/xhtml+xml",
+	"image/svg+xml",
+	"text/xml",
+	"application/xml",
+	"application/javascript",
+	"application/ecmascript",
+	"text/javascript",
+	"text/ecmascript",
+	"application/x-javascript",
+	"application/x-shockwave-flash",
+}
+
+// isRiskyFile returns true if either the content type of a file or its
+// name denote a risky type.
+func isRiskyFile(contentType string, filenames ...string) bool {
+	types := []string{contentType}
+	for _, filename := range filenames {
+		types = append(types, mime.TypeByExtension(filepath.Ext(filename)))
+	}
+
+	for _, t := range types {
+		for _, riskyContentType := range RiskyContentTypes {
+			if t != "" && strings.HasPrefix(t, riskyContentType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func FileUploadResponseFromJSON(data io.Reader) (*FileUploadResponse, error) {
 	var fileUploadResponse FileUploadResponse
 
@@ -77,6 +113,7 @@ func (a *API) registerFilesRoutes(r *mux.Router) {
 	// Files API
 	r.HandleFunc("/files/teams/{teamID}/{boardID}/{filename}", a.attachSession(a.handleServeFile, false)).Methods("GET")
 	r.HandleFunc("/files/teams/{teamID}/{boardID}/{filename}/info", a.attachSession(a.getFileInfo, false)).Methods("GET")
+	r.HandleFunc("/files/teams/{teamID}/{boardID}/{filename}/signed-url", a.sessionRequired(a.handleGetSignedFileURL)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/{boardID}/files", a.sessionRequired(a.handleUploadFile)).Methods("POST")
 }
 
@@ -107,11 +144,35 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	//   description: name of the file
 	//   required: true
 	//   type: string
+	// - name: size
+	//   in: query
+	//   description: size of an image, thumb or preview, the original if not set or not available
+	//   required: false
+	//   type: string
+	// - name: expires
+	//   in: query
+	//   description: expiry time of a signed URL
+	//   required: false
+	//   type: integer
+	// - name: user
+	//   in: query
+	//   description: ID of the user a signed URL was generated for
+	//   required: false
+	//   type: string
+	// - name: signature
+	//   in: query
+	//   description: signature of a signed URL
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
+	//   '302':
+	//     description: redirect to the S3 pre-signed URL of the file
+	//   '400':
+	//     description: invalid size
 	//   '404':
 	//     description: file not found
 	//   default:
@@ -124,7 +185,24 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 	filename := vars["filename"]
 	userID := getUserID(r)
 
-	hasValidReadToken := a.hasValidReadTokenForBoard(r, boardID)
+	size, err := model.ParseFileSize(r.URL.Query().Get("size"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	// signed URLs are served with the permissions of the user they were
+	// generated for
+	isSignedURL := isSignedFileRequest(r)
+	if isSignedURL {
+		userID, err = a.app.ValidateSignedFileURL(vars["teamID"], boardID, filename, r.URL.Query())
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
+	hasValidReadToken, shareLink := a.getReadTokenAccessForBoard(r, boardID)
 	if userID == "" && !hasValidReadToken {
 		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to board"))
 		return
@@ -141,12 +219,53 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if hasValidReadToken && shareLink != nil {
+		if err = a.checkShareLinkFile(board, shareLink, filename); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
+	if err = a.app.CheckFileBoard(boardID, filename); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "getFile", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("teamID", board.TeamID)
 	auditRec.AddMeta("filename", filename)
 
+	if isSignedURL && size == model.FileSizeOriginal {
+		redirectURL, err2 := a.app.GetFileRedirectURL(board.TeamID, boardID, filename)
+		if err2 != nil {
+			// the file is still served by the server
+			a.logger.Warn("Cannot redirect a signed file URL to the files storage", mlog.String("filename", filename), mlog.Err(err2))
+		}
+		if redirectURL != "" {
+			http.Redirect(w, r, redirectURL, http.StatusFound)
+			auditRec.Success()
+			return
+		}
+	}
+
+	if size != model.FileSizeOriginal {
+		auditRec.AddMeta("size", size)
+		previewReader, err2 := a.app.GetFilePreview(board.TeamID, boardID, filename, size)
+		if err2 != nil {
+			a.errorResponse(w, r, err2)
+			return
+		}
+		// files without previews are served as is
+		if previewReader != nil {
+			defer previewReader.Close()
+			writeFileResponse(filename, "image/jpeg", 0, time.Now(), "", previewReader, false, w, r)
+			auditRec.Success()
+			return
+		}
+	}
+
 	fileInfo, fileReader, err := a.app.GetFile(board.TeamID, boardID, filename)
 	if err != nil && !model.IsErrNotFound(err) {
 		a.errorResponse(w, r, err)
@@ -172,17 +291,21 @@ func (a *API) handleServeFile(w http.ResponseWriter, r *http.Request) {
 		// if err is still not nil then it is an error other than `not found` so we must
 		// return the error to the requestor.  fileReader and Fileinfo are nil in this case.
 		a.errorResponse(w, r, err)
+		return
 	}
 
 	defer fileReader.Close()
 
 	mimeType := ""
+	originalName := ""
 	var fileSize int64
 	if fileInfo != nil {
 		mimeType = fileInfo.MimeType
+		originalName = fileInfo.Name
 		fileSize = fileInfo.Size
 	}
-	writeFileResponse(filename, mimeType, fileSize, time.Now(), "", fileReader, false, w, r)
+	forceDownload := isRiskyFile(mimeType, filename, originalName)
+	writeFileResponse(filename, mimeType, fileSize, time.Now(), "", fileReader, forceDownload, w, r)
 	auditRec.Success()
 }
 
@@ -286,7 +409,7 @@ func (a *API) getFileInfo(w http.ResponseWriter, r *http.Request) {
 	filename := vars["filename"]
 	userID := getUserID(r)
 
-	hasValidReadToken := a.hasValidReadTokenForBoard(r, boardID)
+	hasValidReadToken, shareLink := a.getReadTokenAccessForBoard(r, boardID)
 	if userID == "" && !hasValidReadToken {
 		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to board"))
 		return
@@ -297,6 +420,18 @@ func (a *API) getFileInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if hasValidReadToken && shareLink != nil {
+		board, err := a.app.GetBoard(boardID)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		if err = a.checkShareLinkFile(board, shareLink, filename); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	auditRec := a.makeAuditRecord(r, "getFile", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("boardID", boardID)
@@ -318,6 +453,78 @@ func (a *API) getFileInfo(w http.ResponseWriter, r *http.Request) {
 	jsonBytesResponse(w, http.StatusOK, data)
 }
 
+func (a *API) handleGetSignedFileURL(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /files/teams/{teamID}/{boardID}/{filename}/signed-url getSignedFileURL
+	//
+	// Returns a short-lived signed URL of an uploaded file, which can be
+	// downloaded without a session, eg. from emails or external tools.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: filename
+	//   in: path
+	//   description: name of the file
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/SignedFileURL"
+	//   '501':
+	//     description: the server secret isn't configured
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	boardID := vars["boardID"]
+	filename := vars["filename"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getSignedFileURL", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("filename", filename)
+
+	signedURL, err := a.app.GetSignedFileURL(teamID, boardID, filename, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(signedURL)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
 func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/boards/{boardID}/files uploadFile
 	//
@@ -414,3 +621,17 @@ func (a *API) handleUploadFile(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("fileID", fileID)
 	auditRec.Success()
 }
+
+// checkShareLinkFile returns a not found error unless the file is shared
+// by the link, so links restricted to a view don't open the files of the
+// other cards.
+func (a *API) checkShareLinkFile(board *model.Board, link *model.ShareLink, filename string) error {
+	canAccess, err := a.app.CanShareLinkAccessFile(board, link, filename)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return model.NewErrNotFound("file " + filename + " in board ID=" + board.ID)
+	}
+	return nil
+}
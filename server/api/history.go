@@ -0,0 +1,570 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const defaultHistoryLimit = 100
+
+func (a *API) registerHistoryRoutes(r *mux.Router) {
+	// History APIs
+	r.HandleFunc("/boards/{boardID}/history", a.sessionRequired(a.handleGetBoardHistory)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/history/restore", a.sessionRequired(a.handleRestoreBoardHistory)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/history", a.sessionRequired(a.handleGetBlockHistory)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/history/diff", a.sessionRequired(a.handleGetBlockHistoryDiff)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/blocks/{blockID}/history/restore", a.sessionRequired(a.handleRestoreBlockHistory)).Methods("POST")
+}
+
+func (a *API) handleGetBoardHistory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/history getBoardHistory
+	//
+	// Returns the revisions of a board, newest first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: before_update_at
+	//   in: query
+	//   description: Only returns revisions older than this timestamp; Unix time in milliseconds
+	//   required: false
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: Number of revisions to return (default=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	beforeUpdateAt, limit, err := parseHistoryQuery(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardHistory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	opts := model.QueryBoardHistoryOptions{
+		BeforeUpdateAt: beforeUpdateAt,
+		Limit:          limit,
+		Descending:     true,
+	}
+	boards, err := a.app.GetBoardHistory(boardID, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardHistory",
+		mlog.String("boardID", boardID),
+		mlog.Int("revisions", len(boards)),
+	)
+
+	data, err := json.Marshal(boards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("revisionCount", len(boards))
+	auditRec.Success()
+}
+
+func (a *API) handleGetBlockHistory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/blocks/{blockID}/history getBlockHistory
+	//
+	// Returns the revisions of a block, newest first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: Block ID
+	//   required: true
+	//   type: string
+	// - name: before_update_at
+	//   in: query
+	//   description: Only returns revisions older than this timestamp; Unix time in milliseconds
+	//   required: false
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: Number of revisions to return (default=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Block"
+	//   '404':
+	//     description: block not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	blockID := vars["blockID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	if err := a.checkHistoryBlockBoard(userID, boardID, blockID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	beforeUpdateAt, limit, err := parseHistoryQuery(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBlockHistory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("blockID", blockID)
+
+	opts := model.QueryBlockHistoryOptions{
+		BeforeUpdateAt: beforeUpdateAt,
+		Limit:          limit,
+		Descending:     true,
+	}
+	blocks, err := a.app.GetBlockHistory(blockID, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	blocks, err = a.app.FilterBlocksForUser(board, userID, blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBlockHistory",
+		mlog.String("boardID", boardID),
+		mlog.String("blockID", blockID),
+		mlog.Int("revisions", len(blocks)),
+	)
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("revisionCount", len(blocks))
+	auditRec.Success()
+}
+
+func (a *API) handleGetBlockHistoryDiff(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/blocks/{blockID}/history/diff getBlockHistoryDiff
+	//
+	// Returns the differences of a block and its children between two revisions
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: Block ID
+	//   required: true
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: The update_at of the older revision; Unix time in milliseconds
+	//   required: true
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: The update_at of the newer revision, omit to compare with the latest one
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BlockHistoryDiff"
+	//   '404':
+	//     description: block not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	blockID := vars["blockID"]
+	userID := getUserID(r)
+
+	query := r.URL.Query()
+	strFrom := query.Get("from")
+	strTo := query.Get("to")
+
+	if strFrom == "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("`from` parameter required"))
+		return
+	}
+
+	from, err := strconv.ParseInt(strFrom, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("invalid `from` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	var to int64
+	if strTo != "" {
+		to, err = strconv.ParseInt(strTo, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `to` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	if err = a.checkHistoryBlockBoard(userID, boardID, blockID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBlockHistoryDiff", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("blockID", blockID)
+	auditRec.AddMeta("from", from)
+	auditRec.AddMeta("to", to)
+
+	diff, err := a.app.GetBlockHistoryDiff(blockID, from, to)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	diff = a.app.FilterBlockHistoryDiffForUser(board, userID, diff)
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreBlockHistory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/blocks/{blockID}/history/restore restoreBlockHistory
+	//
+	// Restores a block, and optionally its children, to a previous revision. The
+	// restored versions are saved as new revisions
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: Block ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the revision to restore
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/HistoryRestoreRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/HistoryRestoreResult"
+	//   '404':
+	//     description: block or revision not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	blockID := vars["blockID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	if err := a.checkHistoryBlockBoard(userID, boardID, blockID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	request, err := parseHistoryRestoreRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreBlockHistory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("blockID", blockID)
+	auditRec.AddMeta("updateAt", request.UpdateAt)
+	auditRec.AddMeta("includeChildren", request.IncludeChildren)
+
+	result, err := a.app.RestoreBlockHistory(boardID, blockID, request.UpdateAt, request.IncludeChildren, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreBlockHistory",
+		mlog.String("boardID", boardID),
+		mlog.String("blockID", blockID),
+		mlog.Int("updateAt", request.UpdateAt),
+		mlog.Int("restored", len(result.Blocks)),
+		mlog.Int("deleted", len(result.DeletedBlockIDs)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreBoardHistory(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/history/restore restoreBoardHistory
+	//
+	// Restores a board and all its blocks to a previous revision. The restored
+	// versions are saved as new revisions
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the revision to restore
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/HistoryRestoreRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/HistoryRestoreResult"
+	//   '404':
+	//     description: board or revision not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) ||
+		!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	request, err := parseHistoryRestoreRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreBoardHistory", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("updateAt", request.UpdateAt)
+
+	result, err := a.app.RestoreBoardHistory(boardID, request.UpdateAt, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreBoardHistory",
+		mlog.String("boardID", boardID),
+		mlog.Int("updateAt", request.UpdateAt),
+		mlog.Int("restored", len(result.Blocks)),
+		mlog.Int("deleted", len(result.DeletedBlockIDs)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+// checkHistoryBlockBoard checks that a block, which may already be
+// deleted, belongs to a board and is visible to the user.
+func (a *API) checkHistoryBlockBoard(userID, boardID, blockID string) error {
+	block, err := a.app.GetLastBlockHistoryEntry(blockID)
+	if err != nil {
+		return err
+	}
+	if block == nil || block.BoardID != boardID {
+		return model.NewErrNotFound(fmt.Sprintf("block ID=%s on BoardID=%s", blockID, boardID))
+	}
+	if !a.app.CanViewBlock(userID, block) {
+		return model.NewErrPermission("access denied to private card")
+	}
+	return nil
+}
+
+func parseHistoryQuery(r *http.Request) (int64, uint64, error) {
+	query := r.URL.Query()
+	strBeforeUpdateAt := query.Get("before_update_at")
+	strLimit := query.Get("limit")
+
+	var beforeUpdateAt int64
+	if strBeforeUpdateAt != "" {
+		var err error
+		beforeUpdateAt, err = strconv.ParseInt(strBeforeUpdateAt, 10, 64)
+		if err != nil {
+			return 0, 0, model.NewErrBadRequest(fmt.Sprintf("invalid `before_update_at` parameter: %s", err))
+		}
+	}
+
+	var limit uint64
+	if strLimit != "" {
+		var err error
+		limit, err = strconv.ParseUint(strLimit, 10, 64)
+		if err != nil {
+			return 0, 0, model.NewErrBadRequest(fmt.Sprintf("invalid `limit` parameter: %s", err))
+		}
+	}
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+
+	return beforeUpdateAt, limit, nil
+}
+
+func parseHistoryRestoreRequest(r *http.Request) (*model.HistoryRestoreRequest, error) {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *model.HistoryRestoreRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil || request == nil {
+		return nil, model.NewErrBadRequest("invalid restore request")
+	}
+
+	if err = request.IsValid(); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
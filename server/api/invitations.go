@@ -23,6 +23,7 @@ func (a *API) registerInvitationRoutes(r *mux.Router) {
 	r.HandleFunc("/invitations/{invitationID}", a.sessionRequired(a.handleDeleteInvitation)).Methods("DELETE")
 	r.HandleFunc("/invite/{token}", a.handleAcceptInvitation).Methods("GET")
 	r.HandleFunc("/invite/{token}/accept", a.sessionRequired(a.handleAcceptInvitationPost)).Methods("POST")
+	r.HandleFunc("/invite/{token}/register", a.handleRegisterGuestInvitation).Methods("POST")
 }
 
 func (a *API) handleSendInvitation(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +105,27 @@ func (a *API) handleSendInvitation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only the team admins can invite guests, which can't administer the
+	// board, and in plugin mode the guests are managed by Mattermost
+	if inviteReq.Guest {
+		if a.MattermostAuth {
+			a.errorResponse(w, r, model.NewErrNotImplemented("guest invitations not permitted in plugin mode"))
+			return
+		}
+		if !a.permissions.HasPermissionToTeam(userID, board.TeamID, model.PermissionManageTeam) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to invite guests"))
+			return
+		}
+		if inviteReq.Role == "admin" {
+			a.errorResponse(w, r, model.NewErrBadRequest("guests cannot be board admins"))
+			return
+		}
+		if !model.IsValidGuestExpireAt(inviteReq.GuestExpireAt) {
+			a.errorResponse(w, r, model.NewErrBadRequest("the guest expiration must be in the future"))
+			return
+		}
+	}
+
 	// Get inviter info
 	inviter, err := a.app.GetUser(userID)
 	if err != nil {
@@ -133,6 +155,9 @@ func (a *API) handleSendInvitation(w http.ResponseWriter, r *http.Request) {
 		CreatedBy: userID,
 		CreatedAt: time.Now().Unix(),
 		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(), // 7 days expiry
+
+		IsGuest:       inviteReq.Guest,
+		GuestExpireAt: inviteReq.GuestExpireAt,
 	}
 
 	// Save invitation
@@ -251,6 +276,7 @@ func (a *API) handleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
 		"email":      invitation.Email,
 		"role":       invitation.Role,
 		"boardId":    invitation.BoardID,
+		"guest":      invitation.IsGuest,
 		"valid":      true,
 	}
 
@@ -331,43 +357,183 @@ func (a *API) handleAcceptInvitationPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Add user to board
-	newBoardMember := &model.BoardMember{
-		UserID:          userID,
-		BoardID:         invitation.BoardID,
-		SchemeAdmin:     invitation.Role == "admin",
-		SchemeEditor:    invitation.Role == "editor",
-		SchemeCommenter: invitation.Role == "commenter",
-		SchemeViewer:    invitation.Role == "viewer" || invitation.Role == "",
+	if err = a.acceptInvitation(invitation, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
 	}
 
-	_, err = a.app.AddMemberToBoard(newBoardMember)
+	a.logger.Debug("Invitation accepted",
+		mlog.String("boardID", invitation.BoardID),
+		mlog.String("userID", userID),
+		mlog.String("email", invitation.Email))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleRegisterGuestInvitation(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /invite/{token}/register registerGuestInvitation
+	//
+	// Accept a guest invitation, creating the guest account of the invited
+	// email
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: token
+	//   in: path
+	//   description: Invitation Token
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: Register request, the email of the invitation is used
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RegisterRequest"
+	// responses:
+	//   '200':
+	//     description: success
+	//   '400':
+	//     description: invalid invitation
+	//   '404':
+	//     description: invitation not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	token := mux.Vars(r)["token"]
+
+	requestBody, err := io.ReadAll(r.Body)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
+	var registerData model.RegisterRequest
+	if err = json.Unmarshal(requestBody, &registerData); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	invitation, err := a.app.GetBoardInvitation(token)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrNotFound("invitation not found"))
+		return
+	}
+
+	if invitation.IsExpired() {
+		a.errorResponse(w, r, model.NewErrBadRequest("invitation has expired"))
+		return
+	}
+
+	if invitation.IsUsed() {
+		a.errorResponse(w, r, model.NewErrBadRequest("invitation has already been used"))
+		return
+	}
+
+	if !invitation.IsGuest {
+		a.errorResponse(w, r, model.NewErrBadRequest("only guest invitations create accounts"))
+		return
+	}
+
+	registerData.Username = strings.TrimSpace(registerData.Username)
+	registerData.Email = invitation.Email
+	if err = registerData.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	board, err := a.app.GetBoard(invitation.BoardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "registerGuestInvitation", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("token", token[:8]+"...")
+	auditRec.AddMeta("username", registerData.Username)
+	auditRec.AddMeta("boardID", invitation.BoardID)
+
+	user, err := a.app.RegisterGuest(registerData.Username, registerData.Email, registerData.Password, board.TeamID, invitation.GuestExpireAt)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if err = a.acceptInvitation(invitation, user.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("Guest invitation accepted",
+		mlog.String("boardID", invitation.BoardID),
+		mlog.String("userID", user.ID))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.AddMeta("userID", user.ID)
+	auditRec.Success()
+}
+
+// acceptInvitation adds the user accepting an invitation to its board,
+// with the invited role, and marks the invitation as used.
+func (a *API) acceptInvitation(invitation *model.BoardInvitation, userID string) error {
+	// The invited user joins the team of the board, so it can access it
+	if !a.MattermostAuth {
+		board, err := a.app.GetBoard(invitation.BoardID)
+		if err != nil {
+			return err
+		}
+		if err = a.app.EnsureTeamMember(board.TeamID, userID); err != nil {
+			return err
+		}
+	}
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		return err
+	}
+
+	// Add user to board, the guests can't administer it
+	role := invitation.Role
+	if isGuest && role == "admin" {
+		role = "editor"
+	}
+
+	newBoardMember := &model.BoardMember{
+		UserID:          userID,
+		BoardID:         invitation.BoardID,
+		SchemeAdmin:     role == "admin",
+		SchemeEditor:    role == "editor",
+		SchemeCommenter: role == "commenter",
+		SchemeViewer:    role == "viewer" || role == "",
+	}
+
+	if _, err = a.app.AddMemberToBoard(newBoardMember); err != nil {
+		return err
+	}
+
 	// Mark invitation as used
 	now := time.Now().Unix()
 	invitation.UsedAt = &now
 	invitation.UsedBy = &userID
 
-	err = a.app.UpdateBoardInvitation(invitation)
-	if err != nil {
+	if err = a.app.UpdateBoardInvitation(invitation); err != nil {
 		a.logger.Error("Failed to mark invitation as used",
-			mlog.String("token", token),
+			mlog.String("invitationID", invitation.ID),
 			mlog.String("userID", userID),
 			mlog.Err(err))
 		// Don't fail the request, user is already added to board
 	}
-
-	a.logger.Debug("Invitation accepted",
-		mlog.String("boardID", invitation.BoardID),
-		mlog.String("userID", userID),
-		mlog.String("email", invitation.Email))
-
-	jsonStringResponse(w, http.StatusOK, "{}")
-	auditRec.Success()
+	return nil
 }
 
 func (a *API) handleGetBoardInvitations(w http.ResponseWriter, r *http.Request) {
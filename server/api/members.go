@@ -162,6 +162,17 @@ func (a *API) handleAddMember(w http.ResponseWriter, r *http.Request) {
 		SchemeAdmin:     reqBoardMember.SchemeAdmin,
 		SchemeViewer:    reqBoardMember.SchemeViewer,
 		SchemeCommenter: reqBoardMember.SchemeCommenter,
+		CustomRoleID:    reqBoardMember.CustomRoleID,
+	}
+
+	isGuest, err := a.userIsGuest(reqBoardMember.UserID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if isGuest {
+		newBoardMember.SchemeAdmin = false
 	}
 
 	auditRec := a.makeAuditRecord(r, "addMember", audit.Fail)
@@ -432,6 +443,7 @@ func (a *API) handleUpdateMember(w http.ResponseWriter, r *http.Request) {
 		SchemeEditor:    reqBoardMember.SchemeEditor,
 		SchemeCommenter: reqBoardMember.SchemeCommenter,
 		SchemeViewer:    reqBoardMember.SchemeViewer,
+		CustomRoleID:    reqBoardMember.CustomRoleID,
 	}
 
 	isGuest, err := a.userIsGuest(paramsUserID)
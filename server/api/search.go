@@ -119,6 +119,11 @@ func (a *API) handleSearchBoards(w http.ResponseWriter, r *http.Request) {
 	//   description: The field to search on for search term. Can be `title`, `property_name`. Defaults to `title`
 	//   required: false
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Include the archived boards in the results (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -168,7 +173,8 @@ func (a *API) handleSearchBoards(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// retrieve boards list
-	boards, err := a.app.SearchBoardsForUser(term, searchField, userID, !isGuest)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	boards, err := a.app.SearchBoardsForUser(term, searchField, userID, !isGuest, includeArchived)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -295,6 +301,11 @@ func (a *API) handleSearchAllBoards(w http.ResponseWriter, r *http.Request) {
 	//   description: The search term. Must have at least one character
 	//   required: true
 	//   type: string
+	// - name: include_archived
+	//   in: query
+	//   description: Include the archived boards in the results (default=false)
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -327,7 +338,8 @@ func (a *API) handleSearchAllBoards(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// retrieve boards list
-	boards, err := a.app.SearchBoardsForUser(term, model.BoardSearchFieldTitle, userID, !isGuest)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	boards, err := a.app.SearchBoardsForUser(term, model.BoardSearchFieldTitle, userID, !isGuest, includeArchived)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+)
+
+func (a *API) registerSessionsRoutes(r *mux.Router) {
+	// personal-server specific routes. These are not needed in plugin mode.
+	r.HandleFunc("/users/me/sessions", a.sessionRequired(a.localSessionsRequired(a.handleGetMySessions))).Methods("GET")
+	r.HandleFunc("/users/me/sessions", a.sessionRequired(a.localSessionsRequired(a.handleRevokeMyOtherSessions))).Methods("DELETE")
+	r.HandleFunc("/users/me/sessions/{sessionID}", a.sessionRequired(a.localSessionsRequired(a.handleRevokeMySession))).Methods("DELETE")
+}
+
+// localSessionsRequired rejects the requests on the sessions when they
+// are not managed by the server, in plugin and single-user modes.
+func (a *API) localSessionsRequired(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.MattermostAuth {
+			a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+			return
+		}
+
+		if len(a.singleUserToken) > 0 {
+			a.errorResponse(w, r, model.NewErrUnauthorized("not permitted in single-user mode"))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func getSessionID(r *http.Request) string {
+	session, _ := r.Context().Value(sessionContextKey).(*model.Session)
+	if session == nil {
+		return ""
+	}
+	return session.ID
+}
+
+func (a *API) handleGetMySessions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/sessions getMySessions
+	//
+	// Returns the sessions of the current user, with the client they were
+	// created from and their last activity
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/SessionInfo"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "getMySessions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	sessions, err := a.app.GetSessionsForUser(userID, getSessionID(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("sessionCount", len(sessions))
+	auditRec.Success()
+}
+
+func (a *API) handleRevokeMySession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /users/me/sessions/{sessionID} revokeMySession
+	//
+	// Revokes a session of the current user, logging out its client
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: sessionID
+	//   in: path
+	//   description: Session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	sessionID := mux.Vars(r)["sessionID"]
+
+	auditRec := a.makeAuditRecord(r, "revokeMySession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("revokedSessionID", sessionID)
+
+	if err := a.app.RevokeSession(userID, sessionID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleRevokeMyOtherSessions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /users/me/sessions revokeMyOtherSessions
+	//
+	// Revokes all the sessions of the current user but the one of the
+	// request
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "revokeMyOtherSessions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	count, err := a.app.RevokeOtherSessions(userID, getSessionID(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.AddMeta("revokedCount", count)
+	auditRec.Success()
+}
@@ -0,0 +1,402 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerShareLinksRoutes(r *mux.Router) {
+	// Share links APIs
+	r.HandleFunc("/boards/{boardID}/share-links", a.sessionRequired(a.handleGetShareLinks)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/share-links", a.sessionRequired(a.handleCreateShareLink)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/share-links/unlock", a.handleUnlockShareLink).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/share-links/{linkID}", a.sessionRequired(a.handlePatchShareLink)).Methods("PATCH")
+	r.HandleFunc("/boards/{boardID}/share-links/{linkID}", a.sessionRequired(a.handleDeleteShareLink)).Methods("DELETE")
+}
+
+func (a *API) handleGetShareLinks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/share-links getShareLinks
+	//
+	// Returns the share links of a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/ShareLink"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to sharing the board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getShareLinks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	links, err := a.app.GetShareLinksForBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("linkCount", len(links))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateShareLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/share-links createShareLink
+	//
+	// Creates a share link of a board. The link can expire, be protected by
+	// a password, be restricted to a view and hide card properties.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the settings of the share link
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ShareLink"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ShareLink"
+	//   '501':
+	//     description: public shared boards are disabled
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to sharing the board"))
+		return
+	}
+
+	if !a.app.GetClientConfig().EnablePublicSharedBoards {
+		a.errorResponse(w, r, model.NewErrNotImplemented("public shared boards are disabled"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var link model.ShareLink
+	if err = json.Unmarshal(requestBody, &link); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	link.BoardID = boardID
+
+	auditRec := a.makeAuditRecord(r, "createShareLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("viewID", link.ViewID)
+	auditRec.AddMeta("expiresAt", link.ExpiresAt)
+
+	created, err := a.app.CreateShareLink(&link, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("CreateShareLink", mlog.String("boardID", boardID), mlog.String("linkID", created.ID))
+	auditRec.AddMeta("linkID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchShareLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /boards/{boardID}/share-links/{linkID} patchShareLink
+	//
+	// Updates the settings of a share link
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: linkID
+	//   in: path
+	//   description: Share link ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the share link patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ShareLinkPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ShareLink"
+	//   '404':
+	//     description: share link not found
+	//   '501':
+	//     description: public shared boards are disabled
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	link, err := a.getShareLinkOfBoard(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.app.GetClientConfig().EnablePublicSharedBoards {
+		a.errorResponse(w, r, model.NewErrNotImplemented("public shared boards are disabled"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.ShareLinkPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchShareLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", link.BoardID)
+	auditRec.AddMeta("linkID", link.ID)
+
+	updated, err := a.app.PatchShareLink(link, &patch)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteShareLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/share-links/{linkID} deleteShareLink
+	//
+	// Revokes a share link. The other share links of the board keep working.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: linkID
+	//   in: path
+	//   description: Share link ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: share link not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	link, err := a.getShareLinkOfBoard(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteShareLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", link.BoardID)
+	auditRec.AddMeta("linkID", link.ID)
+
+	if err = a.app.DeleteShareLink(link.ID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("DeleteShareLink", mlog.String("boardID", link.BoardID), mlog.String("linkID", link.ID))
+	auditRec.Success()
+}
+
+func (a *API) handleUnlockShareLink(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/share-links/unlock unlockShareLink
+	//
+	// Checks the password of a protected share link, and returns the read
+	// token to open it. The token stops working if the password changes.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the token and password of the share link
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ShareLinkUnlockRequest"
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/ShareLinkUnlockResponse"
+	//   '401':
+	//     description: invalid password
+	//   '404':
+	//     description: share link not found
+	//   '429':
+	//     description: too many failed attempts
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.app.GetClientConfig().EnablePublicSharedBoards {
+		a.errorResponse(w, r, model.NewErrNotImplemented("public shared boards are disabled"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.ShareLinkUnlockRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "unlockShareLink", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	response, err := a.app.UnlockShareLink(boardID, &request, a.getClientIP(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+// getShareLinkOfBoard returns the share link of a request, checking that
+// the user can share the board and that the link belongs to it.
+func (a *API) getShareLinkOfBoard(r *http.Request) (*model.ShareLink, error) {
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(getUserID(r), boardID, model.PermissionShareBoard) {
+		return nil, model.NewErrPermission("access denied to sharing the board")
+	}
+
+	link, err := a.app.GetShareLink(vars["linkID"])
+	if err != nil {
+		return nil, err
+	}
+	if link.BoardID != boardID {
+		return nil, model.NewErrNotFound("share link ID=" + link.ID)
+	}
+	return link, nil
+}
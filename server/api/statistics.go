@@ -56,10 +56,20 @@ func (a *API) handleStatistics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fileUsage, err := a.app.GetFileUsage("")
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	stats := model.BoardsStatistics{
 		Boards: int(boardCount),
 		Cards:  cardCount,
 	}
+	for _, team := range fileUsage {
+		stats.Files += team.Files
+		stats.FilesSize += team.Size
+	}
 	data, err := json.Marshal(stats)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -14,7 +14,14 @@ import (
 func (a *API) registerTeamsRoutes(r *mux.Router) {
 	// Team APIs
 	r.HandleFunc("/teams", a.sessionRequired(a.handleGetTeams)).Methods("GET")
+	r.HandleFunc("/teams", a.sessionRequired(a.handleCreateTeam)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}", a.sessionRequired(a.handleGetTeam)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}", a.sessionRequired(a.handlePatchTeam)).Methods("PATCH")
+	r.HandleFunc("/teams/{teamID}", a.sessionRequired(a.handleDeleteTeam)).Methods("DELETE")
+	r.HandleFunc("/teams/{teamID}/members", a.sessionRequired(a.handleGetTeamMembers)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/members", a.sessionRequired(a.handleAddTeamMember)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/members/{userID}", a.sessionRequired(a.handleUpdateTeamMember)).Methods("PUT")
+	r.HandleFunc("/teams/{teamID}/members/{userID}", a.sessionRequired(a.handleRemoveTeamMember)).Methods("DELETE")
 	r.HandleFunc("/teams/{teamID}/users", a.sessionRequired(a.handleGetTeamUsers)).Methods("GET")
 	r.HandleFunc("/teams/{teamID}/users", a.sessionRequired(a.handleGetTeamUsersByID)).Methods("POST")
 	r.HandleFunc("/teams/{teamID}/archive/export", a.sessionRequired(a.handleArchiveExportTeam)).Methods("GET")
@@ -23,7 +30,7 @@ func (a *API) registerTeamsRoutes(r *mux.Router) {
 func (a *API) handleGetTeams(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /teams getTeams
 	//
-	// Returns information of all the teams
+	// Returns the teams the user is a member of
 	//
 	// ---
 	// produces:
@@ -66,7 +73,7 @@ func (a *API) handleGetTeams(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleGetTeam(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /teams/{teamID} getTeam
 	//
-	// Returns information of the root team
+	// Returns information of a team
 	//
 	// ---
 	// produces:
@@ -110,7 +117,14 @@ func (a *API) handleGetTeam(w http.ResponseWriter, r *http.Request) {
 			a.errorResponse(w, r, err)
 		}
 	} else {
-		team, err = a.app.GetRootTeam()
+		// the teams that aren't stored, like the ones of the boards
+		// created before the teams, are still the root team
+		if teamID != model.GlobalTeamID {
+			team, err = a.app.GetTeam(teamID)
+		}
+		if err == nil && team == nil {
+			team, err = a.app.GetRootTeam()
+		}
 		if err != nil {
 			a.errorResponse(w, r, err)
 			return
@@ -134,7 +148,7 @@ func (a *API) handleGetTeam(w http.ResponseWriter, r *http.Request) {
 func (a *API) handlePostTeamRegenerateSignupToken(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /teams/{teamID}/regenerate_signup_token regenerateSignupToken
 	//
-	// Regenerates the signup token for the root team
+	// Regenerates the signup token of a team
 	//
 	// ---
 	// produces:
@@ -159,18 +173,19 @@ func (a *API) handlePostTeamRegenerateSignupToken(w http.ResponseWriter, r *http
 		return
 	}
 
-	team, err := a.app.GetRootTeam()
-	if err != nil {
-		a.errorResponse(w, r, err)
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
 		return
 	}
 
 	auditRec := a.makeAuditRecord(r, "regenerateSignupToken", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
 
-	team.SignupToken = utils.NewID(utils.IDTypeToken)
-
-	if err = a.app.UpsertTeamSignupToken(*team); err != nil {
+	if err := a.app.RegenerateTeamSignupToken(teamID, userID); err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
@@ -362,3 +377,501 @@ func (a *API) handleGetTeamUsersByID(w http.ResponseWriter, r *http.Request) {
 	jsonStringResponse(w, http.StatusOK, string(usersList))
 	auditRec.Success()
 }
+
+func (a *API) handleCreateTeam(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams createTeam
+	//
+	// Creates a new team. The user creating it becomes its admin.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: the team to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/Team"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Team"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionTo(userID, model.PermissionCreateTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create teams"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var team model.Team
+	if err = json.Unmarshal(requestBody, &team); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createTeam", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	created, err := a.app.CreateTeam(&team, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("teamID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchTeam(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /teams/{teamID} patchTeam
+	//
+	// Updates the title of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the team patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TeamPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/Team"
+	//   '404':
+	//     description: team not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.TeamPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchTeam", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	team, err := a.app.PatchTeam(teamID, &patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(team)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteTeam(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID} deleteTeam
+	//
+	// Deletes a team and its memberships. The boards of the team must be
+	// deleted or moved first, and the root team can't be deleted.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '400':
+	//     description: the team can't be deleted
+	//   '404':
+	//     description: team not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteTeam", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	if err := a.app.DeleteTeam(teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetTeamMembers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/members getTeamMembers
+	//
+	// Returns the members of a team and their roles
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/TeamMember"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTeamMembers", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	members, err := a.app.GetTeamMembers(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("memberCount", len(members))
+	auditRec.Success()
+}
+
+func (a *API) handleAddTeamMember(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/members addTeamMember
+	//
+	// Adds a user to a team, as a member or as a team admin
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the membership to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TeamMember"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/TeamMember"
+	//   '404':
+	//     description: team or user not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var reqMember model.TeamMember
+	if err = json.Unmarshal(requestBody, &reqMember); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if reqMember.UserID == "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("empty userID"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "addTeamMember", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("addedUserID", reqMember.UserID)
+	auditRec.AddMeta("schemeAdmin", reqMember.SchemeAdmin)
+
+	member, err := a.app.AddTeamMember(teamID, reqMember.UserID, reqMember.SchemeAdmin)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(member)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleUpdateTeamMember(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /teams/{teamID}/members/{userID} updateTeamMember
+	//
+	// Changes the role of a team member
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the role of the member
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TeamMember"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/TeamMember"
+	//   '404':
+	//     description: team member not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	memberID := vars["userID"]
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var reqMember model.TeamMember
+	if err = json.Unmarshal(requestBody, &reqMember); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateTeamMember", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("updatedUserID", memberID)
+	auditRec.AddMeta("schemeAdmin", reqMember.SchemeAdmin)
+
+	member, err := a.app.UpdateTeamMember(teamID, memberID, reqMember.SchemeAdmin)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(member)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleRemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/members/{userID} removeTeamMember
+	//
+	// Removes a user from a team. Team admins can remove any member, and
+	// the other members can leave the team.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '400':
+	//     description: the last member of the team can't be removed
+	//   '404':
+	//     description: team member not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if a.MattermostAuth {
+		a.errorResponse(w, r, model.NewErrNotImplemented("not permitted in plugin mode"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	memberID := vars["userID"]
+	userID := getUserID(r)
+	if userID != memberID && !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "removeTeamMember", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("removedUserID", memberID)
+
+	if err := a.app.RemoveTeamMember(teamID, memberID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
@@ -0,0 +1,484 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerTrashRoutes(r *mux.Router) {
+	// Trash APIs
+	r.HandleFunc("/teams/{teamID}/trash", a.sessionRequired(a.handleGetTeamTrash)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/trash/restore", a.sessionRequired(a.handleRestoreTeamTrash)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/trash/{boardID}", a.sessionRequired(a.handlePurgeDeletedBoard)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/trash", a.sessionRequired(a.handleGetBoardTrash)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/trash/restore", a.sessionRequired(a.handleRestoreBoardTrash)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/trash/{blockID}", a.sessionRequired(a.handlePurgeDeletedBlock)).Methods("DELETE")
+}
+
+func (a *API) handleGetTeamTrash(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/trash getTeamTrash
+	//
+	// Returns the deleted boards of a team that the user can access,
+	// most recently deleted first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Board"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTeamTrash", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	deleted, err := a.app.GetDeletedBoards(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	boards := make([]*model.Board, 0, len(deleted))
+	for _, board := range deleted {
+		if a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard) {
+			boards = append(boards, board)
+		}
+	}
+
+	a.logger.Debug("GetTeamTrash",
+		mlog.String("teamID", teamID),
+		mlog.Int("boardsCount", len(boards)),
+	)
+
+	data, err := json.Marshal(boards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("boardsCount", len(boards))
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreTeamTrash(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/trash/restore restoreTeamTrash
+	//
+	// Restores a set of deleted boards of a team. If any of the boards
+	// can't be restored, none is
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the boards to restore
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TrashRestoreRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Board"
+	//   '403':
+	//     description: the user is not an admin of the team
+	//   '404':
+	//     description: board not found in the trash
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	request, err := parseTrashRestoreRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	for _, boardID := range request.IDs {
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to undelete board"))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreTeamTrash", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("boardIDs", request.IDs)
+
+	boards, err := a.app.RestoreDeletedBoards(teamID, request.IDs, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreTeamTrash",
+		mlog.String("teamID", teamID),
+		mlog.Int("boardsCount", len(boards)),
+	)
+
+	data, err := json.Marshal(boards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePurgeDeletedBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/trash/{boardID} purgeDeletedBoard
+	//
+	// Permanently removes a deleted board, its blocks and its history.
+	// This can't be undone, and only the team admins can do it
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: ID of the deleted board
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: board not found in the trash
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	boardID := vars["boardID"]
+	userID := getUserID(r)
+
+	// the deleted board is read from its history, as the board
+	// permissions can't be checked once it is deleted
+	board, err := a.app.GetDeletedBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if board.TeamID != teamID {
+		a.errorResponse(w, r, model.NewErrNotFound("deleted board ID="+boardID))
+		return
+	}
+
+	// only the team and system admins can purge, but in single user
+	// mode there are no admins and the only user can
+	if userID != model.SingleUser && !a.permissions.HasPermissionToTeam(userID, board.TeamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to purge board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "purgeDeletedBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.PurgeDeletedBoard(teamID, boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PurgeDeletedBoard",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+	)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardTrash(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/trash getBoardTrash
+	//
+	// Returns the deleted cards of a board, most recently deleted first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Block"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardTrash", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	cards, err := a.app.GetDeletedCards(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	cards, err = a.app.FilterBlocksForUser(board, userID, cards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardTrash",
+		mlog.String("boardID", boardID),
+		mlog.Int("cardsCount", len(cards)),
+	)
+
+	data, err := json.Marshal(cards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("cardsCount", len(cards))
+	auditRec.Success()
+}
+
+func (a *API) handleRestoreBoardTrash(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/trash/restore restoreBoardTrash
+	//
+	// Restores a set of deleted blocks of a board. If any of the blocks
+	// can't be restored, none is
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the blocks to restore
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/TrashRestoreRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Block"
+	//   '404':
+	//     description: block not found in the trash
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	request, err := parseTrashRestoreRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreBoardTrash", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("blockIDs", request.IDs)
+
+	blocks, err := a.app.RestoreDeletedBlocks(boardID, request.IDs, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreBoardTrash",
+		mlog.String("boardID", boardID),
+		mlog.Int("blocksCount", len(blocks)),
+	)
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handlePurgeDeletedBlock(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/trash/{blockID} purgeDeletedBlock
+	//
+	// Permanently removes a deleted block, its deleted children and their
+	// history. This can't be undone
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: blockID
+	//   in: path
+	//   description: ID of the deleted block
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: block not found in the trash
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	blockID := vars["blockID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionDeleteBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to purge block"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "purgeDeletedBlock", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("blockID", blockID)
+
+	if err := a.app.PurgeDeletedBlock(boardID, blockID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PurgeDeletedBlock",
+		mlog.String("boardID", boardID),
+		mlog.String("blockID", blockID),
+	)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func parseTrashRestoreRequest(r *http.Request) (*model.TrashRestoreRequest, error) {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *model.TrashRestoreRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil || request == nil {
+		return nil, model.NewErrBadRequest("invalid restore request")
+	}
+
+	if err = request.IsValid(); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
@@ -0,0 +1,426 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// UploadOffsetHeader is the header with the offset of an upload chunk,
+// ie. the number of bytes of the file received before the chunk.
+const UploadOffsetHeader = "Upload-Offset"
+
+func (a *API) registerUploadsRoutes(r *mux.Router) {
+	// Resumable uploads APIs
+	r.HandleFunc("/teams/{teamID}/{boardID}/uploads", a.sessionRequired(a.handleCreateUploadSession)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/{boardID}/uploads/{uploadID}", a.sessionRequired(a.handleGetUploadSession)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/{boardID}/uploads/{uploadID}", a.sessionRequired(a.handleUploadChunk)).Methods("PATCH")
+	r.HandleFunc("/teams/{teamID}/{boardID}/uploads/{uploadID}", a.sessionRequired(a.handleDeleteUploadSession)).Methods("DELETE")
+	r.HandleFunc("/teams/{teamID}/{boardID}/uploads/{uploadID}/finalize", a.sessionRequired(a.handleFinalizeUpload)).Methods("POST")
+}
+
+func (a *API) handleCreateUploadSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/{boardID}/uploads createUploadSession
+	//
+	// Starts a resumable upload of a file attached to a board. The file is
+	// then sent in chunks, and finalized once all its bytes are received.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: ID of the team
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the filename, size and optional checksum of the file
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UploadSession"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UploadSession"
+	//   '404':
+	//     description: board not found
+	//   '413':
+	//     description: the file is too large
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	board, err := a.app.GetBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var session model.UploadSession
+	if err = json.Unmarshal(requestBody, &session); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	session.TeamID = board.TeamID
+	session.BoardID = board.ID
+	session.UserID = userID
+
+	auditRec := a.makeAuditRecord(r, "createUploadSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("teamID", board.TeamID)
+	auditRec.AddMeta("filename", session.Filename)
+	auditRec.AddMeta("fileSize", session.FileSize)
+
+	created, err := a.app.CreateUploadSession(&session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.uploadSessionResponse(w, r, created)
+	auditRec.AddMeta("uploadID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetUploadSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/{boardID}/uploads/{uploadID} getUploadSession
+	//
+	// Returns an upload session, with the offset to resume the upload from.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: ID of the team
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: uploadID
+	//   in: path
+	//   description: Upload session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UploadSession"
+	//   '404':
+	//     description: upload session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	session, err := a.getUploadSessionForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.uploadSessionResponse(w, r, session)
+}
+
+func (a *API) handleUploadChunk(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /teams/{teamID}/{boardID}/uploads/{uploadID} uploadChunk
+	//
+	// Appends a chunk to an upload. The chunk must start at the offset of
+	// the upload session, otherwise a 409 is returned and the upload must
+	// be resumed from the offset of the session. With the S3 driver, all
+	// the chunks but the last must be at least 5MiB.
+	//
+	// ---
+	// consumes:
+	// - application/offset+octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: ID of the team
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: uploadID
+	//   in: path
+	//   description: Upload session ID
+	//   required: true
+	//   type: string
+	// - name: Upload-Offset
+	//   in: header
+	//   description: The offset of the chunk in the file
+	//   required: true
+	//   type: integer
+	// - name: Body
+	//   in: body
+	//   description: the bytes of the chunk
+	//   required: true
+	//   schema:
+	//     type: string
+	//     format: binary
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UploadSession"
+	//   '404':
+	//     description: upload session not found
+	//   '409':
+	//     description: the offset doesn't match the upload session
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	session, err := a.getUploadSessionForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid %s header: %s", UploadOffsetHeader, err)))
+		return
+	}
+
+	if a.app.GetConfig().MaxFileSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, a.app.GetConfig().MaxFileSize)
+	}
+
+	session, err = a.app.UploadChunk(session, offset, r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("%w: the chunk exceeds the maximum size of %d bytes", model.ErrRequestEntityTooLarge, maxBytesErr.Limit)
+		}
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("uploadChunk",
+		mlog.String("uploadID", session.ID),
+		mlog.Int("offset", session.FileOffset),
+	)
+	a.uploadSessionResponse(w, r, session)
+}
+
+func (a *API) handleFinalizeUpload(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/{boardID}/uploads/{uploadID}/finalize finalizeUpload
+	//
+	// Completes an upload once all the bytes of the file have been
+	// received. The size and checksum of the file are verified, and the
+	// file is attached to the board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: ID of the team
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: uploadID
+	//   in: path
+	//   description: Upload session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/FileUploadResponse"
+	//   '400':
+	//     description: the upload is incomplete, or doesn't match its size or checksum
+	//   '404':
+	//     description: upload session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	session, err := a.getUploadSessionForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "uploadFile", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", session.BoardID)
+	auditRec.AddMeta("teamID", session.TeamID)
+	auditRec.AddMeta("filename", session.Filename)
+	auditRec.AddMeta("uploadID", session.ID)
+
+	fileID, err := a.app.FinalizeUpload(session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("finalizeUpload",
+		mlog.String("filename", session.Filename),
+		mlog.String("fileID", fileID),
+	)
+	data, err := json.Marshal(FileUploadResponse{FileID: fileID})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("fileID", fileID)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteUploadSession(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/{boardID}/uploads/{uploadID} deleteUploadSession
+	//
+	// Cancels an upload, the received chunks are removed.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: ID of the team
+	//   required: true
+	//   type: string
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: uploadID
+	//   in: path
+	//   description: Upload session ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: upload session not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	session, err := a.getUploadSessionForUser(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteUploadSession", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("uploadID", session.ID)
+
+	if err := a.app.DeleteUploadSession(session); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+// getUploadSessionForUser returns the upload session of the request.
+// Upload sessions are only visible to the user that created them, as long
+// as they can still change the cards of the board.
+func (a *API) getUploadSessionForUser(r *http.Request) (*model.UploadSession, error) {
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	uploadID := vars["uploadID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrPermission("access denied to make board changes")
+	}
+
+	session, err := a.app.GetUploadSession(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.BoardID != boardID {
+		return nil, model.NewErrNotFound("upload session ID=" + uploadID)
+	}
+	if session.UserID != userID {
+		return nil, model.NewErrPermission("access denied to upload session")
+	}
+	return session, nil
+}
+
+// uploadSessionResponse writes an upload session, with its offset in the
+// Upload-Offset header.
+func (a *API) uploadSessionResponse(w http.ResponseWriter, r *http.Request, session *model.UploadSession) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(session.FileOffset, 10))
+	jsonBytesResponse(w, http.StatusOK, data)
+}
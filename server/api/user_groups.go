@@ -0,0 +1,726 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerUserGroupsRoutes(r *mux.Router) {
+	// User groups APIs
+	r.HandleFunc("/teams/{teamID}/groups", a.sessionRequired(a.handleGetUserGroups)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/groups", a.sessionRequired(a.handleCreateUserGroup)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/groups/{groupID}", a.sessionRequired(a.handlePatchUserGroup)).Methods("PATCH")
+	r.HandleFunc("/teams/{teamID}/groups/{groupID}", a.sessionRequired(a.handleDeleteUserGroup)).Methods("DELETE")
+	r.HandleFunc("/teams/{teamID}/groups/{groupID}/members", a.sessionRequired(a.handleGetUserGroupMembers)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/groups/{groupID}/members/{userID}", a.sessionRequired(a.handleAddUserGroupMember)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/groups/{groupID}/members/{userID}", a.sessionRequired(a.handleDeleteUserGroupMember)).Methods("DELETE")
+
+	// Board groups APIs
+	r.HandleFunc("/boards/{boardID}/groups", a.sessionRequired(a.handleGetBoardGroups)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/groups", a.sessionRequired(a.handleAddBoardGroup)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/groups/{groupID}", a.sessionRequired(a.handleDeleteBoardGroup)).Methods("DELETE")
+}
+
+func (a *API) handleGetUserGroups(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/groups getUserGroups
+	//
+	// Returns the user groups of a team
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/UserGroup"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+
+	if err := a.checkCanViewUserGroups(getUserID(r), teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getUserGroups", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	groups, err := a.app.GetUserGroupsForTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(groups)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("groupCount", len(groups))
+	auditRec.Success()
+}
+
+func (a *API) handleCreateUserGroup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/groups createUserGroup
+	//
+	// Creates a user group in a team. The name of the group is used to
+	// mention all its members at once.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the name and the description of the group
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UserGroup"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserGroup"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+
+	userID := getUserID(r)
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage the user groups of the team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var group model.UserGroup
+	if err = json.Unmarshal(requestBody, &group); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	group.TeamID = teamID
+
+	auditRec := a.makeAuditRecord(r, "createUserGroup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("name", group.Name)
+
+	created, err := a.app.CreateUserGroup(&group, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("CreateUserGroup", mlog.String("teamID", teamID), mlog.String("groupID", created.ID))
+	auditRec.AddMeta("groupID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchUserGroup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /teams/{teamID}/groups/{groupID} patchUserGroup
+	//
+	// Updates the name and the description of a user group
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the user group patch to apply
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UserGroupPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserGroup"
+	//   '404':
+	//     description: user group not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	group, err := a.getUserGroupOfTeam(r, true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.UserGroupPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchUserGroup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", group.TeamID)
+	auditRec.AddMeta("groupID", group.ID)
+
+	updated, err := a.app.PatchUserGroup(group, &patch)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteUserGroup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/groups/{groupID} deleteUserGroup
+	//
+	// Deletes a user group. The members that the group added to boards
+	// are removed from them.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: user group not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	group, err := a.getUserGroupOfTeam(r, true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteUserGroup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", group.TeamID)
+	auditRec.AddMeta("groupID", group.ID)
+
+	if err = a.app.DeleteUserGroup(group); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("DeleteUserGroup", mlog.String("teamID", group.TeamID), mlog.String("groupID", group.ID))
+	auditRec.Success()
+}
+
+func (a *API) handleGetUserGroupMembers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/groups/{groupID}/members getUserGroupMembers
+	//
+	// Returns the IDs of the members of a user group
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         type: string
+	//   '404':
+	//     description: user group not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	group, err := a.getUserGroupOfTeam(r, false)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getUserGroupMembers", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("groupID", group.ID)
+
+	userIDs, err := a.app.GetUserGroupMemberIDs(group.ID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(userIDs)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("memberCount", len(userIDs))
+	auditRec.Success()
+}
+
+func (a *API) handleAddUserGroupMember(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/groups/{groupID}/members/{userID} addUserGroupMember
+	//
+	// Adds a member of the team to a user group. The user is added to the
+	// boards the group is linked to.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: user group not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	group, err := a.getUserGroupOfTeam(r, true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	memberID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "addUserGroupMember", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("groupID", group.ID)
+	auditRec.AddMeta("addedUserID", memberID)
+
+	if err = a.app.AddUserGroupMember(group, memberID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("AddUserGroupMember", mlog.String("groupID", group.ID), mlog.String("addedUserID", memberID))
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteUserGroupMember(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /teams/{teamID}/groups/{groupID}/members/{userID} deleteUserGroupMember
+	//
+	// Removes a member from a user group. The user is removed from the
+	// boards the group added it to.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: user group or member not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	group, err := a.getUserGroupOfTeam(r, true)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	memberID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "deleteUserGroupMember", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("groupID", group.ID)
+	auditRec.AddMeta("removedUserID", memberID)
+
+	if err = a.app.DeleteUserGroupMember(group, memberID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("DeleteUserGroupMember", mlog.String("groupID", group.ID), mlog.String("removedUserID", memberID))
+	auditRec.Success()
+}
+
+func (a *API) handleGetBoardGroups(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/groups getBoardGroups
+	//
+	// Returns the user groups linked to a board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardGroup"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(getUserID(r), boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardGroups", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	boardGroups, err := a.app.GetBoardGroupsForBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(boardGroups)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.AddMeta("groupCount", len(boardGroups))
+	auditRec.Success()
+}
+
+func (a *API) handleAddBoardGroup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/groups addBoardGroup
+	//
+	// Adds a user group to a board. All the members of the group become
+	// members of the board with the roles of the request, and the later
+	// changes of the group apply to the board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the group and the roles of its members on the board
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardGroup"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/BoardGroup'
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+
+	if _, err := a.app.GetBoard(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(getUserID(r), boardID, model.PermissionManageBoardRoles) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board members"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var boardGroup model.BoardGroup
+	if err = json.Unmarshal(requestBody, &boardGroup); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	boardGroup.BoardID = boardID
+
+	if boardGroup.GroupID == "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("empty groupID"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "addBoardGroup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("groupID", boardGroup.GroupID)
+
+	added, err := a.app.AddUserGroupToBoard(&boardGroup)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(added)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	a.logger.Debug("AddBoardGroup", mlog.String("boardID", boardID), mlog.String("groupID", boardGroup.GroupID))
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteBoardGroup(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/groups/{groupID} deleteBoardGroup
+	//
+	// Removes a user group from a board. The members that the group added
+	// to the board are removed from it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: groupID
+	//   in: path
+	//   description: User group ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: board group not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	groupID := vars["groupID"]
+
+	if !a.permissions.HasPermissionToBoard(getUserID(r), boardID, model.PermissionManageBoardRoles) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board members"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteBoardGroup", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("groupID", groupID)
+
+	if err := a.app.RemoveUserGroupFromBoard(boardID, groupID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	a.logger.Debug("DeleteBoardGroup", mlog.String("boardID", boardID), mlog.String("groupID", groupID))
+	auditRec.Success()
+}
+
+// checkCanViewUserGroups checks that a user can see the user groups of
+// a team. The guests can't, as the groups list users they may not see.
+func (a *API) checkCanViewUserGroups(userID, teamID string) error {
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		return model.NewErrPermission("access denied to team")
+	}
+
+	isGuest, err := a.userIsGuest(userID)
+	if err != nil {
+		return err
+	}
+	if isGuest {
+		return model.NewErrPermission("access denied to the user groups of the team")
+	}
+	return nil
+}
+
+// getUserGroupOfTeam returns the user group of a request, checking that
+// the user can see or manage the groups of the team and that the group
+// belongs to it.
+func (a *API) getUserGroupOfTeam(r *http.Request, manage bool) (*model.UserGroup, error) {
+	vars := mux.Vars(r)
+	teamID := vars["teamID"]
+	userID := getUserID(r)
+
+	if manage {
+		if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+			return nil, model.NewErrPermission("access denied to manage the user groups of the team")
+		}
+	} else if err := a.checkCanViewUserGroups(userID, teamID); err != nil {
+		return nil, err
+	}
+
+	group, err := a.app.GetUserGroup(vars["groupID"])
+	if err != nil {
+		return nil, err
+	}
+	if group.TeamID != teamID {
+		return nil, model.NewErrNotFound("user group ID=" + group.ID)
+	}
+	return group, nil
+}
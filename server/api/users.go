@@ -2,13 +2,19 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
 	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 func (a *API) registerUsersRoutes(r *mux.Router) {
@@ -16,6 +22,7 @@ func (a *API) registerUsersRoutes(r *mux.Router) {
 	r.HandleFunc("/users", a.sessionRequired(a.handleGetUsersList)).Methods("POST")
 	r.HandleFunc("/users/me", a.sessionRequired(a.handleGetMe)).Methods("GET")
 	r.HandleFunc("/users/me/memberships", a.sessionRequired(a.handleGetMyMemberships)).Methods("GET")
+	r.HandleFunc("/users/me/export", a.sessionRequired(a.handleExportMyData)).Methods("GET")
 	r.HandleFunc("/users/{userID}", a.sessionRequired(a.handleGetUser)).Methods("GET")
 	r.HandleFunc("/users/{userID}/config", a.sessionRequired(a.handleUpdateUserConfig)).Methods(http.MethodPut)
 	r.HandleFunc("/users/me/config", a.sessionRequired(a.handleGetUserPreferences)).Methods(http.MethodGet)
@@ -431,3 +438,84 @@ func (a *API) handleGetUserPreferences(w http.ResponseWriter, r *http.Request) {
 	jsonBytesResponse(w, http.StatusOK, data)
 	auditRec.Success()
 }
+
+func (a *API) handleExportMyData(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/export exportMyData
+	//
+	// Exports an archive of the personal data of the current user: its
+	// profile and preferences, board memberships, the cards it created or
+	// is assigned to, its comments, uploaded files, subscriptions and
+	// sessions, in JSON with a human-readable summary.
+	//
+	// ---
+	// produces:
+	// - application/octet-stream
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     content:
+	//       application-octet-stream:
+	//         type: string
+	//         format: binary
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "exportMyData", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	if err := a.writeUserDataExport(w, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec.Success()
+}
+
+// writeUserDataExport writes the personal data export of a user as a zip
+// archive attachment. The archive is built in a temporary file first, so
+// a failure is reported instead of sending a truncated archive.
+func (a *API) writeUserDataExport(w http.ResponseWriter, userID string) error {
+	export, err := a.app.GetUserDataExport(userID)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.CreateTemp("", "user-data-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		archive.Close()
+		os.Remove(archive.Name())
+	}()
+
+	if err = a.app.WriteUserDataExport(archive, export); err != nil {
+		return err
+	}
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("user-data-%s-%s.zip", export.Profile.Username, time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Transfer-Encoding", "binary")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+
+	if _, err = io.Copy(w, archive); err != nil {
+		// the headers are sent, the error can only be logged
+		a.logger.Error("cannot send the user data export", mlog.String("userID", userID), mlog.Err(err))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/mattermost/focalboard/server/auth"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/email"
+	"github.com/mattermost/focalboard/server/services/filescan"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
 	"github.com/mattermost/focalboard/server/services/permissions"
@@ -39,6 +40,8 @@ type fileBackend interface {
 	CopyFile(oldPath, newPath string) error
 	MoveFile(oldPath, newPath string) error
 	WriteFile(fr io.Reader, path string) (int64, error)
+	AppendFile(fr io.Reader, path string) (int64, error)
+	FileSize(path string) (int64, error)
 	RemoveFile(path string) error
 }
 
@@ -46,6 +49,8 @@ type Services struct {
 	Auth             *auth.Auth
 	Store            store.Store
 	FilesBackend     fileBackend
+	BackupBackend    backupBackend
+	FileScanner      filescan.Scanner
 	Webhook          *webhook.Client
 	Metrics          *metrics.Metrics
 	Notifications    *notify.Service
@@ -62,6 +67,8 @@ type App struct {
 	auth                *auth.Auth
 	wsAdapter           ws.Adapter
 	filesBackend        fileBackend
+	backupBackend       backupBackend
+	fileScanner         filescan.Scanner
 	webhook             *webhook.Client
 	metrics             *metrics.Metrics
 	notifications       *notify.Service
@@ -73,6 +80,18 @@ type App struct {
 
 	cardLimitMux sync.RWMutex
 	cardLimit    int
+
+	textEditSessionsMux sync.Mutex
+	textEditSessions    map[string]*textEditSession
+
+	// archiveJobsRunning holds the IDs of the archive jobs being run by
+	// this server, so a job is never run twice at the same time.
+	archiveJobsRunning sync.Map
+
+	// storageReservations holds the storage reserved for each team by the
+	// files being saved, until their usage is saved.
+	storageReservationsMux sync.Mutex
+	storageReservations    map[string]int64
 }
 
 func (a *App) SetConfig(config *config.Configuration) {
@@ -90,6 +109,8 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		auth:                services.Auth,
 		wsAdapter:           wsAdapter,
 		filesBackend:        services.FilesBackend,
+		backupBackend:       services.BackupBackend,
+		fileScanner:         services.FileScanner,
 		webhook:             services.Webhook,
 		metrics:             services.Metrics,
 		notifications:       services.Notifications,
@@ -98,6 +119,8 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		permissions:         services.Permissions,
 		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger),
 		servicesAPI:         services.ServicesAPI,
+		textEditSessions:    make(map[string]*textEditSession),
+		storageReservations: make(map[string]int64),
 	}
 	app.initialize(services.SkipTemplateInit)
 	return app
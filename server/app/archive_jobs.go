@@ -0,0 +1,292 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	archiveJobsDirectory      = "archives"
+	archiveJobFileExtension   = ".boardarchive"
+	defaultArchiveJobExpiry   = 24 * time.Hour
+	archiveJobProgressStepPct = 5
+)
+
+// StartArchiveExportJob starts exporting an archive of the given boards
+// in the background. The archive is written to the files backend.
+func (a *App) StartArchiveExportJob(teamID, userID string, boardIDs []string) (*model.ArchiveJob, error) {
+	job := newArchiveJob(model.ArchiveJobTypeExport, teamID, userID)
+	job.BoardIDs = boardIDs
+	job.FilePath = archiveJobFilePath(teamID, job.ID, "export")
+
+	if err := a.store.CreateArchiveJob(job); err != nil {
+		return nil, err
+	}
+
+	a.startArchiveJob(job)
+	return job, nil
+}
+
+// StartArchiveImportJob stores the uploaded archive in the files backend
+// and imports it in the background with the given mode.
+func (a *App) StartArchiveImportJob(teamID, userID string, mode model.ImportMode, r io.Reader) (*model.ArchiveJob, error) {
+	mode, err := model.ParseImportMode(string(mode))
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	job := newArchiveJob(model.ArchiveJobTypeImport, teamID, userID)
+	job.ImportMode = mode
+	job.FilePath = archiveJobFilePath(teamID, job.ID, "import")
+
+	if _, err := a.filesBackend.WriteFile(r, job.FilePath); err != nil {
+		return nil, fmt.Errorf("unable to store the archive in the files storage: %w", err)
+	}
+
+	if err := a.store.CreateArchiveJob(job); err != nil {
+		return nil, err
+	}
+
+	a.startArchiveJob(job)
+	return job, nil
+}
+
+// GetArchiveJob returns an archive job.
+func (a *App) GetArchiveJob(jobID string) (*model.ArchiveJob, error) {
+	return a.store.GetArchiveJob(jobID)
+}
+
+// GetArchiveJobsForUser returns the archive jobs started by a user in a
+// team, latest first.
+func (a *App) GetArchiveJobsForUser(teamID, userID string) ([]*model.ArchiveJob, error) {
+	return a.store.GetArchiveJobs(model.QueryArchiveJobsOptions{TeamID: teamID, CreatedBy: userID})
+}
+
+// GetArchiveJobFile returns a reader for the archive exported by a job.
+func (a *App) GetArchiveJobFile(job *model.ArchiveJob) (ReadCloseSeeker, error) {
+	if job.Type != model.ArchiveJobTypeExport || job.Status != model.ArchiveJobStatusSuccess {
+		return nil, model.NewErrBadRequest("the archive of the job is not available")
+	}
+
+	exists, err := a.filesBackend.FileExists(job.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, model.NewErrNotFound("archive of job ID=" + job.ID)
+	}
+	return a.filesBackend.Reader(job.FilePath)
+}
+
+// ResumeArchiveJobs restarts the jobs that were interrupted, i.e. by a
+// server restart. Jobs are run again from the beginning.
+func (a *App) ResumeArchiveJobs() error {
+	jobs, err := a.store.GetArchiveJobs(model.QueryArchiveJobsOptions{
+		Statuses: []model.ArchiveJobStatus{model.ArchiveJobStatusPending, model.ArchiveJobStatusInProgress},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		a.logger.Info("Resuming archive job", mlog.String("job_id", job.ID), mlog.String("type", string(job.Type)))
+		a.startArchiveJob(job)
+	}
+	return nil
+}
+
+// CleanupExpiredArchiveJobs removes the finished jobs that have expired,
+// and their archives.
+func (a *App) CleanupExpiredArchiveJobs() (int, error) {
+	jobs, err := a.store.GetArchiveJobs(model.QueryArchiveJobsOptions{ExpiredBefore: utils.GetMillis()})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := a.removeArchiveJobFile(job); err != nil {
+			return 0, err
+		}
+		if err := a.store.DeleteArchiveJob(job.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	a.logger.Debug("CleanupExpiredArchiveJobs", mlog.Int("deleted", len(jobs)))
+	return len(jobs), nil
+}
+
+// startArchiveJob runs a job in the background, on a copy so the caller
+// can keep using the job.
+func (a *App) startArchiveJob(job *model.ArchiveJob) {
+	jobCopy := *job
+	go a.runArchiveJob(&jobCopy)
+}
+
+func (a *App) runArchiveJob(job *model.ArchiveJob) {
+	if _, running := a.archiveJobsRunning.LoadOrStore(job.ID, true); running {
+		return
+	}
+	defer a.archiveJobsRunning.Delete(job.ID)
+
+	job.Status = model.ArchiveJobStatusInProgress
+	job.Progress = 0
+	job.Error = ""
+	a.saveArchiveJob(job)
+
+	var err error
+	switch job.Type {
+	case model.ArchiveJobTypeExport:
+		err = a.runArchiveExportJob(job)
+	case model.ArchiveJobTypeImport:
+		err = a.runArchiveImportJob(job)
+		// the uploaded archive is not needed anymore
+		if errRemove := a.removeArchiveJobFile(job); errRemove != nil {
+			a.logger.Warn("Cannot remove imported archive", mlog.String("job_id", job.ID), mlog.Err(errRemove))
+		}
+	default:
+		err = fmt.Errorf("unknown archive job type %s", job.Type)
+	}
+
+	if err != nil {
+		a.logger.Error("Archive job failed", mlog.String("job_id", job.ID), mlog.Err(err))
+		job.Status = model.ArchiveJobStatusError
+		job.Error = err.Error()
+	} else {
+		job.Status = model.ArchiveJobStatusSuccess
+		job.Progress = 100
+	}
+	job.ExpireAt = utils.GetMillisForTime(time.Now().Add(a.archiveJobExpiry()))
+	a.saveArchiveJob(job)
+}
+
+func (a *App) runArchiveExportJob(job *model.ArchiveJob) error {
+	opt := model.ExportArchiveOptions{
+		TeamID:   job.TeamID,
+		BoardIDs: job.BoardIDs,
+		UserID:   job.CreatedBy,
+	}
+
+	// the archive is streamed to the files backend as it is written
+	pr, pw := io.Pipe()
+	go func() {
+		err := a.exportArchive(pw, opt, func(done, total int) {
+			a.updateArchiveJobProgress(job, done*100/total)
+		})
+		_ = pw.CloseWithError(err)
+	}()
+
+	_, err := a.filesBackend.WriteFile(pr, job.FilePath)
+	// unblock the export if the backend stopped reading early
+	_ = pr.CloseWithError(err)
+	return err
+}
+
+func (a *App) runArchiveImportJob(job *model.ArchiveJob) error {
+	reader, err := a.filesBackend.Reader(job.FilePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	size, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	pr := &progressReader{r: reader, onRead: func(read int64) {
+		if size > 0 {
+			a.updateArchiveJobProgress(job, int(read*100/size))
+		}
+	}}
+
+	opt := model.ImportArchiveOptions{
+		TeamID:     job.TeamID,
+		ModifiedBy: job.CreatedBy,
+		Mode:       job.ImportMode,
+	}
+	result, err := a.ImportArchive(pr, opt)
+	if err != nil {
+		return err
+	}
+	job.ImportResult = result
+	return nil
+}
+
+// updateArchiveJobProgress saves the progress of a job when it changed
+// enough since the last save.
+func (a *App) updateArchiveJobProgress(job *model.ArchiveJob, progress int) {
+	// the job is only done once the archive is completely written
+	if progress > 99 {
+		progress = 99
+	}
+	if progress-job.Progress < archiveJobProgressStepPct {
+		return
+	}
+	job.Progress = progress
+	a.saveArchiveJob(job)
+}
+
+func (a *App) saveArchiveJob(job *model.ArchiveJob) {
+	job.UpdateAt = utils.GetMillis()
+	if err := a.store.UpdateArchiveJob(job); err != nil {
+		a.logger.Error("Cannot save archive job", mlog.String("job_id", job.ID), mlog.Err(err))
+	}
+}
+
+func (a *App) removeArchiveJobFile(job *model.ArchiveJob) error {
+	exists, err := a.filesBackend.FileExists(job.FilePath)
+	if err != nil || !exists {
+		return err
+	}
+	return a.filesBackend.RemoveFile(job.FilePath)
+}
+
+func (a *App) archiveJobExpiry() time.Duration {
+	if a.config.ArchiveJobExpiryHours <= 0 {
+		return defaultArchiveJobExpiry
+	}
+	return time.Duration(a.config.ArchiveJobExpiryHours) * time.Hour
+}
+
+func newArchiveJob(jobType model.ArchiveJobType, teamID, userID string) *model.ArchiveJob {
+	now := utils.GetMillis()
+	return &model.ArchiveJob{
+		ID:        utils.NewID(utils.IDTypeNone),
+		Type:      jobType,
+		TeamID:    teamID,
+		CreatedBy: userID,
+		Status:    model.ArchiveJobStatusPending,
+		BoardIDs:  []string{},
+		CreateAt:  now,
+		UpdateAt:  now,
+	}
+}
+
+func archiveJobFilePath(teamID, jobID, suffix string) string {
+	return filepath.Join(archiveJobsDirectory, teamID, fmt.Sprintf("%s-%s%s", jobID, suffix, archiveJobFileExtension))
+}
+
+// progressReader reports the number of bytes read so far.
+type progressReader struct {
+	r      io.Reader
+	read   int64
+	onRead func(read int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	p.onRead(p.read)
+	return n, err
+}
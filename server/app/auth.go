@@ -77,7 +77,7 @@ func (a *App) GetUsersList(userIDs []string) ([]*model.User, error) {
 }
 
 // Login create a new user session if the authentication data is valid.
-func (a *App) Login(username, email, password, mfaToken string) (string, error) {
+func (a *App) Login(username, email, password, mfaToken string, client model.SessionClient) (string, error) {
 	var user *model.User
 	if username != "" {
 		var err error
@@ -91,21 +91,39 @@ func (a *App) Login(username, email, password, mfaToken string) (string, error)
 	if user == nil && email != "" {
 		var err error
 		user, err = a.store.GetUserByEmail(email)
-		if err != nil && model.IsErrNotFound(err) {
+		if err != nil && !model.IsErrNotFound(err) {
 			a.metrics.IncrementLoginFailCount(1)
 			return "", errors.Wrap(err, "invalid username or password")
 		}
 	}
 
-	if user == nil {
+	var attemptKeys []loginAttemptKey
+	var now int64
+	if a.loginThrottlingEnabled() {
+		now = utils.GetMillis()
+		attemptKeys = a.loginAttemptKeys(user, username, email, client.IPAddress)
+		if err := a.checkLoginAttempts(attemptKeys, now); err != nil {
+			a.metrics.IncrementLoginFailCount(1)
+			return "", err
+		}
+	}
+
+	if user == nil || !auth.ComparePassword(user.Password, password) {
 		a.metrics.IncrementLoginFailCount(1)
+		if user != nil {
+			a.logger.Debug("Invalid password for user", mlog.String("userID", user.ID))
+		}
+		if attemptKeys != nil {
+			if err := a.recordLoginFailure(attemptKeys, user, now); err != nil {
+				return "", err
+			}
+		}
 		return "", errors.New("invalid username or password")
 	}
 
-	if !auth.ComparePassword(user.Password, password) {
+	if user.IsExpiredGuest() {
 		a.metrics.IncrementLoginFailCount(1)
-		a.logger.Debug("Invalid password for user", mlog.String("userID", user.ID))
-		return "", errors.New("invalid username or password")
+		return "", errors.New("guest account expired")
 	}
 
 	authService := user.AuthService
@@ -118,13 +136,22 @@ func (a *App) Login(username, email, password, mfaToken string) (string, error)
 		Token:       utils.NewID(utils.IDTypeToken),
 		UserID:      user.ID,
 		AuthService: authService,
-		Props:       map[string]interface{}{},
+		Props: map[string]interface{}{
+			model.SessionPropUserAgent: client.UserAgent,
+			model.SessionPropIPAddress: client.IPAddress,
+		},
 	}
 	err := a.store.CreateSession(&session)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to create session")
 	}
 
+	if attemptKeys != nil {
+		if err := a.store.DeleteLoginAttempt(model.LoginAttemptKindUser, user.ID); err != nil {
+			a.logger.Error("unable to clear the failed login attempts", mlog.String("userID", user.ID), mlog.Err(err))
+		}
+	}
+
 	a.metrics.IncrementLoginCount(1)
 
 	// TODO: MFA verification
@@ -137,23 +164,48 @@ func (a *App) Logout(sessionID string) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to delete the session")
 	}
+	a.wsAdapter.CloseSessions(sessionID)
 
 	a.metrics.IncrementLogoutCount(1)
 
 	return nil
 }
 
-// RegisterUser creates a new user if the provided data is valid.
-func (a *App) RegisterUser(username, email, password string) error {
+// RegisterUser creates a new user if the provided data is valid, and adds
+// it to the team it registered to.
+func (a *App) RegisterUser(username, email, password, teamID string) error {
+	_, err := a.registerUser(&model.User{
+		Username: username,
+		Email:    email,
+	}, password, teamID)
+	return err
+}
+
+// RegisterGuest creates a new guest user if the provided data is valid,
+// and adds it to the team of the board it was invited to. The guest
+// account expires at expireAt, if set.
+func (a *App) RegisterGuest(username, email, password, teamID string, expireAt int64) (*model.User, error) {
+	return a.registerUser(&model.User{
+		Username:      username,
+		Email:         email,
+		IsGuest:       true,
+		GuestExpireAt: expireAt,
+	}, password, teamID)
+}
+
+func (a *App) registerUser(newUser *model.User, password, teamID string) (*model.User, error) {
+	username := newUser.Username
+	email := newUser.Email
+
 	var user *model.User
 	if username != "" {
 		var err error
 		user, err = a.store.GetUserByUsername(username)
 		if err != nil && !model.IsErrNotFound(err) {
-			return err
+			return nil, err
 		}
 		if user != nil {
-			return errors.New("The username already exists")
+			return nil, errors.New("The username already exists")
 		}
 	}
 
@@ -161,10 +213,10 @@ func (a *App) RegisterUser(username, email, password string) error {
 		var err error
 		user, err = a.store.GetUserByEmail(email)
 		if err != nil && !model.IsErrNotFound(err) {
-			return err
+			return nil, err
 		}
 		if user != nil {
-			return errors.New("The email already exists")
+			return nil, errors.New("The email already exists")
 		}
 	}
 
@@ -175,23 +227,36 @@ func (a *App) RegisterUser(username, email, password string) error {
 
 	err := auth.IsPasswordValid(password, passwordSettings)
 	if err != nil {
-		return errors.Wrap(err, "Invalid password")
+		return nil, errors.Wrap(err, "Invalid password")
+	}
+
+	// the guests are never system admins
+	newUser.Roles = model.SystemRoles(false)
+	if !newUser.IsGuest {
+		newUser.Roles, err = a.newUserRoles()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newUser.ID = utils.NewID(utils.IDTypeUser)
+	newUser.Password = auth.HashPassword(password)
+	newUser.AuthService = a.config.AuthMode
+	user, err = a.store.CreateUser(newUser)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to create the new user")
 	}
 
-	_, err = a.store.CreateUser(&model.User{
-		ID:          utils.NewID(utils.IDTypeUser),
-		Username:    username,
-		Email:       email,
-		Password:    auth.HashPassword(password),
-		MfaSecret:   "",
-		AuthService: a.config.AuthMode,
-		AuthData:    "",
+	err = a.store.SaveTeamMember(&model.TeamMember{
+		TeamID:   teamID,
+		UserID:   user.ID,
+		CreateAt: utils.GetMillis(),
 	})
 	if err != nil {
-		return errors.Wrap(err, "Unable to create the new user")
+		return nil, errors.Wrap(err, "Unable to add the new user to the team")
 	}
 
-	return nil
+	return user, nil
 }
 
 func (a *App) UpdateUserPassword(username, password string) error {
@@ -46,7 +46,7 @@ func TestLogin(t *testing.T) {
 
 	for _, test := range testcases {
 		t.Run(test.title, func(t *testing.T) {
-			token, err := th.App.Login(test.userName, test.email, test.password, test.mfa)
+			token, err := th.App.Login(test.userName, test.email, test.password, test.mfa, model.SessionClient{})
 			if test.isError {
 				require.Error(t, err)
 			} else {
@@ -109,11 +109,18 @@ func TestRegisterUser(t *testing.T) {
 	th.Store.EXPECT().GetUserByUsername("newUsername").Return(mockUser, errors.New("user not found"))
 	th.Store.EXPECT().GetUserByEmail("existingEmail").Return(mockUser, nil)
 	th.Store.EXPECT().GetUserByEmail("newEmail").Return(nil, model.NewErrNotFound("user"))
-	th.Store.EXPECT().CreateUser(gomock.Any()).Return(nil, nil)
+	th.Store.EXPECT().GetRegisteredUserCount().Return(1, nil)
+	th.Store.EXPECT().CreateUser(gomock.Any()).Return(&model.User{ID: "new-user-id"}, nil)
+	th.Store.EXPECT().SaveTeamMember(gomock.Any()).DoAndReturn(func(member *model.TeamMember) error {
+		require.Equal(t, "team-id", member.TeamID)
+		require.Equal(t, "new-user-id", member.UserID)
+		require.False(t, member.SchemeAdmin)
+		return nil
+	})
 
 	for _, test := range testcases {
 		t.Run(test.title, func(t *testing.T) {
-			err := th.App.RegisterUser(test.userName, test.email, test.password)
+			err := th.App.RegisterUser(test.userName, test.email, test.password, "team-id")
 			if test.isError {
 				require.Error(t, err)
 			} else {
@@ -0,0 +1,644 @@
+package app
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/krolaw/zipstream"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/backup"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backupVersion            = 1
+	backupFilePrefix         = "backup-"
+	backupFileExtension      = ".fbbackup"
+	backupEncryptedExtension = ".enc"
+	backupTimeLayout         = "20060102-150405.000"
+	backupBoardsDirectory    = "boards"
+	backupBoardsExtension    = ".boardarchive"
+)
+
+var (
+	errBackupsNotConfigured = errors.New("the backup storage is not configured")
+
+	backupNameRegexp = regexp.MustCompile(`^backup-\d{8}-\d{6}\.\d{3}\.fbbackup(\.enc)?$`)
+)
+
+// backupBackend is the storage of the backups.
+type backupBackend interface {
+	fileBackend
+	FileModTime(path string) (time.Time, error)
+	ListDirectory(path string) ([]string, error)
+}
+
+// CreateBackup writes a backup of the whole server to the backup storage:
+// the users, teams with their members and board roles, boards with their
+// content and files, sidebar categories and sharing settings. The backup
+// is encrypted when a passphrase is configured, and the oldest backups
+// beyond the retention count are removed.
+func (a *App) CreateBackup() (*model.Backup, error) {
+	if a.backupBackend == nil {
+		return nil, errBackupsNotConfigured
+	}
+
+	now := time.Now()
+	encrypted := a.config.BackupPassphrase != ""
+	name := backupFilePrefix + now.UTC().Format(backupTimeLayout) + backupFileExtension
+	if encrypted {
+		name += backupEncryptedExtension
+	}
+
+	// the backup is streamed to the storage as it is written
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(a.writeBackup(pw, now))
+	}()
+
+	size, err := a.backupBackend.WriteFile(pr, name)
+	_ = pr.CloseWithError(err)
+	if err != nil {
+		// partial backups can't be restored
+		if errRemove := a.backupBackend.RemoveFile(name); errRemove != nil {
+			a.logger.Warn("Cannot remove partial backup", mlog.String("name", name), mlog.Err(errRemove))
+		}
+		return nil, fmt.Errorf("cannot write backup %s: %w", name, err)
+	}
+
+	if err := a.purgeOldBackups(); err != nil {
+		a.logger.Warn("Cannot remove old backups", mlog.Err(err))
+	}
+
+	a.logger.Info("Backup created", mlog.String("name", name), mlog.Int("size", size))
+	return &model.Backup{
+		Name:      name,
+		Size:      size,
+		CreateAt:  utils.GetMillisForTime(now),
+		Encrypted: encrypted,
+	}, nil
+}
+
+// GetBackups returns the backups of the backup storage, latest first.
+func (a *App) GetBackups() ([]*model.Backup, error) {
+	if a.backupBackend == nil {
+		return nil, errBackupsNotConfigured
+	}
+
+	paths, err := a.backupBackend.ListDirectory("")
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []*model.Backup{}
+	for _, p := range paths {
+		name := filepath.Base(p)
+		if !backupNameRegexp.MatchString(name) {
+			continue
+		}
+
+		size, err := a.backupBackend.FileSize(name)
+		if err != nil {
+			return nil, err
+		}
+		modTime, err := a.backupBackend.FileModTime(name)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, &model.Backup{
+			Name:      name,
+			Size:      size,
+			CreateAt:  utils.GetMillisForTime(modTime),
+			Encrypted: strings.HasSuffix(name, backupEncryptedExtension),
+		})
+	}
+
+	// names start with the creation time
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// RestoreBackup restores a backup into this server, typically a fresh
+// one. Users that already exist are kept, and the boards of the backup
+// overwrite the existing boards with the same IDs. Encrypted backups use
+// the given passphrase, or the configured one if empty.
+func (a *App) RestoreBackup(name, passphrase string) (*model.RestoreBackupResult, error) {
+	if a.backupBackend == nil {
+		return nil, errBackupsNotConfigured
+	}
+	if !backupNameRegexp.MatchString(name) {
+		return nil, model.NewErrBadRequest("invalid backup name " + name)
+	}
+
+	exists, err := a.backupBackend.FileExists(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, model.NewErrNotFound("backup " + name)
+	}
+
+	reader, err := a.backupBackend.Reader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	br := bufio.NewReader(reader)
+	var r io.Reader = br
+	if backup.IsEncrypted(br) {
+		if passphrase == "" {
+			passphrase = a.config.BackupPassphrase
+		}
+		r, err = backup.NewDecryptReader(br, passphrase)
+		if err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+	}
+
+	result, err := a.restoreBackup(r)
+	if errors.Is(err, backup.ErrDecrypt) || errors.Is(err, backup.ErrTruncated) {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore backup %s: %w", name, err)
+	}
+
+	a.logger.Info("Backup restored",
+		mlog.String("name", name),
+		mlog.Int("users", result.Users),
+		mlog.Int("boards", result.Boards),
+		mlog.Int("conflicts", len(result.Conflicts)),
+	)
+	return result, nil
+}
+
+// purgeOldBackups removes the oldest backups beyond the retention count.
+func (a *App) purgeOldBackups() error {
+	if a.config.BackupRetentionCount <= 0 {
+		return nil
+	}
+
+	backups, err := a.GetBackups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= a.config.BackupRetentionCount {
+		return nil
+	}
+
+	for _, b := range backups[a.config.BackupRetentionCount:] {
+		if err := a.backupBackend.RemoveFile(b.Name); err != nil {
+			return err
+		}
+		a.logger.Debug("Old backup removed", mlog.String("name", b.Name))
+	}
+	return nil
+}
+
+// writeBackup writes the content of a backup. The files are written in
+// the order they must be restored.
+func (a *App) writeBackup(w io.Writer, now time.Time) (errs error) {
+	if a.config.BackupPassphrase != "" {
+		ew, err := backup.NewEncryptWriter(w, a.config.BackupPassphrase)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := ew.Close(); err != nil && errs == nil {
+				errs = err
+			}
+		}()
+		w = ew
+	}
+
+	zw := zip.NewWriter(w)
+
+	if err := writeBackupFile(zw, "version.json", func(enc *json.Encoder) error {
+		return enc.Encode(model.BackupHeader{Version: backupVersion, Date: utils.GetMillisForTime(now)})
+	}); err != nil {
+		return err
+	}
+
+	// the deactivated users are included, as their content still
+	// references them
+	users, err := a.store.GetAllUsers(model.UserQueryOptions{IncludeDeactivated: true})
+	if err != nil {
+		return err
+	}
+	if err = writeBackupFile(zw, "users.jsonl", func(enc *json.Encoder) error {
+		for _, user := range users {
+			if err := enc.Encode(model.NewBackupUser(user)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	teams, err := a.store.GetAllTeams()
+	if err != nil {
+		return err
+	}
+	if err = writeBackupFile(zw, "teams.jsonl", func(enc *json.Encoder) error {
+		for _, team := range teams {
+			if err := enc.Encode(team); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err = writeBackupFile(zw, "board_roles.jsonl", func(enc *json.Encoder) error {
+		for _, team := range teams {
+			roles, err := a.store.GetCustomBoardRolesForTeam(team.ID)
+			if err != nil {
+				return err
+			}
+			for _, role := range roles {
+				if err := enc.Encode(role); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// all the boards, per team
+	boards, _, err := a.store.GetBoardsForCompliance(model.QueryBoardsForComplianceOptions{})
+	if err != nil {
+		return err
+	}
+	boardIDsByTeam := map[string][]string{}
+	for _, board := range boards {
+		boardIDsByTeam[board.TeamID] = append(boardIDsByTeam[board.TeamID], board.ID)
+	}
+	teamIDs := make([]string, 0, len(boardIDsByTeam))
+	for teamID := range boardIDsByTeam {
+		teamIDs = append(teamIDs, teamID)
+	}
+	sort.Strings(teamIDs)
+
+	for _, teamID := range teamIDs {
+		fw, err := zw.Create(path.Join(backupBoardsDirectory, teamID+backupBoardsExtension))
+		if err != nil {
+			return err
+		}
+		opt := model.ExportArchiveOptions{TeamID: teamID, BoardIDs: boardIDsByTeam[teamID]}
+		if err := a.exportArchive(fw, opt, nil); err != nil {
+			return fmt.Errorf("cannot write the boards of team %s: %w", teamID, err)
+		}
+	}
+
+	// the categories of the teams without boards are empty
+	for _, team := range teams {
+		if _, ok := boardIDsByTeam[team.ID]; !ok {
+			teamIDs = append(teamIDs, team.ID)
+		}
+	}
+	if err = writeBackupFile(zw, "categories.jsonl", func(enc *json.Encoder) error {
+		for _, teamID := range teamIDs {
+			for _, user := range users {
+				categoryBoards, err := a.store.GetUserCategoryBoards(user.ID, teamID)
+				if err != nil {
+					return err
+				}
+				for _, cb := range categoryBoards {
+					if err := enc.Encode(cb); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err = writeBackupFile(zw, "team_members.jsonl", func(enc *json.Encoder) error {
+		for _, teamID := range teamIDs {
+			members, err := a.store.GetTeamMembers(teamID)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				if err := enc.Encode(member); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err = writeBackupFile(zw, "sharing.jsonl", func(enc *json.Encoder) error {
+		for _, board := range boards {
+			sharing, err := a.store.GetSharing(board.ID)
+			if model.IsErrNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(sharing); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeBackupFile(zw *zip.Writer, name string, write func(enc *json.Encoder) error) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	return write(json.NewEncoder(w))
+}
+
+func (a *App) restoreBackup(r io.Reader) (*model.RestoreBackupResult, error) {
+	result := &model.RestoreBackupResult{
+		SkippedUsers: []string{},
+		Conflicts:    []model.ImportConflict{},
+	}
+
+	zr := zipstream.NewReader(r)
+	for {
+		hdr, err := zr.Next()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		dir, filename := path.Split(hdr.Name)
+		switch {
+		case hdr.Name == "version.json":
+			var header model.BackupHeader
+			if err := json.NewDecoder(zr).Decode(&header); err != nil {
+				return nil, fmt.Errorf("cannot parse version.json: %w", err)
+			}
+			if header.Version != backupVersion {
+				return nil, model.NewErrBadRequest(fmt.Sprintf("unsupported backup version %d", header.Version))
+			}
+		case hdr.Name == "users.jsonl":
+			err = a.restoreBackupUsers(zr, result)
+		case hdr.Name == "teams.jsonl":
+			err = a.restoreBackupTeams(zr, result)
+		case hdr.Name == "team_members.jsonl":
+			err = a.restoreBackupTeamMembers(zr, result)
+		case hdr.Name == "board_roles.jsonl":
+			err = a.restoreBackupCustomBoardRoles(zr, result)
+		case path.Clean(dir) == backupBoardsDirectory:
+			err = a.restoreBackupBoards(zr, strings.TrimSuffix(filename, backupBoardsExtension), result)
+		case hdr.Name == "categories.jsonl":
+			err = a.restoreBackupCategories(zr, result)
+		case hdr.Name == "sharing.jsonl":
+			err = a.restoreBackupSharing(zr, result)
+		default:
+			a.logger.Warn("Skipping unknown file in backup", mlog.String("name", hdr.Name))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (a *App) restoreBackupUsers(r io.Reader, result *model.RestoreBackupResult) error {
+	// the deactivated users are included, as their IDs, usernames and
+	// emails can't be reused either
+	users, err := a.store.GetAllUsers(model.UserQueryOptions{IncludeDeactivated: true})
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, user := range users {
+		existing["id:"+user.ID] = true
+		existing["username:"+user.Username] = true
+		if user.Email != "" {
+			existing["email:"+user.Email] = true
+		}
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var backupUser model.BackupUser
+		if err := dec.Decode(&backupUser); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse users.jsonl: %w", err)
+		}
+
+		// a user with the same ID, username or email exists
+		if existing["id:"+backupUser.ID] || existing["username:"+backupUser.Username] ||
+			(backupUser.Email != "" && existing["email:"+backupUser.Email]) {
+			result.SkippedUsers = append(result.SkippedUsers, backupUser.Username)
+			continue
+		}
+
+		if _, err := a.store.CreateUser(backupUser.ToUser()); err != nil {
+			return fmt.Errorf("cannot restore user %s: %w", backupUser.Username, err)
+		}
+		result.Users++
+	}
+}
+
+func (a *App) restoreBackupTeams(r io.Reader, result *model.RestoreBackupResult) error {
+	dec := json.NewDecoder(r)
+	for {
+		var team model.Team
+		if err := dec.Decode(&team); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse teams.jsonl: %w", err)
+		}
+
+		if err := a.store.UpsertTeamSettings(team); err != nil {
+			return fmt.Errorf("cannot restore team %s: %w", team.ID, err)
+		}
+		if err := a.store.UpsertTeamSignupToken(team); err != nil {
+			return fmt.Errorf("cannot restore team %s: %w", team.ID, err)
+		}
+		if err := a.store.UpdateTeam(&team); err != nil {
+			return fmt.Errorf("cannot restore team %s: %w", team.ID, err)
+		}
+		result.Teams++
+	}
+}
+
+func (a *App) restoreBackupTeamMembers(r io.Reader, result *model.RestoreBackupResult) error {
+	dec := json.NewDecoder(r)
+	for {
+		var member model.TeamMember
+		if err := dec.Decode(&member); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse team_members.jsonl: %w", err)
+		}
+
+		if err := a.store.SaveTeamMember(&member); err != nil {
+			return fmt.Errorf("cannot restore member %s of team %s: %w", member.UserID, member.TeamID, err)
+		}
+		result.TeamMembers++
+	}
+}
+
+func (a *App) restoreBackupCustomBoardRoles(r io.Reader, result *model.RestoreBackupResult) error {
+	dec := json.NewDecoder(r)
+	for {
+		var role model.CustomBoardRole
+		if err := dec.Decode(&role); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse board_roles.jsonl: %w", err)
+		}
+
+		err := a.store.UpdateCustomBoardRole(&role)
+		if model.IsErrNotFound(err) {
+			err = a.store.CreateCustomBoardRole(&role)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot restore board role %s: %w", role.ID, err)
+		}
+		result.BoardRoles++
+	}
+}
+
+func (a *App) restoreBackupBoards(r io.Reader, teamID string, result *model.RestoreBackupResult) error {
+	opt := model.ImportArchiveOptions{
+		TeamID:     teamID,
+		ModifiedBy: model.SystemUserID,
+		Mode:       model.ImportModeOverwrite,
+	}
+	importResult, err := a.ImportArchive(r, opt)
+	if err != nil {
+		return fmt.Errorf("cannot restore the boards of team %s: %w", teamID, err)
+	}
+
+	result.Boards += len(importResult.BoardsCreated) + len(importResult.BoardsUpdated)
+	result.Conflicts = append(result.Conflicts, importResult.Conflicts...)
+	return nil
+}
+
+func (a *App) restoreBackupCategories(r io.Reader, result *model.RestoreBackupResult) error {
+	dec := json.NewDecoder(r)
+	for {
+		var categoryBoards model.CategoryBoards
+		if err := dec.Decode(&categoryBoards); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse categories.jsonl: %w", err)
+		}
+
+		if err := a.restoreBackupCategory(categoryBoards); err != nil {
+			return fmt.Errorf("cannot restore category %s: %w", categoryBoards.ID, err)
+		}
+		result.Categories++
+	}
+}
+
+func (a *App) restoreBackupCategory(categoryBoards model.CategoryBoards) error {
+	category := categoryBoards.Category
+
+	// restoring the boards created the default category of their members
+	existingID := ""
+	if category.Type == model.CategoryTypeSystem {
+		categories, err := a.store.GetUserCategories(category.UserID, category.TeamID)
+		if err != nil && !model.IsErrNotFound(err) {
+			return err
+		}
+		for _, c := range categories {
+			if c.Type == model.CategoryTypeSystem && c.Name == category.Name {
+				existingID = c.ID
+				break
+			}
+		}
+	} else if _, err := a.store.GetCategory(category.ID); err == nil {
+		existingID = category.ID
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	if existingID != "" {
+		category.ID = existingID
+		if err := a.store.UpdateCategory(category); err != nil {
+			return err
+		}
+	} else if err := a.store.CreateCategory(category); err != nil {
+		return err
+	}
+
+	boardIDs := make([]string, 0, len(categoryBoards.BoardMetadata))
+	for _, metadata := range categoryBoards.BoardMetadata {
+		boardIDs = append(boardIDs, metadata.BoardID)
+	}
+	if err := a.store.AddUpdateCategoryBoard(category.UserID, category.ID, boardIDs); err != nil {
+		return err
+	}
+
+	for _, metadata := range categoryBoards.BoardMetadata {
+		if !metadata.Hidden {
+			continue
+		}
+		if err := a.store.SetBoardVisibility(category.UserID, category.ID, metadata.BoardID, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) restoreBackupSharing(r io.Reader, result *model.RestoreBackupResult) error {
+	dec := json.NewDecoder(r)
+	for {
+		var sharing model.Sharing
+		if err := dec.Decode(&sharing); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("cannot parse sharing.jsonl: %w", err)
+		}
+
+		// the board may have been skipped
+		if _, err := a.store.GetBoard(sharing.ID); model.IsErrNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if err := a.store.UpsertSharing(sharing); err != nil {
+			return fmt.Errorf("cannot restore the sharing of board %s: %w", sharing.ID, err)
+		}
+		result.Sharing++
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func (a *App) GetCustomBoardRole(id string) (*model.CustomBoardRole, error) {
+	return a.store.GetCustomBoardRole(id)
+}
+
+func (a *App) GetCustomBoardRolesForTeam(teamID string) ([]*model.CustomBoardRole, error) {
+	return a.store.GetCustomBoardRolesForTeam(teamID)
+}
+
+// CreateCustomBoardRole creates a new custom board role in a team.
+func (a *App) CreateCustomBoardRole(role *model.CustomBoardRole, userID string) (*model.CustomBoardRole, error) {
+	if err := role.IsValid(); err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	role.ID = utils.NewID(utils.IDTypeNone)
+	role.CreatedBy = userID
+	role.CreateAt = now
+	role.UpdateAt = now
+
+	if err := a.store.CreateCustomBoardRole(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// PatchCustomBoardRole updates a custom board role. The changes apply
+// right away to the board members the role is assigned to.
+func (a *App) PatchCustomBoardRole(role *model.CustomBoardRole, patch *model.CustomBoardRolePatch) (*model.CustomBoardRole, error) {
+	role = patch.Patch(role)
+	if err := role.IsValid(); err != nil {
+		return nil, err
+	}
+	role.UpdateAt = utils.GetMillis()
+
+	if err := a.store.UpdateCustomBoardRole(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteCustomBoardRole deletes a custom board role. The board members
+// it was assigned to keep their scheme roles.
+func (a *App) DeleteCustomBoardRole(id string) error {
+	return a.store.DeleteCustomBoardRole(id)
+}
+
+// checkCustomBoardRole checks that the custom role assigned to a board
+// member is defined in the team of the board.
+func (a *App) checkCustomBoardRole(board *model.Board, roleID string) error {
+	if roleID == "" {
+		return nil
+	}
+
+	role, err := a.store.GetCustomBoardRole(roleID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrBadRequest("custom board role not found")
+	}
+	if err != nil {
+		return err
+	}
+	if role.TeamID != board.TeamID {
+		return model.NewErrBadRequest("custom board role must be defined in the team of the board")
+	}
+	return nil
+}
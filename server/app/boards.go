@@ -222,8 +222,29 @@ func (a *App) DuplicateBoard(boardID, userID, toTeam string, asTemplate bool) (*
 	return bab, members, err
 }
 
-func (a *App) GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error) {
-	return a.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+// GetBoardsForUserAndTeam returns the boards of a team that the user
+// can access. Archived boards are only included if includeArchived is
+// true.
+func (a *App) GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards, includeArchived bool) ([]*model.Board, error) {
+	boards, err := a.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+	if err != nil {
+		return nil, err
+	}
+
+	if includeArchived {
+		return boards, nil
+	}
+	return filterBoardsByArchived(boards, false), nil
+}
+
+// GetArchivedBoardsForUserAndTeam returns the archived boards of a team
+// that the user can access.
+func (a *App) GetArchivedBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error) {
+	boards, err := a.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+	if err != nil {
+		return nil, err
+	}
+	return filterBoardsByArchived(boards, true), nil
 }
 
 func (a *App) GetTemplateBoards(teamID, userID string) ([]*model.Board, error) {
@@ -528,6 +549,10 @@ func (a *App) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, e
 		return existingMembership, nil
 	}
 
+	if err = a.checkCustomBoardRole(board, member.CustomRoleID); err != nil {
+		return nil, err
+	}
+
 	newMember, err := a.store.SaveMember(member)
 	if err != nil {
 		return nil, err
@@ -572,6 +597,10 @@ func (a *App) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember,
 		return nil, err
 	}
 
+	if err = a.checkCustomBoardRole(board, member.CustomRoleID); err != nil {
+		return nil, err
+	}
+
 	// if we're updating an admin, we need to check that there is at
 	// least still another admin on the board
 	if oldMember.SchemeAdmin && !member.SchemeAdmin {
@@ -656,8 +685,35 @@ func (a *App) DeleteBoardMember(boardID, userID string) error {
 	return nil
 }
 
-func (a *App) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
-	return a.store.SearchBoardsForUser(term, searchField, userID, includePublicBoards)
+func (a *App) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards, includeArchived bool) ([]*model.Board, error) {
+	boards, err := a.store.SearchBoardsForUser(term, searchField, userID, includePublicBoards)
+	if err != nil {
+		return nil, err
+	}
+	boards = a.filterBoardsByTeamAccess(boards, userID)
+
+	if includeArchived {
+		return boards, nil
+	}
+	return filterBoardsByArchived(boards, false), nil
+}
+
+// filterBoardsByTeamAccess removes the boards of the teams the user can't
+// access.
+func (a *App) filterBoardsByTeamAccess(boards []*model.Board, userID string) []*model.Board {
+	teamAccess := map[string]bool{}
+	filtered := make([]*model.Board, 0, len(boards))
+	for _, board := range boards {
+		hasAccess, ok := teamAccess[board.TeamID]
+		if !ok {
+			hasAccess = a.permissions.HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam)
+			teamAccess[board.TeamID] = hasAccess
+		}
+		if hasAccess {
+			filtered = append(filtered, board)
+		}
+	}
+	return filtered
 }
 
 func (a *App) SearchBoardsForUserInTeam(teamID, term, userID string) ([]*model.Board, error) {
@@ -697,3 +753,44 @@ func (a *App) UndeleteBoard(boardID string, modifiedBy string) error {
 
 	return nil
 }
+
+// ArchiveBoard marks a board as archived. Archived boards are read-only
+// and hidden from the board lists unless explicitly requested.
+func (a *App) ArchiveBoard(boardID, userID string) (*model.Board, error) {
+	board, err := a.store.ArchiveBoard(boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		return nil
+	})
+
+	return board, nil
+}
+
+// UnarchiveBoard restores an archived board to its regular state.
+func (a *App) UnarchiveBoard(boardID, userID string) (*model.Board, error) {
+	board, err := a.store.UnarchiveBoard(boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		return nil
+	})
+
+	return board, nil
+}
+
+func filterBoardsByArchived(boards []*model.Board, archived bool) []*model.Board {
+	filtered := make([]*model.Board, 0, len(boards))
+	for _, board := range boards {
+		if board.IsArchived() == archived {
+			filtered = append(filtered, board)
+		}
+	}
+	return filtered
+}
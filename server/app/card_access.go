@@ -0,0 +1,283 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetCardAccess returns what a user can see of the cards of a board.
+func (a *App) GetCardAccess(board *model.Board, userID string) *model.CardAccess {
+	return permissions.GetCardAccess(a.permissions, board, userID)
+}
+
+// FilterBlocksForUser removes from the blocks of a board the private
+// cards a user can't see with their contents, and the values of the
+// restricted properties it can't view.
+func (a *App) FilterBlocksForUser(board *model.Board, userID string, blocks []*model.Block) ([]*model.Block, error) {
+	access := a.GetCardAccess(board, userID)
+	if access.IsUnrestricted() {
+		return blocks, nil
+	}
+
+	hiddenCardIDs, err := a.getHiddenCardIDs(board, access)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if hiddenCardIDs[block.ID] || hiddenCardIDs[block.ParentID] || !access.CanViewCard(block) {
+			continue
+		}
+		filtered = append(filtered, access.FilterBlock(block))
+	}
+	return filtered, nil
+}
+
+// FilterCardsForUser removes from the cards of a board the private ones a
+// user can't see, and the values of the restricted properties it can't
+// view.
+func (a *App) FilterCardsForUser(board *model.Board, userID string, cards []*model.Card) ([]*model.Card, error) {
+	access := a.GetCardAccess(board, userID)
+	if access.IsUnrestricted() {
+		return cards, nil
+	}
+
+	filtered := make([]*model.Card, 0, len(cards))
+	for _, card := range cards {
+		block := model.Card2Block(card)
+		if !access.CanViewCard(block) {
+			continue
+		}
+
+		filteredCard, err := model.Block2Card(access.FilterBlock(block))
+		if err != nil {
+			return nil, err
+		}
+		filtered = append(filtered, filteredCard)
+	}
+	return filtered, nil
+}
+
+// FilterBlockForUser returns the version of a block a user can see, or
+// nil if the block belongs to a private card the user can't see.
+func (a *App) FilterBlockForUser(userID string, block *model.Block) *model.Block {
+	return a.NewBlockFilter(block)(userID)
+}
+
+// NewBlockFilter returns the function filtering a block for each user it
+// is broadcast to, as FilterBlockForUser does. The board and the card of
+// the block are loaded once, and the access of each user is computed once.
+// The returned function isn't safe for concurrent use.
+func (a *App) NewBlockFilter(block *model.Block) func(userID string) *model.Block {
+	// the deleted blocks are broadcast without their contents
+	if block.DeleteAt != 0 {
+		return func(string) *model.Block { return block }
+	}
+
+	board, err := a.store.GetBoard(block.BoardID)
+	if err != nil {
+		a.logger.Error("cannot get board to filter block",
+			mlog.String("boardID", block.BoardID),
+			mlog.String("blockID", block.ID),
+			mlog.Err(err),
+		)
+		return func(string) *model.Block { return nil }
+	}
+
+	var card *model.Block
+	cardLoaded := false
+	filtered := map[string]*model.Block{}
+
+	return func(userID string) *model.Block {
+		if result, ok := filtered[userID]; ok {
+			return result
+		}
+
+		access := a.GetCardAccess(board, userID)
+		if access.IsUnrestricted() {
+			filtered[userID] = block
+			return block
+		}
+
+		if !cardLoaded && !access.CanViewPrivateCards() {
+			var ok bool
+			card, ok = a.getCardOfBlock(block)
+			if !ok {
+				filtered[userID] = nil
+				return nil
+			}
+			cardLoaded = true
+		}
+
+		viewedCard := block
+		if card != nil {
+			viewedCard = card
+		}
+
+		var result *model.Block
+		if access.CanViewCard(viewedCard) {
+			result = access.FilterBlock(block)
+		}
+		filtered[userID] = result
+		return result
+	}
+}
+
+// getCardOfBlock returns the card a content block belongs to, nil if the
+// block is a card or doesn't belong to any. It returns false if the card
+// can't be loaded.
+func (a *App) getCardOfBlock(block *model.Block) (*model.Block, bool) {
+	if block.Type == model.TypeCard || block.ParentID == "" || block.ParentID == block.BoardID {
+		return nil, true
+	}
+
+	parent, err := a.store.GetBlock(block.ParentID)
+	if err != nil && !model.IsErrNotFound(err) {
+		a.logger.Error("cannot get parent block to filter block",
+			mlog.String("parentID", block.ParentID),
+			mlog.String("blockID", block.ID),
+			mlog.Err(err),
+		)
+		return nil, false
+	}
+	return parent, true
+}
+
+// CanViewBlock returns true if a user can see a block of a board it has
+// access to, ie. if the block doesn't belong to a private card hidden to
+// the user.
+func (a *App) CanViewBlock(userID string, block *model.Block) bool {
+	return a.FilterBlockForUser(userID, block) != nil
+}
+
+// PrepareBlockPatchForUser checks that a user can apply a patch to a
+// block, and keeps in the patch the values of the restricted properties
+// hidden to the user, which otherwise would be removed as the patch
+// replaces all the property values of the block.
+func (a *App) PrepareBlockPatchForUser(userID string, block *model.Block, patch *model.BlockPatch) error {
+	if !a.CanViewBlock(userID, block) {
+		return model.NewErrPermission("access denied to private card")
+	}
+	if patch == nil {
+		return nil
+	}
+
+	value, updatesProperties := patch.UpdatedFields["properties"]
+	if !updatesProperties && !slices.Contains(patch.DeletedFields, "properties") {
+		return nil
+	}
+
+	board, err := a.store.GetBoard(block.BoardID)
+	if err != nil {
+		return err
+	}
+
+	hidden := a.GetCardAccess(board, userID).HiddenProperties()
+	if len(hidden) == 0 {
+		return nil
+	}
+
+	// the values of the hidden properties can't be removed with the rest
+	currentProperties, _ := block.Fields["properties"].(map[string]interface{})
+	properties, _ := value.(map[string]interface{})
+	for id := range hidden {
+		if _, ok := properties[id]; ok {
+			return model.NewErrPermission("access denied to restricted card property " + id)
+		}
+		if currentValue, ok := currentProperties[id]; ok {
+			if properties == nil {
+				return model.NewErrPermission("access denied to restricted card property " + id)
+			}
+			properties[id] = currentValue
+		}
+	}
+	return nil
+}
+
+// CheckBlocksForUser checks that a user can add new blocks to a board:
+// it can't add contents to the private cards hidden to it, nor set the
+// values of the restricted properties it can't view.
+func (a *App) CheckBlocksForUser(board *model.Board, userID string, blocks []*model.Block) error {
+	access := a.GetCardAccess(board, userID)
+	if access.IsUnrestricted() {
+		return nil
+	}
+
+	hiddenCardIDs, err := a.getHiddenCardIDs(board, access)
+	if err != nil {
+		return err
+	}
+
+	hidden := access.HiddenProperties()
+	for _, block := range blocks {
+		if hiddenCardIDs[block.ParentID] {
+			return model.NewErrPermission("access denied to private card")
+		}
+
+		properties, _ := block.Fields["properties"].(map[string]interface{})
+		for id := range properties {
+			if hidden[id] {
+				return model.NewErrPermission("access denied to restricted card property " + id)
+			}
+		}
+	}
+	return nil
+}
+
+// FilterBlockHistoryDiffForUser removes from a block history diff the
+// values of the restricted properties hidden to a user.
+func (a *App) FilterBlockHistoryDiffForUser(board *model.Board, userID string, diff *model.BlockHistoryDiff) *model.BlockHistoryDiff {
+	hidden := a.GetCardAccess(board, userID).HiddenProperties()
+	if len(hidden) == 0 {
+		return diff
+	}
+	return blockHistoryDiffWithoutProperties(diff, hidden)
+}
+
+func blockHistoryDiffWithoutProperties(diff *model.BlockHistoryDiff, ids map[string]bool) *model.BlockHistoryDiff {
+	filtered := *diff
+	if diff.OldBlock != nil {
+		filtered.OldBlock = diff.OldBlock.WithoutProperties(ids)
+	}
+	if diff.NewBlock != nil {
+		filtered.NewBlock = diff.NewBlock.WithoutProperties(ids)
+	}
+
+	filtered.PropDiffs = []model.PropDiff{}
+	for _, propDiff := range diff.PropDiffs {
+		if !ids[propDiff.ID] {
+			filtered.PropDiffs = append(filtered.PropDiffs, propDiff)
+		}
+	}
+
+	filtered.Children = make([]*model.BlockHistoryDiff, 0, len(diff.Children))
+	for _, child := range diff.Children {
+		filtered.Children = append(filtered.Children, blockHistoryDiffWithoutProperties(child, ids))
+	}
+	return &filtered
+}
+
+// getHiddenCardIDs returns the IDs of the private cards of a board that
+// are hidden to a user.
+func (a *App) getHiddenCardIDs(board *model.Board, access *model.CardAccess) (map[string]bool, error) {
+	hidden := map[string]bool{}
+	if access.CanViewPrivateCards() {
+		return hidden, nil
+	}
+
+	cards, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard})
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		if !access.CanViewCard(card) {
+			hidden[card.ID] = true
+		}
+	}
+	return hidden, nil
+}
@@ -0,0 +1,698 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/services/spreadsheet"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	cardsColumnID    = "Card ID"
+	cardsColumnTitle = "Title"
+
+	cardsImportActionCreate = "create"
+	cardsImportActionUpdate = "update"
+
+	cardsTimeLayout = "January 02, 2006 15:04"
+)
+
+var errCardsNoTitleColumn = errors.New("the first row must contain a Title column")
+
+// cardsDateLayouts are the date formats accepted when importing date
+// properties. The first one is the format used by PropDef.GetValue.
+var cardsDateLayouts = []string{
+	"January 02, 2006",
+	"January 2, 2006",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+// readOnlyPropertyTypes are computed from the card and can't be imported.
+var readOnlyPropertyTypes = map[string]bool{
+	"createdTime": true,
+	"createdBy":   true,
+	"updatedTime": true,
+	"updatedBy":   true,
+}
+
+// ExportBoardCards writes the cards of a board as a spreadsheet, with a
+// row per card and a column per card property visible to a user.
+func (a *App) ExportBoardCards(w io.Writer, boardID, userID string, format spreadsheet.Format) error {
+	board, err := a.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	cards, err := a.getBoardCards(boardID)
+	if err != nil {
+		return err
+	}
+
+	// the private cards and the restricted properties hidden to the user
+	// are left out
+	cards, err = a.FilterBlocksForUser(board, userID, cards)
+	if err != nil {
+		return err
+	}
+	hidden := a.GetCardAccess(board, userID).HiddenProperties()
+
+	users, err := a.getCardsUsers(cards, schema)
+	if err != nil {
+		return err
+	}
+
+	properties := []map[string]interface{}{}
+	for _, prop := range board.CardProperties {
+		if !hidden[getPropertyString(prop, "id")] {
+			properties = append(properties, prop)
+		}
+	}
+
+	header := []string{cardsColumnID, cardsColumnTitle}
+	for _, prop := range properties {
+		header = append(header, getPropertyString(prop, "name"))
+	}
+
+	rows := [][]string{header}
+	for _, card := range cards {
+		row := []string{card.ID, card.Title}
+		for _, prop := range properties {
+			row = append(row, a.cardPropertyString(card, schema[getPropertyString(prop, "id")], users))
+		}
+		rows = append(rows, row)
+	}
+
+	return spreadsheet.Write(w, format, board.Title, rows)
+}
+
+// cardPropertyString returns the value of a card property as shown to
+// the users.
+func (a *App) cardPropertyString(card *model.Block, def model.PropDef, users cardsUsers) string {
+	switch def.Type {
+	case "createdTime":
+		return utils.GetTimeForMillis(card.CreateAt).Format(cardsTimeLayout)
+	case "updatedTime":
+		return utils.GetTimeForMillis(card.UpdateAt).Format(cardsTimeLayout)
+	case "createdBy":
+		return users.usernameOrID(card.CreatedBy)
+	case "updatedBy":
+		return users.usernameOrID(card.ModifiedBy)
+	}
+
+	value, ok := cardProperties(card)[def.ID]
+	if !ok {
+		return ""
+	}
+	s, err := def.GetValue(value, users)
+	if err != nil {
+		// the value can reference a deleted option
+		a.logger.Debug("cannot resolve card property value",
+			mlog.String("card_id", card.ID),
+			mlog.String("property_id", def.ID),
+			mlog.Err(err),
+		)
+		return ""
+	}
+	return s
+}
+
+// cardsUsers are the users referenced by exported cards, by ID.
+type cardsUsers map[string]*model.User
+
+// GetUserByID resolves the person properties of the cards. The users
+// that don't exist anymore are nil, so their IDs are exported.
+func (u cardsUsers) GetUserByID(userID string) (*model.User, error) {
+	return u[userID], nil
+}
+
+func (u cardsUsers) usernameOrID(userID string) string {
+	if user, ok := u[userID]; ok {
+		return user.Username
+	}
+	return userID
+}
+
+// getCardsUsers loads at once the users that created or modified the
+// cards, or are set in their person properties.
+func (a *App) getCardsUsers(cards []*model.Block, schema model.PropSchema) (cardsUsers, error) {
+	userIDs := map[string]bool{}
+	for _, card := range cards {
+		userIDs[card.CreatedBy] = true
+		userIDs[card.ModifiedBy] = true
+
+		for propID, value := range cardProperties(card) {
+			switch schema[propID].Type {
+			case "person":
+				if userID, ok := value.(string); ok {
+					userIDs[userID] = true
+				}
+			case "multiPerson":
+				values, _ := value.([]interface{})
+				for _, v := range values {
+					if userID, ok := v.(string); ok {
+						userIDs[userID] = true
+					}
+				}
+			}
+		}
+	}
+	delete(userIDs, "")
+
+	users := cardsUsers{}
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for userID := range userIDs {
+		ids = append(ids, userID)
+	}
+	list, err := a.store.GetUsersList(ids, false, false)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	for _, user := range list {
+		users[user.ID] = user
+	}
+	return users, nil
+}
+
+// ImportBoardCards creates and updates the cards of a board from a
+// spreadsheet. Rows with the ID of an existing card update it, and the
+// other rows create new cards. Columns are matched by name to the card
+// properties of the board, and text properties are created for the
+// columns that don't match any.
+func (a *App) ImportBoardCards(boardID string, r io.Reader, format spreadsheet.Format, opt model.ImportCardsOptions) (*model.CardsImportResult, error) {
+	board, err := a.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := spreadsheet.Read(r, format, importMaxFileSize)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, model.NewErrBadRequest(errCardsNoTitleColumn.Error())
+	}
+
+	// the columns of the restricted properties hidden to the user are
+	// ignored, and its hidden private cards are not updated
+	access := a.GetCardAccess(board, opt.ModifiedBy)
+	imp := newCardsImport(board, opt.DryRun)
+	imp.hiddenProperties = access.HiddenProperties()
+	if err = imp.mapColumns(rows[0]); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	cards, err := a.getBoardCards(boardID)
+	if err != nil {
+		return nil, err
+	}
+	cardsByID := make(map[string]*model.Block, len(cards))
+	for _, card := range cards {
+		if access.CanViewCard(card) {
+			cardsByID[card.ID] = card
+		}
+	}
+	if imp.users, err = a.getCardsUsers(cards, imp.schema); err != nil {
+		return nil, err
+	}
+
+	newCards := []*model.Block{}
+	patches := &model.BlockPatchBatch{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		if isEmptyRow(row) {
+			continue
+		}
+
+		var card *model.Block
+		if cardID := cellValue(row, imp.idColumn); cardID != "" {
+			card = cardsByID[cardID]
+			if card == nil {
+				imp.warn(rowNumber, "card %s not found, a new card is created", cardID)
+			}
+		}
+
+		change, properties := a.importCardRow(imp, rowNumber, row, card)
+		if change == nil {
+			imp.result.Unchanged++
+			continue
+		}
+		imp.result.Changes = append(imp.result.Changes, *change)
+
+		if card == nil {
+			newCards = append(newCards, &model.Block{
+				ID:       utils.NewID(utils.IDTypeCard),
+				ParentID: boardID,
+				BoardID:  boardID,
+				Schema:   1,
+				Type:     model.TypeCard,
+				Title:    change.Title,
+				Fields: map[string]interface{}{
+					"icon":         "",
+					"isTemplate":   false,
+					"properties":   properties,
+					"contentOrder": []interface{}{},
+				},
+				CreatedBy:  opt.ModifiedBy,
+				ModifiedBy: opt.ModifiedBy,
+				CreateAt:   utils.GetMillis(),
+				UpdateAt:   utils.GetMillis(),
+			})
+			continue
+		}
+
+		title := change.Title
+		patches.BlockIDs = append(patches.BlockIDs, card.ID)
+		patches.BlockPatches = append(patches.BlockPatches, model.BlockPatch{
+			Title:         &title,
+			UpdatedFields: map[string]interface{}{"properties": properties},
+		})
+	}
+
+	if opt.DryRun {
+		return imp.result, nil
+	}
+
+	var boardPatch *model.BoardPatch
+	if imp.propertiesChanged {
+		boardPatch = &model.BoardPatch{UpdatedCardProperties: imp.cardProperties}
+	}
+
+	// the changes are made in a single transaction, so a failed import
+	// doesn't leave the board half imported
+	bab, err := a.store.ImportBoardCards(boardID, boardPatch, newCards, patches, opt.ModifiedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new cards are the create changes, in the same order
+	n := 0
+	for i := range imp.result.Changes {
+		if imp.result.Changes[i].Action == cardsImportActionCreate {
+			imp.result.Changes[i].CardID = newCards[n].ID
+			n++
+		}
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, updatedBoard := range bab.Boards {
+			a.wsAdapter.BroadcastBoardChange(updatedBoard.TeamID, updatedBoard)
+		}
+		for _, card := range bab.Blocks {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, card)
+			a.webhook.NotifyUpdate(card)
+			if oldCard, ok := cardsByID[card.ID]; ok {
+				a.metrics.IncrementBlocksPatched(1)
+				a.notifyBlockChanged(notify.Update, card, oldCard, opt.ModifiedBy)
+			} else {
+				a.metrics.IncrementBlocksInserted(1)
+				a.notifyBlockChanged(notify.Add, card, nil, opt.ModifiedBy)
+			}
+		}
+		return nil
+	})
+
+	return imp.result, nil
+}
+
+// importCardRow returns the change a row makes to a card, or nil if the
+// card is unchanged, and the resulting card properties.
+func (a *App) importCardRow(imp *cardsImport, rowNumber int, row []string, card *model.Block) (*model.CardsImportChange, map[string]interface{}) {
+	properties := map[string]interface{}{}
+	oldTitle := ""
+	if card != nil {
+		oldTitle = card.Title
+		for id, value := range cardProperties(card) {
+			properties[id] = value
+		}
+	}
+
+	for col, prop := range imp.columns {
+		if prop == nil || readOnlyPropertyTypes[getPropertyString(prop, "type")] {
+			continue
+		}
+		def := imp.schema[getPropertyString(prop, "id")]
+		cell := strings.TrimSpace(cellValue(row, col))
+
+		// values that are displayed the same are kept as they are, as
+		// formatting them loses information, i.e. the time of dates
+		if card != nil && strings.EqualFold(cell, a.cardPropertyString(card, def, imp.users)) {
+			continue
+		}
+		if cell == "" {
+			delete(properties, def.ID)
+			continue
+		}
+
+		value, err := a.parseCardPropertyValue(imp, prop, cell)
+		if err != nil {
+			imp.warn(rowNumber, "cannot import %q in column %s: %s", cell, def.Name, err)
+			continue
+		}
+		if value == nil {
+			delete(properties, def.ID)
+		} else {
+			properties[def.ID] = value
+		}
+	}
+
+	title := cellValue(row, imp.titleColumn)
+	newSchema := imp.currentSchema()
+	newProps, _ := model.ParseProperties(&model.Block{Fields: map[string]interface{}{"properties": properties}}, newSchema, a.store)
+	oldProps := model.BlockProperties{}
+	if card != nil {
+		oldProps, _ = model.ParseProperties(card, newSchema, a.store)
+	}
+	diffs := model.DiffProperties(oldProps, newProps)
+
+	change := &model.CardsImportChange{
+		Row:        rowNumber,
+		Action:     cardsImportActionCreate,
+		Title:      title,
+		Properties: diffs,
+	}
+	if diffs == nil {
+		change.Properties = []model.PropDiff{}
+	}
+	if card != nil {
+		if len(diffs) == 0 && title == oldTitle {
+			return nil, properties
+		}
+		change.Action = cardsImportActionUpdate
+		change.CardID = card.ID
+		if title != oldTitle {
+			change.OldTitle = oldTitle
+		}
+	}
+	return change, properties
+}
+
+// parseCardPropertyValue converts a spreadsheet cell into the value
+// stored for a property, adding options to select properties if needed.
+// A nil value means the property is unset.
+func (a *App) parseCardPropertyValue(imp *cardsImport, prop map[string]interface{}, cell string) (interface{}, error) {
+	switch getPropertyString(prop, "type") {
+	case "select":
+		return imp.optionID(prop, cell), nil
+
+	case "multiSelect":
+		ids := []interface{}{}
+		for _, value := range splitCellValues(cell) {
+			ids = append(ids, imp.optionID(prop, value))
+		}
+		return ids, nil
+
+	case "date":
+		return parseCardDate(cell)
+
+	case "checkbox":
+		checked, err := strconv.ParseBool(strings.ToLower(cell))
+		if err != nil {
+			return nil, err
+		}
+		if !checked {
+			return nil, nil
+		}
+		return "true", nil
+
+	case "number":
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return nil, err
+		}
+		return cell, nil
+
+	case "person":
+		return a.userIDForUsername(cell)
+
+	case "multiPerson":
+		ids := []interface{}{}
+		for _, username := range splitCellValues(cell) {
+			id, err := a.userIDForUsername(username)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	return cell, nil
+}
+
+func (a *App) userIDForUsername(username string) (string, error) {
+	user, err := a.store.GetUserByUsername(username)
+	if model.IsErrNotFound(err) || (err == nil && user == nil) {
+		return "", fmt.Errorf("user %s not found", username)
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// parseCardDate parses dates and date ranges as formatted by
+// PropDef.ParseDate.
+func parseCardDate(cell string) (string, error) {
+	parts := strings.SplitN(cell, " -> ", 2)
+	from, err := parseCardDateLayouts(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf(`{"from":%d}`, from), nil
+	}
+	to, err := parseCardDateLayouts(parts[1])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`{"from":%d,"to":%d}`, from, to), nil
+}
+
+func parseCardDateLayouts(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range cardsDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return utils.GetMillisForTime(t), nil
+		}
+	}
+	return 0, model.ErrInvalidDate
+}
+
+func (a *App) getBoardCards(boardID string) ([]*model.Block, error) {
+	blocks, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: boardID, BlockType: model.TypeCard})
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if isTemplate, _ := block.Fields["isTemplate"].(bool); isTemplate {
+			continue
+		}
+		cards = append(cards, block)
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		if cards[i].CreateAt != cards[j].CreateAt {
+			return cards[i].CreateAt < cards[j].CreateAt
+		}
+		return cards[i].ID < cards[j].ID
+	})
+	return cards, nil
+}
+
+// cardsImport holds the state of a cards import: the card properties of
+// the board, including the ones created by the import, and the result.
+type cardsImport struct {
+	cardProperties    []map[string]interface{}
+	schema            model.PropSchema
+	propertiesChanged bool
+
+	// users are the users referenced by the existing cards.
+	users cardsUsers
+
+	// hiddenProperties are the restricted properties the user can't edit
+	hiddenProperties map[string]bool
+
+	// columns maps each column to its card property, nil for the ID and
+	// title columns and the ignored ones.
+	columns     []map[string]interface{}
+	idColumn    int
+	titleColumn int
+
+	result *model.CardsImportResult
+}
+
+func newCardsImport(board *model.Board, dryRun bool) *cardsImport {
+	// the properties are copied as options can be added to them
+	cardProperties := make([]map[string]interface{}, 0, len(board.CardProperties))
+	for _, prop := range board.CardProperties {
+		newProp := make(map[string]interface{}, len(prop))
+		for k, v := range prop {
+			newProp[k] = v
+		}
+		if options, ok := prop["options"].([]interface{}); ok {
+			newProp["options"] = append([]interface{}{}, options...)
+		}
+		cardProperties = append(cardProperties, newProp)
+	}
+
+	return &cardsImport{
+		cardProperties: cardProperties,
+		idColumn:       -1,
+		titleColumn:    -1,
+		result: &model.CardsImportResult{
+			DryRun:        dryRun,
+			NewProperties: []model.CardsImportProperty{},
+			NewOptions:    []model.CardsImportOption{},
+			Changes:       []model.CardsImportChange{},
+			Warnings:      []string{},
+		},
+	}
+}
+
+func (imp *cardsImport) mapColumns(header []string) error {
+	byName := map[string]map[string]interface{}{}
+	for _, prop := range imp.cardProperties {
+		byName[strings.ToLower(getPropertyString(prop, "name"))] = prop
+	}
+
+	mapped := map[string]bool{}
+	imp.columns = make([]map[string]interface{}, len(header))
+	for col, name := range header {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		switch {
+		case name == "":
+			continue
+		case mapped[key]:
+			imp.warn(1, "column %s is duplicated and ignored", name)
+			continue
+		case key == strings.ToLower(cardsColumnID):
+			imp.idColumn = col
+		case key == strings.ToLower(cardsColumnTitle):
+			imp.titleColumn = col
+		default:
+			prop, ok := byName[key]
+			if ok && imp.hiddenProperties[getPropertyString(prop, "id")] {
+				imp.warn(1, "column %s is restricted and ignored", name)
+				continue
+			}
+			if !ok {
+				prop = map[string]interface{}{
+					"id":      utils.NewID(utils.IDTypeBlock),
+					"name":    name,
+					"type":    "text",
+					"options": []interface{}{},
+				}
+				imp.cardProperties = append(imp.cardProperties, prop)
+				imp.propertiesChanged = true
+				imp.result.NewProperties = append(imp.result.NewProperties, model.CardsImportProperty{
+					ID:   getPropertyString(prop, "id"),
+					Name: name,
+					Type: "text",
+				})
+			}
+			imp.columns[col] = prop
+		}
+		mapped[key] = true
+	}
+
+	if imp.titleColumn == -1 {
+		return errCardsNoTitleColumn
+	}
+	imp.schema = imp.currentSchema()
+	return nil
+}
+
+// optionID returns the ID of the option of a select property matching
+// the value, ignoring its case, adding the option if needed.
+func (imp *cardsImport) optionID(prop map[string]interface{}, value string) string {
+	options, _ := prop["options"].([]interface{})
+	for _, optionIface := range options {
+		option, ok := optionIface.(map[string]interface{})
+		if ok && strings.EqualFold(getPropertyString(option, "value"), value) {
+			return getPropertyString(option, "id")
+		}
+	}
+
+	id := utils.NewID(utils.IDTypeBlock)
+	prop["options"] = append(options, map[string]interface{}{
+		"id":    id,
+		"value": value,
+		"color": "propColorDefault",
+	})
+	imp.propertiesChanged = true
+	imp.result.NewOptions = append(imp.result.NewOptions, model.CardsImportOption{
+		Property: getPropertyString(prop, "name"),
+		Value:    value,
+	})
+	return id
+}
+
+// currentSchema returns the schema of the card properties, including the
+// properties and options added so far.
+func (imp *cardsImport) currentSchema() model.PropSchema {
+	schema, err := model.ParsePropertySchema(&model.Board{CardProperties: imp.cardProperties})
+	if err != nil {
+		return model.PropSchema{}
+	}
+	return schema
+}
+
+func (imp *cardsImport) warn(row int, format string, args ...interface{}) {
+	imp.result.Warnings = append(imp.result.Warnings, fmt.Sprintf("row %d: ", row)+fmt.Sprintf(format, args...))
+}
+
+func cardProperties(card *model.Block) map[string]interface{} {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	return properties
+}
+
+func getPropertyString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func cellValue(row []string, col int) string {
+	if col < 0 || col >= len(row) {
+		return ""
+	}
+	return row[col]
+}
+
+func splitCellValues(cell string) []string {
+	values := []string{}
+	for _, value := range strings.Split(cell, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func isEmptyRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
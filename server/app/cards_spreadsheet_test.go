@@ -0,0 +1,62 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/spreadsheet"
+)
+
+func TestExportBoardCards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("the users are loaded at once", func(t *testing.T) {
+		board := &model.Board{
+			ID:    testBoardID,
+			Title: "Board",
+			CardProperties: []map[string]interface{}{
+				{"id": "prop-owner", "name": "Owner", "type": "person"},
+				{"id": "prop-reviewers", "name": "Reviewers", "type": "multiPerson"},
+				{"id": "prop-created-by", "name": "Created by", "type": "createdBy"},
+			},
+		}
+		cards := []*model.Block{
+			{
+				ID: "card-1", BoardID: testBoardID, Type: model.TypeCard, Title: "Card 1", CreatedBy: "user-1", ModifiedBy: "user-1", CreateAt: 1,
+				Fields: map[string]interface{}{"properties": map[string]interface{}{
+					"prop-owner":     "user-2",
+					"prop-reviewers": []interface{}{"user-1", "deleted-user"},
+				}},
+			},
+			{
+				ID: "card-2", BoardID: testBoardID, Type: model.TypeCard, Title: "Card 2", CreatedBy: "user-2", ModifiedBy: "user-1", CreateAt: 2,
+				Fields: map[string]interface{}{"properties": map[string]interface{}{
+					"prop-owner": "user-1",
+				}},
+			},
+		}
+
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		// the cards are read again to find the private ones
+		th.Store.EXPECT().GetBlocks(gomock.Any()).Return(cards, nil).Times(2)
+		th.Store.EXPECT().GetUsersList(gomock.Any(), false, false).DoAndReturn(func(userIDs []string, _, _ bool) ([]*model.User, error) {
+			require.ElementsMatch(t, []string{"user-1", "user-2", "deleted-user"}, userIDs)
+			users := []*model.User{{ID: "user-1", Username: "alice"}, {ID: "user-2", Username: "bob"}}
+			return users, model.NewErrNotAllFound("user", userIDs)
+		}).Times(1)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, th.App.ExportBoardCards(buf, testBoardID, "", spreadsheet.FormatCSV))
+
+		rows, err := spreadsheet.Read(buf, spreadsheet.FormatCSV, int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, []string{"card-1", "Card 1", "bob", "alice, deleted-user", "alice"}, rows[1][:5])
+		require.Equal(t, []string{"card-2", "Card 2", "alice", "", "bob"}, rows[2][:5])
+	})
+}
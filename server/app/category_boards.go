@@ -84,7 +84,7 @@ func (a *App) createBoardsCategory(userID, teamID string, existingCategoryBoards
 	}
 
 	// get user's current team's baords
-	userTeamBoards, err := a.GetBoardsForUserAndTeam(userID, teamID, false)
+	userTeamBoards, err := a.GetBoardsForUserAndTeam(userID, teamID, false, true)
 	if err != nil {
 		return nil, fmt.Errorf("createBoardsCategory error fetching user's team's boards: %w", err)
 	}
@@ -16,7 +16,13 @@ var (
 	newline = []byte{'\n'}
 )
 
-func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs error) {
+func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) error {
+	return a.exportArchive(w, opt, nil)
+}
+
+// exportArchive writes the archive, calling onBoard after each board is
+// written with the number of boards done so far.
+func (a *App) exportArchive(w io.Writer, opt model.ExportArchiveOptions, onBoard func(done, total int)) (errs error) {
 	boards, err := a.getBoardsForArchive(opt.BoardIDs)
 	if err != nil {
 		return err
@@ -30,7 +36,9 @@ func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs e
 	// wrap the writer in a zip.
 	zw := zip.NewWriter(w)
 	defer func() {
-		merr.Append(zw.Close())
+		if err := zw.Close(); err != nil {
+			merr.Append(err)
+		}
 	}()
 
 	if err := a.writeArchiveVersion(zw); err != nil {
@@ -38,11 +46,14 @@ func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs e
 		return
 	}
 
-	for _, board := range boards {
+	for i, board := range boards {
 		if err := a.writeArchiveBoard(zw, board, opt); err != nil {
 			merr.Append(fmt.Errorf("cannot export board %s: %w", board.ID, err))
 			return
 		}
+		if onBoard != nil {
+			onBoard(i+1, len(boards))
+		}
 	}
 	return nil
 }
@@ -87,6 +98,13 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 		return err
 	}
 
+	if opt.UserID != "" {
+		blocks, err = a.FilterBlocksForUser(&board, opt.UserID, blocks)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, block := range blocks {
 		if err = a.writeArchiveBlockLine(w, block); err != nil {
 			return err
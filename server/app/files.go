@@ -21,6 +21,44 @@ var errEmptyFilename = errors.New("IsFileArchived: empty filename not allowed")
 var ErrFileNotFound = errors.New("file not found")
 
 func (a *App) SaveFile(reader io.Reader, teamID, boardID, filename string, asTemplate bool) (string, error) {
+	dest := newFileDestination(teamID, boardID, filename, asTemplate)
+
+	reader, mimeType, err := a.detectFileType(reader, filename)
+	if err != nil {
+		return "", err
+	}
+
+	// the file isn't stored beyond the storage left to the team, the
+	// quota is then checked with the size of the stored file
+	remaining, err := a.remainingStorage(teamID)
+	if err != nil {
+		return "", err
+	}
+	if remaining >= 0 {
+		reader = io.LimitReader(reader, remaining+1)
+	}
+
+	fileSize, appErr := a.filesBackend.WriteFile(reader, dest.path)
+	if appErr != nil {
+		return "", fmt.Errorf("unable to store the file in the files storage: %w", appErr)
+	}
+
+	return a.saveStoredFile(dest, teamID, boardID, filename, fileSize, mimeType)
+}
+
+// fileDestination is where a new file of a board is stored.
+type fileDestination struct {
+	// the name of the file, without the prefix character used for the
+	// fileinfo ID
+	createdFilename string
+	// the name of the file referenced by the blocks
+	name string
+	// the extension of the file, including the dot
+	extension string
+	path      string
+}
+
+func newFileDestination(teamID, boardID, filename string, asTemplate bool) fileDestination {
 	// NOTE: File extension includes the dot
 	fileExtension := strings.ToLower(filepath.Ext(filename))
 	if fileExtension == ".jpeg" {
@@ -32,24 +70,50 @@ func (a *App) SaveFile(reader io.Reader, teamID, boardID, filename string, asTem
 	if asTemplate {
 		newFileName = filename
 	}
-	filePath := getDestinationFilePath(asTemplate, teamID, boardID, newFileName)
 
-	fileSize, appErr := a.filesBackend.WriteFile(reader, filePath)
-	if appErr != nil {
-		return "", fmt.Errorf("unable to store the file in the files storage: %w", appErr)
+	return fileDestination{
+		createdFilename: createdFilename,
+		name:            newFileName,
+		extension:       fileExtension,
+		path:            getDestinationFilePath(asTemplate, teamID, boardID, newFileName),
+	}
+}
+
+// saveStoredFile checks a file written to its destination against the
+// storage quota and the malware scanner, then creates its fileinfo and
+// previews. The name of the file is returned.
+func (a *App) saveStoredFile(dest fileDestination, teamID, boardID, filename string, fileSize int64, mimeType string) (string, error) {
+	release, err := a.reserveStorage(teamID, fileSize)
+	if err != nil {
+		if errRemove := a.filesBackend.RemoveFile(dest.path); errRemove != nil {
+			a.logger.Error("Cannot remove a file exceeding the storage quota", mlog.String("path", dest.path), mlog.Err(errRemove))
+		}
+		return "", err
+	}
+	defer release()
+
+	if err := a.scanFile(dest.path, filename); err != nil {
+		return "", err
 	}
 
 	fileInfo := model.NewFileInfo(filename)
-	fileInfo.Id = getFileInfoID(createdFilename)
-	fileInfo.Path = filePath
+	fileInfo.Id = getFileInfoID(dest.createdFilename)
+	fileInfo.Path = dest.path
 	fileInfo.Size = fileSize
+	fileInfo.MimeType = mimeType
 
-	err := a.store.SaveFileInfo(fileInfo)
-	if err != nil {
+	if isPreviewImage(dest.extension) {
+		// the upload succeeds even if the previews can't be generated
+		if err := a.generateFilePreviews(fileInfo); err != nil {
+			a.logger.Warn("Cannot generate the previews of an uploaded file", mlog.String("path", dest.path), mlog.Err(err))
+		}
+	}
+
+	if err := a.saveFileInfo(fileInfo, teamID, boardID, dest.name); err != nil {
 		return "", err
 	}
 
-	return newFileName, nil
+	return dest.name, nil
 }
 
 func (a *App) GetFileInfo(filename string) (*mm_model.FileInfo, error) {
@@ -298,7 +362,7 @@ func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []*model.Block, a
 		}
 		fileInfo.Id = getFileInfoID(fileInfoID)
 		fileInfo.Path = destinationFilePath
-		err = a.store.SaveFileInfo(fileInfo)
+		err = a.saveFileInfo(fileInfo, destBoard.TeamID, destBoard.ID, destFilename)
 		if err != nil {
 			return nil, fmt.Errorf("CopyCardFiles: cannot create fileinfo: %w", err)
 		}
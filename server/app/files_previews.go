@@ -0,0 +1,204 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"path/filepath"
+	"strings"
+
+	// register the decoders of the supported image formats.
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"golang.org/x/image/draw"
+
+	"github.com/mattermost/focalboard/server/model"
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	// thumbnailMaxSize and previewMaxSize are the maximum width and height
+	// of the generated images.
+	thumbnailMaxSize = 400
+	previewMaxSize   = 1920
+
+	// maxPreviewImageResolution is the number of pixels above which no
+	// previews are generated, so large images don't exhaust the memory.
+	maxPreviewImageResolution = 7680 * 4320
+
+	previewJPEGQuality = 85
+
+	filePreviewsBackfillBatchSize = 100
+)
+
+// previewImageExtensions are the extensions of the files with previews.
+var previewImageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
+
+func isPreviewImage(extension string) bool {
+	for _, ext := range previewImageExtensions {
+		if strings.EqualFold(ext, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPreviewFilePath returns the path of a generated image, stored
+// alongside the original file.
+func getPreviewFilePath(filePath, suffix string) string {
+	return strings.TrimSuffix(filePath, filepath.Ext(filePath)) + suffix + ".jpg"
+}
+
+// generateFilePreviews generates the thumbnail and preview of an image
+// file and records their paths in its file info. The file info is not
+// saved.
+func (a *App) generateFilePreviews(fileInfo *mm_model.FileInfo) error {
+	reader, err := a.filesBackend.Reader(fileInfo.Path)
+	if err != nil {
+		return fmt.Errorf("cannot read file %s: %w", fileInfo.Path, err)
+	}
+	defer reader.Close()
+
+	config, _, err := image.DecodeConfig(reader)
+	if err != nil {
+		return fmt.Errorf("cannot decode image %s: %w", fileInfo.Path, err)
+	}
+	if config.Width*config.Height > maxPreviewImageResolution {
+		return fmt.Errorf("image %s is too large for previews (%dx%d)", fileInfo.Path, config.Width, config.Height)
+	}
+
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return fmt.Errorf("cannot decode image %s: %w", fileInfo.Path, err)
+	}
+
+	thumbnailPath := getPreviewFilePath(fileInfo.Path, "_thumb")
+	if err := a.writeResizedImage(img, thumbnailMaxSize, thumbnailPath); err != nil {
+		return err
+	}
+	previewPath := getPreviewFilePath(fileInfo.Path, "_preview")
+	if err := a.writeResizedImage(img, previewMaxSize, previewPath); err != nil {
+		return err
+	}
+
+	fileInfo.ThumbnailPath = thumbnailPath
+	fileInfo.PreviewPath = previewPath
+	fileInfo.Width = config.Width
+	fileInfo.Height = config.Height
+	fileInfo.HasPreviewImage = true
+	return nil
+}
+
+// writeResizedImage writes a JPEG version of the image fitting within
+// maxSize. Images are never enlarged, and transparent pixels are
+// rendered on white.
+func (a *App) writeResizedImage(img image.Image, maxSize int, path string) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width > height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: previewJPEGQuality}); err != nil {
+		return fmt.Errorf("cannot encode image %s: %w", path, err)
+	}
+	if _, err := a.filesBackend.WriteFile(&buf, path); err != nil {
+		return fmt.Errorf("cannot write image %s: %w", path, err)
+	}
+	return nil
+}
+
+// GetFilePreview returns the reader of the thumbnail or preview of a
+// file of a board. The reader is nil if the file has no such image, in
+// which case the original should be served.
+func (a *App) GetFilePreview(teamID, boardID, fileName string, size model.FileSize) (filestore.ReadCloseSeeker, error) {
+	fileInfo, err := a.GetFileInfo(fileName)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !fileInfo.HasPreviewImage {
+		return nil, nil
+	}
+
+	var previewPath string
+	switch size {
+	case model.FileSizeThumb:
+		previewPath = fileInfo.ThumbnailPath
+	case model.FileSizePreview:
+		previewPath = fileInfo.PreviewPath
+	}
+	if previewPath == "" {
+		return nil, nil
+	}
+
+	exists, err := a.filesBackend.FileExists(previewPath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		a.logger.Warn("GetFilePreview: missing preview", mlog.String("path", previewPath))
+		return nil, nil
+	}
+
+	return a.filesBackend.Reader(previewPath)
+}
+
+// BackfillFilePreviews generates the missing previews of the existing
+// image files. Files whose previews can't be generated are skipped, and
+// retried on the next run.
+func (a *App) BackfillFilePreviews() (int, error) {
+	count := 0
+	afterID := ""
+	for {
+		fileInfos, err := a.store.GetFileInfosWithoutPreviews(previewImageExtensions, afterID, filePreviewsBackfillBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, fileInfo := range fileInfos {
+			afterID = fileInfo.Id
+			if fileInfo.Path == "" || fileInfo.Path == emptyString {
+				continue
+			}
+
+			if err := a.generateFilePreviews(fileInfo); err != nil {
+				a.logger.Warn("Cannot generate the previews of a file", mlog.String("fileID", fileInfo.Id), mlog.Err(err))
+				continue
+			}
+			if err := a.store.UpdateFileInfoPreviews(fileInfo); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if len(fileInfos) < filePreviewsBackfillBatchSize {
+			return count, nil
+		}
+	}
+}
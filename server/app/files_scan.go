@@ -0,0 +1,118 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// quarantineDirectory is the directory of the files storage where the
+	// infected files are moved.
+	quarantineDirectory = "quarantine"
+
+	// sniffLength is the number of bytes used to detect the type of a
+	// file, see http.DetectContentType.
+	sniffLength = 512
+)
+
+// detectFileType returns the MIME type of a file detected from its
+// content, and a reader of the whole content. An error is returned if
+// the type is not allowed by the configuration.
+func (a *App) detectFileType(reader io.Reader, filename string) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(reader, sniffLength)
+	head, err := br.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", err
+	}
+
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		mimeType = "application/octet-stream"
+	}
+
+	if !isFileTypeAllowed(mimeType, a.config.AllowedFileTypes, a.config.DeniedFileTypes) {
+		return nil, "", model.NewErrBadRequest(fmt.Sprintf("file %s of type %s is not allowed", filename, mimeType))
+	}
+	return br, mimeType, nil
+}
+
+// isFileTypeAllowed returns true if the MIME type is allowed, ie. it
+// matches the allowlist if not empty and doesn't match the denylist.
+// Patterns are either a MIME type or a `type/*` wildcard.
+func isFileTypeAllowed(mimeType string, allowed, denied []string) bool {
+	for _, pattern := range denied {
+		if matchFileType(mimeType, pattern) {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, pattern := range allowed {
+		if matchFileType(mimeType, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchFileType(mimeType, pattern string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "*/*" || pattern == mimeType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mimeType, prefix+"/")
+	}
+	return false
+}
+
+// scanFile scans a stored file for malware. Infected files are moved to
+// the quarantine, and files that can't be scanned are removed.
+func (a *App) scanFile(filePath, filename string) error {
+	if a.fileScanner == nil {
+		return nil
+	}
+
+	reader, err := a.filesBackend.Reader(filePath)
+	if err != nil {
+		return fmt.Errorf("cannot read file %s to scan it: %w", filePath, err)
+	}
+	result, err := a.fileScanner.Scan(reader)
+	reader.Close()
+
+	if err != nil {
+		if errRemove := a.filesBackend.RemoveFile(filePath); errRemove != nil {
+			a.logger.Error("Cannot remove a file that couldn't be scanned", mlog.String("path", filePath), mlog.Err(errRemove))
+		}
+		return fmt.Errorf("cannot scan file %s: %w", filePath, err)
+	}
+
+	if !result.Infected {
+		return nil
+	}
+
+	quarantinePath := filepath.Join(quarantineDirectory, filePath)
+	if err := a.filesBackend.MoveFile(filePath, quarantinePath); err != nil {
+		a.logger.Error("Cannot quarantine an infected file", mlog.String("path", filePath), mlog.Err(err))
+		if errRemove := a.filesBackend.RemoveFile(filePath); errRemove != nil {
+			return fmt.Errorf("cannot remove infected file %s: %w", filePath, errRemove)
+		}
+	}
+
+	a.logger.Warn("Infected file quarantined",
+		mlog.String("filename", filename),
+		mlog.String("path", quarantinePath),
+		mlog.String("signature", result.Signature),
+	)
+	return model.NewErrBadRequest(fmt.Sprintf("file %s is infected (%s)", filename, result.Signature))
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFileTypeAllowed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mimeType string
+		allowed  []string
+		denied   []string
+		expected bool
+	}{
+		{"no lists", "text/html", nil, nil, true},
+		{"exact allow", "image/png", []string{"image/png"}, nil, true},
+		{"not in allowlist", "text/plain", []string{"image/png"}, nil, false},
+		{"wildcard allow", "image/gif", []string{"image/*"}, nil, true},
+		{"wildcard doesn't match other types", "imagex/gif", []string{"image/*"}, nil, false},
+		{"deny", "text/html", nil, []string{"text/html"}, false},
+		{"deny wins over allow", "text/html", []string{"text/*"}, []string{"text/html"}, false},
+		{"patterns are normalized", "text/html", nil, []string{" Text/HTML "}, false},
+		{"any type", "application/pdf", []string{"*/*"}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isFileTypeAllowed(tc.mimeType, tc.allowed, tc.denied))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const defaultSignedURLExpiry = 5 * time.Minute
+
+// GetSignedFileURL returns a short-lived URL to download a file of a board
+// without a session. The URL is signed with the server secret, and grants
+// the permissions of the user it is generated for.
+func (a *App) GetSignedFileURL(teamID, boardID, filename, userID string) (*model.SignedFileURL, error) {
+	if a.config.Secret == "" {
+		return nil, model.NewErrNotImplemented("signed file URLs require the server secret to be configured")
+	}
+
+	expiresAt := utils.GetMillis() + a.signedURLExpiry().Milliseconds()
+	expires := strconv.FormatInt(expiresAt, 10)
+
+	query := url.Values{}
+	query.Set(model.SignedURLExpiresParam, expires)
+	query.Set(model.SignedURLUserParam, userID)
+	query.Set(model.SignedURLSignatureParam, a.signFileURL(teamID, boardID, filename, expires, userID))
+
+	fileURL := fmt.Sprintf("%s/api/v2/files/teams/%s/%s/%s?%s",
+		strings.TrimRight(a.config.ServerRoot, "/"),
+		url.PathEscape(teamID), url.PathEscape(boardID), url.PathEscape(filename), query.Encode())
+
+	return &model.SignedFileURL{URL: fileURL, ExpiresAt: expiresAt}, nil
+}
+
+// ValidateSignedFileURL checks the signature and expiry of the query of a
+// signed file URL, and returns the ID of the user it was generated for.
+func (a *App) ValidateSignedFileURL(teamID, boardID, filename string, query url.Values) (string, error) {
+	if a.config.Secret == "" {
+		return "", model.NewErrUnauthorized("signed file URLs are not enabled")
+	}
+
+	expires := query.Get(model.SignedURLExpiresParam)
+	userID := query.Get(model.SignedURLUserParam)
+	signature := query.Get(model.SignedURLSignatureParam)
+
+	expected := a.signFileURL(teamID, boardID, filename, expires, userID)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return "", model.NewErrUnauthorized("invalid file URL signature")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || expiresAt < utils.GetMillis() {
+		return "", model.NewErrUnauthorized("the file URL has expired")
+	}
+	return userID, nil
+}
+
+// GetFileRedirectURL returns an S3 pre-signed URL of a file, for signed
+// URLs to be served directly by S3. An empty URL is returned if the
+// redirection isn't enabled.
+func (a *App) GetFileRedirectURL(teamID, boardID, filename string) (string, error) {
+	if !a.config.SignedURLRedirectS3 || a.config.FilesDriver != "amazons3" {
+		return "", nil
+	}
+	linkGenerator, ok := a.filesBackend.(filestore.FileBackendWithLinkGenerator)
+	if !ok {
+		return "", nil
+	}
+
+	_, filePath, err := a.GetFilePath(teamID, boardID, filename)
+	if err != nil {
+		return "", err
+	}
+	link, _, err := linkGenerator.GeneratePublicLink(filePath)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate the pre-signed URL of file %s: %w", filePath, err)
+	}
+	return link, nil
+}
+
+func (a *App) signFileURL(teamID, boardID, filename, expires, userID string) string {
+	mac := hmac.New(sha256.New, []byte(a.config.Secret))
+	mac.Write([]byte(strings.Join([]string{"files/teams", teamID, boardID, filename, expires, userID}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (a *App) signedURLExpiry() time.Duration {
+	if a.config.SignedURLExpirySeconds <= 0 {
+		return defaultSignedURLExpiry
+	}
+	return time.Duration(a.config.SignedURLExpirySeconds) * time.Second
+}
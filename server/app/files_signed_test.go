@@ -0,0 +1,109 @@
+package app
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore/mocks"
+)
+
+func TestSignedFileURL(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.Secret = "test-secret"
+	th.App.config.ServerRoot = "http://localhost:8000/"
+
+	signedQuery := func(t *testing.T) url.Values {
+		signedURL, err := th.App.GetSignedFileURL("team-id", "board-id", "7file.png", "user-id")
+		require.NoError(t, err)
+		parsed, err := url.Parse(signedURL.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/files/teams/team-id/board-id/7file.png", parsed.Path)
+		return parsed.Query()
+	}
+
+	t.Run("valid URL", func(t *testing.T) {
+		userID, err := th.App.ValidateSignedFileURL("team-id", "board-id", "7file.png", signedQuery(t))
+		require.NoError(t, err)
+		assert.Equal(t, "user-id", userID)
+	})
+
+	t.Run("URL of another file", func(t *testing.T) {
+		_, err := th.App.ValidateSignedFileURL("team-id", "board-id", "7other.png", signedQuery(t))
+		assert.True(t, model.IsErrUnauthorized(err))
+	})
+
+	t.Run("extended expiry", func(t *testing.T) {
+		query := signedQuery(t)
+		query.Set(model.SignedURLExpiresParam, strconv.FormatInt(utils.GetMillis()+time.Hour.Milliseconds(), 10))
+		_, err := th.App.ValidateSignedFileURL("team-id", "board-id", "7file.png", query)
+		assert.True(t, model.IsErrUnauthorized(err))
+	})
+
+	t.Run("expired URL", func(t *testing.T) {
+		expires := strconv.FormatInt(utils.GetMillis()-1, 10)
+		query := url.Values{}
+		query.Set(model.SignedURLExpiresParam, expires)
+		query.Set(model.SignedURLUserParam, "user-id")
+		query.Set(model.SignedURLSignatureParam, th.App.signFileURL("team-id", "board-id", "7file.png", expires, "user-id"))
+
+		_, err := th.App.ValidateSignedFileURL("team-id", "board-id", "7file.png", query)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expired")
+	})
+
+	t.Run("URL signed with another secret", func(t *testing.T) {
+		query := signedQuery(t)
+		th.App.config.Secret = "other-secret"
+		defer func() { th.App.config.Secret = "test-secret" }()
+
+		_, err := th.App.ValidateSignedFileURL("team-id", "board-id", "7file.png", query)
+		assert.True(t, model.IsErrUnauthorized(err))
+	})
+
+	t.Run("no secret", func(t *testing.T) {
+		th.App.config.Secret = ""
+		defer func() { th.App.config.Secret = "test-secret" }()
+
+		_, err := th.App.GetSignedFileURL("team-id", "board-id", "7file.png", "user-id")
+		assert.True(t, model.IsErrNotImplemented(err))
+	})
+}
+
+type linkGeneratorFileBackend struct {
+	*mocks.FileBackend
+}
+
+func (b linkGeneratorFileBackend) GeneratePublicLink(path string) (string, time.Duration, error) {
+	return "https://bucket.s3.amazonaws.com/" + path + "?X-Amz-Signature=sig", time.Minute, nil
+}
+
+func TestGetFileRedirectURL(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.filesBackend = linkGeneratorFileBackend{&mocks.FileBackend{}}
+	th.App.config.FilesDriver = "amazons3"
+
+	t.Run("redirection disabled", func(t *testing.T) {
+		th.App.config.SignedURLRedirectS3 = false
+		redirectURL, err := th.App.GetFileRedirectURL("team-id", "board-id", "7file.png")
+		require.NoError(t, err)
+		assert.Empty(t, redirectURL)
+	})
+
+	t.Run("redirection enabled", func(t *testing.T) {
+		th.App.config.SignedURLRedirectS3 = true
+		th.Store.EXPECT().GetFileInfo("file").Return(nil, model.NewErrNotFound("file"))
+
+		redirectURL, err := th.App.GetFileRedirectURL("team-id", "board-id", "7file.png")
+		require.NoError(t, err)
+		assert.Equal(t, "https://bucket.s3.amazonaws.com/team-id/board-id/7file.png?X-Amz-Signature=sig", redirectURL)
+	})
+}
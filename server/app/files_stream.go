@@ -3,29 +3,19 @@ package app
 import (
 	"fmt"
 	"io"
-	"path/filepath"
-	"strings"
 
-	"github.com/mattermost/focalboard/server/model"
-	"github.com/mattermost/focalboard/server/utils"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 // SaveFileStreaming saves a file using streaming to avoid loading entire file into memory
 // This is a memory-efficient version of SaveFile for large files
 func (a *App) SaveFileStreaming(reader io.Reader, teamID, boardID, filename string, asTemplate bool) (string, error) {
-	// NOTE: File extension includes the dot
-	fileExtension := strings.ToLower(filepath.Ext(filename))
-	if fileExtension == ".jpeg" {
-		fileExtension = ".jpg"
-	}
+	dest := newFileDestination(teamID, boardID, filename, asTemplate)
 
-	createdFilename := utils.NewID(utils.IDTypeNone)
-	newFileName := fmt.Sprintf(`%s%s`, createdFilename, fileExtension)
-	if asTemplate {
-		newFileName = filename
+	reader, mimeType, err := a.detectFileType(reader, filename)
+	if err != nil {
+		return "", err
 	}
-	filePath := getDestinationFilePath(asTemplate, teamID, boardID, newFileName)
 
 	// Use a limited reader to prevent memory exhaustion
 	// Process in 1MB chunks to stay well under memory limits
@@ -41,7 +31,7 @@ func (a *App) SaveFileStreaming(reader io.Reader, teamID, boardID, filename stri
 		bufSize: bufSize,
 	}
 
-	fileSize, appErr := a.filesBackend.WriteFile(bufferedReader, filePath)
+	fileSize, appErr := a.filesBackend.WriteFile(bufferedReader, dest.path)
 	if appErr != nil {
 		return "", fmt.Errorf("unable to store the file in the files storage: %w", appErr)
 	}
@@ -54,17 +44,7 @@ func (a *App) SaveFileStreaming(reader io.Reader, teamID, boardID, filename stri
 		)
 	}
 
-	fileInfo := model.NewFileInfo(filename)
-	fileInfo.Id = getFileInfoID(createdFilename)
-	fileInfo.Path = filePath
-	fileInfo.Size = fileSize
-
-	err := a.store.SaveFileInfo(fileInfo)
-	if err != nil {
-		return "", err
-	}
-
-	return newFileName, nil
+	return a.saveStoredFile(dest, teamID, boardID, filename, fileSize, mimeType)
 }
 
 // BufferedCopyReader wraps an io.Reader to control memory usage during copying
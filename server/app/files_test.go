@@ -190,11 +190,15 @@ func TestGetFileReader(t *testing.T) {
 func TestSaveFile(t *testing.T) {
 	th, _ := SetupTestHelper(t)
 	mockedReadCloseSeek := &mocks.ReadCloseSeeker{}
+	// the content type is detected from the beginning of the file, then the
+	// whole content is written through a buffered reader
+	mockedReadCloseSeek.On("Read", mock.Anything).Return(0, io.EOF)
 	t.Run("should save file to file store using file backend", func(t *testing.T) {
 		fileName := "temp-file-name.txt"
 		mockedFileBackend := &mocks.FileBackend{}
 		th.App.filesBackend = mockedFileBackend
 		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 
 		writeFileFunc := func(reader io.Reader, path string) int64 {
 			paths := strings.Split(path, string(os.PathSeparator))
@@ -208,7 +212,7 @@ func TestSaveFile(t *testing.T) {
 			return nil
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
 		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", testBoardID, fileName, false)
 		assert.Equal(t, fileName, actual)
 		assert.Nil(t, err)
@@ -219,6 +223,7 @@ func TestSaveFile(t *testing.T) {
 		mockedFileBackend := &mocks.FileBackend{}
 		th.App.filesBackend = mockedFileBackend
 		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 
 		writeFileFunc := func(reader io.Reader, path string) int64 {
 			paths := strings.Split(path, string(os.PathSeparator))
@@ -232,7 +237,9 @@ func TestSaveFile(t *testing.T) {
 			return nil
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		// the mocked content is not an image, so no previews are generated
+		mockedFileBackend.On("Reader", mock.Anything).Return(nil, &TestError{})
 		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName, false)
 		assert.Nil(t, err)
 		assert.NotNil(t, actual)
@@ -256,7 +263,7 @@ func TestSaveFile(t *testing.T) {
 			return mockedError
 		}
 
-		mockedFileBackend.On("WriteFile", mockedReadCloseSeek, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(writeFileFunc, writeFileErrorFunc)
 		actual, err := th.App.SaveFile(mockedReadCloseSeek, "1", "test-board-id", fileName, false)
 		assert.Equal(t, "", actual)
 		assert.Equal(t, "unable to store the file in the files storage: Mocked File backend error", err.Error())
@@ -447,6 +454,7 @@ func TestCopyCard(t *testing.T) {
 		}, nil)
 		th.Store.EXPECT().GetFileInfo("fileName").Return(fileInfo, nil)
 		th.Store.EXPECT().SaveFileInfo(fileInfo).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 
 		mockedFileBackend := &mocks.FileBackend{}
 		th.App.filesBackend = mockedFileBackend
@@ -485,6 +493,7 @@ func TestCopyCard(t *testing.T) {
 		}, nil)
 		th.Store.EXPECT().GetFileInfo("fileName").Return(fileInfo, nil)
 		th.Store.EXPECT().SaveFileInfo(fileInfo).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 
 		mockedFileBackend := &mocks.FileBackend{}
 		th.App.filesBackend = mockedFileBackend
@@ -502,6 +511,7 @@ func TestCopyCard(t *testing.T) {
 		}, nil)
 		th.Store.EXPECT().GetFileInfo(gomock.Any()).Return(nil, nil)
 		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 
 		mockedFileBackend := &mocks.FileBackend{}
 		th.App.filesBackend = mockedFileBackend
@@ -542,6 +552,7 @@ func TestCopyAndUpdateCardFiles(t *testing.T) {
 		}, nil)
 		th.Store.EXPECT().GetFileInfo("fileName").Return(fileInfo, nil)
 		th.Store.EXPECT().SaveFileInfo(fileInfo).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil)
 		th.Store.EXPECT().PatchBlocks(gomock.Any(), "userID").Return(nil)
 
 		mockedFileBackend := &mocks.FileBackend{}
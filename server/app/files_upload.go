@@ -0,0 +1,292 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// uploadsDirectory is the directory of the files storage where the
+	// parts of the resumable uploads are stored until they are complete.
+	uploadsDirectory = "uploads"
+
+	// uploadSessionExpiry is the time after which an upload session
+	// without progress is removed.
+	uploadSessionExpiry = 24 * time.Hour
+
+	// uploadClaimExpiry is the time after which the claim of a chunk is
+	// released, should the server receiving it stop.
+	uploadClaimExpiry = 15 * time.Minute
+
+	// s3MinUploadChunkSize is the minimum size of the first chunk of an
+	// upload to S3, unless it is the whole file. S3 appends a chunk by
+	// composing the received part with it, which is only possible when the
+	// received part is at least 5 MiB.
+	s3MinUploadChunkSize = 5 * 1024 * 1024
+
+	uploadSessionsBatchSize = 100
+)
+
+// CreateUploadSession starts a resumable upload of a file to a board.
+func (a *App) CreateUploadSession(session *model.UploadSession) (*model.UploadSession, error) {
+	if err := session.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if maxSize := a.maxUploadSessionFileSize(); maxSize > 0 && session.FileSize > maxSize {
+		return nil, fmt.Errorf("%w: the file exceeds the maximum size of %d bytes", model.ErrRequestEntityTooLarge, maxSize)
+	}
+
+	// fail early, the quota is checked again once the upload is complete
+	if err := a.checkStorageQuota(session.TeamID, session.FileSize); err != nil {
+		return nil, err
+	}
+
+	session.ID = utils.NewID(utils.IDTypeNone)
+	session.Path = filepath.Join(uploadsDirectory, session.ID)
+	session.FileOffset = 0
+	session.CreateAt = utils.GetMillis()
+	session.UpdateAt = session.CreateAt
+
+	if err := a.store.CreateUploadSession(session); err != nil {
+		return nil, err
+	}
+	session.MinChunkSize = a.minUploadChunkSize()
+	return session, nil
+}
+
+// maxUploadSessionFileSize returns the maximum size of a resumable upload,
+// which is the maximum size of the files unless configured.
+func (a *App) maxUploadSessionFileSize() int64 {
+	if a.config.MaxUploadSessionFileSize > 0 {
+		return a.config.MaxUploadSessionFileSize
+	}
+	return a.config.MaxFileSize
+}
+
+// minUploadChunkSize returns the minimum size of the first chunk of an
+// upload that doesn't complete the file, 0 if any size is accepted.
+func (a *App) minUploadChunkSize() int64 {
+	if a.config.FilesDriver == mm_model.ImageDriverS3 {
+		return s3MinUploadChunkSize
+	}
+	return 0
+}
+
+func (a *App) GetUploadSession(id string) (*model.UploadSession, error) {
+	session, err := a.store.GetUploadSession(id)
+	if err != nil {
+		return nil, err
+	}
+	session.MinChunkSize = a.minUploadChunkSize()
+	return session, nil
+}
+
+// claimUploadSession claims an upload session at an offset in the store,
+// so the chunks of an upload are never appended concurrently nor the
+// upload finalized twice, even by different servers. The claim is
+// released when the progress of the session is saved.
+func (a *App) claimUploadSession(session *model.UploadSession, offset int64) error {
+	now := utils.GetMillis()
+	return a.store.ClaimUploadSession(session.ID, offset, now, now+uploadClaimExpiry.Milliseconds())
+}
+
+// releaseUploadSession releases the claim of an upload session that
+// hasn't progressed, unless the session has been removed.
+func (a *App) releaseUploadSession(session *model.UploadSession) {
+	if err := a.store.UpdateUploadSession(session); err != nil && !model.IsErrNotFound(err) {
+		a.logger.Error("Cannot release the claim of an upload session", mlog.String("sessionID", session.ID), mlog.Err(err))
+	}
+}
+
+// UploadChunk appends a chunk of the file to an upload session. The offset
+// must be the number of bytes received so far, otherwise a conflict error
+// is returned and the client must resume from the offset of the session.
+func (a *App) UploadChunk(session *model.UploadSession, offset int64, r io.Reader) (*model.UploadSession, error) {
+	// the session may have progressed since it was read
+	session, err := a.GetUploadSession(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != session.FileOffset {
+		return nil, model.NewErrConflict(fmt.Sprintf("the chunk starts at offset %d, but %d bytes have been received", offset, session.FileOffset))
+	}
+	if err = a.claimUploadSession(session, offset); err != nil {
+		return nil, err
+	}
+
+	remaining := &io.LimitedReader{R: r, N: session.FileSize - session.FileOffset}
+
+	if session.FileOffset == 0 {
+		_, err = a.filesBackend.WriteFile(remaining, session.Path)
+	} else {
+		_, err = a.filesBackend.AppendFile(remaining, session.Path)
+	}
+
+	// the stored part may have been partially written, so the progress is
+	// saved even on failure to let the client resume from there.
+	if size, errSize := a.filesBackend.FileSize(session.Path); errSize == nil && size <= session.FileSize {
+		session.FileOffset = size
+	} else if errSize != nil && err == nil {
+		err = errSize
+	}
+	if err == nil && offset == 0 && session.FileOffset < session.FileSize && session.FileOffset < session.MinChunkSize {
+		if err = a.removeUploadPart(session); err == nil {
+			session.FileOffset = 0
+			err = model.NewErrBadRequest(fmt.Sprintf("the first chunk must have at least %d bytes, unless it is the whole file", session.MinChunkSize))
+		}
+	}
+	session.UpdateAt = utils.GetMillis()
+	if errUpdate := a.store.UpdateUploadSession(session); errUpdate != nil {
+		return nil, errUpdate
+	}
+
+	if model.IsErrBadRequest(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to store the chunk in the files storage: %w", err)
+	}
+
+	if remaining.N == 0 {
+		if n, _ := r.Read(make([]byte, 1)); n > 0 {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("the upload exceeds the declared file size of %d bytes", session.FileSize))
+		}
+	}
+
+	return session, nil
+}
+
+// FinalizeUpload verifies the size and checksum of a complete upload and
+// saves it as a file of the board, the same way SaveFile does. The upload
+// session is removed and the name of the file is returned.
+func (a *App) FinalizeUpload(session *model.UploadSession) (string, error) {
+	if !session.IsComplete() {
+		return "", model.NewErrBadRequest(fmt.Sprintf("the upload is incomplete, %d of %d bytes received", session.FileOffset, session.FileSize))
+	}
+	if err := a.claimUploadSession(session, session.FileSize); err != nil {
+		return "", err
+	}
+	defer a.releaseUploadSession(session)
+
+	board, err := a.GetBoard(session.BoardID)
+	if err != nil {
+		return "", err
+	}
+
+	size, err := a.filesBackend.FileSize(session.Path)
+	if err != nil {
+		return "", fmt.Errorf("cannot get the size of upload %s: %w", session.ID, err)
+	}
+	if size != session.FileSize {
+		a.abortUpload(session)
+		return "", model.NewErrBadRequest(fmt.Sprintf("the uploaded file has %d bytes instead of %d", size, session.FileSize))
+	}
+
+	mimeType, checksum, err := a.readUploadedFile(session)
+	if err != nil {
+		if model.IsErrBadRequest(err) {
+			a.abortUpload(session)
+		}
+		return "", err
+	}
+	if session.Checksum != "" && checksum != session.Checksum {
+		a.abortUpload(session)
+		return "", model.NewErrBadRequest(fmt.Sprintf("the checksum of the uploaded file %s doesn't match %s", checksum, session.Checksum))
+	}
+
+	dest := newFileDestination(session.TeamID, session.BoardID, session.Filename, board.IsTemplate)
+	if err = a.filesBackend.MoveFile(session.Path, dest.path); err != nil {
+		return "", fmt.Errorf("unable to move the upload to the files storage: %w", err)
+	}
+	if err = a.store.DeleteUploadSession(session.ID); err != nil {
+		a.logger.Error("Cannot delete a finalized upload session", mlog.String("sessionID", session.ID), mlog.Err(err))
+	}
+
+	return a.saveStoredFile(dest, session.TeamID, session.BoardID, session.Filename, size, mimeType)
+}
+
+// readUploadedFile returns the MIME type and the hex encoded SHA-256
+// checksum of the stored part of an upload.
+func (a *App) readUploadedFile(session *model.UploadSession) (string, string, error) {
+	reader, err := a.filesBackend.Reader(session.Path)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read upload %s: %w", session.ID, err)
+	}
+	defer reader.Close()
+
+	content, mimeType, err := a.detectFileType(reader, session.Filename)
+	if err != nil {
+		return "", "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", "", fmt.Errorf("cannot read upload %s: %w", session.ID, err)
+	}
+	return mimeType, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// DeleteUploadSession cancels an upload and removes the received part of
+// the file.
+func (a *App) DeleteUploadSession(session *model.UploadSession) error {
+	if err := a.removeUploadPart(session); err != nil {
+		return err
+	}
+	return a.store.DeleteUploadSession(session.ID)
+}
+
+// abortUpload removes an upload that can't be completed.
+func (a *App) abortUpload(session *model.UploadSession) {
+	if err := a.DeleteUploadSession(session); err != nil {
+		a.logger.Error("Cannot delete an aborted upload session", mlog.String("sessionID", session.ID), mlog.Err(err))
+	}
+}
+
+func (a *App) removeUploadPart(session *model.UploadSession) error {
+	exists, err := a.filesBackend.FileExists(session.Path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	return a.filesBackend.RemoveFile(session.Path)
+}
+
+// CleanupExpiredUploadSessions removes the upload sessions without
+// progress since the expiry, and their received parts.
+func (a *App) CleanupExpiredUploadSessions() (int, error) {
+	updatedBefore := utils.GetMillis() - uploadSessionExpiry.Milliseconds()
+
+	deleted := 0
+	for {
+		sessions, err := a.store.GetUploadSessionsUpdatedBefore(updatedBefore, uploadSessionsBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, session := range sessions {
+			if err := a.DeleteUploadSession(session); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if len(sessions) < uploadSessionsBatchSize {
+			a.logger.Debug("CleanupExpiredUploadSessions", mlog.Int("deleted", deleted))
+			return deleted, nil
+		}
+	}
+}
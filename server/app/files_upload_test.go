@@ -0,0 +1,151 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore/mocks"
+)
+
+func TestUploadChunk(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("a chunk not starting at the offset of the session", func(t *testing.T) {
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 40}
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(session, nil)
+
+		_, err := th.App.UploadChunk(session, 0, bytes.NewReader([]byte("chunk")))
+		require.Error(t, err)
+		assert.True(t, model.IsErrConflict(err))
+	})
+
+	t.Run("a chunk read with an outdated session", func(t *testing.T) {
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 40}
+		current := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 45}
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(current, nil)
+
+		_, err := th.App.UploadChunk(session, 40, bytes.NewReader([]byte("chunk")))
+		require.Error(t, err)
+		assert.True(t, model.IsErrConflict(err))
+	})
+
+	t.Run("a chunk received while another chunk is being received", func(t *testing.T) {
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 40}
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(session, nil)
+		th.Store.EXPECT().ClaimUploadSession("upload-id", int64(40), gomock.Any(), gomock.Any()).
+			Return(model.NewErrConflict("upload session is receiving another chunk"))
+
+		_, err := th.App.UploadChunk(session, 40, bytes.NewReader([]byte("chunk")))
+		require.Error(t, err)
+		assert.True(t, model.IsErrConflict(err))
+	})
+
+	t.Run("a chunk appended to the received part", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 40}
+		mockedFileBackend.On("AppendFile", mock.Anything, "uploads/upload-id").Return(int64(5), nil)
+		mockedFileBackend.On("FileSize", "uploads/upload-id").Return(int64(45), nil)
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(session, nil)
+		th.Store.EXPECT().ClaimUploadSession("upload-id", int64(40), gomock.Any(), gomock.Any()).Return(nil)
+		th.Store.EXPECT().UpdateUploadSession(session).Return(nil)
+
+		updated, err := th.App.UploadChunk(session, 40, bytes.NewReader([]byte("chunk")))
+		require.NoError(t, err)
+		assert.Equal(t, int64(45), updated.FileOffset)
+		mockedFileBackend.AssertExpectations(t)
+	})
+
+	t.Run("a first chunk smaller than the minimum of S3", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		filesDriver := th.App.config.FilesDriver
+		th.App.config.FilesDriver = "amazons3"
+		defer func() { th.App.config.FilesDriver = filesDriver }()
+
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 2 * s3MinUploadChunkSize}
+		mockedFileBackend.On("WriteFile", mock.Anything, "uploads/upload-id").Return(int64(5), nil)
+		mockedFileBackend.On("FileSize", "uploads/upload-id").Return(int64(5), nil)
+		mockedFileBackend.On("FileExists", "uploads/upload-id").Return(true, nil)
+		mockedFileBackend.On("RemoveFile", "uploads/upload-id").Return(nil)
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(session, nil)
+		th.Store.EXPECT().ClaimUploadSession("upload-id", int64(0), gomock.Any(), gomock.Any()).Return(nil)
+		th.Store.EXPECT().UpdateUploadSession(session).Return(nil)
+
+		_, err := th.App.UploadChunk(session, 0, bytes.NewReader([]byte("chunk")))
+		require.Error(t, err)
+		assert.True(t, model.IsErrBadRequest(err))
+		assert.Zero(t, session.FileOffset)
+		assert.Equal(t, int64(s3MinUploadChunkSize), session.MinChunkSize)
+		mockedFileBackend.AssertExpectations(t)
+	})
+
+	t.Run("a first chunk that is the whole file to S3", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		filesDriver := th.App.config.FilesDriver
+		th.App.config.FilesDriver = "amazons3"
+		defer func() { th.App.config.FilesDriver = filesDriver }()
+
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 5}
+		mockedFileBackend.On("WriteFile", mock.Anything, "uploads/upload-id").Return(int64(5), nil)
+		mockedFileBackend.On("FileSize", "uploads/upload-id").Return(int64(5), nil)
+		th.Store.EXPECT().GetUploadSession("upload-id").Return(session, nil)
+		th.Store.EXPECT().ClaimUploadSession("upload-id", int64(0), gomock.Any(), gomock.Any()).Return(nil)
+		th.Store.EXPECT().UpdateUploadSession(session).Return(nil)
+
+		updated, err := th.App.UploadChunk(session, 0, bytes.NewReader([]byte("chunk")))
+		require.NoError(t, err)
+		assert.True(t, updated.IsComplete())
+		mockedFileBackend.AssertExpectations(t)
+	})
+}
+
+func TestFinalizeUpload(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("an upload being finalized by another request", func(t *testing.T) {
+		session := &model.UploadSession{ID: "upload-id", Path: "uploads/upload-id", FileSize: 100, FileOffset: 100}
+		th.Store.EXPECT().ClaimUploadSession("upload-id", int64(100), gomock.Any(), gomock.Any()).
+			Return(model.NewErrConflict("upload session is being finalized"))
+
+		_, err := th.App.FinalizeUpload(session)
+		require.Error(t, err)
+		assert.True(t, model.IsErrConflict(err))
+	})
+}
+
+func TestCleanupExpiredUploadSessions(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	mockedFileBackend := &mocks.FileBackend{}
+	th.App.filesBackend = mockedFileBackend
+
+	sessions := []*model.UploadSession{
+		{ID: "stale", Path: "uploads/stale"},
+		{ID: "never-started", Path: "uploads/never-started"},
+	}
+	th.Store.EXPECT().GetUploadSessionsUpdatedBefore(gomock.Any(), uploadSessionsBatchSize).Return(sessions, nil)
+
+	mockedFileBackend.On("FileExists", "uploads/stale").Return(true, nil)
+	mockedFileBackend.On("RemoveFile", "uploads/stale").Return(nil)
+	th.Store.EXPECT().DeleteUploadSession("stale").Return(nil)
+
+	mockedFileBackend.On("FileExists", "uploads/never-started").Return(false, nil)
+	th.Store.EXPECT().DeleteUploadSession("never-started").Return(nil)
+
+	deleted, err := th.App.CleanupExpiredUploadSessions()
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	mockedFileBackend.AssertExpectations(t)
+}
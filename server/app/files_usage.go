@@ -0,0 +1,374 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// orphanedFileGracePeriod is the time during which a file that isn't
+	// referenced by any block is kept, as files are uploaded before the
+	// blocks referencing them are created.
+	orphanedFileGracePeriod = 24 * time.Hour
+
+	orphanedFilesBatchSize = 100
+
+	fileUsageBackfillBatchSize = 100
+
+	// fileUsageBackfillKey is the system setting set once the usage of the
+	// files stored before it was tracked has been saved.
+	fileUsageBackfillKey = "FileUsageBackfillComplete"
+)
+
+// saveFileInfo saves the info of a file stored for a board, and tracks the
+// storage it uses.
+func (a *App) saveFileInfo(fileInfo *mm_model.FileInfo, teamID, boardID, fileName string) error {
+	if err := a.store.SaveFileInfo(fileInfo); err != nil {
+		return err
+	}
+
+	usage := &model.FileUsage{
+		FileID:   fileInfo.Id,
+		TeamID:   teamID,
+		BoardID:  boardID,
+		FileName: fileName,
+		Path:     fileInfo.Path,
+		Size:     fileInfo.Size,
+		CreateAt: fileInfo.CreateAt,
+	}
+	return a.store.SaveFileUsage(usage)
+}
+
+// teamStorageQuota returns the storage quota of a team in bytes, 0 if the
+// storage is unlimited.
+func (a *App) teamStorageQuota(teamID string) int64 {
+	if quota, ok := a.config.TeamStorageQuotas[teamID]; ok {
+		return quota
+	}
+	return a.config.TeamStorageQuota
+}
+
+// checkStorageQuota returns an error if storing a new file of the given
+// size exceeds the storage quota of a team.
+func (a *App) checkStorageQuota(teamID string, size int64) error {
+	quota := a.teamStorageQuota(teamID)
+	if quota <= 0 {
+		return nil
+	}
+
+	a.storageReservationsMux.Lock()
+	defer a.storageReservationsMux.Unlock()
+
+	return a.checkStorageQuotaLocked(teamID, size, quota)
+}
+
+// reserveStorage reserves the storage of a new file of a team until its
+// usage is saved, and returns the function releasing the reservation.
+// The storage reserved is counted as used, so the files saved at the same
+// time can't exceed the quota together.
+func (a *App) reserveStorage(teamID string, size int64) (func(), error) {
+	quota := a.teamStorageQuota(teamID)
+	if quota <= 0 {
+		return func() {}, nil
+	}
+
+	a.storageReservationsMux.Lock()
+	defer a.storageReservationsMux.Unlock()
+
+	if err := a.checkStorageQuotaLocked(teamID, size, quota); err != nil {
+		return nil, err
+	}
+	a.storageReservations[teamID] += size
+
+	return func() {
+		a.storageReservationsMux.Lock()
+		defer a.storageReservationsMux.Unlock()
+
+		a.storageReservations[teamID] -= size
+		if a.storageReservations[teamID] <= 0 {
+			delete(a.storageReservations, teamID)
+		}
+	}, nil
+}
+
+// remainingStorage returns the storage left to a team, -1 if the storage
+// is unlimited.
+func (a *App) remainingStorage(teamID string) (int64, error) {
+	quota := a.teamStorageQuota(teamID)
+	if quota <= 0 {
+		return -1, nil
+	}
+
+	a.storageReservationsMux.Lock()
+	defer a.storageReservationsMux.Unlock()
+
+	used, err := a.usedStorageLocked(teamID)
+	if err != nil {
+		return 0, err
+	}
+	if used > quota {
+		return 0, nil
+	}
+	return quota - used, nil
+}
+
+func (a *App) checkStorageQuotaLocked(teamID string, size, quota int64) error {
+	used, err := a.usedStorageLocked(teamID)
+	if err != nil {
+		return err
+	}
+	if used+size > quota {
+		return fmt.Errorf("%w: the file exceeds the storage quota of the team (%d of %d bytes used)",
+			model.ErrRequestEntityTooLarge, used, quota)
+	}
+	return nil
+}
+
+// usedStorageLocked returns the storage used by the files of a team and
+// reserved by the files being saved.
+func (a *App) usedStorageLocked(teamID string) (int64, error) {
+	used, err := a.store.GetTeamFileUsageSize(teamID)
+	if err != nil {
+		return 0, err
+	}
+	return used + a.storageReservations[teamID], nil
+}
+
+// BackfillFileUsage saves the usage of the files stored before it was
+// tracked, attributing each file to the board of the blocks referencing
+// it, and returns the number of files saved. It only runs once.
+func (a *App) BackfillFileUsage() (int, error) {
+	setting, err := a.store.GetSystemSetting(fileUsageBackfillKey)
+	if err != nil {
+		return 0, err
+	}
+	if done, _ := strconv.ParseBool(setting); done {
+		return 0, nil
+	}
+
+	count := 0
+	boards := map[string]*model.Board{}
+	afterID := ""
+	for {
+		blocks, err := a.store.GetFileBlocks(afterID, fileUsageBackfillBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, block := range blocks {
+			afterID = block.ID
+			saved, err := a.backfillBlockFileUsage(block, boards)
+			if err != nil {
+				return count, err
+			}
+			if saved {
+				count++
+			}
+		}
+
+		if len(blocks) < fileUsageBackfillBatchSize {
+			break
+		}
+	}
+
+	return count, a.store.SetSystemSetting(fileUsageBackfillKey, strconv.FormatBool(true))
+}
+
+// backfillBlockFileUsage saves the usage of the file of a block if it
+// isn't tracked yet, and returns whether it was saved.
+func (a *App) backfillBlockFileUsage(block *model.Block, boards map[string]*model.Board) (bool, error) {
+	fileName, err := extractFilename(block)
+	if err != nil || fileName == "" {
+		return false, nil
+	}
+
+	fileInfo, err := a.GetFileInfo(fileName)
+	if model.IsErrNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if fileInfo.DeleteAt > 0 || fileInfo.Archived {
+		return false, nil
+	}
+
+	_, err = a.store.GetFileUsageByFileID(fileInfo.Id)
+	if err == nil {
+		return false, nil
+	}
+	if !model.IsErrNotFound(err) {
+		return false, err
+	}
+
+	board, ok := boards[block.BoardID]
+	if !ok {
+		board, err = a.store.GetBoard(block.BoardID)
+		if err != nil && !model.IsErrNotFound(err) {
+			return false, err
+		}
+		boards[block.BoardID] = board
+	}
+	if board == nil {
+		return false, nil
+	}
+
+	path := fileInfo.Path
+	if path == "" || path == emptyString {
+		path = filepath.Join(board.TeamID, board.ID, fileName)
+	}
+
+	usage := &model.FileUsage{
+		FileID:   fileInfo.Id,
+		TeamID:   board.TeamID,
+		BoardID:  board.ID,
+		FileName: fileName,
+		Path:     path,
+		Size:     fileInfo.Size,
+		CreateAt: fileInfo.CreateAt,
+	}
+	if err := a.store.SaveFileUsage(usage); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetFileUsage returns the storage used by the files of each board of a
+// team, or of all the teams if teamID is empty.
+func (a *App) GetFileUsage(teamID string) ([]*model.TeamFileUsage, error) {
+	usage, err := a.store.GetFileUsage(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if teamID != "" && len(usage) == 0 {
+		usage = append(usage, &model.TeamFileUsage{TeamID: teamID, Boards: []*model.BoardFileUsage{}})
+	}
+	for _, team := range usage {
+		team.Quota = a.teamStorageQuota(team.TeamID)
+	}
+	return usage, nil
+}
+
+// CheckFileBoard returns a not found error if a file is tracked as
+// stored for another board, so the files of a board can't be read through
+// the URLs of another one. The untracked files aren't checked.
+func (a *App) CheckFileBoard(boardID, fileName string) error {
+	if fileName == "" {
+		return errEmptyFilename
+	}
+
+	usage, err := a.store.GetFileUsageByFileID(getFileInfoID(strings.Split(fileName, ".")[0]))
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if usage.BoardID != boardID {
+		return model.NewErrNotFound("file " + fileName + " in board ID=" + boardID)
+	}
+	return nil
+}
+
+// CleanupOrphanedFiles deletes the files that are no longer referenced by
+// any image or attachment block of their board, including the deleted
+// blocks that can still be restored.
+func (a *App) CleanupOrphanedFiles() (*model.OrphanedFilesCleanup, error) {
+	result := &model.OrphanedFilesCleanup{}
+	createdBefore := utils.GetMillis() - orphanedFileGracePeriod.Milliseconds()
+
+	// the file names referenced by each board
+	references := map[string]map[string]bool{}
+
+	afterID := ""
+	for {
+		usages, err := a.store.GetFileUsagesCreatedBefore(createdBefore, afterID, orphanedFilesBatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, usage := range usages {
+			afterID = usage.FileID
+
+			boardReferences, ok := references[usage.BoardID]
+			if !ok {
+				fileNames, err := a.store.GetBoardFileReferences(usage.BoardID)
+				if err != nil {
+					return result, err
+				}
+				boardReferences = make(map[string]bool, len(fileNames))
+				for _, fileName := range fileNames {
+					boardReferences[fileName] = true
+				}
+				references[usage.BoardID] = boardReferences
+			}
+
+			if boardReferences[usage.FileName] {
+				continue
+			}
+
+			if err := a.deleteOrphanedFile(usage); err != nil {
+				a.logger.Warn("Cannot delete an orphaned file", mlog.String("path", usage.Path), mlog.Err(err))
+				continue
+			}
+			result.Files++
+			result.Size += usage.Size
+		}
+
+		if len(usages) < orphanedFilesBatchSize {
+			return result, nil
+		}
+	}
+}
+
+func (a *App) deleteOrphanedFile(usage *model.FileUsage) error {
+	paths := []string{usage.Path}
+
+	fileInfo, err := a.store.GetFileInfo(usage.FileID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+	if fileInfo != nil {
+		for _, path := range []string{fileInfo.ThumbnailPath, fileInfo.PreviewPath} {
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		exists, err := a.filesBackend.FileExists(path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := a.filesBackend.RemoveFile(path); err != nil {
+			return err
+		}
+	}
+
+	if fileInfo != nil {
+		if err := a.store.DeleteFileInfo(fileInfo.Id); err != nil {
+			return err
+		}
+	}
+
+	a.logger.Debug("Deleted an orphaned file",
+		mlog.String("teamID", usage.TeamID),
+		mlog.String("boardID", usage.BoardID),
+		mlog.String("path", usage.Path),
+	)
+	return a.store.DeleteFileUsage(usage.FileID)
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore/mocks"
+)
+
+func TestCheckStorageQuota(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("no quota", func(t *testing.T) {
+		th.App.config.TeamStorageQuota = 0
+		require.NoError(t, th.App.checkStorageQuota("team-id", 1<<40))
+	})
+
+	t.Run("within the quota", func(t *testing.T) {
+		th.App.config.TeamStorageQuota = 1000
+		th.Store.EXPECT().GetTeamFileUsageSize("team-id").Return(int64(600), nil)
+		require.NoError(t, th.App.checkStorageQuota("team-id", 400))
+	})
+
+	t.Run("exceeding the quota", func(t *testing.T) {
+		th.App.config.TeamStorageQuota = 1000
+		th.Store.EXPECT().GetTeamFileUsageSize("team-id").Return(int64(600), nil)
+		err := th.App.checkStorageQuota("team-id", 401)
+		require.Error(t, err)
+		require.True(t, model.IsErrRequestEntityTooLarge(err))
+		require.Contains(t, err.Error(), "600 of 1000 bytes used")
+	})
+
+	t.Run("quota of a specific team", func(t *testing.T) {
+		th.App.config.TeamStorageQuota = 1000
+		th.App.config.TeamStorageQuotas = map[string]int64{"big-team": 5000, "free-team": 0}
+		defer func() { th.App.config.TeamStorageQuotas = nil }()
+
+		th.Store.EXPECT().GetTeamFileUsageSize("big-team").Return(int64(600), nil)
+		require.NoError(t, th.App.checkStorageQuota("big-team", 2000))
+		require.NoError(t, th.App.checkStorageQuota("free-team", 1<<40))
+	})
+}
+
+func TestReserveStorage(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.TeamStorageQuota = 1000
+	defer func() { th.App.config.TeamStorageQuota = 0 }()
+
+	th.Store.EXPECT().GetTeamFileUsageSize("team-id").Return(int64(200), nil).AnyTimes()
+
+	release, err := th.App.reserveStorage("team-id", 500)
+	require.NoError(t, err)
+
+	// the storage reserved by the files being saved counts as used
+	_, err = th.App.reserveStorage("team-id", 400)
+	require.True(t, model.IsErrRequestEntityTooLarge(err))
+	require.Contains(t, err.Error(), "700 of 1000 bytes used")
+	require.True(t, model.IsErrRequestEntityTooLarge(th.App.checkStorageQuota("team-id", 400)))
+
+	remaining, err := th.App.remainingStorage("team-id")
+	require.NoError(t, err)
+	require.Equal(t, int64(300), remaining)
+
+	release()
+	releaseOther, err := th.App.reserveStorage("team-id", 400)
+	require.NoError(t, err)
+	releaseOther()
+	require.Empty(t, th.App.storageReservations)
+}
+
+func TestBackfillFileUsage(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("the backfill only runs once", func(t *testing.T) {
+		th.Store.EXPECT().GetSystemSetting(fileUsageBackfillKey).Return("true", nil)
+
+		count, err := th.App.BackfillFileUsage()
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("the usage of the untracked files is saved", func(t *testing.T) {
+		newBlock := func(id, boardID, fileName string) *model.Block {
+			return &model.Block{ID: id, BoardID: boardID, Type: model.TypeImage, Fields: map[string]interface{}{"fileId": fileName}}
+		}
+		blocks := []*model.Block{
+			newBlock("block-1", "board-id", "7untracked.png"),
+			newBlock("block-2", "board-id", "7tracked.png"),
+			newBlock("block-3", "board-id", "7missing.png"),
+			newBlock("block-4", "deleted-board-id", "7deleted.png"),
+		}
+
+		th.Store.EXPECT().GetSystemSetting(fileUsageBackfillKey).Return("", nil)
+		th.Store.EXPECT().GetFileBlocks("", fileUsageBackfillBatchSize).Return(blocks, nil)
+
+		th.Store.EXPECT().GetFileInfo("untracked").Return(&mm_model.FileInfo{Id: "untracked", Path: "boards/20220101/7untracked.png", Size: 10, CreateAt: 1000}, nil)
+		th.Store.EXPECT().GetFileUsageByFileID("untracked").Return(nil, model.NewErrNotFound("untracked"))
+		th.Store.EXPECT().GetBoard("board-id").Return(&model.Board{ID: "board-id", TeamID: "team-id"}, nil)
+		th.Store.EXPECT().SaveFileUsage(&model.FileUsage{
+			FileID:   "untracked",
+			TeamID:   "team-id",
+			BoardID:  "board-id",
+			FileName: "7untracked.png",
+			Path:     "boards/20220101/7untracked.png",
+			Size:     10,
+			CreateAt: 1000,
+		}).Return(nil)
+
+		th.Store.EXPECT().GetFileInfo("tracked").Return(&mm_model.FileInfo{Id: "tracked"}, nil)
+		th.Store.EXPECT().GetFileUsageByFileID("tracked").Return(&model.FileUsage{FileID: "tracked"}, nil)
+
+		th.Store.EXPECT().GetFileInfo("missing").Return(nil, model.NewErrNotFound("missing"))
+
+		th.Store.EXPECT().GetFileInfo("deleted").Return(&mm_model.FileInfo{Id: "deleted"}, nil)
+		th.Store.EXPECT().GetFileUsageByFileID("deleted").Return(nil, model.NewErrNotFound("deleted"))
+		th.Store.EXPECT().GetBoard("deleted-board-id").Return(nil, model.NewErrNotFound("deleted-board-id"))
+
+		th.Store.EXPECT().SetSystemSetting(fileUsageBackfillKey, "true").Return(nil)
+
+		count, err := th.App.BackfillFileUsage()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestGetFileUsage(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	th.App.config.TeamStorageQuota = 1000
+
+	th.Store.EXPECT().GetFileUsage("team-id").Return([]*model.TeamFileUsage{}, nil)
+	usage, err := th.App.GetFileUsage("team-id")
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, "team-id", usage[0].TeamID)
+	assert.Equal(t, int64(1000), usage[0].Quota)
+	assert.Empty(t, usage[0].Boards)
+}
+
+func TestCheckFileBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("a file of the board", func(t *testing.T) {
+		th.Store.EXPECT().GetFileUsageByFileID("file").Return(&model.FileUsage{FileID: "file", BoardID: "board-id"}, nil)
+		require.NoError(t, th.App.CheckFileBoard("board-id", "7file.png"))
+	})
+
+	t.Run("a file of another board", func(t *testing.T) {
+		th.Store.EXPECT().GetFileUsageByFileID("file").Return(&model.FileUsage{FileID: "file", BoardID: "other-board-id"}, nil)
+		err := th.App.CheckFileBoard("board-id", "7file.png")
+		require.True(t, model.IsErrNotFound(err), err)
+	})
+
+	t.Run("an untracked file", func(t *testing.T) {
+		th.Store.EXPECT().GetFileUsageByFileID("file").Return(nil, model.NewErrNotFound("file"))
+		require.NoError(t, th.App.CheckFileBoard("board-id", "7file.png"))
+	})
+}
+
+func TestCleanupOrphanedFiles(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	mockedFileBackend := &mocks.FileBackend{}
+	th.App.filesBackend = mockedFileBackend
+
+	usages := []*model.FileUsage{
+		{FileID: "referenced", BoardID: "board-1", FileName: "7referenced.png", Path: "boards/20220101/7referenced.png", Size: 10},
+		{FileID: "orphan", BoardID: "board-1", FileName: "7orphan.png", Path: "boards/20220101/7orphan.png", Size: 20},
+		{FileID: "missing", BoardID: "board-2", FileName: "7missing.txt", Path: "boards/20220101/7missing.txt", Size: 30},
+	}
+	th.Store.EXPECT().GetFileUsagesCreatedBefore(gomock.Any(), "", orphanedFilesBatchSize).Return(usages, nil)
+	th.Store.EXPECT().GetBoardFileReferences("board-1").Return([]string{"7referenced.png"}, nil)
+	th.Store.EXPECT().GetBoardFileReferences("board-2").Return([]string{}, nil)
+
+	// the previews of the orphaned image are deleted too
+	th.Store.EXPECT().GetFileInfo("orphan").Return(&mm_model.FileInfo{
+		Id:            "orphan",
+		Path:          "boards/20220101/7orphan.png",
+		ThumbnailPath: "boards/20220101/7orphan_thumb.jpg",
+	}, nil)
+	mockedFileBackend.On("FileExists", mock.Anything).Return(true, nil).Times(2)
+	mockedFileBackend.On("RemoveFile", "boards/20220101/7orphan.png").Return(nil)
+	mockedFileBackend.On("RemoveFile", "boards/20220101/7orphan_thumb.jpg").Return(nil)
+	th.Store.EXPECT().DeleteFileInfo("orphan").Return(nil)
+	th.Store.EXPECT().DeleteFileUsage("orphan").Return(nil)
+
+	// the usage of files already removed from the storage is cleaned up
+	th.Store.EXPECT().GetFileInfo("missing").Return(nil, model.NewErrNotFound("missing"))
+	mockedFileBackend.On("FileExists", "boards/20220101/7missing.txt").Return(false, nil)
+	th.Store.EXPECT().DeleteFileUsage("missing").Return(nil)
+
+	result, err := th.App.CleanupOrphanedFiles()
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Files)
+	assert.Equal(t, int64(50), result.Size)
+	mockedFileBackend.AssertExpectations(t)
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *App) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return a.store.GetBlockHistory(blockID, opts)
+}
+
+func (a *App) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+	return a.store.GetBoardHistory(boardID, opts)
+}
+
+// GetBlockHistoryDiff returns the differences of a block and its
+// children between the revisions at fromUpdateAt and toUpdateAt. If
+// toUpdateAt is zero, the latest revision is used.
+func (a *App) GetBlockHistoryDiff(blockID string, fromUpdateAt, toUpdateAt int64) (*model.BlockHistoryDiff, error) {
+	if toUpdateAt != 0 && toUpdateAt < fromUpdateAt {
+		return nil, model.NewErrBadRequest("the from revision must be older than the to revision")
+	}
+
+	oldBlock, err := a.getBlockVersion(blockID, fromUpdateAt)
+	if err != nil {
+		return nil, err
+	}
+	newBlock, err := a.getBlockVersion(blockID, toUpdateAt)
+	if err != nil {
+		return nil, err
+	}
+	if oldBlock == nil && newBlock == nil {
+		return nil, model.NewErrNotFound(fmt.Sprintf("block ID=%s between updateAt=%d and updateAt=%d", blockID, fromUpdateAt, toUpdateAt))
+	}
+
+	var boardID string
+	if newBlock != nil {
+		boardID = newBlock.BoardID
+	} else {
+		boardID = oldBlock.BoardID
+	}
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse board's property schema here so it only happens once.
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse property schema for board %s: %w", board.ID, err)
+	}
+
+	diff := a.diffBlockVersions(blockID, oldBlock, newBlock, schema)
+
+	opts := model.QueryBlockHistoryChildOptions{}
+	if toUpdateAt != 0 {
+		opts.BeforeUpdateAt = toUpdateAt + 1
+	}
+	children, _, err := a.store.GetBlockHistoryNewestChildren(blockID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get children for block %s: %w", blockID, err)
+	}
+
+	for _, child := range children {
+		oldChild, err := a.getBlockVersion(child.ID, fromUpdateAt)
+		if err != nil {
+			return nil, err
+		}
+		if oldChild != nil && oldChild.UpdateAt == child.UpdateAt {
+			continue
+		}
+
+		newChild := child
+		if newChild.DeleteAt != 0 {
+			newChild = nil
+		}
+		if oldChild == nil && newChild == nil {
+			continue
+		}
+		diff.Children = append(diff.Children, a.diffBlockVersions(child.ID, oldChild, newChild, schema))
+	}
+
+	return diff, nil
+}
+
+// getBlockVersion returns the version of a block at updateAt, or its
+// latest one if updateAt is zero. It returns nil if the block didn't
+// exist at that time.
+func (a *App) getBlockVersion(blockID string, updateAt int64) (*model.Block, error) {
+	opts := model.QueryBlockHistoryOptions{
+		Limit:      1,
+		Descending: true,
+	}
+	if updateAt != 0 {
+		opts.BeforeUpdateAt = updateAt + 1
+	}
+
+	history, err := a.store.GetBlockHistory(blockID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block history for block %s: %w", blockID, err)
+	}
+	if len(history) == 0 || history[0].DeleteAt != 0 {
+		return nil, nil
+	}
+	return history[0], nil
+}
+
+func (a *App) diffBlockVersions(blockID string, oldBlock, newBlock *model.Block, schema model.PropSchema) *model.BlockHistoryDiff {
+	oldProps, err := model.ParseProperties(oldBlock, schema, a.store)
+	if err != nil {
+		a.logger.Error("Cannot parse properties for old block",
+			mlog.String("block_id", blockID),
+			mlog.Err(err),
+		)
+	}
+
+	newProps, err := model.ParseProperties(newBlock, schema, a.store)
+	if err != nil {
+		a.logger.Error("Cannot parse properties for new block",
+			mlog.String("block_id", blockID),
+			mlog.Err(err),
+		)
+	}
+
+	propDiffs := model.DiffProperties(oldProps, newProps)
+	if propDiffs == nil {
+		propDiffs = []model.PropDiff{}
+	}
+
+	return &model.BlockHistoryDiff{
+		BlockID:   blockID,
+		OldBlock:  oldBlock,
+		NewBlock:  newBlock,
+		PropDiffs: propDiffs,
+		Children:  []*model.BlockHistoryDiff{},
+	}
+}
+
+// RestoreBlockHistory restores a block, and optionally its children,
+// to their revision at updateAt.
+func (a *App) RestoreBlockHistory(boardID, blockID string, updateAt int64, includeChildren bool, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.store.RestoreBlockHistory(blockID, updateAt, includeChildren, modifiedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	a.notifyHistoryRestored(board, result)
+	return result, nil
+}
+
+// RestoreBoardHistory restores a board and all its blocks to their
+// revision at updateAt.
+func (a *App) RestoreBoardHistory(boardID string, updateAt int64, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	result, err := a.store.RestoreBoardHistory(boardID, updateAt, modifiedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	a.notifyHistoryRestored(result.Board, result)
+	return result, nil
+}
+
+func (a *App) notifyHistoryRestored(board *model.Board, result *model.HistoryRestoreResult) {
+	a.metrics.IncrementBlocksPatched(len(result.Blocks))
+	a.metrics.IncrementBlocksDeleted(len(result.DeletedBlockIDs))
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		if result.Board != nil {
+			a.wsAdapter.BroadcastBoardChange(board.TeamID, result.Board)
+		}
+		for _, block := range result.Blocks {
+			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.webhook.NotifyUpdate(block)
+		}
+		for _, blockID := range result.DeletedBlockIDs {
+			a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, board.ID)
+		}
+		return nil
+	})
+}
@@ -35,15 +35,26 @@ var (
 //
 // Archives are ZIP files containing a `version.json` file and zero or more
 // directories, each containing a `board.jsonl` and zero or more image files.
-func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
+//
+// Depending on the import mode, boards of the archive that already exist are
+// imported as new boards, overwritten or merged. See `model.ImportMode`.
+func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) (*model.ImportArchiveResult, error) {
+	mode, err := model.ParseImportMode(string(opt.Mode))
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	opt.Mode = mode
+	result := model.NewImportArchiveResult(mode)
+
 	// peek at the first bytes to see if this is a legacy archive format
 	br := bufio.NewReader(r)
 	peek, err := br.Peek(len(legacyFileBegin))
 	if err == nil && string(peek) == legacyFileBegin {
 		a.logger.Debug("importing legacy archive")
-		_, errImport := a.ImportBoardJSONL(br, opt)
-
-		return errImport
+		if _, errImport := a.importBoardJSONL(br, opt, result); errImport != nil {
+			return nil, errImport
+		}
+		return result, nil
 	}
 
 	zr := zipstream.NewReader(br)
@@ -57,9 +68,9 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 			if errors.Is(err, io.EOF) {
 				a.fixImagesAttachments(boardMap, fileMap, opt.TeamID, opt.ModifiedBy)
 				a.logger.Debug("import archive - done", mlog.Int("boards_imported", len(boardMap)))
-				return nil
+				return result, nil
 			}
-			return err
+			return nil, err
 		}
 
 		dir, filename := filepath.Split(hdr.Name)
@@ -69,15 +80,19 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 		case "version.json":
 			ver, errVer := parseVersionFile(zr)
 			if errVer != nil {
-				return errVer
+				return nil, errVer
 			}
 			if ver != archiveVersion {
-				return model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
+				return nil, model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
 			}
 		case "board.jsonl":
-			board, err := a.ImportBoardJSONL(zr, opt)
+			board, err := a.importBoardJSONL(zr, opt, result)
 			if err != nil {
-				return fmt.Errorf("cannot import board %s: %w", dir, err)
+				return nil, fmt.Errorf("cannot import board %s: %w", dir, err)
+			}
+			if board == nil {
+				// the board conflicts with an existing one, its files are skipped
+				continue
 			}
 			boardMap[dir] = board
 		default:
@@ -144,12 +159,17 @@ func (a *App) fixImagesAttachments(boardMap map[string]*model.Board, fileMap map
 		for _, block := range newBlocks {
 			if block.Type == "image" || block.Type == "attachment" {
 				fieldName := "fileId"
-				oldID := block.Fields[fieldName]
+				oldID, _ := block.Fields[fieldName].(string)
+				newID, ok := fileMap[oldID]
+				if !ok {
+					// existing blocks of merged boards keep their files
+					continue
+				}
 				blockIDs = append(blockIDs, block.ID)
 
 				blockPatches = append(blockPatches, model.BlockPatch{
 					UpdatedFields: map[string]interface{}{
-						fieldName: fileMap[oldID.(string)],
+						fieldName: newID,
 					},
 				})
 			}
@@ -166,6 +186,12 @@ func (a *App) fixImagesAttachments(boardMap map[string]*model.Board, fileMap map
 // ImportBoardJSONL imports a JSONL file containing blocks for one board. The resulting
 // board id is returned.
 func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.Board, error) {
+	return a.importBoardJSONL(r, opt, model.NewImportArchiveResult(opt.Mode))
+}
+
+// importBoardJSONL imports a JSONL file and adds the outcome to the result.
+// No board is returned if it was skipped because of a conflict.
+func (a *App) importBoardJSONL(r io.Reader, opt model.ImportArchiveOptions, result *model.ImportArchiveResult) (*model.Board, error) {
 	// TODO: Stream this once `model.GenerateBlockIDs` can take a stream of blocks.
 	//       We don't want to load the whole file in memory, even though it's a single board.
 	boardsAndBlocks := &model.BoardsAndBlocks{
@@ -182,6 +208,8 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 	now := utils.GetMillis()
 	var boardID string
 	var boardMembers []*model.BoardMember
+	// the update times of the archive, used to merge with existing boards
+	updateAts := make(map[string]int64)
 
 	lineNum := 1
 	firstLine := true
@@ -217,6 +245,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 					if err2 := json.Unmarshal(archiveLine.Data, &board); err2 != nil {
 						return nil, fmt.Errorf("invalid board in archive line %d: %w", lineNum, err2)
 					}
+					updateAts[board.ID] = board.UpdateAt
 					board.ModifiedBy = userID
 					board.UpdateAt = now
 					board.TeamID = opt.TeamID
@@ -228,6 +257,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
 						return nil, fmt.Errorf("invalid board block in archive line %d: %w", lineNum, err2)
 					}
+					updateAts[block.ID] = block.UpdateAt
 					block.ModifiedBy = userID
 					block.UpdateAt = now
 					board, err := a.blockToBoard(block, opt)
@@ -241,6 +271,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
 						return nil, fmt.Errorf("invalid block in archive line %d: %w", lineNum, err2)
 					}
+					updateAts[block.ID] = block.UpdateAt
 					block.ModifiedBy = userID
 					block.UpdateAt = now
 					block.BoardID = boardID
@@ -272,19 +303,38 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 
 	a.fixBoardsandBlocks(boardsAndBlocks, opt)
 
+	var boards []*model.Board
 	var err error
-	boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
-	if err != nil {
-		return nil, fmt.Errorf("error generating archive block IDs: %w", err)
-	}
+	if opt.Mode == model.ImportModeOverwrite || opt.Mode == model.ImportModeMerge {
+		if len(boardsAndBlocks.Boards) == 0 {
+			return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+		}
+		boards, err = a.importBoardsInPlace(boardsAndBlocks, updateAts, opt, result)
+		if err != nil {
+			return nil, err
+		}
+		if len(boards) == 0 {
+			return nil, nil
+		}
+	} else {
+		boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
+		if err != nil {
+			return nil, fmt.Errorf("error generating archive block IDs: %w", err)
+		}
 
-	boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
-	if err != nil {
-		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+		boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
+		if err != nil {
+			return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+		}
+		boards = boardsAndBlocks.Boards
+		for _, board := range boards {
+			result.BoardsCreated = append(result.BoardsCreated, board.ID)
+		}
+		result.BlocksCreated += len(boardsAndBlocks.Blocks)
 	}
 
 	// add users to all the new boards (if not the fake system user).
-	for _, board := range boardsAndBlocks.Boards {
+	for _, board := range boards {
 		// make sure an admin user gets added
 		adminMember := &model.BoardMember{
 			BoardID:     board.ID,
@@ -295,6 +345,13 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 			return nil, fmt.Errorf("cannot add adminMember to board: %w", err2)
 		}
 		for _, boardMember := range boardMembers {
+			// the custom roles are defined per team, so they are only
+			// kept if the archive comes from the same team
+			customRoleID := boardMember.CustomRoleID
+			if a.checkCustomBoardRole(board, customRoleID) != nil {
+				customRoleID = ""
+			}
+
 			bm := &model.BoardMember{
 				BoardID:         board.ID,
 				UserID:          boardMember.UserID,
@@ -304,6 +361,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 				SchemeEditor:    boardMember.SchemeEditor,
 				SchemeCommenter: boardMember.SchemeCommenter,
 				SchemeViewer:    boardMember.SchemeViewer,
+				CustomRoleID:    customRoleID,
 				Synthetic:       boardMember.Synthetic,
 			}
 			if _, err2 := a.AddMemberToBoard(bm); err2 != nil {
@@ -313,7 +371,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 	}
 
 	// find new board id
-	for _, board := range boardsAndBlocks.Boards {
+	for _, board := range boards {
 		return board, nil
 	}
 	return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/importer"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// ImportExternal imports an export from another tool (Trello, Jira, Asana
+// or Notion) into the team, and returns a report of what was imported.
+func (a *App) ImportExternal(source importer.Source, r io.Reader, filename string, opt model.ImportArchiveOptions) (*model.ImportReport, error) {
+	// the exports larger than the limit are refused rather than truncated
+	data, err := io.ReadAll(io.LimitReader(r, importMaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > importMaxFileSize {
+		return nil, fmt.Errorf("%w: the export exceeds the maximum size of %d bytes", model.ErrRequestEntityTooLarge, importMaxFileSize)
+	}
+
+	result, err := importer.Import(source, bytes.NewReader(data), filename)
+	if errors.Is(err, importer.ErrArchiveTooLarge) {
+		return nil, fmt.Errorf("%w: %s", model.ErrRequestEntityTooLarge, err.Error())
+	}
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	bab := result.BoardsAndBlocks
+	for _, board := range bab.Boards {
+		board.TeamID = opt.TeamID
+		board.CreatedBy = opt.ModifiedBy
+		board.ModifiedBy = opt.ModifiedBy
+	}
+	for _, block := range bab.Blocks {
+		block.CreatedBy = opt.ModifiedBy
+		block.ModifiedBy = opt.ModifiedBy
+	}
+
+	// the files are stored first so their IDs can be set on the blocks
+	// referencing them, and are deleted if the import fails
+	blocksByID := make(map[string]*model.Block, len(bab.Blocks))
+	for _, block := range bab.Blocks {
+		blocksByID[block.ID] = block
+	}
+	savedFiles := make([]string, 0, len(result.Files))
+	for _, file := range result.Files {
+		block, ok := blocksByID[file.BlockID]
+		if !ok {
+			a.deleteImportedFiles(savedFiles)
+			return nil, fmt.Errorf("cannot save file %s: block %s not found", file.Filename, file.BlockID)
+		}
+		fileID, err := a.SaveFile(bytes.NewReader(file.Data), opt.TeamID, block.BoardID, file.Filename, false)
+		if err != nil {
+			a.deleteImportedFiles(savedFiles)
+			return nil, fmt.Errorf("cannot save file %s: %w", file.Filename, err)
+		}
+		savedFiles = append(savedFiles, fileID)
+		block.Fields["fileId"] = fileID
+	}
+
+	newBab, err := a.CreateBoardsAndBlocks(bab, opt.ModifiedBy, true)
+	if err != nil {
+		a.deleteImportedFiles(savedFiles)
+		return nil, err
+	}
+
+	report := &model.ImportReport{
+		Source:   string(source),
+		BoardIDs: make([]string, 0, len(newBab.Boards)),
+		Skipped:  result.Skipped,
+	}
+	for _, board := range newBab.Boards {
+		report.BoardIDs = append(report.BoardIDs, board.ID)
+	}
+	for _, block := range newBab.Blocks {
+		switch block.Type {
+		case model.TypeCard:
+			report.Cards++
+		case model.TypeComment:
+			report.Comments++
+		case model.TypeImage, model.TypeAttachment:
+			report.Attachments++
+		}
+	}
+
+	a.logger.Debug("import external - done",
+		mlog.String("source", string(source)),
+		mlog.Int("boards_imported", len(report.BoardIDs)),
+		mlog.Int("cards_imported", report.Cards),
+		mlog.Int("skipped", len(report.Skipped)),
+	)
+	return report, nil
+}
+
+// deleteImportedFiles deletes the files saved for an import that failed.
+func (a *App) deleteImportedFiles(fileNames []string) {
+	for _, fileName := range fileNames {
+		fileID := getFileInfoID(strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+		usage, err := a.store.GetFileUsageByFileID(fileID)
+		if err == nil {
+			err = a.deleteOrphanedFile(usage)
+		}
+		if err != nil {
+			a.logger.Warn("Cannot delete a file of a failed import", mlog.String("filename", fileName), mlog.Err(err))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/importer"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore/mocks"
+)
+
+// zeroReader reads an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestImportExternal(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	opt := model.ImportArchiveOptions{TeamID: "team-id", ModifiedBy: "user-id"}
+
+	t.Run("exports larger than the limit are refused", func(t *testing.T) {
+		_, err := th.App.ImportExternal(importer.SourceTrello, zeroReader{}, "export.json", opt)
+		require.True(t, model.IsErrRequestEntityTooLarge(err), err)
+	})
+
+	t.Run("the files are deleted when the import fails", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for name, content := range map[string]string{
+			"Tasks.csv":               "Name\nTask 1\n",
+			"Tasks/Task 1.md":         "# Task 1\n",
+			"Tasks/Task 1/report.pdf": "%PDF-1.4",
+		} {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+		mockedFileBackend.On("WriteFile", mock.Anything, mock.Anything).Return(int64(8), nil)
+
+		var usage *model.FileUsage
+		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil)
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).DoAndReturn(func(u *model.FileUsage) error {
+			usage = u
+			return nil
+		})
+		th.Store.EXPECT().CreateBoardsAndBlocksWithAdmin(gomock.Any(), "user-id").Return(nil, nil, errDummy)
+		th.Store.EXPECT().GetFileUsageByFileID(gomock.Any()).DoAndReturn(func(fileID string) (*model.FileUsage, error) {
+			require.Equal(t, usage.FileID, fileID)
+			return usage, nil
+		})
+		th.Store.EXPECT().GetFileInfo(gomock.Any()).Return(nil, model.NewErrNotFound("file info"))
+		th.Store.EXPECT().DeleteFileUsage(gomock.Any()).Return(nil)
+		mockedFileBackend.On("FileExists", mock.Anything).Return(true, nil)
+		mockedFileBackend.On("RemoveFile", mock.Anything).Return(nil)
+
+		_, err := th.App.ImportExternal(importer.SourceNotion, buf, "export.zip", opt)
+		require.ErrorIs(t, err, errDummy)
+		mockedFileBackend.AssertCalled(t, "RemoveFile", usage.Path)
+	})
+}
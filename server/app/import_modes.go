@@ -0,0 +1,244 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+const (
+	importConflictOtherTeam  = "the board belongs to another team"
+	importConflictArchived   = "the board is archived"
+	importConflictPermission = "access denied to modify the board"
+	importConflictOtherBoard = "the block belongs to another board"
+	importConflictNewerBoard = "the existing board is newer"
+	importConflictNewerBlock = "the existing block is newer"
+)
+
+// importBoardsInPlace imports boards keeping their IDs, for the overwrite
+// and merge modes. Boards that don't exist yet are created, the others are
+// updated according to the mode. The imported boards are returned.
+func (a *App) importBoardsInPlace(bab *model.BoardsAndBlocks, updateAts map[string]int64, opt model.ImportArchiveOptions, result *model.ImportArchiveResult) ([]*model.Board, error) {
+	boards := []*model.Board{}
+
+	for _, board := range bab.Boards {
+		blocks, err := a.importableBlocks(board, bab.Blocks, result)
+		if err != nil {
+			return nil, err
+		}
+
+		existingBoard, err := a.store.GetBoard(board.ID)
+		if err != nil && !model.IsErrNotFound(err) {
+			return nil, err
+		}
+
+		if existingBoard == nil {
+			newBab, err := a.CreateBoardsAndBlocks(&model.BoardsAndBlocks{Boards: []*model.Board{board}, Blocks: blocks}, opt.ModifiedBy, false)
+			if err != nil {
+				return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+			}
+			result.BoardsCreated = append(result.BoardsCreated, board.ID)
+			result.BlocksCreated += len(newBab.Blocks)
+			boards = append(boards, newBab.Boards[0])
+			continue
+		}
+
+		if reason := a.importBoardConflict(existingBoard, opt); reason != "" {
+			result.Conflicts = append(result.Conflicts, model.ImportConflict{
+				BoardID: board.ID,
+				Title:   existingBoard.Title,
+				Reason:  reason,
+			})
+			continue
+		}
+
+		updatedBoard, err := a.updateImportedBoard(existingBoard, board, blocks, updateAts, opt, result)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, updatedBoard)
+	}
+
+	return boards, nil
+}
+
+// importableBlocks returns the blocks of a board in the archive, leaving out
+// the ones whose ID is used by a block of another board.
+func (a *App) importableBlocks(board *model.Board, blocks []*model.Block, result *model.ImportArchiveResult) ([]*model.Block, error) {
+	ids := []string{}
+	for _, block := range blocks {
+		if block.BoardID == board.ID {
+			ids = append(ids, block.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return []*model.Block{}, nil
+	}
+
+	existingBlocks, err := a.store.GetBlocksByIDs(ids)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	otherBoards := map[string]bool{}
+	for _, block := range existingBlocks {
+		if block.BoardID != board.ID {
+			otherBoards[block.ID] = true
+		}
+	}
+
+	importable := make([]*model.Block, 0, len(ids))
+	for _, block := range blocks {
+		if block.BoardID != board.ID {
+			continue
+		}
+		if otherBoards[block.ID] {
+			result.Conflicts = append(result.Conflicts, model.ImportConflict{
+				BoardID: board.ID,
+				BlockID: block.ID,
+				Title:   block.Title,
+				Reason:  importConflictOtherBoard,
+			})
+			continue
+		}
+		importable = append(importable, block)
+	}
+	return importable, nil
+}
+
+// importBoardConflict returns why an existing board can't be modified by
+// the import, or an empty string if it can.
+func (a *App) importBoardConflict(board *model.Board, opt model.ImportArchiveOptions) string {
+	if board.TeamID != opt.TeamID {
+		return importConflictOtherTeam
+	}
+	if board.IsArchived() {
+		return importConflictArchived
+	}
+	if opt.ModifiedBy != model.SystemUserID && opt.ModifiedBy != model.SingleUser &&
+		!a.permissions.HasPermissionToBoard(opt.ModifiedBy, board.ID, model.PermissionManageBoardProperties) {
+		return importConflictPermission
+	}
+	return ""
+}
+
+// updateImportedBoard updates an existing board with the content of the
+// archive. In overwrite mode everything is replaced, in merge mode only
+// the existing elements older than the ones of the archive are.
+func (a *App) updateImportedBoard(existingBoard, board *model.Board, blocks []*model.Block, updateAts map[string]int64, opt model.ImportArchiveOptions, result *model.ImportArchiveResult) (*model.Board, error) {
+	overwrite := opt.Mode == model.ImportModeOverwrite
+	changed := false
+
+	switch {
+	case overwrite || updateAts[board.ID] > existingBoard.UpdateAt:
+		// the location and lifecycle of the board are kept
+		board.TeamID = existingBoard.TeamID
+		board.ChannelID = existingBoard.ChannelID
+		board.CreatedBy = existingBoard.CreatedBy
+		board.CreateAt = existingBoard.CreateAt
+		board.ArchiveAt = existingBoard.ArchiveAt
+		board.DeleteAt = 0
+
+		updatedBoard, err := a.store.InsertBoard(board, opt.ModifiedBy)
+		if err != nil {
+			return nil, fmt.Errorf("cannot update board %s: %w", board.ID, err)
+		}
+		a.wsAdapter.BroadcastBoardChange(updatedBoard.TeamID, updatedBoard)
+		existingBoard = updatedBoard
+		changed = true
+	case updateAts[board.ID] < existingBoard.UpdateAt && !sameBoardContent(board, existingBoard):
+		result.Conflicts = append(result.Conflicts, model.ImportConflict{
+			BoardID: board.ID,
+			Title:   existingBoard.Title,
+			Reason:  importConflictNewerBoard,
+		})
+	}
+
+	existingBlocks, err := a.store.GetBlocksForBoard(board.ID)
+	if err != nil {
+		return nil, err
+	}
+	existingMap := make(map[string]*model.Block, len(existingBlocks))
+	for _, block := range existingBlocks {
+		existingMap[block.ID] = block
+	}
+
+	// blocks missing from the archive are deleted before the others are
+	// written, as deleting a block also deletes its children
+	if overwrite {
+		archived := make(map[string]bool, len(blocks))
+		for _, block := range blocks {
+			archived[block.ID] = true
+		}
+		for _, block := range existingBlocks {
+			if archived[block.ID] {
+				continue
+			}
+			result.BlocksDeleted++
+			changed = true
+			if parent, ok := existingMap[block.ParentID]; ok && !archived[parent.ID] {
+				// deleted with its parent
+				continue
+			}
+			if err := a.DeleteBlockAndNotify(block.ID, opt.ModifiedBy, true); err != nil && !model.IsErrNotFound(err) {
+				return nil, fmt.Errorf("cannot delete block %s: %w", block.ID, err)
+			}
+		}
+	}
+
+	toInsert := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		existingBlock, ok := existingMap[block.ID]
+		switch {
+		case !ok:
+			result.BlocksCreated++
+		case overwrite || updateAts[block.ID] > existingBlock.UpdateAt:
+			result.BlocksUpdated++
+		case updateAts[block.ID] < existingBlock.UpdateAt && !sameBlockContent(block, existingBlock):
+			result.Conflicts = append(result.Conflicts, model.ImportConflict{
+				BoardID: board.ID,
+				BlockID: block.ID,
+				Title:   existingBlock.Title,
+				Reason:  importConflictNewerBlock,
+			})
+			continue
+		default:
+			// unchanged
+			continue
+		}
+		toInsert = append(toInsert, block)
+	}
+
+	if len(toInsert) != 0 {
+		if _, err := a.InsertBlocksAndNotify(toInsert, opt.ModifiedBy, true); err != nil {
+			return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+		}
+		changed = true
+	}
+
+	if changed {
+		result.BoardsUpdated = append(result.BoardsUpdated, board.ID)
+	}
+	return existingBoard, nil
+}
+
+// sameBoardContent returns true if the boards only differ by their
+// metadata, i.e. an archive imported again.
+func sameBoardContent(a, b *model.Board) bool {
+	return a.Title == b.Title &&
+		a.Description == b.Description &&
+		a.Icon == b.Icon &&
+		a.ShowDescription == b.ShowDescription &&
+		a.Type == b.Type &&
+		reflect.DeepEqual(a.Properties, b.Properties) &&
+		reflect.DeepEqual(a.CardProperties, b.CardProperties)
+}
+
+// sameBlockContent returns true if the blocks only differ by their
+// metadata, i.e. an archive imported again.
+func sameBlockContent(a, b *model.Block) bool {
+	return a.ParentID == b.ParentID &&
+		a.Type == b.Type &&
+		a.Title == b.Title &&
+		reflect.DeepEqual(a.Fields, b.Fields)
+}
@@ -68,8 +68,10 @@ func TestApp_ImportArchive(t *testing.T) {
 		th.Store.EXPECT().GetMembersForUser("user").Return([]*model.BoardMember{}, nil)
 		th.Store.EXPECT().AddUpdateCategoryBoard("user", utils.Anything, utils.Anything).Return(nil)
 
-		err := th.App.ImportArchive(r, opts)
+		result, err := th.App.ImportArchive(r, opts)
 		require.NoError(t, err, "import archive should not fail")
+		require.Equal(t, model.ImportModeCreate, result.Mode)
+		require.Equal(t, []string{board.ID}, result.BoardsCreated)
 	})
 
 	t.Run("import board archive", func(t *testing.T) {
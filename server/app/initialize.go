@@ -16,6 +16,8 @@ func (a *App) initialize(skipTemplateInit bool) {
 }
 
 func (a *App) Shutdown() {
+	a.closeTextEditSessions()
+
 	if a.blockChangeNotifier != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), blockChangeNotifierShutdownTimeout)
 		defer cancel()
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// defaultLoginAttemptWindowSeconds is the time after which the failed
+	// login attempts are forgotten when the lockout is disabled.
+	defaultLoginAttemptWindowSeconds = 900
+	// maxLoginFailureDelayShift caps the doubling of the failure delay.
+	maxLoginFailureDelayShift = 16
+)
+
+// loginAttemptKey identifies the failed login attempts on an account or
+// from a client IP address.
+type loginAttemptKey struct {
+	kind       string
+	identifier string
+	maxFailed  int
+}
+
+func (a *App) loginThrottlingEnabled() bool {
+	return a.config.LoginFailureDelaySeconds > 0 ||
+		(a.config.LoginLockoutSeconds > 0 && (a.config.LoginMaxFailedAttempts > 0 || a.config.LoginMaxFailedAttemptsPerIP > 0))
+}
+
+func (a *App) loginAttemptWindowMillis() int64 {
+	if a.config.LoginLockoutSeconds > 0 {
+		return int64(a.config.LoginLockoutSeconds) * 1000
+	}
+	return defaultLoginAttemptWindowSeconds * 1000
+}
+
+// loginAttemptKeys returns the keys the failed login attempts are tracked
+// on. The account is identified by the user ID, or by the login name when
+// it doesn't match any user, so unknown accounts are throttled the same
+// way and can't be told apart.
+func (a *App) loginAttemptKeys(user *model.User, username, email, ipAddress string) []loginAttemptKey {
+	accountID := strings.ToLower(username)
+	if user != nil {
+		accountID = user.ID
+	} else if accountID == "" {
+		accountID = strings.ToLower(email)
+	}
+
+	keys := []loginAttemptKey{{
+		kind:       model.LoginAttemptKindUser,
+		identifier: accountID,
+		maxFailed:  a.config.LoginMaxFailedAttempts,
+	}}
+	return a.appendIPAttemptKey(keys, ipAddress)
+}
+
+// shareLinkAttemptKeys returns the keys the failed attempts to unlock a
+// password protected share link are tracked on, so the link passwords
+// can't be guessed faster than the account passwords.
+func (a *App) shareLinkAttemptKeys(link *model.ShareLink, ipAddress string) []loginAttemptKey {
+	keys := []loginAttemptKey{{
+		kind:       model.LoginAttemptKindShareLink,
+		identifier: link.ID,
+		maxFailed:  a.config.LoginMaxFailedAttempts,
+	}}
+	return a.appendIPAttemptKey(keys, ipAddress)
+}
+
+func (a *App) appendIPAttemptKey(keys []loginAttemptKey, ipAddress string) []loginAttemptKey {
+	if ipAddress != "" && a.config.LoginMaxFailedAttemptsPerIP > 0 {
+		keys = append(keys, loginAttemptKey{
+			kind:       model.LoginAttemptKindIP,
+			identifier: ipAddress,
+			maxFailed:  a.config.LoginMaxFailedAttemptsPerIP,
+		})
+	}
+	return keys
+}
+
+// loginFailureDelayMillis returns the time to wait after the last failed
+// attempt, doubled after each consecutive failure.
+func (a *App) loginFailureDelayMillis(failedCount int) int64 {
+	if a.config.LoginFailureDelaySeconds <= 0 || failedCount < 1 {
+		return 0
+	}
+
+	shift := failedCount - 1
+	if shift > maxLoginFailureDelayShift {
+		shift = maxLoginFailureDelayShift
+	}
+	delay := int64(a.config.LoginFailureDelaySeconds) * 1000 << shift
+	if window := a.loginAttemptWindowMillis(); delay > window {
+		delay = window
+	}
+	return delay
+}
+
+// checkLoginAttempts returns an ErrTooManyRequests if the login is locked
+// out, or delayed after the previous failed attempts.
+func (a *App) checkLoginAttempts(keys []loginAttemptKey, now int64) error {
+	windowStart := now - a.loginAttemptWindowMillis()
+
+	for _, key := range keys {
+		attempt, err := a.store.GetLoginAttempt(key.kind, key.identifier)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if attempt.IsLocked(now) {
+			return model.NewErrTooManyRequests("too many failed login attempts", retryAfterSeconds(attempt.LockedUntil-now))
+		}
+
+		if key.kind == model.LoginAttemptKindIP || attempt.LastFailureAt < windowStart {
+			continue
+		}
+		if nextAttemptAt := attempt.LastFailureAt + a.loginFailureDelayMillis(attempt.FailedCount); nextAttemptAt > now {
+			return model.NewErrTooManyRequests("login attempted too soon after a failure", retryAfterSeconds(nextAttemptAt-now))
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login attempt on each key, and locks
+// out the ones reaching their maximum number of failed attempts. It
+// returns an ErrLoginLockout if any lockout started.
+func (a *App) recordLoginFailure(keys []loginAttemptKey, user *model.User, now int64) error {
+	windowStart := now - a.loginAttemptWindowMillis()
+	lockoutMillis := int64(a.config.LoginLockoutSeconds) * 1000
+
+	var locked []*model.LoginAttempt
+	for _, key := range keys {
+		attempt, err := a.store.IncrementLoginAttempt(key.kind, key.identifier, now, windowStart)
+		if err != nil {
+			return err
+		}
+
+		if lockoutMillis <= 0 || key.maxFailed <= 0 || attempt.FailedCount < key.maxFailed {
+			continue
+		}
+
+		attempt.LockedUntil = now + lockoutMillis
+		if key.kind == model.LoginAttemptKindUser && user != nil && user.Email != "" && a.IsEmailConfigured() {
+			attempt.UnlockToken = utils.NewID(utils.IDTypeToken)
+		}
+		if err := a.store.LockLoginAttempt(attempt.Kind, attempt.Identifier, attempt.LockedUntil, attempt.UnlockToken); err != nil {
+			return err
+		}
+
+		a.logger.Warn("login locked out after too many failed attempts",
+			mlog.String("kind", attempt.Kind),
+			mlog.String("identifier", attempt.Identifier),
+			mlog.Int("failedCount", attempt.FailedCount),
+		)
+
+		if attempt.UnlockToken != "" {
+			a.sendAccountLockoutEmail(user, attempt.UnlockToken)
+		}
+		locked = append(locked, attempt)
+	}
+
+	if len(locked) > 0 {
+		return model.NewErrLoginLockout(int64(a.config.LoginLockoutSeconds), locked)
+	}
+	return nil
+}
+
+func (a *App) sendAccountLockoutEmail(user *model.User, unlockToken string) {
+	serverRoot := a.config.ServerRoot
+	if serverRoot == "" {
+		serverRoot = "http://localhost:8000"
+	}
+
+	lockedMinutes := (a.config.LoginLockoutSeconds + 59) / 60
+	if err := a.email.SendAccountLockout(user.Email, user.Username, unlockToken, serverRoot, lockedMinutes); err != nil {
+		a.logger.Error("failed to send the account lockout email",
+			mlog.String("userID", user.ID),
+			mlog.Err(err),
+		)
+	}
+}
+
+// UnlockAccount lifts the lockout of the account the unlock token was
+// sent for, and returns the attempts it was locked out by.
+func (a *App) UnlockAccount(token string) (*model.LoginAttempt, error) {
+	attempt, err := a.store.GetLoginAttemptByUnlockToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.store.DeleteLoginAttempt(attempt.Kind, attempt.Identifier); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// UnlockUser lifts the lockout of a user account and clears its failed
+// login attempts.
+func (a *App) UnlockUser(actorID, userID string) error {
+	if _, err := a.store.GetUserByIDIncludingDeactivated(userID); err != nil {
+		return err
+	}
+
+	if err := a.store.DeleteLoginAttempt(model.LoginAttemptKindUser, userID); err != nil {
+		return err
+	}
+
+	a.logger.Info("user login unlocked",
+		mlog.String("userID", userID),
+		mlog.String("actorID", actorID),
+	)
+	return nil
+}
+
+// CleanupExpiredLoginAttempts deletes the failed login attempts that
+// don't count anymore, and returns how many were deleted.
+func (a *App) CleanupExpiredLoginAttempts() (int64, error) {
+	now := utils.GetMillis()
+	return a.store.DeleteExpiredLoginAttempts(now-a.loginAttemptWindowMillis(), now)
+}
+
+func retryAfterSeconds(millis int64) int64 {
+	return (millis + 999) / 1000
+}
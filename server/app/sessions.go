@@ -0,0 +1,87 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetSessionsForUser returns the sessions of a user, flagging the
+// current one.
+func (a *App) GetSessionsForUser(userID, currentSessionID string) ([]*model.SessionInfo, error) {
+	sessions, err := a.store.GetSessionsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*model.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, model.NewSessionInfo(session, currentSessionID))
+	}
+	return infos, nil
+}
+
+// RevokeSession revokes a session of a user, logging out its client.
+func (a *App) RevokeSession(userID, sessionID string) error {
+	sessions, err := a.store.GetSessionsForUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			if err := a.store.DeleteSession(sessionID); err != nil {
+				return err
+			}
+			a.wsAdapter.CloseSessions(sessionID)
+			a.metrics.IncrementLogoutCount(1)
+			return nil
+		}
+	}
+	return model.NewErrNotFound("session ID=" + sessionID)
+}
+
+// RevokeOtherSessions revokes all the sessions of a user but the current
+// one, and returns the number of sessions revoked.
+func (a *App) RevokeOtherSessions(userID, currentSessionID string) (int, error) {
+	sessions, err := a.store.GetSessionsForUser(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := []string{}
+	defer func() { a.wsAdapter.CloseSessions(revoked...) }()
+
+	for _, session := range sessions {
+		if session.ID == currentSessionID {
+			continue
+		}
+		if err := a.store.DeleteSession(session.ID); err != nil {
+			return len(revoked), err
+		}
+		revoked = append(revoked, session.ID)
+	}
+
+	count := len(revoked)
+	a.metrics.IncrementLogoutCount(count)
+	return count, nil
+}
+
+// RevokeSessionsForUser revokes all the sessions of a user, forcing it to
+// log in again, e.g. when its account is compromised.
+func (a *App) RevokeSessionsForUser(actorID, userID string) error {
+	if _, err := a.store.GetUserByIDIncludingDeactivated(userID); err != nil {
+		return err
+	}
+
+	if err := a.store.DeleteSessionsForUser(userID); err != nil {
+		return err
+	}
+	a.wsAdapter.CloseSessionsForUser(userID)
+
+	a.logger.Info("user sessions revoked",
+		mlog.String("userID", userID),
+		mlog.String("actorID", actorID),
+	)
+	return nil
+}
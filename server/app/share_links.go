@@ -0,0 +1,321 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/auth"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// CreateShareLink creates a new share link of a board, with its own token.
+func (a *App) CreateShareLink(link *model.ShareLink, userID string) (*model.ShareLink, error) {
+	if err := link.IsValid(); err != nil {
+		return nil, err
+	}
+	if err := a.checkShareLinkView(link); err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	link.ID = utils.NewID(utils.IDTypeNone)
+	link.Token = utils.NewID(utils.IDTypeToken)
+	link.PasswordHash = ""
+	if link.Password != "" {
+		link.PasswordHash = auth.HashPassword(link.Password)
+	}
+	link.ViewCount = 0
+	link.LastViewedAt = 0
+	link.CreatedBy = userID
+	link.CreateAt = now
+	link.UpdateAt = now
+
+	if err := a.store.CreateShareLink(link); err != nil {
+		return nil, err
+	}
+	link.Sanitize()
+	return link, nil
+}
+
+func (a *App) GetShareLink(id string) (*model.ShareLink, error) {
+	return a.store.GetShareLink(id)
+}
+
+func (a *App) GetShareLinksForBoard(boardID string) ([]*model.ShareLink, error) {
+	return a.store.GetShareLinksForBoard(boardID)
+}
+
+// PatchShareLink updates the settings of a share link. Changing the
+// password invalidates the read tokens unlocked with the previous one.
+func (a *App) PatchShareLink(link *model.ShareLink, patch *model.ShareLinkPatch) (*model.ShareLink, error) {
+	link = patch.Patch(link)
+	if err := link.IsValid(); err != nil {
+		return nil, err
+	}
+	if patch.ViewID != nil {
+		if err := a.checkShareLinkView(link); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Password != nil {
+		link.PasswordHash = ""
+		if *patch.Password != "" {
+			link.PasswordHash = auth.HashPassword(*patch.Password)
+		}
+	}
+	link.UpdateAt = utils.GetMillis()
+
+	if err := a.store.UpdateShareLink(link); err != nil {
+		return nil, err
+	}
+	link.Sanitize()
+	return link, nil
+}
+
+// DeleteShareLink revokes a share link, leaving the other links of the
+// board untouched.
+func (a *App) DeleteShareLink(id string) error {
+	return a.store.DeleteShareLink(id)
+}
+
+// UnlockShareLink checks the password of a share link, and returns the
+// read token to open it. The failed attempts are throttled like the
+// failed logins.
+func (a *App) UnlockShareLink(boardID string, request *model.ShareLinkUnlockRequest, ipAddress string) (*model.ShareLinkUnlockResponse, error) {
+	link, err := a.store.GetShareLinkByToken(request.Token)
+	if err != nil {
+		return nil, err
+	}
+	now := utils.GetMillis()
+	if link.BoardID != boardID || link.IsExpired(now) {
+		return nil, model.NewErrNotFound("share link")
+	}
+
+	if link.PasswordHash == "" {
+		return &model.ShareLinkUnlockResponse{ReadToken: link.UnlockedReadToken()}, nil
+	}
+
+	var attemptKeys []loginAttemptKey
+	if a.loginThrottlingEnabled() {
+		attemptKeys = a.shareLinkAttemptKeys(link, ipAddress)
+		if err := a.checkLoginAttempts(attemptKeys, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if !auth.ComparePassword(link.PasswordHash, request.Password) {
+		if attemptKeys != nil {
+			if err := a.recordLoginFailure(attemptKeys, nil, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, model.NewErrUnauthorized("invalid share link password")
+	}
+
+	if attemptKeys != nil {
+		if err := a.store.DeleteLoginAttempt(model.LoginAttemptKindShareLink, link.ID); err != nil {
+			a.logger.Error("unable to clear the failed share link unlock attempts", mlog.String("linkID", link.ID), mlog.Err(err))
+		}
+	}
+	return &model.ShareLinkUnlockResponse{ReadToken: link.UnlockedReadToken()}, nil
+}
+
+// GetShareLinkForReadToken returns the share link of a board opened by a
+// read token, or nil if there is none.
+func (a *App) GetShareLinkForReadToken(boardID string, readToken string) (*model.ShareLink, error) {
+	return a.auth.GetShareLinkForReadToken(boardID, readToken)
+}
+
+// RecordShareLinkView counts an opening of a share link.
+func (a *App) RecordShareLinkView(link *model.ShareLink) error {
+	return a.store.IncrementShareLinkViews(link.ID)
+}
+
+// GetBoardForShareLink returns a copy of a board without the card
+// properties hidden by a share link.
+func (a *App) GetBoardForShareLink(board *model.Board, link *model.ShareLink) *model.Board {
+	if len(link.HiddenProperties) == 0 {
+		return board
+	}
+
+	hidden := hiddenPropertiesSet(link)
+	sharedBoard := *board
+	sharedBoard.CardProperties = []map[string]interface{}{}
+	for _, property := range board.CardProperties {
+		if id, _ := property["id"].(string); !hidden[id] {
+			sharedBoard.CardProperties = append(sharedBoard.CardProperties, property)
+		}
+	}
+	return &sharedBoard
+}
+
+// GetBlocksForShareLink filters the blocks of a board shared by a link.
+// Links restricted to a view only share the view, the cards it shows and
+// their contents, and the hidden properties are removed from the cards.
+func (a *App) GetBlocksForShareLink(board *model.Board, link *model.ShareLink, blocks []*model.Block) ([]*model.Block, error) {
+	var allowedIDs map[string]bool
+	if link.ViewID != "" {
+		var err error
+		if allowedIDs, err = a.getShareLinkViewBlockIDs(board, link); err != nil {
+			return nil, err
+		}
+	}
+	return filterBlocksForShareLink(link, allowedIDs, blocks), nil
+}
+
+// NewShareLinkBlockFilter returns the function filtering the blocks of a
+// board for each share link they are broadcast to, as
+// GetBlocksForShareLink does, nil if the link doesn't share the block.
+// The board and the blocks of the view of each link are loaded once. The
+// returned function isn't safe for concurrent use.
+func (a *App) NewShareLinkBlockFilter(boardID string) func(link *model.ShareLink, block *model.Block) *model.Block {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		a.logger.Error("cannot get board to filter block for share link",
+			mlog.String("boardID", boardID),
+			mlog.Err(err),
+		)
+		return func(*model.ShareLink, *model.Block) *model.Block { return nil }
+	}
+
+	allowedIDsByLink := map[string]map[string]bool{}
+	return func(link *model.ShareLink, block *model.Block) *model.Block {
+		// the deleted blocks are broadcast without their contents
+		if block.DeleteAt != 0 {
+			return block
+		}
+
+		allowedIDs, ok := allowedIDsByLink[link.ID]
+		if !ok && link.ViewID != "" {
+			var err error
+			if allowedIDs, err = a.getShareLinkViewBlockIDs(board, link); err != nil {
+				a.logger.Error("cannot get the blocks of a share link view",
+					mlog.String("boardID", boardID),
+					mlog.String("shareLinkID", link.ID),
+					mlog.Err(err),
+				)
+				return nil
+			}
+			allowedIDsByLink[link.ID] = allowedIDs
+		}
+
+		blocks := filterBlocksForShareLink(link, allowedIDs, []*model.Block{block})
+		if len(blocks) == 0 {
+			return nil
+		}
+		return blocks[0]
+	}
+}
+
+// filterBlocksForShareLink filters the blocks shared by a link, given the
+// IDs of its view and of the cards the view shows, nil if the link isn't
+// restricted to a view.
+func filterBlocksForShareLink(link *model.ShareLink, allowedIDs map[string]bool, blocks []*model.Block) []*model.Block {
+	if allowedIDs != nil {
+		filtered := []*model.Block{}
+		for _, block := range blocks {
+			if allowedIDs[block.ID] || (block.Type != model.TypeCard && allowedIDs[block.ParentID]) {
+				filtered = append(filtered, block)
+			}
+		}
+		blocks = filtered
+	}
+
+	if len(link.HiddenProperties) == 0 {
+		return blocks
+	}
+
+	hidden := hiddenPropertiesSet(link)
+	sharedBlocks := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		sharedBlocks = append(sharedBlocks, block.WithoutProperties(hidden))
+	}
+	return sharedBlocks
+}
+
+// CanShareLinkAccessFile returns whether a file of a board is shared by a
+// link. Links restricted to a view only share the files of the cards the
+// view shows.
+func (a *App) CanShareLinkAccessFile(board *model.Board, link *model.ShareLink, fileName string) (bool, error) {
+	if link.ViewID == "" {
+		return true, nil
+	}
+
+	allowedIDs, err := a.getShareLinkViewBlockIDs(board, link)
+	if err != nil {
+		return false, err
+	}
+
+	for _, blockType := range []string{model.TypeImage, model.TypeAttachment} {
+		blocks, err := a.GetBlocks(board.ID, "", blockType)
+		if err != nil {
+			return false, err
+		}
+		for _, block := range blocks {
+			if !allowedIDs[block.ParentID] {
+				continue
+			}
+			for _, key := range []string{"fileId", "attachmentId"} {
+				if id, _ := block.Fields[key].(string); id == fileName {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// getShareLinkViewBlockIDs returns the IDs of the view of a share link and
+// of the cards the view shows.
+func (a *App) getShareLinkViewBlockIDs(board *model.Board, link *model.ShareLink) (map[string]bool, error) {
+	view, err := a.GetBlockByID(link.ViewID)
+	if model.IsErrNotFound(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if view.BoardID != board.ID {
+		return map[string]bool{}, nil
+	}
+
+	cards, err := a.GetBlocks(board.ID, "", model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedIDs := map[string]bool{view.ID: true}
+	for _, card := range cards {
+		if model.CardMatchesViewFilter(view, board.CardProperties, card) {
+			allowedIDs[card.ID] = true
+		}
+	}
+	return allowedIDs, nil
+}
+
+func (a *App) checkShareLinkView(link *model.ShareLink) error {
+	if link.ViewID == "" {
+		return nil
+	}
+
+	view, err := a.GetBlockByID(link.ViewID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrBadRequest("share link view not found")
+	}
+	if err != nil {
+		return err
+	}
+	if view.BoardID != link.BoardID || view.Type != model.TypeView {
+		return model.NewErrBadRequest("share link view must be a view of the board")
+	}
+	return nil
+}
+
+func hiddenPropertiesSet(link *model.ShareLink) map[string]bool {
+	hidden := map[string]bool{}
+	for _, id := range link.HiddenProperties {
+		hidden[id] = true
+	}
+	return hidden
+}
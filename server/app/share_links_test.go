@@ -0,0 +1,80 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShareLinkBlockFilter(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-id",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "type": "select"},
+			{"id": "secret", "type": "text"},
+		},
+	}
+	view := &model.Block{
+		ID:      "view-id",
+		BoardID: board.ID,
+		Type:    model.TypeView,
+		Fields: map[string]interface{}{"filter": map[string]interface{}{
+			"operation": "and",
+			"filters": []interface{}{map[string]interface{}{
+				"propertyId": "status", "condition": "includes", "values": []interface{}{"done"},
+			}},
+		}},
+	}
+	newCard := func(id, status string) *model.Block {
+		return &model.Block{
+			ID:       id,
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Fields: map[string]interface{}{"properties": map[string]interface{}{
+				"status": status,
+				"secret": "hidden",
+			}},
+		}
+	}
+	doneCard := newCard("done-card", "done")
+	todoCard := newCard("todo-card", "todo")
+	todoContent := &model.Block{ID: "todo-content", BoardID: board.ID, ParentID: todoCard.ID, Type: model.TypeText}
+
+	th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+	filter := th.App.NewShareLinkBlockFilter(board.ID)
+
+	t.Run("a view limited link only receives the blocks of its view", func(t *testing.T) {
+		link := &model.ShareLink{ID: "view-link", BoardID: board.ID, ViewID: view.ID}
+		// the blocks of the view are loaded once
+		th.Store.EXPECT().GetBlock(view.ID).Return(view, nil)
+		th.Store.EXPECT().GetBlocksWithType(board.ID, model.TypeCard).Return([]*model.Block{doneCard, todoCard}, nil)
+
+		require.Equal(t, doneCard, filter(link, doneCard))
+		require.Equal(t, view, filter(link, view))
+		require.Nil(t, filter(link, todoCard))
+		require.Nil(t, filter(link, todoContent))
+	})
+
+	t.Run("a link hiding properties doesn't receive their values", func(t *testing.T) {
+		link := &model.ShareLink{ID: "hiding-link", BoardID: board.ID, HiddenProperties: []string{"secret"}}
+
+		block := filter(link, todoCard)
+		require.NotNil(t, block)
+		properties := block.Fields["properties"].(map[string]interface{})
+		require.Equal(t, "todo", properties["status"])
+		require.NotContains(t, properties, "secret")
+	})
+
+	t.Run("the deleted blocks are received", func(t *testing.T) {
+		link := &model.ShareLink{ID: "view-link", BoardID: board.ID, ViewID: view.ID}
+		deleted := &model.Block{ID: todoCard.ID, BoardID: board.ID, DeleteAt: 1}
+
+		require.Equal(t, deleted, filter(link, deleted))
+	})
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// EnsureSystemAdmins promotes the users configured as system admins, and
+// the first registered user if the server has no system admin, so that
+// the server can always be administered from the web.
+func (a *App) EnsureSystemAdmins() error {
+	for _, username := range a.config.SystemAdminUsernames {
+		user, err := a.store.GetUserByUsername(username)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if user.IsSystemAdmin() {
+			continue
+		}
+		if err = a.store.UpdateUserRoles(user.ID, model.SystemRoles(true)); err != nil {
+			return err
+		}
+		a.logger.Info("Promoted configured user to system admin", mlog.String("username", username))
+	}
+
+	users, err := a.store.GetAllUsers(model.UserQueryOptions{})
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 || countSystemAdmins(users) > 0 {
+		return nil
+	}
+
+	first := firstRegisteredUser(users)
+	if err = a.store.UpdateUserRoles(first.ID, model.SystemRoles(true)); err != nil {
+		return err
+	}
+	a.logger.Info("Promoted the first registered user to system admin", mlog.String("username", first.Username))
+	return nil
+}
+
+// newUserRoles returns the roles of a user registering. Only the first
+// user of the server is a system admin, the configured users are promoted
+// by EnsureSystemAdmins, as anybody could register with their usernames.
+func (a *App) newUserRoles() (string, error) {
+	count, err := a.store.GetRegisteredUserCount()
+	if err != nil {
+		return "", err
+	}
+	return model.SystemRoles(count == 0), nil
+}
+
+// GetUsersForAdmin returns a page of the users of the server.
+func (a *App) GetUsersForAdmin(opts model.UserQueryOptions) ([]*model.User, error) {
+	users, err := a.store.GetAllUsers(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		user.Sanitize(map[string]bool{})
+	}
+	return users, nil
+}
+
+// UpdateUserActive deactivates a user and revokes its sessions, or
+// reactivates it. The last system admin can't be deactivated.
+func (a *App) UpdateUserActive(actorID, userID string, active bool) error {
+	user, err := a.store.GetUserByIDIncludingDeactivated(userID)
+	if err != nil {
+		return err
+	}
+
+	if !active {
+		if actorID == userID {
+			return model.NewErrBadRequest("system admins cannot deactivate themselves")
+		}
+		if err = a.checkNotLastSystemAdmin(user); err != nil {
+			return err
+		}
+	}
+
+	if err = a.store.UpdateUserActive(userID, active); err != nil {
+		return err
+	}
+	if !active {
+		if err = a.store.DeleteSessionsForUser(userID); err != nil {
+			return err
+		}
+		a.wsAdapter.CloseSessionsForUser(userID)
+	}
+	return nil
+}
+
+// DeleteUser deletes a user, replacing it by an anonymous deactivated
+// user. The content the user authored is kept and attributed to the
+// anonymous user, while its memberships, subscriptions, categories,
+// preferences and sessions are removed. It returns the record of what
+// was done.
+func (a *App) DeleteUser(actorID, userID string) (*model.UserDeletionReport, error) {
+	user, err := a.store.GetUserByIDIncludingDeactivated(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID == userID {
+		return nil, model.NewErrBadRequest("system admins cannot delete themselves")
+	}
+	if err = a.checkNotLastSystemAdmin(user); err != nil {
+		return nil, err
+	}
+
+	anonymousID := utils.NewID(utils.IDTypeUser)
+	anonymousUser := &model.User{
+		ID:       anonymousID,
+		Username: model.DeletedUsernamePrefix + anonymousID,
+		DeleteAt: utils.GetMillis(),
+	}
+
+	report, err := a.store.AnonymizeUser(userID, anonymousUser)
+	if err != nil {
+		return nil, err
+	}
+	report.DeletedBy = actorID
+	a.wsAdapter.CloseSessionsForUser(userID)
+
+	a.logger.Info("Deleted user",
+		mlog.String("userID", userID),
+		mlog.String("anonymousID", anonymousID),
+		mlog.String("deletedBy", actorID),
+		mlog.Any("anonymized", report.Anonymized),
+		mlog.Any("removed", report.Removed),
+	)
+	return report, nil
+}
+
+// UpdateUserSystemAdmin promotes a user to system admin, or demotes it.
+// The last system admin can't be demoted.
+func (a *App) UpdateUserSystemAdmin(userID string, isAdmin bool) error {
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsSystemAdmin() == isAdmin {
+		return nil
+	}
+
+	if isAdmin && user.IsGuest {
+		return model.NewErrBadRequest("guests cannot be system admins")
+	}
+	if !isAdmin {
+		if err = a.checkNotLastSystemAdmin(user); err != nil {
+			return err
+		}
+	}
+	return a.store.UpdateUserRoles(userID, model.SystemRoles(isAdmin))
+}
+
+// UpdateUserGuest makes a user a guest, whose account expires at expireAt
+// if set, or a regular user. The system admins can't be guests.
+func (a *App) UpdateUserGuest(userID string, isGuest bool, expireAt int64) error {
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if isGuest {
+		if user.IsSystemAdmin() {
+			return model.NewErrBadRequest("system admins cannot be guests")
+		}
+		if !model.IsValidGuestExpireAt(expireAt) {
+			return model.NewErrBadRequest("the guest expiration must be in the future")
+		}
+	}
+	return a.store.UpdateUserGuest(userID, isGuest, expireAt)
+}
+
+// GetSystemStatistics returns the statistics of the server.
+func (a *App) GetSystemStatistics() (*model.SystemStatistics, error) {
+	users, err := a.GetRegisteredUserCount()
+	if err != nil {
+		return nil, err
+	}
+	dailyActiveUsers, err := a.GetDailyActiveUsers()
+	if err != nil {
+		return nil, err
+	}
+	boards, err := a.GetBoardCount()
+	if err != nil {
+		return nil, err
+	}
+	cards, err := a.GetUsedCardsCount()
+	if err != nil {
+		return nil, err
+	}
+	fileUsage, err := a.GetFileUsage("")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &model.SystemStatistics{
+		Users:            users,
+		DailyActiveUsers: dailyActiveUsers,
+		Boards:           int(boards),
+		Cards:            cards,
+	}
+	for _, team := range fileUsage {
+		stats.Files += team.Files
+		stats.FilesSize += team.Size
+	}
+	return stats, nil
+}
+
+func (a *App) checkNotLastSystemAdmin(user *model.User) error {
+	if !user.IsSystemAdmin() || user.DeleteAt != 0 {
+		return nil
+	}
+
+	users, err := a.store.GetAllUsers(model.UserQueryOptions{})
+	if err != nil {
+		return err
+	}
+	if countSystemAdmins(users) <= 1 {
+		return model.NewErrBadRequest("the server must keep at least one system admin")
+	}
+	return nil
+}
+
+// firstRegisteredUser returns the user created first, the users created
+// in the same millisecond being ordered by ID.
+func firstRegisteredUser(users []*model.User) *model.User {
+	first := users[0]
+	for _, user := range users[1:] {
+		if user.CreateAt < first.CreateAt || (user.CreateAt == first.CreateAt && user.ID < first.ID) {
+			first = user
+		}
+	}
+	return first
+}
+
+func countSystemAdmins(users []*model.User) int {
+	count := 0
+	for _, user := range users {
+		if user.IsSystemAdmin() {
+			count++
+		}
+	}
+	return count
+}
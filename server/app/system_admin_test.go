@@ -0,0 +1,58 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestEnsureSystemAdmins(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("the earliest user is promoted", func(t *testing.T) {
+		users := []*model.User{
+			{ID: "user-c", Username: "c", CreateAt: 20, Roles: model.SystemRoles(false)},
+			{ID: "user-b", Username: "b", CreateAt: 10, Roles: model.SystemRoles(false)},
+			{ID: "user-a", Username: "a", CreateAt: 10, Roles: model.SystemRoles(false)},
+		}
+		th.Store.EXPECT().GetAllUsers(model.UserQueryOptions{}).Return(users, nil)
+		// user-a and user-b were created in the same millisecond
+		th.Store.EXPECT().UpdateUserRoles("user-a", model.SystemRoles(true)).Return(nil)
+
+		require.NoError(t, th.App.EnsureSystemAdmins())
+	})
+
+	t.Run("nobody is promoted if there is a system admin", func(t *testing.T) {
+		users := []*model.User{
+			{ID: "user-a", Username: "a", CreateAt: 10, Roles: model.SystemRoles(false)},
+			{ID: "user-b", Username: "b", CreateAt: 20, Roles: model.SystemRoles(true)},
+		}
+		th.Store.EXPECT().GetAllUsers(model.UserQueryOptions{}).Return(users, nil)
+
+		require.NoError(t, th.App.EnsureSystemAdmins())
+	})
+}
+
+func TestNewUserRoles(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("the first user is a system admin", func(t *testing.T) {
+		th.Store.EXPECT().GetRegisteredUserCount().Return(0, nil)
+
+		roles, err := th.App.newUserRoles()
+		require.NoError(t, err)
+		require.Equal(t, model.SystemRoles(true), roles)
+	})
+
+	t.Run("the next users are not", func(t *testing.T) {
+		th.Store.EXPECT().GetRegisteredUserCount().Return(1, nil)
+
+		roles, err := th.App.newUserRoles()
+		require.NoError(t, err)
+		require.Equal(t, model.SystemRoles(false), roles)
+	})
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"strings"
+
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
 
@@ -44,10 +46,185 @@ func (a *App) GetTeam(id string) (*model.Team, error) {
 	return team, nil
 }
 
+// GetTeamsForUser returns the teams a user is a member of. In single user
+// mode there are no memberships, and the user belongs to every team.
 func (a *App) GetTeamsForUser(userID string) ([]*model.Team, error) {
+	if userID == model.SingleUser {
+		return a.store.GetAllTeams()
+	}
 	return a.store.GetTeamsForUser(userID)
 }
 
+// GetTeamBySignupToken returns the team a signup token lets users join.
+func (a *App) GetTeamBySignupToken(signupToken string) (*model.Team, error) {
+	return a.store.GetTeamBySignupToken(signupToken)
+}
+
+// CreateTeam creates a new team with its own signup token. The user
+// creating the team becomes its admin.
+func (a *App) CreateTeam(team *model.Team, userID string) (*model.Team, error) {
+	team.Title = strings.TrimSpace(team.Title)
+	if err := team.IsValid(); err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	team.ID = utils.NewID(utils.IDTypeTeam)
+	team.SignupToken = utils.NewID(utils.IDTypeToken)
+	team.ModifiedBy = userID
+	team.UpdateAt = now
+	if team.Settings == nil {
+		team.Settings = map[string]interface{}{}
+	}
+
+	if err := a.store.CreateTeam(team); err != nil {
+		return nil, err
+	}
+
+	member := &model.TeamMember{
+		TeamID:      team.ID,
+		UserID:      userID,
+		SchemeAdmin: true,
+		CreateAt:    now,
+	}
+	if err := a.store.SaveTeamMember(member); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (a *App) PatchTeam(teamID string, patch *model.TeamPatch, userID string) (*model.Team, error) {
+	team, err := a.store.GetTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	team = patch.Patch(team)
+	if err = team.IsValid(); err != nil {
+		return nil, err
+	}
+	team.ModifiedBy = userID
+	team.UpdateAt = utils.GetMillis()
+
+	if err = a.store.UpdateTeam(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam deletes a team and its memberships. The root team and the
+// teams that still have boards can't be deleted.
+func (a *App) DeleteTeam(teamID string) error {
+	if teamID == model.GlobalTeamID {
+		return model.NewErrBadRequest("the root team cannot be deleted")
+	}
+	if _, err := a.store.GetTeam(teamID); err != nil {
+		return err
+	}
+
+	boardCount, err := a.store.GetTeamBoardCount(teamID)
+	if err != nil {
+		return err
+	}
+	if boardCount > 0 {
+		return model.NewErrBadRequest("the boards of the team must be deleted or moved before deleting it")
+	}
+	return a.store.DeleteTeam(teamID)
+}
+
+// RegenerateTeamSignupToken replaces the signup token of a team, so the
+// previous invite links stop working. The teams of the boards created
+// before the memberships may not be stored yet, they are upserted.
+func (a *App) RegenerateTeamSignupToken(teamID string, userID string) error {
+	team, err := a.store.GetTeam(teamID)
+	if model.IsErrNotFound(err) {
+		team = &model.Team{ID: teamID}
+	} else if err != nil {
+		return err
+	}
+
+	team.SignupToken = utils.NewID(utils.IDTypeToken)
+	team.ModifiedBy = userID
+	return a.store.UpsertTeamSignupToken(*team)
+}
+
+func (a *App) GetTeamMembers(teamID string) ([]*model.TeamMember, error) {
+	return a.store.GetTeamMembers(teamID)
+}
+
+// AddTeamMember adds a user to a team, or changes its role if it is
+// already a member.
+func (a *App) AddTeamMember(teamID, userID string, schemeAdmin bool) (*model.TeamMember, error) {
+	if _, err := a.store.GetTeam(teamID); err != nil {
+		return nil, err
+	}
+	if _, err := a.store.GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	member, err := a.store.GetTeamMember(teamID, userID)
+	if model.IsErrNotFound(err) {
+		member = &model.TeamMember{
+			TeamID:   teamID,
+			UserID:   userID,
+			CreateAt: utils.GetMillis(),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	member.SchemeAdmin = schemeAdmin
+	if err = a.store.SaveTeamMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// EnsureTeamMember adds a user to a team if it is not a member yet,
+// keeping the role of the existing members.
+func (a *App) EnsureTeamMember(teamID, userID string) error {
+	_, err := a.store.GetTeamMember(teamID, userID)
+	if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	return a.store.SaveTeamMember(&model.TeamMember{
+		TeamID:   teamID,
+		UserID:   userID,
+		CreateAt: utils.GetMillis(),
+	})
+}
+
+func (a *App) UpdateTeamMember(teamID, userID string, schemeAdmin bool) (*model.TeamMember, error) {
+	member, err := a.store.GetTeamMember(teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.SchemeAdmin = schemeAdmin
+	if err = a.store.SaveTeamMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveTeamMember removes a user from a team. The last member can't be
+// removed, as the teams without members are open to every user.
+func (a *App) RemoveTeamMember(teamID, userID string) error {
+	if _, err := a.store.GetTeamMember(teamID, userID); err != nil {
+		return err
+	}
+
+	count, err := a.store.GetTeamMemberCount(teamID)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return model.NewErrBadRequest("the last member of a team can't be removed")
+	}
+	return a.store.DeleteTeamMember(teamID, userID)
+}
+
 func (a *App) DoesUserHaveTeamAccess(userID string, teamID string) bool {
 	return a.auth.DoesUserHaveTeamAccess(userID, teamID)
 }
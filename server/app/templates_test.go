@@ -50,6 +50,7 @@ func TestApp_initializeTemplates(t *testing.T) {
 		th.Store.EXPECT().GetBoard(board.ID).AnyTimes().Return(board, nil)
 		th.Store.EXPECT().GetMemberForBoard(gomock.Any(), gomock.Any()).AnyTimes().Return(boardMember, nil)
 		th.Store.EXPECT().SaveFileInfo(gomock.Any()).Return(nil).AnyTimes()
+		th.Store.EXPECT().SaveFileUsage(gomock.Any()).Return(nil).AnyTimes()
 
 		th.FilesBackend.On("WriteFile", mock.Anything, mock.Anything).Return(int64(1), nil)
 
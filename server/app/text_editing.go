@@ -0,0 +1,334 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	textEditHistorySize       = 200
+	textEditSnapshotInterval  = 30 * time.Second
+	textEditBroadcastInterval = 1 * time.Second
+	textEditSessionIdleTime   = 10 * time.Minute
+)
+
+// textEditSession holds the state of a text block that is being
+// collaboratively edited. Operations are applied in memory and the
+// resulting title is written to the block and broadcast right away, at
+// most once per textEditBroadcastInterval, but a history entry is only
+// recorded once per textEditSnapshotInterval.
+type textEditSession struct {
+	mu               sync.Mutex
+	block            *model.Block // the block as of the last snapshot
+	teamID           string
+	text             string
+	revision         int64
+	history          []*model.TextOperation // the operations that produced the latest revisions
+	modifiedBy       string
+	dirty            bool
+	closed           bool
+	broadcastPending bool
+	lastBroadcast    time.Time
+	lastSnapshot     time.Time
+	lastActivity     time.Time
+	timer            *time.Timer
+}
+
+// GetTextEditState returns the current text and revision of a text
+// block, which clients need to start sending operations.
+func (a *App) GetTextEditState(blockID string) (*model.TextEditState, error) {
+	session, err := a.lockTextEditSession(blockID)
+	if err != nil {
+		return nil, err
+	}
+	defer session.mu.Unlock()
+
+	return &model.TextEditState{
+		BlockID:  session.block.ID,
+		BoardID:  session.block.BoardID,
+		Revision: session.revision,
+		Text:     session.text,
+	}, nil
+}
+
+// ApplyTextOperation transforms an operation based on a given revision
+// of a text block against the operations applied after it, applies it
+// and persists the resulting title.
+func (a *App) ApplyTextOperation(blockID string, revision int64, op *model.TextOperation, modifiedByID string) (*model.TextChange, error) {
+	session, err := a.lockTextEditSession(blockID)
+	if err != nil {
+		return nil, err
+	}
+	defer session.mu.Unlock()
+
+	current, err := a.store.GetBlock(blockID)
+	if model.IsErrNotFound(err) {
+		a.closeTextEditSession(session)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Title != session.text {
+		// the block was modified outside of the collaborative
+		// session, so the operations based on previous revisions
+		// can't be applied anymore
+		session.text = current.Title
+		session.history = nil
+		session.revision++
+	}
+
+	if revision < 0 || revision > session.revision {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("invalid revision %d for block %s", revision, blockID))
+	}
+
+	behind := int(session.revision - revision)
+	if behind > len(session.history) {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("revision %d for block %s is no longer available", revision, blockID))
+	}
+
+	for _, concurrentOp := range session.history[len(session.history)-behind:] {
+		if op, _, err = model.TransformTextOperations(op, concurrentOp); err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+	}
+
+	text, err := op.Apply(session.text)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	if utf8.RuneCountInString(text) > model.BlockTitleMaxRunes {
+		return nil, model.NewErrBadRequest(model.ErrBlockTitleSizeLimitExceeded.Error())
+	}
+
+	if time.Since(session.lastSnapshot) >= textEditSnapshotInterval {
+		err = a.snapshotTextEditSession(session, text, modifiedByID)
+	} else {
+		err = a.store.UpdateBlockTitle(blockID, text, modifiedByID)
+		session.dirty = true
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.dirty {
+		a.scheduleTextEditBroadcast(session)
+	}
+
+	session.text = text
+	session.revision++
+	session.history = append(session.history, op)
+	if len(session.history) > textEditHistorySize {
+		session.history = session.history[len(session.history)-textEditHistorySize:]
+	}
+	session.modifiedBy = modifiedByID
+	session.lastActivity = time.Now()
+
+	if session.dirty {
+		session.timer.Reset(textEditSnapshotInterval - time.Since(session.lastSnapshot))
+	}
+
+	return &model.TextChange{
+		BlockID:    blockID,
+		BoardID:    session.block.BoardID,
+		Revision:   session.revision,
+		Operation:  op,
+		ModifiedBy: modifiedByID,
+	}, nil
+}
+
+// lockTextEditSession returns the locked edit session for a block,
+// creating it if it doesn't exist yet.
+func (a *App) lockTextEditSession(blockID string) (*textEditSession, error) {
+	for {
+		session, err := a.getTextEditSession(blockID)
+		if err != nil {
+			return nil, err
+		}
+
+		session.mu.Lock()
+		if !session.closed {
+			return session, nil
+		}
+		// the session was closed while we waited for the lock
+		session.mu.Unlock()
+	}
+}
+
+func (a *App) getTextEditSession(blockID string) (*textEditSession, error) {
+	a.textEditSessionsMux.Lock()
+	defer a.textEditSessionsMux.Unlock()
+
+	if session, ok := a.textEditSessions[blockID]; ok {
+		return session, nil
+	}
+
+	block, err := a.store.GetBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != model.TypeText {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("block %s is not a text block", blockID))
+	}
+
+	board, err := a.store.GetBoard(block.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &textEditSession{
+		block:         block,
+		teamID:        board.TeamID,
+		text:          block.Title,
+		lastBroadcast: now,
+		lastSnapshot:  now,
+		lastActivity:  now,
+	}
+	session.timer = time.AfterFunc(textEditSessionIdleTime, func() {
+		a.onTextEditSessionTimer(session)
+	})
+	a.textEditSessions[blockID] = session
+
+	return session, nil
+}
+
+// snapshotTextEditSession patches the block with the current text,
+// recording a history entry and notifying the change. The session
+// must be locked by the caller.
+func (a *App) snapshotTextEditSession(session *textEditSession, text, modifiedByID string) error {
+	blockID := session.block.ID
+	if err := a.store.PatchBlock(blockID, &model.BlockPatch{Title: &text}, modifiedByID); err != nil {
+		return err
+	}
+
+	block, err := a.store.GetBlock(blockID)
+	if err != nil {
+		return err
+	}
+
+	oldBlock := session.block
+	session.block = block
+	session.dirty = false
+	session.lastSnapshot = time.Now()
+	session.lastBroadcast = session.lastSnapshot
+
+	a.metrics.IncrementBlocksPatched(1)
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChange(session.teamID, block)
+		a.webhook.NotifyUpdate(block)
+		a.notifyBlockChanged(notify.Update, block, oldBlock, modifiedByID)
+		return nil
+	})
+	return nil
+}
+
+// scheduleTextEditBroadcast broadcasts the title written to the block
+// without a snapshot, so the clients that aren't editing the text are
+// updated too. The broadcasts are throttled to one per
+// textEditBroadcastInterval. The session must be locked by the caller.
+func (a *App) scheduleTextEditBroadcast(session *textEditSession) {
+	if session.broadcastPending {
+		return
+	}
+	session.broadcastPending = true
+
+	delay := textEditBroadcastInterval - time.Since(session.lastBroadcast)
+	if delay < 0 {
+		delay = 0
+	}
+	time.AfterFunc(delay, func() {
+		a.broadcastTextEditSession(session)
+	})
+}
+
+func (a *App) broadcastTextEditSession(session *textEditSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	session.broadcastPending = false
+	// the snapshots are broadcast when they are taken
+	if session.closed || !session.dirty {
+		return
+	}
+	session.lastBroadcast = time.Now()
+
+	// the block is read again, as other fields may have been patched
+	// since the last snapshot
+	block, err := a.store.GetBlock(session.block.ID)
+	if err != nil {
+		a.logger.Error("Error broadcasting text edit", mlog.String("blockID", session.block.ID), mlog.Err(err))
+		return
+	}
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockChange(session.teamID, block)
+		return nil
+	})
+}
+
+// onTextEditSessionTimer takes the pending snapshot of a session and
+// closes it once it has been idle for long enough.
+func (a *App) onTextEditSessionTimer(session *textEditSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed {
+		return
+	}
+
+	if session.dirty {
+		if err := a.snapshotTextEditSession(session, session.text, session.modifiedBy); err != nil {
+			a.logger.Error("Error saving text edit snapshot", mlog.String("blockID", session.block.ID), mlog.Err(err))
+		}
+	}
+
+	if !session.dirty && time.Since(session.lastActivity) >= textEditSessionIdleTime {
+		a.closeTextEditSession(session)
+		return
+	}
+
+	session.timer.Reset(textEditSessionIdleTime)
+}
+
+// closeTextEditSession removes a session so the next operation on the
+// block starts a new one. The session must be locked by the caller.
+func (a *App) closeTextEditSession(session *textEditSession) {
+	session.closed = true
+	session.timer.Stop()
+
+	a.textEditSessionsMux.Lock()
+	defer a.textEditSessionsMux.Unlock()
+	delete(a.textEditSessions, session.block.ID)
+}
+
+// closeTextEditSessions saves the pending snapshots and closes all the
+// sessions.
+func (a *App) closeTextEditSessions() {
+	a.textEditSessionsMux.Lock()
+	sessions := make([]*textEditSession, 0, len(a.textEditSessions))
+	for _, session := range a.textEditSessions {
+		sessions = append(sessions, session)
+	}
+	a.textEditSessionsMux.Unlock()
+
+	for _, session := range sessions {
+		session.mu.Lock()
+		if !session.closed {
+			if session.dirty {
+				if err := a.snapshotTextEditSession(session, session.text, session.modifiedBy); err != nil {
+					a.logger.Error("Error saving text edit snapshot", mlog.String("blockID", session.block.ID), mlog.Err(err))
+				}
+			}
+			a.closeTextEditSession(session)
+		}
+		session.mu.Unlock()
+	}
+}
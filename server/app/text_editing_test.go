@@ -0,0 +1,102 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestApplyTextOperation(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: testBoardID, TeamID: "team-id"}
+
+	t.Run("concurrent operations should be transformed", func(t *testing.T) {
+		block := &model.Block{ID: "text-block-1", BoardID: testBoardID, Type: model.TypeText, Title: "hello world"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().UpdateBlockTitle(block.ID, "hello world!", "user-id-1").Return(nil)
+
+		state, err := th.App.GetTextEditState(block.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), state.Revision)
+		require.Equal(t, "hello world", state.Text)
+
+		change, err := th.App.ApplyTextOperation(block.ID, 0, model.NewTextOperation().Retain(11).Insert("!"), "user-id-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), change.Revision)
+
+		// the second operation is based on the same revision as the first one
+		updated := &model.Block{ID: block.ID, BoardID: testBoardID, Type: model.TypeText, Title: "hello world!"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(updated, nil)
+		th.Store.EXPECT().UpdateBlockTitle(block.ID, ">hello world!", "user-id-2").Return(nil)
+
+		change, err = th.App.ApplyTextOperation(block.ID, 0, model.NewTextOperation().Insert(">").Retain(11), "user-id-2")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), change.Revision)
+		require.Equal(t, 12, change.Operation.BaseLength())
+
+		state, err = th.App.GetTextEditState(block.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), state.Revision)
+		require.Equal(t, ">hello world!", state.Text)
+
+		// closing the session saves the pending snapshot
+		th.Store.EXPECT().PatchBlock(block.ID, gomock.Any(), "user-id-2").Return(nil)
+		th.Store.EXPECT().GetBlock(block.ID).Return(updated, nil)
+		th.Store.EXPECT().GetMembersForBoard(testBoardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.App.closeTextEditSessions()
+	})
+
+	t.Run("edits between snapshots should broadcast the stored block", func(t *testing.T) {
+		block := &model.Block{ID: "text-block-4", BoardID: testBoardID, Type: model.TypeText, Title: "hello"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+		th.Store.EXPECT().UpdateBlockTitle(block.ID, "hello!", "user-id-1").Return(nil)
+
+		_, err := th.App.ApplyTextOperation(block.ID, 0, model.NewTextOperation().Retain(5).Insert("!"), "user-id-1")
+		require.NoError(t, err)
+
+		// the block was patched outside of the session, so the
+		// broadcast must not send it as of the last snapshot
+		patched := &model.Block{ID: block.ID, BoardID: testBoardID, Type: model.TypeText, Title: "hello!", ParentID: "card-id"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(patched, nil)
+		session, err := th.App.getTextEditSession(block.ID)
+		require.NoError(t, err)
+		th.App.broadcastTextEditSession(session)
+
+		th.Store.EXPECT().PatchBlock(block.ID, gomock.Any(), "user-id-1").Return(nil)
+		th.Store.EXPECT().GetBlock(block.ID).Return(patched, nil)
+		th.App.closeTextEditSessions()
+	})
+
+	t.Run("operations based on unknown revisions should fail", func(t *testing.T) {
+		block := &model.Block{ID: "text-block-2", BoardID: testBoardID, Type: model.TypeText, Title: "hello"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+
+		_, err := th.App.ApplyTextOperation(block.ID, 3, model.NewTextOperation().Retain(5).Insert("!"), "user-id-1")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("operations that don't match the text should fail", func(t *testing.T) {
+		block := &model.Block{ID: "text-block-3", BoardID: testBoardID, Type: model.TypeText, Title: "hello"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil).Times(2)
+		th.Store.EXPECT().GetBoard(testBoardID).Return(board, nil)
+
+		_, err := th.App.ApplyTextOperation(block.ID, 0, model.NewTextOperation().Retain(3).Insert("!"), "user-id-1")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("non text blocks can't be edited", func(t *testing.T) {
+		block := &model.Block{ID: "card-block", BoardID: testBoardID, Type: model.TypeCard, Title: "card"}
+		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil)
+
+		_, err := th.App.GetTextEditState(block.ID)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
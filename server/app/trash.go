@@ -0,0 +1,159 @@
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const trashPurgeBatchSize = 100
+
+// GetDeletedBoards returns the boards of a team that are in the trash.
+func (a *App) GetDeletedBoards(teamID string) ([]*model.Board, error) {
+	return a.store.GetDeletedBoards(model.QueryTrashOptions{TeamID: teamID})
+}
+
+// GetDeletedBoard returns the last version of a board that is in the
+// trash.
+func (a *App) GetDeletedBoard(boardID string) (*model.Board, error) {
+	board, err := a.getBoardHistory(boardID, true)
+	if err != nil {
+		return nil, err
+	}
+	if board == nil || board.DeleteAt == 0 {
+		return nil, model.NewErrNotFound("deleted board ID=" + boardID)
+	}
+	return board, nil
+}
+
+// GetDeletedCards returns the cards of a board that are in the trash.
+func (a *App) GetDeletedCards(boardID string) ([]*model.Block, error) {
+	return a.store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boardID, BlockType: model.TypeCard})
+}
+
+// RestoreDeletedBoards restores a set of boards of a team from the
+// trash. All the boards must be in the trash of the team, otherwise
+// none of them is restored.
+func (a *App) RestoreDeletedBoards(teamID string, boardIDs []string, modifiedBy string) ([]*model.Board, error) {
+	deleted, err := a.GetDeletedBoards(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTrashIDs(boardIDs, extractBoardIDs(deleted)); err != nil {
+		return nil, err
+	}
+
+	boards := make([]*model.Board, 0, len(boardIDs))
+	for _, boardID := range boardIDs {
+		if err := a.UndeleteBoard(boardID, modifiedBy); err != nil {
+			return nil, err
+		}
+
+		board, err := a.store.GetBoard(boardID)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+// RestoreDeletedBlocks restores a set of blocks of a board from the
+// trash. All the blocks must be in the trash of the board, otherwise
+// none of them is restored.
+func (a *App) RestoreDeletedBlocks(boardID string, blockIDs []string, modifiedBy string) ([]*model.Block, error) {
+	deleted, err := a.store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boardID})
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTrashIDs(blockIDs, extractBlockIDs(deleted)); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*model.Block, 0, len(blockIDs))
+	for _, blockID := range blockIDs {
+		block, err := a.UndeleteBlock(blockID, modifiedBy)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// PurgeDeletedBoard permanently removes a board of a team from the trash.
+func (a *App) PurgeDeletedBoard(teamID, boardID string) error {
+	deleted, err := a.GetDeletedBoards(teamID)
+	if err != nil {
+		return err
+	}
+	if err = checkTrashIDs([]string{boardID}, extractBoardIDs(deleted)); err != nil {
+		return err
+	}
+
+	return a.store.PurgeDeletedBoard(boardID)
+}
+
+// PurgeDeletedBlock permanently removes a block of a board, and its
+// deleted children, from the trash.
+func (a *App) PurgeDeletedBlock(boardID, blockID string) error {
+	deleted, err := a.store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boardID})
+	if err != nil {
+		return err
+	}
+	if err = checkTrashIDs([]string{blockID}, extractBlockIDs(deleted)); err != nil {
+		return err
+	}
+
+	return a.store.PurgeDeletedBlock(blockID)
+}
+
+// PurgeExpiredTrash permanently removes the boards and blocks that have
+// been in the trash for longer than the configured retention period.
+func (a *App) PurgeExpiredTrash() (int64, error) {
+	if a.config.TrashRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	deletedBefore := utils.GetMillisForTime(time.Now().AddDate(0, 0, -a.config.TrashRetentionDays))
+	affected, err := a.store.PurgeTrash(deletedBefore, trashPurgeBatchSize)
+	if err != nil {
+		return affected, err
+	}
+
+	a.logger.Debug("PurgeExpiredTrash",
+		mlog.Int("retentionDays", a.config.TrashRetentionDays),
+		mlog.Int("affected", affected),
+	)
+	return affected, nil
+}
+
+func checkTrashIDs(ids []string, deletedIDs map[string]bool) error {
+	for _, id := range ids {
+		if !deletedIDs[id] {
+			return model.NewErrNotFound("deleted item ID=" + id)
+		}
+	}
+	return nil
+}
+
+func extractBoardIDs(boards []*model.Board) map[string]bool {
+	ids := make(map[string]bool, len(boards))
+	for _, board := range boards {
+		ids[board.ID] = true
+	}
+	return ids
+}
+
+func extractBlockIDs(blocks []*model.Block) map[string]bool {
+	ids := make(map[string]bool, len(blocks))
+	for _, block := range blocks {
+		ids[block.ID] = true
+	}
+	return ids
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// auditLogMaxLineSize is the size of the longest audit record read from
+// the audit log files.
+const auditLogMaxLineSize = 1024 * 1024
+
+// auditLogFile is an audit log file of the server, with the key its JSON
+// records group their fields under, if any.
+type auditLogFile struct {
+	path      string
+	groupKey  string
+	targetKey string
+}
+
+// getUserAuditEntries returns the audit records of the actions of a user.
+// The records can only be read back from the audit log file targets in
+// JSON, the records sent to the other targets aren't returned.
+func (a *App) getUserAuditEntries(userID string) ([]map[string]interface{}, error) {
+	files, err := a.getAuditLogFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []map[string]interface{}{}
+	for _, file := range files {
+		fileEntries, err := readUserAuditEntries(file, userID)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read audit log target %s: %w", file.targetKey, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// getAuditLogFiles returns the audit log files configured, the targets of
+// the configuration file taking precedence over the JSON configuration,
+// as when the audit service is configured.
+func (a *App) getAuditLogFiles() ([]auditLogFile, error) {
+	cfg := mlog.LoggerConfiguration{}
+	if a.config.AuditCfgJSON != "" {
+		if err := json.Unmarshal([]byte(a.config.AuditCfgJSON), &cfg); err != nil {
+			return nil, fmt.Errorf("invalid audit log configuration: %w", err)
+		}
+	}
+	if a.config.AuditCfgFile != "" {
+		data, err := os.ReadFile(a.config.AuditCfgFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the audit log configuration: %w", err)
+		}
+		fileCfg := mlog.LoggerConfiguration{}
+		if err := json.Unmarshal(data, &fileCfg); err != nil {
+			return nil, fmt.Errorf("invalid audit log configuration file: %w", err)
+		}
+		cfg.Append(fileCfg)
+	}
+
+	files := []auditLogFile{}
+	paths := map[string]bool{}
+	for key, target := range cfg {
+		if target.Type != "file" || target.Format != "json" {
+			continue
+		}
+
+		var options struct {
+			Filename string `json:"filename"`
+		}
+		if err := json.Unmarshal(target.Options, &options); err != nil || options.Filename == "" || paths[options.Filename] {
+			continue
+		}
+		paths[options.Filename] = true
+		var formatOptions struct {
+			KeyGroupFields string `json:"key_group_fields"`
+		}
+		if len(target.FormatOptions) > 0 {
+			_ = json.Unmarshal(target.FormatOptions, &formatOptions)
+		}
+
+		files = append(files, auditLogFile{
+			path:      options.Filename,
+			groupKey:  formatOptions.KeyGroupFields,
+			targetKey: key,
+		})
+	}
+	return files, nil
+}
+
+// readUserAuditEntries reads the records of a user from an audit log
+// file. The lines that aren't JSON records are skipped.
+func readUserAuditEntries(file auditLogFile, userID string) ([]map[string]interface{}, error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []map[string]interface{}{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), auditLogMaxLineSize)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		fields := entry
+		if file.groupKey != "" {
+			fields, _ = entry[file.groupKey].(map[string]interface{})
+		}
+		if fields != nil && fields[audit.KeyUserID] == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,325 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetUserDataExport gathers the personal data of a user: its profile and
+// preferences, board memberships, the cards it created or is assigned
+// to, its comments, uploaded files, subscriptions, sessions and audit
+// entries.
+func (a *App) GetUserDataExport(userID string) (*model.UserDataExport, error) {
+	user, err := a.store.GetUserByIDIncludingDeactivated(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &model.UserDataExport{
+		Version: model.UserDataExportVersion,
+		Date:    utils.GetMillis(),
+		Profile: &model.UserDataProfile{User: user, Email: user.Email},
+	}
+
+	if export.Preferences, err = a.store.GetUserPreferences(userID); err != nil {
+		return nil, fmt.Errorf("cannot get the preferences: %w", err)
+	}
+	if export.BoardMemberships, err = a.store.GetMembersForUser(userID); err != nil {
+		return nil, fmt.Errorf("cannot get the board memberships: %w", err)
+	}
+	if export.BoardMembershipHistory, err = a.store.GetBoardMemberHistoryForUser(userID); err != nil {
+		return nil, fmt.Errorf("cannot get the board membership history: %w", err)
+	}
+	if export.Subscriptions, err = a.store.GetSubscriptions(userID); err != nil {
+		return nil, fmt.Errorf("cannot get the subscriptions: %w", err)
+	}
+
+	export.Sessions = []*model.UserDataSession{}
+	sessions, err := a.store.GetSessionsForUser(userID)
+	var nse store.NotSupportedError
+	if err != nil && !errors.As(err, &nse) {
+		return nil, fmt.Errorf("cannot get the sessions: %w", err)
+	}
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, model.NewUserDataSession(session))
+	}
+
+	if export.AuditEntries, err = a.getUserAuditEntries(userID); err != nil {
+		return nil, fmt.Errorf("cannot get the audit entries: %w", err)
+	}
+
+	if err := a.addUserDataBlocks(export, userID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// addUserDataBlocks sorts the blocks of a user into the cards it created
+// or is assigned to, its comments and its files.
+func (a *App) addUserDataBlocks(export *model.UserDataExport, userID string) error {
+	blocks, err := a.store.GetBlocksForUser(userID)
+	if err != nil {
+		return fmt.Errorf("cannot get the blocks: %w", err)
+	}
+
+	export.CreatedCards = []*model.Block{}
+	export.AssignedCards = []*model.Block{}
+	export.Comments = []*model.Block{}
+	export.Files = []*model.UserDataFile{}
+
+	boardIDs := []string{}
+	cardsByBoard := map[string][]*model.Block{}
+	for _, block := range blocks {
+		switch block.Type {
+		case model.TypeCard:
+			if _, ok := cardsByBoard[block.BoardID]; !ok {
+				boardIDs = append(boardIDs, block.BoardID)
+			}
+			cardsByBoard[block.BoardID] = append(cardsByBoard[block.BoardID], block)
+		case model.TypeComment:
+			if block.CreatedBy == userID {
+				export.Comments = append(export.Comments, block)
+			}
+		case model.TypeImage, model.TypeAttachment:
+			if block.CreatedBy != userID {
+				continue
+			}
+			filename, err := extractFilename(block)
+			if err != nil {
+				continue
+			}
+			file := &model.UserDataFile{
+				BlockID:  block.ID,
+				BoardID:  block.BoardID,
+				FileID:   filename,
+				Name:     filename,
+				CreateAt: block.CreateAt,
+			}
+			if fileInfo, err := a.GetFileInfo(filename); err == nil {
+				file.Name = fileInfo.Name
+				file.Size = fileInfo.Size
+				file.MimeType = fileInfo.MimeType
+			}
+			export.Files = append(export.Files, file)
+		}
+	}
+
+	// the cards are exported as the user sees them, without the cards of
+	// the boards it can't access anymore, the private cards it can't see
+	// and the values of the restricted properties it can't view
+	for _, boardID := range boardIDs {
+		board, err := a.store.GetBoard(boardID)
+		if model.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+			continue
+		}
+
+		cards, err := a.FilterBlocksForUser(board, userID, cardsByBoard[boardID])
+		if err != nil {
+			return err
+		}
+		for _, card := range cards {
+			if card.CreatedBy == userID {
+				export.CreatedCards = append(export.CreatedCards, card)
+			}
+			if isCardAssignedTo(board, card, userID) {
+				export.AssignedCards = append(export.AssignedCards, card)
+			}
+		}
+	}
+	return nil
+}
+
+// isCardAssignedTo returns true if the user is set in a person property
+// of the card.
+func isCardAssignedTo(board *model.Board, card *model.Block, userID string) bool {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	for _, property := range board.CardProperties {
+		if property["type"] != "person" && property["type"] != "multiPerson" {
+			continue
+		}
+		id, _ := property["id"].(string)
+		switch value := properties[id].(type) {
+		case string:
+			if value == userID {
+				return true
+			}
+		case []interface{}:
+			for _, v := range value {
+				if v == userID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// WriteUserDataExport writes the personal data of a user to a zip
+// archive, with the data in machine-readable JSON, a human-readable
+// summary and the files the user uploaded.
+func (a *App) WriteUserDataExport(w io.Writer, export *model.UserDataExport) (errs error) {
+	merr := merror.New()
+	defer func() {
+		errs = merr.ErrorOrNil()
+	}()
+
+	zw := zip.NewWriter(w)
+	defer func() {
+		if err := zw.Close(); err != nil {
+			merr.Append(err)
+		}
+	}()
+
+	for _, file := range export.Files {
+		if err := a.writeUserDataFile(zw, file); err != nil {
+			merr.Append(fmt.Errorf("cannot write file %s to user data export: %w", file.FileID, err))
+			return
+		}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		merr.Append(err)
+		return
+	}
+	f, err := zw.Create("user.json")
+	if err != nil {
+		merr.Append(err)
+		return
+	}
+	if _, err := f.Write(data); err != nil {
+		merr.Append(err)
+		return
+	}
+
+	f, err = zw.Create("summary.txt")
+	if err != nil {
+		merr.Append(err)
+		return
+	}
+	if err := writeUserDataSummary(f, export); err != nil {
+		merr.Append(err)
+	}
+	return nil
+}
+
+// writeUserDataFile copies a file uploaded by the user to the archive,
+// setting its path in the archive.
+func (a *App) writeUserDataFile(zw *zip.Writer, file *model.UserDataFile) error {
+	board, err := a.store.GetBoard(file.BoardID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+	teamID := model.GlobalTeamID
+	if board != nil {
+		teamID = board.TeamID
+	}
+
+	_, fileReader, err := a.GetFile(teamID, file.BoardID, file.FileID)
+	if err != nil {
+		// the file is still listed in the export, without its content
+		a.logger.Warn("file missing for user data export",
+			mlog.String("filename", file.FileID),
+			mlog.String("board_id", file.BoardID),
+			mlog.Err(err),
+		)
+		return nil
+	}
+	defer fileReader.Close()
+
+	file.Path = path.Join(model.UserDataExportFilesDir, file.FileID)
+	dest, err := zw.Create(file.Path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dest, fileReader)
+	return err
+}
+
+// writeUserDataSummary writes a human-readable summary of the personal
+// data of a user.
+func writeUserDataSummary(w io.Writer, export *model.UserDataExport) error {
+	formatTime := func(millis int64) string {
+		if millis == 0 {
+			return "-"
+		}
+		return utils.GetTimeForMillis(millis).UTC().Format(time.RFC3339)
+	}
+
+	profile := export.Profile
+	lines := []string{
+		"Personal data export",
+		"Exported at: " + formatTime(export.Date),
+		"",
+		"Profile",
+		"  ID: " + profile.ID,
+		"  Username: " + profile.Username,
+		"  Email: " + profile.Email,
+		"  Name: " + profile.FirstName + " " + profile.LastName,
+		"  Nickname: " + profile.Nickname,
+		"  Created at: " + formatTime(profile.CreateAt),
+		"  Deactivated at: " + formatTime(profile.DeleteAt),
+		"",
+		fmt.Sprintf("Preferences: %d", len(export.Preferences)),
+		fmt.Sprintf("Board memberships: %d", len(export.BoardMemberships)),
+		fmt.Sprintf("Board membership history entries: %d", len(export.BoardMembershipHistory)),
+		fmt.Sprintf("Cards created: %d", len(export.CreatedCards)),
+		fmt.Sprintf("Cards assigned: %d", len(export.AssignedCards)),
+		fmt.Sprintf("Comments: %d", len(export.Comments)),
+		fmt.Sprintf("Files uploaded: %d", len(export.Files)),
+		fmt.Sprintf("Subscriptions: %d", len(export.Subscriptions)),
+		fmt.Sprintf("Sessions: %d", len(export.Sessions)),
+		fmt.Sprintf("Audit entries: %d", len(export.AuditEntries)),
+		"",
+	}
+
+	if len(export.Files) > 0 {
+		lines = append(lines, "Files")
+		for _, file := range export.Files {
+			location := file.Path
+			if location == "" {
+				location = "missing from the file store"
+			}
+			lines = append(lines, fmt.Sprintf("  %s (%d bytes, %s): %s", file.Name, file.Size, formatTime(file.CreateAt), location))
+		}
+		lines = append(lines, "")
+	}
+
+	if len(export.Sessions) > 0 {
+		lines = append(lines, "Sessions")
+		for _, session := range export.Sessions {
+			lines = append(lines, fmt.Sprintf("  logged in at %s, last active at %s", formatTime(session.CreateAt), formatTime(session.UpdateAt)))
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines,
+		"The full data is in user.json, and the uploaded files in the "+model.UserDataExportFilesDir+" directory.",
+		"The audit entries are read from the JSON audit log files of the server, the records sent to the other audit log targets aren't part of this export.",
+	)
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *App) GetUserGroup(id string) (*model.UserGroup, error) {
+	return a.store.GetUserGroup(id)
+}
+
+func (a *App) GetUserGroupsForTeam(teamID string) ([]*model.UserGroup, error) {
+	return a.store.GetUserGroupsForTeam(teamID)
+}
+
+func (a *App) GetUserGroupMemberIDs(groupID string) ([]string, error) {
+	return a.store.GetUserGroupMemberIDs(groupID)
+}
+
+// GetUserGroupMembersByName returns the members of the group of a team
+// with the given name, used to expand the @mentions of the group.
+func (a *App) GetUserGroupMembersByName(teamID, name string) ([]*model.User, error) {
+	group, err := a.store.GetUserGroupByName(teamID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs, err := a.store.GetUserGroupMemberIDs(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return []*model.User{}, nil
+	}
+	return a.store.GetUsersList(userIDs, false, false)
+}
+
+// CreateUserGroup creates a new user group in a team.
+func (a *App) CreateUserGroup(group *model.UserGroup, userID string) (*model.UserGroup, error) {
+	group.Name = strings.ToLower(strings.TrimSpace(group.Name))
+	if err := a.checkUserGroupName(group); err != nil {
+		return nil, err
+	}
+
+	now := utils.GetMillis()
+	group.ID = utils.NewID(utils.IDTypeNone)
+	group.CreatedBy = userID
+	group.CreateAt = now
+	group.UpdateAt = now
+
+	if err := a.store.CreateUserGroup(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// PatchUserGroup updates the name and the description of a user group.
+func (a *App) PatchUserGroup(group *model.UserGroup, patch *model.UserGroupPatch) (*model.UserGroup, error) {
+	group = patch.Patch(group)
+	if err := a.checkUserGroupName(group); err != nil {
+		return nil, err
+	}
+	group.UpdateAt = utils.GetMillis()
+
+	if err := a.store.UpdateUserGroup(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteUserGroup deletes a user group, removing from the boards the
+// members that the group added to them.
+func (a *App) DeleteUserGroup(group *model.UserGroup) error {
+	boardGroups, err := a.store.GetBoardGroupsForGroup(group.ID)
+	if err != nil {
+		return err
+	}
+
+	userIDs, err := a.store.GetUserGroupMemberIDs(group.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.DeleteUserGroup(group.ID); err != nil {
+		return err
+	}
+
+	for _, boardGroup := range boardGroups {
+		for _, userID := range userIDs {
+			a.removeGroupMemberFromBoard(boardGroup.BoardID, group.ID, userID)
+		}
+	}
+	return nil
+}
+
+// checkUserGroupName checks that the name of a group is valid and that
+// it is not used by another group of the team nor by a user, as both
+// share the @mentions.
+func (a *App) checkUserGroupName(group *model.UserGroup) error {
+	if err := group.IsValid(); err != nil {
+		return err
+	}
+
+	existing, err := a.store.GetUserGroupByName(group.TeamID, group.Name)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.ID != group.ID {
+		return model.NewErrBadRequest("a user group named " + group.Name + " already exists in the team")
+	}
+
+	user, err := a.store.GetUserByUsername(group.Name)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+	if user != nil {
+		return model.NewErrBadRequest("the user group name " + group.Name + " is used by a user")
+	}
+	return nil
+}
+
+// AddUserGroupMember adds a member of the team to a user group, and to
+// the boards the group is linked to.
+func (a *App) AddUserGroupMember(group *model.UserGroup, userID string) error {
+	if !a.permissions.HasPermissionToTeam(userID, group.TeamID, model.PermissionViewTeam) {
+		return model.NewErrBadRequest("the user group members must be members of the team")
+	}
+
+	userIDs, err := a.store.GetUserGroupMemberIDs(group.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if id == userID {
+			return nil
+		}
+	}
+
+	if err = a.store.AddUserGroupMember(group.ID, userID, utils.GetMillis()); err != nil {
+		return err
+	}
+
+	boardGroups, err := a.store.GetBoardGroupsForGroup(group.ID)
+	if err != nil {
+		return err
+	}
+	for _, boardGroup := range boardGroups {
+		if err := a.addGroupMemberToBoard(boardGroup, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteUserGroupMember removes a member from a user group, and from
+// the boards the group added it to.
+func (a *App) DeleteUserGroupMember(group *model.UserGroup, userID string) error {
+	if err := a.store.DeleteUserGroupMember(group.ID, userID); err != nil {
+		return err
+	}
+
+	boardGroups, err := a.store.GetBoardGroupsForGroup(group.ID)
+	if err != nil {
+		return err
+	}
+	for _, boardGroup := range boardGroups {
+		a.removeGroupMemberFromBoard(boardGroup.BoardID, group.ID, userID)
+	}
+	return nil
+}
+
+func (a *App) GetBoardGroupsForBoard(boardID string) ([]*model.BoardGroup, error) {
+	return a.store.GetBoardGroupsForBoard(boardID)
+}
+
+// AddUserGroupToBoard links a user group to a board, adding its members
+// to the board with the roles of the link. The users that already are
+// members of the board keep their membership.
+func (a *App) AddUserGroupToBoard(boardGroup *model.BoardGroup) (*model.BoardGroup, error) {
+	board, err := a.store.GetBoard(boardGroup.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := a.store.GetUserGroup(boardGroup.GroupID)
+	if model.IsErrNotFound(err) {
+		return nil, model.NewErrBadRequest("user group not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if group.TeamID != board.TeamID {
+		return nil, model.NewErrBadRequest("user group must be defined in the team of the board")
+	}
+
+	if err = a.checkCustomBoardRole(board, boardGroup.CustomRoleID); err != nil {
+		return nil, err
+	}
+
+	boardGroup.CreateAt = utils.GetMillis()
+	if err = a.store.SaveBoardGroup(boardGroup); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := a.store.GetUserGroupMemberIDs(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		if err := a.addGroupMemberToBoard(boardGroup, userID); err != nil {
+			return nil, err
+		}
+	}
+	return boardGroup, nil
+}
+
+// RemoveUserGroupFromBoard unlinks a user group from a board, removing
+// the members that the group added to it.
+func (a *App) RemoveUserGroupFromBoard(boardID, groupID string) error {
+	if err := a.store.DeleteBoardGroup(boardID, groupID); err != nil {
+		return err
+	}
+
+	userIDs, err := a.store.GetUserGroupMemberIDs(groupID)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		a.removeGroupMemberFromBoard(boardID, groupID, userID)
+	}
+	return nil
+}
+
+// groupMemberFor returns the board membership that a board link gives
+// to a member of the group. The guests can't be board admins.
+func (a *App) groupMemberFor(boardGroup *model.BoardGroup, userID string) (*model.BoardMember, error) {
+	member := boardGroup.MemberFor(userID)
+
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsGuest && member.SchemeAdmin {
+		member.SchemeAdmin = false
+		member.SchemeEditor = true
+	}
+	return member, nil
+}
+
+// addGroupMemberToBoard adds a member of a group to a board linked to
+// the group.
+func (a *App) addGroupMemberToBoard(boardGroup *model.BoardGroup, userID string) error {
+	member, err := a.groupMemberFor(boardGroup, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.AddMemberToBoard(member)
+	return err
+}
+
+// removeGroupMemberFromBoard removes from a board a user that a group
+// added to it. If another group linked to the board contains the user,
+// the membership is moved to that group instead. The users added
+// directly to the board, and the last admin of the board, are kept.
+func (a *App) removeGroupMemberFromBoard(boardID, groupID, userID string) {
+	member, err := a.store.GetMemberForBoard(boardID, userID)
+	if err != nil || member.GroupID != groupID {
+		return
+	}
+
+	boardGroups, err := a.store.GetBoardGroupsForBoard(boardID)
+	if err != nil {
+		a.logger.Error("cannot get the groups of the board", mlog.String("boardID", boardID), mlog.Err(err))
+		return
+	}
+	for _, boardGroup := range boardGroups {
+		if boardGroup.GroupID == groupID {
+			continue
+		}
+		userIDs, err := a.store.GetUserGroupMemberIDs(boardGroup.GroupID)
+		if err != nil {
+			a.logger.Error("cannot get the members of the group", mlog.String("groupID", boardGroup.GroupID), mlog.Err(err))
+			return
+		}
+		for _, id := range userIDs {
+			if id != userID {
+				continue
+			}
+			newMember, err := a.groupMemberFor(boardGroup, userID)
+			if err == nil {
+				_, err = a.UpdateBoardMember(newMember)
+			}
+			if err != nil {
+				a.logger.Error("cannot move the board member to another group",
+					mlog.String("boardID", boardID),
+					mlog.String("userID", userID),
+					mlog.Err(err),
+				)
+			}
+			return
+		}
+	}
+
+	err = a.DeleteBoardMember(boardID, userID)
+	if errors.Is(err, model.ErrBoardMemberIsLastAdmin) {
+		a.logger.Warn("keeping the last admin of the board added by a group",
+			mlog.String("boardID", boardID),
+			mlog.String("userID", userID),
+		)
+		return
+	}
+	if err != nil {
+		a.logger.Error("cannot remove the board member added by a group",
+			mlog.String("boardID", boardID),
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+	}
+}
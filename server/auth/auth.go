@@ -13,6 +13,7 @@ import (
 type AuthInterface interface {
 	GetSession(token string) (*model.Session, error)
 	IsValidReadToken(boardID string, readToken string) (bool, error)
+	GetShareLinkForReadToken(boardID string, readToken string) (*model.ShareLink, error)
 	DoesUserHaveTeamAccess(userID string, teamID string) bool
 }
 
@@ -38,6 +39,12 @@ func (a *Auth) GetSession(token string) (*model.Session, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get the session for the token")
 	}
+
+	// the sessions of the expired guests are not valid anymore
+	if session.IsExpiredGuest() {
+		return nil, errors.New("guest account expired")
+	}
+
 	if session.UpdateAt < (utils.GetMillis() - utils.SecondsToMillis(a.config.SessionRefreshTime)) {
 		_ = a.store.RefreshSession(session)
 	}
@@ -47,22 +54,48 @@ func (a *Auth) GetSession(token string) (*model.Session, error) {
 // IsValidReadToken validates the read token for a board.
 func (a *Auth) IsValidReadToken(boardID string, readToken string) (bool, error) {
 	sharing, err := a.store.GetSharing(boardID)
-	if model.IsErrNotFound(err) {
-		return false, nil
+	if err != nil && !model.IsErrNotFound(err) {
+		return false, err
+	}
+
+	if sharing != nil {
+		if !a.config.EnablePublicSharedBoards {
+			return false, errors.New("public shared boards disabled")
+		}
+
+		if sharing.ID == boardID && sharing.Enabled && sharing.Token == readToken {
+			return true, nil
+		}
 	}
+
+	// share links restricted to a view or hiding properties don't grant
+	// access to the whole board
+	link, err := a.GetShareLinkForReadToken(boardID, readToken)
 	if err != nil {
 		return false, err
 	}
+	return link != nil && !link.IsScoped(), nil
+}
 
-	if !a.config.EnablePublicSharedBoards {
-		return false, errors.New("public shared boards disabled")
+// GetShareLinkForReadToken returns the share link of a board opened by a
+// read token, or nil if the token doesn't open any valid link.
+func (a *Auth) GetShareLinkForReadToken(boardID string, readToken string) (*model.ShareLink, error) {
+	if !a.config.EnablePublicSharedBoards || readToken == "" {
+		return nil, nil
 	}
 
-	if sharing != nil && (sharing.ID == boardID && sharing.Enabled && sharing.Token == readToken) {
-		return true, nil
+	link, err := a.store.GetShareLinkByToken(model.ShareLinkTokenFromReadToken(readToken))
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return false, nil
+	if link.BoardID != boardID || link.IsExpired(utils.GetMillis()) || !link.CheckReadToken(readToken) {
+		return nil, nil
+	}
+	return link, nil
 }
 
 func (a *Auth) DoesUserHaveTeamAccess(userID string, teamID string) bool {
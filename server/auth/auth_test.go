@@ -63,11 +63,13 @@ func TestGetSession(t *testing.T) {
 	}{
 		{"fail, no token", "", 0, true},
 		{"fail, invalid username", "badToken", 0, true},
+		{"fail, expired guest", "guestToken", 0, true},
 		{"success, good token", "goodToken", 1000, false},
 	}
 
 	th.Store.EXPECT().GetSession("badToken", gomock.Any()).Return(nil, errors.New("Invalid Token"))
 	th.Store.EXPECT().GetSession("goodToken", gomock.Any()).Return(mockSession, nil)
+	th.Store.EXPECT().GetSession("guestToken", gomock.Any()).Return(&model.Session{Token: "guestToken", UserID: "guest-id", IsGuest: true, GuestExpireAt: 1}, nil)
 	th.Store.EXPECT().RefreshSession(gomock.Any()).Return(nil)
 
 	for _, test := range testcases {
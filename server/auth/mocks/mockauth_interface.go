@@ -63,6 +63,21 @@ func (mr *MockAuthInterfaceMockRecorder) GetSession(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockAuthInterface)(nil).GetSession), arg0)
 }
 
+// GetShareLinkForReadToken mocks base method.
+func (m *MockAuthInterface) GetShareLinkForReadToken(arg0, arg1 string) (*model.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLinkForReadToken", arg0, arg1)
+	ret0, _ := ret[0].(*model.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLinkForReadToken indicates an expected call of GetShareLinkForReadToken.
+func (mr *MockAuthInterfaceMockRecorder) GetShareLinkForReadToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLinkForReadToken", reflect.TypeOf((*MockAuthInterface)(nil).GetShareLinkForReadToken), arg0, arg1)
+}
+
 // IsValidReadToken mocks base method.
 func (m *MockAuthInterface) IsValidReadToken(arg0, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
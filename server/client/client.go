@@ -7,6 +7,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/api"
@@ -215,6 +217,96 @@ func (c *Client) GetTeam(teamID string) (*model.Team, *Response) {
 	return model.TeamFromJSON(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetTeams() ([]*model.Team, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamsRoute(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) CreateTeam(team *model.Team) (*model.Team, *Response) {
+	r, err := c.DoAPIPost(c.GetTeamsRoute(), toJSON(team))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PatchTeam(teamID string, patch *model.TeamPatch) (*model.Team, *Response) {
+	r, err := c.DoAPIPatch(c.GetTeamRoute(teamID), toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteTeam(teamID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTeamRoute(teamID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RegenerateTeamSignupToken(teamID string) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetTeamRoute(teamID)+"/regenerate_signup_token", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetTeamMembers(teamID string) ([]*model.TeamMember, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/members", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamMembersFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) AddTeamMember(member *model.TeamMember) (*model.TeamMember, *Response) {
+	r, err := c.DoAPIPost(c.GetTeamRoute(member.TeamID)+"/members", toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UpdateTeamMember(member *model.TeamMember) (*model.TeamMember, *Response) {
+	r, err := c.DoAPIPut(c.GetTeamRoute(member.TeamID)+"/members/"+member.UserID, toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.TeamMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RemoveTeamMember(teamID, userID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTeamRoute(teamID)+"/members/"+userID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
 func (c *Client) GetBlocksForBoard(boardID string) ([]*model.Block, *Response) {
 	r, err := c.DoAPIGet(c.GetBlocksRoute(boardID), "")
 	if err != nil {
@@ -235,6 +327,16 @@ func (c *Client) GetAllBlocksForBoard(boardID string) ([]*model.Block, *Response
 	return model.BlocksFromJSON(r.Body), BuildResponse(r)
 }
 
+func (c *Client) GetAllBlocksForBoardWithReadToken(boardID, readToken string) ([]*model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetAllBlocksRoute(boardID)+"&read_token="+url.QueryEscape(readToken), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
 const disableNotifyQueryParam = "disable_notify=true"
 
 func (c *Client) PatchBlock(boardID, blockID string, blockPatch *model.BlockPatch, disableNotify bool) (bool, *Response) {
@@ -523,12 +625,25 @@ func (c *Client) PostSharing(sharing *model.Sharing) (bool, *Response) {
 	return true, BuildResponse(r)
 }
 
-func (c *Client) GetRegisterRoute() string {
-	return "/register"
+// System administration
+
+func (c *Client) GetAdminUsers(page, perPage int, includeDeactivated bool) ([]*model.User, *Response) {
+	route := fmt.Sprintf("/admin/users?page=%d&per_page=%d&include_deactivated=%t", page, perPage, includeDeactivated)
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var users []*model.User
+	if err := json.NewDecoder(r.Body).Decode(&users); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return users, BuildResponse(r)
 }
 
-func (c *Client) Register(request *model.RegisterRequest) (bool, *Response) {
-	r, err := c.DoAPIPost(c.GetRegisterRoute(), toJSON(&request))
+func (c *Client) AdminSetUserPassword(username, password string) (bool, *Response) {
+	r, err := c.DoAPIPost("/admin/users/"+username+"/password", toJSON(&api.AdminSetPasswordData{Password: password}))
 	if err != nil {
 		return false, BuildErrorResponse(r, err)
 	}
@@ -537,129 +652,140 @@ func (c *Client) Register(request *model.RegisterRequest) (bool, *Response) {
 	return true, BuildResponse(r)
 }
 
-func (c *Client) GetLoginRoute() string {
-	return "/login"
-}
-
-func (c *Client) Login(request *model.LoginRequest) (*model.LoginResponse, *Response) {
-	r, err := c.DoAPIPost(c.GetLoginRoute(), toJSON(&request))
+func (c *Client) AdminUpdateUserActive(userID string, active bool) (bool, *Response) {
+	r, err := c.DoAPIPut("/admin/users/"+userID+"/active", toJSON(&model.AdminUserActivePatch{Active: active}))
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	data, err := model.LoginResponseFromJSON(r.Body)
+	return true, BuildResponse(r)
+}
+
+func (c *Client) AdminDeleteUser(userID string) (*model.UserDeletionReport, *Response) {
+	r, err := c.DoAPIDelete("/admin/users/"+userID, "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
+	defer closeBody(r)
 
-	if data.Token != "" {
-		c.Token = data.Token
-	}
-
-	return data, BuildResponse(r)
-}
-
-func (c *Client) GetMeRoute() string {
-	return "/users/me"
+	return model.UserDeletionReportFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) GetMe() (*model.User, *Response) {
-	r, err := c.DoAPIGet(c.GetMeRoute(), "")
+func (c *Client) AdminExportUserData(userID string) ([]byte, *Response) {
+	r, err := c.DoAPIGet("/admin/users/"+userID+"/export", "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	me, err := model.UserFromJSON(r.Body)
+	buf, err := io.ReadAll(r.Body)
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
-	return me, BuildResponse(r)
+	return buf, BuildResponse(r)
 }
 
-func (c *Client) GetUserID() string {
-	me, _ := c.GetMe()
-	if me == nil {
-		return ""
+func (c *Client) AdminRevokeUserSessions(userID string) (bool, *Response) {
+	r, err := c.DoAPIDelete("/admin/users/"+userID+"/sessions", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
 	}
-	return me.ID
-}
+	defer closeBody(r)
 
-func (c *Client) GetUserRoute(id string) string {
-	return fmt.Sprintf("/users/%s", id)
+	return true, BuildResponse(r)
 }
 
-func (c *Client) GetUser(id string) (*model.User, *Response) {
-	r, err := c.DoAPIGet(c.GetUserRoute(id), "")
+func (c *Client) AdminUnlockUser(userID string) (bool, *Response) {
+	r, err := c.DoAPIPost("/admin/users/"+userID+"/unlock", "")
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	user, err := model.UserFromJSON(r.Body)
+	return true, BuildResponse(r)
+}
+
+func (c *Client) AdminUpdateUserRoles(userID string, systemAdmin bool) (bool, *Response) {
+	r, err := c.DoAPIPut("/admin/users/"+userID+"/roles", toJSON(&model.AdminUserRolesPatch{SystemAdmin: systemAdmin}))
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
-	return user, BuildResponse(r)
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
 }
 
-func (c *Client) GetUserList(ids []string) ([]model.User, *Response) {
-	r, err := c.DoAPIPost("/users", toJSON(ids))
+func (c *Client) AdminUpdateUserGuest(userID string, guest bool, guestExpireAt int64) (bool, *Response) {
+	r, err := c.DoAPIPut("/admin/users/"+userID+"/guest", toJSON(&model.AdminUserGuestPatch{Guest: guest, GuestExpireAt: guestExpireAt}))
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	requestBody, err := io.ReadAll(r.Body)
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetSystemStatistics() (*model.SystemStatistics, *Response) {
+	r, err := c.DoAPIGet("/admin/statistics", "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
+	defer closeBody(r)
 
-	var users []model.User
-	err = json.Unmarshal(requestBody, &users)
-	if err != nil {
+	var stats *model.SystemStatistics
+	if err := json.NewDecoder(r.Body).Decode(&stats); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
-	return users, BuildResponse(r)
+	return stats, BuildResponse(r)
 }
 
-func (c *Client) GetUserChangePasswordRoute(id string) string {
-	return fmt.Sprintf("/users/%s/changepassword", id)
+// Share links
+
+func (c *Client) GetShareLinksRoute(boardID string) string {
+	return fmt.Sprintf("%s/share-links", c.GetBoardRoute(boardID))
 }
 
-func (c *Client) UserChangePassword(id string, data *model.ChangePasswordRequest) (bool, *Response) {
-	r, err := c.DoAPIPost(c.GetUserChangePasswordRoute(id), toJSON(&data))
+func (c *Client) GetShareLinkRoute(boardID, linkID string) string {
+	return fmt.Sprintf("%s/%s", c.GetShareLinksRoute(boardID), linkID)
+}
+
+func (c *Client) GetShareLinks(boardID string) ([]*model.ShareLink, *Response) {
+	r, err := c.DoAPIGet(c.GetShareLinksRoute(boardID), "")
 	if err != nil {
-		return false, BuildErrorResponse(r, err)
+		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return true, BuildResponse(r)
+	var links []*model.ShareLink
+	if err := json.NewDecoder(r.Body).Decode(&links); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return links, BuildResponse(r)
 }
 
-func (c *Client) CreateBoard(board *model.Board) (*model.Board, *Response) {
-	r, err := c.DoAPIPost(c.GetBoardsRoute(), toJSON(board))
+func (c *Client) CreateShareLink(link *model.ShareLink) (*model.ShareLink, *Response) {
+	r, err := c.DoAPIPost(c.GetShareLinksRoute(link.BoardID), toJSON(link))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardFromJSON(r.Body), BuildResponse(r)
+	return shareLinkFromJSON(r)
 }
 
-func (c *Client) PatchBoard(boardID string, patch *model.BoardPatch) (*model.Board, *Response) {
-	r, err := c.DoAPIPatch(c.GetBoardRoute(boardID), toJSON(patch))
+func (c *Client) PatchShareLink(boardID, linkID string, patch *model.ShareLinkPatch) (*model.ShareLink, *Response) {
+	r, err := c.DoAPIPatch(c.GetShareLinkRoute(boardID, linkID), toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardFromJSON(r.Body), BuildResponse(r)
+	return shareLinkFromJSON(r)
 }
 
-func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
-	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID), "")
+func (c *Client) DeleteShareLink(boardID, linkID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetShareLinkRoute(boardID, linkID), "")
 	if err != nil {
 		return false, BuildErrorResponse(r, err)
 	}
@@ -668,129 +794,152 @@ func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
 	return true, BuildResponse(r)
 }
 
-func (c *Client) UndeleteBoard(boardID string) (bool, *Response) {
-	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/undelete", "")
+func (c *Client) UnlockShareLink(boardID string, request *model.ShareLinkUnlockRequest) (*model.ShareLinkUnlockResponse, *Response) {
+	r, err := c.DoAPIPost(c.GetShareLinksRoute(boardID)+"/unlock", toJSON(request))
 	if err != nil {
-		return false, BuildErrorResponse(r, err)
+		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return true, BuildResponse(r)
-}
-
-func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
-	url := c.GetBoardRoute(boardID)
-	if readToken != "" {
-		url += fmt.Sprintf("?read_token=%s", readToken)
+	var response *model.ShareLinkUnlockResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	}
+	return response, BuildResponse(r)
+}
 
-	r, err := c.DoAPIGet(url, "")
-	if err != nil {
+func shareLinkFromJSON(r *http.Response) (*model.ShareLink, *Response) {
+	var link *model.ShareLink
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
-	defer closeBody(r)
+	return link, BuildResponse(r)
+}
 
-	return model.BoardFromJSON(r.Body), BuildResponse(r)
+// Custom board roles
+
+func (c *Client) GetCustomBoardRolesRoute(teamID string) string {
+	return fmt.Sprintf("%s/board-roles", c.GetTeamRoute(teamID))
 }
 
-func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetadata, *Response) {
-	url := c.GetBoardMetadataRoute(boardID)
-	if readToken != "" {
-		url += fmt.Sprintf("?read_token=%s", readToken)
-	}
+func (c *Client) GetCustomBoardRoleRoute(teamID, roleID string) string {
+	return fmt.Sprintf("%s/%s", c.GetCustomBoardRolesRoute(teamID), roleID)
+}
 
-	r, err := c.DoAPIGet(url, "")
+func (c *Client) GetCustomBoardRoles(teamID string) ([]*model.CustomBoardRole, *Response) {
+	r, err := c.DoAPIGet(c.GetCustomBoardRolesRoute(teamID), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMetadataFromJSON(r.Body), BuildResponse(r)
+	return model.CustomBoardRolesFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards", "")
+func (c *Client) CreateCustomBoardRole(role *model.CustomBoardRole) (*model.CustomBoardRole, *Response) {
+	r, err := c.DoAPIPost(c.GetCustomBoardRolesRoute(role.TeamID), toJSON(role))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	return model.CustomBoardRoleFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) SearchBoardsForUser(teamID, term string, field model.BoardSearchField) ([]*model.Board, *Response) {
-	query := fmt.Sprintf("q=%s&field=%s", term, field)
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?"+query, "")
+func (c *Client) PatchCustomBoardRole(teamID, roleID string, patch *model.CustomBoardRolePatch) (*model.CustomBoardRole, *Response) {
+	r, err := c.DoAPIPatch(c.GetCustomBoardRoleRoute(teamID, roleID), toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	return model.CustomBoardRoleFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Response) {
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?q="+term, "")
+func (c *Client) DeleteCustomBoardRole(teamID, roleID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetCustomBoardRoleRoute(teamID, roleID), "")
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	return true, BuildResponse(r)
 }
 
-func (c *Client) GetMembersForBoard(boardID string) ([]*model.BoardMember, *Response) {
-	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/members", "")
+// User groups
+
+func (c *Client) GetUserGroupsRoute(teamID string) string {
+	return fmt.Sprintf("%s/groups", c.GetTeamRoute(teamID))
+}
+
+func (c *Client) GetUserGroupRoute(teamID, groupID string) string {
+	return fmt.Sprintf("%s/%s", c.GetUserGroupsRoute(teamID), groupID)
+}
+
+func (c *Client) GetUserGroupMembersRoute(teamID, groupID string) string {
+	return fmt.Sprintf("%s/members", c.GetUserGroupRoute(teamID, groupID))
+}
+
+func (c *Client) GetBoardGroupsRoute(boardID string) string {
+	return fmt.Sprintf("%s/groups", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetUserGroups(teamID string) ([]*model.UserGroup, *Response) {
+	r, err := c.DoAPIGet(c.GetUserGroupsRoute(teamID), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMembersFromJSON(r.Body), BuildResponse(r)
+	return model.UserGroupsFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetBoardRoute(member.BoardID)+"/members", toJSON(member))
+func (c *Client) CreateUserGroup(group *model.UserGroup) (*model.UserGroup, *Response) {
+	r, err := c.DoAPIPost(c.GetUserGroupsRoute(group.TeamID), toJSON(group))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	return model.UserGroupFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) JoinBoard(boardID string) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetJoinBoardRoute(boardID), "")
+func (c *Client) PatchUserGroup(teamID, groupID string, patch *model.UserGroupPatch) (*model.UserGroup, *Response) {
+	r, err := c.DoAPIPatch(c.GetUserGroupRoute(teamID, groupID), toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	return model.UserGroupFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) LeaveBoard(boardID string) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetLeaveBoardRoute(boardID), "")
+func (c *Client) DeleteUserGroup(teamID, groupID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetUserGroupRoute(teamID, groupID), "")
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return false, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	return true, BuildResponse(r)
 }
 
-func (c *Client) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPut(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, toJSON(member))
+func (c *Client) GetUserGroupMembers(teamID, groupID string) ([]string, *Response) {
+	r, err := c.DoAPIGet(c.GetUserGroupMembersRoute(teamID, groupID), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	var userIDs []string
+	if err := json.NewDecoder(r.Body).Decode(&userIDs); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return userIDs, BuildResponse(r)
 }
 
-func (c *Client) DeleteBoardMember(member *model.BoardMember) (bool, *Response) {
-	r, err := c.DoAPIDelete(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, "")
+func (c *Client) AddUserGroupMember(teamID, groupID, userID string) (bool, *Response) {
+	r, err := c.DoAPIPost(fmt.Sprintf("%s/%s", c.GetUserGroupMembersRoute(teamID, groupID), userID), "")
 	if err != nil {
 		return false, BuildErrorResponse(r, err)
 	}
@@ -799,14 +948,442 @@ func (c *Client) DeleteBoardMember(member *model.BoardMember) (bool, *Response)
 	return true, BuildResponse(r)
 }
 
-func (c *Client) GetTeamUploadFileRoute(teamID, boardID string) string {
-	return fmt.Sprintf("%s/%s/files", c.GetTeamRoute(teamID), boardID)
+func (c *Client) DeleteUserGroupMember(teamID, groupID, userID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(fmt.Sprintf("%s/%s", c.GetUserGroupMembersRoute(teamID, groupID), userID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
 }
 
-func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.FileUploadResponse, *Response) {
+func (c *Client) GetBoardGroups(boardID string) ([]*model.BoardGroup, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardGroupsRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardGroupsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) AddBoardGroup(boardGroup *model.BoardGroup) (*model.BoardGroup, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardGroupsRoute(boardGroup.BoardID), toJSON(boardGroup))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardGroupFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardGroup(boardID, groupID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(fmt.Sprintf("%s/%s", c.GetBoardGroupsRoute(boardID), groupID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetRegisterRoute() string {
+	return "/register"
+}
+
+func (c *Client) Register(request *model.RegisterRequest) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetRegisterRoute(), toJSON(&request))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RegisterGuestInvitation(token string, request *model.RegisterRequest) (bool, *Response) {
+	r, err := c.DoAPIPost("/invite/"+token+"/register", toJSON(&request))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetLoginRoute() string {
+	return "/login"
+}
+
+func (c *Client) Login(request *model.LoginRequest) (*model.LoginResponse, *Response) {
+	r, err := c.DoAPIPost(c.GetLoginRoute(), toJSON(&request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	data, err := model.LoginResponseFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	if data.Token != "" {
+		c.Token = data.Token
+	}
+
+	return data, BuildResponse(r)
+}
+
+func (c *Client) UnlockAccount(token string) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetLoginRoute()+"/unlock", toJSON(&model.AccountUnlockRequest{Token: token}))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetMeRoute() string {
+	return "/users/me"
+}
+
+func (c *Client) GetMe() (*model.User, *Response) {
+	r, err := c.DoAPIGet(c.GetMeRoute(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	me, err := model.UserFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return me, BuildResponse(r)
+}
+
+func (c *Client) ExportMyData() ([]byte, *Response) {
+	r, err := c.DoAPIGet(c.GetMeRoute()+"/export", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+func (c *Client) GetMySessions() ([]*model.SessionInfo, *Response) {
+	r, err := c.DoAPIGet(c.GetMeRoute()+"/sessions", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.SessionInfosFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RevokeMySession(sessionID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetMeRoute()+"/sessions/"+sessionID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RevokeMyOtherSessions() (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetMeRoute()+"/sessions", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetUserID() string {
+	me, _ := c.GetMe()
+	if me == nil {
+		return ""
+	}
+	return me.ID
+}
+
+func (c *Client) GetUserRoute(id string) string {
+	return fmt.Sprintf("/users/%s", id)
+}
+
+func (c *Client) GetUser(id string) (*model.User, *Response) {
+	r, err := c.DoAPIGet(c.GetUserRoute(id), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	user, err := model.UserFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return user, BuildResponse(r)
+}
+
+func (c *Client) GetUserList(ids []string) ([]model.User, *Response) {
+	r, err := c.DoAPIPost("/users", toJSON(ids))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	var users []model.User
+	err = json.Unmarshal(requestBody, &users)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return users, BuildResponse(r)
+}
+
+func (c *Client) SearchTeamUsers(teamID, searchQuery string) ([]*model.User, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/users?search="+url.QueryEscape(searchQuery), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var users []*model.User
+	if err := json.NewDecoder(r.Body).Decode(&users); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return users, BuildResponse(r)
+}
+
+func (c *Client) GetUserChangePasswordRoute(id string) string {
+	return fmt.Sprintf("/users/%s/changepassword", id)
+}
+
+func (c *Client) UserChangePassword(id string, data *model.ChangePasswordRequest) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetUserChangePasswordRoute(id), toJSON(&data))
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) CreateBoard(board *model.Board) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardsRoute(), toJSON(board))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PatchBoard(boardID string, patch *model.BoardPatch) (*model.Board, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardRoute(boardID), toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) UndeleteBoard(boardID string) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/undelete", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) ArchiveBoard(boardID string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/archive", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UnarchiveBoard(boardID string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/unarchive", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
+	url := c.GetBoardRoute(boardID)
+	if readToken != "" {
+		url += fmt.Sprintf("?read_token=%s", readToken)
+	}
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetadata, *Response) {
+	url := c.GetBoardMetadataRoute(boardID)
+	if readToken != "" {
+		url += fmt.Sprintf("?read_token=%s", readToken)
+	}
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMetadataFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetArchivedBoardsForTeam(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/archived", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) SearchBoardsForUser(teamID, term string, field model.BoardSearchField) ([]*model.Board, *Response) {
+	query := fmt.Sprintf("q=%s&field=%s", term, field)
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?q="+term, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetMembersForBoard(boardID string) ([]*model.BoardMember, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/members", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMembersFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(member.BoardID)+"/members", toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) JoinBoard(boardID string) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetJoinBoardRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) LeaveBoard(boardID string) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetLeaveBoardRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPut(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardMember(member *model.BoardMember) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetTeamUploadFileRoute(teamID, boardID string) string {
+	return fmt.Sprintf("%s/%s/files", c.GetTeamRoute(teamID), boardID)
+}
+
+func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.FileUploadResponse, *Response) {
+	return c.TeamUploadFileWithName(teamID, boardID, "file", data)
+}
+
+func (c *Client) TeamUploadFileWithName(teamID, boardID, filename string, data io.Reader) (*api.FileUploadResponse, *Response) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, filename)
 	if err != nil {
 		return nil, &Response{Error: err}
 	}
@@ -833,6 +1410,120 @@ func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.Fi
 	return fileUploadResponse, BuildResponse(r)
 }
 
+func (c *Client) GetUploadSessionRoute(teamID, boardID, uploadID string) string {
+	return fmt.Sprintf("%s/%s/uploads/%s", c.GetTeamRoute(teamID), boardID, uploadID)
+}
+
+// CreateUploadSession starts a resumable upload of a file.
+func (c *Client) CreateUploadSession(teamID, boardID string, session *model.UploadSession) (*model.UploadSession, *Response) {
+	r, err := c.DoAPIPost(fmt.Sprintf("%s/%s/uploads", c.GetTeamRoute(teamID), boardID), toJSON(session))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return uploadSessionFromJSON(r)
+}
+
+func (c *Client) GetUploadSession(teamID, boardID, uploadID string) (*model.UploadSession, *Response) {
+	r, err := c.DoAPIGet(c.GetUploadSessionRoute(teamID, boardID, uploadID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return uploadSessionFromJSON(r)
+}
+
+// UploadChunk sends the chunk of a resumable upload starting at the given
+// offset of the file.
+func (c *Client) UploadChunk(teamID, boardID, uploadID string, offset int64, data io.Reader) (*model.UploadSession, *Response) {
+	opt := func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/offset+octet-stream")
+		r.Header.Set(api.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPatch, c.APIURL+c.GetUploadSessionRoute(teamID, boardID, uploadID), data, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return uploadSessionFromJSON(r)
+}
+
+// FinalizeUpload completes a resumable upload once the whole file has
+// been sent.
+func (c *Client) FinalizeUpload(teamID, boardID, uploadID string) (*api.FileUploadResponse, *Response) {
+	r, err := c.DoAPIPost(c.GetUploadSessionRoute(teamID, boardID, uploadID)+"/finalize", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	fileUploadResponse, err := api.FileUploadResponseFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return fileUploadResponse, BuildResponse(r)
+}
+
+func (c *Client) DeleteUploadSession(teamID, boardID, uploadID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetUploadSessionRoute(teamID, boardID, uploadID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func uploadSessionFromJSON(r *http.Response) (*model.UploadSession, *Response) {
+	var session *model.UploadSession
+	if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return session, BuildResponse(r)
+}
+
+// GetFile returns the content of an uploaded file, in the given size for
+// images.
+func (c *Client) GetFile(teamID, boardID, fileName string, size model.FileSize) ([]byte, *Response) {
+	url := fmt.Sprintf("/files/teams/%s/%s/%s", teamID, boardID, fileName)
+	if size != model.FileSizeOriginal {
+		url += "?size=" + string(size)
+	}
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+// GetFileWithReadToken returns the content of an uploaded file of a board
+// opened by a read token.
+func (c *Client) GetFileWithReadToken(teamID, boardID, fileName, readToken string) ([]byte, *Response) {
+	r, err := c.DoAPIGet(fmt.Sprintf("/files/teams/%s/%s/%s?read_token=%s", teamID, boardID, fileName, url.QueryEscape(readToken)), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
 func (c *Client) TeamUploadFileInfo(teamID, boardID string, fileName string) (*mmModel.FileInfo, *Response) {
 	r, err := c.DoAPIGet(fmt.Sprintf("/files/teams/%s/%s/%s/info", teamID, boardID, fileName), "")
 	if err != nil {
@@ -846,6 +1537,22 @@ func (c *Client) TeamUploadFileInfo(teamID, boardID string, fileName string) (*m
 	return fileInfoResponse, BuildResponse(r)
 }
 
+// GetSignedFileURL returns a short-lived URL to download a file without a
+// session.
+func (c *Client) GetSignedFileURL(teamID, boardID, fileName string) (*model.SignedFileURL, *Response) {
+	r, err := c.DoAPIPost(fmt.Sprintf("/files/teams/%s/%s/%s/signed-url", teamID, boardID, fileName), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var signedURL *model.SignedFileURL
+	if err := json.NewDecoder(r.Body).Decode(&signedURL); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return signedURL, BuildResponse(r)
+}
+
 func (c *Client) GetSubscriptionsRoute() string {
 	return "/subscriptions"
 }
@@ -943,6 +1650,196 @@ func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 	return BuildResponse(r)
 }
 
+func (c *Client) ImportExternal(teamID string, source, filename string, data io.Reader) (*model.ImportReport, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, filename)
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/import/"+source, body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var report *model.ImportReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return report, BuildResponse(r)
+}
+
+func (c *Client) ExportBoardCards(boardID, format string) ([]byte, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/export?format="+format, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+func (c *Client) ImportBoardCards(boardID, filename string, data io.Reader, dryRun bool) (*model.CardsImportResult, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, filename)
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	url := c.APIURL + c.GetBoardRoute(boardID) + "/import"
+	if dryRun {
+		url += "?dry_run=true"
+	}
+	r, err := c.doAPIRequestReader(http.MethodPost, url, body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.CardsImportResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return result, BuildResponse(r)
+}
+
+func (c *Client) StartArchiveExportJob(teamID string) (*model.ArchiveJob, *Response) {
+	r, err := c.DoAPIPost(c.GetTeamRoute(teamID)+"/archive/export", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var job *model.ArchiveJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return job, BuildResponse(r)
+}
+
+func (c *Client) ImportArchiveWithMode(teamID string, mode model.ImportMode, data io.Reader) (*model.ImportArchiveResult, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/archive/import?mode="+string(mode), body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.ImportArchiveResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return result, BuildResponse(r)
+}
+
+func (c *Client) ImportArchiveAsync(teamID string, mode model.ImportMode, data io.Reader) (*model.ArchiveJob, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/archive/import?async=true&mode="+string(mode), body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var job *model.ArchiveJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return job, BuildResponse(r)
+}
+
+func (c *Client) GetArchiveJobs(teamID string) ([]*model.ArchiveJob, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/archive/jobs", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var jobs []*model.ArchiveJob
+	if err := json.NewDecoder(r.Body).Decode(&jobs); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return jobs, BuildResponse(r)
+}
+
+func (c *Client) GetArchiveJob(teamID, jobID string) (*model.ArchiveJob, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/archive/jobs/"+jobID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var job *model.ArchiveJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return job, BuildResponse(r)
+}
+
+func (c *Client) DownloadArchiveJob(teamID, jobID string) ([]byte, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/archive/jobs/"+jobID+"/download", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
 func (c *Client) MoveContentBlock(srcBlockID string, dstBlockID string, where string, userID string) (bool, *Response) {
 	r, err := c.DoAPIPost("/content-blocks/"+srcBlockID+"/moveto/"+where+"/"+dstBlockID, "")
 	if err != nil {
@@ -1027,3 +1924,140 @@ func (c *Client) UnhideBoard(teamID, categoryID, boardID string) *Response {
 	defer closeBody(r)
 	return BuildResponse(r)
 }
+
+// History
+
+func (c *Client) GetBoardHistory(boardID string, beforeUpdateAt int64, limit int) ([]*model.Board, *Response) {
+	query := fmt.Sprintf("?before_update_at=%d&limit=%d", beforeUpdateAt, limit)
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/history"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBlockHistory(boardID, blockID string, beforeUpdateAt int64, limit int) ([]*model.Block, *Response) {
+	query := fmt.Sprintf("?before_update_at=%d&limit=%d", beforeUpdateAt, limit)
+	r, err := c.DoAPIGet(c.GetBlockRoute(boardID, blockID)+"/history"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBlockHistoryDiff(boardID, blockID string, from, to int64) (*model.BlockHistoryDiff, *Response) {
+	query := fmt.Sprintf("?from=%d&to=%d", from, to)
+	r, err := c.DoAPIGet(c.GetBlockRoute(boardID, blockID)+"/history/diff"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var diff *model.BlockHistoryDiff
+	err = json.NewDecoder(r.Body).Decode(&diff)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return diff, BuildResponse(r)
+}
+
+func (c *Client) RestoreBlockHistory(boardID, blockID string, request *model.HistoryRestoreRequest) (*model.HistoryRestoreResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBlockRoute(boardID, blockID)+"/history/restore", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.HistoryRestoreResult
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) RestoreBoardHistory(boardID string, request *model.HistoryRestoreRequest) (*model.HistoryRestoreResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/history/restore", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.HistoryRestoreResult
+	err = json.NewDecoder(r.Body).Decode(&result)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+// Trash
+
+func (c *Client) GetTeamTrash(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/trash", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RestoreTeamTrash(teamID string, boardIDs []string) ([]*model.Board, *Response) {
+	request := &model.TrashRestoreRequest{IDs: boardIDs}
+	r, err := c.DoAPIPost(c.GetTeamRoute(teamID)+"/trash/restore", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PurgeDeletedBoard(teamID, boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTeamRoute(teamID)+"/trash/"+boardID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBoardTrash(boardID string) ([]*model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/trash", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RestoreBoardTrash(boardID string, blockIDs []string) ([]*model.Block, *Response) {
+	request := &model.TrashRestoreRequest{IDs: blockIDs}
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/trash/restore", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PurgeDeletedBlock(boardID, blockID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID)+"/trash/"+blockID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
@@ -0,0 +1,126 @@
+package integrationtests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func waitForArchiveJob(t *testing.T, th *TestHelper, c *client.Client, teamID, jobID string) *model.ArchiveJob {
+	var job *model.ArchiveJob
+	require.Eventually(t, func() bool {
+		var resp *client.Response
+		job, resp = c.GetArchiveJob(teamID, jobID)
+		th.CheckOK(resp)
+		return job.IsFinished()
+	}, 10*time.Second, 50*time.Millisecond)
+	return job
+}
+
+func TestArchiveJobs(t *testing.T) {
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		th.Logout(th.Client)
+
+		job, resp := th.Client.StartArchiveExportJob("test-team")
+		th.CheckUnauthorized(resp)
+		require.Nil(t, job)
+	})
+
+	t.Run("export and import a team in the background", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := &model.Board{
+			ID:        utils.NewID(utils.IDTypeBoard),
+			TeamID:    "test-team",
+			Title:     "Export Job Board",
+			CreatedBy: th.GetUser1().ID,
+			Type:      model.BoardTypeOpen,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		block := &model.Block{
+			ID:        utils.NewID(utils.IDTypeCard),
+			ParentID:  board.ID,
+			Type:      model.TypeCard,
+			BoardID:   board.ID,
+			Title:     "Card exported by a job",
+			CreatedBy: th.GetUser1().ID,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		_, resp := th.Client.CreateBoardsAndBlocks(&model.BoardsAndBlocks{
+			Boards: []*model.Board{board},
+			Blocks: []*model.Block{block},
+		})
+		th.CheckOK(resp)
+
+		job, resp := th.Client.StartArchiveExportJob("test-team")
+		th.CheckOK(resp)
+		require.Equal(t, model.ArchiveJobTypeExport, job.Type)
+		require.Empty(t, job.DownloadURL)
+
+		job = waitForArchiveJob(t, th, th.Client, "test-team", job.ID)
+		require.Equal(t, model.ArchiveJobStatusSuccess, job.Status, job.Error)
+		require.Equal(t, 100, job.Progress)
+		require.NotZero(t, job.ExpireAt)
+		require.Equal(t, "/api/v2/teams/test-team/archive/jobs/"+job.ID+"/download", job.DownloadURL)
+
+		jobs, resp := th.Client.GetArchiveJobs("test-team")
+		th.CheckOK(resp)
+		require.Len(t, jobs, 1)
+		require.Equal(t, job.ID, jobs[0].ID)
+
+		// the job is only visible to the user that started it
+		_, resp = th.Client2.GetArchiveJob("test-team", job.ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.DownloadArchiveJob("test-team", job.ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client.GetArchiveJob("other-team", job.ID)
+		th.CheckNotFound(resp)
+
+		buf, resp := th.Client.DownloadArchiveJob("test-team", job.ID)
+		th.CheckOK(resp)
+		require.NotEmpty(t, buf)
+
+		importJob, resp := th.Client.ImportArchiveAsync(model.GlobalTeamID, model.ImportModeCreate, bytes.NewReader(buf))
+		th.CheckOK(resp)
+		require.Equal(t, model.ArchiveJobTypeImport, importJob.Type)
+
+		importJob = waitForArchiveJob(t, th, th.Client, model.GlobalTeamID, importJob.ID)
+		require.Equal(t, model.ArchiveJobStatusSuccess, importJob.Status, importJob.Error)
+		require.Empty(t, importJob.DownloadURL)
+		require.Equal(t, model.ImportModeCreate, importJob.ImportMode)
+		require.NotNil(t, importJob.ImportResult)
+		require.Len(t, importJob.ImportResult.BoardsCreated, 1)
+
+		// only exported archives can be downloaded
+		_, resp = th.Client.DownloadArchiveJob(model.GlobalTeamID, importJob.ID)
+		th.CheckBadRequest(resp)
+
+		boards, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, model.GlobalTeamID, true, false)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, board.Title, boards[0].Title)
+		blocks, err := th.Server.App().GetBlocksForBoard(boards[0].ID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		require.Equal(t, block.Title, blocks[0].Title)
+	})
+
+	t.Run("an unknown job should not be found", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client.GetArchiveJob("test-team", utils.NewID(utils.IDTypeNone))
+		th.CheckNotFound(resp)
+	})
+}
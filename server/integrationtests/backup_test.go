@@ -0,0 +1,208 @@
+package integrationtests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackups(t *testing.T) {
+	t.Run("create, list and restore a backup into a fresh server", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+
+		board := th.CreateBoard("test-team", model.BoardTypeOpen)
+		block := &model.Block{
+			ID:        utils.NewID(utils.IDTypeCard),
+			ParentID:  board.ID,
+			Type:      model.TypeCard,
+			BoardID:   board.ID,
+			Title:     "Card in a backup",
+			CreatedBy: th.GetUser1().ID,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		_, resp := th.Client.InsertBlocks(board.ID, []*model.Block{block}, false)
+		th.CheckOK(resp)
+
+		category := th.CreateCategory(model.Category{
+			Name:   "Backed up",
+			UserID: th.GetUser1().ID,
+			TeamID: "test-team",
+			Type:   model.CategoryTypeCustom,
+		})
+		th.UpdateCategoryBoard("test-team", category.ID, board.ID)
+
+		th.Server.Config().EnablePublicSharedBoards = true
+		_, resp = th.Client.PostSharing(&model.Sharing{
+			ID:      board.ID,
+			Enabled: true,
+			Token:   utils.NewID(utils.IDTypeToken),
+		})
+		th.CheckOK(resp)
+
+		rootTeam, err := th.Server.App().GetRootTeam()
+		require.NoError(t, err)
+
+		backup, err := th.Server.App().CreateBackup()
+		require.NoError(t, err)
+		require.False(t, backup.Encrypted)
+		require.NotZero(t, backup.Size)
+
+		backups, err := th.Server.App().GetBackups()
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		require.Equal(t, backup.Name, backups[0].Name)
+
+		data, err := os.ReadFile(filepath.Join(th.Server.Config().BackupPath, backup.Name))
+		require.NoError(t, err)
+		th.TearDown()
+
+		// restore into a fresh server
+		th = SetupTestHelper(t).Start()
+		defer th.TearDown()
+		require.NoError(t, os.MkdirAll(th.Server.Config().BackupPath, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(th.Server.Config().BackupPath, backup.Name), data, 0600))
+
+		result, err := th.Server.App().RestoreBackup(backup.Name, "")
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Users)
+		require.Empty(t, result.SkippedUsers)
+		require.Equal(t, 1, result.Boards)
+		require.Equal(t, 1, result.Sharing)
+		require.Empty(t, result.Conflicts)
+
+		// the restored users can log in
+		th.Login1()
+
+		restoredBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, board.Title, restoredBoard.Title)
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.Equal(t, block.Title, blocks[0].Title)
+
+		categoryBoards := th.GetUserCategoryBoards("test-team")
+		var restoredCategory *model.CategoryBoards
+		for i := range categoryBoards {
+			if categoryBoards[i].ID == category.ID {
+				restoredCategory = &categoryBoards[i]
+			}
+		}
+		require.NotNil(t, restoredCategory)
+		require.Equal(t, category.Name, restoredCategory.Name)
+		require.Len(t, restoredCategory.BoardMetadata, 1)
+		require.Equal(t, board.ID, restoredCategory.BoardMetadata[0].BoardID)
+
+		th.Server.Config().EnablePublicSharedBoards = true
+		sharing, resp := th.Client.GetSharing(board.ID)
+		th.CheckOK(resp)
+		require.True(t, sharing.Enabled)
+
+		restoredRootTeam, err := th.Server.App().GetRootTeam()
+		require.NoError(t, err)
+		require.Equal(t, rootTeam.SignupToken, restoredRootTeam.SignupToken)
+
+		// restoring twice keeps the existing users
+		result, err = th.Server.App().RestoreBackup(backup.Name, "")
+		require.NoError(t, err)
+		require.Zero(t, result.Users)
+		require.Len(t, result.SkippedUsers, 2)
+	})
+
+	t.Run("deactivated users are restored deactivated", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+
+		deactivated, err := th.Server.Store().CreateUser(&model.User{
+			ID:       utils.NewID(utils.IDTypeUser),
+			Username: "deactivated",
+			Email:    "deactivated@example.com",
+			DeleteAt: utils.GetMillis(),
+		})
+		require.NoError(t, err)
+
+		backup, err := th.Server.App().CreateBackup()
+		require.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(th.Server.Config().BackupPath, backup.Name))
+		require.NoError(t, err)
+		th.TearDown()
+
+		th = SetupTestHelper(t).Start()
+		defer th.TearDown()
+		require.NoError(t, os.MkdirAll(th.Server.Config().BackupPath, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(th.Server.Config().BackupPath, backup.Name), data, 0600))
+
+		result, err := th.Server.App().RestoreBackup(backup.Name, "")
+		require.NoError(t, err)
+		require.Equal(t, 3, result.Users)
+
+		_, err = th.Server.Store().GetUserByID(deactivated.ID)
+		require.True(t, model.IsErrNotFound(err), err)
+		users, err := th.Server.Store().GetAllUsers(model.UserQueryOptions{IncludeDeactivated: true})
+		require.NoError(t, err)
+		require.Len(t, users, 3)
+
+		// restoring twice skips the deactivated user too
+		result, err = th.Server.App().RestoreBackup(backup.Name, "")
+		require.NoError(t, err)
+		require.Zero(t, result.Users)
+		require.Len(t, result.SkippedUsers, 3)
+	})
+
+	t.Run("encrypted backups need the passphrase", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		th.Server.Config().BackupPassphrase = "secret"
+		backup, err := th.Server.App().CreateBackup()
+		require.NoError(t, err)
+		require.True(t, backup.Encrypted)
+
+		th.Server.Config().BackupPassphrase = ""
+		_, err = th.Server.App().RestoreBackup(backup.Name, "")
+		require.True(t, model.IsErrBadRequest(err), err)
+		_, err = th.Server.App().RestoreBackup(backup.Name, "wrong")
+		require.True(t, model.IsErrBadRequest(err), err)
+
+		result, err := th.Server.App().RestoreBackup(backup.Name, "secret")
+		require.NoError(t, err)
+		require.Len(t, result.SkippedUsers, 2)
+	})
+
+	t.Run("old backups are removed", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		th.Server.Config().BackupRetentionCount = 2
+		var names []string
+		for i := 0; i < 3; i++ {
+			backup, err := th.Server.App().CreateBackup()
+			require.NoError(t, err)
+			names = append(names, backup.Name)
+			// backup names have a millisecond precision
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		backups, err := th.Server.App().GetBackups()
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		require.Equal(t, names[2], backups[0].Name)
+		require.Equal(t, names[1], backups[1].Name)
+	})
+
+	t.Run("invalid backup names are rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, err := th.Server.App().RestoreBackup("../files/backup.fbbackup", "")
+		require.True(t, model.IsErrBadRequest(err), err)
+		_, err = th.Server.App().RestoreBackup("backup-20200101-000000.000.fbbackup", "")
+		require.True(t, model.IsErrNotFound(err), err)
+	})
+}
@@ -0,0 +1,143 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomBoardRoles(t *testing.T) {
+	t.Run("team admins manage the custom board roles", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		role, resp := th.Client.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      testTeamID,
+			Name:        "Reviewer",
+			Permissions: []string{model.PermissionViewBoard.Id, model.PermissionCommentBoardCards.Id},
+		})
+		th.CheckOK(resp)
+		require.NotEmpty(t, role.ID)
+		require.Equal(t, th.GetUser1().ID, role.CreatedBy)
+
+		// the members of the team can list the roles but not manage them
+		roles, resp := th.Client2.GetCustomBoardRoles(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, roles, 1)
+		_, resp = th.Client2.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      testTeamID,
+			Name:        "Other",
+			Permissions: []string{model.PermissionViewBoard.Id},
+		})
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.DeleteCustomBoardRole(testTeamID, role.ID)
+		th.CheckForbidden(resp)
+
+		// the permissions must be board permissions, including viewing it
+		_, resp = th.Client.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      testTeamID,
+			Name:        "Invalid",
+			Permissions: []string{model.PermissionManageSystem.Id},
+		})
+		th.CheckBadRequest(resp)
+		_, resp = th.Client.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      testTeamID,
+			Name:        "Invalid",
+			Permissions: []string{model.PermissionCommentBoardCards.Id},
+		})
+		th.CheckBadRequest(resp)
+
+		// the roles of a team can't be managed from another team
+		_, resp = th.Client.DeleteCustomBoardRole("test-team", role.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.DeleteCustomBoardRole(testTeamID, role.ID)
+		th.CheckOK(resp)
+		roles, resp = th.Client.GetCustomBoardRoles(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, roles)
+	})
+
+	t.Run("a custom role grants its permissions to the board members", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		role, resp := th.Client.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      testTeamID,
+			Name:        "Mover",
+			Permissions: []string{model.PermissionViewBoard.Id, model.PermissionCommentBoardCards.Id, model.PermissionMoveBoardCards.Id},
+		})
+		th.CheckOK(resp)
+
+		board, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypePrivate, 1)
+		card := cards[0]
+
+		_, resp = th.Client.AddMemberToBoard(&model.BoardMember{
+			BoardID:      board.ID,
+			UserID:       th.GetUser2().ID,
+			CustomRoleID: role.ID,
+		})
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+
+		// the member can move the card, but not edit it
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "done"},
+		}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client2.PatchBlock(board.ID, card.ID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "todo"}},
+		}, false)
+		th.CheckOK(resp)
+
+		title := "new title"
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{Title: &title}, false)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.PatchBlock(board.ID, card.ID, &model.BlockPatch{Title: &title}, false)
+		th.CheckForbidden(resp)
+
+		// changing the role applies to its members right away
+		permissions := []string{model.PermissionViewBoard.Id, model.PermissionManageBoardCards.Id}
+		_, resp = th.Client.PatchCustomBoardRole(testTeamID, role.ID, &model.CustomBoardRolePatch{Permissions: &permissions})
+		th.CheckOK(resp)
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{Title: &title}, false)
+		th.CheckOK(resp)
+
+		// deleting the role leaves the member without permissions
+		_, resp = th.Client.DeleteCustomBoardRole(testTeamID, role.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("only the roles of the team of the board can be assigned", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		role, resp := th.Client.CreateCustomBoardRole(&model.CustomBoardRole{
+			TeamID:      "test-team",
+			Name:        "Reviewer",
+			Permissions: []string{model.PermissionViewBoard.Id},
+		})
+		th.CheckOK(resp)
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		_, resp = th.Client.AddMemberToBoard(&model.BoardMember{
+			BoardID:      board.ID,
+			UserID:       th.GetUser2().ID,
+			CustomRoleID: role.ID,
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.AddMemberToBoard(&model.BoardMember{
+			BoardID:      board.ID,
+			UserID:       th.GetUser2().ID,
+			CustomRoleID: "nonexistent-role-id",
+		})
+		th.CheckBadRequest(resp)
+	})
+}
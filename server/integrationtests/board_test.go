@@ -259,7 +259,7 @@ func TestCreateBoard(t *testing.T) {
 			th.CheckBadRequest(resp)
 			require.Nil(t, board)
 
-			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true)
+			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true, false)
 			require.NoError(t, err)
 			require.Empty(t, boards)
 		})
@@ -273,7 +273,7 @@ func TestCreateBoard(t *testing.T) {
 			th.CheckBadRequest(resp)
 			require.Nil(t, board)
 
-			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true)
+			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true, false)
 			require.NoError(t, err)
 			require.Empty(t, boards)
 		})
@@ -288,7 +288,7 @@ func TestCreateBoard(t *testing.T) {
 			th.CheckForbidden(resp)
 			require.Nil(t, board)
 
-			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true)
+			boards, err := th.Server.App().GetBoardsForUserAndTeam(user1.ID, teamID, true, false)
 			require.NoError(t, err)
 			require.Empty(t, boards)
 		})
@@ -1195,6 +1195,84 @@ func TestUndeleteBoard(t *testing.T) {
 	})
 }
 
+func TestArchiveBoard(t *testing.T) {
+	teamID := testTeamID
+
+	t.Run("a user without admin permissions should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(teamID, model.BoardTypeOpen)
+		_, err := th.Server.App().AddMemberToBoard(&model.BoardMember{
+			UserID:       th.GetUser2().ID,
+			BoardID:      board.ID,
+			SchemeEditor: true,
+		})
+		require.NoError(t, err)
+
+		archivedBoard, resp := th.Client2.ArchiveBoard(board.ID)
+		th.CheckForbidden(resp)
+		require.Nil(t, archivedBoard)
+	})
+
+	t.Run("an archived board should be read-only and listed separately", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(teamID, model.BoardTypeOpen)
+		otherBoard := th.CreateBoard(teamID, model.BoardTypeOpen)
+
+		newTitle := "new title"
+
+		archivedBoard, resp := th.Client.ArchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.NotZero(t, archivedBoard.ArchiveAt)
+
+		boards, resp := th.Client.GetBoardsForTeam(teamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+		require.Equal(t, otherBoard.ID, boards[0].ID)
+
+		boards, resp = th.Client.GetArchivedBoardsForTeam(teamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+		require.Equal(t, board.ID, boards[0].ID)
+
+		boards, resp = th.Client.SearchBoardsForUser(teamID, board.Title, model.BoardSearchFieldTitle)
+		th.CheckOK(resp)
+		for _, b := range boards {
+			require.NotEqual(t, board.ID, b.ID)
+		}
+
+		rBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, archivedBoard.ArchiveAt, rBoard.ArchiveAt)
+
+		_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &newTitle})
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+		}}, false)
+		th.CheckForbidden(resp)
+
+		unarchivedBoard, resp := th.Client.UnarchiveBoard(board.ID)
+		th.CheckOK(resp)
+		require.Zero(t, unarchivedBoard.ArchiveAt)
+
+		boards, resp = th.Client.GetBoardsForTeam(teamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 2)
+
+		_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &newTitle})
+		th.CheckOK(resp)
+	})
+}
+
 func TestGetMembersForBoard(t *testing.T) {
 	teamID := testTeamID
 
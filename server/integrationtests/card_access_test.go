@@ -0,0 +1,166 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardAccess(t *testing.T) {
+	setup := func(t *testing.T) (*TestHelper, *model.Board) {
+		th := SetupTestHelper(t).InitBasic()
+
+		board, resp := th.Client.CreateBoard(&model.Board{
+			TeamID: testTeamID,
+			Type:   model.BoardTypePrivate,
+			CardProperties: []map[string]interface{}{
+				{"id": "status", "name": "Status", "type": "text"},
+				{"id": "salary", "name": "Salary", "type": "text", model.CardPropertyRestrictedField: true},
+				{"id": "owner", "name": "Owner", "type": "person"},
+			},
+		})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.AddMemberToBoard(&model.BoardMember{
+			BoardID:      board.ID,
+			UserID:       th.GetUser2().ID,
+			SchemeEditor: true,
+		})
+		th.CheckOK(resp)
+		return th, board
+	}
+
+	t.Run("private cards are hidden to the members that are not assigned", func(t *testing.T) {
+		th, board := setup(t)
+		defer th.TearDown()
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "private card",
+			IsPrivate:  true,
+			Properties: map[string]any{"status": "open"},
+		}, true)
+		th.CheckOK(resp)
+
+		cards, resp := th.Client2.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Empty(t, cards)
+
+		blocks, resp := th.Client2.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		for _, block := range blocks {
+			require.NotEqual(t, card.ID, block.ID)
+		}
+
+		_, resp = th.Client2.GetCard(card.ID)
+		th.CheckForbidden(resp)
+
+		title := "new title"
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{Title: &title}, true)
+		th.CheckForbidden(resp)
+
+		// once assigned, the member can see the card
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"owner": th.GetUser2().ID},
+		}, true)
+		th.CheckOK(resp)
+
+		cards, resp = th.Client2.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, card.ID, cards[0].ID)
+	})
+
+	t.Run("restricted property values are hidden to the members without permission", func(t *testing.T) {
+		th, board := setup(t)
+		defer th.TearDown()
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "card",
+			Properties: map[string]any{"status": "open", "salary": "1000"},
+		}, true)
+		th.CheckOK(resp)
+
+		cards, resp := th.Client2.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, "open", cards[0].Properties["status"])
+		require.NotContains(t, cards[0].Properties, "salary")
+
+		// the member can't edit the restricted property
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"salary": "2000"},
+		}, true)
+		th.CheckForbidden(resp)
+
+		// but editing the other properties keeps its value
+		_, resp = th.Client2.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "done"},
+		}, true)
+		th.CheckOK(resp)
+
+		adminCard, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "done", adminCard.Properties["status"])
+		require.Equal(t, "1000", adminCard.Properties["salary"])
+
+		// the restricted column is left out of the exports
+		data, resp := th.Client2.ExportBoardCards(board.ID, "csv")
+		th.CheckOK(resp)
+		require.Contains(t, string(data), "Status")
+		require.NotContains(t, string(data), "Salary")
+		require.NotContains(t, string(data), "1000")
+
+		data, resp = th.Client.ExportBoardCards(board.ID, "csv")
+		th.CheckOK(resp)
+		require.Contains(t, string(data), "Salary")
+
+		// only the members that can view the restricted properties can
+		// change them
+		_, resp = th.Client2.PatchBoard(board.ID, &model.BoardPatch{DeletedCardProperties: []string{"salary"}})
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("deleted private cards and restricted values are hidden in the trash", func(t *testing.T) {
+		th, board := setup(t)
+		defer th.TearDown()
+
+		privateCard, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:     "private card",
+			IsPrivate: true,
+		}, true)
+		th.CheckOK(resp)
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "card",
+			Properties: map[string]any{"status": "open", "salary": "1000"},
+		}, true)
+		th.CheckOK(resp)
+
+		for _, id := range []string{privateCard.ID, card.ID} {
+			_, resp = th.Client.DeleteBlock(board.ID, id, true)
+			th.CheckOK(resp)
+		}
+
+		trash, resp := th.Client2.GetBoardTrash(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, trash, 1)
+		require.Equal(t, card.ID, trash[0].ID)
+		properties, _ := trash[0].Fields["properties"].(map[string]interface{})
+		require.Equal(t, "open", properties["status"])
+		require.NotContains(t, properties, "salary")
+
+		trash, resp = th.Client.GetBoardTrash(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, trash, 2)
+
+		// the hidden private card can't be restored by the member
+		_, resp = th.Client2.UndeleteBlock(board.ID, privateCard.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.UndeleteBlock(board.ID, card.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client.UndeleteBlock(board.ID, privateCard.ID)
+		th.CheckOK(resp)
+	})
+}
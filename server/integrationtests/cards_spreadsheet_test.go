@@ -0,0 +1,195 @@
+package integrationtests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createSpreadsheetTestBoard(th *TestHelper) (*model.Board, []*model.Block) {
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+	board, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To Do", "color": "propColorGray"},
+					map[string]interface{}{"id": "done", "value": "Done", "color": "propColorGreen"},
+				},
+			},
+			{"id": "notes", "name": "Notes", "type": "text", "options": []interface{}{}},
+		},
+	})
+	th.CheckOK(resp)
+
+	cards := []*model.Block{
+		{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+			Title:    "Card 1",
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "todo", "notes": "first"}},
+		},
+		{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			CreateAt: 2,
+			UpdateAt: 2,
+			Type:     model.TypeCard,
+			Title:    "Card 2",
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "done"}},
+		},
+	}
+	cards, resp = th.Client.InsertBlocks(board.ID, cards, false)
+	th.CheckOK(resp)
+	return board, cards
+}
+
+func TestExportBoardCards(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board, cards := createSpreadsheetTestBoard(th)
+
+	t.Run("CSV export", func(t *testing.T) {
+		data, resp := th.Client.ExportBoardCards(board.ID, "csv")
+		th.CheckOK(resp)
+
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, []string{"Card ID", "Title", "Status", "Notes"}, rows[0])
+		// the cards are created in the same millisecond, so their order
+		// depends on their random IDs
+		require.ElementsMatch(t, [][]string{
+			{cards[0].ID, "Card 1", "TO DO", "first"},
+			{cards[1].ID, "Card 2", "DONE", ""},
+		}, rows[1:])
+	})
+
+	t.Run("an unknown format should fail", func(t *testing.T) {
+		_, resp := th.Client.ExportBoardCards(board.ID, "pdf")
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("an exported spreadsheet imports without changes", func(t *testing.T) {
+		for _, format := range []string{"csv", "xlsx"} {
+			data, resp := th.Client.ExportBoardCards(board.ID, format)
+			th.CheckOK(resp)
+
+			result, resp := th.Client.ImportBoardCards(board.ID, "cards."+format, bytes.NewReader(data), true)
+			th.CheckOK(resp)
+			require.Empty(t, result.Changes)
+			require.Empty(t, result.NewProperties)
+			require.Empty(t, result.NewOptions)
+			require.Equal(t, 2, result.Unchanged)
+		}
+	})
+}
+
+func TestImportBoardCards(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board, cards := createSpreadsheetTestBoard(th)
+
+	csvData := "Card ID,Title,Status,Budget\n" +
+		cards[0].ID + ",Card 1,done,100\n" +
+		",Card 3,Blocked,\n"
+
+	t.Run("dry run", func(t *testing.T) {
+		result, resp := th.Client.ImportBoardCards(board.ID, "cards.csv", strings.NewReader(csvData), true)
+		th.CheckOK(resp)
+		require.True(t, result.DryRun)
+		require.Len(t, result.NewProperties, 1)
+		require.Equal(t, "Budget", result.NewProperties[0].Name)
+		require.Equal(t, []model.CardsImportOption{{Property: "Status", Value: "Blocked"}}, result.NewOptions)
+
+		require.Len(t, result.Changes, 2)
+		require.Equal(t, "update", result.Changes[0].Action)
+		require.Equal(t, cards[0].ID, result.Changes[0].CardID)
+		require.Len(t, result.Changes[0].Properties, 2)
+		require.Equal(t, "create", result.Changes[1].Action)
+		require.Empty(t, result.Changes[1].CardID)
+		require.Equal(t, "Card 3", result.Changes[1].Title)
+
+		// nothing has changed
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 2)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		result, resp := th.Client.ImportBoardCards(board.ID, "cards.csv", strings.NewReader(csvData), false)
+		th.CheckOK(resp)
+		require.False(t, result.DryRun)
+		require.Len(t, result.Changes, 2)
+		newCardID := result.Changes[1].CardID
+		require.NotEmpty(t, newCardID)
+
+		rBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, rBoard.CardProperties, 3)
+		budgetID := rBoard.CardProperties[2]["id"].(string)
+		require.Len(t, rBoard.CardProperties[0]["options"], 3)
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 3)
+		for _, block := range blocks {
+			properties := block.Fields["properties"].(map[string]interface{})
+			switch block.ID {
+			case cards[0].ID:
+				require.Equal(t, "done", properties["status"])
+				require.Equal(t, "100", properties[budgetID])
+				// the notes column is not in the spreadsheet
+				require.Equal(t, "first", properties["notes"])
+			case newCardID:
+				require.Equal(t, "Card 3", block.Title)
+				require.NotEmpty(t, properties["status"])
+			}
+		}
+	})
+
+	t.Run("a spreadsheet without title column should fail", func(t *testing.T) {
+		_, resp := th.Client.ImportBoardCards(board.ID, "cards.csv", strings.NewReader("Status\nDone\n"), true)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("invalid values are reported", func(t *testing.T) {
+		data := "Title,Due\nCard 4,not a date\n"
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "due", "name": "Due", "type": "date", "options": []interface{}{}},
+			},
+		})
+		th.CheckOK(resp)
+
+		result, resp := th.Client.ImportBoardCards(board.ID, "cards.csv", strings.NewReader(data), true)
+		th.CheckOK(resp)
+		require.Len(t, result.Warnings, 1)
+		require.Contains(t, result.Warnings[0], "row 2")
+	})
+}
+
+func TestImportBoardCardsPermissions(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+	_, resp := th.Client2.ImportBoardCards(board.ID, "cards.csv", strings.NewReader("Title\nCard\n"), false)
+	th.CheckForbidden(resp)
+
+	_, resp = th.Client2.ExportBoardCards(board.ID, "csv")
+	th.CheckForbidden(resp)
+}
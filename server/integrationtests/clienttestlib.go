@@ -131,6 +131,8 @@ func getTestConfig() (*config.Configuration, error) {
 		WebPath:           "./pack",
 		FilesDriver:       "local",
 		FilesPath:         "./files",
+		BackupDriver:      "local",
+		BackupPath:        "./backups",
 		LoggingCfgJSON:    logging,
 		SessionExpireTime: int64(30 * time.Second),
 		AuthMode:          "native",
@@ -147,8 +149,12 @@ func newTestServerWithLicense(singleUserToken string, licenseType LicenseType) *
 		panic(err)
 	}
 
+	return newTestServerWithConfig(cfg, singleUserToken, licenseType)
+}
+
+func newTestServerWithConfig(cfg *config.Configuration, singleUserToken string, licenseType LicenseType) *server.Server {
 	logger, _ := mlog.NewLogger()
-	if err = logger.Configure("", cfg.LoggingCfgJSON, nil); err != nil {
+	if err := logger.Configure("", cfg.LoggingCfgJSON, nil); err != nil {
 		panic(err)
 	}
 	singleUser := len(singleUserToken) > 0
@@ -325,6 +331,27 @@ func SetupTestHelperWithLicense(t *testing.T, licenseType LicenseType) *TestHelp
 	return th
 }
 
+// SetupTestHelperWithConfig creates a test helper whose server
+// configuration is first modified by updateConfig.
+func SetupTestHelperWithConfig(t *testing.T, updateConfig func(*config.Configuration)) *TestHelper {
+	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
+	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
+
+	th := &TestHelper{
+		T:                  t,
+		origEnvUnitTesting: origUnitTesting,
+	}
+
+	cfg, err := getTestConfig()
+	require.NoError(t, err)
+	updateConfig(cfg)
+
+	th.Server = newTestServerWithConfig(cfg, "", LicenseNone)
+	th.Client = client.NewClient(th.Server.Config().ServerRoot, "")
+	th.Client2 = client.NewClient(th.Server.Config().ServerRoot, "")
+	return th
+}
+
 // Start starts the test server and ensures that it's correctly
 // responding to requests before returning.
 func (th *TestHelper) Start() *TestHelper {
@@ -380,9 +407,25 @@ func (th *TestHelper) InitBasic() *TestHelper {
 	// user2
 	th.RegisterAndLogin(th.Client2, user2Username, "user2@sample.com", password, team.SignupToken)
 
+	// the tests create their boards in test teams both users are members of
+	for _, teamID := range []string{testTeamID, "test-team"} {
+		for _, c := range []*client.Client{th.Client, th.Client2} {
+			th.AddTeamMember(teamID, c.GetUserID())
+		}
+	}
+
 	return th
 }
 
+func (th *TestHelper) AddTeamMember(teamID, userID string) {
+	member := &model.TeamMember{
+		TeamID:   teamID,
+		UserID:   userID,
+		CreateAt: utils.GetMillis(),
+	}
+	require.NoError(th.T, th.Server.Store().SaveTeamMember(member))
+}
+
 var ErrRegisterFail = errors.New("register failed")
 
 func (th *TestHelper) TearDown() {
@@ -400,6 +443,7 @@ func (th *TestHelper) TearDown() {
 	}
 
 	os.RemoveAll(th.Server.Config().FilesPath)
+	os.RemoveAll(th.Server.Config().BackupPath)
 
 	if err := os.Remove(th.Server.Config().DBConfigString); err == nil {
 		logger.Debug("Removed test database", mlog.String("file", th.Server.Config().DBConfigString))
@@ -551,6 +595,11 @@ func (th *TestHelper) CheckForbidden(r *client.Response) {
 	require.Error(th.T, r.Error)
 }
 
+func (th *TestHelper) CheckConflict(r *client.Response) {
+	require.Equal(th.T, http.StatusConflict, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
+
 func (th *TestHelper) CheckRequestEntityTooLarge(r *client.Response) {
 	require.Equal(th.T, http.StatusRequestEntityTooLarge, r.StatusCode)
 	require.Error(th.T, r.Error)
@@ -560,3 +609,8 @@ func (th *TestHelper) CheckNotImplemented(r *client.Response) {
 	require.Equal(th.T, http.StatusNotImplemented, r.StatusCode)
 	require.Error(th.T, r.Error)
 }
+
+func (th *TestHelper) CheckTooManyRequests(r *client.Response) {
+	require.Equal(th.T, http.StatusTooManyRequests, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
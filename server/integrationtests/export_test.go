@@ -54,7 +54,7 @@ func TestExportBoard(t *testing.T) {
 		require.NoError(t, resp.Error)
 
 		// check for test card
-		boardsImported, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, model.GlobalTeamID, true)
+		boardsImported, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, model.GlobalTeamID, true, false)
 		require.NoError(t, err)
 		require.Len(t, boardsImported, 1)
 		boardImported := boardsImported[0]
@@ -64,3 +64,128 @@ func TestExportBoard(t *testing.T) {
 		require.Equal(t, block.Title, blocksImported[0].Title)
 	})
 }
+
+func TestImportArchiveModes(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	teamID := "test-team"
+	board := &model.Board{
+		ID:        utils.NewID(utils.IDTypeBoard),
+		TeamID:    teamID,
+		Title:     "Backup Test Board",
+		CreatedBy: th.GetUser1().ID,
+		Type:      model.BoardTypeOpen,
+	}
+	card := &model.Block{
+		ID:        utils.NewID(utils.IDTypeCard),
+		ParentID:  board.ID,
+		Type:      model.TypeCard,
+		BoardID:   board.ID,
+		Title:     "Card in the backup",
+		CreatedBy: th.GetUser1().ID,
+		CreateAt:  utils.GetMillis(),
+		UpdateAt:  utils.GetMillis(),
+	}
+	babs, resp := th.Client.CreateBoardsAndBlocks(&model.BoardsAndBlocks{
+		Boards: []*model.Board{board},
+		Blocks: []*model.Block{card},
+	})
+	th.CheckOK(resp)
+	board = babs.Boards[0]
+	card = babs.Blocks[0]
+
+	archive, resp := th.Client.ExportBoardArchive(board.ID)
+	th.CheckOK(resp)
+
+	// change the board after the backup
+	newTitle := "Card changed after the backup"
+	_, resp = th.Client.PatchBlock(board.ID, card.ID, &model.BlockPatch{Title: &newTitle}, false)
+	th.CheckOK(resp)
+	newCard := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		BoardID:  board.ID,
+		Title:    "Card added after the backup",
+		CreateAt: utils.GetMillis(),
+		UpdateAt: utils.GetMillis(),
+	}
+	_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{newCard}, false)
+	th.CheckOK(resp)
+
+	blockTitles := func(boardID string) []string {
+		blocks, resp := th.Client.GetBlocksForBoard(boardID)
+		th.CheckOK(resp)
+		titles := []string{}
+		for _, block := range blocks {
+			titles = append(titles, block.Title)
+		}
+		return titles
+	}
+
+	t.Run("an invalid mode should fail", func(t *testing.T) {
+		_, resp := th.Client.ImportArchiveWithMode(teamID, "replace", bytes.NewReader(archive))
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("merge keeps the newer changes and reports the conflicts", func(t *testing.T) {
+		result, resp := th.Client.ImportArchiveWithMode(teamID, model.ImportModeMerge, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Equal(t, model.ImportModeMerge, result.Mode)
+		require.Empty(t, result.BoardsCreated)
+		require.Empty(t, result.BoardsUpdated)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, card.ID, result.Conflicts[0].BlockID)
+		require.Equal(t, newTitle, result.Conflicts[0].Title)
+
+		require.ElementsMatch(t, []string{newTitle, newCard.Title}, blockTitles(board.ID))
+	})
+
+	t.Run("other users can't overwrite the board", func(t *testing.T) {
+		result, resp := th.Client2.ImportArchiveWithMode(teamID, model.ImportModeOverwrite, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Empty(t, result.BoardsUpdated)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, board.ID, result.Conflicts[0].BoardID)
+		require.Empty(t, result.Conflicts[0].BlockID)
+	})
+
+	t.Run("overwrite restores the backup", func(t *testing.T) {
+		result, resp := th.Client.ImportArchiveWithMode(teamID, model.ImportModeOverwrite, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Equal(t, []string{board.ID}, result.BoardsUpdated)
+		require.Equal(t, 1, result.BlocksUpdated)
+		require.Equal(t, 1, result.BlocksDeleted)
+		require.Empty(t, result.Conflicts)
+
+		require.Equal(t, []string{card.Title}, blockTitles(board.ID))
+	})
+
+	t.Run("merging the same backup again changes nothing", func(t *testing.T) {
+		result, resp := th.Client.ImportArchiveWithMode(teamID, model.ImportModeMerge, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Empty(t, result.BoardsUpdated)
+		require.Empty(t, result.Conflicts)
+	})
+
+	t.Run("create imports a copy of the board", func(t *testing.T) {
+		result, resp := th.Client.ImportArchiveWithMode(teamID, model.ImportModeCreate, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Len(t, result.BoardsCreated, 1)
+		require.NotEqual(t, board.ID, result.BoardsCreated[0])
+		require.Equal(t, 1, result.BlocksCreated)
+	})
+
+	t.Run("merge recreates a deleted board with the same IDs", func(t *testing.T) {
+		_, resp := th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+
+		result, resp := th.Client.ImportArchiveWithMode(teamID, model.ImportModeMerge, bytes.NewReader(archive))
+		th.CheckOK(resp)
+		require.Equal(t, []string{board.ID}, result.BoardsCreated)
+		require.Equal(t, 1, result.BlocksCreated)
+
+		require.Equal(t, []string{card.Title}, blockTitles(board.ID))
+	})
+}
@@ -2,9 +2,18 @@ package integrationtests
 
 import (
 	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"path/filepath"
 	"testing"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/services/filescan"
+	"github.com/mattermost/focalboard/server/services/filescan/clamdtest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -86,3 +95,287 @@ func TestFileInfo(t *testing.T) {
 		require.NotNil(t, fileInfo.Id)
 	})
 }
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func decodeImageSize(t *testing.T, data []byte) (int, int) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	return config.Width, config.Height
+}
+
+func TestFilePreviews(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	t.Run("thumbnails and previews are generated on upload", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		original := testPNG(t, 2400, 1200)
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "photo.png", bytes.NewReader(original))
+		th.CheckOK(resp)
+
+		fileInfo, err := th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		require.True(t, fileInfo.HasPreviewImage)
+		require.Equal(t, 2400, fileInfo.Width)
+		require.Equal(t, 1200, fileInfo.Height)
+
+		data, resp := th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+		require.Equal(t, original, data)
+
+		data, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckOK(resp)
+		width, height := decodeImageSize(t, data)
+		require.Equal(t, 400, width)
+		require.Equal(t, 200, height)
+
+		data, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizePreview)
+		th.CheckOK(resp)
+		width, height = decodeImageSize(t, data)
+		require.Equal(t, 1920, width)
+		require.Equal(t, 960, height)
+
+		_, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, "huge")
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("small images are not enlarged", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "icon.png", bytes.NewReader(testPNG(t, 64, 32)))
+		th.CheckOK(resp)
+
+		data, resp := th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizePreview)
+		th.CheckOK(resp)
+		width, height := decodeImageSize(t, data)
+		require.Equal(t, 64, width)
+		require.Equal(t, 32, height)
+	})
+
+	t.Run("files without previews are served as is", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "notes.txt", bytes.NewBufferString("not an image"))
+		th.CheckOK(resp)
+
+		data, resp := th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckOK(resp)
+		require.Equal(t, "not an image", string(data))
+	})
+
+	t.Run("the files of a board can't be read through another board", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, privateBoard.ID, "photo.png", bytes.NewReader(testPNG(t, 800, 600)))
+		th.CheckOK(resp)
+
+		otherBoard, resp := th.Client2.CreateBoard(&model.Board{TeamID: testTeamID, Type: model.BoardTypePrivate})
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.GetFile(testTeamID, privateBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckForbidden(resp)
+
+		for _, size := range []model.FileSize{model.FileSizeThumb, model.FileSizePreview, model.FileSizeOriginal} {
+			_, resp = th.Client2.GetFile(testTeamID, otherBoard.ID, file.FileID, size)
+			th.CheckNotFound(resp)
+		}
+
+		// the file is still served through its own board
+		_, resp = th.Client.GetFile(testTeamID, privateBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckOK(resp)
+	})
+
+	t.Run("existing files are back-filled", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "photo.png", bytes.NewReader(testPNG(t, 800, 600)))
+		th.CheckOK(resp)
+
+		// simulate a file uploaded before the previews existed
+		fileInfo, err := th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		fileInfo.ThumbnailPath = ""
+		fileInfo.PreviewPath = ""
+		fileInfo.Width = 0
+		fileInfo.Height = 0
+		fileInfo.HasPreviewImage = false
+		require.NoError(t, th.Server.Store().UpdateFileInfoPreviews(fileInfo))
+
+		data, resp := th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckOK(resp)
+		width, _ := decodeImageSize(t, data)
+		require.Equal(t, 800, width)
+
+		count, err := th.Server.App().BackfillFilePreviews()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		data, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeThumb)
+		th.CheckOK(resp)
+		width, height := decodeImageSize(t, data)
+		require.Equal(t, 400, width)
+		require.Equal(t, 300, height)
+
+		// nothing left to back-fill
+		count, err = th.Server.App().BackfillFilePreviews()
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
+
+// findFiles returns the files with the given extension under dir.
+func findFiles(t *testing.T, dir, extension string) []string {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == extension {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if !errors.Is(err, fs.ErrNotExist) {
+		require.NoError(t, err)
+	}
+	return files
+}
+
+func TestFileScan(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	clamd, err := clamdtest.NewServer()
+	require.NoError(t, err)
+	defer clamd.Close()
+
+	setupScan := func(t *testing.T, allowed, denied []string) *TestHelper {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.FileScanDriver = filescan.DriverClamd
+			cfg.ClamdAddress = clamd.Address()
+			cfg.AllowedFileTypes = allowed
+			cfg.DeniedFileTypes = denied
+		}).InitBasic()
+		return th
+	}
+
+	t.Run("clean files are stored with their detected type", func(t *testing.T) {
+		th := setupScan(t, nil, nil)
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "notes.txt", bytes.NewBufferString("clean notes"))
+		th.CheckOK(resp)
+
+		fileInfo, err := th.Server.App().GetFileInfo(file.FileID)
+		require.NoError(t, err)
+		require.Equal(t, "text/plain", fileInfo.MimeType)
+
+		data, resp := th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+		require.Equal(t, "clean notes", string(data))
+	})
+
+	t.Run("infected files are quarantined", func(t *testing.T) {
+		th := setupScan(t, nil, nil)
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "eicar.txt", bytes.NewBufferString(clamdtest.EICAR))
+		th.CheckBadRequest(resp)
+		require.Nil(t, file)
+
+		quarantineDir := filepath.Join(th.Server.Config().FilesPath, "quarantine")
+		require.Len(t, findFiles(t, quarantineDir, ".txt"), 1)
+		// the quarantined file is the only one left
+		require.Len(t, findFiles(t, th.Server.Config().FilesPath, ".txt"), 1)
+	})
+
+	t.Run("files are rejected if clamd is unreachable", func(t *testing.T) {
+		unreachable, err := clamdtest.NewServer()
+		require.NoError(t, err)
+		unreachable.Close()
+
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.FileScanDriver = filescan.DriverClamd
+			cfg.ClamdAddress = unreachable.Address()
+		}).InitBasic()
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		_, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "notes.txt", bytes.NewBufferString("clean notes"))
+		require.Error(t, resp.Error)
+
+		require.Empty(t, findFiles(t, th.Server.Config().FilesPath, ".txt"))
+	})
+
+	t.Run("denied file types are rejected", func(t *testing.T) {
+		th := setupScan(t, nil, []string{"text/html"})
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		// the type is detected from the content, not the name
+		_, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "page.txt", bytes.NewBufferString("<html><body>hi</body></html>"))
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "notes.txt", bytes.NewBufferString("clean notes"))
+		th.CheckOK(resp)
+	})
+
+	t.Run("only allowed file types are accepted", func(t *testing.T) {
+		th := setupScan(t, []string{"image/*"}, nil)
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		_, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "photo.png", bytes.NewReader(testPNG(t, 10, 10)))
+		th.CheckOK(resp)
+
+		_, resp = th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "photo.png", bytes.NewBufferString("not an image"))
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("risky files are always downloaded", func(t *testing.T) {
+		th := setupScan(t, nil, nil)
+		defer th.TearDown()
+		testBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		// an image named as a web page must not be rendered by the browser
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "page.html", bytes.NewReader(testPNG(t, 10, 10)))
+		th.CheckOK(resp)
+		_, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+		require.Contains(t, resp.Header.Get("Content-Disposition"), "attachment")
+
+		file, resp = th.Client.TeamUploadFileWithName(testTeamID, testBoard.ID, "photo.png", bytes.NewReader(testPNG(t, 10, 10)))
+		th.CheckOK(resp)
+		_, resp = th.Client.GetFile(testTeamID, testBoard.ID, file.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+		require.Contains(t, resp.Header.Get("Content-Disposition"), "inline")
+	})
+
+	require.NotZero(t, clamd.Scanned())
+}
@@ -0,0 +1,107 @@
+package integrationtests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileUsage(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	t.Run("the usage is tracked per team and board and the quota enforced", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.TeamStorageQuota = 150
+		}).InitBasic()
+		defer th.TearDown()
+
+		board1 := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		board2 := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		_, resp := th.Client.TeamUploadFileWithName(testTeamID, board1.ID, "a.txt", bytes.NewReader(bytes.Repeat([]byte("a"), 100)))
+		th.CheckOK(resp)
+		_, resp = th.Client.TeamUploadFileWithName(testTeamID, board2.ID, "b.txt", bytes.NewReader(bytes.Repeat([]byte("b"), 50)))
+		th.CheckOK(resp)
+
+		_, resp = th.Client.TeamUploadFileWithName(testTeamID, board2.ID, "c.txt", bytes.NewReader([]byte("c")))
+		th.CheckRequestEntityTooLarge(resp)
+		require.Contains(t, resp.Error.Error(), "storage quota")
+
+		usage, err := th.Server.App().GetFileUsage(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		require.Equal(t, int64(2), usage[0].Files)
+		require.Equal(t, int64(150), usage[0].Size)
+		require.Equal(t, int64(150), usage[0].Quota)
+		require.ElementsMatch(t, []*model.BoardFileUsage{
+			{BoardID: board1.ID, Files: 1, Size: 100},
+			{BoardID: board2.ID, Files: 1, Size: 50},
+		}, usage[0].Boards)
+
+		// the rejected file isn't kept in the storage
+		require.Len(t, findFiles(t, th.Server.Config().FilesPath, ".txt"), 2)
+	})
+
+	t.Run("the files not referenced by any block are cleaned up", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		referenced, resp := th.Client.TeamUploadFileWithName(testTeamID, board.ID, "photo.png", bytes.NewReader(testPNG(t, 10, 10)))
+		th.CheckOK(resp)
+		orphan, resp := th.Client.TeamUploadFileWithName(testTeamID, board.ID, "notes.txt", bytes.NewBufferString("no longer used"))
+		th.CheckOK(resp)
+
+		image := &model.Block{
+			ID:        utils.NewID(utils.IDTypeBlock),
+			ParentID:  board.ID,
+			BoardID:   board.ID,
+			Type:      model.TypeImage,
+			Fields:    map[string]interface{}{"fileId": referenced.FileID},
+			CreatedBy: th.GetUser1().ID,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{image}, false)
+		th.CheckOK(resp)
+
+		// recently uploaded files are kept, as their blocks may not exist yet
+		result, err := th.Server.App().CleanupOrphanedFiles()
+		require.NoError(t, err)
+		require.Zero(t, result.Files)
+
+		// simulate files uploaded before the grace period
+		usages, err := th.Server.Store().GetFileUsagesCreatedBefore(utils.GetMillis()+1, "", 10)
+		require.NoError(t, err)
+		require.Len(t, usages, 2)
+		for _, usage := range usages {
+			require.NoError(t, th.Server.Store().DeleteFileUsage(usage.FileID))
+			usage.CreateAt = 1
+			require.NoError(t, th.Server.Store().SaveFileUsage(usage))
+		}
+
+		result, err = th.Server.App().CleanupOrphanedFiles()
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Files)
+		require.Equal(t, int64(len("no longer used")), result.Size)
+
+		require.Empty(t, findFiles(t, th.Server.Config().FilesPath, ".txt"))
+		fileInfo, err := th.Server.App().GetFileInfo(orphan.FileID)
+		require.NoError(t, err)
+		require.NotZero(t, fileInfo.DeleteAt)
+
+		_, resp = th.Client.GetFile(testTeamID, board.ID, referenced.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+
+		usage, err := th.Server.App().GetFileUsage(testTeamID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), usage[0].Files)
+	})
+}
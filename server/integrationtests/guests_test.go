@@ -0,0 +1,134 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuests(t *testing.T) {
+	t.Run("guests only access the boards they are members of", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		publicBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client.AdminUpdateUserGuest(th.GetUser2().ID, true, 0)
+		th.CheckOK(resp)
+		require.True(t, th.Me(th.Client2).IsGuest)
+
+		// the guests can't be system admins
+		_, resp = th.Client.AdminUpdateUserRoles(th.GetUser2().ID, true)
+		th.CheckBadRequest(resp)
+
+		// without boards, the guest doesn't see anyone in the team
+		users, resp := th.Client2.SearchTeamUsers(testTeamID, "")
+		th.CheckOK(resp)
+		require.Empty(t, users)
+
+		member, resp := th.Client.AddMemberToBoard(&model.BoardMember{
+			BoardID:     privateBoard.ID,
+			UserID:      th.GetUser2().ID,
+			SchemeAdmin: true,
+		})
+		th.CheckOK(resp)
+		require.False(t, member.SchemeAdmin)
+
+		boards, resp := th.Client2.GetBoardsForTeam(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+		require.Equal(t, privateBoard.ID, boards[0].ID)
+
+		_, resp = th.Client2.GetBoard(publicBoard.ID, "")
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.CreateBoard(&model.Board{TeamID: testTeamID, Type: model.BoardTypePrivate})
+		th.CheckForbidden(resp)
+
+		users, resp = th.Client2.SearchTeamUsers(testTeamID, "")
+		th.CheckOK(resp)
+		require.Len(t, users, 2)
+	})
+
+	t.Run("expired guests can't use the server", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		user2ID := th.GetUser2().ID
+		_, resp := th.Client.AdminUpdateUserGuest(user2ID, true, utils.GetMillis()-1000)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.AdminUpdateUserGuest(user2ID, true, utils.GetMillis()+60*60*1000)
+		th.CheckOK(resp)
+		require.True(t, th.Me(th.Client2).IsGuest)
+
+		require.NoError(t, th.Server.Store().UpdateUserGuest(user2ID, true, utils.GetMillis()-1000))
+
+		_, resp = th.Client2.GetMe()
+		th.CheckUnauthorized(resp)
+
+		_, resp = th.Client2.Login(&model.LoginRequest{Type: "normal", Username: user2Username, Password: password})
+		th.CheckUnauthorized(resp)
+
+		// making the user a regular user again restores its access
+		_, resp = th.Client.AdminUpdateUserGuest(user2ID, false, 0)
+		th.CheckOK(resp)
+		th.Login2()
+		require.False(t, th.Me(th.Client2).IsGuest)
+	})
+
+	t.Run("guest invitations create guest accounts", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		expireAt := utils.GetMillis() + 60*60*1000
+
+		for _, invitation := range []*model.BoardInvitation{
+			{Token: "guest-invitation-token", Email: "guest@sample.com", Role: "admin", IsGuest: true, GuestExpireAt: expireAt},
+			{Token: "member-invitation-token", Email: "member@sample.com", Role: "editor"},
+		} {
+			invitation.BoardID = board.ID
+			invitation.CreatedBy = th.GetUser1().ID
+			invitation.CreatedAt = time.Now().Unix()
+			invitation.ExpiresAt = time.Now().Add(time.Hour).Unix()
+			require.NoError(t, th.Server.App().CreateBoardInvitation(invitation))
+		}
+
+		guestClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		// only the guest invitations create accounts
+		_, resp := guestClient.RegisterGuestInvitation("member-invitation-token", &model.RegisterRequest{Username: "member", Password: password})
+		th.CheckBadRequest(resp)
+
+		_, resp = guestClient.RegisterGuestInvitation("guest-invitation-token", &model.RegisterRequest{Username: "guest", Password: password})
+		th.CheckOK(resp)
+
+		_, resp = guestClient.RegisterGuestInvitation("guest-invitation-token", &model.RegisterRequest{Username: "guest2", Password: password})
+		th.CheckBadRequest(resp)
+
+		th.Login(guestClient, "guest", password)
+		me := th.Me(guestClient)
+		require.True(t, me.IsGuest)
+		require.Equal(t, expireAt, me.GuestExpireAt)
+		require.False(t, me.IsSystemAdmin())
+
+		_, resp = guestClient.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+
+		members, resp := th.Client.GetMembersForBoard(board.ID)
+		th.CheckOK(resp)
+		for _, member := range members {
+			if member.UserID == me.ID {
+				require.False(t, member.SchemeAdmin)
+				require.True(t, member.SchemeEditor)
+			}
+		}
+	})
+}
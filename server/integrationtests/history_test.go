@@ -0,0 +1,190 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockHistory(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	cardID := utils.NewID(utils.IDTypeBlock)
+	textID := utils.NewID(utils.IDTypeBlock)
+	newBlocks := []*model.Block{
+		{
+			ID:       cardID,
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+			Title:    "card v1",
+		},
+		{
+			ID:       textID,
+			BoardID:  board.ID,
+			ParentID: cardID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeText,
+			Title:    "text v1",
+		},
+	}
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, newBlocks, false)
+	require.NoError(t, resp.Error)
+	require.Len(t, newBlocks, 2)
+	cardID = newBlocks[0].ID
+	textID = newBlocks[1].ID
+
+	// the blocks may be saved on different milliseconds, so the first
+	// revision of the card is taken after all of them
+	time.Sleep(10 * time.Millisecond)
+	firstRevision := utils.GetMillis()
+	time.Sleep(10 * time.Millisecond)
+
+	_, resp = th.Client.PatchBlock(board.ID, cardID, &model.BlockPatch{Title: mmModel.NewString("card v2")}, false)
+	require.NoError(t, resp.Error)
+	_, resp = th.Client.PatchBlock(board.ID, textID, &model.BlockPatch{Title: mmModel.NewString("text v2")}, false)
+	require.NoError(t, resp.Error)
+	extraBlocks, resp := th.Client.InsertBlocks(board.ID, []*model.Block{{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: cardID,
+		CreateAt: 1,
+		UpdateAt: 1,
+		Type:     model.TypeText,
+		Title:    "extra",
+	}}, false)
+	require.NoError(t, resp.Error)
+	require.Len(t, extraBlocks, 1)
+	extraID := extraBlocks[0].ID
+
+	t.Run("list the revisions of a block", func(t *testing.T) {
+		history, resp := th.Client.GetBlockHistory(board.ID, cardID, 0, 0)
+		require.NoError(t, resp.Error)
+		require.Len(t, history, 2)
+		require.Equal(t, "card v2", history[0].Title)
+		require.Equal(t, "card v1", history[1].Title)
+		require.Equal(t, th.GetUser1().ID, history[0].ModifiedBy)
+
+		history, resp = th.Client.GetBlockHistory(board.ID, cardID, 0, 1)
+		require.NoError(t, resp.Error)
+		require.Len(t, history, 1)
+	})
+
+	t.Run("diff two revisions of a card", func(t *testing.T) {
+		diff, resp := th.Client.GetBlockHistoryDiff(board.ID, cardID, firstRevision, 0)
+		require.NoError(t, resp.Error)
+		require.Equal(t, "card v1", diff.OldBlock.Title)
+		require.Equal(t, "card v2", diff.NewBlock.Title)
+		require.Len(t, diff.Children, 2)
+
+		for _, child := range diff.Children {
+			switch child.BlockID {
+			case textID:
+				require.Equal(t, "text v1", child.OldBlock.Title)
+				require.Equal(t, "text v2", child.NewBlock.Title)
+			case extraID:
+				require.Nil(t, child.OldBlock)
+				require.Equal(t, "extra", child.NewBlock.Title)
+			default:
+				require.Fail(t, "unexpected child diff", child.BlockID)
+			}
+		}
+	})
+
+	t.Run("a block from another board should not be found", func(t *testing.T) {
+		otherBoard := th.CreateBoard("team-id", model.BoardTypeOpen)
+		_, resp := th.Client.GetBlockHistory(otherBoard.ID, cardID, 0, 0)
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("restore a card and its contents", func(t *testing.T) {
+		request := &model.HistoryRestoreRequest{UpdateAt: firstRevision, IncludeChildren: true}
+		result, resp := th.Client.RestoreBlockHistory(board.ID, cardID, request)
+		require.NoError(t, resp.Error)
+		require.Len(t, result.Blocks, 2)
+		require.Equal(t, []string{extraID}, result.DeletedBlockIDs)
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		require.NoError(t, resp.Error)
+		require.Len(t, blocks, 2)
+		for _, block := range blocks {
+			switch block.ID {
+			case cardID:
+				require.Equal(t, "card v1", block.Title)
+			case textID:
+				require.Equal(t, "text v1", block.Title)
+			}
+		}
+
+		// the restore is recorded as a new revision
+		history, resp := th.Client.GetBlockHistory(board.ID, cardID, 0, 0)
+		require.NoError(t, resp.Error)
+		require.Len(t, history, 3)
+		require.Equal(t, "card v1", history[0].Title)
+	})
+
+	t.Run("restoring to a time before the block existed should fail", func(t *testing.T) {
+		request := &model.HistoryRestoreRequest{UpdateAt: board.CreateAt - 1000}
+		_, resp := th.Client.RestoreBlockHistory(board.ID, cardID, request)
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+}
+
+func TestBoardHistory(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	history, resp := th.Client.GetBoardHistory(board.ID, 0, 0)
+	require.NoError(t, resp.Error)
+	require.Len(t, history, 1)
+	firstRevision := history[0].UpdateAt
+
+	time.Sleep(10 * time.Millisecond)
+
+	_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: mmModel.NewString("new title")})
+	require.NoError(t, resp.Error)
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, []*model.Block{{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		CreateAt: 1,
+		UpdateAt: 1,
+		Type:     model.TypeCard,
+		Title:    "new card",
+	}}, false)
+	require.NoError(t, resp.Error)
+	require.Len(t, newBlocks, 1)
+
+	history, resp = th.Client.GetBoardHistory(board.ID, 0, 0)
+	require.NoError(t, resp.Error)
+	require.Len(t, history, 2)
+	require.Equal(t, "new title", history[0].Title)
+
+	result, resp := th.Client.RestoreBoardHistory(board.ID, &model.HistoryRestoreRequest{UpdateAt: firstRevision})
+	require.NoError(t, resp.Error)
+	require.Equal(t, board.Title, result.Board.Title)
+	require.Empty(t, result.Blocks)
+	require.Equal(t, []string{newBlocks[0].ID}, result.DeletedBlockIDs)
+
+	restoredBoard, resp := th.Client.GetBoard(board.ID, "")
+	require.NoError(t, resp.Error)
+	require.Equal(t, board.Title, restoredBoard.Title)
+
+	blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+	require.NoError(t, resp.Error)
+	require.Empty(t, blocks)
+}
@@ -0,0 +1,82 @@
+package integrationtests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const trelloExport = `{
+  "name": "Imported board",
+  "lists": [{"id": "l1", "name": "To Do", "pos": 1}],
+  "cards": [
+    {"id": "c1", "name": "Card 1", "desc": "Description", "idList": "l1", "pos": 1},
+    {"id": "c2", "name": "Card 2", "idList": "l1", "pos": 2, "closed": true}
+  ],
+  "actions": [
+    {"type": "commentCard", "date": "2022-01-01T10:00:00.000Z",
+     "memberCreator": {"fullName": "Jane Doe"}, "data": {"text": "A comment", "card": {"id": "c1"}}}
+  ]
+}`
+
+func TestImportExternal(t *testing.T) {
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		th.Logout(th.Client)
+
+		report, resp := th.Client.ImportExternal("team-id", "trello", "export.json", strings.NewReader(trelloExport))
+		th.CheckUnauthorized(resp)
+		require.Nil(t, report)
+	})
+
+	t.Run("import a Trello export", func(t *testing.T) {
+		th := SetupTestHelperWithToken(t).Start()
+		defer th.TearDown()
+
+		report, resp := th.Client.ImportExternal("team-id", "trello", "export.json", strings.NewReader(trelloExport))
+		th.CheckOK(resp)
+		require.NotNil(t, report)
+		require.Equal(t, "trello", report.Source)
+		require.Len(t, report.BoardIDs, 1)
+		require.Equal(t, 1, report.Cards)
+		require.Equal(t, 1, report.Comments)
+		require.Len(t, report.Skipped, 1)
+		require.Equal(t, "Card 2", report.Skipped[0].Name)
+
+		board, resp := th.Client.GetBoard(report.BoardIDs[0], "")
+		th.CheckOK(resp)
+		require.Equal(t, "Imported board", board.Title)
+		require.Equal(t, "team-id", board.TeamID)
+		require.Equal(t, model.BoardTypePrivate, board.Type)
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		titles := []string{}
+		for _, block := range blocks {
+			titles = append(titles, block.Title)
+		}
+		require.Contains(t, titles, "Card 1")
+		require.Contains(t, titles, "Description")
+		require.Contains(t, titles, "**Jane Doe**: A comment")
+	})
+
+	t.Run("an unsupported source should fail", func(t *testing.T) {
+		th := SetupTestHelperWithToken(t).Start()
+		defer th.TearDown()
+
+		_, resp := th.Client.ImportExternal("team-id", "monday", "export.json", strings.NewReader(trelloExport))
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("an invalid export should fail", func(t *testing.T) {
+		th := SetupTestHelperWithToken(t).Start()
+		defer th.TearDown()
+
+		_, resp := th.Client.ImportExternal("team-id", "trello", "export.json", strings.NewReader("not json"))
+		th.CheckBadRequest(resp)
+	})
+}
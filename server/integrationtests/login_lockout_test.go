@@ -0,0 +1,188 @@
+package integrationtests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loginWithPassword(c *client.Client, username, password string) *client.Response {
+	_, resp := c.Login(&model.LoginRequest{
+		Type:     "normal",
+		Username: username,
+		Password: password,
+	})
+	return resp
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Run("an account is locked out after too many failed logins", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 3
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		for i := 0; i < 2; i++ {
+			th.CheckUnauthorized(loginWithPassword(otherClient, user2Username, "wrong-password"))
+		}
+		resp := loginWithPassword(otherClient, user2Username, "wrong-password")
+		th.CheckTooManyRequests(resp)
+		require.Equal(t, "60", resp.Header.Get("Retry-After"))
+
+		// the correct password is refused too until the lockout ends
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, password))
+
+		// the other accounts aren't affected
+		th.CheckOK(loginWithPassword(otherClient, user1Username, password))
+	})
+
+	t.Run("unknown accounts are locked out the same way", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 2
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckUnauthorized(loginWithPassword(otherClient, "nonexistent-user", "wrong-password"))
+		th.CheckTooManyRequests(loginWithPassword(otherClient, "nonexistent-user", "wrong-password"))
+	})
+
+	t.Run("a successful login resets the failed attempts", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 3
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		for i := 0; i < 2; i++ {
+			th.CheckUnauthorized(loginWithPassword(otherClient, user2Username, "wrong-password"))
+		}
+		th.CheckOK(loginWithPassword(otherClient, user2Username, password))
+		for i := 0; i < 2; i++ {
+			th.CheckUnauthorized(loginWithPassword(otherClient, user2Username, "wrong-password"))
+		}
+	})
+
+	t.Run("a client IP address is locked out after too many failed logins", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttemptsPerIP = 3
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckUnauthorized(loginWithPassword(otherClient, "nonexistent-user1", "wrong-password"))
+		th.CheckUnauthorized(loginWithPassword(otherClient, "nonexistent-user2", "wrong-password"))
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, "wrong-password"))
+
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user1Username, password))
+	})
+
+	t.Run("the logins are delayed after a failure", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginFailureDelaySeconds = 30
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckUnauthorized(loginWithPassword(otherClient, user2Username, "wrong-password"))
+
+		resp := loginWithPassword(otherClient, user2Username, password)
+		th.CheckTooManyRequests(resp)
+		require.Equal(t, "30", resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("a system admin unlocks an account", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 1
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, "wrong-password"))
+
+		_, resp := th.Client2.AdminUnlockUser(th.GetUser2().ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.AdminUnlockUser(th.GetUser2().ID)
+		th.CheckOK(resp)
+		th.CheckOK(loginWithPassword(otherClient, user2Username, password))
+
+		_, resp = th.Client.AdminUnlockUser("nonexistent-user")
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("an account is unlocked with the emailed token", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 1
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, "wrong-password"))
+
+		// email isn't configured in the tests, so the token is set directly
+		token := utils.NewID(utils.IDTypeToken)
+		lockedUntil := utils.GetMillis() + 60*1000
+		require.NoError(t, th.Server.Store().LockLoginAttempt(model.LoginAttemptKindUser, th.GetUser2().ID, lockedUntil, token))
+
+		_, resp := otherClient.UnlockAccount("invalid-token")
+		th.CheckNotFound(resp)
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, password))
+
+		_, resp = otherClient.UnlockAccount(token)
+		th.CheckOK(resp)
+		th.CheckOK(loginWithPassword(otherClient, user2Username, password))
+
+		// the token can only be used once
+		_, resp = otherClient.UnlockAccount(token)
+		th.CheckNotFound(resp)
+	})
+	t.Run("the account can't be unlocked by opening a link", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.LoginMaxFailedAttempts = 1
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, "wrong-password"))
+
+		token := utils.NewID(utils.IDTypeToken)
+		lockedUntil := utils.GetMillis() + 60*1000
+		require.NoError(t, th.Server.Store().LockLoginAttempt(model.LoginAttemptKindUser, th.GetUser2().ID, lockedUntil, token))
+
+		// the mail scanners may open the links of the emails, the GET
+		// requests are left to the webapp
+		resp, err := http.Get(th.Server.Config().ServerRoot + "/api/v2/login/unlock?token=" + token)
+		require.NoError(t, err)
+		resp.Body.Close()
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, password))
+
+		// and the requests of other sites have no CSRF header
+		body := strings.NewReader(`{"token": "` + token + `"}`)
+		resp, err = http.Post(th.Server.Config().ServerRoot+"/api/v2/login/unlock", "application/json", body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		th.CheckTooManyRequests(loginWithPassword(otherClient, user2Username, password))
+
+		_, clientResp := otherClient.UnlockAccount(token)
+		th.CheckOK(clientResp)
+		th.CheckOK(loginWithPassword(otherClient, user2Username, password))
+	})
+}
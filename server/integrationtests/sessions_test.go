@@ -0,0 +1,130 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/client"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	t.Run("a user lists its sessions", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.Login(otherClient, user2Username, password)
+
+		sessions, resp := th.Client2.GetMySessions()
+		th.CheckOK(resp)
+		require.Len(t, sessions, 2)
+
+		current := 0
+		for _, session := range sessions {
+			require.NotEmpty(t, session.ID)
+			require.NotEmpty(t, session.UserAgent)
+			require.Equal(t, "127.0.0.1", session.IPAddress)
+			require.NotZero(t, session.CreateAt)
+			require.NotZero(t, session.LastActivityAt)
+			if session.Current {
+				current++
+			}
+		}
+		require.Equal(t, 1, current)
+
+		th.Logout(th.Client2)
+		_, resp = th.Client2.GetMySessions()
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("a user revokes one of its sessions", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.Login(otherClient, user2Username, password)
+
+		sessions, resp := otherClient.GetMySessions()
+		th.CheckOK(resp)
+		var otherSessionID string
+		for _, session := range sessions {
+			if session.Current {
+				otherSessionID = session.ID
+			}
+		}
+		require.NotEmpty(t, otherSessionID)
+
+		// the sessions of the other users can't be revoked
+		_, resp = th.Client.RevokeMySession(otherSessionID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client2.RevokeMySession(otherSessionID)
+		th.CheckOK(resp)
+		_, resp = otherClient.GetMe()
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client2.GetMe()
+		th.CheckOK(resp)
+	})
+
+	t.Run("a user revokes all its other sessions", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		otherClients := []*client.Client{}
+		for i := 0; i < 2; i++ {
+			otherClient := client.NewClient(th.Server.Config().ServerRoot, "")
+			th.Login(otherClient, user2Username, password)
+			otherClients = append(otherClients, otherClient)
+		}
+
+		_, resp := th.Client2.RevokeMyOtherSessions()
+		th.CheckOK(resp)
+
+		for _, otherClient := range otherClients {
+			_, resp = otherClient.GetMe()
+			th.CheckUnauthorized(resp)
+		}
+		sessions, resp := th.Client2.GetMySessions()
+		th.CheckOK(resp)
+		require.Len(t, sessions, 1)
+		require.True(t, sessions[0].Current)
+
+		// the sessions of the other users are kept
+		_, resp = th.Client.GetMe()
+		th.CheckOK(resp)
+	})
+
+	t.Run("a system admin revokes all the sessions of a user", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client2.AdminRevokeUserSessions(th.GetUser1().ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.AdminRevokeUserSessions(th.GetUser2().ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetMe()
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client.GetMe()
+		th.CheckOK(resp)
+
+		// the user can log in again
+		th.Login2()
+		_, resp = th.Client2.GetMe()
+		th.CheckOK(resp)
+
+		_, resp = th.Client.AdminRevokeUserSessions("nonexistent-user")
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("the sessions aren't managed in single-user mode", func(t *testing.T) {
+		th := SetupTestHelperWithToken(t).Start()
+		defer th.TearDown()
+
+		_, resp := th.Client.GetMySessions()
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client.RevokeMyOtherSessions()
+		th.CheckUnauthorized(resp)
+	})
+}
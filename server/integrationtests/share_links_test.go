@@ -0,0 +1,333 @@
+package integrationtests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinks(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	setupShareLinksHelper := func(t *testing.T) *TestHelper {
+		return SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.EnablePublicSharedBoards = true
+		}).InitBasic()
+	}
+
+	setupSharedBoard := func(t *testing.T, th *TestHelper) (*model.Board, *model.Block, *model.Block, *model.Block) {
+		board, err := th.Server.App().CreateBoard(&model.Board{
+			Title:  "Roadmap",
+			Type:   model.BoardTypePrivate,
+			TeamID: testTeamID,
+			CardProperties: []map[string]interface{}{
+				{"id": "status", "name": "Status", "type": "select"},
+				{"id": "estimate", "name": "Estimate", "type": "number"},
+			},
+		}, th.GetUser1().ID, true)
+		require.NoError(t, err)
+
+		now := utils.GetMillis()
+		blocks := []*model.Block{
+			{
+				ID:       "view",
+				BoardID:  board.ID,
+				ParentID: board.ID,
+				Type:     model.TypeView,
+				Fields: map[string]interface{}{
+					"visiblePropertyIds": []interface{}{"status", "estimate"},
+					"filter": map[string]interface{}{
+						"operation": "and",
+						"filters": []interface{}{
+							map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"done"}},
+						},
+					},
+				},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       "done-card",
+				BoardID:  board.ID,
+				ParentID: board.ID,
+				Type:     model.TypeCard,
+				Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "done", "estimate": "3"}},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       "todo-card",
+				BoardID:  board.ID,
+				ParentID: board.ID,
+				Type:     model.TypeCard,
+				Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "todo"}},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       "done-text",
+				BoardID:  board.ID,
+				ParentID: "done-card",
+				Type:     model.TypeText,
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       "todo-text",
+				BoardID:  board.ID,
+				ParentID: "todo-card",
+				Type:     model.TypeText,
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		}
+		newBlocks, resp := th.Client.InsertBlocks(board.ID, blocks, false)
+		th.CheckOK(resp)
+		require.Len(t, newBlocks, 5)
+		return board, newBlocks[0], newBlocks[1], newBlocks[3]
+	}
+
+	t.Run("a board is shared by several links", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, _, _, _ := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		link1, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, Name: "team"})
+		th.CheckOK(resp)
+		link2, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, Name: "customers"})
+		th.CheckOK(resp)
+		require.NotEqual(t, link1.Token, link2.Token)
+
+		sharedBoard, resp := anonClient.GetBoard(board.ID, link1.Token)
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, sharedBoard.ID)
+		blocks, resp := anonClient.GetAllBlocksForBoardWithReadToken(board.ID, link1.Token)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 5)
+
+		links, resp := th.Client.GetShareLinks(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, links, 2)
+		require.Equal(t, int64(1), links[0].ViewCount)
+		require.Equal(t, int64(0), links[1].ViewCount)
+
+		// revoking a link leaves the other one working
+		_, resp = th.Client.DeleteShareLink(board.ID, link1.ID)
+		th.CheckOK(resp)
+
+		_, resp = anonClient.GetBoard(board.ID, link1.Token)
+		th.CheckUnauthorized(resp)
+		_, resp = anonClient.GetBoard(board.ID, link2.Token)
+		th.CheckOK(resp)
+
+		// a link doesn't open other boards
+		otherBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		_, resp = anonClient.GetBoard(otherBoard.ID, link2.Token)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("a link restricted to a view only shares its cards", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, view, doneCard, doneText := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		link, resp := th.Client.CreateShareLink(&model.ShareLink{
+			BoardID:          board.ID,
+			ViewID:           view.ID,
+			HiddenProperties: []string{"estimate"},
+		})
+		th.CheckOK(resp)
+
+		sharedBoard, resp := anonClient.GetBoard(board.ID, link.Token)
+		th.CheckOK(resp)
+		require.Len(t, sharedBoard.CardProperties, 1)
+		require.Equal(t, "status", sharedBoard.CardProperties[0]["id"])
+
+		blocks, resp := anonClient.GetAllBlocksForBoardWithReadToken(board.ID, link.Token)
+		th.CheckOK(resp)
+		blockIDs := []string{}
+		for _, block := range blocks {
+			blockIDs = append(blockIDs, block.ID)
+			if block.ID == doneCard.ID {
+				require.Equal(t, map[string]interface{}{"status": "done"}, block.Fields["properties"])
+			}
+			if block.ID == view.ID {
+				require.Equal(t, []interface{}{"status"}, block.Fields["visiblePropertyIds"])
+			}
+		}
+		require.ElementsMatch(t, []string{view.ID, doneCard.ID, doneText.ID}, blockIDs)
+	})
+
+	t.Run("a link restricted to a view only shares the files of its cards", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, view, doneCard, _ := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		doneFile, resp := th.Client.TeamUploadFile(testTeamID, board.ID, bytes.NewBuffer([]byte("done")))
+		th.CheckOK(resp)
+		todoFile, resp := th.Client.TeamUploadFile(testTeamID, board.ID, bytes.NewBuffer([]byte("todo")))
+		th.CheckOK(resp)
+
+		now := utils.GetMillis()
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{
+			{
+				ID:       "done-image",
+				BoardID:  board.ID,
+				ParentID: doneCard.ID,
+				Type:     model.TypeImage,
+				Fields:   map[string]interface{}{"fileId": doneFile.FileID},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+			{
+				ID:       "todo-attachment",
+				BoardID:  board.ID,
+				ParentID: "todo-card",
+				Type:     model.TypeAttachment,
+				Fields:   map[string]interface{}{"attachmentId": todoFile.FileID},
+				CreateAt: now,
+				UpdateAt: now,
+			},
+		}, false)
+		th.CheckOK(resp)
+
+		viewLink, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, ViewID: view.ID})
+		th.CheckOK(resp)
+		data, resp := anonClient.GetFileWithReadToken(testTeamID, board.ID, doneFile.FileID, viewLink.Token)
+		th.CheckOK(resp)
+		require.Equal(t, []byte("done"), data)
+		_, resp = anonClient.GetFileWithReadToken(testTeamID, board.ID, todoFile.FileID, viewLink.Token)
+		th.CheckNotFound(resp)
+
+		boardLink, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID})
+		th.CheckOK(resp)
+		data, resp = anonClient.GetFileWithReadToken(testTeamID, board.ID, todoFile.FileID, boardLink.Token)
+		th.CheckOK(resp)
+		require.Equal(t, []byte("todo"), data)
+	})
+
+	t.Run("a link can only be restricted to a view of the board", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, _, doneCard, _ := setupSharedBoard(t, th)
+
+		_, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, ViewID: doneCard.ID})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("password protected links", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, _, _, _ := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		link, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, Password: "secret"})
+		th.CheckOK(resp)
+		require.True(t, link.HasPassword)
+		require.Empty(t, link.Password)
+
+		_, resp = anonClient.GetBoard(board.ID, link.Token)
+		th.CheckUnauthorized(resp)
+
+		_, resp = anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: link.Token, Password: "wrong"})
+		th.CheckUnauthorized(resp)
+
+		unlocked, resp := anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: link.Token, Password: "secret"})
+		th.CheckOK(resp)
+		_, resp = anonClient.GetBoard(board.ID, unlocked.ReadToken)
+		th.CheckOK(resp)
+
+		// changing the password invalidates the unlocked tokens
+		newPassword := "new-secret"
+		_, resp = th.Client.PatchShareLink(board.ID, link.ID, &model.ShareLinkPatch{Password: &newPassword})
+		th.CheckOK(resp)
+		_, resp = anonClient.GetBoard(board.ID, unlocked.ReadToken)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("the link password attempts are throttled", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.EnablePublicSharedBoards = true
+			cfg.LoginMaxFailedAttempts = 2
+			cfg.LoginLockoutSeconds = 60
+		}).InitBasic()
+		defer th.TearDown()
+		board, _, _, _ := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		link, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, Password: "secret"})
+		th.CheckOK(resp)
+		otherLink, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, Password: "secret"})
+		th.CheckOK(resp)
+
+		_, resp = anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: link.Token, Password: "wrong"})
+		th.CheckUnauthorized(resp)
+		_, resp = anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: link.Token, Password: "wrong"})
+		th.CheckTooManyRequests(resp)
+		require.Equal(t, "60", resp.Header.Get("Retry-After"))
+
+		// the correct password is refused too until the lockout ends
+		_, resp = anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: link.Token, Password: "secret"})
+		th.CheckTooManyRequests(resp)
+
+		// the other links aren't affected
+		_, resp = anonClient.UnlockShareLink(board.ID, &model.ShareLinkUnlockRequest{Token: otherLink.Token, Password: "secret"})
+		th.CheckOK(resp)
+	})
+
+	t.Run("expired links", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, _, _, _ := setupSharedBoard(t, th)
+		anonClient := client.NewClient(th.Server.Config().ServerRoot, "")
+
+		link, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID, ExpiresAt: utils.GetMillis() - 1})
+		th.CheckOK(resp)
+
+		_, resp = anonClient.GetBoard(board.ID, link.Token)
+		th.CheckUnauthorized(resp)
+
+		// extending the expiry reopens the link
+		expiresAt := utils.GetMillis() + 60*1000
+		_, resp = th.Client.PatchShareLink(board.ID, link.ID, &model.ShareLinkPatch{ExpiresAt: &expiresAt})
+		th.CheckOK(resp)
+		_, resp = anonClient.GetBoard(board.ID, link.Token)
+		th.CheckOK(resp)
+	})
+
+	t.Run("share links require public shared boards", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID})
+		th.CheckNotImplemented(resp)
+	})
+
+	t.Run("only the board admins manage the links", func(t *testing.T) {
+		th := setupShareLinksHelper(t)
+		defer th.TearDown()
+		board, _, _, _ := setupSharedBoard(t, th)
+
+		link, resp := th.Client.CreateShareLink(&model.ShareLink{BoardID: board.ID})
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.GetShareLinks(board.ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.CreateShareLink(&model.ShareLink{BoardID: board.ID})
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.DeleteShareLink(board.ID, link.ID)
+		th.CheckForbidden(resp)
+	})
+}
@@ -0,0 +1,89 @@
+package integrationtests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedFileURL(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	download := func(t *testing.T, fileURL string) (int, []byte) {
+		resp, err := http.Get(fileURL) //nolint:gosec
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, data
+	}
+
+	t.Run("a file is downloaded without a session", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.Secret = "test-secret"
+		}).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		content := []byte("meeting notes")
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, board.ID, "notes.txt", bytes.NewReader(content))
+		th.CheckOK(resp)
+
+		signedURL, resp := th.Client.GetSignedFileURL(testTeamID, board.ID, file.FileID)
+		th.CheckOK(resp)
+		require.Greater(t, signedURL.ExpiresAt, int64(0))
+
+		status, data := download(t, signedURL.URL)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, content, data)
+
+		// the signature covers the user and the file
+		parsed, err := url.Parse(signedURL.URL)
+		require.NoError(t, err)
+		query := parsed.Query()
+		query.Set(model.SignedURLUserParam, th.GetUser2().ID)
+		parsed.RawQuery = query.Encode()
+		status, _ = download(t, parsed.String())
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		otherFile, resp := th.Client.TeamUploadFileWithName(testTeamID, board.ID, "other.txt", bytes.NewReader(content))
+		th.CheckOK(resp)
+		parsed, err = url.Parse(signedURL.URL)
+		require.NoError(t, err)
+		parsed.Path = "/api/v2/files/teams/" + testTeamID + "/" + board.ID + "/" + otherFile.FileID
+		status, _ = download(t, parsed.String())
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("only the users with access to the board get signed URLs", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.Secret = "test-secret"
+		}).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		file, resp := th.Client.TeamUploadFileWithName(testTeamID, board.ID, "notes.txt", bytes.NewBufferString("private"))
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.GetSignedFileURL(testTeamID, board.ID, file.FileID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("signed URLs require the server secret", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client.GetSignedFileURL(testTeamID, board.ID, "7file.txt")
+		th.CheckNotImplemented(resp)
+	})
+}
@@ -0,0 +1,165 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemAdmin(t *testing.T) {
+	t.Run("the first registered user is a system admin", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		me := th.Me(th.Client)
+		require.True(t, me.IsSystemAdmin())
+		require.False(t, th.Me(th.Client2).IsSystemAdmin())
+
+		users, resp := th.Client.GetAdminUsers(0, 100, false)
+		th.CheckOK(resp)
+		require.Len(t, users, 2)
+
+		stats, resp := th.Client.GetSystemStatistics()
+		th.CheckOK(resp)
+		require.Equal(t, 2, stats.Users)
+	})
+
+	t.Run("the other users can't administer the server", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client2.GetAdminUsers(0, 100, false)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.GetSystemStatistics()
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.AdminUpdateUserRoles(th.GetUser2().ID, true)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.AdminSetUserPassword(user1Username, "new-password")
+		th.CheckForbidden(resp)
+
+		th.Logout(th.Client2)
+		_, resp = th.Client2.GetAdminUsers(0, 100, false)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("a system admin promotes other admins", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client.AdminUpdateUserRoles(th.GetUser2().ID, true)
+		th.CheckOK(resp)
+		require.True(t, th.Me(th.Client2).IsSystemAdmin())
+
+		_, resp = th.Client2.GetSystemStatistics()
+		th.CheckOK(resp)
+
+		// an admin can step down as long as another admin remains
+		_, resp = th.Client2.AdminUpdateUserRoles(th.GetUser1().ID, false)
+		th.CheckOK(resp)
+		_, resp = th.Client2.AdminUpdateUserRoles(th.GetUser2().ID, false)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("a system admin deactivates and reactivates users", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		user2 := th.GetUser2()
+
+		_, resp := th.Client.AdminUpdateUserActive(user2.ID, false)
+		th.CheckOK(resp)
+
+		// the sessions of the user are revoked and it can't log in anymore
+		_, resp = th.Client2.GetMe()
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client2.Login(&model.LoginRequest{Type: "normal", Username: user2Username, Password: password})
+		th.CheckUnauthorized(resp)
+
+		users, resp := th.Client.GetAdminUsers(0, 100, false)
+		th.CheckOK(resp)
+		require.Len(t, users, 1)
+		users, resp = th.Client.GetAdminUsers(0, 100, true)
+		th.CheckOK(resp)
+		require.Len(t, users, 2)
+
+		_, resp = th.Client.AdminUpdateUserActive(user2.ID, true)
+		th.CheckOK(resp)
+		th.Login2()
+
+		// the admins can't lock themselves out
+		_, resp = th.Client.AdminUpdateUserActive(th.GetUser1().ID, false)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("a system admin deletes users keeping their content", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		user2 := th.GetUser2()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client.AddMemberToBoard(&model.BoardMember{BoardID: board.ID, UserID: user2.ID, SchemeEditor: true})
+		th.CheckOK(resp)
+		card, resp := th.Client2.CreateCard(board.ID, &model.Card{Title: "card of user2"}, true)
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.AdminDeleteUser(th.GetUser1().ID)
+		th.CheckForbidden(resp)
+
+		// the admins can't delete themselves
+		_, resp = th.Client.AdminDeleteUser(th.GetUser1().ID)
+		th.CheckBadRequest(resp)
+
+		report, resp := th.Client.AdminDeleteUser(user2.ID)
+		th.CheckOK(resp)
+		require.Equal(t, user2.ID, report.UserID)
+		require.Equal(t, th.GetUser1().ID, report.DeletedBy)
+		require.Equal(t, int64(1), report.Removed["board_members"])
+		require.NotZero(t, report.Removed["sessions"])
+
+		_, resp = th.Client2.GetMe()
+		th.CheckUnauthorized(resp)
+		_, resp = th.Client2.Login(&model.LoginRequest{Type: "normal", Username: user2Username, Password: password})
+		th.CheckUnauthorized(resp)
+
+		// the card is kept, attributed to the anonymous user
+		gotCard, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "card of user2", gotCard.Title)
+		require.Equal(t, report.AnonymousID, gotCard.CreatedBy)
+
+		users, resp := th.Client.GetAdminUsers(0, 100, true)
+		th.CheckOK(resp)
+		require.Len(t, users, 2)
+		for _, user := range users {
+			require.NotEqual(t, user2.ID, user.ID)
+			require.NotEqual(t, user2Username, user.Username)
+		}
+
+		_, resp = th.Client.AdminDeleteUser(user2.ID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("a system admin resets passwords", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client.AdminSetUserPassword(user2Username, "new-password")
+		th.CheckOK(resp)
+		th.Login(th.Client2, user2Username, "new-password")
+	})
+
+	t.Run("the configured users are system admins", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.SystemAdminUsernames = []string{user2Username}
+		}).InitBasic()
+		defer th.TearDown()
+
+		// registering with a configured username doesn't make an admin,
+		// the configured users are promoted when the server starts
+		require.False(t, th.Me(th.Client2).IsSystemAdmin())
+		require.NoError(t, th.Server.App().EnsureSystemAdmins())
+		require.True(t, th.Me(th.Client2).IsSystemAdmin())
+	})
+}
@@ -0,0 +1,255 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/client"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupTeam creates a team administered by user1, with user2 as a member,
+// and registers a third user outside of the team. It returns the team and
+// the client of the outsider.
+func setupTeam(th *TestHelper) (*model.Team, *client.Client) {
+	team, resp := th.Client.CreateTeam(&model.Team{Title: "Marketing"})
+	th.CheckOK(resp)
+	_, resp = th.Client.AddTeamMember(&model.TeamMember{TeamID: team.ID, UserID: th.GetUser2().ID})
+	th.CheckOK(resp)
+
+	rootTeam, resp := th.Client.GetTeam(model.GlobalTeamID)
+	th.CheckOK(resp)
+	outsider := client.NewClient(th.Server.Config().ServerRoot, "")
+	th.RegisterAndLogin(outsider, "outsider", "outsider@sample.com", password, rootTeam.SignupToken)
+
+	return team, outsider
+}
+
+func TestTeams(t *testing.T) {
+	t.Run("a system admin creates a team and becomes its admin", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, resp := th.Client.CreateTeam(&model.Team{Title: "Marketing"})
+		th.CheckOK(resp)
+		require.NotEmpty(t, team.ID)
+		require.Equal(t, "Marketing", team.Title)
+		require.NotEmpty(t, team.SignupToken)
+
+		members, resp := th.Client.GetTeamMembers(team.ID)
+		th.CheckOK(resp)
+		require.Len(t, members, 1)
+		require.Equal(t, th.GetUser1().ID, members[0].UserID)
+		require.True(t, members[0].SchemeAdmin)
+
+		_, resp = th.Client.CreateTeam(&model.Team{Title: ""})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("the other users can't create teams", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, resp := th.Client2.CreateTeam(&model.Team{Title: "Marketing"})
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("the callers outside of a team are refused", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, outsider := setupTeam(th)
+		board := th.CreateBoard(team.ID, model.BoardTypeOpen)
+		title := "Sales"
+
+		_, resp := outsider.GetTeam(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.PatchTeam(team.ID, &model.TeamPatch{Title: &title})
+		th.CheckForbidden(resp)
+		_, resp = outsider.DeleteTeam(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.RegenerateTeamSignupToken(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.GetTeamMembers(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.AddTeamMember(&model.TeamMember{TeamID: team.ID, UserID: outsider.GetUserID()})
+		th.CheckForbidden(resp)
+		_, resp = outsider.UpdateTeamMember(&model.TeamMember{TeamID: team.ID, UserID: th.GetUser2().ID, SchemeAdmin: true})
+		th.CheckForbidden(resp)
+		_, resp = outsider.RemoveTeamMember(team.ID, th.GetUser2().ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.SearchTeamUsers(team.ID, "")
+		th.CheckForbidden(resp)
+		_, resp = outsider.GetBoardsForTeam(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.GetTemplatesForTeam(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = outsider.GetBoard(board.ID, "")
+		th.CheckForbidden(resp)
+
+		teams, resp := outsider.GetTeams()
+		th.CheckOK(resp)
+		require.Len(t, teams, 1)
+		require.Equal(t, model.GlobalTeamID, teams[0].ID)
+
+		// the team is unchanged
+		members, resp := th.Client.GetTeamMembers(team.ID)
+		th.CheckOK(resp)
+		require.Len(t, members, 2)
+		checkedTeam, resp := th.Client.GetTeam(team.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "Marketing", checkedTeam.Title)
+		require.Equal(t, team.SignupToken, checkedTeam.SignupToken)
+	})
+
+	t.Run("the teams without members are open to every user", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, outsider := setupTeam(th)
+		board := th.CreateBoard("legacy-team", model.BoardTypeOpen)
+
+		_, resp := outsider.GetBoardsForTeam("legacy-team")
+		th.CheckOK(resp)
+		_, resp = outsider.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+	})
+
+	t.Run("the teams and their boards are only accessible to their members", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, resp := th.Client.CreateTeam(&model.Team{Title: "Marketing"})
+		th.CheckOK(resp)
+		board := th.CreateBoard(team.ID, model.BoardTypeOpen)
+
+		teams, resp := th.Client2.GetTeams()
+		th.CheckOK(resp)
+		for _, t2 := range teams {
+			require.NotEqual(t, team.ID, t2.ID)
+		}
+
+		_, resp = th.Client2.GetTeam(team.ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckForbidden(resp)
+		boards, resp := th.Client2.SearchBoardsForUser(testTeamID, board.Title, model.BoardSearchFieldTitle)
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		_, resp = th.Client.AddTeamMember(&model.TeamMember{TeamID: team.ID, UserID: th.GetUser2().ID})
+		th.CheckOK(resp)
+
+		teams, resp = th.Client2.GetTeams()
+		th.CheckOK(resp)
+		teamIDs := []string{}
+		for _, t2 := range teams {
+			teamIDs = append(teamIDs, t2.ID)
+		}
+		require.Contains(t, teamIDs, team.ID)
+
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+
+		// a member can't manage the team
+		_, resp = th.Client2.RegenerateTeamSignupToken(team.ID)
+		th.CheckForbidden(resp)
+
+		// unless it's promoted to team admin
+		_, resp = th.Client.UpdateTeamMember(&model.TeamMember{TeamID: team.ID, UserID: th.GetUser2().ID, SchemeAdmin: true})
+		th.CheckOK(resp)
+		_, resp = th.Client2.RegenerateTeamSignupToken(team.ID)
+		th.CheckOK(resp)
+	})
+
+	t.Run("registering with the signup token of a team joins the team", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, resp := th.Client.CreateTeam(&model.Team{Title: "Marketing"})
+		th.CheckOK(resp)
+
+		client3 := client.NewClient(th.Server.Config().ServerRoot, "")
+		th.RegisterAndLogin(client3, "user3", "user3@sample.com", password, team.SignupToken)
+
+		teams, resp := client3.GetTeams()
+		th.CheckOK(resp)
+		require.Len(t, teams, 1)
+		require.Equal(t, team.ID, teams[0].ID)
+
+		success, resp := th.Client.Register(&model.RegisterRequest{
+			Username: "user4",
+			Email:    "user4@sample.com",
+			Password: password,
+			Token:    "invalid-token",
+		})
+		th.CheckUnauthorized(resp)
+		require.False(t, success)
+	})
+
+	t.Run("a team admin patches the team and removes members", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, _ := setupTeam(th)
+
+		title := "Sales"
+		_, resp := th.Client2.PatchTeam(team.ID, &model.TeamPatch{Title: &title})
+		th.CheckForbidden(resp)
+		patchedTeam, resp := th.Client.PatchTeam(team.ID, &model.TeamPatch{Title: &title})
+		th.CheckOK(resp)
+		require.Equal(t, "Sales", patchedTeam.Title)
+
+		_, resp = th.Client.RemoveTeamMember(team.ID, th.GetUser2().ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetTeam(team.ID)
+		th.CheckForbidden(resp)
+
+		members, resp := th.Client.GetTeamMembers(team.ID)
+		th.CheckOK(resp)
+		require.Len(t, members, 1)
+	})
+
+	t.Run("members can leave a team", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, _ := setupTeam(th)
+
+		_, resp := th.Client2.RemoveTeamMember(team.ID, th.GetUser1().ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.RemoveTeamMember(team.ID, th.GetUser2().ID)
+		th.CheckOK(resp)
+
+		// the last member can't leave, the team would be open to everyone
+		_, resp = th.Client.RemoveTeamMember(team.ID, th.GetUser1().ID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("only teams without boards can be deleted", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		team, resp := th.Client.CreateTeam(&model.Team{Title: "Marketing"})
+		th.CheckOK(resp)
+		board := th.CreateBoard(team.ID, model.BoardTypeOpen)
+
+		_, resp = th.Client.DeleteTeam(team.ID)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteTeam(team.ID)
+		th.CheckOK(resp)
+
+		teams, resp := th.Client.GetTeams()
+		th.CheckOK(resp)
+		for _, t2 := range teams {
+			require.NotEqual(t, team.ID, t2.ID)
+		}
+
+		_, resp = th.Client.DeleteTeam(model.GlobalTeamID)
+		th.CheckBadRequest(resp)
+	})
+}
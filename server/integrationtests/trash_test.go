@@ -0,0 +1,223 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamTrash(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	teamID := "team-id"
+	board1 := th.CreateBoard(teamID, model.BoardTypeOpen)
+	board2 := th.CreateBoard(teamID, model.BoardTypeOpen)
+	board3 := th.CreateBoard(teamID, model.BoardTypeOpen)
+
+	t.Run("an empty trash", func(t *testing.T) {
+		boards, resp := th.Client.GetTeamTrash(teamID)
+		require.NoError(t, resp.Error)
+		require.Empty(t, boards)
+	})
+
+	for _, board := range []*model.Board{board1, board2, board3} {
+		success, resp := th.Client.DeleteBoard(board.ID)
+		require.NoError(t, resp.Error)
+		require.True(t, success)
+	}
+
+	t.Run("list the deleted boards", func(t *testing.T) {
+		boards, resp := th.Client.GetTeamTrash(teamID)
+		require.NoError(t, resp.Error)
+		require.Len(t, boards, 3)
+		for _, board := range boards {
+			require.NotZero(t, board.DeleteAt)
+			require.Equal(t, th.GetUser1().ID, board.ModifiedBy)
+		}
+	})
+
+	t.Run("restore several boards", func(t *testing.T) {
+		boards, resp := th.Client.RestoreTeamTrash(teamID, []string{board1.ID, board2.ID})
+		require.NoError(t, resp.Error)
+		require.Len(t, boards, 2)
+
+		rBoard, resp := th.Client.GetBoard(board1.ID, "")
+		require.NoError(t, resp.Error)
+		require.Equal(t, board1.Title, rBoard.Title)
+
+		boards, resp = th.Client.GetTeamTrash(teamID)
+		require.NoError(t, resp.Error)
+		require.Len(t, boards, 1)
+		require.Equal(t, board3.ID, boards[0].ID)
+	})
+
+	t.Run("restoring a board that is not in the trash should fail", func(t *testing.T) {
+		_, resp := th.Client.RestoreTeamTrash(teamID, []string{board1.ID, board3.ID})
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+
+		// the other boards are not restored either
+		boards, resp := th.Client.GetTeamTrash(teamID)
+		require.NoError(t, resp.Error)
+		require.Len(t, boards, 1)
+	})
+
+	t.Run("an empty restore request should fail", func(t *testing.T) {
+		_, resp := th.Client.RestoreTeamTrash(teamID, nil)
+		require.Error(t, resp.Error)
+		require.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("purge a deleted board", func(t *testing.T) {
+		success, resp := th.Client.PurgeDeletedBoard(teamID, board3.ID)
+		require.NoError(t, resp.Error)
+		require.True(t, success)
+
+		boards, resp := th.Client.GetTeamTrash(teamID)
+		require.NoError(t, resp.Error)
+		require.Empty(t, boards)
+
+		// a purged board can't be restored
+		_, resp = th.Client.RestoreTeamTrash(teamID, []string{board3.ID})
+		require.Error(t, resp.Error)
+	})
+
+	t.Run("a live board can't be purged", func(t *testing.T) {
+		_, resp := th.Client.PurgeDeletedBoard(teamID, board1.ID)
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+}
+
+func TestPurgeDeletedBoardPermissions(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	// user2 administers its boards, but not their team
+	createDeletedBoard := func() *model.Board {
+		board, resp := th.Client2.CreateBoard(&model.Board{TeamID: testTeamID, Type: model.BoardTypeOpen})
+		th.CheckOK(resp)
+		_, resp = th.Client2.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		return board
+	}
+
+	t.Run("a board admin that is not a team admin can't purge", func(t *testing.T) {
+		board := createDeletedBoard()
+
+		_, resp := th.Client2.PurgeDeletedBoard(testTeamID, board.ID)
+		th.CheckForbidden(resp)
+
+		boards, resp := th.Client2.GetTeamTrash(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+		require.Equal(t, board.ID, boards[0].ID)
+	})
+
+	t.Run("a board of another team can't be purged", func(t *testing.T) {
+		board := createDeletedBoard()
+
+		_, resp := th.Client.PurgeDeletedBoard("test-team", board.ID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("a system admin can purge", func(t *testing.T) {
+		board := createDeletedBoard()
+
+		_, resp := th.Client.PurgeDeletedBoard(testTeamID, board.ID)
+		th.CheckOK(resp)
+	})
+
+	t.Run("a team admin can purge", func(t *testing.T) {
+		board := createDeletedBoard()
+		require.NoError(t, th.Server.Store().SaveTeamMember(&model.TeamMember{
+			TeamID:      testTeamID,
+			UserID:      th.GetUser2().ID,
+			SchemeAdmin: true,
+			CreateAt:    utils.GetMillis(),
+		}))
+
+		_, resp := th.Client2.PurgeDeletedBoard(testTeamID, board.ID)
+		th.CheckOK(resp)
+	})
+}
+
+func TestBoardTrash(t *testing.T) {
+	th := SetupTestHelperWithToken(t).Start()
+	defer th.TearDown()
+
+	board := th.CreateBoard("team-id", model.BoardTypeOpen)
+
+	newBlocks := []*model.Block{
+		{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+			Title:    "card 1",
+		},
+		{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			CreateAt: 1,
+			UpdateAt: 1,
+			Type:     model.TypeCard,
+			Title:    "card 2",
+		},
+	}
+	newBlocks, resp := th.Client.InsertBlocks(board.ID, newBlocks, false)
+	require.NoError(t, resp.Error)
+	require.Len(t, newBlocks, 2)
+	card1ID := newBlocks[0].ID
+	card2ID := newBlocks[1].ID
+
+	for _, cardID := range []string{card1ID, card2ID} {
+		_, resp = th.Client.DeleteBlock(board.ID, cardID, false)
+		require.NoError(t, resp.Error)
+	}
+
+	t.Run("list the deleted cards", func(t *testing.T) {
+		cards, resp := th.Client.GetBoardTrash(board.ID)
+		require.NoError(t, resp.Error)
+		require.Len(t, cards, 2)
+		require.ElementsMatch(t, []string{card1ID, card2ID}, []string{cards[0].ID, cards[1].ID})
+	})
+
+	t.Run("restore a card", func(t *testing.T) {
+		blocks, resp := th.Client.RestoreBoardTrash(board.ID, []string{card1ID})
+		require.NoError(t, resp.Error)
+		require.Len(t, blocks, 1)
+		require.Equal(t, card1ID, blocks[0].ID)
+
+		blocks, resp = th.Client.GetBlocksForBoard(board.ID)
+		require.NoError(t, resp.Error)
+		require.Len(t, blocks, 1)
+		require.Equal(t, card1ID, blocks[0].ID)
+	})
+
+	t.Run("a card from another board should not be found", func(t *testing.T) {
+		otherBoard := th.CreateBoard("team-id", model.BoardTypeOpen)
+		_, resp := th.Client.RestoreBoardTrash(otherBoard.ID, []string{card2ID})
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+
+		_, resp = th.Client.PurgeDeletedBlock(otherBoard.ID, card2ID)
+		require.Error(t, resp.Error)
+		require.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("purge a deleted card", func(t *testing.T) {
+		success, resp := th.Client.PurgeDeletedBlock(board.ID, card2ID)
+		require.NoError(t, resp.Error)
+		require.True(t, success)
+
+		cards, resp := th.Client.GetBoardTrash(board.ID)
+		require.NoError(t, resp.Error)
+		require.Empty(t, cards)
+	})
+}
@@ -0,0 +1,190 @@
+package integrationtests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/api"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableUpload(t *testing.T) {
+	const (
+		testTeamID = "team-id"
+	)
+
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	t.Run("a file is uploaded in chunks and resumed after a conflict", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		session, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "numbers.txt",
+			FileSize: int64(len(content)),
+			Checksum: checksum,
+		})
+		th.CheckOK(resp)
+		require.NotEmpty(t, session.ID)
+		require.Equal(t, th.GetUser1().ID, session.UserID)
+		require.Zero(t, session.FileOffset)
+
+		session, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 0, bytes.NewReader(content[:400]))
+		th.CheckOK(resp)
+		require.Equal(t, int64(400), session.FileOffset)
+		require.Equal(t, "400", resp.Header.Get(api.UploadOffsetHeader))
+
+		// the upload can't be finalized before all the chunks are received
+		_, resp = th.Client.FinalizeUpload(testTeamID, board.ID, session.ID)
+		th.CheckBadRequest(resp)
+
+		// a chunk sent again after a lost response is rejected
+		_, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 0, bytes.NewReader(content[:400]))
+		th.CheckConflict(resp)
+
+		// the client resumes from the offset of the session
+		session, resp = th.Client.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckOK(resp)
+		require.Equal(t, int64(400), session.FileOffset)
+
+		session, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 400, bytes.NewReader(content[400:]))
+		th.CheckOK(resp)
+		require.True(t, session.IsComplete())
+
+		fileResp, resp := th.Client.FinalizeUpload(testTeamID, board.ID, session.ID)
+		th.CheckOK(resp)
+		require.NotEmpty(t, fileResp.FileID)
+
+		// the file is saved the same way as a single request upload
+		fileInfo, resp := th.Client.TeamUploadFileInfo(testTeamID, board.ID, fileResp.FileID)
+		th.CheckOK(resp)
+		require.Equal(t, "numbers.txt", fileInfo.Name)
+		require.Equal(t, int64(len(content)), fileInfo.Size)
+		require.Equal(t, "text/plain", fileInfo.MimeType)
+
+		data, resp := th.Client.GetFile(testTeamID, board.ID, fileResp.FileID, model.FileSizeOriginal)
+		th.CheckOK(resp)
+		require.Equal(t, content, data)
+
+		usage, err := th.Server.App().GetFileUsage(testTeamID)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), usage[0].Size)
+
+		// the session is removed once finalized
+		_, resp = th.Client.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckNotFound(resp)
+		require.Empty(t, findFiles(t, filepath.Join(th.Server.Config().FilesPath, "uploads"), ""))
+	})
+
+	t.Run("an upload not matching its checksum is rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		session, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "numbers.txt",
+			FileSize: int64(len(content)),
+			Checksum: checksum,
+		})
+		th.CheckOK(resp)
+
+		corrupted := bytes.Repeat([]byte("x"), len(content))
+		_, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 0, bytes.NewReader(corrupted))
+		th.CheckOK(resp)
+
+		_, resp = th.Client.FinalizeUpload(testTeamID, board.ID, session.ID)
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), "checksum")
+
+		_, resp = th.Client.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckNotFound(resp)
+		require.Empty(t, findFiles(t, th.Server.Config().FilesPath, ".txt"))
+	})
+
+	t.Run("the size of the upload is checked", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.MaxUploadSessionFileSize = 2000
+		}).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		_, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "big.bin",
+			FileSize: 2001,
+		})
+		th.CheckRequestEntityTooLarge(resp)
+
+		_, resp = th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "empty.bin",
+		})
+		th.CheckBadRequest(resp)
+
+		session, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "numbers.txt",
+			FileSize: 500,
+		})
+		th.CheckOK(resp)
+
+		// the bytes beyond the declared size are rejected
+		_, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 0, bytes.NewReader(content))
+		th.CheckBadRequest(resp)
+
+		session, resp = th.Client.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckOK(resp)
+		require.Equal(t, int64(500), session.FileOffset)
+	})
+
+	t.Run("the size of the upload is limited by the max file size by default", func(t *testing.T) {
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.MaxFileSize = 1000
+		}).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		_, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "big.bin",
+			FileSize: 1001,
+		})
+		th.CheckRequestEntityTooLarge(resp)
+
+		_, resp = th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "numbers.txt",
+			FileSize: 1000,
+		})
+		th.CheckOK(resp)
+	})
+
+	t.Run("an upload can be cancelled only by its user", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		session, resp := th.Client.CreateUploadSession(testTeamID, board.ID, &model.UploadSession{
+			Filename: "numbers.txt",
+			FileSize: int64(len(content)),
+		})
+		th.CheckOK(resp)
+		_, resp = th.Client.UploadChunk(testTeamID, board.ID, session.ID, 0, bytes.NewReader(content[:100]))
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.DeleteUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.DeleteUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client.GetUploadSession(testTeamID, board.ID, session.ID)
+		th.CheckNotFound(resp)
+		require.Empty(t, findFiles(t, filepath.Join(th.Server.Config().FilesPath, "uploads"), ""))
+	})
+}
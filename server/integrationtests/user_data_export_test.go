@@ -0,0 +1,215 @@
+package integrationtests
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readUserDataExport(t *testing.T, archive []byte) (*model.UserDataExport, map[string][]byte) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = data
+	}
+
+	require.Contains(t, files, "user.json")
+	require.Contains(t, files, "summary.txt")
+	export := model.UserDataExportFromJSON(bytes.NewReader(files["user.json"]))
+	require.NotNil(t, export)
+	return export, files
+}
+
+func TestUserDataExport(t *testing.T) {
+	t.Run("a user exports its personal data", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		user2 := th.GetUser2()
+
+		board, resp := th.Client.CreateBoard(&model.Board{
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+			CardProperties: []map[string]interface{}{
+				{"id": "assignee", "name": "Assignee", "type": "person"},
+			},
+		})
+		th.CheckOK(resp)
+		_, resp = th.Client.AddMemberToBoard(&model.BoardMember{BoardID: board.ID, UserID: user2.ID, SchemeEditor: true})
+		th.CheckOK(resp)
+
+		created, resp := th.Client2.CreateCard(board.ID, &model.Card{Title: "card of user2"}, true)
+		th.CheckOK(resp)
+		assigned, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "card assigned to user2",
+			Properties: map[string]any{"assignee": user2.ID},
+		}, true)
+		th.CheckOK(resp)
+		_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "card of user1"}, true)
+		th.CheckOK(resp)
+
+		file, resp := th.Client2.TeamUploadFile(testTeamID, board.ID, bytes.NewBuffer([]byte("file of user2")))
+		th.CheckOK(resp)
+		_, resp = th.Client2.InsertBlocks(board.ID, []*model.Block{
+			{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  board.ID,
+				ParentID: created.ID,
+				Type:     model.TypeComment,
+				Title:    "comment of user2",
+				CreateAt: utils.GetMillis(),
+				UpdateAt: utils.GetMillis(),
+			},
+			{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  board.ID,
+				ParentID: created.ID,
+				Type:     model.TypeAttachment,
+				Fields:   map[string]interface{}{"fileId": file.FileID},
+				CreateAt: utils.GetMillis(),
+				UpdateAt: utils.GetMillis(),
+			},
+		}, true)
+		th.CheckOK(resp)
+
+		archive, resp := th.Client2.ExportMyData()
+		th.CheckOK(resp)
+		export, files := readUserDataExport(t, archive)
+
+		require.Equal(t, user2.ID, export.Profile.ID)
+		require.Equal(t, user2Username, export.Profile.Username)
+		require.NotEmpty(t, export.Profile.Email)
+		require.Len(t, export.BoardMemberships, 1)
+		require.Equal(t, board.ID, export.BoardMemberships[0].BoardID)
+		require.Len(t, export.BoardMembershipHistory, 1)
+		require.Len(t, export.CreatedCards, 1)
+		require.Equal(t, created.ID, export.CreatedCards[0].ID)
+		require.Len(t, export.AssignedCards, 1)
+		require.Equal(t, assigned.ID, export.AssignedCards[0].ID)
+		require.Len(t, export.Comments, 1)
+		require.Equal(t, "comment of user2", export.Comments[0].Title)
+		require.Len(t, export.Sessions, 1)
+
+		// the uploaded files are part of the archive, the session tokens
+		// aren't
+		require.Len(t, export.Files, 1)
+		require.Equal(t, file.FileID, export.Files[0].FileID)
+		require.Equal(t, []byte("file of user2"), files[export.Files[0].Path])
+		require.NotContains(t, string(files["user.json"]), th.Client2.Token)
+		require.Contains(t, string(files["summary.txt"]), "Cards assigned: 1")
+	})
+
+	t.Run("the cards are exported as the user sees them", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		user2 := th.GetUser2()
+
+		board, resp := th.Client.CreateBoard(&model.Board{
+			TeamID: testTeamID,
+			Type:   model.BoardTypePrivate,
+			CardProperties: []map[string]interface{}{
+				{"id": "salary", "name": "Salary", "type": "text", model.CardPropertyRestrictedField: true},
+				{"id": "owner", "name": "Owner", "type": "person"},
+			},
+		})
+		th.CheckOK(resp)
+		member := &model.BoardMember{BoardID: board.ID, UserID: user2.ID, SchemeEditor: true}
+		_, resp = th.Client.AddMemberToBoard(member)
+		th.CheckOK(resp)
+
+		assigned, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "card assigned to user2",
+			Properties: map[string]any{"owner": user2.ID, "salary": "1000"},
+		}, true)
+		th.CheckOK(resp)
+
+		otherBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		otherMember := &model.BoardMember{BoardID: otherBoard.ID, UserID: user2.ID, SchemeEditor: true}
+		_, resp = th.Client.AddMemberToBoard(otherMember)
+		th.CheckOK(resp)
+		_, resp = th.Client2.CreateCard(otherBoard.ID, &model.Card{Title: "card of a board left"}, true)
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteBoardMember(otherMember)
+		th.CheckOK(resp)
+
+		archive, resp := th.Client2.ExportMyData()
+		th.CheckOK(resp)
+		export, _ := readUserDataExport(t, archive)
+
+		require.Empty(t, export.CreatedCards)
+		require.Len(t, export.AssignedCards, 1)
+		require.Equal(t, assigned.ID, export.AssignedCards[0].ID)
+		properties, _ := export.AssignedCards[0].Fields["properties"].(map[string]interface{})
+		require.Equal(t, user2.ID, properties["owner"])
+		require.NotContains(t, properties, "salary")
+	})
+
+	t.Run("the audit entries of the user are exported", func(t *testing.T) {
+		auditFile := filepath.Join(t.TempDir(), "audit.log")
+		th := SetupTestHelperWithConfig(t, func(cfg *config.Configuration) {
+			cfg.AuditCfgJSON = fmt.Sprintf(`{"audit": {
+				"type": "file",
+				"format": "json",
+				"options": {"filename": %q},
+				"levels": [{"id": 1000, "name": "auth"}, {"id": 1001, "name": "mod"}, {"id": 1002, "name": "read"}]
+			}}`, auditFile)
+		}).InitBasic()
+		defer th.TearDown()
+
+		th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		_, resp := th.Client2.GetMe()
+		th.CheckOK(resp)
+		_, resp = th.Client2.ExportMyData()
+		th.CheckOK(resp)
+
+		// the audit records are written asynchronously
+		var export *model.UserDataExport
+		require.Eventually(t, func() bool {
+			archive, resp := th.Client2.ExportMyData()
+			th.CheckOK(resp)
+			export, _ = readUserDataExport(t, archive)
+			return len(export.AuditEntries) > 0
+		}, 5*time.Second, 100*time.Millisecond)
+
+		events := []interface{}{}
+		for _, entry := range export.AuditEntries {
+			require.Equal(t, th.GetUser2().ID, entry["user_id"])
+			events = append(events, entry["event"])
+		}
+		require.Contains(t, events, "exportMyData")
+	})
+
+	t.Run("a system admin exports the personal data of a user", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		user2 := th.GetUser2()
+
+		_, resp := th.Client2.AdminExportUserData(th.GetUser1().ID)
+		th.CheckForbidden(resp)
+
+		archive, resp := th.Client.AdminExportUserData(user2.ID)
+		th.CheckOK(resp)
+		export, _ := readUserDataExport(t, archive)
+		require.Equal(t, user2.ID, export.Profile.ID)
+		require.Empty(t, export.CreatedCards)
+
+		_, resp = th.Client.AdminExportUserData("nonexistent-user")
+		th.CheckNotFound(resp)
+	})
+}
@@ -0,0 +1,147 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserGroups(t *testing.T) {
+	t.Run("team admins manage the user groups", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		group, resp := th.Client.CreateUserGroup(&model.UserGroup{TeamID: testTeamID, Name: "Reviewers"})
+		th.CheckOK(resp)
+		require.NotEmpty(t, group.ID)
+		require.Equal(t, "reviewers", group.Name)
+		require.Equal(t, th.GetUser1().ID, group.CreatedBy)
+
+		// the members of the team can list the groups but not manage them
+		groups, resp := th.Client2.GetUserGroups(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, groups, 1)
+		_, resp = th.Client2.CreateUserGroup(&model.UserGroup{TeamID: testTeamID, Name: "other"})
+		th.CheckForbidden(resp)
+		_, resp = th.Client2.AddUserGroupMember(testTeamID, group.ID, th.GetUser2().ID)
+		th.CheckForbidden(resp)
+
+		// the names must be valid mentions, not used by other groups or users
+		for _, name := range []string{"", "two words", "reviewers", user2Username} {
+			_, resp = th.Client.CreateUserGroup(&model.UserGroup{TeamID: testTeamID, Name: name})
+			th.CheckBadRequest(resp)
+		}
+
+		newName := "qa"
+		group, resp = th.Client.PatchUserGroup(testTeamID, group.ID, &model.UserGroupPatch{Name: &newName})
+		th.CheckOK(resp)
+		require.Equal(t, "qa", group.Name)
+
+		_, resp = th.Client.AddUserGroupMember(testTeamID, group.ID, th.GetUser2().ID)
+		th.CheckOK(resp)
+		userIDs, resp := th.Client2.GetUserGroupMembers(testTeamID, group.ID)
+		th.CheckOK(resp)
+		require.Equal(t, []string{th.GetUser2().ID}, userIDs)
+
+		// the groups of a team can't be managed from another team
+		_, resp = th.Client.DeleteUserGroup("test-team", group.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.DeleteUserGroup(testTeamID, group.ID)
+		th.CheckOK(resp)
+		groups, resp = th.Client.GetUserGroups(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, groups)
+	})
+
+	t.Run("the members of a group are members of its boards", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		user2ID := th.GetUser2().ID
+
+		group, resp := th.Client.CreateUserGroup(&model.UserGroup{TeamID: testTeamID, Name: "designers"})
+		th.CheckOK(resp)
+		_, resp = th.Client.AddUserGroupMember(testTeamID, group.ID, user2ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client2.AddBoardGroup(&model.BoardGroup{BoardID: board.ID, GroupID: group.ID, SchemeAdmin: true})
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client.AddBoardGroup(&model.BoardGroup{BoardID: board.ID, GroupID: group.ID, SchemeEditor: true})
+		th.CheckOK(resp)
+
+		member, err := th.Server.App().GetMemberForBoard(board.ID, user2ID)
+		require.NoError(t, err)
+		require.True(t, member.SchemeEditor)
+		require.Equal(t, group.ID, member.GroupID)
+
+		boardGroups, resp := th.Client2.GetBoardGroups(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, boardGroups, 1)
+
+		// removing the user from the group removes it from the board
+		_, resp = th.Client.DeleteUserGroupMember(testTeamID, group.ID, user2ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckForbidden(resp)
+
+		// and adding it back adds it to the board
+		_, resp = th.Client.AddUserGroupMember(testTeamID, group.ID, user2ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+
+		_, resp = th.Client.DeleteBoardGroup(board.ID, group.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("the direct members of a board keep their membership", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		user2ID := th.GetUser2().ID
+
+		_, resp := th.Client.AddMemberToBoard(&model.BoardMember{BoardID: board.ID, UserID: user2ID, SchemeViewer: true})
+		th.CheckOK(resp)
+
+		group, resp := th.Client.CreateUserGroup(&model.UserGroup{TeamID: testTeamID, Name: "designers"})
+		th.CheckOK(resp)
+		_, resp = th.Client.AddUserGroupMember(testTeamID, group.ID, user2ID)
+		th.CheckOK(resp)
+		_, resp = th.Client.AddBoardGroup(&model.BoardGroup{BoardID: board.ID, GroupID: group.ID, SchemeEditor: true})
+		th.CheckOK(resp)
+
+		member, err := th.Server.App().GetMemberForBoard(board.ID, user2ID)
+		require.NoError(t, err)
+		require.True(t, member.SchemeViewer)
+		require.False(t, member.SchemeEditor)
+		require.Empty(t, member.GroupID)
+
+		_, resp = th.Client.DeleteUserGroup(testTeamID, group.ID)
+		th.CheckOK(resp)
+		_, resp = th.Client2.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+	})
+
+	t.Run("the groups of a board must be of its team", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		group, resp := th.Client.CreateUserGroup(&model.UserGroup{TeamID: "other-team", Name: "designers"})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.AddBoardGroup(&model.BoardGroup{BoardID: board.ID, GroupID: group.ID, SchemeEditor: true})
+		th.CheckBadRequest(resp)
+		_, resp = th.Client.AddBoardGroup(&model.BoardGroup{BoardID: board.ID, GroupID: "nonexistent-id", SchemeEditor: true})
+		th.CheckBadRequest(resp)
+	})
+}
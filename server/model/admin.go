@@ -0,0 +1,67 @@
+package model
+
+// UserQueryOptions are the options to list the users of the server.
+type UserQueryOptions struct {
+	Page               int  // page number, starting at 0
+	PerPage            int  // number of users per page, 0 for all the users
+	IncludeDeactivated bool // include the deactivated users
+}
+
+// AdminUserActivePatch activates or deactivates a user.
+// swagger:model
+type AdminUserActivePatch struct {
+	// False to deactivate the user, true to reactivate it
+	// required: true
+	Active bool `json:"active"`
+}
+
+// AdminUserRolesPatch promotes a user to system admin or demotes it.
+// swagger:model
+type AdminUserRolesPatch struct {
+	// True to make the user a system admin
+	// required: true
+	SystemAdmin bool `json:"systemAdmin"`
+}
+
+// AdminUserGuestPatch makes a user a guest or a regular user.
+// swagger:model
+type AdminUserGuestPatch struct {
+	// True to make the user a guest, only able to access the boards it is
+	// a member of
+	// required: true
+	Guest bool `json:"guest"`
+
+	// Expiration time of the guest account in miliseconds since the
+	// current epoch, 0 if it doesn't expire
+	// required: false
+	GuestExpireAt int64 `json:"guestExpireAt"`
+}
+
+// SystemStatistics are the statistics of the server for the system
+// admins.
+// swagger:model
+type SystemStatistics struct {
+	// The number of active users
+	// required: true
+	Users int `json:"user_count"`
+
+	// The number of users active in the last day
+	// required: true
+	DailyActiveUsers int `json:"daily_active_user_count"`
+
+	// The number of boards
+	// required: true
+	Boards int `json:"board_count"`
+
+	// The number of cards
+	// required: true
+	Cards int `json:"card_count"`
+
+	// The number of uploaded files
+	// required: true
+	Files int64 `json:"file_count"`
+
+	// The size of the uploaded files in bytes
+	// required: true
+	FilesSize int64 `json:"file_size"`
+}
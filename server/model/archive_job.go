@@ -0,0 +1,96 @@
+package model
+
+// ArchiveJobType is the kind of work done by an archive job.
+type ArchiveJobType string
+
+const (
+	ArchiveJobTypeExport ArchiveJobType = "export"
+	ArchiveJobTypeImport ArchiveJobType = "import"
+)
+
+// ArchiveJobStatus is the state of an archive job.
+type ArchiveJobStatus string
+
+const (
+	ArchiveJobStatusPending    ArchiveJobStatus = "pending"
+	ArchiveJobStatusInProgress ArchiveJobStatus = "in_progress"
+	ArchiveJobStatusSuccess    ArchiveJobStatus = "success"
+	ArchiveJobStatusError      ArchiveJobStatus = "error"
+)
+
+// ArchiveJob is an archive export or import running in the background.
+// swagger:model
+type ArchiveJob struct {
+	// The ID of the job
+	// required: true
+	ID string `json:"id"`
+
+	// The kind of job, either export or import
+	// required: true
+	Type ArchiveJobType `json:"type"`
+
+	// The team the archive is exported from or imported to
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the user that started the job
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The state of the job: pending, in_progress, success or error
+	// required: true
+	Status ArchiveJobStatus `json:"status"`
+
+	// The progress of the job, in percent
+	// required: true
+	Progress int `json:"progress"`
+
+	// The error that made the job fail
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The boards to export
+	// required: false
+	BoardIDs []string `json:"boardIds"`
+
+	// The import mode of import jobs
+	// required: false
+	ImportMode ImportMode `json:"importMode,omitempty"`
+
+	// The outcome of import jobs, once they succeeded
+	// required: false
+	ImportResult *ImportArchiveResult `json:"importResult,omitempty"`
+
+	// The path of the archive in the files backend
+	FilePath string `json:"-"`
+
+	// The URL to download the exported archive from, once the job succeeded
+	// required: false
+	DownloadURL string `json:"downloadUrl,omitempty"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The time in milliseconds since the current epoch after which the
+	// archive is removed. Zero until the job is finished
+	// required: true
+	ExpireAt int64 `json:"expireAt"`
+}
+
+// IsFinished returns true if the job succeeded or failed.
+func (j *ArchiveJob) IsFinished() bool {
+	return j.Status == ArchiveJobStatusSuccess || j.Status == ArchiveJobStatusError
+}
+
+// QueryArchiveJobsOptions are query options that can be passed to GetArchiveJobs.
+type QueryArchiveJobsOptions struct {
+	TeamID        string             // if not empty then filter for jobs of the specified team
+	CreatedBy     string             // if not empty then filter for jobs started by the specified user
+	Statuses      []ArchiveJobStatus // if not empty then filter for jobs in one of the specified statuses
+	ExpiredBefore int64              // if non-zero then filter for finished jobs with expire_at less than ExpiredBefore
+}
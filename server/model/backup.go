@@ -0,0 +1,115 @@
+package model
+
+// Backup is a backup of the whole server, stored in the backup storage.
+// swagger:model
+type Backup struct {
+	// The name of the backup, used to restore it
+	// required: true
+	Name string `json:"name"`
+
+	// The size of the backup in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// Whether the backup is encrypted with a passphrase
+	// required: true
+	Encrypted bool `json:"encrypted"`
+}
+
+// BackupHeader is the content of the first file (`version.json`) within a
+// backup.
+type BackupHeader struct {
+	Version int   `json:"version"`
+	Date    int64 `json:"date"`
+}
+
+// BackupUser is a user in a backup. Unlike User, it includes the
+// credentials so users can log in after a restore.
+type BackupUser struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	MfaSecret   string `json:"mfaSecret"`
+	AuthService string `json:"authService"`
+	AuthData    string `json:"authData"`
+	Roles       string `json:"roles"`
+	CreateAt    int64  `json:"createAt"`
+	DeleteAt    int64  `json:"deleteAt"`
+}
+
+// NewBackupUser returns the backup of a user.
+func NewBackupUser(user *User) *BackupUser {
+	return &BackupUser{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		Password:    user.Password,
+		MfaSecret:   user.MfaSecret,
+		AuthService: user.AuthService,
+		AuthData:    user.AuthData,
+		Roles:       user.Roles,
+		CreateAt:    user.CreateAt,
+		DeleteAt:    user.DeleteAt,
+	}
+}
+
+// ToUser returns the user saved in the backup.
+func (u *BackupUser) ToUser() *User {
+	return &User{
+		ID:          u.ID,
+		Username:    u.Username,
+		Email:       u.Email,
+		Password:    u.Password,
+		MfaSecret:   u.MfaSecret,
+		AuthService: u.AuthService,
+		AuthData:    u.AuthData,
+		Roles:       u.Roles,
+		CreateAt:    u.CreateAt,
+		DeleteAt:    u.DeleteAt,
+	}
+}
+
+// RestoreBackupResult is the outcome of a backup restore.
+// swagger:model
+type RestoreBackupResult struct {
+	// The number of users created
+	// required: true
+	Users int `json:"users"`
+
+	// The usernames of the users not restored because they already exist
+	// required: true
+	SkippedUsers []string `json:"skippedUsers"`
+
+	// The number of teams restored
+	// required: true
+	Teams int `json:"teams"`
+
+	// The number of team memberships restored
+	// required: true
+	TeamMembers int `json:"teamMembers"`
+
+	// The number of custom board roles restored
+	// required: true
+	BoardRoles int `json:"boardRoles"`
+
+	// The number of boards created or updated
+	// required: true
+	Boards int `json:"boards"`
+
+	// The number of sidebar categories restored
+	// required: true
+	Categories int `json:"categories"`
+
+	// The number of sharing settings restored
+	// required: true
+	Sharing int `json:"sharing"`
+
+	// The boards and blocks that were not restored
+	// required: true
+	Conflicts []ImportConflict `json:"conflicts"`
+}
@@ -110,6 +110,20 @@ type BlockPatch struct {
 	DeletedFields []string `json:"deletedFields"`
 }
 
+// OnlyUpdatesProperties returns true if a patch only changes the
+// property values of a block, eg. to move a card to another column.
+func (p *BlockPatch) OnlyUpdatesProperties() bool {
+	if p.ParentID != nil || p.Schema != nil || p.Type != nil || p.Title != nil || len(p.DeletedFields) > 0 {
+		return false
+	}
+	for key := range p.UpdatedFields {
+		if key != "properties" {
+			return false
+		}
+	}
+	return len(p.UpdatedFields) > 0
+}
+
 // BlockPatchBatch is a batch of IDs and patches for modify blocks
 // swagger:model
 type BlockPatchBatch struct {
@@ -290,3 +304,34 @@ func (b *Block) GetLimited() *Block {
 
 	return newBlock
 }
+
+// WithoutProperties returns a copy of a block without the values of some
+// card properties, and without them in the visible properties of views.
+func (b *Block) WithoutProperties(ids map[string]bool) *Block {
+	newBlock := *b
+	newBlock.Fields = make(map[string]interface{}, len(b.Fields))
+	for key, value := range b.Fields {
+		newBlock.Fields[key] = value
+	}
+
+	if properties, ok := b.Fields["properties"].(map[string]interface{}); ok {
+		newProperties := map[string]interface{}{}
+		for id, value := range properties {
+			if !ids[id] {
+				newProperties[id] = value
+			}
+		}
+		newBlock.Fields["properties"] = newProperties
+	}
+
+	if visiblePropertyIDs, ok := b.Fields["visiblePropertyIds"].([]interface{}); ok {
+		newIDs := []interface{}{}
+		for _, id := range visiblePropertyIDs {
+			if s, _ := id.(string); !ids[s] {
+				newIDs = append(newIDs, id)
+			}
+		}
+		newBlock.Fields["visiblePropertyIds"] = newIDs
+	}
+	return &newBlock
+}
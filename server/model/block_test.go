@@ -307,3 +307,27 @@ func TestStampModificationMetadata(t *testing.T) {
 		assert.NotEmpty(t, blocks[0].UpdateAt)
 	})
 }
+
+func TestBlockPatchOnlyUpdatesProperties(t *testing.T) {
+	title := "new title"
+
+	t.Run("Should accept a patch of the property values", func(t *testing.T) {
+		patch := &BlockPatch{UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": "done"}}}
+		require.True(t, patch.OnlyUpdatesProperties())
+	})
+
+	t.Run("Should reject an empty patch", func(t *testing.T) {
+		require.False(t, (&BlockPatch{}).OnlyUpdatesProperties())
+	})
+
+	t.Run("Should reject a patch of other fields", func(t *testing.T) {
+		patches := []*BlockPatch{
+			{Title: &title, UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{}}},
+			{UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{}, "icon": "🚀"}},
+			{UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{}}, DeletedFields: []string{"icon"}},
+		}
+		for _, patch := range patches {
+			require.False(t, patch.OnlyUpdatesProperties())
+		}
+	})
+}
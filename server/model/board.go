@@ -103,6 +103,15 @@ type Board struct {
 	// The deleted time in miliseconds since the current epoch. Set to indicate this block is deleted
 	// required: false
 	DeleteAt int64 `json:"deleteAt"`
+
+	// The archived time in miliseconds since the current epoch. Set to indicate this board is archived
+	// required: false
+	ArchiveAt int64 `json:"archiveAt"`
+}
+
+// IsArchived returns true if the board is archived.
+func (b *Board) IsArchived() bool {
+	return b.ArchiveAt != 0
 }
 
 // GetPropertyString returns the value of the specified property as a string,
@@ -203,6 +212,16 @@ type BoardMember struct {
 	// required: true
 	SchemeViewer bool `json:"schemeViewer"`
 
+	// The custom board role of the user, granting permissions on top of
+	// its scheme roles
+	// required: false
+	CustomRoleID string `json:"customRoleId"`
+
+	// The ID of the user group that added the user to the board, empty
+	// if the user was added directly
+	// required: false
+	GroupID string `json:"groupId,omitempty"`
+
 	// Marks the membership as generated by an access group
 	// required: true
 	Synthetic bool `json:"synthetic"`
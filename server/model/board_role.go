@@ -0,0 +1,135 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+// CustomBoardRoleNameMaxLength is the maximum length of the name of a
+// custom board role.
+const CustomBoardRoleNameMaxLength = 100
+
+// CustomBoardRole is a named set of board permissions defined by the
+// admins of a team. It can be assigned to the members of the boards of
+// the team, on top of their scheme roles.
+// swagger:model
+type CustomBoardRole struct {
+	// The ID of the role
+	// required: true
+	ID string `json:"id"`
+
+	// The team the role is defined in
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The name of the role
+	// required: true
+	Name string `json:"name"`
+
+	// The description of the role
+	// required: false
+	Description string `json:"description"`
+
+	// The IDs of the board permissions granted by the role
+	// required: true
+	Permissions []string `json:"permissions"`
+
+	// The ID of the user that created the role
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// CustomBoardRolePatch is a patch to update a custom board role.
+// swagger:model
+type CustomBoardRolePatch struct {
+	// The name of the role
+	// required: false
+	Name *string `json:"name"`
+
+	// The description of the role
+	// required: false
+	Description *string `json:"description"`
+
+	// The IDs of the board permissions granted by the role
+	// required: false
+	Permissions *[]string `json:"permissions"`
+}
+
+// IsValid checks the name and the permissions of a custom board role.
+// The permissions must be board permissions, and include viewing the
+// board as every other permission requires it.
+func (r *CustomBoardRole) IsValid() error {
+	if r.TeamID == "" {
+		return NewErrBadRequest("custom board role team ID is required")
+	}
+
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return NewErrBadRequest("custom board role name is required")
+	}
+	if len(name) > CustomBoardRoleNameMaxLength {
+		return NewErrBadRequest(fmt.Sprintf("custom board role name cannot be longer than %d characters", CustomBoardRoleNameMaxLength))
+	}
+
+	hasViewBoard := false
+	for _, id := range r.Permissions {
+		if BoardPermissionByID(id) == nil {
+			return NewErrBadRequest("invalid board permission: " + id)
+		}
+		if id == PermissionViewBoard.Id {
+			hasViewBoard = true
+		}
+	}
+	if !hasViewBoard {
+		return NewErrBadRequest("custom board role must grant " + PermissionViewBoard.Id)
+	}
+	return nil
+}
+
+// HasPermission returns true if the role grants a permission.
+func (r *CustomBoardRole) HasPermission(permission *mmModel.Permission) bool {
+	for _, id := range r.Permissions {
+		if id == permission.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// Patch applies the changes of a patch to a custom board role.
+func (p *CustomBoardRolePatch) Patch(role *CustomBoardRole) *CustomBoardRole {
+	if p.Name != nil {
+		role.Name = strings.TrimSpace(*p.Name)
+	}
+	if p.Description != nil {
+		role.Description = *p.Description
+	}
+	if p.Permissions != nil {
+		role.Permissions = *p.Permissions
+	}
+	return role
+}
+
+func CustomBoardRoleFromJSON(data io.Reader) *CustomBoardRole {
+	var role *CustomBoardRole
+	_ = json.NewDecoder(data).Decode(&role)
+	return role
+}
+
+func CustomBoardRolesFromJSON(data io.Reader) []*CustomBoardRole {
+	var roles []*CustomBoardRole
+	_ = json.NewDecoder(data).Decode(&roles)
+	return roles
+}
@@ -12,4 +12,12 @@ type BoardsStatistics struct {
 	// The maximum number of cards on the server
 	// required: true
 	Cards int `json:"card_count"`
+
+	// The number of uploaded files on the server
+	// required: true
+	Files int64 `json:"file_count"`
+
+	// The size of the uploaded files on the server in bytes
+	// required: true
+	FilesSize int64 `json:"file_size"`
 }
@@ -69,6 +69,11 @@ type Card struct {
 	// required: false
 	IsTemplate bool `json:"isTemplate"`
 
+	// True if this card is private to its creator, its assignees and the
+	// board admins
+	// required: false
+	IsPrivate bool `json:"isPrivate"`
+
 	// A map of property ids to property values (option ids, strings, array of option ids)
 	// required: false
 	Properties map[string]any `json:"properties"`
@@ -152,11 +157,22 @@ type CardPatch struct {
 	// required: false
 	Icon *string `json:"icon"`
 
+	// Makes the card private to its creator, its assignees and the board
+	// admins, or public again
+	// required: false
+	IsPrivate *bool `json:"isPrivate"`
+
 	// A map of property ids to property option ids to be updated
 	// required: false
 	UpdatedProperties map[string]any `json:"updatedProperties"`
 }
 
+// OnlyUpdatesProperties returns true if a patch only changes the
+// property values of a card, eg. to move it to another column.
+func (p *CardPatch) OnlyUpdatesProperties() bool {
+	return p.Title == nil && p.ContentOrder == nil && p.Icon == nil && p.IsPrivate == nil && len(p.UpdatedProperties) > 0
+}
+
 // Patch returns an updated version of the card.
 func (p *CardPatch) Patch(card *Card) *Card {
 	if p.Title != nil {
@@ -171,6 +187,10 @@ func (p *CardPatch) Patch(card *Card) *Card {
 		card.Icon = *p.Icon
 	}
 
+	if p.IsPrivate != nil {
+		card.IsPrivate = *p.IsPrivate
+	}
+
 	if card.Properties == nil {
 		card.Properties = make(map[string]any)
 	}
@@ -199,6 +219,7 @@ func Card2Block(card *Card) *Block {
 	fields["contentOrder"] = card.ContentOrder
 	fields["icon"] = card.Icon
 	fields["isTemplate"] = card.IsTemplate
+	fields[CardPrivateField] = card.IsPrivate
 	fields["properties"] = card.Properties
 
 	return &Block{
@@ -226,6 +247,7 @@ func Block2Card(block *Block) (*Card, error) {
 	contentOrder := make([]string, 0)
 	icon := ""
 	isTemplate := false
+	isPrivate := false
 	properties := make(map[string]any)
 
 	if co, ok := block.Fields["contentOrder"]; ok {
@@ -261,6 +283,14 @@ func Block2Card(block *Block) (*Card, error) {
 		}
 	}
 
+	if isPrivateAny, ok := block.Fields[CardPrivateField]; ok {
+		if b, ok := isPrivateAny.(bool); ok {
+			isPrivate = b
+		} else {
+			return nil, ErrInvalidFieldType{CardPrivateField}
+		}
+	}
+
 	if props, ok := block.Fields["properties"]; ok {
 		if propMap, ok := props.(map[string]any); ok {
 			for k, v := range propMap {
@@ -280,6 +310,7 @@ func Block2Card(block *Block) (*Card, error) {
 		ContentOrder: contentOrder,
 		Icon:         icon,
 		IsTemplate:   isTemplate,
+		IsPrivate:    isPrivate,
 		Properties:   properties,
 		CreateAt:     block.CreateAt,
 		UpdateAt:     block.UpdateAt,
@@ -307,6 +338,9 @@ func CardPatch2BlockPatch(cardPatch *CardPatch) (*BlockPatch, error) {
 	if cardPatch.Icon != nil {
 		updatedFields["icon"] = cardPatch.Icon
 	}
+	if cardPatch.IsPrivate != nil {
+		updatedFields[CardPrivateField] = *cardPatch.IsPrivate
+	}
 
 	properties := make(map[string]any)
 	for k, v := range cardPatch.UpdatedProperties {
@@ -0,0 +1,150 @@
+package model
+
+const (
+	// CardPrivateField is the field of the card blocks that makes them
+	// private. The private cards are only visible to their creator, their
+	// assignees and the members that can view the private cards of the
+	// board, the board admins by default.
+	CardPrivateField = "isPrivate"
+
+	// CardPropertyRestrictedField is the field of the card properties of a
+	// board that restricts their values to the members that can view the
+	// restricted properties, the board admins by default.
+	CardPropertyRestrictedField = "restricted"
+)
+
+// IsPrivateCard returns true if a block is a private card.
+func IsPrivateCard(block *Block) bool {
+	if block.Type != TypeCard {
+		return false
+	}
+	isPrivate, _ := block.Fields[CardPrivateField].(bool)
+	return isPrivate
+}
+
+// RestrictedCardProperties returns the IDs of the restricted card
+// properties of a board.
+func RestrictedCardProperties(board *Board) map[string]bool {
+	restricted := map[string]bool{}
+	for _, property := range board.CardProperties {
+		id, _ := property["id"].(string)
+		if isRestricted, _ := property[CardPropertyRestrictedField].(bool); isRestricted && id != "" {
+			restricted[id] = true
+		}
+	}
+	return restricted
+}
+
+// CardAccess is what a user can see of the cards of a board: the private
+// cards and the values of the restricted properties are only visible to
+// the users with the permissions to view them.
+type CardAccess struct {
+	userID                      string
+	canViewPrivateCards         bool
+	canViewRestrictedProperties bool
+	restrictedProperties        map[string]bool
+	personProperties            []string
+}
+
+// NewCardAccess returns the access of a user to the cards of a board,
+// given its permissions to view the private cards and the restricted
+// properties.
+func NewCardAccess(board *Board, userID string, canViewPrivateCards, canViewRestrictedProperties bool) *CardAccess {
+	access := &CardAccess{
+		userID:                      userID,
+		canViewPrivateCards:         canViewPrivateCards,
+		canViewRestrictedProperties: canViewRestrictedProperties,
+		restrictedProperties:        RestrictedCardProperties(board),
+	}
+
+	for _, property := range board.CardProperties {
+		switch property["type"] {
+		case "person", "multiPerson":
+			if id, _ := property["id"].(string); id != "" {
+				access.personProperties = append(access.personProperties, id)
+			}
+		}
+	}
+	return access
+}
+
+// IsUnrestricted returns true if the user can see every card of the
+// board and all their property values.
+func (ca *CardAccess) IsUnrestricted() bool {
+	return ca.canViewPrivateCards && len(ca.HiddenProperties()) == 0
+}
+
+// CanViewPrivateCards returns true if the user can see all the private
+// cards of the board.
+func (ca *CardAccess) CanViewPrivateCards() bool {
+	return ca.canViewPrivateCards
+}
+
+// CanViewCard returns true if the user can see a card. Private cards are
+// visible to their creator and their assignees, ie. the users set in
+// their person properties.
+func (ca *CardAccess) CanViewCard(card *Block) bool {
+	if ca.canViewPrivateCards || !IsPrivateCard(card) || card.CreatedBy == ca.userID {
+		return true
+	}
+
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	for _, id := range ca.personProperties {
+		switch value := properties[id].(type) {
+		case string:
+			if value == ca.userID {
+				return true
+			}
+		case []interface{}:
+			for _, userID := range value {
+				if userID == ca.userID {
+					return true
+				}
+			}
+		case []string:
+			for _, userID := range value {
+				if userID == ca.userID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HiddenProperties returns the IDs of the restricted properties whose
+// values the user can't see nor edit.
+func (ca *CardAccess) HiddenProperties() map[string]bool {
+	if ca.canViewRestrictedProperties {
+		return nil
+	}
+	return ca.restrictedProperties
+}
+
+// FilterBlock returns a copy of a block without the values of the
+// properties hidden to the user.
+func (ca *CardAccess) FilterBlock(block *Block) *Block {
+	hidden := ca.HiddenProperties()
+	if len(hidden) == 0 {
+		return block
+	}
+	return block.WithoutProperties(hidden)
+}
+
+// ChangesRestrictedProperties returns true if a board patch changes or
+// deletes restricted card properties of a board, or restricts a property.
+func (p *BoardPatch) ChangesRestrictedProperties(board *Board) bool {
+	restricted := RestrictedCardProperties(board)
+	for _, id := range p.DeletedCardProperties {
+		if restricted[id] {
+			return true
+		}
+	}
+	for _, property := range p.UpdatedCardProperties {
+		id, _ := property["id"].(string)
+		if isRestricted, _ := property[CardPropertyRestrictedField].(bool); isRestricted || restricted[id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cardAccessTestBoard() *Board {
+	return &Board{
+		ID: "board-id",
+		CardProperties: []map[string]any{
+			{"id": "status", "type": "select"},
+			{"id": "salary", "type": "number", CardPropertyRestrictedField: true},
+			{"id": "owner", "type": "person"},
+			{"id": "reviewers", "type": "multiPerson"},
+		},
+	}
+}
+
+func TestCardAccess(t *testing.T) {
+	board := cardAccessTestBoard()
+
+	privateCard := &Block{
+		ID:        "card-id",
+		BoardID:   board.ID,
+		Type:      TypeCard,
+		CreatedBy: "creator-id",
+		Fields: map[string]any{
+			CardPrivateField: true,
+			"properties": map[string]any{
+				"status":    "open",
+				"salary":    "1000",
+				"owner":     "owner-id",
+				"reviewers": []any{"reviewer-id"},
+			},
+		},
+	}
+
+	t.Run("non private cards are visible to everyone", func(t *testing.T) {
+		access := NewCardAccess(board, "user-id", false, false)
+		card := &Block{ID: "card-id", Type: TypeCard, Fields: map[string]any{}}
+		assert.True(t, access.CanViewCard(card))
+	})
+
+	t.Run("private cards are visible to their creator and assignees", func(t *testing.T) {
+		for _, userID := range []string{"creator-id", "owner-id", "reviewer-id"} {
+			access := NewCardAccess(board, userID, false, false)
+			assert.True(t, access.CanViewCard(privateCard), userID)
+		}
+
+		access := NewCardAccess(board, "user-id", false, false)
+		assert.False(t, access.CanViewCard(privateCard))
+
+		access = NewCardAccess(board, "user-id", true, false)
+		assert.True(t, access.CanViewCard(privateCard))
+	})
+
+	t.Run("restricted properties are hidden without the permission", func(t *testing.T) {
+		access := NewCardAccess(board, "user-id", true, false)
+		assert.False(t, access.IsUnrestricted())
+		assert.Equal(t, map[string]bool{"salary": true}, access.HiddenProperties())
+
+		filtered := access.FilterBlock(privateCard)
+		properties := filtered.Fields["properties"].(map[string]any)
+		assert.NotContains(t, properties, "salary")
+		assert.Equal(t, "open", properties["status"])
+
+		// the original block is left untouched
+		require.Contains(t, privateCard.Fields["properties"], "salary")
+
+		access = NewCardAccess(board, "user-id", true, true)
+		assert.True(t, access.IsUnrestricted())
+		assert.Empty(t, access.HiddenProperties())
+		assert.Equal(t, privateCard, access.FilterBlock(privateCard))
+	})
+}
+
+func TestBoardPatchChangesRestrictedProperties(t *testing.T) {
+	board := cardAccessTestBoard()
+
+	testCases := []struct {
+		name     string
+		patch    *BoardPatch
+		expected bool
+	}{
+		{"empty patch", &BoardPatch{}, false},
+		{"updates a non restricted property", &BoardPatch{UpdatedCardProperties: []map[string]any{{"id": "status"}}}, false},
+		{"deletes a non restricted property", &BoardPatch{DeletedCardProperties: []string{"status"}}, false},
+		{"updates a restricted property", &BoardPatch{UpdatedCardProperties: []map[string]any{{"id": "salary"}}}, true},
+		{"deletes a restricted property", &BoardPatch{DeletedCardProperties: []string{"salary"}}, true},
+		{"restricts a property", &BoardPatch{UpdatedCardProperties: []map[string]any{{"id": "status", CardPropertyRestrictedField: true}}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.patch.ChangesRestrictedProperties(board))
+		})
+	}
+}
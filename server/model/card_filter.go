@@ -0,0 +1,274 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// halfDay is the tolerance of the date filters on the created and updated
+// times, which include the time of the day.
+const halfDay = 12 * 60 * 60 * 1000
+
+// CardMatchesViewFilter returns true if a card is shown by a view, ie. it
+// meets the filter of the view. The filter is evaluated the same way as
+// the webapp does, see webapp/src/cardFilter.ts.
+func CardMatchesViewFilter(view *Block, cardProperties []map[string]interface{}, card *Block) bool {
+	filterGroup, ok := view.Fields["filter"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+	return isFilterGroupMet(filterGroup, cardProperties, card)
+}
+
+func isFilterGroup(filter map[string]interface{}) bool {
+	_, hasOperation := filter["operation"]
+	_, hasFilters := filter["filters"]
+	return hasOperation && hasFilters
+}
+
+func isFilterGroupMet(filterGroup map[string]interface{}, cardProperties []map[string]interface{}, card *Block) bool {
+	filters, _ := filterGroup["filters"].([]interface{})
+	if len(filters) == 0 {
+		return true
+	}
+
+	isOr := filterGroup["operation"] == "or"
+	for _, f := range filters {
+		filter, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var met bool
+		if isFilterGroup(filter) {
+			met = isFilterGroupMet(filter, cardProperties, card)
+		} else {
+			met = isFilterClauseMet(filter, cardProperties, card)
+		}
+
+		if isOr && met {
+			return true
+		}
+		if !isOr && !met {
+			return false
+		}
+	}
+	return !isOr
+}
+
+// filterDate is the value of a date property, a single date or a range.
+type filterDate struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+func parseFilterDate(value string) *filterDate {
+	date := &filterDate{}
+	if value == "" {
+		return date
+	}
+	if from, err := strconv.ParseInt(value, 10, 64); err == nil {
+		date.From = from
+		return date
+	}
+
+	var rangeDate struct {
+		From float64 `json:"from"`
+		To   float64 `json:"to"`
+	}
+	if err := json.Unmarshal([]byte(value), &rangeDate); err == nil {
+		date.From = int64(rangeDate.From)
+		date.To = int64(rangeDate.To)
+	}
+	return date
+}
+
+func findCardProperty(cardProperties []map[string]interface{}, id string) map[string]interface{} {
+	for _, property := range cardProperties {
+		if property["id"] == id {
+			return property
+		}
+	}
+	return nil
+}
+
+func isFilterClauseMet(clause map[string]interface{}, cardProperties []map[string]interface{}, card *Block) bool {
+	propertyID, _ := clause["propertyId"].(string)
+	condition, _ := clause["condition"].(string)
+	values := []string{}
+	if rawValues, ok := clause["values"].([]interface{}); ok {
+		for _, v := range rawValues {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var value interface{}
+	if properties, ok := card.Fields["properties"].(map[string]interface{}); ok {
+		value = properties[propertyID]
+	}
+	if propertyID == "title" {
+		value = strings.ToLower(card.Title)
+	}
+
+	property := findCardProperty(cardProperties, propertyID)
+	propertyType := ""
+	if property != nil {
+		propertyType, _ = property["type"].(string)
+	}
+
+	var date *filterDate
+	if propertyType == "date" {
+		s, _ := value.(string)
+		date = parseFilterDate(s)
+	}
+	if !isFilterValueSet(value) && property != nil {
+		switch propertyType {
+		case "createdBy":
+			value = card.CreatedBy
+		case "updatedBy":
+			value = card.ModifiedBy
+		case "createdTime":
+			value = strconv.FormatInt(card.CreateAt, 10)
+			date = parseFilterDate(value.(string))
+		case "updatedTime":
+			value = strconv.FormatInt(card.UpdateAt, 10)
+			date = parseFilterDate(value.(string))
+		}
+	}
+	isTimestamp := propertyType == "createdTime" || propertyType == "updatedTime"
+
+	stringValue, _ := value.(string)
+	filterValue := ""
+	if len(values) > 0 {
+		filterValue = strings.ToLower(values[0])
+	}
+
+	switch condition {
+	case "includes":
+		if len(values) == 0 {
+			return true
+		}
+		return filterValueIncludesAny(value, values)
+	case "notIncludes":
+		if len(values) == 0 {
+			return true
+		}
+		return !filterValueIncludesAny(value, values)
+	case "isEmpty":
+		return filterValueLength(value) == 0
+	case "isNotEmpty":
+		return filterValueLength(value) > 0
+	case "isSet":
+		return isFilterValueSet(value)
+	case "isNotSet":
+		return !isFilterValueSet(value)
+	case "is":
+		if len(values) == 0 {
+			return true
+		}
+		if date != nil {
+			numericFilter, err := strconv.ParseInt(values[0], 10, 64)
+			if err != nil {
+				return false
+			}
+			if isTimestamp {
+				return date.From != 0 && date.From > numericFilter-halfDay && date.From < numericFilter+halfDay
+			}
+			if date.From != 0 && date.To != 0 {
+				return date.From <= numericFilter && date.To >= numericFilter
+			}
+			return date.From == numericFilter
+		}
+		return filterValue == stringValue
+	case "contains":
+		return len(values) == 0 || strings.Contains(stringValue, filterValue)
+	case "notContains":
+		return len(values) == 0 || !strings.Contains(stringValue, filterValue)
+	case "startsWith":
+		return len(values) == 0 || strings.HasPrefix(stringValue, filterValue)
+	case "notStartsWith":
+		return len(values) == 0 || !strings.HasPrefix(stringValue, filterValue)
+	case "endsWith":
+		return len(values) == 0 || strings.HasSuffix(stringValue, filterValue)
+	case "notEndsWith":
+		return len(values) == 0 || !strings.HasSuffix(stringValue, filterValue)
+	case "isBefore":
+		if len(values) == 0 {
+			return true
+		}
+		if date == nil {
+			return false
+		}
+		numericFilter, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil || date.From == 0 {
+			return false
+		}
+		if isTimestamp {
+			return date.From < numericFilter-halfDay
+		}
+		return date.From < numericFilter
+	case "isAfter":
+		if len(values) == 0 {
+			return true
+		}
+		if date == nil {
+			return false
+		}
+		numericFilter, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if isTimestamp {
+			return date.From != 0 && date.From > numericFilter+halfDay
+		}
+		if date.To != 0 {
+			return date.To > numericFilter
+		}
+		return date.From != 0 && date.From > numericFilter
+	}
+	return true
+}
+
+// isFilterValueSet returns true if a property value is truthy, as in
+// javascript.
+func isFilterValueSet(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	}
+	return true
+}
+
+func filterValueLength(value interface{}) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	}
+	return 0
+}
+
+func filterValueIncludesAny(value interface{}, values []string) bool {
+	for _, filterValue := range values {
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				if item == filterValue {
+					return true
+				}
+			}
+		} else if value == filterValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardMatchesViewFilter(t *testing.T) {
+	cardProperties := []map[string]interface{}{
+		{"id": "status", "type": "select"},
+		{"id": "labels", "type": "multiSelect"},
+		{"id": "due", "type": "date"},
+		{"id": "notes", "type": "text"},
+	}
+
+	card := &Block{
+		Type:  TypeCard,
+		Title: "Release Notes",
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"status": "done",
+				"labels": []interface{}{"docs", "web"},
+				"due":    `{"from":1000,"to":3000}`,
+				"notes":  "shipped in v7",
+			},
+		},
+	}
+
+	viewWithFilter := func(t *testing.T, filter string) *Block {
+		var filterGroup map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(filter), &filterGroup))
+		return &Block{Type: TypeView, Fields: map[string]interface{}{"filter": filterGroup}}
+	}
+
+	testCases := []struct {
+		name     string
+		filter   string
+		expected bool
+	}{
+		{"empty filter", `{"operation": "and", "filters": []}`, true},
+		{"select includes", `{"operation": "and", "filters": [{"propertyId": "status", "condition": "includes", "values": ["done"]}]}`, true},
+		{"select not includes", `{"operation": "and", "filters": [{"propertyId": "status", "condition": "notIncludes", "values": ["done"]}]}`, false},
+		{"multi select includes", `{"operation": "and", "filters": [{"propertyId": "labels", "condition": "includes", "values": ["web", "mobile"]}]}`, true},
+		{"not set", `{"operation": "and", "filters": [{"propertyId": "missing", "condition": "isNotSet", "values": []}]}`, true},
+		{"title contains", `{"operation": "and", "filters": [{"propertyId": "title", "condition": "contains", "values": ["notes"]}]}`, true},
+		{"text starts with", `{"operation": "and", "filters": [{"propertyId": "notes", "condition": "startsWith", "values": ["draft"]}]}`, false},
+		{"date in range", `{"operation": "and", "filters": [{"propertyId": "due", "condition": "is", "values": ["2000"]}]}`, true},
+		{"date before", `{"operation": "and", "filters": [{"propertyId": "due", "condition": "isBefore", "values": ["500"]}]}`, false},
+		{"date range after", `{"operation": "and", "filters": [{"propertyId": "due", "condition": "isAfter", "values": ["2000"]}]}`, true},
+		{"and with a failing clause", `{"operation": "and", "filters": [
+			{"propertyId": "status", "condition": "includes", "values": ["done"]},
+			{"propertyId": "labels", "condition": "includes", "values": ["mobile"]}]}`, false},
+		{"or with a met clause", `{"operation": "or", "filters": [
+			{"propertyId": "status", "condition": "includes", "values": ["todo"]},
+			{"propertyId": "labels", "condition": "includes", "values": ["docs"]}]}`, true},
+		{"nested groups", `{"operation": "and", "filters": [
+			{"operation": "or", "filters": [{"propertyId": "status", "condition": "includes", "values": ["todo"]}]}]}`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CardMatchesViewFilter(viewWithFilter(t, tc.filter), cardProperties, card))
+		})
+	}
+
+	t.Run("view without filter", func(t *testing.T) {
+		assert.True(t, CardMatchesViewFilter(&Block{Type: TypeView, Fields: map[string]interface{}{}}, cardProperties, card))
+	})
+}
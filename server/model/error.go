@@ -132,6 +132,23 @@ func (br *ErrForbidden) Error() string {
 	return br.reason
 }
 
+// ErrConflict can be returned when a request conflicts with the
+// current state of a resource.
+type ErrConflict struct {
+	reason string
+}
+
+// NewErrConflict creates a new ErrConflict instance.
+func NewErrConflict(reason string) *ErrConflict {
+	return &ErrConflict{
+		reason: reason,
+	}
+}
+
+func (c *ErrConflict) Error() string {
+	return c.reason
+}
+
 type ErrInvalidCategory struct {
 	msg string
 }
@@ -146,6 +163,25 @@ func (e *ErrInvalidCategory) Error() string {
 	return e.msg
 }
 
+// ErrTooManyRequests is returned when a client makes too many requests,
+// like failed login attempts, and has to wait before retrying.
+type ErrTooManyRequests struct {
+	msg string
+	// RetryAfter is the number of seconds to wait before retrying
+	RetryAfter int64
+}
+
+func NewErrTooManyRequests(msg string, retryAfter int64) *ErrTooManyRequests {
+	return &ErrTooManyRequests{
+		msg:        msg,
+		RetryAfter: retryAfter,
+	}
+}
+
+func (tmr *ErrTooManyRequests) Error() string {
+	return tmr.msg
+}
+
 type ErrNotImplemented struct {
 	msg string
 }
@@ -311,6 +347,27 @@ func IsErrRequestEntityTooLarge(err error) bool {
 	return errors.Is(err, ErrRequestEntityTooLarge)
 }
 
+// IsErrConflict returns true if `err` is or wraps a model.ErrConflict.
+func IsErrConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var c *ErrConflict
+	return errors.As(err, &c)
+}
+
+// IsErrTooManyRequests returns true if `err` is or wraps a
+// model.ErrTooManyRequests.
+func IsErrTooManyRequests(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var tmr *ErrTooManyRequests
+	return errors.As(err, &tmr)
+}
+
 // IsErrNotImplemented returns true if `err` is or wraps one of:
 // - model.ErrNotImplemented
 // - model.ErrInsufficientLicense.
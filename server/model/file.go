@@ -3,6 +3,8 @@
 package model
 
 import (
+	"errors"
+	"fmt"
 	"mime"
 	"path/filepath"
 	"strings"
@@ -23,3 +25,47 @@ func NewFileInfo(name string) *mm_model.FileInfo {
 		MimeType:  mime.TypeByExtension(extension),
 	}
 }
+
+// FileSize is the size in which an image file is served.
+type FileSize string
+
+const (
+	// FileSizeOriginal is the file as it was uploaded.
+	FileSizeOriginal FileSize = ""
+	// FileSizeThumb is a small version of an image, used for card covers.
+	FileSizeThumb FileSize = "thumb"
+	// FileSizePreview is a version of an image fitting a screen.
+	FileSizePreview FileSize = "preview"
+)
+
+var ErrInvalidFileSize = errors.New("invalid file size")
+
+// ParseFileSize returns the file size with the given name. An empty name
+// is the original size.
+func ParseFileSize(s string) (FileSize, error) {
+	switch FileSize(s) {
+	case FileSizeOriginal, FileSizeThumb, FileSizePreview:
+		return FileSize(s), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidFileSize, s)
+}
+
+// The query parameters of a signed file URL.
+const (
+	SignedURLExpiresParam   = "expires"
+	SignedURLUserParam      = "user"
+	SignedURLSignatureParam = "signature"
+)
+
+// SignedFileURL is a short-lived URL to download a file without a session,
+// with the permissions of the user it was generated for.
+// swagger:model
+type SignedFileURL struct {
+	// The URL of the file
+	// required: true
+	URL string `json:"url"`
+
+	// The expiry time of the URL in milliseconds since the current epoch
+	// required: true
+	ExpiresAt int64 `json:"expiresAt"`
+}
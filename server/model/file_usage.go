@@ -0,0 +1,79 @@
+package model
+
+// FileUsage is the storage used by an uploaded file, tracked per team and
+// board.
+type FileUsage struct {
+	// The ID of the file info
+	FileID string `json:"fileId"`
+
+	// The team of the board the file belongs to
+	TeamID string `json:"teamId"`
+
+	// The board the file belongs to
+	BoardID string `json:"boardId"`
+
+	// The name of the file as referenced by the blocks
+	FileName string `json:"fileName"`
+
+	// The path of the file in the files storage
+	Path string `json:"path"`
+
+	// The size of the file in bytes
+	Size int64 `json:"size"`
+
+	// The creation time in milliseconds since the current epoch
+	CreateAt int64 `json:"createAt"`
+}
+
+// BoardFileUsage is the storage used by the files of a board.
+// swagger:model
+type BoardFileUsage struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The number of files
+	// required: true
+	Files int64 `json:"files"`
+
+	// The size of the files in bytes
+	// required: true
+	Size int64 `json:"size"`
+}
+
+// TeamFileUsage is the storage used by the files of a team.
+// swagger:model
+type TeamFileUsage struct {
+	// The ID of the team
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The number of files
+	// required: true
+	Files int64 `json:"files"`
+
+	// The size of the files in bytes
+	// required: true
+	Size int64 `json:"size"`
+
+	// The storage quota of the team in bytes, 0 if unlimited
+	// required: true
+	Quota int64 `json:"quota"`
+
+	// The usage of each board of the team
+	// required: true
+	Boards []*BoardFileUsage `json:"boards"`
+}
+
+// OrphanedFilesCleanup is the outcome of a cleanup of the files that are
+// no longer referenced by any block.
+// swagger:model
+type OrphanedFilesCleanup struct {
+	// The number of files deleted
+	// required: true
+	Files int `json:"files"`
+
+	// The size of the files deleted in bytes
+	// required: true
+	Size int64 `json:"size"`
+}
@@ -0,0 +1,66 @@
+package model
+
+// BlockHistoryDiff is the difference between two versions of a block
+// swagger:model
+type BlockHistoryDiff struct {
+	// The id of the block
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The older version of the block, nil if it didn't exist yet
+	// required: false
+	OldBlock *Block `json:"oldBlock"`
+
+	// The newer version of the block, nil if it didn't exist yet
+	// required: false
+	NewBlock *Block `json:"newBlock"`
+
+	// The properties that changed between the two versions
+	// required: true
+	PropDiffs []PropDiff `json:"propDiffs"`
+
+	// The differences of the child blocks that changed, e.g. the
+	// contents of a card
+	// required: true
+	Children []*BlockHistoryDiff `json:"children"`
+}
+
+// HistoryRestoreRequest is the point in time to restore a board or
+// a block to
+// swagger:model
+type HistoryRestoreRequest struct {
+	// The update_at of the revision to restore
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// Also restores the child blocks, e.g. the contents of a card.
+	// Ignored when restoring a board, which always includes its blocks
+	// required: false
+	IncludeChildren bool `json:"includeChildren"`
+}
+
+// HistoryRestoreResult contains the changes made when restoring a
+// board or a block to a previous revision
+// swagger:model
+type HistoryRestoreResult struct {
+	// The restored board, only present when a board is restored
+	// required: false
+	Board *Board `json:"board,omitempty"`
+
+	// The blocks that were restored
+	// required: true
+	Blocks []*Block `json:"blocks"`
+
+	// The ids of the blocks that didn't exist at the time of the
+	// revision and were deleted
+	// required: true
+	DeletedBlockIDs []string `json:"deletedBlockIds"`
+}
+
+// IsValid checks that the restore request points to a revision.
+func (r *HistoryRestoreRequest) IsValid() error {
+	if r.UpdateAt <= 0 {
+		return NewErrBadRequest("updateAt is required")
+	}
+	return nil
+}
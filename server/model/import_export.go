@@ -38,16 +38,254 @@ type ExportArchiveOptions struct {
 	// BoardIDs is the list of boards to include in the archive.
 	// Empty slice means export all boards from workspace/team.
 	BoardIDs []string
+
+	// UserID is the user the archive is exported for, the private cards
+	// and the restricted properties hidden to the user are left out.
+	// Empty means a full export, eg. for the backups.
+	UserID string
+}
+
+// ImportMode defines how an archive is imported when it contains boards
+// that already exist.
+type ImportMode string
+
+const (
+	// ImportModeCreate imports the boards as new boards, with new IDs.
+	ImportModeCreate ImportMode = "create"
+	// ImportModeOverwrite replaces the existing boards with the ones in the
+	// archive. Blocks missing from the archive are deleted.
+	ImportModeOverwrite ImportMode = "overwrite"
+	// ImportModeMerge only updates the blocks of the existing boards that
+	// are older than the ones in the archive, and reports the conflicts.
+	ImportModeMerge ImportMode = "merge"
+)
+
+var ErrInvalidImportMode = errors.New("invalid import mode")
+
+// ParseImportMode returns the import mode with the given name. An empty
+// name is the create mode.
+func ParseImportMode(s string) (ImportMode, error) {
+	switch ImportMode(s) {
+	case "", ImportModeCreate:
+		return ImportModeCreate, nil
+	case ImportModeOverwrite, ImportModeMerge:
+		return ImportMode(s), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidImportMode, s)
 }
 
 // ImportArchiveOptions provides options when importing an archive.
 type ImportArchiveOptions struct {
 	TeamID        string
 	ModifiedBy    string
+	Mode          ImportMode
 	BoardModifier BoardModifier
 	BlockModifier BlockModifier
 }
 
+// ImportConflict describes an element of an archive that was not imported
+// because it conflicts with the existing data.
+// swagger:model
+type ImportConflict struct {
+	// The ID of the board in the archive
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the block in the archive, empty for the board itself
+	// required: false
+	BlockID string `json:"blockId,omitempty"`
+
+	// The title of the board or block
+	// required: true
+	Title string `json:"title"`
+
+	// Why the element was not imported
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// ImportArchiveResult is the outcome of an archive import.
+// swagger:model
+type ImportArchiveResult struct {
+	// The import mode
+	// required: true
+	Mode ImportMode `json:"mode"`
+
+	// The IDs of the boards that were created
+	// required: true
+	BoardsCreated []string `json:"boardsCreated"`
+
+	// The IDs of the existing boards that were updated
+	// required: true
+	BoardsUpdated []string `json:"boardsUpdated"`
+
+	// The number of blocks created
+	// required: true
+	BlocksCreated int `json:"blocksCreated"`
+
+	// The number of existing blocks updated
+	// required: true
+	BlocksUpdated int `json:"blocksUpdated"`
+
+	// The number of existing blocks deleted because they are not in the archive
+	// required: true
+	BlocksDeleted int `json:"blocksDeleted"`
+
+	// The elements that were not imported
+	// required: true
+	Conflicts []ImportConflict `json:"conflicts"`
+}
+
+// NewImportArchiveResult returns an empty result for the given mode.
+func NewImportArchiveResult(mode ImportMode) *ImportArchiveResult {
+	return &ImportArchiveResult{
+		Mode:          mode,
+		BoardsCreated: []string{},
+		BoardsUpdated: []string{},
+		Conflicts:     []ImportConflict{},
+	}
+}
+
+// ImportSkippedItem describes an element of an external export that
+// could not be imported.
+// swagger:model
+type ImportSkippedItem struct {
+	// The kind of element that was skipped, i.e. card, comment or attachment
+	// required: true
+	Type string `json:"type"`
+
+	// The name of the skipped element in the source export
+	// required: true
+	Name string `json:"name"`
+
+	// Why the element was skipped
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// ImportReport summarizes the result of importing an export from an
+// external tool.
+// swagger:model
+type ImportReport struct {
+	// The tool the export was generated by
+	// required: true
+	Source string `json:"source"`
+
+	// The IDs of the boards created by the import
+	// required: true
+	BoardIDs []string `json:"boardIds"`
+
+	// The number of cards created
+	// required: true
+	Cards int `json:"cards"`
+
+	// The number of comments created
+	// required: true
+	Comments int `json:"comments"`
+
+	// The number of attachments created
+	// required: true
+	Attachments int `json:"attachments"`
+
+	// The elements of the export that were not imported
+	// required: true
+	Skipped []ImportSkippedItem `json:"skipped"`
+}
+
+// ImportCardsOptions provides options when importing cards from a
+// spreadsheet into a board.
+type ImportCardsOptions struct {
+	ModifiedBy string
+
+	// DryRun computes the changes without applying them.
+	DryRun bool
+}
+
+// CardsImportProperty is a card property created by an import.
+// swagger:model
+type CardsImportProperty struct {
+	// The ID of the property
+	// required: true
+	ID string `json:"id"`
+
+	// The name of the property, from the column header
+	// required: true
+	Name string `json:"name"`
+
+	// The type of the property
+	// required: true
+	Type string `json:"type"`
+}
+
+// CardsImportOption is a property option created by an import.
+// swagger:model
+type CardsImportOption struct {
+	// The name of the property the option is added to
+	// required: true
+	Property string `json:"property"`
+
+	// The value of the option
+	// required: true
+	Value string `json:"value"`
+}
+
+// CardsImportChange is a card created or updated by an import.
+// swagger:model
+type CardsImportChange struct {
+	// The spreadsheet row, starting at 1 for the header
+	// required: true
+	Row int `json:"row"`
+
+	// Either "create" or "update"
+	// required: true
+	Action string `json:"action"`
+
+	// The ID of the card, empty for cards not created yet
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The title of the card
+	// required: true
+	Title string `json:"title"`
+
+	// The previous title of the card, if changed
+	// required: false
+	OldTitle string `json:"oldTitle,omitempty"`
+
+	// The changed properties
+	// required: true
+	Properties []PropDiff `json:"properties"`
+}
+
+// CardsImportResult describes the changes made by a cards import, or the
+// ones that would be made for a dry run.
+// swagger:model
+type CardsImportResult struct {
+	// Whether the changes were only previewed
+	// required: true
+	DryRun bool `json:"dryRun"`
+
+	// The card properties created for unknown columns
+	// required: true
+	NewProperties []CardsImportProperty `json:"newProperties"`
+
+	// The options added to select properties
+	// required: true
+	NewOptions []CardsImportOption `json:"newOptions"`
+
+	// The created and updated cards
+	// required: true
+	Changes []CardsImportChange `json:"changes"`
+
+	// The number of rows matching a card without changes
+	// required: true
+	Unchanged int `json:"unchanged"`
+
+	// The values that could not be imported
+	// required: true
+	Warnings []string `json:"warnings"`
+}
+
 // ErrUnsupportedArchiveVersion is an error returned when trying to import an
 // archive with a version that this server does not support.
 type ErrUnsupportedArchiveVersion struct {
@@ -15,6 +15,8 @@ type BoardInvitation struct {
 	UsedAt           *int64    `json:"usedAt,omitempty" db:"used_at"`
 	UsedBy           *string   `json:"usedBy,omitempty" db:"used_by"`
 	LastSentAt       *int64    `json:"lastSentAt,omitempty" db:"last_sent_at"`
+	IsGuest          bool      `json:"isGuest" db:"is_guest"`
+	GuestExpireAt    int64     `json:"guestExpireAt,omitempty" db:"guest_expire_at"`
 	ResendCooldownSeconds int   `json:"resendCooldownSeconds,omitempty" db:"-"` // Calculated field, not stored
 }
 
@@ -22,6 +24,11 @@ type BoardInvitation struct {
 type BoardInviteRequest struct {
 	Email string `json:"email"`
 	Role  string `json:"role"`
+
+	// Guest invitations create a guest account on acceptance, which
+	// expires at GuestExpireAt, in milliseconds, if set
+	Guest         bool  `json:"guest,omitempty"`
+	GuestExpireAt int64 `json:"guestExpireAt,omitempty"`
 }
 
 // IsExpired checks if the invitation has expired
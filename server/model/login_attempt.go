@@ -0,0 +1,79 @@
+package model
+
+const (
+	// LoginAttemptKindUser is the kind of the failed login attempts on an
+	// account, identified by the user ID, or by the login name for the
+	// unknown accounts.
+	LoginAttemptKindUser = "user"
+	// LoginAttemptKindIP is the kind of the failed login attempts from a
+	// client IP address.
+	LoginAttemptKindIP = "ip"
+	// LoginAttemptKindShareLink is the kind of the failed attempts to
+	// unlock a password protected share link, identified by the link ID.
+	LoginAttemptKindShareLink = "share_link"
+)
+
+// LoginAttempt tracks the failed login attempts on an account, or from a
+// client IP address, to throttle and lock out the password guessing.
+// swagger:model
+type LoginAttempt struct {
+	// The kind of the attempts, user, ip or share_link
+	// required: true
+	Kind string `json:"kind"`
+
+	// The user ID, login name, IP address or share link ID the attempts
+	// are made on
+	// required: true
+	Identifier string `json:"identifier"`
+
+	// The number of consecutive failed attempts
+	// required: true
+	FailedCount int `json:"failedCount"`
+
+	// The time of the last failed attempt in milliseconds since the
+	// current epoch
+	// required: true
+	LastFailureAt int64 `json:"lastFailureAt"`
+
+	// The time the lockout ends in milliseconds since the current epoch,
+	// 0 if not locked out
+	// required: true
+	LockedUntil int64 `json:"lockedUntil"`
+
+	// The token to unlock the account from the lockout email
+	// swagger:ignore
+	UnlockToken string `json:"-"`
+}
+
+// IsLocked returns true if the attempts are locked out at the given time.
+func (la *LoginAttempt) IsLocked(now int64) bool {
+	return la.LockedUntil > now
+}
+
+// AccountUnlockRequest is the request to unlock an account locked out
+// after too many failed logins, with the token sent by email.
+// swagger:model
+type AccountUnlockRequest struct {
+	// The unlock token
+	// required: true
+	Token string `json:"token"`
+}
+
+// ErrLoginLockout is returned when a failed login locks out accounts or
+// client IP addresses, with the attempts that got locked out, so the
+// lockouts can be audited.
+type ErrLoginLockout struct {
+	*ErrTooManyRequests
+	Attempts []*LoginAttempt
+}
+
+func NewErrLoginLockout(retryAfter int64, attempts []*LoginAttempt) *ErrLoginLockout {
+	return &ErrLoginLockout{
+		ErrTooManyRequests: NewErrTooManyRequests("too many failed login attempts", retryAfter),
+		Attempts:           attempts,
+	}
+}
+
+func (ll *ErrLoginLockout) Unwrap() error {
+	return ll.ErrTooManyRequests
+}
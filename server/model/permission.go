@@ -7,6 +7,7 @@ import (
 var (
 	PermissionViewTeam              = mmModel.PermissionViewTeam
 	PermissionManageTeam            = mmModel.PermissionManageTeam
+	PermissionCreateTeam            = mmModel.PermissionCreateTeam
 	PermissionManageSystem          = mmModel.PermissionManageSystem
 	PermissionReadChannel           = mmModel.PermissionReadChannel
 	PermissionCreatePost            = mmModel.PermissionCreatePost
@@ -15,11 +16,44 @@ var (
 	PermissionCreatePrivateChannel  = mmModel.PermissionCreatePrivateChannel
 	PermissionManageBoardType       = &mmModel.Permission{Id: "manage_board_type", Name: "", Description: "", Scope: ""}
 	PermissionDeleteBoard           = &mmModel.Permission{Id: "delete_board", Name: "", Description: "", Scope: ""}
+	PermissionArchiveBoard          = &mmModel.Permission{Id: "archive_board", Name: "", Description: "", Scope: ""}
 	PermissionViewBoard             = &mmModel.Permission{Id: "view_board", Name: "", Description: "", Scope: ""}
 	PermissionManageBoardRoles      = &mmModel.Permission{Id: "manage_board_roles", Name: "", Description: "", Scope: ""}
 	PermissionShareBoard            = &mmModel.Permission{Id: "share_board", Name: "", Description: "", Scope: ""}
 	PermissionManageBoardCards      = &mmModel.Permission{Id: "manage_board_cards", Name: "", Description: "", Scope: ""}
+	PermissionMoveBoardCards        = &mmModel.Permission{Id: "move_board_cards", Name: "", Description: "", Scope: ""}
 	PermissionManageBoardProperties = &mmModel.Permission{Id: "manage_board_properties", Name: "", Description: "", Scope: ""}
 	PermissionCommentBoardCards     = &mmModel.Permission{Id: "comment_board_cards", Name: "", Description: "", Scope: ""}
 	PermissionDeleteOthersComments  = &mmModel.Permission{Id: "delete_others_comments", Name: "", Description: "", Scope: ""}
+	PermissionViewPrivateCards      = &mmModel.Permission{Id: "view_private_cards", Name: "", Description: "", Scope: ""}
+	PermissionViewRestrictedProps   = &mmModel.Permission{Id: "view_restricted_properties", Name: "", Description: "", Scope: ""}
 )
+
+// BoardPermissions are the permissions granted on a board, which custom
+// board roles are made of.
+var BoardPermissions = []*mmModel.Permission{
+	PermissionViewBoard,
+	PermissionViewPrivateCards,
+	PermissionViewRestrictedProps,
+	PermissionCommentBoardCards,
+	PermissionMoveBoardCards,
+	PermissionManageBoardCards,
+	PermissionManageBoardProperties,
+	PermissionDeleteOthersComments,
+	PermissionShareBoard,
+	PermissionManageBoardRoles,
+	PermissionManageBoardType,
+	PermissionArchiveBoard,
+	PermissionDeleteBoard,
+}
+
+// BoardPermissionByID returns the board permission with an ID, or nil if
+// there is none.
+func BoardPermissionByID(id string) *mmModel.Permission {
+	for _, permission := range BoardPermissions {
+		if permission.Id == id {
+			return permission
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/utils"
@@ -38,6 +39,15 @@ type BlockProp struct {
 	Value string `json:"value"`
 }
 
+// PropDiff represents the change of a property between two versions of a block.
+type PropDiff struct {
+	ID       string `json:"id"` // property id
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
 // PropSchema is a map of PropDef's keyed by property id.
 type PropSchema map[string]PropDef
 
@@ -271,3 +281,57 @@ func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver
 	}
 	return props, nil
 }
+
+// DiffProperties compares the properties of two versions of a block and returns
+// the ones that were added, changed or deleted, sorted by their index.
+func DiffProperties(oldProps, newProps BlockProperties) []PropDiff {
+	var propDiffs []PropDiff
+
+	// look for new or changed properties.
+	for k, prop := range newProps {
+		oldP, ok := oldProps[k]
+		if ok {
+			// prop changed
+			if prop.Value != oldP.Value {
+				propDiffs = append(propDiffs, PropDiff{
+					ID:       prop.ID,
+					Index:    prop.Index,
+					Name:     prop.Name,
+					NewValue: prop.Value,
+					OldValue: oldP.Value,
+				})
+			}
+		} else {
+			// prop added
+			propDiffs = append(propDiffs, PropDiff{
+				ID:       prop.ID,
+				Index:    prop.Index,
+				Name:     prop.Name,
+				NewValue: prop.Value,
+				OldValue: "",
+			})
+		}
+	}
+
+	// look for deleted properties
+	for k, prop := range oldProps {
+		_, ok := newProps[k]
+		if !ok {
+			// prop deleted
+			propDiffs = append(propDiffs, PropDiff{
+				ID:       prop.ID,
+				Index:    prop.Index,
+				Name:     prop.Name,
+				NewValue: "",
+				OldValue: prop.Value,
+			})
+		}
+	}
+
+	if len(propDiffs) != 0 {
+		sort.Slice(propDiffs, func(i, j int) bool {
+			return propDiffs[i].Index < propDiffs[j].Index
+		})
+	}
+	return propDiffs
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	// SessionPropUserAgent is the session property holding the user agent
+	// of the client that logged in.
+	SessionPropUserAgent = "userAgent"
+	// SessionPropIPAddress is the session property holding the IP address
+	// of the client that logged in.
+	SessionPropIPAddress = "ipAddress"
+)
+
+// SessionClient is the client a session is created from.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionInfo is a session of a user, without its token, as listed to
+// the user to review and revoke its sessions.
+// swagger:model
+type SessionInfo struct {
+	// The ID of the session
+	// required: true
+	ID string `json:"id"`
+
+	// The authentication service of the session
+	// required: false
+	AuthService string `json:"authService,omitempty"`
+
+	// The user agent of the client that logged in
+	// required: false
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// The IP address of the client that logged in
+	// required: false
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// The login time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last activity time in milliseconds since the current epoch
+	// required: true
+	LastActivityAt int64 `json:"lastActivityAt"`
+
+	// If the session is the one of the current request
+	// required: true
+	Current bool `json:"current"`
+}
+
+func NewSessionInfo(session *Session, currentSessionID string) *SessionInfo {
+	userAgent, _ := session.Props[SessionPropUserAgent].(string)
+	ipAddress, _ := session.Props[SessionPropIPAddress].(string)
+
+	return &SessionInfo{
+		ID:             session.ID,
+		AuthService:    session.AuthService,
+		UserAgent:      userAgent,
+		IPAddress:      ipAddress,
+		CreateAt:       session.CreateAt,
+		LastActivityAt: session.UpdateAt,
+		Current:        session.ID == currentSessionID,
+	}
+}
+
+func SessionInfosFromJSON(data io.Reader) []*SessionInfo {
+	var sessions []*SessionInfo
+	_ = json.NewDecoder(data).Decode(&sessions)
+	return sessions
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// shareLinkProofSeparator separates the token of a password protected
+// share link from the proof that the password was provided.
+const shareLinkProofSeparator = "."
+
+// ShareLinkNameMaxLength is the maximum length of the name of a share link.
+const ShareLinkNameMaxLength = 100
+
+// ShareLink is a public link to a board, optionally restricted to a view
+// and hiding some card properties. A board can have several share links,
+// each revocable on its own.
+// swagger:model
+type ShareLink struct {
+	// The ID of the share link
+	// required: true
+	ID string `json:"id"`
+
+	// The board shared by the link
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The read token of the link
+	// required: true
+	Token string `json:"token"`
+
+	// A name describing the link for the board admins
+	// required: false
+	Name string `json:"name"`
+
+	// The view the link is restricted to, only the cards of the view are
+	// shared if set
+	// required: false
+	ViewID string `json:"viewId"`
+
+	// The IDs of the card properties hidden from the link
+	// required: false
+	HiddenProperties []string `json:"hiddenProperties"`
+
+	// The expiry time of the link in milliseconds since the current epoch,
+	// 0 if the link doesn't expire
+	// required: false
+	ExpiresAt int64 `json:"expiresAt"`
+
+	// The password required to open the link, only set when creating or
+	// changing it
+	// required: false
+	Password string `json:"password,omitempty"`
+
+	// The bcrypt hash of the password
+	PasswordHash string `json:"-"`
+
+	// True if the link is protected by a password
+	// required: true
+	HasPassword bool `json:"hasPassword"`
+
+	// The number of times the link has been opened
+	// required: true
+	ViewCount int64 `json:"viewCount"`
+
+	// The last time the link was opened in milliseconds since the current
+	// epoch
+	// required: true
+	LastViewedAt int64 `json:"lastViewedAt"`
+
+	// The ID of the user that created the link
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// ShareLinkPatch is a patch to update the settings of a share link.
+// swagger:model
+type ShareLinkPatch struct {
+	// The name of the link
+	// required: false
+	Name *string `json:"name"`
+
+	// The view the link is restricted to, empty for the whole board
+	// required: false
+	ViewID *string `json:"viewId"`
+
+	// The IDs of the hidden card properties
+	// required: false
+	HiddenProperties *[]string `json:"hiddenProperties"`
+
+	// The expiry time of the link, 0 for no expiry
+	// required: false
+	ExpiresAt *int64 `json:"expiresAt"`
+
+	// The new password of the link, empty to remove the password
+	// required: false
+	Password *string `json:"password"`
+}
+
+// ShareLinkUnlockRequest is the password of a protected share link.
+// swagger:model
+type ShareLinkUnlockRequest struct {
+	// The token of the share link
+	// required: true
+	Token string `json:"token"`
+
+	// The password of the share link
+	// required: true
+	Password string `json:"password"`
+}
+
+// ShareLinkUnlockResponse is the read token to open a password protected
+// share link.
+// swagger:model
+type ShareLinkUnlockResponse struct {
+	// The read token to use in the read_token parameter
+	// required: true
+	ReadToken string `json:"readToken"`
+}
+
+// IsValid checks the settings of a share link.
+func (l *ShareLink) IsValid() error {
+	if l.BoardID == "" {
+		return NewErrBadRequest("share link board ID is required")
+	}
+	if len(l.Name) > ShareLinkNameMaxLength {
+		return NewErrBadRequest("share link name is too long")
+	}
+	if l.ExpiresAt < 0 {
+		return NewErrBadRequest("share link expiry must not be negative")
+	}
+	return nil
+}
+
+// Patch applies the changes of a patch to a share link, except for the
+// password that needs to be hashed.
+func (p *ShareLinkPatch) Patch(link *ShareLink) *ShareLink {
+	if p.Name != nil {
+		link.Name = *p.Name
+	}
+	if p.ViewID != nil {
+		link.ViewID = *p.ViewID
+	}
+	if p.HiddenProperties != nil {
+		link.HiddenProperties = *p.HiddenProperties
+	}
+	if p.ExpiresAt != nil {
+		link.ExpiresAt = *p.ExpiresAt
+	}
+	return link
+}
+
+// IsExpired returns true if the link has expired at the given time.
+func (l *ShareLink) IsExpired(now int64) bool {
+	return l.ExpiresAt > 0 && l.ExpiresAt <= now
+}
+
+// IsScoped returns true if the link doesn't share the whole board.
+func (l *ShareLink) IsScoped() bool {
+	return l.ViewID != "" || len(l.HiddenProperties) > 0
+}
+
+// Sanitize removes the secrets of the link before sending it.
+func (l *ShareLink) Sanitize() {
+	l.Password = ""
+	l.HasPassword = l.PasswordHash != ""
+}
+
+// UnlockedReadToken returns the read token of a password protected link,
+// which proves the password was provided. The token is invalidated when
+// the password changes.
+func (l *ShareLink) UnlockedReadToken() string {
+	return l.Token + shareLinkProofSeparator + l.passwordProof()
+}
+
+// CheckReadToken checks that a read token opens the link. Password
+// protected links require an unlocked read token.
+func (l *ShareLink) CheckReadToken(readToken string) bool {
+	token, proof, _ := strings.Cut(readToken, shareLinkProofSeparator)
+	if token != l.Token {
+		return false
+	}
+	if l.PasswordHash == "" {
+		return true
+	}
+	return hmac.Equal([]byte(proof), []byte(l.passwordProof()))
+}
+
+func (l *ShareLink) passwordProof() string {
+	mac := hmac.New(sha256.New, []byte(l.PasswordHash))
+	mac.Write([]byte(l.Token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ShareLinkTokenFromReadToken returns the token of the share link opened
+// by a read token.
+func ShareLinkTokenFromReadToken(readToken string) string {
+	token, _, _ := strings.Cut(readToken, shareLinkProofSeparator)
+	return token
+}
@@ -2,7 +2,13 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"strings"
+)
+
+const (
+	TeamTitleMaxLength = 256
 )
 
 // Team is information global to a team
@@ -33,6 +39,54 @@ type Team struct {
 	UpdateAt int64 `json:"updateAt"`
 }
 
+// TeamPatch is a patch for modify a team
+// swagger:model
+type TeamPatch struct {
+	// The title of the team
+	// required: false
+	Title *string `json:"title"`
+}
+
+// TeamMember stores the membership of a user in a team
+// swagger:model
+type TeamMember struct {
+	// The ID of the team
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// Marks the user as an admin of the team
+	// required: true
+	SchemeAdmin bool `json:"schemeAdmin"`
+
+	// Created time in miliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// IsValid checks the title of a team.
+func (t *Team) IsValid() error {
+	title := strings.TrimSpace(t.Title)
+	if title == "" {
+		return NewErrBadRequest("team title cannot be empty")
+	}
+	if len(title) > TeamTitleMaxLength {
+		return NewErrBadRequest(fmt.Sprintf("team title cannot be longer than %d characters", TeamTitleMaxLength))
+	}
+	return nil
+}
+
+// Patch returns an updated version of the team.
+func (p *TeamPatch) Patch(team *Team) *Team {
+	if p.Title != nil {
+		team.Title = strings.TrimSpace(*p.Title)
+	}
+	return team
+}
+
 func TeamFromJSON(data io.Reader) *Team {
 	var team *Team
 	_ = json.NewDecoder(data).Decode(&team)
@@ -44,3 +98,15 @@ func TeamsFromJSON(data io.Reader) []*Team {
 	_ = json.NewDecoder(data).Decode(&teams)
 	return teams
 }
+
+func TeamMemberFromJSON(data io.Reader) *TeamMember {
+	var member *TeamMember
+	_ = json.NewDecoder(data).Decode(&member)
+	return member
+}
+
+func TeamMembersFromJSON(data io.Reader) []*TeamMember {
+	var members []*TeamMember
+	_ = json.NewDecoder(data).Decode(&members)
+	return members
+}
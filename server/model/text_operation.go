@@ -0,0 +1,317 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+var (
+	ErrTextOperationLengthMismatch = errors.New("text operation base length doesn't match the document length")
+	ErrTextOperationsIncompatible  = errors.New("text operations have different base lengths")
+	ErrTextOperationInvalid        = errors.New("invalid text operation component")
+)
+
+// TextOperation is an operational transformation over the title of a
+// text block. It is a sequence of components that walk the whole
+// document: a retain keeps the next characters unchanged, an insert
+// adds new text at the current position and a delete removes the
+// next characters. Lengths are counted in Unicode code points.
+//
+// It is serialized as a JSON array where positive integers are
+// retains, negative integers are deletes and strings are inserts,
+// e.g. [5, "abc", -2, 3].
+// swagger:model
+type TextOperation struct {
+	components   []textOpComponent
+	baseLength   int
+	targetLength int
+}
+
+type textOpComponent struct {
+	retain int
+	insert string
+	delete int
+}
+
+func (c textOpComponent) isRetain() bool { return c.retain > 0 }
+func (c textOpComponent) isInsert() bool { return c.insert != "" }
+func (c textOpComponent) isDelete() bool { return c.delete > 0 }
+
+// TextEditState is the current content of a text block that is being
+// collaboratively edited, along with the revision that text
+// operations should be based on.
+// swagger:model
+type TextEditState struct {
+	// The id of the text block
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The id of the board the block belongs to
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The revision of the text
+	// required: true
+	Revision int64 `json:"revision"`
+
+	// The current text
+	// required: true
+	Text string `json:"text"`
+}
+
+// TextChange is a text operation that has been applied to a block,
+// already transformed against any concurrent operation.
+// swagger:model
+type TextChange struct {
+	// The id of the text block
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The id of the board the block belongs to
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The revision produced by applying the operation
+	// required: true
+	Revision int64 `json:"revision"`
+
+	// The operation that was applied
+	// required: true
+	Operation *TextOperation `json:"operation"`
+
+	// The id of the user who made the change
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+}
+
+func NewTextOperation() *TextOperation {
+	return &TextOperation{components: []textOpComponent{}}
+}
+
+// BaseLength is the length of the documents the operation can be applied to.
+func (o *TextOperation) BaseLength() int {
+	return o.baseLength
+}
+
+// TargetLength is the length of the document after the operation is applied.
+func (o *TextOperation) TargetLength() int {
+	return o.targetLength
+}
+
+// IsNoop returns true if applying the operation leaves the document unchanged.
+func (o *TextOperation) IsNoop() bool {
+	for _, c := range o.components {
+		if !c.isRetain() {
+			return false
+		}
+	}
+	return true
+}
+
+// Retain skips over the next n characters of the document.
+func (o *TextOperation) Retain(n int) *TextOperation {
+	if n <= 0 {
+		return o
+	}
+	o.baseLength += n
+	o.targetLength += n
+
+	if last := len(o.components) - 1; last >= 0 && o.components[last].isRetain() {
+		o.components[last].retain += n
+		return o
+	}
+	o.components = append(o.components, textOpComponent{retain: n})
+	return o
+}
+
+// Insert adds text at the current position of the document.
+func (o *TextOperation) Insert(text string) *TextOperation {
+	if text == "" {
+		return o
+	}
+	o.targetLength += utf8.RuneCountInString(text)
+
+	last := len(o.components) - 1
+	switch {
+	case last >= 0 && o.components[last].isInsert():
+		o.components[last].insert += text
+	case last >= 0 && o.components[last].isDelete():
+		// inserts always go before deletes at the same position, so
+		// equivalent operations have the same representation
+		if last > 0 && o.components[last-1].isInsert() {
+			o.components[last-1].insert += text
+		} else {
+			del := o.components[last]
+			o.components[last] = textOpComponent{insert: text}
+			o.components = append(o.components, del)
+		}
+	default:
+		o.components = append(o.components, textOpComponent{insert: text})
+	}
+	return o
+}
+
+// Delete removes the next n characters of the document.
+func (o *TextOperation) Delete(n int) *TextOperation {
+	if n <= 0 {
+		return o
+	}
+	o.baseLength += n
+
+	if last := len(o.components) - 1; last >= 0 && o.components[last].isDelete() {
+		o.components[last].delete += n
+		return o
+	}
+	o.components = append(o.components, textOpComponent{delete: n})
+	return o
+}
+
+// Apply returns the result of applying the operation to a document.
+func (o *TextOperation) Apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if len(runes) != o.baseLength {
+		return "", ErrTextOperationLengthMismatch
+	}
+
+	result := make([]rune, 0, o.targetLength)
+	pos := 0
+	for _, c := range o.components {
+		switch {
+		case c.isRetain():
+			result = append(result, runes[pos:pos+c.retain]...)
+			pos += c.retain
+		case c.isInsert():
+			result = append(result, []rune(c.insert)...)
+		case c.isDelete():
+			pos += c.delete
+		}
+	}
+	return string(result), nil
+}
+
+// TransformTextOperations takes two operations a and b that apply to
+// the same document and produces a' and b' so that applying a and then
+// b' gives the same result as applying b and then a'. When both insert
+// at the same position, the text from a goes first.
+func TransformTextOperations(a, b *TextOperation) (*TextOperation, *TextOperation, error) {
+	if a.baseLength != b.baseLength {
+		return nil, nil, ErrTextOperationsIncompatible
+	}
+
+	aPrime := NewTextOperation()
+	bPrime := NewTextOperation()
+
+	ia, ib := 0, 0
+	var ca, cb *textOpComponent
+	next := func(ops []textOpComponent, i *int) *textOpComponent {
+		if *i >= len(ops) {
+			return nil
+		}
+		c := ops[*i]
+		*i++
+		return &c
+	}
+	ca = next(a.components, &ia)
+	cb = next(b.components, &ib)
+
+	for ca != nil || cb != nil {
+		if ca != nil && ca.isInsert() {
+			aPrime.Insert(ca.insert)
+			bPrime.Retain(utf8.RuneCountInString(ca.insert))
+			ca = next(a.components, &ia)
+			continue
+		}
+		if cb != nil && cb.isInsert() {
+			aPrime.Retain(utf8.RuneCountInString(cb.insert))
+			bPrime.Insert(cb.insert)
+			cb = next(b.components, &ib)
+			continue
+		}
+		if ca == nil || cb == nil {
+			return nil, nil, ErrTextOperationsIncompatible
+		}
+
+		var n int
+		switch {
+		case ca.isRetain() && cb.isRetain():
+			n = min(ca.retain, cb.retain)
+			aPrime.Retain(n)
+			bPrime.Retain(n)
+			ca.retain -= n
+			cb.retain -= n
+		case ca.isDelete() && cb.isDelete():
+			n = min(ca.delete, cb.delete)
+			ca.delete -= n
+			cb.delete -= n
+		case ca.isDelete() && cb.isRetain():
+			n = min(ca.delete, cb.retain)
+			aPrime.Delete(n)
+			ca.delete -= n
+			cb.retain -= n
+		case ca.isRetain() && cb.isDelete():
+			n = min(ca.retain, cb.delete)
+			bPrime.Delete(n)
+			ca.retain -= n
+			cb.delete -= n
+		}
+
+		if ca.retain == 0 && ca.delete == 0 {
+			ca = next(a.components, &ia)
+		}
+		if cb.retain == 0 && cb.delete == 0 {
+			cb = next(b.components, &ib)
+		}
+	}
+
+	return aPrime, bPrime, nil
+}
+
+func (o *TextOperation) MarshalJSON() ([]byte, error) {
+	components := make([]interface{}, 0, len(o.components))
+	for _, c := range o.components {
+		switch {
+		case c.isRetain():
+			components = append(components, c.retain)
+		case c.isInsert():
+			components = append(components, c.insert)
+		case c.isDelete():
+			components = append(components, -c.delete)
+		}
+	}
+	return json.Marshal(components)
+}
+
+func (o *TextOperation) UnmarshalJSON(data []byte) error {
+	var components []interface{}
+	if err := json.Unmarshal(data, &components); err != nil {
+		return err
+	}
+
+	op := NewTextOperation()
+	for _, component := range components {
+		switch v := component.(type) {
+		case string:
+			if v == "" {
+				return fmt.Errorf("empty insert: %w", ErrTextOperationInvalid)
+			}
+			op.Insert(v)
+		case float64:
+			if v == 0 || v != math.Trunc(v) {
+				return fmt.Errorf("%v: %w", v, ErrTextOperationInvalid)
+			}
+			if v > 0 {
+				op.Retain(int(v))
+			} else {
+				op.Delete(int(-v))
+			}
+		default:
+			return fmt.Errorf("%v: %w", v, ErrTextOperationInvalid)
+		}
+	}
+
+	*o = *op
+	return nil
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextOperationApply(t *testing.T) {
+	t.Run("Should apply retains, inserts and deletes", func(t *testing.T) {
+		op := NewTextOperation().Retain(6).Delete(5).Insert("there")
+
+		result, err := op.Apply("hello world")
+		require.NoError(t, err)
+		require.Equal(t, "hello there", result)
+		require.Equal(t, 11, op.BaseLength())
+		require.Equal(t, 11, op.TargetLength())
+	})
+
+	t.Run("Should count lengths in code points", func(t *testing.T) {
+		op := NewTextOperation().Retain(2).Insert("ñ").Retain(1)
+
+		result, err := op.Apply("añb")
+		require.NoError(t, err)
+		require.Equal(t, "aññb", result)
+	})
+
+	t.Run("Should fail if the document length doesn't match", func(t *testing.T) {
+		op := NewTextOperation().Retain(3).Insert("x")
+
+		_, err := op.Apply("too long")
+		require.ErrorIs(t, err, ErrTextOperationLengthMismatch)
+	})
+
+	t.Run("Should merge consecutive components and put inserts before deletes", func(t *testing.T) {
+		op := NewTextOperation().Retain(1).Retain(2).Delete(1).Insert("a").Insert("b")
+
+		data, err := json.Marshal(op)
+		require.NoError(t, err)
+		require.JSONEq(t, `[3, "ab", -1]`, string(data))
+	})
+}
+
+func TestTransformTextOperations(t *testing.T) {
+	doc := "hello world"
+
+	testCases := []struct {
+		name     string
+		a        *TextOperation
+		b        *TextOperation
+		expected string
+	}{
+		{
+			name:     "inserts at different positions",
+			a:        NewTextOperation().Insert(">").Retain(11),
+			b:        NewTextOperation().Retain(11).Insert("!"),
+			expected: ">hello world!",
+		},
+		{
+			name:     "inserts at the same position",
+			a:        NewTextOperation().Retain(5).Insert("A").Retain(6),
+			b:        NewTextOperation().Retain(5).Insert("B").Retain(6),
+			expected: "helloAB world",
+		},
+		{
+			name:     "overlapping deletes",
+			a:        NewTextOperation().Retain(2).Delete(5).Retain(4),
+			b:        NewTextOperation().Retain(4).Delete(5).Retain(2),
+			expected: "held",
+		},
+		{
+			name:     "insert inside a deleted range",
+			a:        NewTextOperation().Retain(3).Insert("X").Retain(8),
+			b:        NewTextOperation().Delete(6).Retain(5),
+			expected: "Xworld",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aPrime, bPrime, err := TransformTextOperations(tc.a, tc.b)
+			require.NoError(t, err)
+
+			afterA, err := tc.a.Apply(doc)
+			require.NoError(t, err)
+			resultAB, err := bPrime.Apply(afterA)
+			require.NoError(t, err)
+
+			afterB, err := tc.b.Apply(doc)
+			require.NoError(t, err)
+			resultBA, err := aPrime.Apply(afterB)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, resultAB)
+			require.Equal(t, tc.expected, resultBA)
+		})
+	}
+
+	t.Run("Should fail with operations on different documents", func(t *testing.T) {
+		a := NewTextOperation().Retain(3)
+		b := NewTextOperation().Retain(4)
+
+		_, _, err := TransformTextOperations(a, b)
+		require.ErrorIs(t, err, ErrTextOperationsIncompatible)
+	})
+}
+
+func TestTextOperationJSON(t *testing.T) {
+	t.Run("Should round trip through JSON", func(t *testing.T) {
+		var op TextOperation
+		require.NoError(t, json.Unmarshal([]byte(`[2, "new", -3, 1]`), &op))
+		require.Equal(t, 6, op.BaseLength())
+		require.Equal(t, 6, op.TargetLength())
+
+		data, err := json.Marshal(&op)
+		require.NoError(t, err)
+		require.JSONEq(t, `[2, "new", -3, 1]`, string(data))
+	})
+
+	t.Run("Should reject invalid components", func(t *testing.T) {
+		for _, data := range []string{`[0]`, `[1.5]`, `[""]`, `[true]`, `[{}]`} {
+			var op TextOperation
+			require.ErrorIs(t, json.Unmarshal([]byte(data), &op), ErrTextOperationInvalid, data)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package model
+
+// QueryTrashOptions are query options that can be passed to GetDeletedBoards,
+// GetDeletedBlocks and PurgeTrash.
+type QueryTrashOptions struct {
+	TeamID        string    // if not empty then filter for boards belonging to specified team
+	BoardID       string    // if not empty then filter for blocks belonging to specified board
+	BlockType     BlockType // if not empty then filter for blocks of specified type
+	DeletedBefore int64     // if non-zero then filter for records with delete_at less than DeletedBefore
+}
+
+// TrashRestoreRequest contains the ids of the deleted boards or blocks to restore
+// swagger:model
+type TrashRestoreRequest struct {
+	// The ids of the boards or blocks to restore
+	// required: true
+	IDs []string `json:"ids"`
+}
+
+// IsValid checks that the restore request contains at least one id.
+func (r *TrashRestoreRequest) IsValid() error {
+	if len(r.IDs) == 0 {
+		return NewErrBadRequest("ids are required")
+	}
+	for _, id := range r.IDs {
+		if id == "" {
+			return NewErrBadRequest("ids can't be empty")
+		}
+	}
+	return nil
+}
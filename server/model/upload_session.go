@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UploadSession is a resumable upload of a file, sent in chunks.
+// swagger:model
+type UploadSession struct {
+	// The ID of the upload session
+	// required: true
+	ID string `json:"id"`
+
+	// The team of the board the file is uploaded to
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The board the file is uploaded to
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the user uploading the file
+	// required: true
+	UserID string `json:"userId"`
+
+	// The name of the uploaded file
+	// required: true
+	Filename string `json:"filename"`
+
+	// The path of the received part of the file in the files storage
+	Path string `json:"-"`
+
+	// The size of the whole file in bytes
+	// required: true
+	FileSize int64 `json:"fileSize"`
+
+	// The number of bytes received so far, where the next chunk starts
+	// required: true
+	FileOffset int64 `json:"fileOffset"`
+
+	// The hex encoded SHA-256 checksum of the whole file, verified once
+	// the upload is complete
+	// required: false
+	Checksum string `json:"checksum,omitempty"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The minimum size in bytes of the chunks that don't complete the
+	// file, 0 if chunks of any size are accepted
+	// required: false
+	MinChunkSize int64 `json:"minChunkSize"`
+}
+
+// IsValid checks the fields of an upload session sent by a client.
+func (s *UploadSession) IsValid() error {
+	if strings.TrimSpace(s.Filename) == "" {
+		return NewErrBadRequest("upload session filename is required")
+	}
+	if s.FileSize <= 0 {
+		return NewErrBadRequest("upload session file size must be positive")
+	}
+	if s.Checksum != "" {
+		if sum, err := hex.DecodeString(s.Checksum); err != nil || len(sum) != 32 {
+			return NewErrBadRequest(fmt.Sprintf("invalid upload session checksum %q, a hex encoded SHA-256 is expected", s.Checksum))
+		}
+	}
+	return nil
+}
+
+// IsComplete returns true if the whole file has been received.
+func (s *UploadSession) IsComplete() bool {
+	return s.FileOffset == s.FileSize
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"io"
+	"strings"
 )
 
 const (
@@ -12,6 +13,13 @@ const (
 	PreferencesCategoryFocalboard = "focalboard"
 )
 
+const (
+	// SystemAdminRoleID is the role of the users administering the server.
+	SystemAdminRoleID = "system_admin"
+	// SystemUserRoleID is the role of all the users.
+	SystemUserRoleID = "system_user"
+)
+
 // User is a user
 // swagger:model
 type User struct {
@@ -66,6 +74,10 @@ type User struct {
 	// required: true
 	IsGuest bool `json:"is_guest"`
 
+	// Expiration time of a guest account in miliseconds since the current
+	// epoch, 0 if it doesn't expire
+	GuestExpireAt int64 `json:"guest_expire_at,omitempty"`
+
 	// Special Permissions the user may have
 	Permissions []string `json:"permissions,omitempty"`
 
@@ -92,6 +104,11 @@ type Session struct {
 	Props       map[string]interface{} `json:"props"`
 	CreateAt    int64                  `json:"create_at,omitempty"`
 	UpdateAt    int64                  `json:"update_at,omitempty"`
+
+	// IsGuest and GuestExpireAt are read from the user of the session, to
+	// reject the sessions of the expired guests.
+	IsGuest       bool  `json:"-"`
+	GuestExpireAt int64 `json:"-"`
 }
 
 func UserFromJSON(data io.Reader) (*User, error) {
@@ -114,3 +131,40 @@ func (u *User) Sanitize(options map[string]bool) {
 		u.LastName = ""
 	}
 }
+
+// IsSystemAdmin returns true if the user administers the server.
+func (u *User) IsSystemAdmin() bool {
+	for _, role := range strings.Fields(u.Roles) {
+		if role == SystemAdminRoleID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpiredGuest returns true if the user is a guest whose account has
+// expired.
+func (u *User) IsExpiredGuest() bool {
+	return u.IsGuest && u.GuestExpireAt != 0 && u.GuestExpireAt <= GetMillis()
+}
+
+// IsExpiredGuest returns true if the session is of a guest whose account
+// has expired.
+func (s *Session) IsExpiredGuest() bool {
+	return s.IsGuest && s.GuestExpireAt != 0 && s.GuestExpireAt <= GetMillis()
+}
+
+// IsValidGuestExpireAt returns true if a guest account expiration time is
+// either unset or in the future.
+func IsValidGuestExpireAt(expireAt int64) bool {
+	return expireAt == 0 || expireAt > GetMillis()
+}
+
+// SystemRoles returns the roles of a user, with or without the system
+// admin role.
+func SystemRoles(isAdmin bool) string {
+	if isAdmin {
+		return SystemAdminRoleID + " " + SystemUserRoleID
+	}
+	return SystemUserRoleID
+}
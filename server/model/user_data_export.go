@@ -0,0 +1,164 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// UserDataExportVersion is the version of the user data exports.
+	UserDataExportVersion = 1
+
+	// UserDataExportFilesDir is the directory of the user data export
+	// archives that holds the files uploaded by the user.
+	UserDataExportFilesDir = "files"
+)
+
+// UserDataExport is the personal data of a user, exported to answer the
+// data-subject access requests.
+// swagger:model
+type UserDataExport struct {
+	// The version of the export
+	// required: true
+	Version int `json:"version"`
+
+	// The export time in milliseconds since the current epoch
+	// required: true
+	Date int64 `json:"date"`
+
+	// The profile of the user
+	// required: true
+	Profile *UserDataProfile `json:"profile"`
+
+	// The preferences of the user
+	// required: true
+	Preferences mmModel.Preferences `json:"preferences"`
+
+	// The board memberships of the user
+	// required: true
+	BoardMemberships []*BoardMember `json:"boardMemberships"`
+
+	// The history of the board memberships of the user, as recorded when
+	// it was added to and removed from the boards
+	// required: true
+	BoardMembershipHistory []*BoardMemberHistoryEntry `json:"boardMembershipHistory"`
+
+	// The cards created by the user
+	// required: true
+	CreatedCards []*Block `json:"createdCards"`
+
+	// The cards the user is assigned to, ie. that it's set in a person
+	// property of
+	// required: true
+	AssignedCards []*Block `json:"assignedCards"`
+
+	// The comments written by the user
+	// required: true
+	Comments []*Block `json:"comments"`
+
+	// The files uploaded by the user
+	// required: true
+	Files []*UserDataFile `json:"files"`
+
+	// The block subscriptions of the user
+	// required: true
+	Subscriptions []*Subscription `json:"subscriptions"`
+
+	// The sessions of the user, without their tokens
+	// required: true
+	Sessions []*UserDataSession `json:"sessions"`
+
+	// The audit records of the user's actions, as written to the JSON
+	// audit log files of the server
+	// required: true
+	AuditEntries []map[string]interface{} `json:"auditEntries"`
+}
+
+// UserDataProfile is the profile of a user, including its email.
+// swagger:model
+type UserDataProfile struct {
+	*User
+
+	// The user's email
+	// required: true
+	Email string `json:"email"`
+}
+
+// UserDataFile is a file uploaded by a user.
+// swagger:model
+type UserDataFile struct {
+	// The ID of the block that holds the file
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The ID of the board of the file
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the file, as stored in the block
+	// required: true
+	FileID string `json:"fileId"`
+
+	// The original name of the file
+	// required: true
+	Name string `json:"name"`
+
+	// The size of the file in bytes
+	// required: false
+	Size int64 `json:"size,omitempty"`
+
+	// The mime type of the file
+	// required: false
+	MimeType string `json:"mimeType,omitempty"`
+
+	// The upload time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The path of the file in the export archive, empty if the file is
+	// missing from the file store
+	// required: false
+	Path string `json:"path,omitempty"`
+}
+
+// UserDataSession is a session of a user, without its token.
+// swagger:model
+type UserDataSession struct {
+	// The ID of the session
+	// required: true
+	ID string `json:"id"`
+
+	// The authentication service of the session
+	// required: false
+	AuthService string `json:"authService,omitempty"`
+
+	// The properties of the session
+	// required: false
+	Props map[string]interface{} `json:"props,omitempty"`
+
+	// The login time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last activity time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+func NewUserDataSession(session *Session) *UserDataSession {
+	return &UserDataSession{
+		ID:          session.ID,
+		AuthService: session.AuthService,
+		Props:       session.Props,
+		CreateAt:    session.CreateAt,
+		UpdateAt:    session.UpdateAt,
+	}
+}
+
+func UserDataExportFromJSON(data io.Reader) *UserDataExport {
+	var export *UserDataExport
+	_ = json.NewDecoder(data).Decode(&export)
+	return export
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DeletedUsernamePrefix is the prefix of the usernames of the anonymous
+// users that replace the deleted users.
+const DeletedUsernamePrefix = "deleted-"
+
+// UserDeletionReport is the record of the deletion of a user. The user
+// is replaced by an anonymous deactivated user, that the content the
+// user authored is attributed to.
+// swagger:model
+type UserDeletionReport struct {
+	// The ID of the deleted user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The ID of the anonymous user that replaces the deleted user
+	// required: true
+	AnonymousID string `json:"anonymousId"`
+
+	// The username of the anonymous user
+	// required: true
+	AnonymousUsername string `json:"anonymousUsername"`
+
+	// The ID of the user that deleted the user
+	// required: true
+	DeletedBy string `json:"deletedBy"`
+
+	// The deletion time in milliseconds since the current epoch
+	// required: true
+	DeleteAt int64 `json:"deleteAt"`
+
+	// The number of rows attributed to the anonymous user, by table and
+	// column
+	// required: true
+	Anonymized map[string]int64 `json:"anonymized"`
+
+	// The number of rows removed, by table
+	// required: true
+	Removed map[string]int64 `json:"removed"`
+}
+
+func NewUserDeletionReport(userID string, anonymousUser *User) *UserDeletionReport {
+	return &UserDeletionReport{
+		UserID:            userID,
+		AnonymousID:       anonymousUser.ID,
+		AnonymousUsername: anonymousUser.Username,
+		DeleteAt:          anonymousUser.DeleteAt,
+		Anonymized:        map[string]int64{},
+		Removed:           map[string]int64{},
+	}
+}
+
+func UserDeletionReportFromJSON(data io.Reader) *UserDeletionReport {
+	var report *UserDeletionReport
+	_ = json.NewDecoder(data).Decode(&report)
+	return report
+}
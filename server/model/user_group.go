@@ -0,0 +1,157 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+// UserGroup is a named set of users of a team. The groups can be added
+// to boards, making all their members board members, and mentioned in
+// cards with @name.
+// swagger:model
+type UserGroup struct {
+	// The ID of the group
+	// required: true
+	ID string `json:"id"`
+
+	// The team the group is defined in
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The name of the group, used to mention it
+	// required: true
+	Name string `json:"name"`
+
+	// The description of the group
+	// required: false
+	Description string `json:"description"`
+
+	// The ID of the user that created the group
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last update time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// UserGroupPatch is a patch to update a user group.
+// swagger:model
+type UserGroupPatch struct {
+	// The name of the group
+	// required: false
+	Name *string `json:"name"`
+
+	// The description of the group
+	// required: false
+	Description *string `json:"description"`
+}
+
+// BoardGroup links a user group to a board. The members of the group
+// are members of the board with the roles of the link.
+// swagger:model
+type BoardGroup struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the user group
+	// required: true
+	GroupID string `json:"groupId"`
+
+	// Marks the members of the group as admins of the board
+	// required: true
+	SchemeAdmin bool `json:"schemeAdmin"`
+
+	// Marks the members of the group as editors of the board
+	// required: true
+	SchemeEditor bool `json:"schemeEditor"`
+
+	// Marks the members of the group as commenters of the board
+	// required: true
+	SchemeCommenter bool `json:"schemeCommenter"`
+
+	// Marks the members of the group as viewers of the board
+	// required: true
+	SchemeViewer bool `json:"schemeViewer"`
+
+	// The ID of the custom board role of the members of the group
+	// required: false
+	CustomRoleID string `json:"customRoleId"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// IsValid checks the name of a user group. As the groups are mentioned
+// like the users, their names follow the rules of the usernames.
+func (g *UserGroup) IsValid() error {
+	if g.TeamID == "" {
+		return NewErrBadRequest("user group team ID is required")
+	}
+	if g.Name == "" {
+		return NewErrBadRequest("user group name is required")
+	}
+	if !mmModel.IsValidUsername(g.Name) {
+		return NewErrBadRequest("invalid user group name, it can only contain lowercase letters, numbers and the symbols '.', '-' and '_'")
+	}
+	return nil
+}
+
+// Patch applies the changes of a patch to a user group.
+func (p *UserGroupPatch) Patch(group *UserGroup) *UserGroup {
+	if p.Name != nil {
+		group.Name = strings.ToLower(strings.TrimSpace(*p.Name))
+	}
+	if p.Description != nil {
+		group.Description = *p.Description
+	}
+	return group
+}
+
+// MemberFor returns the board membership that the link gives to a
+// member of the group.
+func (bg *BoardGroup) MemberFor(userID string) *BoardMember {
+	return &BoardMember{
+		BoardID:         bg.BoardID,
+		UserID:          userID,
+		SchemeAdmin:     bg.SchemeAdmin,
+		SchemeEditor:    bg.SchemeEditor,
+		SchemeCommenter: bg.SchemeCommenter,
+		SchemeViewer:    bg.SchemeViewer,
+		CustomRoleID:    bg.CustomRoleID,
+		GroupID:         bg.GroupID,
+	}
+}
+
+func UserGroupFromJSON(data io.Reader) *UserGroup {
+	var group *UserGroup
+	_ = json.NewDecoder(data).Decode(&group)
+	return group
+}
+
+func UserGroupsFromJSON(data io.Reader) []*UserGroup {
+	var groups []*UserGroup
+	_ = json.NewDecoder(data).Decode(&groups)
+	return groups
+}
+
+func BoardGroupFromJSON(data io.Reader) *BoardGroup {
+	var boardGroup *BoardGroup
+	_ = json.NewDecoder(data).Decode(&boardGroup)
+	return boardGroup
+}
+
+func BoardGroupsFromJSON(data io.Reader) []*BoardGroup {
+	var boardGroups []*BoardGroup
+	_ = json.NewDecoder(data).Decode(&boardGroups)
+	return boardGroups
+}
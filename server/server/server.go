@@ -21,6 +21,7 @@ import (
 	"github.com/mattermost/focalboard/server/services/audit"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/email"
+	"github.com/mattermost/focalboard/server/services/filescan"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
 	"github.com/mattermost/focalboard/server/services/notify/notifylogger"
@@ -41,6 +42,12 @@ import (
 const (
 	cleanupSessionTaskFrequency = 10 * time.Minute
 	updateMetricsTaskFrequency  = 15 * time.Minute
+	purgeTrashTaskFrequency     = 24 * time.Hour
+	archiveJobsTaskFrequency    = 1 * time.Hour
+	filePreviewsBackfillDelay   = 1 * time.Minute
+	fileUsageBackfillDelay      = 1 * time.Minute
+	uploadSessionsTaskFrequency = 1 * time.Hour
+	loginAttemptsTaskFrequency  = 1 * time.Hour
 
 	minSessionExpiryTime = int64(60 * 60 * 24 * 31) // 31 days
 
@@ -59,6 +66,14 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	purgeTrashTask         *scheduler.ScheduledTask
+	archiveJobsTask        *scheduler.ScheduledTask
+	backupTask             *scheduler.ScheduledTask
+	filePreviewsTask       *scheduler.ScheduledTask
+	fileUsageTask          *scheduler.ScheduledTask
+	orphanedFilesTask      *scheduler.ScheduledTask
+	uploadSessionsTask     *scheduler.ScheduledTask
+	loginAttemptsTask      *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -96,6 +111,7 @@ func New(params Params) (*Server, error) {
 	filesBackendSettings.AmazonS3SSE = params.Cfg.FilesS3Config.SSE
 	filesBackendSettings.AmazonS3Trace = params.Cfg.FilesS3Config.Trace
 	filesBackendSettings.AmazonS3RequestTimeoutMilliseconds = params.Cfg.FilesS3Config.Timeout
+	filesBackendSettings.AmazonS3PresignExpiresSeconds = int64(params.Cfg.SignedURLExpirySeconds)
 
 	filesBackend, appErr := filestore.NewFileBackend(filesBackendSettings)
 	if appErr != nil {
@@ -104,6 +120,20 @@ func New(params Params) (*Server, error) {
 		return nil, errors.New("unable to initialize the files storage")
 	}
 
+	backupBackend, errBackup := newBackupBackend(params.Cfg)
+	if errBackup != nil {
+		params.Logger.Error("Unable to initialize the backup storage", mlog.Err(errBackup))
+
+		return nil, errors.New("unable to initialize the backup storage")
+	}
+
+	fileScanner, errScan := newFileScanner(params.Cfg, params.Logger)
+	if errScan != nil {
+		params.Logger.Error("Unable to initialize the file scanner", mlog.Err(errScan))
+
+		return nil, errors.New("unable to initialize the file scanner")
+	}
+
 	webhookClient := webhook.NewClient(params.Cfg, params.Logger)
 
 	// Init metrics
@@ -141,6 +171,8 @@ func New(params Params) (*Server, error) {
 		Auth:             authenticator,
 		Store:            params.DBStore,
 		FilesBackend:     filesBackend,
+		BackupBackend:    backupBackend,
+		FileScanner:      fileScanner,
 		Webhook:          webhookClient,
 		Metrics:          metricsService,
 		Notifications:    notificationService,
@@ -152,6 +184,18 @@ func New(params Params) (*Server, error) {
 	}
 	app := app.New(params.Cfg, wsAdapter, appServices)
 
+	// the standalone websocket server relays the collaborative text
+	// edition operations to the app, and both adapters hide the private
+	// cards and the restricted properties from the users that can't
+	// view them
+	switch adapter := wsAdapter.(type) {
+	case *ws.Server:
+		adapter.SetTextEditor(app)
+		adapter.SetBlockFilter(app)
+	case *ws.PluginAdapter:
+		adapter.SetBlockFilter(app)
+	}
+
 	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService)
 
 	// Local router for admin APIs
@@ -217,6 +261,49 @@ func New(params Params) (*Server, error) {
 	return &server, nil
 }
 
+// newBackupBackend returns the storage of the backups, which shares the S3
+// settings of the files storage. It returns nil if backups are disabled.
+func newBackupBackend(cfg *config.Configuration) (filestore.FileBackend, error) {
+	if cfg.BackupDriver == "" {
+		return nil, nil
+	}
+
+	settings := filestore.FileBackendSettings{}
+	settings.DriverName = cfg.BackupDriver
+	settings.Directory = cfg.BackupPath
+	settings.AmazonS3AccessKeyId = cfg.FilesS3Config.AccessKeyID
+	settings.AmazonS3SecretAccessKey = cfg.FilesS3Config.SecretAccessKey
+	settings.AmazonS3Bucket = cfg.FilesS3Config.Bucket
+	settings.AmazonS3PathPrefix = cfg.BackupPath
+	settings.AmazonS3Region = cfg.FilesS3Config.Region
+	settings.AmazonS3Endpoint = cfg.FilesS3Config.Endpoint
+	settings.AmazonS3SSL = cfg.FilesS3Config.SSL
+	settings.AmazonS3SignV2 = cfg.FilesS3Config.SignV2
+	settings.AmazonS3SSE = cfg.FilesS3Config.SSE
+	settings.AmazonS3Trace = cfg.FilesS3Config.Trace
+	settings.AmazonS3RequestTimeoutMilliseconds = cfg.FilesS3Config.Timeout
+
+	return filestore.NewFileBackend(settings)
+}
+
+// newFileScanner returns the scanner of the uploaded files. It returns nil
+// if the scanning is disabled.
+func newFileScanner(cfg *config.Configuration, logger mlog.LoggerIFace) (filescan.Scanner, error) {
+	timeout := time.Duration(cfg.FileScanTimeoutSeconds) * time.Second
+	scanner, err := filescan.New(cfg.FileScanDriver, cfg.ClamdAddress, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// uploads are rejected until clamd is reachable
+	if clamd, ok := scanner.(*filescan.Clamd); ok {
+		if err := clamd.Ping(); err != nil {
+			logger.Warn("Unable to reach clamd, uploads will fail", mlog.String("address", cfg.ClamdAddress), mlog.Err(err))
+		}
+	}
+	return scanner, nil
+}
+
 func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (store.Store, error) {
 	sqlDB, err := sql.Open(config.DBType, config.DBConfigString)
 	if err != nil {
@@ -263,6 +350,10 @@ func (s *Server) Start() error {
 	}
 
 	if s.config.AuthMode != MattermostAuthMod {
+		if err := s.app.EnsureSystemAdmins(); err != nil {
+			s.logger.Error("Unable to ensure the server has a system admin", mlog.Err(err))
+		}
+
 		s.cleanUpSessionsTask = scheduler.CreateRecurringTask("cleanUpSessions", func() {
 			secondsAgo := minSessionExpiryTime
 			if secondsAgo < s.config.SessionExpireTime {
@@ -303,6 +394,77 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	if s.config.TrashRetentionDays > 0 {
+		s.purgeTrashTask = scheduler.CreateRecurringTask("purgeTrash", func() {
+			if _, err := s.app.PurgeExpiredTrash(); err != nil {
+				s.logger.Error("Unable to purge the trash", mlog.Err(err))
+			}
+		}, purgeTrashTaskFrequency)
+	}
+
+	if err := s.app.ResumeArchiveJobs(); err != nil {
+		s.logger.Error("Unable to resume the archive jobs", mlog.Err(err))
+	}
+	s.archiveJobsTask = scheduler.CreateRecurringTask("cleanupArchiveJobs", func() {
+		if _, err := s.app.CleanupExpiredArchiveJobs(); err != nil {
+			s.logger.Error("Unable to cleanup the expired archive jobs", mlog.Err(err))
+		}
+	}, archiveJobsTaskFrequency)
+
+	if s.config.BackupFrequencyHours > 0 && s.config.BackupDriver != "" {
+		s.backupTask = scheduler.CreateRecurringTask("backup", func() {
+			if _, err := s.app.CreateBackup(); err != nil {
+				s.logger.Error("Unable to create the scheduled backup", mlog.Err(err))
+			}
+		}, time.Duration(s.config.BackupFrequencyHours)*time.Hour)
+	}
+
+	// generate the previews of the images uploaded before they existed
+	s.filePreviewsTask = scheduler.CreateTask("backfillFilePreviews", func() {
+		count, err := s.app.BackfillFilePreviews()
+		if err != nil {
+			s.logger.Error("Unable to generate the missing file previews", mlog.Err(err))
+		}
+		if count > 0 {
+			s.logger.Info("Generated the missing file previews", mlog.Int("count", count))
+		}
+	}, filePreviewsBackfillDelay)
+
+	// track the usage of the files stored before it was tracked
+	s.fileUsageTask = scheduler.CreateTask("backfillFileUsage", func() {
+		count, err := s.app.BackfillFileUsage()
+		if err != nil {
+			s.logger.Error("Unable to save the usage of the existing files", mlog.Err(err))
+		}
+		if count > 0 {
+			s.logger.Info("Saved the usage of the existing files", mlog.Int("count", count))
+		}
+	}, fileUsageBackfillDelay)
+
+	if s.config.OrphanFileCleanupHours > 0 {
+		s.orphanedFilesTask = scheduler.CreateRecurringTask("cleanupOrphanedFiles", func() {
+			result, err := s.app.CleanupOrphanedFiles()
+			if err != nil {
+				s.logger.Error("Unable to clean up the orphaned files", mlog.Err(err))
+			}
+			if result != nil && result.Files > 0 {
+				s.logger.Info("Deleted the orphaned files", mlog.Int("count", result.Files), mlog.Int("size", result.Size))
+			}
+		}, time.Duration(s.config.OrphanFileCleanupHours)*time.Hour)
+	}
+
+	s.uploadSessionsTask = scheduler.CreateRecurringTask("cleanupUploadSessions", func() {
+		if _, err := s.app.CleanupExpiredUploadSessions(); err != nil {
+			s.logger.Error("Unable to cleanup the expired upload sessions", mlog.Err(err))
+		}
+	}, uploadSessionsTaskFrequency)
+
+	s.loginAttemptsTask = scheduler.CreateRecurringTask("cleanupLoginAttempts", func() {
+		if _, err := s.app.CleanupExpiredLoginAttempts(); err != nil {
+			s.logger.Error("Unable to cleanup the expired login attempts", mlog.Err(err))
+		}
+	}, loginAttemptsTaskFrequency)
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -344,6 +506,38 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.purgeTrashTask != nil {
+		s.purgeTrashTask.Cancel()
+	}
+
+	if s.archiveJobsTask != nil {
+		s.archiveJobsTask.Cancel()
+	}
+
+	if s.backupTask != nil {
+		s.backupTask.Cancel()
+	}
+
+	if s.filePreviewsTask != nil {
+		s.filePreviewsTask.Cancel()
+	}
+
+	if s.fileUsageTask != nil {
+		s.fileUsageTask.Cancel()
+	}
+
+	if s.orphanedFilesTask != nil {
+		s.orphanedFilesTask.Cancel()
+	}
+
+	if s.uploadSessionsTask != nil {
+		s.uploadSessionsTask.Cancel()
+	}
+
+	if s.loginAttemptsTask != nil {
+		s.loginAttemptsTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
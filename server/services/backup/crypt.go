@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package backup encrypts and decrypts backups with a passphrase.
+//
+// Encrypted backups start with a header made of a magic string and the
+// salt used to derive the key from the passphrase. The content follows as
+// a sequence of chunks sealed with AES-GCM, each prefixed by its length.
+// The nonce of a chunk is its index, with a flag marking the last chunk so
+// truncated backups are detected.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	magic     = "FBBKENC1"
+	saltSize  = 16
+	keySize   = 32
+	chunkSize = 64 * 1024
+
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrMissingPassphrase = errors.New("a passphrase is required")
+	ErrDecrypt           = errors.New("cannot decrypt the backup, wrong passphrase or corrupted data")
+	ErrTruncated         = errors.New("the backup is truncated")
+)
+
+// IsEncrypted returns true if the content read by r is encrypted. It
+// doesn't consume any data.
+func IsEncrypted(r *bufio.Reader) bool {
+	header, err := r.Peek(len(magic))
+	return err == nil && string(header) == magic
+}
+
+// NewEncryptWriter returns a writer encrypting the data written to w. It
+// must be closed to write the last chunk.
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	if passphrase == "" {
+		return nil, ErrMissingPassphrase
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(salt); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{w: w, aead: aead, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+// NewDecryptReader returns a reader decrypting the data read from r.
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	if passphrase == "" {
+		return nil, ErrMissingPassphrase
+	}
+
+	header := make([]byte, len(magic)+saltSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("cannot read the backup header: %w", err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrDecrypt
+	}
+
+	aead, err := newAEAD(passphrase, header[len(magic):])
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: r, aead: aead}, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk with the given index.
+func chunkNonce(size int, index uint64, last bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:size-1], index)
+	if last {
+		nonce[size-1] = 1
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	buf    []byte
+	index  uint64
+	closed bool
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to a closed encrypt writer")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+
+		// a full chunk is only sealed once more data comes, as the last
+		// chunk is sealed differently
+		if len(e.buf) == cap(e.buf) && len(p) > 0 {
+			if err := e.writeChunk(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.writeChunk(true)
+}
+
+func (e *encryptWriter) writeChunk(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.aead.NonceSize(), e.index, last), e.buf, nil)
+	e.index++
+	e.buf = e.buf[:0]
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(sealed)))
+	if _, err := e.w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+type decryptReader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	buf   bytes.Reader
+	index uint64
+	done  bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for d.buf.Len() == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	return d.buf.Read(p)
+}
+
+func (d *decryptReader) readChunk() error {
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+
+	length := binary.BigEndian.Uint32(size[:])
+	if length > uint32(chunkSize+d.aead.Overhead()) {
+		return ErrDecrypt
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+
+	// the chunk is either a middle chunk or the last one
+	nonceSize := d.aead.NonceSize()
+	plain, err := d.aead.Open(nil, chunkNonce(nonceSize, d.index, false), sealed, nil)
+	if err != nil {
+		plain, err = d.aead.Open(nil, chunkNonce(nonceSize, d.index, true), sealed, nil)
+		if err != nil {
+			return ErrDecrypt
+		}
+		d.done = true
+	}
+	d.index++
+	d.buf.Reset(plain)
+	return nil
+}
@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(t *testing.T, data []byte, passphrase string) []byte {
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, passphrase)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	sizes := []int{0, 10, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 42}
+
+	for _, size := range sizes {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encrypt(t, data, "secret")
+		require.True(t, IsEncrypted(bufio.NewReader(bytes.NewReader(encrypted))))
+		if size > 0 {
+			require.NotContains(t, string(encrypted), string(data))
+		}
+
+		r, err := NewDecryptReader(bytes.NewReader(encrypted), "secret")
+		require.NoError(t, err)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, data, decrypted, "size %d", size)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	data := bytes.Repeat([]byte("board"), chunkSize)
+	encrypted := encrypt(t, data, "secret")
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		r, err := NewDecryptReader(bytes.NewReader(encrypted), "wrong")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("truncated backup", func(t *testing.T) {
+		// cut right after the first chunk
+		firstChunk := len(magic) + saltSize + 4 + chunkSize + 16
+		r, err := NewDecryptReader(bytes.NewReader(encrypted[:firstChunk]), "secret")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrTruncated)
+	})
+
+	t.Run("corrupted backup", func(t *testing.T) {
+		corrupted := append([]byte{}, encrypted...)
+		corrupted[len(corrupted)-1] ^= 0xff
+		r, err := NewDecryptReader(bytes.NewReader(corrupted), "secret")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("missing passphrase", func(t *testing.T) {
+		_, err := NewDecryptReader(bytes.NewReader(encrypted), "")
+		require.ErrorIs(t, err, ErrMissingPassphrase)
+		_, err = NewEncryptWriter(io.Discard, "")
+		require.ErrorIs(t, err, ErrMissingPassphrase)
+	})
+
+	t.Run("plain data is not encrypted", func(t *testing.T) {
+		require.False(t, IsEncrypted(bufio.NewReader(bytes.NewReader(data))))
+	})
+}
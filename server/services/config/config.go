@@ -74,12 +74,38 @@ type Configuration struct {
 	FeatureFlags             map[string]string `json:"featureFlags" mapstructure:"featureFlags"`
 	EnableDataRetention      bool              `json:"enable_data_retention" mapstructure:"enable_data_retention"`
 	DataRetentionDays        int               `json:"data_retention_days" mapstructure:"data_retention_days"`
+	TrashRetentionDays       int               `json:"trash_retention_days" mapstructure:"trash_retention_days"`
+	ArchiveJobExpiryHours    int               `json:"archive_job_expiry_hours" mapstructure:"archive_job_expiry_hours"`
+	BackupFrequencyHours     int               `json:"backup_frequency_hours" mapstructure:"backup_frequency_hours"`
+	BackupRetentionCount     int               `json:"backup_retention_count" mapstructure:"backup_retention_count"`
+	BackupDriver             string            `json:"backup_driver" mapstructure:"backup_driver"`
+	BackupPath               string            `json:"backup_path" mapstructure:"backup_path"`
+	BackupPassphrase         string            `json:"backup_passphrase" mapstructure:"backup_passphrase"`
+	FileScanDriver           string            `json:"file_scan_driver" mapstructure:"file_scan_driver"`
+	ClamdAddress             string            `json:"clamd_address" mapstructure:"clamd_address"`
+	FileScanTimeoutSeconds   int               `json:"file_scan_timeout_seconds" mapstructure:"file_scan_timeout_seconds"`
+	AllowedFileTypes         []string          `json:"allowed_file_types" mapstructure:"allowed_file_types"`
+	DeniedFileTypes          []string          `json:"denied_file_types" mapstructure:"denied_file_types"`
+	TeamStorageQuota         int64             `json:"team_storage_quota" mapstructure:"team_storage_quota"`
+	TeamStorageQuotas        map[string]int64  `json:"team_storage_quotas" mapstructure:"team_storage_quotas"`
+	OrphanFileCleanupHours   int               `json:"orphan_file_cleanup_hours" mapstructure:"orphan_file_cleanup_hours"`
+	MaxUploadSessionFileSize int64             `json:"max_upload_session_file_size" mapstructure:"max_upload_session_file_size"`
+	SignedURLExpirySeconds   int               `json:"signed_url_expiry_seconds" mapstructure:"signed_url_expiry_seconds"`
+	SignedURLRedirectS3      bool              `json:"signed_url_redirect_s3" mapstructure:"signed_url_redirect_s3"`
+	SystemAdminUsernames     []string          `json:"system_admin_usernames" mapstructure:"system_admin_usernames"`
 	TeammateNameDisplay      string            `json:"teammate_name_display" mapstructure:"teammateNameDisplay"`
 	ShowEmailAddress         bool              `json:"show_email_address" mapstructure:"showEmailAddress"`
 	ShowFullName             bool              `json:"show_full_name" mapstructure:"showFullName"`
 
 	AuthMode string `json:"authMode" mapstructure:"authMode"`
 
+	LoginMaxFailedAttempts      int `json:"login_max_failed_attempts" mapstructure:"login_max_failed_attempts"`
+	LoginMaxFailedAttemptsPerIP int `json:"login_max_failed_attempts_per_ip" mapstructure:"login_max_failed_attempts_per_ip"`
+	LoginLockoutSeconds         int `json:"login_lockout_seconds" mapstructure:"login_lockout_seconds"`
+	LoginFailureDelaySeconds    int `json:"login_failure_delay_seconds" mapstructure:"login_failure_delay_seconds"`
+
+	TrustedProxies []string `json:"trusted_proxies" mapstructure:"trusted_proxies"`
+
 	LoggingCfgFile string `json:"logging_cfg_file" mapstructure:"logging_cfg_file"`
 	LoggingCfgJSON string `json:"logging_cfg_json" mapstructure:"logging_cfg_json"`
 
@@ -167,10 +193,34 @@ func setDefaults() {
 	viper.SetDefault("featureFlags", map[string]string{})
 	viper.SetDefault("enable_data_retention", false)
 	viper.SetDefault("data_retention_days", 365) // 1 year is default
+	viper.SetDefault("trash_retention_days", 0)  // 0 keeps deleted items forever
+	viper.SetDefault("archive_job_expiry_hours", 24)
+	viper.SetDefault("backup_frequency_hours", 0) // 0 disables the scheduled backups
+	viper.SetDefault("backup_retention_count", 7)
+	viper.SetDefault("backup_driver", "local")
+	viper.SetDefault("backup_path", "./backups")
+	viper.SetDefault("backup_passphrase", "")
+	viper.SetDefault("file_scan_driver", "") // empty disables the scanning of uploads
+	viper.SetDefault("clamd_address", "tcp://localhost:3310")
+	viper.SetDefault("file_scan_timeout_seconds", 60)
+	viper.SetDefault("allowed_file_types", []string{}) // empty allows all the types
+	viper.SetDefault("denied_file_types", []string{})
+	viper.SetDefault("team_storage_quota", 0)                   // bytes per team, 0 for no quota
+	viper.SetDefault("team_storage_quotas", map[string]int64{}) // overrides the quota of specific teams
+	viper.SetDefault("orphan_file_cleanup_hours", 24)           // 0 disables the cleanup of orphaned files
+	viper.SetDefault("max_upload_session_file_size", int64(0))  // bytes of a resumable upload, 0 for the max file size
+	viper.SetDefault("signed_url_expiry_seconds", 300)          // 5 minutes
+	viper.SetDefault("signed_url_redirect_s3", false)           // serve signed URLs from S3 pre-signed URLs
+	viper.SetDefault("system_admin_usernames", []string{})      // users promoted to system admin
 	viper.SetDefault("teammateNameDisplay", "username")
 	viper.SetDefault("showEmailAddress", false)
 	viper.SetDefault("showFullName", false)
 	viper.SetDefault("authMode", "native")
+	viper.SetDefault("login_max_failed_attempts", 0)        // per account, 0 disables the lockout
+	viper.SetDefault("login_max_failed_attempts_per_ip", 0) // per client IP, 0 disables the lockout
+	viper.SetDefault("login_lockout_seconds", 900)          // 15 minutes
+	viper.SetDefault("login_failure_delay_seconds", 1)      // doubled after each failure, 0 disables the delays
+	viper.SetDefault("trusted_proxies", []string{})         // IPs or CIDRs of the proxies forwarding the client IP
 	viper.SetDefault("logging_cfg_file", "")
 	viper.SetDefault("logging_cfg_json", "")
 	viper.SetDefault("audit_cfg_file", "")
@@ -220,10 +270,29 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("enablePublicSharedBoards", "FOCALBOARD_ENABLEPUBLICSHAREDBOARDS")
 	viper.BindEnv("enable_data_retention", "FOCALBOARD_ENABLEDATARETENTION")
 	viper.BindEnv("data_retention_days", "FOCALBOARD_DATARETENTIONDAYS")
+	viper.BindEnv("trash_retention_days", "FOCALBOARD_TRASHRETENTIONDAYS")
+	viper.BindEnv("archive_job_expiry_hours", "FOCALBOARD_ARCHIVEJOBEXPIRYHOURS")
+	viper.BindEnv("backup_frequency_hours", "FOCALBOARD_BACKUPFREQUENCYHOURS")
+	viper.BindEnv("backup_retention_count", "FOCALBOARD_BACKUPRETENTIONCOUNT")
+	viper.BindEnv("backup_driver", "FOCALBOARD_BACKUPDRIVER")
+	viper.BindEnv("backup_path", "FOCALBOARD_BACKUPPATH")
+	viper.BindEnv("backup_passphrase", "FOCALBOARD_BACKUPPASSPHRASE")
+	viper.BindEnv("file_scan_driver", "FOCALBOARD_FILESCANDRIVER")
+	viper.BindEnv("clamd_address", "FOCALBOARD_CLAMDADDRESS")
+	viper.BindEnv("file_scan_timeout_seconds", "FOCALBOARD_FILESCANTIMEOUTSECONDS")
+	viper.BindEnv("team_storage_quota", "FOCALBOARD_TEAMSTORAGEQUOTA")
+	viper.BindEnv("orphan_file_cleanup_hours", "FOCALBOARD_ORPHANFILECLEANUPHOURS")
+	viper.BindEnv("max_upload_session_file_size", "FOCALBOARD_MAXUPLOADSESSIONFILESIZE")
+	viper.BindEnv("signed_url_expiry_seconds", "FOCALBOARD_SIGNEDURLEXPIRYSECONDS")
+	viper.BindEnv("signed_url_redirect_s3", "FOCALBOARD_SIGNEDURLREDIRECTS3")
 	viper.BindEnv("teammateNameDisplay", "FOCALBOARD_TEAMMATENAMEDISPLAY")
 	viper.BindEnv("showEmailAddress", "FOCALBOARD_SHOWEMAILADDRESS")
 	viper.BindEnv("showFullName", "FOCALBOARD_SHOWFULLNAME")
 	viper.BindEnv("authMode", "FOCALBOARD_AUTHMODE")
+	viper.BindEnv("login_max_failed_attempts", "FOCALBOARD_LOGINMAXFAILEDATTEMPTS")
+	viper.BindEnv("login_max_failed_attempts_per_ip", "FOCALBOARD_LOGINMAXFAILEDATTEMPTSPERIP")
+	viper.BindEnv("login_lockout_seconds", "FOCALBOARD_LOGINLOCKOUTSECONDS")
+	viper.BindEnv("login_failure_delay_seconds", "FOCALBOARD_LOGINFAILUREDELAYSECONDS")
 	viper.BindEnv("logging_cfg_file", "FOCALBOARD_LOGGINGCFGFILE")
 	viper.BindEnv("logging_cfg_json", "FOCALBOARD_LOGGINGCFGJSON")
 	viper.BindEnv("audit_cfg_file", "FOCALBOARD_AUDITCFGFILE")
@@ -280,6 +349,33 @@ func applyEnvironmentOverridesPre() {
 			viper.Set("webhook_update", webhookList)
 		}
 	}
+
+	// Handle the file type lists - comma separated MIME types
+	setListFromEnv("allowed_file_types", "FOCALBOARD_ALLOWEDFILETYPES")
+	setListFromEnv("denied_file_types", "FOCALBOARD_DENIEDFILETYPES")
+
+	// Handle the system admins - comma separated usernames
+	setListFromEnv("system_admin_usernames", "FOCALBOARD_SYSTEMADMINUSERNAMES")
+
+	// Handle the trusted proxies - comma separated IPs or CIDRs
+	setListFromEnv("trusted_proxies", "FOCALBOARD_TRUSTEDPROXIES")
+}
+
+// setListFromEnv sets a list setting from a comma separated environment
+// variable, if set.
+func setListFromEnv(key, env string) {
+	value, ok := os.LookupEnv(env)
+	if !ok {
+		return
+	}
+
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	viper.Set(key, list)
 }
 
 // applyEnvironmentOverridesPost applies environment variable overrides after viper unmarshaling
@@ -357,5 +453,6 @@ func parseFeatureFlags(featureFlagsStr string) map[string]string {
 
 func removeSecurityData(config Configuration) Configuration {
 	clean := config
+	clean.BackupPassphrase = ""
 	return clean
 }
@@ -133,6 +133,25 @@ func TestEnvironmentVariableOverrides(t *testing.T) {
 		assert.Equal(t, "amazons3", config.FilesDriver)
 		assert.Equal(t, "abc123def456789012345678901234567890.r2.cloudflarestorage.com", config.FilesS3Config.Endpoint)
 	})
+
+	// Test file type lists parsing
+	t.Run("File type lists parsing", func(t *testing.T) {
+		cleanupViper()
+
+		os.Setenv("FOCALBOARD_ALLOWEDFILETYPES", "image/*, application/pdf,")
+		os.Setenv("FOCALBOARD_DENIEDFILETYPES", "image/svg+xml")
+		defer func() {
+			os.Unsetenv("FOCALBOARD_ALLOWEDFILETYPES")
+			os.Unsetenv("FOCALBOARD_DENIEDFILETYPES")
+			cleanupViper()
+		}()
+
+		config, err := ReadConfigFile("")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"image/*", "application/pdf"}, config.AllowedFileTypes)
+		assert.Equal(t, []string{"image/svg+xml"}, config.DeniedFileTypes)
+	})
 }
 
 func TestParseFeatureFlags(t *testing.T) {
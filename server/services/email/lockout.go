@@ -0,0 +1,115 @@
+package email
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+)
+
+// LockoutData contains data for account lockout email templates
+type LockoutData struct {
+	Username      string
+	UnlockURL     string
+	LockedMinutes int
+	FromName      string
+}
+
+var (
+	lockoutHTMLTemplate = htmltemplate.Must(htmltemplate.New("lockout_html").Parse(defaultLockoutHTMLTemplate))
+	lockoutTextTemplate = texttemplate.Must(texttemplate.New("lockout_text").Parse(defaultLockoutTextTemplate))
+)
+
+// SendAccountLockout sends the email notifying a user that its account is
+// locked out after too many failed logins, with a link to unlock it
+func (s *Service) SendAccountLockout(toEmail, username, unlockToken, serverRoot string, lockedMinutes int) error {
+	if s.provider == nil {
+		return fmt.Errorf("email provider not configured")
+	}
+
+	// the link opens the unlock page of the webapp, as the unlocking is
+	// only done by a POST request, that the mail scanners don't send
+	unlockURL := fmt.Sprintf("%s/unlock/%s", strings.TrimSuffix(serverRoot, "/"), url.PathEscape(unlockToken))
+
+	data := LockoutData{
+		Username:      username,
+		UnlockURL:     unlockURL,
+		LockedMinutes: lockedMinutes,
+		FromName:      s.config.EmailConfig.FromName,
+	}
+
+	var htmlBody strings.Builder
+	if err := lockoutHTMLTemplate.Execute(&htmlBody, data); err != nil {
+		return fmt.Errorf("failed to render HTML template: %w", err)
+	}
+
+	var textBody strings.Builder
+	if err := lockoutTextTemplate.Execute(&textBody, data); err != nil {
+		return fmt.Errorf("failed to render text template: %w", err)
+	}
+
+	fromAddr := fmt.Sprintf("%s <%s>", s.config.EmailConfig.FromName, s.config.EmailConfig.FromEmail)
+
+	return s.provider.SendEmail(toEmail, fromAddr, defaultLockoutSubject, htmlBody.String(), textBody.String())
+}
+
+const defaultLockoutHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <title>Account Locked</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #f8f9fa; padding: 20px; border-radius: 8px; margin-bottom: 20px; }
+        .content { padding: 20px 0; }
+        .button {
+            display: inline-block;
+            background-color: #007bff;
+            color: white;
+            padding: 12px 24px;
+            text-decoration: none;
+            border-radius: 5px;
+            margin: 20px 0;
+        }
+        .footer { color: #666; font-size: 12px; margin-top: 30px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Your account has been locked</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.Username}},</p>
+            <p>There were too many failed attempts to log in to your Focalboard account, so it has been locked for {{.LockedMinutes}} minutes.</p>
+            <p>If these attempts were yours, you can unlock your account now:</p>
+            <a href="{{.UnlockURL}}" class="button">Unlock Account</a>
+            <p>If the button doesn't work, copy and paste this link into your browser:</p>
+            <p><a href="{{.UnlockURL}}">{{.UnlockURL}}</a></p>
+            <p>If these attempts weren't yours, someone may be trying to guess your password. Consider changing it once your account is unlocked.</p>
+        </div>
+        <div class="footer">
+            <p>This notification was sent by {{.FromName}}.</p>
+            <p>Powered by Focalboard</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+const defaultLockoutTextTemplate = `Your account has been locked
+
+Hi {{.Username}},
+
+There were too many failed attempts to log in to your Focalboard account, so it has been locked for {{.LockedMinutes}} minutes.
+
+If these attempts were yours, you can unlock your account now by visiting this link:
+{{.UnlockURL}}
+
+If these attempts weren't yours, someone may be trying to guess your password. Consider changing it once your account is unlocked.
+
+This notification was sent by {{.FromName}}.
+Powered by Focalboard`
+
+const defaultLockoutSubject = `Your Focalboard account has been locked`
@@ -0,0 +1,166 @@
+package filescan
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize is the size of the chunks streamed to clamd.
+const clamdChunkSize = 64 * 1024
+
+var ErrClamd = errors.New("clamd error")
+
+// Clamd is a client of the clamd daemon, scanning the files with the
+// INSTREAM command over TCP or a unix socket.
+type Clamd struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamd returns a clamd client. The address is either
+// `tcp://host:port`, `unix:///path/to/clamd.sock` or `host:port`.
+func NewClamd(address string, timeout time.Duration) (*Clamd, error) {
+	c := &Clamd{network: "tcp", address: address, timeout: timeout}
+	switch {
+	case strings.HasPrefix(address, "tcp://"):
+		c.address = strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "unix://"):
+		c.network = "unix"
+		c.address = strings.TrimPrefix(address, "unix://")
+	}
+	if c.address == "" {
+		return nil, fmt.Errorf("%w: missing address", ErrClamd)
+	}
+	return c, nil
+}
+
+// Ping checks that clamd is reachable.
+func (c *Clamd) Ping() error {
+	reply, err := c.command("zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("%w: unexpected reply %q", ErrClamd, reply)
+	}
+	return nil
+}
+
+func (c *Clamd) Scan(r io.Reader) (*Result, error) {
+	reply, err := c.command("zINSTREAM\x00", r)
+	if err != nil {
+		return nil, err
+	}
+
+	// the reply is `stream: OK`, `stream: <signature> FOUND` or
+	// `<message> ERROR`
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return &Result{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, "OK"):
+		return &Result{}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrClamd, reply)
+}
+
+// command sends a command to clamd, followed by the content of r in
+// chunks if not nil, and returns the reply.
+func (c *Clamd) command(command string, r io.Reader) (string, error) {
+	conn, err := net.DialTimeout(c.network, c.address, c.timeout)
+	if err != nil {
+		return "", fmt.Errorf("cannot connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if c.timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return "", err
+		}
+	}
+
+	if err = send(conn, command, r); err != nil {
+		var errSource *sourceError
+		if errors.As(err, &errSource) {
+			return "", errSource.err
+		}
+		// clamd replies and closes the connection when the stream is too
+		// large
+		if reply, errRead := readReply(conn); errRead == nil && reply != "" {
+			return reply, nil
+		}
+		return "", fmt.Errorf("cannot send to clamd: %w", err)
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the clamd reply: %w", err)
+	}
+	return reply, nil
+}
+
+func send(conn net.Conn, command string, r io.Reader) error {
+	w := bufio.NewWriterSize(conn, clamdChunkSize+4)
+	if _, err := w.WriteString(command); err != nil {
+		return err
+	}
+	if r != nil {
+		if err := writeChunks(w, r); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// writeChunks writes the content of r as length prefixed chunks, ending
+// with an empty chunk.
+func writeChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, errWrite := w.Write(size[:]); errWrite != nil {
+				return errWrite
+			}
+			if _, errWrite := w.Write(buf[:n]); errWrite != nil {
+				return errWrite
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return &sourceError{err: err}
+		}
+	}
+
+	binary.BigEndian.PutUint32(size[:], 0)
+	_, err := w.Write(size[:])
+	return err
+}
+
+// sourceError is an error reading the scanned content, as opposed to an
+// error sending it to clamd.
+type sourceError struct {
+	err error
+}
+
+func (e *sourceError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,95 @@
+package filescan
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/services/filescan/clamdtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failure")
+}
+
+func TestClamd(t *testing.T) {
+	server, err := clamdtest.NewServer()
+	require.NoError(t, err)
+	defer server.Close()
+
+	scanner, err := New(DriverClamd, server.Address(), 5*time.Second)
+	require.NoError(t, err)
+	clamd := scanner.(*Clamd)
+
+	t.Run("ping", func(t *testing.T) {
+		require.NoError(t, clamd.Ping())
+	})
+
+	t.Run("clean content", func(t *testing.T) {
+		result, err := scanner.Scan(strings.NewReader("a clean file"))
+		require.NoError(t, err)
+		require.False(t, result.Infected)
+	})
+
+	t.Run("infected content spanning several chunks", func(t *testing.T) {
+		content := append(bytes.Repeat([]byte("a"), 3*clamdChunkSize), []byte(clamdtest.EICAR)...)
+		result, err := scanner.Scan(bytes.NewReader(content))
+		require.NoError(t, err)
+		require.True(t, result.Infected)
+		require.Equal(t, clamdtest.EICARSignature, result.Signature)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		result, err := scanner.Scan(bytes.NewReader(nil))
+		require.NoError(t, err)
+		require.False(t, result.Infected)
+	})
+
+	t.Run("content that can't be read", func(t *testing.T) {
+		_, err := scanner.Scan(failingReader{})
+		require.EqualError(t, err, "read failure")
+	})
+
+	require.Equal(t, 3, server.Scanned())
+}
+
+func TestClamdUnreachable(t *testing.T) {
+	server, err := clamdtest.NewServer()
+	require.NoError(t, err)
+	address := server.Address()
+	server.Close()
+
+	clamd, err := NewClamd(address, time.Second)
+	require.NoError(t, err)
+	_, err = clamd.Scan(strings.NewReader("content"))
+	require.Error(t, err)
+	require.Error(t, clamd.Ping())
+}
+
+func TestNew(t *testing.T) {
+	scanner, err := New("", "", 0)
+	require.NoError(t, err)
+	require.Nil(t, scanner)
+
+	_, err = New("unknown", "", 0)
+	require.ErrorIs(t, err, ErrUnknownDriver)
+
+	_, err = New(DriverClamd, "", 0)
+	require.ErrorIs(t, err, ErrClamd)
+
+	clamd, err := NewClamd("unix:///var/run/clamd.sock", 0)
+	require.NoError(t, err)
+	require.Equal(t, "unix", clamd.network)
+	require.Equal(t, "/var/run/clamd.sock", clamd.address)
+
+	clamd, err = NewClamd("localhost:3310", 0)
+	require.NoError(t, err)
+	require.Equal(t, "tcp", clamd.network)
+	require.Equal(t, "localhost:3310", clamd.address)
+}
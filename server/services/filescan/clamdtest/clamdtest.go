@@ -0,0 +1,117 @@
+// Package clamdtest provides a fake clamd daemon for tests.
+package clamdtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EICAR is the standard antivirus test file, reported as infected by the
+// fake daemon.
+const EICAR = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// EICARSignature is the signature reported for EICAR.
+const EICARSignature = "Win.Test.EICAR_HDB-1"
+
+// Server is a fake clamd daemon listening on a local TCP port. It
+// implements the PING and INSTREAM commands.
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+	scanned  atomic.Int64
+}
+
+// NewServer starts a fake clamd daemon.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Address returns the address of the daemon, usable by filescan.NewClamd.
+func (s *Server) Address() string {
+	return "tcp://" + s.listener.Addr().String()
+}
+
+// Scanned returns the number of streams scanned so far.
+func (s *Server) Scanned() int {
+	return int(s.scanned.Load())
+}
+
+// Close stops the daemon.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch strings.TrimSuffix(command, "\x00") {
+	case "zPING":
+		_, _ = conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM":
+		content, err := readChunks(r)
+		if err != nil {
+			_, _ = conn.Write([]byte("INSTREAM read error. ERROR\x00"))
+			return
+		}
+		s.scanned.Add(1)
+		if bytes.Contains(content, []byte(EICAR)) {
+			_, _ = conn.Write([]byte("stream: " + EICARSignature + " FOUND\x00"))
+			return
+		}
+		_, _ = conn.Write([]byte("stream: OK\x00"))
+	default:
+		_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+	}
+}
+
+func readChunks(r io.Reader) ([]byte, error) {
+	var content bytes.Buffer
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil, err
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n == 0 {
+			return content.Bytes(), nil
+		}
+		if _, err := io.CopyN(&content, r, int64(n)); err != nil {
+			return nil, err
+		}
+	}
+}
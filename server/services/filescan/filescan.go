@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package filescan scans the uploaded files for malware.
+package filescan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// DriverClamd scans the files with a clamd daemon.
+	DriverClamd = "clamd"
+)
+
+var ErrUnknownDriver = errors.New("unknown file scan driver")
+
+// Result is the outcome of a scan.
+type Result struct {
+	// Infected is true if malware was found.
+	Infected bool
+
+	// Signature is the name of the malware found.
+	Signature string
+}
+
+// Scanner scans the content of files.
+type Scanner interface {
+	// Scan reads r until EOF and returns whether it is infected. An error
+	// is returned if the content couldn't be scanned.
+	Scan(r io.Reader) (*Result, error)
+}
+
+// New returns the scanner of the given driver, or nil if the driver is
+// empty.
+func New(driver, address string, timeout time.Duration) (Scanner, error) {
+	switch driver {
+	case "":
+		return nil, nil
+	case DriverClamd:
+		return NewClamd(address, timeout)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, driver)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+type asanaExport struct {
+	Data []asanaTask `json:"data"`
+}
+
+type asanaTask struct {
+	GID         string            `json:"gid"`
+	Name        string            `json:"name"`
+	Notes       string            `json:"notes"`
+	Completed   bool              `json:"completed"`
+	DueOn       string            `json:"due_on"`
+	Assignee    *asanaNamed       `json:"assignee"`
+	Tags        []asanaNamed      `json:"tags"`
+	Memberships []asanaMembership `json:"memberships"`
+	Subtasks    []asanaTask       `json:"subtasks"`
+}
+
+type asanaNamed struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+type asanaMembership struct {
+	Project *asanaNamed `json:"project"`
+	Section *asanaNamed `json:"section"`
+}
+
+// asanaProject is the board being built for an Asana project.
+type asanaProject struct {
+	builder   *boardBuilder
+	section   *property
+	assignee  *property
+	tags      *property
+	due       *property
+	completed *property
+}
+
+// importAsana converts an Asana JSON export. Each project becomes a
+// board, and the sections of the project the options of a select
+// property the board view is grouped by.
+func importAsana(r io.Reader) (*Result, error) {
+	var input asanaExport
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return nil, fmt.Errorf("invalid Asana JSON: %w", err)
+	}
+
+	result := newResult()
+	projects := map[string]*asanaProject{}
+	projectOrder := []*asanaProject{}
+
+	for _, task := range input.Data {
+		membership := task.firstMembership()
+		if membership == nil {
+			result.skip("card", task.Name, "the task doesn't belong to any project")
+			continue
+		}
+
+		project, ok := projects[membership.Project.GID]
+		if !ok {
+			project = newAsanaProject(membership.Project.Name)
+			projects[membership.Project.GID] = project
+			projectOrder = append(projectOrder, project)
+		}
+		project.addTask(task, membership)
+	}
+
+	for _, project := range projectOrder {
+		result.add(project.builder)
+	}
+	return result, nil
+}
+
+func newAsanaProject(name string) *asanaProject {
+	b := newBoardBuilder(name, "")
+	project := &asanaProject{
+		builder:   b,
+		section:   b.addProperty("Section", propTypeSelect),
+		assignee:  b.addProperty("Assignee", propTypeSelect),
+		tags:      b.addProperty("Tags", propTypeMultiSelect),
+		due:       b.addProperty("Due Date", propTypeDate),
+		completed: b.addProperty("Completed", propTypeCheckbox),
+	}
+	b.groupByProperty(project.section)
+	return project
+}
+
+func (p *asanaProject) addTask(task asanaTask, membership *asanaMembership) {
+	properties := map[string]interface{}{}
+	if membership.Section != nil && membership.Section.Name != "" {
+		properties[p.section.id] = p.section.optionID(membership.Section.Name)
+	}
+	if task.Assignee != nil && task.Assignee.Name != "" {
+		properties[p.assignee.id] = p.assignee.optionID(task.Assignee.Name)
+	}
+	if len(task.Tags) > 0 {
+		tagIDs := []interface{}{}
+		for _, tag := range task.Tags {
+			tagIDs = append(tagIDs, p.tags.optionID(tag.Name))
+		}
+		properties[p.tags.id] = tagIDs
+	}
+	if due, err := time.Parse("2006-01-02", task.DueOn); err == nil {
+		properties[p.due.id] = dateValue(utils.GetMillisForTime(due))
+	}
+	if task.Completed {
+		properties[p.completed.id] = "true"
+	}
+
+	card := p.builder.addCard(task.Name, properties)
+	p.builder.addText(card, task.Notes)
+	for _, subtask := range task.Subtasks {
+		p.builder.addCheckbox(card, subtask.Name, subtask.Completed)
+	}
+}
+
+func (t asanaTask) firstMembership() *asanaMembership {
+	for i := range t.Memberships {
+		if t.Memberships[i].Project != nil {
+			return &t.Memberships[i]
+		}
+	}
+	return nil
+}
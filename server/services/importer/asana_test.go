@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const asanaExportJSON = `{"data": [
+  {
+    "gid": "1", "name": "Write docs", "notes": "For the API", "completed": true, "due_on": "2022-01-02",
+    "assignee": {"gid": "u1", "name": "Jane Doe"},
+    "tags": [{"gid": "t1", "name": "docs"}],
+    "memberships": [{"project": {"gid": "p1", "name": "Launch"}, "section": {"gid": "s1", "name": "Doing"}}],
+    "subtasks": [{"gid": "2", "name": "Outline", "completed": true}]
+  },
+  {
+    "gid": "3", "name": "Hire", "memberships": [{"project": {"gid": "p2", "name": "Team"}, "section": {"gid": "s2", "name": "Backlog"}}]
+  },
+  {"gid": "4", "name": "Orphan task", "memberships": []}
+]}`
+
+func TestImportAsana(t *testing.T) {
+	result, err := Import(SourceAsana, strings.NewReader(asanaExportJSON), "export.json")
+	require.NoError(t, err)
+
+	boards := result.BoardsAndBlocks.Boards
+	require.Len(t, boards, 2)
+	require.Equal(t, "Launch", boards[0].Title)
+	require.Equal(t, "Team", boards[1].Title)
+
+	cards := cardsByTitle(t, result)
+	require.Len(t, cards, 2)
+	require.Equal(t, boards[1].ID, cards["Hire"].BoardID)
+
+	card := cards["Write docs"]
+	require.Equal(t, boards[0].ID, card.BoardID)
+	require.Equal(t, "Doing", propertyValue(boards[0], card, "Section"))
+	require.Equal(t, "Jane Doe", propertyValue(boards[0], card, "Assignee"))
+	require.Equal(t, "true", propertyValue(boards[0], card, "Completed"))
+	require.Equal(t, `{"from":1641081600000}`, propertyValue(boards[0], card, "Due Date"))
+	require.Len(t, propertyValue(boards[0], card, "Tags"), 1)
+
+	texts := childrenOf(result, card.ID, model.TypeText)
+	require.Len(t, texts, 1)
+	require.Equal(t, "For the API", texts[0].Title)
+
+	checkboxes := childrenOf(result, card.ID, model.TypeCheckbox)
+	require.Len(t, checkboxes, 1)
+	require.Equal(t, "Outline", checkboxes[0].Title)
+
+	require.Len(t, result.Skipped, 1)
+	require.Equal(t, "Orphan task", result.Skipped[0].Name)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	propTypeText        = "text"
+	propTypeSelect      = "select"
+	propTypeMultiSelect = "multiSelect"
+	propTypeDate        = "date"
+	propTypeCheckbox    = "checkbox"
+	propTypeURL         = "url"
+)
+
+var optionColors = []string{
+	"propColorGray",
+	"propColorBrown",
+	"propColorOrange",
+	"propColorYellow",
+	"propColorGreen",
+	"propColorBlue",
+	"propColorPurple",
+	"propColorPink",
+	"propColorRed",
+}
+
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+	".svg":  true,
+}
+
+// property is a card property template being built.
+type property struct {
+	id       string
+	name     string
+	propType string
+	options  []map[string]interface{}
+	byValue  map[string]string
+}
+
+// optionID returns the ID of the option with the given value, adding
+// the option to the property if needed.
+func (p *property) optionID(value string) string {
+	if id, ok := p.byValue[value]; ok {
+		return id
+	}
+	id := utils.NewID(utils.IDTypeBlock)
+	p.options = append(p.options, map[string]interface{}{
+		"id":    id,
+		"value": value,
+		"color": optionColors[len(p.options)%len(optionColors)],
+	})
+	p.byValue[value] = id
+	return id
+}
+
+func (p *property) template() map[string]interface{} {
+	options := make([]interface{}, len(p.options))
+	for i, option := range p.options {
+		options[i] = option
+	}
+	return map[string]interface{}{
+		"id":      p.id,
+		"name":    p.name,
+		"type":    p.propType,
+		"options": options,
+	}
+}
+
+// boardBuilder accumulates the board, card properties and blocks
+// converted from an export.
+type boardBuilder struct {
+	board      *model.Board
+	properties []*property
+	blocks     []*model.Block
+	files      []File
+	groupBy    *property
+	now        int64
+}
+
+func newBoardBuilder(title, description string) *boardBuilder {
+	now := utils.GetMillis()
+	return &boardBuilder{
+		board: &model.Board{
+			ID:              utils.NewID(utils.IDTypeBoard),
+			Type:            model.BoardTypePrivate,
+			MinimumRole:     model.BoardRoleNone,
+			Title:           title,
+			Description:     description,
+			ShowDescription: description != "",
+			Properties:      map[string]interface{}{},
+			CardProperties:  []map[string]interface{}{},
+			CreateAt:        now,
+			UpdateAt:        now,
+		},
+		now: now,
+	}
+}
+
+// addProperty adds a card property template to the board.
+func (b *boardBuilder) addProperty(name, propType string) *property {
+	p := &property{
+		id:       utils.NewID(utils.IDTypeBlock),
+		name:     name,
+		propType: propType,
+		options:  []map[string]interface{}{},
+		byValue:  map[string]string{},
+	}
+	b.properties = append(b.properties, p)
+	return p
+}
+
+// groupByProperty sets the select property the board view is grouped by.
+func (b *boardBuilder) groupByProperty(p *property) {
+	b.groupBy = p
+}
+
+func (b *boardBuilder) newBlock(blockType model.BlockType, parentID, title string, fields map[string]interface{}) *model.Block {
+	idType := utils.IDTypeBlock
+	if blockType == model.TypeCard {
+		idType = utils.IDTypeCard
+	}
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	block := &model.Block{
+		ID:       utils.NewID(idType),
+		ParentID: parentID,
+		BoardID:  b.board.ID,
+		Schema:   1,
+		Type:     blockType,
+		Title:    title,
+		Fields:   fields,
+		CreateAt: b.now,
+		UpdateAt: b.now,
+	}
+	b.blocks = append(b.blocks, block)
+	return block
+}
+
+// addCard adds a card with the given property values, indexed by
+// property template ID.
+func (b *boardBuilder) addCard(title string, properties map[string]interface{}) *model.Block {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	return b.newBlock(model.TypeCard, b.board.ID, title, map[string]interface{}{
+		"icon":         "",
+		"isTemplate":   false,
+		"properties":   properties,
+		"contentOrder": []interface{}{},
+	})
+}
+
+// addContent adds a content block to a card and appends it to the card's
+// content order.
+func (b *boardBuilder) addContent(card *model.Block, blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+	block := b.newBlock(blockType, card.ID, title, fields)
+	contentOrder, _ := card.Fields["contentOrder"].([]interface{})
+	card.Fields["contentOrder"] = append(contentOrder, block.ID)
+	return block
+}
+
+// addText adds a markdown text block to a card, if the text is not empty.
+func (b *boardBuilder) addText(card *model.Block, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	b.addContent(card, model.TypeText, text, nil)
+}
+
+// addCheckbox adds a checklist item to a card.
+func (b *boardBuilder) addCheckbox(card *model.Block, title string, checked bool) {
+	b.addContent(card, model.TypeCheckbox, title, map[string]interface{}{"value": checked})
+}
+
+// addComment adds a comment to a card. As the author of the comment
+// can't be mapped to a user, their name is prepended to the text.
+func (b *boardBuilder) addComment(card *model.Block, author, text string, createAt int64) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if author != "" {
+		text = fmt.Sprintf("**%s**: %s", author, text)
+	}
+	comment := b.newBlock(model.TypeComment, card.ID, text, nil)
+	if createAt != 0 {
+		comment.CreateAt = createAt
+		comment.UpdateAt = createAt
+	}
+}
+
+// addFile adds an image or an attachment block to a card, depending on
+// the extension of the file.
+func (b *boardBuilder) addFile(card *model.Block, filename string, data []byte) {
+	var block *model.Block
+	if imageExtensions[strings.ToLower(filepath.Ext(filename))] {
+		block = b.addContent(card, model.TypeImage, "", nil)
+	} else {
+		block = b.newBlock(model.TypeAttachment, card.ID, filename, nil)
+	}
+	b.files = append(b.files, File{
+		BlockID:  block.ID,
+		Filename: filename,
+		Data:     data,
+	})
+}
+
+func (b *boardBuilder) addView() {
+	groupByID := ""
+	if b.groupBy != nil {
+		groupByID = b.groupBy.id
+	}
+	b.newBlock(model.TypeView, b.board.ID, "Board view", map[string]interface{}{
+		"viewType":           "board",
+		"groupById":          groupByID,
+		"sortOptions":        []interface{}{},
+		"visiblePropertyIds": []interface{}{},
+		"visibleOptionIds":   []interface{}{},
+		"hiddenOptionIds":    []interface{}{},
+		"collapsedOptionIds": []interface{}{},
+		"filter": map[string]interface{}{
+			"operation": "and",
+			"filters":   []interface{}{},
+		},
+		"cardOrder":          []interface{}{},
+		"columnWidths":       map[string]interface{}{},
+		"columnCalculations": map[string]interface{}{},
+		"kanbanCalculations": map[string]interface{}{},
+		"defaultTemplateId":  "",
+	})
+}
+
+func (b *boardBuilder) build() (*model.Board, []*model.Block) {
+	for _, p := range b.properties {
+		b.board.CardProperties = append(b.board.CardProperties, p.template())
+	}
+	b.addView()
+	return b.board, b.blocks
+}
+
+// dateValue returns the value of a date property for the given time in
+// milliseconds.
+func dateValue(millis int64) string {
+	return fmt.Sprintf(`{"from":%d}`, millis)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package importer converts the exports of other project management tools
+// into boards and blocks.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// Source identifies the tool an export was generated by.
+type Source string
+
+const (
+	SourceTrello Source = "trello"
+	SourceJira   Source = "jira"
+	SourceAsana  Source = "asana"
+	SourceNotion Source = "notion"
+)
+
+var (
+	ErrUnsupportedSource = errors.New("unsupported import source")
+	ErrNothingToImport   = errors.New("the export doesn't contain anything to import")
+	ErrArchiveTooLarge   = errors.New("the export archive is too large uncompressed")
+)
+
+// File is an attachment found in an export. It needs to be stored before
+// the block referencing it is created.
+type File struct {
+	// BlockID is the image or attachment block that references the file.
+	BlockID  string
+	Filename string
+	Data     []byte
+}
+
+// Result is the outcome of converting an export.
+type Result struct {
+	BoardsAndBlocks *model.BoardsAndBlocks
+	Files           []File
+	Skipped         []model.ImportSkippedItem
+}
+
+// IsValidSource returns true if the source is supported by Import.
+func IsValidSource(source string) bool {
+	switch Source(source) {
+	case SourceTrello, SourceJira, SourceAsana, SourceNotion:
+		return true
+	}
+	return false
+}
+
+// Import converts the export read from r into boards and blocks. The
+// filename of the export is used to detect its format when a source
+// supports more than one. The resulting boards don't belong to any team.
+func Import(source Source, r io.Reader, filename string) (*Result, error) {
+	var result *Result
+	var err error
+
+	switch source {
+	case SourceTrello:
+		result, err = importTrello(r)
+	case SourceJira:
+		result, err = importJira(r, filename)
+	case SourceAsana:
+		result, err = importAsana(r)
+	case SourceNotion:
+		result, err = importNotion(r, filename)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSource, source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot import %s export: %w", source, err)
+	}
+
+	if len(result.BoardsAndBlocks.Boards) == 0 {
+		return nil, ErrNothingToImport
+	}
+	return result, nil
+}
+
+func (r *Result) skip(itemType, name, reason string) {
+	r.Skipped = append(r.Skipped, model.ImportSkippedItem{
+		Type:   itemType,
+		Name:   name,
+		Reason: reason,
+	})
+}
+
+func (r *Result) add(b *boardBuilder) {
+	board, blocks := b.build()
+	r.BoardsAndBlocks.Boards = append(r.BoardsAndBlocks.Boards, board)
+	r.BoardsAndBlocks.Blocks = append(r.BoardsAndBlocks.Blocks, blocks...)
+	r.Files = append(r.Files, b.files...)
+}
+
+func newResult() *Result {
+	return &Result{
+		BoardsAndBlocks: &model.BoardsAndBlocks{
+			Boards: []*model.Board{},
+			Blocks: []*model.Block{},
+		},
+		Files:   []File{},
+		Skipped: []model.ImportSkippedItem{},
+	}
+}
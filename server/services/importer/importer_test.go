@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	t.Run("unsupported source", func(t *testing.T) {
+		_, err := Import(Source("monday"), strings.NewReader("{}"), "export.json")
+		require.ErrorIs(t, err, ErrUnsupportedSource)
+	})
+
+	t.Run("invalid export", func(t *testing.T) {
+		_, err := Import(SourceTrello, strings.NewReader("not json"), "export.json")
+		require.Error(t, err)
+	})
+
+	t.Run("valid sources", func(t *testing.T) {
+		for _, source := range []string{"trello", "jira", "asana", "notion"} {
+			require.True(t, IsValidSource(source))
+		}
+		require.False(t, IsValidSource("monday"))
+	})
+}
+
+// cardsByTitle indexes the cards of a result by title, and checks that
+// all the blocks belong to a board of the result.
+func cardsByTitle(t *testing.T, result *Result) map[string]*model.Block {
+	t.Helper()
+	require.NoError(t, result.BoardsAndBlocks.IsValid())
+
+	cards := map[string]*model.Block{}
+	for _, block := range result.BoardsAndBlocks.Blocks {
+		if block.Type == model.TypeCard {
+			cards[block.Title] = block
+		}
+	}
+	return cards
+}
+
+// childrenOf returns the blocks of a type whose parent is the given block.
+func childrenOf(result *Result, parentID string, blockType model.BlockType) []*model.Block {
+	children := []*model.Block{}
+	for _, block := range result.BoardsAndBlocks.Blocks {
+		if block.ParentID == parentID && block.Type == blockType {
+			children = append(children, block)
+		}
+	}
+	return children
+}
+
+// propertyValue returns the value of a card property by name, resolving
+// select options to their value.
+func propertyValue(board *model.Board, card *model.Block, name string) interface{} {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	for _, template := range board.CardProperties {
+		if template["name"] != name {
+			continue
+		}
+		value := properties[template["id"].(string)]
+		if optionID, ok := value.(string); ok && template["type"] == propTypeSelect {
+			for _, option := range template["options"].([]interface{}) {
+				o := option.(map[string]interface{})
+				if o["id"] == optionID {
+					return o["value"]
+				}
+			}
+		}
+		return value
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+var (
+	htmlLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</h[1-6]>`)
+	htmlListItemRegex  = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlTagRegex       = regexp.MustCompile(`<[^>]*>`)
+	blankLinesRegex    = regexp.MustCompile(`\n{3,}`)
+)
+
+// jiraDateLayouts are the date formats used by the Jira XML and CSV
+// exports, depending on the locale settings of the instance.
+var jiraDateLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05.000-0700",
+}
+
+type jiraXML struct {
+	Channel struct {
+		Title string        `xml:"title"`
+		Items []jiraXMLItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type jiraXMLItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Key         string `xml:"key"`
+	Summary     string `xml:"summary"`
+	Description string `xml:"description"`
+	Type        string `xml:"type"`
+	Priority    string `xml:"priority"`
+	Status      string `xml:"status"`
+	Resolution  string `xml:"resolution"`
+	Assignee    string `xml:"assignee"`
+	Reporter    string `xml:"reporter"`
+	Created     string `xml:"created"`
+	Comments    []struct {
+		Author  string `xml:"author,attr"`
+		Created string `xml:"created,attr"`
+		Text    string `xml:",chardata"`
+	} `xml:"comments>comment"`
+	Attachments []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"attachments>attachment"`
+}
+
+// jiraIssue is an issue read from either a XML or a CSV export.
+type jiraIssue struct {
+	key         string
+	summary     string
+	description string
+	url         string
+	issueType   string
+	priority    string
+	status      string
+	resolution  string
+	assignee    string
+	reporter    string
+	created     string
+	comments    []jiraComment
+	attachments []string
+}
+
+type jiraComment struct {
+	author  string
+	created string
+	text    string
+}
+
+// importJira converts a Jira XML (RSS) or CSV export. The format is
+// detected from the extension of the file or, failing that, its content.
+func importJira(r io.Reader, filename string) (*Result, error) {
+	br := bufio.NewReader(r)
+
+	isXML := strings.HasSuffix(strings.ToLower(filename), ".xml")
+	if !strings.HasSuffix(strings.ToLower(filename), ".csv") && !isXML {
+		peek, _ := br.Peek(64)
+		isXML = strings.HasPrefix(strings.TrimSpace(string(peek)), "<")
+	}
+
+	var title string
+	var issues []jiraIssue
+	var err error
+	if isXML {
+		title, issues, err = readJiraXML(br)
+	} else {
+		title = "Jira import"
+		issues, err = readJiraCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := newResult()
+	b := newBoardBuilder(title, "")
+
+	keyProperty := b.addProperty("Key", propTypeText)
+	statusProperty := b.addProperty("Status", propTypeSelect)
+	typeProperty := b.addProperty("Type", propTypeSelect)
+	priorityProperty := b.addProperty("Priority", propTypeSelect)
+	resolutionProperty := b.addProperty("Resolution", propTypeSelect)
+	assigneeProperty := b.addProperty("Assignee", propTypeSelect)
+	reporterProperty := b.addProperty("Reporter", propTypeSelect)
+	urlProperty := b.addProperty("Original URL", propTypeURL)
+	createdProperty := b.addProperty("Created Date", propTypeDate)
+	b.groupByProperty(statusProperty)
+
+	for _, issue := range issues {
+		properties := map[string]interface{}{}
+		setText := func(p *property, value string) {
+			if value != "" {
+				properties[p.id] = value
+			}
+		}
+		setOption := func(p *property, value string) {
+			if value != "" {
+				properties[p.id] = p.optionID(value)
+			}
+		}
+		setText(keyProperty, issue.key)
+		setText(urlProperty, issue.url)
+		setOption(statusProperty, issue.status)
+		setOption(typeProperty, issue.issueType)
+		setOption(priorityProperty, issue.priority)
+		setOption(resolutionProperty, issue.resolution)
+		setOption(assigneeProperty, issue.assignee)
+		setOption(reporterProperty, issue.reporter)
+		if created := parseJiraDate(issue.created); created != 0 {
+			properties[createdProperty.id] = dateValue(created)
+		}
+
+		title := issue.summary
+		if title == "" {
+			title = issue.key
+		}
+		card := b.addCard(title, properties)
+		b.addText(card, htmlToText(issue.description))
+
+		for _, comment := range issue.comments {
+			b.addComment(card, comment.author, htmlToText(comment.text), parseJiraDate(comment.created))
+		}
+
+		// the exports only contain the names of the attachments
+		for _, attachment := range issue.attachments {
+			result.skip("attachment", attachment, fmt.Sprintf("the export of %s doesn't contain the file", title))
+		}
+	}
+
+	result.add(b)
+	return result, nil
+}
+
+func readJiraXML(r io.Reader) (string, []jiraIssue, error) {
+	var input jiraXML
+	if err := xml.NewDecoder(r).Decode(&input); err != nil {
+		return "", nil, fmt.Errorf("invalid Jira XML: %w", err)
+	}
+
+	issues := make([]jiraIssue, 0, len(input.Channel.Items))
+	for _, item := range input.Channel.Items {
+		issue := jiraIssue{
+			key:         item.Key,
+			summary:     item.Summary,
+			description: item.Description,
+			url:         item.Link,
+			issueType:   item.Type,
+			priority:    item.Priority,
+			status:      item.Status,
+			resolution:  item.Resolution,
+			assignee:    item.Assignee,
+			reporter:    item.Reporter,
+			created:     item.Created,
+		}
+		if issue.summary == "" {
+			issue.summary = item.Title
+		}
+		for _, comment := range item.Comments {
+			issue.comments = append(issue.comments, jiraComment{
+				author:  comment.Author,
+				created: comment.Created,
+				text:    comment.Text,
+			})
+		}
+		for _, attachment := range item.Attachments {
+			issue.attachments = append(issue.attachments, attachment.Name)
+		}
+		issues = append(issues, issue)
+	}
+
+	title := input.Channel.Title
+	if title == "" {
+		title = "Jira import"
+	}
+	return title, issues, nil
+}
+
+// readJiraCSV reads a Jira CSV export. Columns that can have several
+// values, like comments and attachments, are repeated in the header.
+// Comments have the form "date;author;text".
+func readJiraCSV(r io.Reader) ([]jiraIssue, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira CSV: %w", err)
+	}
+
+	issues := []jiraIssue{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Jira CSV: %w", err)
+		}
+
+		issue := jiraIssue{}
+		for i, value := range record {
+			if i >= len(header) || value == "" {
+				continue
+			}
+			switch strings.TrimSpace(header[i]) {
+			case "Summary":
+				issue.summary = value
+			case "Issue key":
+				issue.key = value
+			case "Issue Type":
+				issue.issueType = value
+			case "Status":
+				issue.status = value
+			case "Priority":
+				issue.priority = value
+			case "Resolution":
+				issue.resolution = value
+			case "Assignee":
+				issue.assignee = value
+			case "Reporter":
+				issue.reporter = value
+			case "Created":
+				issue.created = value
+			case "Description":
+				issue.description = value
+			case "Comment":
+				parts := strings.SplitN(value, ";", 3)
+				if len(parts) == 3 {
+					issue.comments = append(issue.comments, jiraComment{created: parts[0], author: parts[1], text: parts[2]})
+				} else {
+					issue.comments = append(issue.comments, jiraComment{text: value})
+				}
+			case "Attachment":
+				// attachments have the form "date;author;name;url"
+				parts := strings.Split(value, ";")
+				name := value
+				if len(parts) >= 3 {
+					name = parts[2]
+				}
+				issue.attachments = append(issue.attachments, name)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func parseJiraDate(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	for _, layout := range jiraDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return utils.GetMillisForTime(t)
+		}
+	}
+	return 0
+}
+
+// htmlToText converts the HTML markup used by Jira into plain text,
+// keeping the line breaks.
+func htmlToText(s string) string {
+	s = htmlListItemRegex.ReplaceAllString(s, "- ")
+	s = htmlLineBreakRegex.ReplaceAllString(s, "\n")
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	s = blankLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(s)
+}
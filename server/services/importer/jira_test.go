@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const jiraXMLExport = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="0.92">
+  <channel>
+    <title>Jira Project</title>
+    <item>
+      <title>[PRJ-1] Crash on start</title>
+      <link>https://jira.example.com/browse/PRJ-1</link>
+      <key id="1">PRJ-1</key>
+      <summary>Crash on start</summary>
+      <description>&lt;p&gt;The app &lt;b&gt;crashes&lt;/b&gt;&lt;/p&gt;&lt;ul&gt;&lt;li&gt;step 1&lt;/li&gt;&lt;/ul&gt;</description>
+      <type id="1">Bug</type>
+      <priority id="2">High</priority>
+      <status id="3">Open</status>
+      <resolution id="-1">Unresolved</resolution>
+      <assignee username="jdoe">Jane Doe</assignee>
+      <reporter username="jsmith">John Smith</reporter>
+      <created>Mon, 3 Jan 2022 10:00:00 +0000</created>
+      <comments>
+        <comment id="10" author="jdoe" created="Tue, 4 Jan 2022 10:00:00 +0000">&lt;p&gt;Looking&lt;/p&gt;</comment>
+      </comments>
+      <attachments>
+        <attachment id="20" name="log.txt" size="100"/>
+      </attachments>
+    </item>
+    <item>
+      <title>[PRJ-2] Add dark mode</title>
+      <key id="2">PRJ-2</key>
+      <summary>Add dark mode</summary>
+      <type id="2">Story</type>
+      <status id="4">Done</status>
+    </item>
+  </channel>
+</rss>`
+
+const jiraCSVExport = `Summary,Issue key,Issue Type,Status,Priority,Created,Description,Comment,Comment,Attachment
+Crash on start,PRJ-1,Bug,Open,High,03/Jan/22 10:00 AM,The app crashes,04/Jan/22 10:00 AM;jdoe;Looking,04/Jan/22 11:00 AM;jsmith;Thanks,03/Jan/22 10:00 AM;jdoe;log.txt;https://jira.example.com/log.txt
+Add dark mode,PRJ-2,Story,Done,,,,,,
+`
+
+func TestImportJira(t *testing.T) {
+	t.Run("XML export", func(t *testing.T) {
+		result, err := Import(SourceJira, strings.NewReader(jiraXMLExport), "export.xml")
+		require.NoError(t, err)
+
+		require.Len(t, result.BoardsAndBlocks.Boards, 1)
+		board := result.BoardsAndBlocks.Boards[0]
+		require.Equal(t, "Jira Project", board.Title)
+
+		cards := cardsByTitle(t, result)
+		require.Len(t, cards, 2)
+
+		card := cards["Crash on start"]
+		require.Equal(t, "PRJ-1", propertyValue(board, card, "Key"))
+		require.Equal(t, "Open", propertyValue(board, card, "Status"))
+		require.Equal(t, "Bug", propertyValue(board, card, "Type"))
+		require.Equal(t, "High", propertyValue(board, card, "Priority"))
+		require.Equal(t, "Jane Doe", propertyValue(board, card, "Assignee"))
+		require.Equal(t, "https://jira.example.com/browse/PRJ-1", propertyValue(board, card, "Original URL"))
+		require.Equal(t, `{"from":1641204000000}`, propertyValue(board, card, "Created Date"))
+		require.Equal(t, "Done", propertyValue(board, cards["Add dark mode"], "Status"))
+
+		texts := childrenOf(result, card.ID, model.TypeText)
+		require.Len(t, texts, 1)
+		require.Equal(t, "The app crashes\n- step 1", texts[0].Title)
+
+		comments := childrenOf(result, card.ID, model.TypeComment)
+		require.Len(t, comments, 1)
+		require.Equal(t, "**jdoe**: Looking", comments[0].Title)
+
+		require.Len(t, result.Skipped, 1)
+		require.Equal(t, "attachment", result.Skipped[0].Type)
+		require.Equal(t, "log.txt", result.Skipped[0].Name)
+	})
+
+	t.Run("CSV export", func(t *testing.T) {
+		result, err := Import(SourceJira, strings.NewReader(jiraCSVExport), "export.csv")
+		require.NoError(t, err)
+
+		board := result.BoardsAndBlocks.Boards[0]
+		cards := cardsByTitle(t, result)
+		require.Len(t, cards, 2)
+
+		card := cards["Crash on start"]
+		require.Equal(t, "PRJ-1", propertyValue(board, card, "Key"))
+		require.Equal(t, "Open", propertyValue(board, card, "Status"))
+		require.Equal(t, `{"from":1641204000000}`, propertyValue(board, card, "Created Date"))
+		require.Len(t, childrenOf(result, card.ID, model.TypeComment), 2)
+
+		require.Len(t, result.Skipped, 1)
+		require.Equal(t, "log.txt", result.Skipped[0].Name)
+	})
+
+	t.Run("the format is detected from the content", func(t *testing.T) {
+		result, err := Import(SourceJira, strings.NewReader(jiraXMLExport), "export")
+		require.NoError(t, err)
+		require.Len(t, cardsByTitle(t, result), 2)
+	})
+}
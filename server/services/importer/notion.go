@@ -0,0 +1,317 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// notionMaxSelectOptions is the number of distinct values above which a
+	// column is imported as a text property rather than a select one.
+	notionMaxSelectOptions = 30
+
+	// zipMaxEntrySize and zipMaxTotalSize cap the uncompressed size of
+	// each file read from an archive, and of all of them.
+	zipMaxEntrySize = 100 * 1024 * 1024
+	zipMaxTotalSize = 500 * 1024 * 1024
+)
+
+var (
+	ErrNotionNoCSV = errors.New("the archive doesn't contain a CSV file")
+
+	// Notion appends the ID of the page to the names of the exported files.
+	notionIDSuffixRegex = regexp.MustCompile(` [0-9a-f]{32}$`)
+	markdownImageRegex  = regexp.MustCompile(`!\[[^\]]*\]\(([^)]+)\)\n?`)
+)
+
+type notionPage struct {
+	title   string
+	content string
+	// dir holds the files attached to the page.
+	dir string
+}
+
+// importNotion converts a Notion database export, either as a single CSV
+// file or as the "Markdown & CSV" zip archive, which also contains the
+// content of each page and its files.
+func importNotion(r io.Reader, filename string) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newResult()
+
+	if !bytes.HasPrefix(data, []byte("PK")) {
+		title := notionTitle(strings.TrimSuffix(path.Base(filename), path.Ext(filename)))
+		if _, err := importNotionCSV(result, title, data, nil, nil, nil); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Notion archive: %w", err)
+	}
+
+	csvFile := findNotionCSV(zr.File)
+	if csvFile == nil {
+		return nil, ErrNotionNoCSV
+	}
+	archive := newZipReader()
+	csvData, err := archive.readFile(csvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// the pages of the database are stored in a directory named after
+	// the CSV file
+	csvBase := strings.TrimSuffix(strings.TrimSuffix(csvFile.Name, ".csv"), "_all")
+	pagesDir := csvBase + "/"
+	pages := map[string][]*notionPage{}
+	files := []*zip.File{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || f == csvFile || !strings.HasPrefix(f.Name, pagesDir) {
+			continue
+		}
+		rel := strings.TrimPrefix(f.Name, pagesDir)
+		if strings.HasSuffix(rel, ".md") && !strings.Contains(rel, "/") {
+			content, err := archive.readFile(f)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(rel, ".md")
+			page := &notionPage{
+				title:   notionTitle(name),
+				content: string(content),
+				dir:     pagesDir + name + "/",
+			}
+			pages[page.title] = append(pages[page.title], page)
+			continue
+		}
+		files = append(files, f)
+	}
+
+	title := notionTitle(path.Base(csvBase))
+	used, err := importNotionCSV(result, title, csvData, pages, files, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidates := range pages {
+		for _, page := range candidates {
+			result.skip("page", page.title, "the page doesn't match any row of the CSV")
+		}
+	}
+	for _, f := range files {
+		if !used[f.Name] {
+			result.skip("attachment", f.Name, "the file doesn't belong to any card")
+		}
+	}
+	return result, nil
+}
+
+// importNotionCSV adds a board with a card per row of the CSV. The first
+// column holds the title of the cards. It returns the names of the
+// archive files that were attached to a card.
+func importNotionCSV(result *Result, title string, data []byte, pages map[string][]*notionPage, files []*zip.File, archive *zipReader) (map[string]bool, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Notion CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, ErrNothingToImport
+	}
+	header, rows := records[0], records[1:]
+
+	b := newBoardBuilder(title, "")
+	columns := make([]*property, len(header))
+	for i := 1; i < len(header); i++ {
+		distinct := map[string]bool{}
+		for _, row := range rows {
+			if i < len(row) && row[i] != "" {
+				distinct[row[i]] = true
+			}
+		}
+		propType := propTypeSelect
+		if len(distinct) > notionMaxSelectOptions {
+			propType = propTypeText
+		}
+		columns[i] = b.addProperty(header[i], propType)
+		if i == 1 && propType == propTypeSelect {
+			b.groupByProperty(columns[i])
+		}
+	}
+
+	used := map[string]bool{}
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		properties := map[string]interface{}{}
+		for i := 1; i < len(row) && i < len(columns); i++ {
+			if row[i] == "" {
+				continue
+			}
+			if columns[i].propType == propTypeSelect {
+				properties[columns[i].id] = columns[i].optionID(row[i])
+			} else {
+				properties[columns[i].id] = row[i]
+			}
+		}
+
+		card := b.addCard(row[0], properties)
+
+		candidates := pages[row[0]]
+		if len(candidates) == 0 {
+			continue
+		}
+		page := candidates[0]
+		pages[row[0]] = candidates[1:]
+
+		b.addText(card, notionPageContent(page.content, row[0], header))
+		for _, f := range files {
+			if !strings.HasPrefix(f.Name, page.dir) {
+				continue
+			}
+			if strings.HasSuffix(f.Name, ".md") {
+				result.skip("page", f.Name, "sub-pages are not supported")
+				used[f.Name] = true
+				continue
+			}
+			fileData, err := archive.readFile(f)
+			if err != nil {
+				return nil, err
+			}
+			b.addFile(card, path.Base(f.Name), fileData)
+			used[f.Name] = true
+		}
+	}
+
+	result.add(b)
+	return used, nil
+}
+
+// notionPageContent removes from the markdown of a page its title, its
+// properties, which are already imported from the CSV, and the links to
+// its files, which are imported as image and attachment blocks.
+func notionPageContent(content, title string, header []string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i < len(lines) && strings.TrimSpace(lines[i]) == "# "+title {
+		i++
+	}
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	for i < len(lines) && isNotionPropertyLine(lines[i], header) {
+		i++
+	}
+
+	body := strings.Join(lines[i:], "\n")
+	body = markdownImageRegex.ReplaceAllStringFunc(body, func(s string) string {
+		if strings.Contains(s, "://") {
+			return s
+		}
+		return ""
+	})
+	return strings.TrimSpace(body)
+}
+
+func isNotionPropertyLine(line string, header []string) bool {
+	for _, name := range header {
+		if strings.HasPrefix(line, name+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+// findNotionCSV returns the CSV file of the database, which is the one
+// closest to the root of the archive. Recent exports contain both a CSV
+// of the current view and a "_all" one with every row, the latter being
+// preferred.
+func findNotionCSV(files []*zip.File) *zip.File {
+	csvFiles := []*zip.File{}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name, ".csv") {
+			csvFiles = append(csvFiles, f)
+		}
+	}
+	if len(csvFiles) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(csvFiles, func(i, j int) bool {
+		di, dj := strings.Count(csvFiles[i].Name, "/"), strings.Count(csvFiles[j].Name, "/")
+		if di != dj {
+			return di < dj
+		}
+		return strings.HasSuffix(csvFiles[i].Name, "_all.csv") && !strings.HasSuffix(csvFiles[j].Name, "_all.csv")
+	})
+	return csvFiles[0]
+}
+
+func notionTitle(name string) string {
+	return notionIDSuffixRegex.ReplaceAllString(name, "")
+}
+
+// zipReader reads the files of an archive, failing with
+// ErrArchiveTooLarge when a file or all of them are larger than the caps.
+type zipReader struct {
+	remaining int64
+}
+
+func newZipReader() *zipReader {
+	return &zipReader{remaining: zipMaxTotalSize}
+}
+
+func (zr *zipReader) readFile(f *zip.File) ([]byte, error) {
+	limit := int64(zipMaxEntrySize)
+	if zr.remaining < limit {
+		limit = zr.remaining
+	}
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("%w: %s", ErrArchiveTooLarge, f.Name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// the uncompressed size of the zip headers can't be trusted
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s", ErrArchiveTooLarge, f.Name)
+	}
+	zr.remaining -= int64(len(data))
+	return data, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const notionCSV = "\ufeffName,Status,Owner\nTask 1,Doing,Jane\nTask 2,Done,\n"
+
+func TestImportNotion(t *testing.T) {
+	t.Run("CSV export", func(t *testing.T) {
+		result, err := Import(SourceNotion, strings.NewReader(notionCSV), "Tasks 0123456789abcdef0123456789abcdef.csv")
+		require.NoError(t, err)
+
+		board := result.BoardsAndBlocks.Boards[0]
+		require.Equal(t, "Tasks", board.Title)
+		require.Len(t, board.CardProperties, 2)
+
+		cards := cardsByTitle(t, result)
+		require.Len(t, cards, 2)
+		require.Equal(t, "Doing", propertyValue(board, cards["Task 1"], "Status"))
+		require.Equal(t, "Jane", propertyValue(board, cards["Task 1"], "Owner"))
+		require.Nil(t, propertyValue(board, cards["Task 2"], "Owner"))
+	})
+
+	t.Run("zip export", func(t *testing.T) {
+		const id = " 0123456789abcdef0123456789abcdef"
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		files := map[string]string{
+			"Tasks" + id + ".csv":                         notionCSV,
+			"Tasks" + id + "/Task 1" + id + ".md":         "# Task 1\n\nStatus: Doing\nOwner: Jane\n\nSome **content**\n\n![shot.png](Task%201/shot.png)\n",
+			"Tasks" + id + "/Task 1" + id + "/shot.png":   "png",
+			"Tasks" + id + "/Task 1" + id + "/report.pdf": "pdf",
+			"Tasks" + id + "/Other" + id + "/orphan.png":  "png",
+		}
+		for name, content := range files {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		result, err := Import(SourceNotion, buf, "export.zip")
+		require.NoError(t, err)
+
+		board := result.BoardsAndBlocks.Boards[0]
+		require.Equal(t, "Tasks", board.Title)
+
+		cards := cardsByTitle(t, result)
+		require.Len(t, cards, 2)
+
+		card := cards["Task 1"]
+		texts := childrenOf(result, card.ID, model.TypeText)
+		require.Len(t, texts, 1)
+		require.Equal(t, "Some **content**", texts[0].Title)
+
+		images := childrenOf(result, card.ID, model.TypeImage)
+		require.Len(t, images, 1)
+		attachments := childrenOf(result, card.ID, model.TypeAttachment)
+		require.Len(t, attachments, 1)
+		require.Equal(t, "report.pdf", attachments[0].Title)
+
+		require.Len(t, result.Files, 2)
+		for _, file := range result.Files {
+			require.Contains(t, []string{images[0].ID, attachments[0].ID}, file.BlockID)
+		}
+
+		require.Len(t, result.Skipped, 1)
+		require.Equal(t, "attachment", result.Skipped[0].Type)
+	})
+
+	t.Run("zip without CSV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		_, err := zw.Create("page.md")
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		_, err = Import(SourceNotion, buf, "export.zip")
+		require.ErrorIs(t, err, ErrNotionNoCSV)
+	})
+}
+
+func TestZipReaderLimits(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("12345"))
+		require.NoError(t, err)
+	}
+	w, err := zw.CreateRaw(&zip.FileHeader{Name: "bomb.txt", Method: zip.Store, CompressedSize64: 5, UncompressedSize64: zipMaxEntrySize + 1})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("12345"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	t.Run("the total size is capped", func(t *testing.T) {
+		archive := &zipReader{remaining: 8}
+		data, err := archive.readFile(r.File[0])
+		require.NoError(t, err)
+		require.Equal(t, "12345", string(data))
+
+		_, err = archive.readFile(r.File[1])
+		require.ErrorIs(t, err, ErrArchiveTooLarge)
+	})
+
+	t.Run("the size of each file is capped", func(t *testing.T) {
+		_, err := newZipReader().readFile(r.File[2])
+		require.ErrorIs(t, err, ErrArchiveTooLarge)
+	})
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Desc       string            `json:"desc"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Labels     []trelloLabel     `json:"labels"`
+	Checklists []trelloChecklist `json:"checklists"`
+	Actions    []trelloAction    `json:"actions"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Desc         string             `json:"desc"`
+	Closed       bool               `json:"closed"`
+	IDList       string             `json:"idList"`
+	IDLabels     []string           `json:"idLabels"`
+	IDChecklists []string           `json:"idChecklists"`
+	Due          *time.Time         `json:"due"`
+	DueComplete  bool               `json:"dueComplete"`
+	Pos          float64            `json:"pos"`
+	Attachments  []trelloAttachment `json:"attachments"`
+}
+
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloChecklist struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+type trelloAttachment struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type trelloAction struct {
+	Type          string    `json:"type"`
+	Date          time.Time `json:"date"`
+	MemberCreator struct {
+		FullName string `json:"fullName"`
+	} `json:"memberCreator"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+}
+
+// importTrello converts a Trello board JSON export. Lists become the
+// options of a select property the board view is grouped by.
+func importTrello(r io.Reader) (*Result, error) {
+	var input trelloBoard
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return nil, fmt.Errorf("invalid Trello JSON: %w", err)
+	}
+
+	result := newResult()
+	b := newBoardBuilder(input.Name, input.Desc)
+
+	listProperty := b.addProperty("List", propTypeSelect)
+	labelsProperty := b.addProperty("Labels", propTypeMultiSelect)
+	dueProperty := b.addProperty("Due Date", propTypeDate)
+	dueCompleteProperty := b.addProperty("Due Complete", propTypeCheckbox)
+	b.groupByProperty(listProperty)
+
+	sort.SliceStable(input.Lists, func(i, j int) bool { return input.Lists[i].Pos < input.Lists[j].Pos })
+	lists := make(map[string]trelloList, len(input.Lists))
+	for _, list := range input.Lists {
+		lists[list.ID] = list
+		if !list.Closed {
+			listProperty.optionID(list.Name)
+		}
+	}
+
+	labels := make(map[string]string, len(input.Labels))
+	for _, label := range input.Labels {
+		name := label.Name
+		if name == "" {
+			name = label.Color
+		}
+		labels[label.ID] = name
+	}
+
+	checklists := make(map[string]trelloChecklist, len(input.Checklists))
+	for _, checklist := range input.Checklists {
+		checklists[checklist.ID] = checklist
+	}
+
+	comments := map[string][]trelloAction{}
+	for _, action := range input.Actions {
+		if action.Type == "commentCard" {
+			comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], action)
+		}
+	}
+
+	sort.SliceStable(input.Cards, func(i, j int) bool { return input.Cards[i].Pos < input.Cards[j].Pos })
+	for _, trelloCard := range input.Cards {
+		list, ok := lists[trelloCard.IDList]
+		switch {
+		case trelloCard.Closed:
+			result.skip("card", trelloCard.Name, "the card is archived")
+			continue
+		case !ok:
+			result.skip("card", trelloCard.Name, "the card doesn't belong to any list")
+			continue
+		case list.Closed:
+			result.skip("card", trelloCard.Name, "the list of the card is archived")
+			continue
+		}
+
+		properties := map[string]interface{}{
+			listProperty.id: listProperty.optionID(list.Name),
+		}
+		if len(trelloCard.IDLabels) > 0 {
+			labelIDs := []interface{}{}
+			for _, labelID := range trelloCard.IDLabels {
+				if name, ok := labels[labelID]; ok && name != "" {
+					labelIDs = append(labelIDs, labelsProperty.optionID(name))
+				}
+			}
+			properties[labelsProperty.id] = labelIDs
+		}
+		if trelloCard.Due != nil {
+			properties[dueProperty.id] = dateValue(utils.GetMillisForTime(*trelloCard.Due))
+		}
+		if trelloCard.DueComplete {
+			properties[dueCompleteProperty.id] = "true"
+		}
+
+		card := b.addCard(trelloCard.Name, properties)
+		b.addText(card, trelloCard.Desc)
+
+		for _, checklistID := range trelloCard.IDChecklists {
+			checklist, ok := checklists[checklistID]
+			if !ok {
+				continue
+			}
+			b.addText(card, "### "+checklist.Name)
+			sort.SliceStable(checklist.CheckItems, func(i, j int) bool {
+				return checklist.CheckItems[i].Pos < checklist.CheckItems[j].Pos
+			})
+			for _, item := range checklist.CheckItems {
+				b.addCheckbox(card, item.Name, item.State == "complete")
+			}
+		}
+
+		// attachments are only available as links to Trello, which
+		// require the user's credentials to be downloaded
+		for _, attachment := range trelloCard.Attachments {
+			b.addText(card, fmt.Sprintf("[%s](%s)", attachment.Name, attachment.URL))
+		}
+
+		for _, comment := range comments[trelloCard.ID] {
+			b.addComment(card, comment.MemberCreator.FullName, comment.Data.Text, utils.GetMillisForTime(comment.Date))
+		}
+	}
+
+	result.add(b)
+	return result, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const trelloExport = `{
+  "name": "Roadmap",
+  "desc": "Our roadmap",
+  "lists": [
+    {"id": "l2", "name": "Done", "pos": 2},
+    {"id": "l1", "name": "To Do", "pos": 1},
+    {"id": "l3", "name": "Old", "pos": 3, "closed": true}
+  ],
+  "labels": [
+    {"id": "lb1", "name": "Bug", "color": "red"},
+    {"id": "lb2", "name": "", "color": "green"}
+  ],
+  "cards": [
+    {
+      "id": "c1", "name": "Fix login", "desc": "It fails", "idList": "l1", "pos": 1,
+      "idLabels": ["lb1", "lb2"], "idChecklists": ["ch1"], "due": "2022-01-02T10:00:00.000Z",
+      "attachments": [{"name": "spec", "url": "https://trello.com/spec.pdf"}]
+    },
+    {"id": "c2", "name": "Ship it", "idList": "l2", "pos": 2},
+    {"id": "c3", "name": "Archived card", "idList": "l1", "pos": 3, "closed": true},
+    {"id": "c4", "name": "Card in archived list", "idList": "l3", "pos": 4}
+  ],
+  "checklists": [
+    {"id": "ch1", "name": "Steps", "checkItems": [
+      {"name": "second", "state": "incomplete", "pos": 2},
+      {"name": "first", "state": "complete", "pos": 1}
+    ]}
+  ],
+  "actions": [
+    {"type": "commentCard", "date": "2022-01-01T10:00:00.000Z",
+     "memberCreator": {"fullName": "Jane Doe"}, "data": {"text": "On it", "card": {"id": "c1"}}},
+    {"type": "updateCard", "data": {"card": {"id": "c1"}}}
+  ]
+}`
+
+func TestImportTrello(t *testing.T) {
+	result, err := Import(SourceTrello, strings.NewReader(trelloExport), "roadmap.json")
+	require.NoError(t, err)
+
+	require.Len(t, result.BoardsAndBlocks.Boards, 1)
+	board := result.BoardsAndBlocks.Boards[0]
+	require.Equal(t, "Roadmap", board.Title)
+	require.Equal(t, "Our roadmap", board.Description)
+
+	cards := cardsByTitle(t, result)
+	require.Len(t, cards, 2)
+	require.Len(t, result.Skipped, 2)
+	require.Equal(t, "Archived card", result.Skipped[0].Name)
+	require.Equal(t, "Card in archived list", result.Skipped[1].Name)
+
+	t.Run("properties", func(t *testing.T) {
+		card := cards["Fix login"]
+		require.Equal(t, "To Do", propertyValue(board, card, "List"))
+		require.Equal(t, "Done", propertyValue(board, cards["Ship it"], "List"))
+		require.Len(t, propertyValue(board, card, "Labels"), 2)
+		require.Equal(t, `{"from":1641117600000}`, propertyValue(board, card, "Due Date"))
+	})
+
+	t.Run("content", func(t *testing.T) {
+		card := cards["Fix login"]
+		texts := childrenOf(result, card.ID, model.TypeText)
+		require.Len(t, texts, 3)
+		require.Equal(t, "It fails", texts[0].Title)
+		require.Equal(t, "### Steps", texts[1].Title)
+		require.Equal(t, "[spec](https://trello.com/spec.pdf)", texts[2].Title)
+
+		checkboxes := childrenOf(result, card.ID, model.TypeCheckbox)
+		require.Len(t, checkboxes, 2)
+		require.Equal(t, "first", checkboxes[0].Title)
+		require.Equal(t, true, checkboxes[0].Fields["value"])
+		require.Equal(t, false, checkboxes[1].Fields["value"])
+
+		require.Len(t, card.Fields["contentOrder"], 5)
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		comments := childrenOf(result, cards["Fix login"].ID, model.TypeComment)
+		require.Len(t, comments, 1)
+		require.Equal(t, "**Jane Doe**: On it", comments[0].Title)
+		require.Equal(t, int64(1641031200000), comments[0].CreateAt)
+	})
+}
@@ -103,12 +103,12 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 		return nil
 	}
 
-	mentions := extractMentions(evt.BlockChanged)
+	mentions := b.expandGroupMentions(evt.TeamID, extractMentions(evt.BlockChanged))
 	if len(mentions) == 0 {
 		return nil
 	}
 
-	oldMentions := extractMentions(evt.BlockOld)
+	oldMentions := b.expandGroupMentions(evt.TeamID, extractMentions(evt.BlockOld))
 	merr := merror.New()
 
 	b.mux.RLock()
@@ -116,13 +116,13 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 	copy(listeners, b.listeners)
 	b.mux.RUnlock()
 
-	for username := range mentions {
+	for username, mention := range mentions {
 		if _, exists := oldMentions[username]; exists {
 			// the mention already existed; no need to notify again
 			continue
 		}
 
-		extract := extractText(evt.BlockChanged.Title, username, newLimits())
+		extract := extractText(evt.BlockChanged.Title, mention, newLimits())
 
 		userID, err := b.deliverMentionNotification(username, extract, evt)
 		if err != nil {
@@ -150,6 +150,30 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 	return merr.ErrorOrNil()
 }
 
+// expandGroupMentions replaces the mentions of the user groups of a team
+// by the mentions of their members. It returns the mentioned usernames,
+// each with the mention it comes from.
+func (b *Backend) expandGroupMentions(teamID string, mentions map[string]struct{}) map[string]string {
+	usernames := make(map[string]string, len(mentions))
+	for name := range mentions {
+		members, err := b.appAPI.GetUserGroupMembersByName(teamID, name)
+		if err != nil {
+			if !model.IsErrNotFound(err) {
+				b.logger.Error("Cannot lookup mentioned user group", mlog.String("group", name), mlog.Err(err))
+			}
+			usernames[name] = name
+			continue
+		}
+
+		for _, member := range members {
+			if _, exists := usernames[member.Username]; !exists {
+				usernames[member.Username] = name
+			}
+		}
+	}
+	return usernames
+}
+
 func safeCallListener(listener MentionListener, userID string, evt notify.BlockChangeEvent, logger mlog.LoggerIFace) {
 	// don't let panicky listeners stop notifications
 	defer func() {
@@ -175,6 +199,11 @@ func (b *Backend) deliverMentionNotification(username string, extract string, ev
 		return "", fmt.Errorf("invalid user cannot mention: %w", ErrMentionPermission)
 	}
 
+	// the users are not notified of the private cards hidden to them
+	if !permissions.GetCardAccess(b.permissions, evt.Board, mentionedUser.Id).CanViewCard(evt.Card) {
+		return "", fmt.Errorf("%s cannot see the private card %s: %w", mentionedUser.Id, evt.Card.ID, ErrMentionPermission)
+	}
+
 	if evt.Board.Type == model.BoardTypeOpen {
 		// public board rules:
 		//    - admin, editor, commenter: can mention anyone on team (mentioned users are automatically added to board)
@@ -10,6 +10,7 @@ import (
 	"github.com/mattermost/focalboard/server/model"
 
 	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 func Test_extractMentions(t *testing.T) {
@@ -35,6 +36,47 @@ func Test_extractMentions(t *testing.T) {
 	}
 }
 
+type groupsAppAPI struct {
+	AppAPI
+	groups map[string][]*model.User
+}
+
+func (a groupsAppAPI) GetUserGroupMembersByName(teamID, name string) ([]*model.User, error) {
+	members, ok := a.groups[teamID+"/"+name]
+	if !ok {
+		return nil, model.NewErrNotFound("user group")
+	}
+	return members, nil
+}
+
+func Test_expandGroupMentions(t *testing.T) {
+	backend := New(BackendParams{
+		AppAPI: groupsAppAPI{groups: map[string][]*model.User{
+			"team-id/designers": {{Username: "user1"}, {Username: "user2"}},
+			"team-id/empty":     {},
+		}},
+		Logger: mlog.CreateConsoleTestLogger(t),
+	})
+
+	tests := []struct {
+		name     string
+		mentions map[string]struct{}
+		want     map[string]string
+	}{
+		{name: "no mentions", mentions: makeMap(), want: map[string]string{}},
+		{name: "users", mentions: makeMap("user1", "user3"), want: map[string]string{"user1": "user1", "user3": "user3"}},
+		{name: "group", mentions: makeMap("designers"), want: map[string]string{"user1": "designers", "user2": "designers"}},
+		{name: "empty group", mentions: makeMap("empty"), want: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backend.expandGroupMentions("team-id", tt.mentions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandGroupMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func makeBlock(text string) *model.Block {
 	return &model.Block{
 		ID:    mm_model.NewId(),
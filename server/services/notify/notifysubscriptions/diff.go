@@ -5,7 +5,6 @@ package notifysubscriptions
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/mattermost/focalboard/server/model"
 
@@ -30,13 +29,7 @@ type Diff struct {
 	Diffs []*Diff // Diffs for child blocks
 }
 
-type PropDiff struct {
-	ID       string // property id
-	Index    int
-	Name     string
-	OldValue string
-	NewValue string
-}
+type PropDiff = model.PropDiff
 
 type SchemaDiff struct {
 	Board *model.Board
@@ -291,8 +284,6 @@ func (dg *diffGenerator) generateDiffForBlock(newBlock *model.Block, schema mode
 }
 
 func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, schema model.PropSchema) []PropDiff {
-	var propDiffs []PropDiff
-
 	oldProps, err := model.ParseProperties(oldBlock, schema, dg.store)
 	if err != nil {
 		dg.logger.Error("Cannot parse properties for old block",
@@ -309,56 +300,23 @@ func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, sche
 		)
 	}
 
-	// look for new or changed properties.
-	for k, prop := range newProps {
-		oldP, ok := oldProps[k]
-		if ok {
-			// prop changed
-			if prop.Value != oldP.Value {
-				propDiffs = append(propDiffs, PropDiff{
-					ID:       prop.ID,
-					Index:    prop.Index,
-					Name:     prop.Name,
-					NewValue: prop.Value,
-					OldValue: oldP.Value,
-				})
-			}
-		} else {
-			// prop added
-			propDiffs = append(propDiffs, PropDiff{
-				ID:       prop.ID,
-				Index:    prop.Index,
-				Name:     prop.Name,
-				NewValue: prop.Value,
-				OldValue: "",
-			})
-		}
-	}
-
-	// look for deleted properties
-	for k, prop := range oldProps {
-		_, ok := newProps[k]
-		if !ok {
-			// prop deleted
-			propDiffs = append(propDiffs, PropDiff{
-				ID:       prop.ID,
-				Index:    prop.Index,
-				Name:     prop.Name,
-				NewValue: "",
-				OldValue: prop.Value,
-			})
-		}
-	}
-	return sortPropDiffs(propDiffs)
+	return model.DiffProperties(oldProps, newProps)
 }
 
-func sortPropDiffs(propDiffs []PropDiff) []PropDiff {
-	if len(propDiffs) == 0 {
-		return propDiffs
+// diffsWithoutProperties returns a copy of the diffs without the changes
+// of some card properties.
+func diffsWithoutProperties(diffs []*Diff, ids map[string]bool) []*Diff {
+	filtered := make([]*Diff, 0, len(diffs))
+	for _, d := range diffs {
+		diff := *d
+		diff.PropDiffs = nil
+		for _, propDiff := range d.PropDiffs {
+			if !ids[propDiff.ID] {
+				diff.PropDiffs = append(diff.PropDiffs, propDiff)
+			}
+		}
+		diff.Diffs = diffsWithoutProperties(d.Diffs, ids)
+		filtered = append(filtered, &diff)
 	}
-
-	sort.Slice(propDiffs, func(i, j int) bool {
-		return propDiffs[i].Index < propDiffs[j].Index
-	})
-	return propDiffs
+	return filtered
 }
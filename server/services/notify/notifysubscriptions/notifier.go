@@ -14,6 +14,7 @@ import (
 	"github.com/mattermost/focalboard/server/utils"
 	"github.com/wiggin77/merror"
 
+	mm_model "github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
@@ -215,6 +216,17 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 		return err
 	}
 
+	// the restricted properties are left out of the notifications of the
+	// subscribers that can't view them
+	hiddenProperties := model.RestrictedCardProperties(board)
+	var restrictedAttachments []*mm_model.SlackAttachment
+	if len(hiddenProperties) > 0 {
+		restrictedAttachments, err = Diffs2SlackAttachments(diffsWithoutProperties(diffs, hiddenProperties), opts)
+		if err != nil {
+			return err
+		}
+	}
+
 	merr := merror.New()
 	if len(attachments) > 0 {
 		for _, sub := range subs {
@@ -239,6 +251,23 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 				continue
 			}
 
+			subAttachments := attachments
+			access := permissions.GetCardAccess(n.permissions, board, sub.SubscriberID)
+			if !access.CanViewCard(card) {
+				n.logger.Debug("notifySubscribers - skipping private card",
+					mlog.Any("hint", hint),
+					mlog.String("subscriber_id", sub.SubscriberID),
+					mlog.String("card_id", card.ID),
+				)
+				continue
+			}
+			if len(access.HiddenProperties()) > 0 {
+				subAttachments = restrictedAttachments
+			}
+			if len(subAttachments) == 0 {
+				continue
+			}
+
 			n.logger.Debug("notifySubscribers - deliver",
 				mlog.Any("hint", hint),
 				mlog.String("modified_by_id", hint.ModifiedByID),
@@ -246,7 +275,7 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 				mlog.String("subscriber_type", string(sub.SubscriberType)),
 			)
 
-			if err = n.delivery.SubscriptionDeliverSlackAttachments(board.TeamID, sub.SubscriberID, sub.SubscriberType, attachments); err != nil {
+			if err = n.delivery.SubscriptionDeliverSlackAttachments(board.TeamID, sub.SubscriberID, sub.SubscriberType, subAttachments); err != nil {
 				merr.Append(fmt.Errorf("cannot deliver notification to subscriber %s [%s]: %w",
 					sub.SubscriberID, sub.SubscriberType, err))
 			}
@@ -37,6 +37,16 @@ func SetupTestHelper(t *testing.T) *TestHelper {
 func (th *TestHelper) checkBoardPermissions(roleName string, member *model.BoardMember, hasPermissionTo, hasNotPermissionTo []*mmModel.Permission) {
 	for _, p := range hasPermissionTo {
 		th.t.Run(roleName+" "+p.Id, func(t *testing.T) {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(&model.Board{ID: member.BoardID, TeamID: "team-id"}, nil).
+				Times(1)
+
+			th.store.EXPECT().
+				GetTeamMember("team-id", member.UserID).
+				Return(&model.TeamMember{TeamID: "team-id", UserID: member.UserID}, nil).
+				Times(1)
+
 			th.store.EXPECT().
 				GetMemberForBoard(member.BoardID, member.UserID).
 				Return(member, nil).
@@ -49,6 +59,16 @@ func (th *TestHelper) checkBoardPermissions(roleName string, member *model.Board
 
 	for _, p := range hasNotPermissionTo {
 		th.t.Run(roleName+" "+p.Id, func(t *testing.T) {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(&model.Board{ID: member.BoardID, TeamID: "team-id"}, nil).
+				Times(1)
+
+			th.store.EXPECT().
+				GetTeamMember("team-id", member.UserID).
+				Return(&model.TeamMember{TeamID: "team-id", UserID: member.UserID}, nil).
+				Times(1)
+
 			th.store.EXPECT().
 				GetMemberForBoard(member.BoardID, member.UserID).
 				Return(member, nil).
@@ -23,18 +23,71 @@ func New(store permissions.Store, logger mlog.LoggerIFace) *Service {
 	}
 }
 
+// HasPermissionTo checks the system permissions of a user. The system
+// admins have all the system permissions, the other users have none.
 func (s *Service) HasPermissionTo(userID string, permission *mmModel.Permission) bool {
-	return false
+	if userID == "" || permission == nil {
+		return false
+	}
+
+	user, err := s.store.GetUserByID(userID)
+	if model.IsErrNotFound(err) {
+		return false
+	}
+	if err != nil {
+		s.logger.Error("error getting user",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return false
+	}
+	return user.IsSystemAdmin()
 }
 
+// HasPermissionToTeam checks the team permissions of a user. Only the
+// members of a team can access it, and only its admins can manage it.
+// The teams without members, like the ones of the boards created before
+// the memberships, stay open to every user. The system admins have all
+// the permissions on every team.
 func (s *Service) HasPermissionToTeam(userID, teamID string, permission *mmModel.Permission) bool {
 	if userID == "" || teamID == "" || permission == nil {
 		return false
 	}
-	if permission.Id == model.PermissionManageTeam.Id {
+
+	// in single user mode there are no memberships, and the only user
+	// belongs to every team
+	if userID == model.SingleUser {
+		return permission.Id != model.PermissionManageTeam.Id
+	}
+
+	member, err := s.store.GetTeamMember(teamID, userID)
+	if err != nil && !model.IsErrNotFound(err) {
+		s.logger.Error("error getting member for team",
+			mlog.String("teamID", teamID),
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
 		return false
 	}
-	return true
+
+	if member != nil && (member.SchemeAdmin || permission.Id != model.PermissionManageTeam.Id) {
+		return true
+	}
+
+	if member == nil {
+		count, err := s.store.GetTeamMemberCount(teamID)
+		if err != nil {
+			s.logger.Error("error counting members for team",
+				mlog.String("teamID", teamID),
+				mlog.Err(err),
+			)
+			return false
+		}
+		if count == 0 {
+			return true
+		}
+	}
+	return s.HasPermissionTo(userID, model.PermissionManageSystem)
 }
 
 func (s *Service) HasPermissionToChannel(userID, channelID string, permission *mmModel.Permission) bool {
@@ -49,6 +102,29 @@ func (s *Service) HasPermissionToBoard(userID, boardID string, permission *mmMod
 		return false
 	}
 
+	// deleted boards are not found, but their permissions are still
+	// checked to allow undeleting them
+	board, err := s.store.GetBoard(boardID)
+	if err != nil && !model.IsErrNotFound(err) {
+		s.logger.Error("error getting board",
+			mlog.String("boardID", boardID),
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return false
+	}
+	if board != nil && board.IsArchived() && !permissions.IsAllowedOnArchivedBoard(permission) {
+		return false
+	}
+
+	// we need to check that the user has permission to see the team
+	// regardless of its local permissions to the board, except for the
+	// global templates that every user can see
+	if board != nil && !(board.IsTemplate && board.TeamID == model.GlobalTeamID) &&
+		!s.HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam) {
+		return false
+	}
+
 	member, err := s.store.GetMemberForBoard(boardID, userID)
 	if model.IsErrNotFound(err) {
 		return false
@@ -62,27 +138,5 @@ func (s *Service) HasPermissionToBoard(userID, boardID string, permission *mmMod
 		return false
 	}
 
-	switch member.MinimumRole {
-	case "admin":
-		member.SchemeAdmin = true
-	case "editor":
-		member.SchemeEditor = true
-	case "commenter":
-		member.SchemeCommenter = true
-	case "viewer":
-		member.SchemeViewer = true
-	}
-
-	switch permission {
-	case model.PermissionManageBoardType, model.PermissionDeleteBoard, model.PermissionManageBoardRoles, model.PermissionShareBoard, model.PermissionDeleteOthersComments:
-		return member.SchemeAdmin
-	case model.PermissionManageBoardCards, model.PermissionManageBoardProperties:
-		return member.SchemeAdmin || member.SchemeEditor
-	case model.PermissionCommentBoardCards:
-		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter
-	case model.PermissionViewBoard:
-		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter || member.SchemeViewer
-	default:
-		return false
-	}
+	return permissions.MemberHasPermission(member, permissions.GetCustomRole(s.store, s.logger, member), permission)
 }
@@ -14,6 +14,42 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestHasPermissionTo(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	t.Run("empty input should always unauthorize", func(t *testing.T) {
+		assert.False(t, th.permissions.HasPermissionTo("", model.PermissionManageSystem))
+		assert.False(t, th.permissions.HasPermissionTo("user-id", nil))
+	})
+
+	t.Run("system admin", func(t *testing.T) {
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(&model.User{ID: "user-id", Roles: model.SystemRoles(true)}, nil).
+			Times(1)
+
+		assert.True(t, th.permissions.HasPermissionTo("user-id", model.PermissionManageSystem))
+	})
+
+	t.Run("system user", func(t *testing.T) {
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(&model.User{ID: "user-id", Roles: model.SystemRoles(false)}, nil).
+			Times(1)
+
+		assert.False(t, th.permissions.HasPermissionTo("user-id", model.PermissionManageSystem))
+	})
+
+	t.Run("nonexistent user", func(t *testing.T) {
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(nil, model.NewErrNotFound("user ID=user-id")).
+			Times(1)
+
+		assert.False(t, th.permissions.HasPermissionTo("user-id", model.PermissionManageSystem))
+	})
+}
+
 func TestHasPermissionToTeam(t *testing.T) {
 	th := SetupTestHelper(t)
 
@@ -23,15 +59,85 @@ func TestHasPermissionToTeam(t *testing.T) {
 		assert.False(t, th.permissions.HasPermissionToTeam("user-id", "team-id", nil))
 	})
 
-	t.Run("all users have all permissions on teams", func(t *testing.T) {
+	t.Run("team members have the permissions of the team", func(t *testing.T) {
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(&model.TeamMember{TeamID: "team-id", UserID: "user-id"}, nil).
+			Times(1)
+
 		hasPermission := th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageBoardCards)
 		assert.True(t, hasPermission)
 	})
 
-	t.Run("no users have PermissionManageTeam on teams", func(t *testing.T) {
-		hasPermission := th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageTeam)
+	t.Run("only team admins have PermissionManageTeam on teams", func(t *testing.T) {
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(&model.TeamMember{TeamID: "team-id", UserID: "user-id"}, nil).
+			Times(1)
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(&model.User{ID: "user-id", Roles: model.SystemRoles(false)}, nil).
+			Times(1)
+
+		assert.False(t, th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageTeam))
+
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(&model.TeamMember{TeamID: "team-id", UserID: "user-id", SchemeAdmin: true}, nil).
+			Times(1)
+
+		assert.True(t, th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageTeam))
+	})
+
+	t.Run("users outside of the team have no permissions", func(t *testing.T) {
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(nil, model.NewErrNotFound("team member")).
+			Times(1)
+		th.store.EXPECT().
+			GetTeamMemberCount("team-id").
+			Return(int64(1), nil).
+			Times(1)
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(&model.User{ID: "user-id", Roles: model.SystemRoles(false)}, nil).
+			Times(1)
+
+		hasPermission := th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionViewTeam)
 		assert.False(t, hasPermission)
 	})
+
+	t.Run("teams without members are open to every user", func(t *testing.T) {
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(nil, model.NewErrNotFound("team member")).
+			Times(2)
+		th.store.EXPECT().
+			GetTeamMemberCount("team-id").
+			Return(int64(0), nil).
+			Times(2)
+
+		assert.True(t, th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionViewTeam))
+		assert.True(t, th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageTeam))
+	})
+
+	t.Run("system admins have all permissions on teams", func(t *testing.T) {
+		th.store.EXPECT().
+			GetTeamMember("team-id", "user-id").
+			Return(nil, model.NewErrNotFound("team member")).
+			Times(1)
+		th.store.EXPECT().
+			GetTeamMemberCount("team-id").
+			Return(int64(1), nil).
+			Times(1)
+		th.store.EXPECT().
+			GetUserByID("user-id").
+			Return(&model.User{ID: "user-id", Roles: model.SystemRoles(true)}, nil).
+			Times(1)
+
+		hasPermission := th.permissions.HasPermissionToTeam("user-id", "team-id", model.PermissionManageTeam)
+		assert.True(t, hasPermission)
+	})
 }
 
 func TestHasPermissionToBoard(t *testing.T) {
@@ -47,6 +153,16 @@ func TestHasPermissionToBoard(t *testing.T) {
 		userID := "user-id"
 		boardID := "board-id"
 
+		th.store.EXPECT().
+			GetBoard(boardID).
+			Return(&model.Board{ID: boardID, TeamID: "team-id"}, nil).
+			Times(1)
+
+		th.store.EXPECT().
+			GetTeamMember("team-id", userID).
+			Return(&model.TeamMember{TeamID: "team-id", UserID: userID}, nil).
+			Times(1)
+
 		th.store.EXPECT().
 			GetMemberForBoard(boardID, userID).
 			Return(nil, sql.ErrNoRows).
@@ -56,6 +172,69 @@ func TestHasPermissionToBoard(t *testing.T) {
 		assert.False(t, hasPermission)
 	})
 
+	t.Run("user outside of the team of the board", func(t *testing.T) {
+		userID := "user-id"
+		boardID := "board-id"
+
+		th.store.EXPECT().
+			GetBoard(boardID).
+			Return(&model.Board{ID: boardID, TeamID: "team-id"}, nil).
+			Times(1)
+
+		th.store.EXPECT().
+			GetTeamMember("team-id", userID).
+			Return(nil, model.NewErrNotFound("team member")).
+			Times(1)
+
+		th.store.EXPECT().
+			GetTeamMemberCount("team-id").
+			Return(int64(1), nil).
+			Times(1)
+
+		th.store.EXPECT().
+			GetUserByID(userID).
+			Return(&model.User{ID: userID, Roles: model.SystemRoles(false)}, nil).
+			Times(1)
+
+		hasPermission := th.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("archived board", func(t *testing.T) {
+		member := &model.BoardMember{
+			UserID:      "user-id",
+			BoardID:     "board-id",
+			SchemeAdmin: true,
+		}
+		board := &model.Board{ID: member.BoardID, TeamID: "team-id", ArchiveAt: 1}
+
+		for _, p := range []*mmModel.Permission{model.PermissionViewBoard, model.PermissionArchiveBoard, model.PermissionDeleteBoard} {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(board, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetTeamMember(board.TeamID, member.UserID).
+				Return(&model.TeamMember{TeamID: board.TeamID, UserID: member.UserID}, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetMemberForBoard(member.BoardID, member.UserID).
+				Return(member, nil).
+				Times(1)
+
+			assert.True(t, th.permissions.HasPermissionToBoard(member.UserID, member.BoardID, p), p.Id)
+		}
+
+		for _, p := range []*mmModel.Permission{model.PermissionManageBoardCards, model.PermissionManageBoardProperties, model.PermissionCommentBoardCards, model.PermissionShareBoard} {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(board, nil).
+				Times(1)
+
+			assert.False(t, th.permissions.HasPermissionToBoard(member.UserID, member.BoardID, p), p.Id)
+		}
+	})
+
 	t.Run("board admin", func(t *testing.T) {
 		member := &model.BoardMember{
 			UserID:      "user-id",
@@ -66,11 +245,14 @@ func TestHasPermissionToBoard(t *testing.T) {
 		hasPermissionTo := []*mmModel.Permission{
 			model.PermissionManageBoardType,
 			model.PermissionDeleteBoard,
+			model.PermissionArchiveBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
 			model.PermissionManageBoardCards,
 			model.PermissionViewBoard,
 			model.PermissionManageBoardProperties,
+			model.PermissionViewPrivateCards,
+			model.PermissionViewRestrictedProps,
 		}
 
 		hasNotPermissionTo := []*mmModel.Permission{}
@@ -87,6 +269,7 @@ func TestHasPermissionToBoard(t *testing.T) {
 
 		hasPermissionTo := []*mmModel.Permission{
 			model.PermissionManageBoardCards,
+			model.PermissionMoveBoardCards,
 			model.PermissionViewBoard,
 			model.PermissionManageBoardProperties,
 		}
@@ -96,6 +279,8 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionDeleteBoard,
 			model.PermissionManageBoardRoles,
 			model.PermissionShareBoard,
+			model.PermissionViewPrivateCards,
+			model.PermissionViewRestrictedProps,
 		}
 
 		th.checkBoardPermissions("editor", member, hasPermissionTo, hasNotPermissionTo)
@@ -119,11 +304,93 @@ func TestHasPermissionToBoard(t *testing.T) {
 			model.PermissionShareBoard,
 			model.PermissionManageBoardCards,
 			model.PermissionManageBoardProperties,
+			model.PermissionViewPrivateCards,
+			model.PermissionViewRestrictedProps,
 		}
 
 		th.checkBoardPermissions("commenter", member, hasPermissionTo, hasNotPermissionTo)
 	})
 
+	t.Run("board member with a custom role", func(t *testing.T) {
+		role := &model.CustomBoardRole{
+			ID:     "role-id",
+			TeamID: "team-id",
+			Permissions: []string{
+				model.PermissionViewBoard.Id,
+				model.PermissionCommentBoardCards.Id,
+				model.PermissionMoveBoardCards.Id,
+			},
+		}
+		member := &model.BoardMember{
+			UserID:       "user-id",
+			BoardID:      "board-id",
+			CustomRoleID: role.ID,
+		}
+
+		expected := map[*mmModel.Permission]bool{
+			model.PermissionViewBoard:             true,
+			model.PermissionCommentBoardCards:     true,
+			model.PermissionMoveBoardCards:        true,
+			model.PermissionManageBoardCards:      false,
+			model.PermissionManageBoardProperties: false,
+			model.PermissionShareBoard:            false,
+		}
+		for p, hasPermission := range expected {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(&model.Board{ID: member.BoardID, TeamID: "team-id"}, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetTeamMember("team-id", member.UserID).
+				Return(&model.TeamMember{TeamID: "team-id", UserID: member.UserID}, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetMemberForBoard(member.BoardID, member.UserID).
+				Return(member, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetCustomBoardRole(role.ID).
+				Return(role, nil).
+				Times(1)
+
+			assert.Equal(t, hasPermission, th.permissions.HasPermissionToBoard(member.UserID, member.BoardID, p), p.Id)
+		}
+	})
+
+	t.Run("board member with a deleted custom role keeps its scheme role", func(t *testing.T) {
+		member := &model.BoardMember{
+			UserID:       "user-id",
+			BoardID:      "board-id",
+			SchemeViewer: true,
+			CustomRoleID: "deleted-role-id",
+		}
+
+		expected := map[*mmModel.Permission]bool{
+			model.PermissionViewBoard:        true,
+			model.PermissionManageBoardCards: false,
+		}
+		for p, hasPermission := range expected {
+			th.store.EXPECT().
+				GetBoard(member.BoardID).
+				Return(&model.Board{ID: member.BoardID, TeamID: "team-id"}, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetTeamMember("team-id", member.UserID).
+				Return(&model.TeamMember{TeamID: "team-id", UserID: member.UserID}, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetMemberForBoard(member.BoardID, member.UserID).
+				Return(member, nil).
+				Times(1)
+			th.store.EXPECT().
+				GetCustomBoardRole(member.CustomRoleID).
+				Return(nil, model.NewErrNotFound("custom board role")).
+				Times(1)
+
+			assert.Equal(t, hasPermission, th.permissions.HasPermissionToBoard(member.UserID, member.BoardID, p), p.Id)
+		}
+	})
+
 	t.Run("board viewer", func(t *testing.T) {
 		member := &model.BoardMember{
 			UserID:       "user-id",
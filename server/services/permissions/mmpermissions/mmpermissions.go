@@ -82,6 +82,10 @@ func (s *Service) HasPermissionToBoard(userID, boardID string, permission *mmMod
 	if !s.HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam) {
 		return false
 	}
+
+	if board.IsArchived() && !permissions.IsAllowedOnArchivedBoard(permission) {
+		return false
+	}
 	member, err := s.store.GetMemberForBoard(boardID, userID)
 	if model.IsErrNotFound(err) {
 		return false
@@ -95,34 +99,12 @@ func (s *Service) HasPermissionToBoard(userID, boardID string, permission *mmMod
 		return false
 	}
 
-	switch member.MinimumRole {
-	case "admin":
-		member.SchemeAdmin = true
-	case "editor":
-		member.SchemeEditor = true
-	case "commenter":
-		member.SchemeCommenter = true
-	case "viewer":
-		member.SchemeViewer = true
-	}
-
 	// Admins become member of boards, but get minimal role
 	// if they are a System/Team Admin (model.PermissionManageTeam)
 	// elevate their permissions
-	if !member.SchemeAdmin && s.HasPermissionToTeam(userID, board.TeamID, model.PermissionManageTeam) {
+	if !member.SchemeAdmin && member.MinimumRole != "admin" && s.HasPermissionToTeam(userID, board.TeamID, model.PermissionManageTeam) {
 		return true
 	}
 
-	switch permission {
-	case model.PermissionManageBoardType, model.PermissionDeleteBoard, model.PermissionManageBoardRoles, model.PermissionShareBoard, model.PermissionDeleteOthersComments:
-		return member.SchemeAdmin
-	case model.PermissionManageBoardCards, model.PermissionManageBoardProperties:
-		return member.SchemeAdmin || member.SchemeEditor
-	case model.PermissionCommentBoardCards:
-		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter
-	case model.PermissionViewBoard:
-		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter || member.SchemeViewer
-	default:
-		return false
-	}
+	return permissions.MemberHasPermission(member, permissions.GetCustomRole(s.store, s.logger, member), permission)
 }
@@ -103,6 +103,33 @@ func TestHasPermissionToBoard(t *testing.T) {
 		assert.False(t, hasPermission)
 	})
 
+	t.Run("archived board is read-only", func(t *testing.T) {
+		member := &model.BoardMember{
+			UserID:      userID,
+			BoardID:     boardID,
+			SchemeAdmin: true,
+		}
+		board := &model.Board{ID: boardID, TeamID: teamID, ArchiveAt: 1}
+
+		th.store.EXPECT().
+			GetBoard(boardID).
+			Return(board, nil).
+			Times(2)
+
+		th.api.EXPECT().
+			HasPermissionToTeam(userID, teamID, model.PermissionViewTeam).
+			Return(true).
+			Times(2)
+
+		th.store.EXPECT().
+			GetMemberForBoard(boardID, userID).
+			Return(member, nil).
+			Times(1)
+
+		assert.False(t, th.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards))
+		assert.True(t, th.permissions.HasPermissionToBoard(userID, boardID, model.PermissionArchiveBoard))
+	})
+
 	t.Run("user that has been removed from the team", func(t *testing.T) {
 		member := &model.BoardMember{
 			UserID:      userID,
@@ -240,4 +267,51 @@ func TestHasPermissionToBoard(t *testing.T) {
 		hasNotPermissionTo := []*mmModel.Permission{}
 		th.checkBoardPermissions("elevated-admin", member, teamID, hasPermissionTo, hasNotPermissionTo)
 	})
+
+	t.Run("board member with a custom role", func(t *testing.T) {
+		role := &model.CustomBoardRole{
+			ID:     "role-id",
+			TeamID: teamID,
+			Permissions: []string{
+				model.PermissionViewBoard.Id,
+				model.PermissionManageBoardCards.Id,
+			},
+		}
+		member := &model.BoardMember{
+			UserID:       userID,
+			BoardID:      boardID,
+			CustomRoleID: role.ID,
+		}
+
+		expected := map[*mmModel.Permission]bool{
+			model.PermissionViewBoard:             true,
+			model.PermissionManageBoardCards:      true,
+			model.PermissionManageBoardProperties: false,
+			model.PermissionManageBoardRoles:      false,
+		}
+		for p, hasPermission := range expected {
+			th.store.EXPECT().
+				GetBoard(boardID).
+				Return(&model.Board{ID: boardID, TeamID: teamID}, nil).
+				Times(1)
+			th.api.EXPECT().
+				HasPermissionToTeam(userID, teamID, model.PermissionViewTeam).
+				Return(true).
+				Times(1)
+			th.store.EXPECT().
+				GetMemberForBoard(boardID, userID).
+				Return(member, nil).
+				Times(1)
+			th.api.EXPECT().
+				HasPermissionToTeam(userID, teamID, model.PermissionManageTeam).
+				Return(false).
+				Times(1)
+			th.store.EXPECT().
+				GetCustomBoardRole(role.ID).
+				Return(role, nil).
+				Times(1)
+
+			assert.Equal(t, hasPermission, th.permissions.HasPermissionToBoard(userID, boardID, p), p.Id)
+		}
+	})
 }
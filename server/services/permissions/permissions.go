@@ -8,6 +8,7 @@ import (
 	"github.com/mattermost/focalboard/server/model"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 type PermissionsService interface {
@@ -21,4 +22,84 @@ type Store interface {
 	GetBoard(boardID string) (*model.Board, error)
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
 	GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error)
+	GetUserByID(userID string) (*model.User, error)
+	GetTeamMember(teamID, userID string) (*model.TeamMember, error)
+	GetTeamMemberCount(teamID string) (int64, error)
+	GetCustomBoardRole(roleID string) (*model.CustomBoardRole, error)
+}
+
+// IsAllowedOnArchivedBoard returns true if the permission can be
+// granted on an archived board. Archived boards are read-only, so only
+// viewing, unarchiving and deleting them is allowed.
+func IsAllowedOnArchivedBoard(permission *mmModel.Permission) bool {
+	switch permission {
+	case model.PermissionViewBoard, model.PermissionViewPrivateCards, model.PermissionViewRestrictedProps,
+		model.PermissionArchiveBoard, model.PermissionDeleteBoard:
+		return true
+	default:
+		return false
+	}
+}
+
+// MemberHasPermission resolves a board permission of a member from its
+// scheme roles, the minimum role of the board and its custom role, if
+// any.
+func MemberHasPermission(member *model.BoardMember, customRole *model.CustomBoardRole, permission *mmModel.Permission) bool {
+	switch member.MinimumRole {
+	case "admin":
+		member.SchemeAdmin = true
+	case "editor":
+		member.SchemeEditor = true
+	case "commenter":
+		member.SchemeCommenter = true
+	case "viewer":
+		member.SchemeViewer = true
+	}
+
+	if customRole != nil && customRole.HasPermission(permission) {
+		return true
+	}
+
+	switch permission {
+	case model.PermissionManageBoardType, model.PermissionDeleteBoard, model.PermissionArchiveBoard, model.PermissionManageBoardRoles, model.PermissionShareBoard, model.PermissionDeleteOthersComments,
+		model.PermissionViewPrivateCards, model.PermissionViewRestrictedProps:
+		return member.SchemeAdmin
+	case model.PermissionManageBoardCards, model.PermissionManageBoardProperties, model.PermissionMoveBoardCards:
+		return member.SchemeAdmin || member.SchemeEditor
+	case model.PermissionCommentBoardCards:
+		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter
+	case model.PermissionViewBoard:
+		return member.SchemeAdmin || member.SchemeEditor || member.SchemeCommenter || member.SchemeViewer
+	default:
+		return false
+	}
+}
+
+// GetCustomRole returns the custom board role of a member, or nil if it
+// has none.
+func GetCustomRole(store Store, logger mlog.LoggerIFace, member *model.BoardMember) *model.CustomBoardRole {
+	if member.CustomRoleID == "" {
+		return nil
+	}
+
+	role, err := store.GetCustomBoardRole(member.CustomRoleID)
+	if err != nil {
+		if !model.IsErrNotFound(err) {
+			logger.Error("error getting custom board role",
+				mlog.String("roleID", member.CustomRoleID),
+				mlog.Err(err),
+			)
+		}
+		return nil
+	}
+	return role
+}
+
+// GetCardAccess returns what a user can see of the cards of a board, ie.
+// if it can view its private cards and its restricted properties.
+func GetCardAccess(service PermissionsService, board *model.Board, userID string) *model.CardAccess {
+	return model.NewCardAccess(board, userID,
+		service.HasPermissionToBoard(userID, board.ID, model.PermissionViewPrivateCards),
+		service.HasPermissionToBoard(userID, board.ID, model.PermissionViewRestrictedProps),
+	)
 }
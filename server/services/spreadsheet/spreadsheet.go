@@ -0,0 +1,127 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package spreadsheet reads and writes tables of strings as CSV or Excel
+// (xlsx) files.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is a spreadsheet file format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+
+// ParseFormat returns the format matching the given name or file extension.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "csv", ".csv":
+		return FormatCSV, nil
+	case "xlsx", ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, s)
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// formulaPrefixes are the first characters making spreadsheet
+// applications evaluate a value as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// Write writes the rows to w. The name is used as the sheet name of
+// Excel files. The values that would be evaluated as formulas are
+// escaped with a leading quote.
+func Write(w io.Writer, format Format, name string, rows [][]string) error {
+	rows = escapeFormulas(rows)
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case FormatXLSX:
+		return writeXLSX(w, name, rows)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// Read reads all the rows of a file. For Excel files, only the first
+// sheet is read. At most maxSize bytes are read.
+func Read(r io.Reader, format Format, maxSize int64) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(io.LimitReader(r, maxSize))
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		rows, err := cr.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			// Excel adds a byte order mark to UTF-8 CSV files
+			rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+		}
+		return unescapeFormulas(rows), nil
+	case FormatXLSX:
+		data, err := io.ReadAll(io.LimitReader(r, maxSize))
+		if err != nil {
+			return nil, err
+		}
+		rows, err := readXLSX(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Excel file: %w", err)
+		}
+		return unescapeFormulas(rows), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// escapeFormulas returns a copy of the rows with a quote prepended to the
+// values starting like a formula, so opening an exported file doesn't
+// run the formulas users entered in the cards.
+func escapeFormulas(rows [][]string) [][]string {
+	escaped := make([][]string, len(rows))
+	for i, row := range rows {
+		escaped[i] = make([]string, len(row))
+		for j, value := range row {
+			if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+				value = "'" + value
+			}
+			escaped[i][j] = value
+		}
+	}
+	return escaped
+}
+
+// unescapeFormulas removes the quote escaping the values starting like a
+// formula, so exported files are imported back unchanged.
+func unescapeFormulas(rows [][]string) [][]string {
+	for _, row := range rows {
+		for j, value := range row {
+			if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+				row[j] = value[1:]
+			}
+		}
+	}
+	return rows
+}
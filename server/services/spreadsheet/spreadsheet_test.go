@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"Title", "Status", "Notes"},
+		{"Card 1", "Done", "with <markup> & \"quotes\""},
+		{"Card 2", "", "multi\nline"},
+		{},
+		{"Card 3"},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, Write(buf, format, "My board", rows))
+
+			read, err := Read(buf, format, 1024*1024)
+			require.NoError(t, err)
+
+			expected := rows
+			if format == FormatCSV {
+				// CSV files don't keep empty lines
+				expected = [][]string{rows[0], rows[1], rows[2], rows[4]}
+			}
+			require.Len(t, read, len(expected))
+			for i := range expected {
+				require.Equal(t, strings.Join(expected[i], "|"), strings.Join(read[i], "|"))
+			}
+		})
+	}
+}
+
+func TestReadXLSXSharedStrings(t *testing.T) {
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Title</t></si><si><r><t>Rich </t></r><r><t>text</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1"><v>42</v></c></row>` +
+			`<row r="3"><c r="B3" t="s"><v>1</v></c><c r="C3" t="b"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	rows, err := Read(zipFiles(t, files), FormatXLSX, 1024*1024)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"Title", "", "42"},
+		{},
+		{"", "Rich text", "1"},
+	}, rows)
+}
+
+func TestReadXLSXLimits(t *testing.T) {
+	readSheet := func(sheetData string) error {
+		files := map[string]string{
+			"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+				`<sheets><sheet name="Data" sheetId="1"/></sheets></workbook>`,
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				sheetData + `</sheetData></worksheet>`,
+		}
+		_, err := Read(zipFiles(t, files), FormatXLSX, 1024*1024)
+		return err
+	}
+
+	require.NoError(t, readSheet(`<row r="100000"><c r="ALL100000"><v>1</v></c></row>`))
+	require.ErrorIs(t, readSheet(`<row r="100001"><c r="A100001"><v>1</v></c></row>`), ErrTooManyRows)
+	require.ErrorIs(t, readSheet(`<row r="1"><c r="ALM1"><v>1</v></c></row>`), ErrTooManyColumns)
+	require.Error(t, readSheet(`<row r="1"><c r="AAAAAAAAAAAAAAAAAAAA1"><v>1</v></c></row>`))
+
+	rows := strings.Builder{}
+	for i := 1; i <= 1001; i++ {
+		fmt.Fprintf(&rows, `<row r="%d"><c r="ALL%d"><v>1</v></c></row>`, i, i)
+	}
+	require.ErrorIs(t, readSheet(rows.String()), ErrTooManyCells)
+}
+
+func TestLimitedReader(t *testing.T) {
+	data, err := io.ReadAll(&limitedReader{r: strings.NewReader("12345"), n: 5})
+	require.NoError(t, err)
+	require.Equal(t, "12345", string(data))
+
+	_, err = io.ReadAll(&limitedReader{r: strings.NewReader("123456"), n: 5})
+	require.ErrorIs(t, err, ErrEntryTooLarge)
+}
+
+func TestFormulasEscaping(t *testing.T) {
+	rows := [][]string{
+		{"=1+1", "+1", "-1", "@SUM(A1)", "plain", "'quoted", "a=b"},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, Write(buf, format, "My board", rows))
+			if format == FormatCSV {
+				require.Equal(t, "'=1+1,'+1,'-1,'@SUM(A1),plain,'quoted,a=b\n", buf.String())
+			}
+
+			read, err := Read(buf, format, 1024*1024)
+			require.NoError(t, err)
+			require.Equal(t, rows, read)
+		})
+	}
+}
+
+func zipFiles(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf
+}
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		require.Equal(t, name, columnName(index))
+		parsed, err := columnIndex(name + "12")
+		require.NoError(t, err)
+		require.Equal(t, index, parsed)
+	}
+}
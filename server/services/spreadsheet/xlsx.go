@@ -0,0 +1,357 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// This is a minimal implementation of the Office Open XML spreadsheet
+// format: a single sheet of inline strings is written, and the values of
+// the first sheet are read, ignoring formatting.
+
+const (
+	xlsxMainNS = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelNS  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+	xlsxMaxSheetNameLength = 31
+
+	// the size of the sheets and of the uncompressed files are capped to
+	// read the files with a bounded amount of memory
+	xlsxMaxRows      = 100000
+	xlsxMaxColumns   = 1000
+	xlsxMaxCells     = 1000000
+	xlsxMaxEntrySize = 100 * 1024 * 1024
+)
+
+var (
+	ErrNoSheet        = errors.New("the workbook doesn't contain any sheet")
+	ErrTooManyRows    = fmt.Errorf("the sheet has more than %d rows", xlsxMaxRows)
+	ErrTooManyColumns = fmt.Errorf("the sheet has more than %d columns", xlsxMaxColumns)
+	ErrTooManyCells   = fmt.Errorf("the sheet has more than %d cells", xlsxMaxCells)
+	ErrEntryTooLarge  = fmt.Errorf("a file of the workbook is larger than %d bytes uncompressed", xlsxMaxEntrySize)
+)
+
+var xlsxStaticFiles = map[string]string{
+	"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`,
+	"_rels/.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`,
+}
+
+var xlsxStaticFileOrder = []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels"}
+
+func writeXLSX(w io.Writer, name string, rows [][]string) error {
+	zw := zip.NewWriter(w)
+
+	for _, filename := range xlsxStaticFileOrder {
+		if err := writeZipFile(zw, filename, []byte(xlsxStaticFiles[filename])); err != nil {
+			return err
+		}
+	}
+
+	workbook := &bytes.Buffer{}
+	workbook.WriteString(xml.Header)
+	workbook.WriteString(`<workbook xmlns="` + xlsxMainNS + `" xmlns:r="` + xlsxRelNS + `"><sheets><sheet name="`)
+	if err := xml.EscapeText(workbook, []byte(sheetName(name))); err != nil {
+		return err
+	}
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+	if err := writeZipFile(zw, "xl/workbook.xml", workbook.Bytes()); err != nil {
+		return err
+	}
+
+	sheet := &bytes.Buffer{}
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="` + xlsxMainNS + `"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			if err := xml.EscapeText(sheet, []byte(value)); err != nil {
+				return err
+			}
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	if err := writeZipFile(zw, "xl/worksheets/sheet1.xml", sheet.Bytes()); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeZipFile(zw *zip.Writer, filename string, data []byte) error {
+	f, err := zw.Create(filename)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a string item, either plain or made of rich text runs.
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, run := range t.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err = decodeZipFile(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, ErrNoSheet
+	}
+
+	sheetPath := "xl/worksheets/sheet1.xml"
+	var rels xlsxRelationships
+	if err = decodeZipFile(files, "xl/_rels/workbook.xml.rels", &rels); err == nil {
+		for _, rel := range rels.Relationships {
+			if rel.ID == workbook.Sheets[0].RID {
+				if strings.HasPrefix(rel.Target, "/") {
+					sheetPath = strings.TrimPrefix(rel.Target, "/")
+				} else {
+					sheetPath = path.Join("xl", rel.Target)
+				}
+			}
+		}
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decodeZipFile(files, "xl/sharedStrings.xml", &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxSheet
+	if err = decodeZipFile(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	cells := 0
+	for i, row := range sheet.Rows {
+		rowIndex := row.R - 1
+		if row.R == 0 {
+			rowIndex = i
+		}
+		if rowIndex >= xlsxMaxRows || len(rows) >= xlsxMaxRows {
+			return nil, ErrTooManyRows
+		}
+		// empty rows are omitted from the sheet
+		for len(rows) < rowIndex {
+			rows = append(rows, []string{})
+		}
+
+		values := []string{}
+		for j, cell := range row.Cells {
+			col := j
+			if cell.R != "" {
+				if col, err = columnIndex(cell.R); err != nil {
+					return nil, err
+				}
+			}
+
+			if col >= xlsxMaxColumns {
+				return nil, ErrTooManyColumns
+			}
+
+			var value string
+			switch cell.T {
+			case "s":
+				index, err := strconv.Atoi(cell.V)
+				if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("invalid shared string reference in cell %s", cell.R)
+				}
+				value = sharedStrings.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			default:
+				value = cell.V
+			}
+
+			for len(values) < col {
+				values = append(values, "")
+			}
+			values = append(values, value)
+		}
+
+		if cells += len(values); cells > xlsxMaxCells {
+			return nil, ErrTooManyCells
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func decodeZipFile(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("missing %s", name)
+	}
+	if f.UncompressedSize64 > xlsxMaxEntrySize {
+		return ErrEntryTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	// the uncompressed size of the zip headers can't be trusted
+	return xml.NewDecoder(&limitedReader{r: rc, n: xlsxMaxEntrySize}).Decode(v)
+}
+
+// limitedReader reads at most n bytes, and fails with ErrEntryTooLarge
+// instead of ending the file when there is more to read.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, ErrEntryTooLarge
+		}
+		if err == nil {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// columnName returns the letters of a zero based column index, i.e. A
+// for 0 and AA for 26.
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// columnIndex returns the zero based column index of a cell reference,
+// i.e. 1 for B3.
+func columnIndex(ref string) (int, error) {
+	index := 0
+	letters := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		index = index*26 + int(c-'A'+1)
+		letters++
+	}
+	// the last Excel column is XFD
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid cell reference %s", ref)
+	}
+	return index - 1, nil
+}
+
+// sheetName returns a valid Excel sheet name.
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > xlsxMaxSheetNameLength {
+		name = string(runes[:xlsxMaxSheetNameLength])
+	}
+	return name
+}
@@ -131,6 +131,30 @@ func (s *MattermostAuthLayer) UpdateUserPasswordByID(userID, password string) er
 	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
 }
 
+func (s *MattermostAuthLayer) GetAllUsers(opts model.UserQueryOptions) ([]*model.User, error) {
+	return nil, store.NewNotSupportedError("the users are managed by mattermost")
+}
+
+func (s *MattermostAuthLayer) GetUserByIDIncludingDeactivated(userID string) (*model.User, error) {
+	return nil, store.NewNotSupportedError("the users are managed by mattermost")
+}
+
+func (s *MattermostAuthLayer) UpdateUserRoles(userID, roles string) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) UpdateUserActive(userID string, active bool) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) UpdateUserGuest(userID string, isGuest bool, expireAt int64) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) AnonymizeUser(userID string, anonymousUser *model.User) (*model.UserDeletionReport, error) {
+	return nil, store.NewNotSupportedError("no delete allowed from focalboard, delete it using mattermost")
+}
+
 func (s *MattermostAuthLayer) PatchUserPreferences(userID string, patch model.UserPreferencesPatch) (mmModel.Preferences, error) {
 	preferences, err := s.GetUserPreferences(userID)
 	if err != nil {
@@ -240,6 +264,10 @@ func (s *MattermostAuthLayer) GetSession(token string, expireTime int64) (*model
 	return nil, store.NewNotSupportedError("sessions not used when using mattermost")
 }
 
+func (s *MattermostAuthLayer) GetSessionsForUser(userID string) ([]*model.Session, error) {
+	return nil, store.NewNotSupportedError("sessions not used when using mattermost")
+}
+
 func (s *MattermostAuthLayer) CreateSession(session *model.Session) error {
 	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
 }
@@ -322,6 +350,42 @@ func (s *MattermostAuthLayer) GetTeamsForUser(userID string) ([]*model.Team, err
 	return teams, nil
 }
 
+func (s *MattermostAuthLayer) GetTeamBySignupToken(signupToken string) (*model.Team, error) {
+	return nil, store.NewNotSupportedError("signup tokens not used when using mattermost")
+}
+
+func (s *MattermostAuthLayer) CreateTeam(team *model.Team) error {
+	return store.NewNotSupportedError("no team creation allowed from focalboard, create it using mattermost")
+}
+
+func (s *MattermostAuthLayer) UpdateTeam(team *model.Team) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) DeleteTeam(id string) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) GetTeamMembers(teamID string) ([]*model.TeamMember, error) {
+	return nil, store.NewNotSupportedError("the team members are managed by mattermost")
+}
+
+func (s *MattermostAuthLayer) GetTeamMember(teamID, userID string) (*model.TeamMember, error) {
+	return nil, store.NewNotSupportedError("the team members are managed by mattermost")
+}
+
+func (s *MattermostAuthLayer) GetTeamMemberCount(teamID string) (int64, error) {
+	return 0, store.NewNotSupportedError("the team members are managed by mattermost")
+}
+
+func (s *MattermostAuthLayer) SaveTeamMember(member *model.TeamMember) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
+func (s *MattermostAuthLayer) DeleteTeamMember(teamID, userID string) error {
+	return store.NewNotSupportedError("no update allowed from focalboard, update it using mattermost")
+}
+
 func (s *MattermostAuthLayer) getQueryBuilder() sq.StatementBuilderType {
 	builder := sq.StatementBuilder
 	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
@@ -595,6 +659,92 @@ func (s *MattermostAuthLayer) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 	return nil
 }
 
+func (s *MattermostAuthLayer) DeleteFileInfo(id string) error {
+	now := mmModel.GetMillis()
+	query := s.getQueryBuilder().
+		Update("FileInfo").
+		Set("DeleteAt", now).
+		Set("UpdateAt", now).
+		Where(sq.Eq{"Id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to delete fileinfo", mlog.String("id", id), mlog.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *MattermostAuthLayer) UpdateFileInfoPreviews(fileInfo *mmModel.FileInfo) error {
+	query := s.getQueryBuilder().
+		Update("FileInfo").
+		Set("ThumbnailPath", fileInfo.ThumbnailPath).
+		Set("PreviewPath", fileInfo.PreviewPath).
+		Set("Width", fileInfo.Width).
+		Set("Height", fileInfo.Height).
+		Set("HasPreviewImage", fileInfo.HasPreviewImage).
+		Set("UpdateAt", mmModel.GetMillis()).
+		Where(sq.Eq{"Id": fileInfo.Id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to update fileinfo previews", mlog.String("id", fileInfo.Id), mlog.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *MattermostAuthLayer) GetFileInfosWithoutPreviews(extensions []string, afterID string, limit int) ([]*mmModel.FileInfo, error) {
+	query := s.getQueryBuilder().
+		Select(
+			"Id",
+			"CreateAt",
+			"DeleteAt",
+			"Name",
+			"Extension",
+			"Size",
+			"Archived",
+			"Path",
+		).
+		From("FileInfo").
+		Where(sq.Eq{"CreatorId": "boards"}).
+		Where(sq.Eq{"Extension": extensions}).
+		Where(sq.Gt{"Id": afterID}).
+		Where(sq.Eq{"DeleteAt": 0}).
+		Where(sq.Eq{"Archived": false}).
+		Where(sq.Eq{"HasPreviewImage": false}).
+		OrderBy("Id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to get fileinfos without previews", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	fileInfos := []*mmModel.FileInfo{}
+	for rows.Next() {
+		var fileInfo mmModel.FileInfo
+		err := rows.Scan(
+			&fileInfo.Id,
+			&fileInfo.CreateAt,
+			&fileInfo.DeleteAt,
+			&fileInfo.Name,
+			&fileInfo.Extension,
+			&fileInfo.Size,
+			&fileInfo.Archived,
+			&fileInfo.Path,
+		)
+		if err != nil {
+			return nil, err
+		}
+		fileInfos = append(fileInfos, &fileInfo)
+	}
+
+	return fileInfos, rows.Err()
+}
+
 func (s *MattermostAuthLayer) GetLicense() *mmModel.License {
 	return s.servicesAPI.GetLicense()
 }
@@ -619,6 +769,7 @@ func boardFields(prefix string) []string { //nolint:unparam
 		"create_at",
 		"update_at",
 		"delete_at",
+		"archive_at",
 	}
 
 	if prefix == "" {
@@ -921,6 +1072,7 @@ func (s *MattermostAuthLayer) boardsFromRows(rows *sql.Rows, removeDuplicates bo
 			&board.CreateAt,
 			&board.UpdateAt,
 			&board.DeleteAt,
+			&board.ArchiveAt,
 		)
 		if err != nil {
 			s.logger.Error("boardsFromRows scan error", mlog.Err(err))
@@ -50,6 +50,50 @@ func (mr *MockStoreMockRecorder) AddUpdateCategoryBoard(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUpdateCategoryBoard", reflect.TypeOf((*MockStore)(nil).AddUpdateCategoryBoard), arg0, arg1, arg2)
 }
 
+// AddUserGroupMember mocks base method.
+func (m *MockStore) AddUserGroupMember(arg0, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserGroupMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserGroupMember indicates an expected call of AddUserGroupMember.
+func (mr *MockStoreMockRecorder) AddUserGroupMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserGroupMember", reflect.TypeOf((*MockStore)(nil).AddUserGroupMember), arg0, arg1, arg2)
+}
+
+// AnonymizeUser mocks base method.
+func (m *MockStore) AnonymizeUser(arg0 string, arg1 *model.User) (*model.UserDeletionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUser", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserDeletionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeUser indicates an expected call of AnonymizeUser.
+func (mr *MockStoreMockRecorder) AnonymizeUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUser", reflect.TypeOf((*MockStore)(nil).AnonymizeUser), arg0, arg1)
+}
+
+// ArchiveBoard mocks base method.
+func (m *MockStore) ArchiveBoard(arg0, arg1 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveBoard", arg0, arg1)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveBoard indicates an expected call of ArchiveBoard.
+func (mr *MockStoreMockRecorder) ArchiveBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveBoard", reflect.TypeOf((*MockStore)(nil).ArchiveBoard), arg0, arg1)
+}
+
 // CanSeeUser mocks base method.
 func (m *MockStore) CanSeeUser(arg0, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +109,20 @@ func (mr *MockStoreMockRecorder) CanSeeUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSeeUser", reflect.TypeOf((*MockStore)(nil).CanSeeUser), arg0, arg1)
 }
 
+// ClaimUploadSession mocks base method.
+func (m *MockStore) ClaimUploadSession(arg0 string, arg1, arg2, arg3 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimUploadSession", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimUploadSession indicates an expected call of ClaimUploadSession.
+func (mr *MockStoreMockRecorder) ClaimUploadSession(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimUploadSession", reflect.TypeOf((*MockStore)(nil).ClaimUploadSession), arg0, arg1, arg2, arg3)
+}
+
 // CleanUpSessions mocks base method.
 func (m *MockStore) CleanUpSessions(arg0 int64) error {
 	m.ctrl.T.Helper()
@@ -79,6 +137,34 @@ func (mr *MockStoreMockRecorder) CleanUpSessions(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUpSessions", reflect.TypeOf((*MockStore)(nil).CleanUpSessions), arg0)
 }
 
+// CreateArchiveJob mocks base method.
+func (m *MockStore) CreateArchiveJob(arg0 *model.ArchiveJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateArchiveJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateArchiveJob indicates an expected call of CreateArchiveJob.
+func (mr *MockStoreMockRecorder) CreateArchiveJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArchiveJob", reflect.TypeOf((*MockStore)(nil).CreateArchiveJob), arg0)
+}
+
+// CreateBoardInvitation mocks base method.
+func (m *MockStore) CreateBoardInvitation(arg0 *model.BoardInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBoardInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBoardInvitation indicates an expected call of CreateBoardInvitation.
+func (mr *MockStoreMockRecorder) CreateBoardInvitation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBoardInvitation", reflect.TypeOf((*MockStore)(nil).CreateBoardInvitation), arg0)
+}
+
 // CreateBoardsAndBlocks mocks base method.
 func (m *MockStore) CreateBoardsAndBlocks(arg0 *model.BoardsAndBlocks, arg1 string) (*model.BoardsAndBlocks, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +210,20 @@ func (mr *MockStoreMockRecorder) CreateCategory(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), arg0)
 }
 
+// CreateCustomBoardRole mocks base method.
+func (m *MockStore) CreateCustomBoardRole(arg0 *model.CustomBoardRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomBoardRole", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCustomBoardRole indicates an expected call of CreateCustomBoardRole.
+func (mr *MockStoreMockRecorder) CreateCustomBoardRole(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomBoardRole", reflect.TypeOf((*MockStore)(nil).CreateCustomBoardRole), arg0)
+}
+
 // CreateSession mocks base method.
 func (m *MockStore) CreateSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -138,6 +238,20 @@ func (mr *MockStoreMockRecorder) CreateSession(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0)
 }
 
+// CreateShareLink mocks base method.
+func (m *MockStore) CreateShareLink(arg0 *model.ShareLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShareLink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateShareLink indicates an expected call of CreateShareLink.
+func (mr *MockStoreMockRecorder) CreateShareLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShareLink", reflect.TypeOf((*MockStore)(nil).CreateShareLink), arg0)
+}
+
 // CreateSubscription mocks base method.
 func (m *MockStore) CreateSubscription(arg0 *model.Subscription) (*model.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +267,34 @@ func (mr *MockStoreMockRecorder) CreateSubscription(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockStore)(nil).CreateSubscription), arg0)
 }
 
+// CreateTeam mocks base method.
+func (m *MockStore) CreateTeam(arg0 *model.Team) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTeam", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTeam indicates an expected call of CreateTeam.
+func (mr *MockStoreMockRecorder) CreateTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeam", reflect.TypeOf((*MockStore)(nil).CreateTeam), arg0)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockStore) CreateUploadSession(arg0 *model.UploadSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockStoreMockRecorder) CreateUploadSession(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockStore)(nil).CreateUploadSession), arg0)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -168,6 +310,20 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0)
 }
 
+// CreateUserGroup mocks base method.
+func (m *MockStore) CreateUserGroup(arg0 *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserGroup indicates an expected call of CreateUserGroup.
+func (mr *MockStoreMockRecorder) CreateUserGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserGroup", reflect.TypeOf((*MockStore)(nil).CreateUserGroup), arg0)
+}
+
 // DBType mocks base method.
 func (m *MockStore) DBType() string {
 	m.ctrl.T.Helper()
@@ -196,6 +352,20 @@ func (mr *MockStoreMockRecorder) DBVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBVersion", reflect.TypeOf((*MockStore)(nil).DBVersion))
 }
 
+// DeleteArchiveJob mocks base method.
+func (m *MockStore) DeleteArchiveJob(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArchiveJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArchiveJob indicates an expected call of DeleteArchiveJob.
+func (mr *MockStoreMockRecorder) DeleteArchiveJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArchiveJob", reflect.TypeOf((*MockStore)(nil).DeleteArchiveJob), arg0)
+}
+
 // DeleteBlock mocks base method.
 func (m *MockStore) DeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -238,6 +408,34 @@ func (mr *MockStoreMockRecorder) DeleteBoard(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoard", reflect.TypeOf((*MockStore)(nil).DeleteBoard), arg0, arg1)
 }
 
+// DeleteBoardGroup mocks base method.
+func (m *MockStore) DeleteBoardGroup(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBoardGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBoardGroup indicates an expected call of DeleteBoardGroup.
+func (mr *MockStoreMockRecorder) DeleteBoardGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardGroup", reflect.TypeOf((*MockStore)(nil).DeleteBoardGroup), arg0, arg1)
+}
+
+// DeleteBoardInvitation mocks base method.
+func (m *MockStore) DeleteBoardInvitation(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBoardInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBoardInvitation indicates an expected call of DeleteBoardInvitation.
+func (mr *MockStoreMockRecorder) DeleteBoardInvitation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardInvitation", reflect.TypeOf((*MockStore)(nil).DeleteBoardInvitation), arg0)
+}
+
 // DeleteBoardRecord mocks base method.
 func (m *MockStore) DeleteBoardRecord(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -280,6 +478,77 @@ func (mr *MockStoreMockRecorder) DeleteCategory(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), arg0, arg1, arg2)
 }
 
+// DeleteCustomBoardRole mocks base method.
+func (m *MockStore) DeleteCustomBoardRole(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomBoardRole", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomBoardRole indicates an expected call of DeleteCustomBoardRole.
+func (mr *MockStoreMockRecorder) DeleteCustomBoardRole(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomBoardRole", reflect.TypeOf((*MockStore)(nil).DeleteCustomBoardRole), arg0)
+}
+
+// DeleteExpiredLoginAttempts mocks base method.
+func (m *MockStore) DeleteExpiredLoginAttempts(arg0, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredLoginAttempts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredLoginAttempts indicates an expected call of DeleteExpiredLoginAttempts.
+func (mr *MockStoreMockRecorder) DeleteExpiredLoginAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredLoginAttempts", reflect.TypeOf((*MockStore)(nil).DeleteExpiredLoginAttempts), arg0, arg1)
+}
+
+// DeleteFileInfo mocks base method.
+func (m *MockStore) DeleteFileInfo(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileInfo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileInfo indicates an expected call of DeleteFileInfo.
+func (mr *MockStoreMockRecorder) DeleteFileInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileInfo", reflect.TypeOf((*MockStore)(nil).DeleteFileInfo), arg0)
+}
+
+// DeleteFileUsage mocks base method.
+func (m *MockStore) DeleteFileUsage(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileUsage", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileUsage indicates an expected call of DeleteFileUsage.
+func (mr *MockStoreMockRecorder) DeleteFileUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileUsage", reflect.TypeOf((*MockStore)(nil).DeleteFileUsage), arg0)
+}
+
+// DeleteLoginAttempt mocks base method.
+func (m *MockStore) DeleteLoginAttempt(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginAttempt indicates an expected call of DeleteLoginAttempt.
+func (mr *MockStoreMockRecorder) DeleteLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginAttempt", reflect.TypeOf((*MockStore)(nil).DeleteLoginAttempt), arg0, arg1)
+}
+
 // DeleteMember mocks base method.
 func (m *MockStore) DeleteMember(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -322,6 +591,34 @@ func (mr *MockStoreMockRecorder) DeleteSession(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStore)(nil).DeleteSession), arg0)
 }
 
+// DeleteSessionsForUser mocks base method.
+func (m *MockStore) DeleteSessionsForUser(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionsForUser", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionsForUser indicates an expected call of DeleteSessionsForUser.
+func (mr *MockStoreMockRecorder) DeleteSessionsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionsForUser", reflect.TypeOf((*MockStore)(nil).DeleteSessionsForUser), arg0)
+}
+
+// DeleteShareLink mocks base method.
+func (m *MockStore) DeleteShareLink(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShareLink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShareLink indicates an expected call of DeleteShareLink.
+func (mr *MockStoreMockRecorder) DeleteShareLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShareLink", reflect.TypeOf((*MockStore)(nil).DeleteShareLink), arg0)
+}
+
 // DeleteSubscription mocks base method.
 func (m *MockStore) DeleteSubscription(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -336,6 +633,76 @@ func (mr *MockStoreMockRecorder) DeleteSubscription(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockStore)(nil).DeleteSubscription), arg0, arg1)
 }
 
+// DeleteTeam mocks base method.
+func (m *MockStore) DeleteTeam(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeam", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTeam indicates an expected call of DeleteTeam.
+func (mr *MockStoreMockRecorder) DeleteTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeam", reflect.TypeOf((*MockStore)(nil).DeleteTeam), arg0)
+}
+
+// DeleteTeamMember mocks base method.
+func (m *MockStore) DeleteTeamMember(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeamMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTeamMember indicates an expected call of DeleteTeamMember.
+func (mr *MockStoreMockRecorder) DeleteTeamMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeamMember", reflect.TypeOf((*MockStore)(nil).DeleteTeamMember), arg0, arg1)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockStore) DeleteUploadSession(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockStoreMockRecorder) DeleteUploadSession(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockStore)(nil).DeleteUploadSession), arg0)
+}
+
+// DeleteUserGroup mocks base method.
+func (m *MockStore) DeleteUserGroup(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGroup indicates an expected call of DeleteUserGroup.
+func (mr *MockStoreMockRecorder) DeleteUserGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGroup", reflect.TypeOf((*MockStore)(nil).DeleteUserGroup), arg0)
+}
+
+// DeleteUserGroupMember mocks base method.
+func (m *MockStore) DeleteUserGroupMember(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGroupMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGroupMember indicates an expected call of DeleteUserGroupMember.
+func (mr *MockStoreMockRecorder) DeleteUserGroupMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGroupMember", reflect.TypeOf((*MockStore)(nil).DeleteUserGroupMember), arg0, arg1)
+}
+
 // DuplicateBlock mocks base method.
 func (m *MockStore) DuplicateBlock(arg0, arg1, arg2 string, arg3 bool) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -397,6 +764,51 @@ func (mr *MockStoreMockRecorder) GetAllTeams() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockStore)(nil).GetAllTeams))
 }
 
+// GetAllUsers mocks base method.
+func (m *MockStore) GetAllUsers(arg0 model.UserQueryOptions) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers", arg0)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockStoreMockRecorder) GetAllUsers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockStore)(nil).GetAllUsers), arg0)
+}
+
+// GetArchiveJob mocks base method.
+func (m *MockStore) GetArchiveJob(arg0 string) (*model.ArchiveJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchiveJob", arg0)
+	ret0, _ := ret[0].(*model.ArchiveJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchiveJob indicates an expected call of GetArchiveJob.
+func (mr *MockStoreMockRecorder) GetArchiveJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchiveJob", reflect.TypeOf((*MockStore)(nil).GetArchiveJob), arg0)
+}
+
+// GetArchiveJobs mocks base method.
+func (m *MockStore) GetArchiveJobs(arg0 model.QueryArchiveJobsOptions) ([]*model.ArchiveJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchiveJobs", arg0)
+	ret0, _ := ret[0].([]*model.ArchiveJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchiveJobs indicates an expected call of GetArchiveJobs.
+func (mr *MockStoreMockRecorder) GetArchiveJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchiveJobs", reflect.TypeOf((*MockStore)(nil).GetArchiveJobs), arg0)
+}
+
 // GetBlock mocks base method.
 func (m *MockStore) GetBlock(arg0 string) (*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -534,6 +946,21 @@ func (mr *MockStoreMockRecorder) GetBlocksForBoard(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksForBoard", reflect.TypeOf((*MockStore)(nil).GetBlocksForBoard), arg0)
 }
 
+// GetBlocksForUser mocks base method.
+func (m *MockStore) GetBlocksForUser(arg0 string) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocksForUser", arg0)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocksForUser indicates an expected call of GetBlocksForUser.
+func (mr *MockStoreMockRecorder) GetBlocksForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocksForUser", reflect.TypeOf((*MockStore)(nil).GetBlocksForUser), arg0)
+}
+
 // GetBlocksWithParent mocks base method.
 func (m *MockStore) GetBlocksWithParent(arg0, arg1 string) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -641,26 +1068,131 @@ func (mr *MockStoreMockRecorder) GetBoardCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardCount", reflect.TypeOf((*MockStore)(nil).GetBoardCount))
 }
 
-// GetBoardHistory mocks base method.
-func (m *MockStore) GetBoardHistory(arg0 string, arg1 model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+// GetBoardFileReferences mocks base method.
+func (m *MockStore) GetBoardFileReferences(arg0 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBoardHistory", arg0, arg1)
-	ret0, _ := ret[0].([]*model.Board)
+	ret := m.ctrl.Call(m, "GetBoardFileReferences", arg0)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBoardHistory indicates an expected call of GetBoardHistory.
-func (mr *MockStoreMockRecorder) GetBoardHistory(arg0, arg1 interface{}) *gomock.Call {
+// GetBoardFileReferences indicates an expected call of GetBoardFileReferences.
+func (mr *MockStoreMockRecorder) GetBoardFileReferences(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardHistory", reflect.TypeOf((*MockStore)(nil).GetBoardHistory), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardFileReferences", reflect.TypeOf((*MockStore)(nil).GetBoardFileReferences), arg0)
 }
 
-// GetBoardMemberHistory mocks base method.
-func (m *MockStore) GetBoardMemberHistory(arg0, arg1 string, arg2 uint64) ([]*model.BoardMemberHistoryEntry, error) {
+// GetBoardGroup mocks base method.
+func (m *MockStore) GetBoardGroup(arg0, arg1 string) (*model.BoardGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBoardMemberHistory", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]*model.BoardMemberHistoryEntry)
+	ret := m.ctrl.Call(m, "GetBoardGroup", arg0, arg1)
+	ret0, _ := ret[0].(*model.BoardGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardGroup indicates an expected call of GetBoardGroup.
+func (mr *MockStoreMockRecorder) GetBoardGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardGroup", reflect.TypeOf((*MockStore)(nil).GetBoardGroup), arg0, arg1)
+}
+
+// GetBoardGroupsForBoard mocks base method.
+func (m *MockStore) GetBoardGroupsForBoard(arg0 string) ([]*model.BoardGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardGroupsForBoard", arg0)
+	ret0, _ := ret[0].([]*model.BoardGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardGroupsForBoard indicates an expected call of GetBoardGroupsForBoard.
+func (mr *MockStoreMockRecorder) GetBoardGroupsForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardGroupsForBoard", reflect.TypeOf((*MockStore)(nil).GetBoardGroupsForBoard), arg0)
+}
+
+// GetBoardGroupsForGroup mocks base method.
+func (m *MockStore) GetBoardGroupsForGroup(arg0 string) ([]*model.BoardGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardGroupsForGroup", arg0)
+	ret0, _ := ret[0].([]*model.BoardGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardGroupsForGroup indicates an expected call of GetBoardGroupsForGroup.
+func (mr *MockStoreMockRecorder) GetBoardGroupsForGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardGroupsForGroup", reflect.TypeOf((*MockStore)(nil).GetBoardGroupsForGroup), arg0)
+}
+
+// GetBoardHistory mocks base method.
+func (m *MockStore) GetBoardHistory(arg0 string, arg1 model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardHistory indicates an expected call of GetBoardHistory.
+func (mr *MockStoreMockRecorder) GetBoardHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardHistory", reflect.TypeOf((*MockStore)(nil).GetBoardHistory), arg0, arg1)
+}
+
+// GetBoardInvitationByID mocks base method.
+func (m *MockStore) GetBoardInvitationByID(arg0 string) (*model.BoardInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardInvitationByID", arg0)
+	ret0, _ := ret[0].(*model.BoardInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardInvitationByID indicates an expected call of GetBoardInvitationByID.
+func (mr *MockStoreMockRecorder) GetBoardInvitationByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardInvitationByID", reflect.TypeOf((*MockStore)(nil).GetBoardInvitationByID), arg0)
+}
+
+// GetBoardInvitationByToken mocks base method.
+func (m *MockStore) GetBoardInvitationByToken(arg0 string) (*model.BoardInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardInvitationByToken", arg0)
+	ret0, _ := ret[0].(*model.BoardInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardInvitationByToken indicates an expected call of GetBoardInvitationByToken.
+func (mr *MockStoreMockRecorder) GetBoardInvitationByToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardInvitationByToken", reflect.TypeOf((*MockStore)(nil).GetBoardInvitationByToken), arg0)
+}
+
+// GetBoardInvitationsForBoard mocks base method.
+func (m *MockStore) GetBoardInvitationsForBoard(arg0 string) ([]*model.BoardInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardInvitationsForBoard", arg0)
+	ret0, _ := ret[0].([]*model.BoardInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardInvitationsForBoard indicates an expected call of GetBoardInvitationsForBoard.
+func (mr *MockStoreMockRecorder) GetBoardInvitationsForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardInvitationsForBoard", reflect.TypeOf((*MockStore)(nil).GetBoardInvitationsForBoard), arg0)
+}
+
+// GetBoardMemberHistory mocks base method.
+func (m *MockStore) GetBoardMemberHistory(arg0, arg1 string, arg2 uint64) ([]*model.BoardMemberHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardMemberHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.BoardMemberHistoryEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -671,6 +1203,21 @@ func (mr *MockStoreMockRecorder) GetBoardMemberHistory(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMemberHistory", reflect.TypeOf((*MockStore)(nil).GetBoardMemberHistory), arg0, arg1, arg2)
 }
 
+// GetBoardMemberHistoryForUser mocks base method.
+func (m *MockStore) GetBoardMemberHistoryForUser(arg0 string) ([]*model.BoardMemberHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardMemberHistoryForUser", arg0)
+	ret0, _ := ret[0].([]*model.BoardMemberHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardMemberHistoryForUser indicates an expected call of GetBoardMemberHistoryForUser.
+func (mr *MockStoreMockRecorder) GetBoardMemberHistoryForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMemberHistoryForUser", reflect.TypeOf((*MockStore)(nil).GetBoardMemberHistoryForUser), arg0)
+}
+
 // GetBoardsComplianceHistory mocks base method.
 func (m *MockStore) GetBoardsComplianceHistory(arg0 model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	m.ctrl.T.Helper()
@@ -778,6 +1325,96 @@ func (mr *MockStoreMockRecorder) GetChannel(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockStore)(nil).GetChannel), arg0, arg1)
 }
 
+// GetCustomBoardRole mocks base method.
+func (m *MockStore) GetCustomBoardRole(arg0 string) (*model.CustomBoardRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomBoardRole", arg0)
+	ret0, _ := ret[0].(*model.CustomBoardRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomBoardRole indicates an expected call of GetCustomBoardRole.
+func (mr *MockStoreMockRecorder) GetCustomBoardRole(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomBoardRole", reflect.TypeOf((*MockStore)(nil).GetCustomBoardRole), arg0)
+}
+
+// GetCustomBoardRolesForTeam mocks base method.
+func (m *MockStore) GetCustomBoardRolesForTeam(arg0 string) ([]*model.CustomBoardRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomBoardRolesForTeam", arg0)
+	ret0, _ := ret[0].([]*model.CustomBoardRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomBoardRolesForTeam indicates an expected call of GetCustomBoardRolesForTeam.
+func (mr *MockStoreMockRecorder) GetCustomBoardRolesForTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomBoardRolesForTeam", reflect.TypeOf((*MockStore)(nil).GetCustomBoardRolesForTeam), arg0)
+}
+
+// GetDeletedBlocks mocks base method.
+func (m *MockStore) GetDeletedBlocks(arg0 model.QueryTrashOptions) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBlocks", arg0)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBlocks indicates an expected call of GetDeletedBlocks.
+func (mr *MockStoreMockRecorder) GetDeletedBlocks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBlocks", reflect.TypeOf((*MockStore)(nil).GetDeletedBlocks), arg0)
+}
+
+// GetDeletedBoards mocks base method.
+func (m *MockStore) GetDeletedBoards(arg0 model.QueryTrashOptions) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBoards", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBoards indicates an expected call of GetDeletedBoards.
+func (mr *MockStoreMockRecorder) GetDeletedBoards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBoards", reflect.TypeOf((*MockStore)(nil).GetDeletedBoards), arg0)
+}
+
+// GetExpiredBoardInvitations mocks base method.
+func (m *MockStore) GetExpiredBoardInvitations() ([]*model.BoardInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredBoardInvitations")
+	ret0, _ := ret[0].([]*model.BoardInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredBoardInvitations indicates an expected call of GetExpiredBoardInvitations.
+func (mr *MockStoreMockRecorder) GetExpiredBoardInvitations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredBoardInvitations", reflect.TypeOf((*MockStore)(nil).GetExpiredBoardInvitations))
+}
+
+// GetFileBlocks mocks base method.
+func (m *MockStore) GetFileBlocks(arg0 string, arg1 int) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileBlocks indicates an expected call of GetFileBlocks.
+func (mr *MockStoreMockRecorder) GetFileBlocks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileBlocks", reflect.TypeOf((*MockStore)(nil).GetFileBlocks), arg0, arg1)
+}
+
 // GetFileInfo mocks base method.
 func (m *MockStore) GetFileInfo(arg0 string) (*model0.FileInfo, error) {
 	m.ctrl.T.Helper()
@@ -793,6 +1430,66 @@ func (mr *MockStoreMockRecorder) GetFileInfo(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockStore)(nil).GetFileInfo), arg0)
 }
 
+// GetFileInfosWithoutPreviews mocks base method.
+func (m *MockStore) GetFileInfosWithoutPreviews(arg0 []string, arg1 string, arg2 int) ([]*model0.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileInfosWithoutPreviews", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model0.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileInfosWithoutPreviews indicates an expected call of GetFileInfosWithoutPreviews.
+func (mr *MockStoreMockRecorder) GetFileInfosWithoutPreviews(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfosWithoutPreviews", reflect.TypeOf((*MockStore)(nil).GetFileInfosWithoutPreviews), arg0, arg1, arg2)
+}
+
+// GetFileUsage mocks base method.
+func (m *MockStore) GetFileUsage(arg0 string) ([]*model.TeamFileUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUsage", arg0)
+	ret0, _ := ret[0].([]*model.TeamFileUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileUsage indicates an expected call of GetFileUsage.
+func (mr *MockStoreMockRecorder) GetFileUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUsage", reflect.TypeOf((*MockStore)(nil).GetFileUsage), arg0)
+}
+
+// GetFileUsageByFileID mocks base method.
+func (m *MockStore) GetFileUsageByFileID(arg0 string) (*model.FileUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUsageByFileID", arg0)
+	ret0, _ := ret[0].(*model.FileUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileUsageByFileID indicates an expected call of GetFileUsageByFileID.
+func (mr *MockStoreMockRecorder) GetFileUsageByFileID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUsageByFileID", reflect.TypeOf((*MockStore)(nil).GetFileUsageByFileID), arg0)
+}
+
+// GetFileUsagesCreatedBefore mocks base method.
+func (m *MockStore) GetFileUsagesCreatedBefore(arg0 int64, arg1 string, arg2 int) ([]*model.FileUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileUsagesCreatedBefore", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.FileUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileUsagesCreatedBefore indicates an expected call of GetFileUsagesCreatedBefore.
+func (mr *MockStoreMockRecorder) GetFileUsagesCreatedBefore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileUsagesCreatedBefore", reflect.TypeOf((*MockStore)(nil).GetFileUsagesCreatedBefore), arg0, arg1, arg2)
+}
+
 // GetLicense mocks base method.
 func (m *MockStore) GetLicense() *model0.License {
 	m.ctrl.T.Helper()
@@ -807,6 +1504,36 @@ func (mr *MockStoreMockRecorder) GetLicense() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicense", reflect.TypeOf((*MockStore)(nil).GetLicense))
 }
 
+// GetLoginAttempt mocks base method.
+func (m *MockStore) GetLoginAttempt(arg0, arg1 string) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAttempt indicates an expected call of GetLoginAttempt.
+func (mr *MockStoreMockRecorder) GetLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAttempt", reflect.TypeOf((*MockStore)(nil).GetLoginAttempt), arg0, arg1)
+}
+
+// GetLoginAttemptByUnlockToken mocks base method.
+func (m *MockStore) GetLoginAttemptByUnlockToken(arg0 string) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAttemptByUnlockToken", arg0)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAttemptByUnlockToken indicates an expected call of GetLoginAttemptByUnlockToken.
+func (mr *MockStoreMockRecorder) GetLoginAttemptByUnlockToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAttemptByUnlockToken", reflect.TypeOf((*MockStore)(nil).GetLoginAttemptByUnlockToken), arg0)
+}
+
 // GetMemberForBoard mocks base method.
 func (m *MockStore) GetMemberForBoard(arg0, arg1 string) (*model.BoardMember, error) {
 	m.ctrl.T.Helper()
@@ -912,6 +1639,66 @@ func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
 }
 
+// GetSessionsForUser mocks base method.
+func (m *MockStore) GetSessionsForUser(arg0 string) ([]*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionsForUser", arg0)
+	ret0, _ := ret[0].([]*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionsForUser indicates an expected call of GetSessionsForUser.
+func (mr *MockStoreMockRecorder) GetSessionsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionsForUser", reflect.TypeOf((*MockStore)(nil).GetSessionsForUser), arg0)
+}
+
+// GetShareLink mocks base method.
+func (m *MockStore) GetShareLink(arg0 string) (*model.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLink", arg0)
+	ret0, _ := ret[0].(*model.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLink indicates an expected call of GetShareLink.
+func (mr *MockStoreMockRecorder) GetShareLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLink", reflect.TypeOf((*MockStore)(nil).GetShareLink), arg0)
+}
+
+// GetShareLinkByToken mocks base method.
+func (m *MockStore) GetShareLinkByToken(arg0 string) (*model.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLinkByToken", arg0)
+	ret0, _ := ret[0].(*model.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLinkByToken indicates an expected call of GetShareLinkByToken.
+func (mr *MockStoreMockRecorder) GetShareLinkByToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLinkByToken", reflect.TypeOf((*MockStore)(nil).GetShareLinkByToken), arg0)
+}
+
+// GetShareLinksForBoard mocks base method.
+func (m *MockStore) GetShareLinksForBoard(arg0 string) ([]*model.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShareLinksForBoard", arg0)
+	ret0, _ := ret[0].([]*model.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShareLinksForBoard indicates an expected call of GetShareLinksForBoard.
+func (mr *MockStoreMockRecorder) GetShareLinksForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShareLinksForBoard", reflect.TypeOf((*MockStore)(nil).GetShareLinksForBoard), arg0)
+}
+
 // GetSharing mocks base method.
 func (m *MockStore) GetSharing(arg0 string) (*model.Sharing, error) {
 	m.ctrl.T.Helper()
@@ -1047,6 +1834,36 @@ func (mr *MockStoreMockRecorder) GetTeam(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockStore)(nil).GetTeam), arg0)
 }
 
+// GetTeamBoardCount mocks base method.
+func (m *MockStore) GetTeamBoardCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamBoardCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamBoardCount indicates an expected call of GetTeamBoardCount.
+func (mr *MockStoreMockRecorder) GetTeamBoardCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamBoardCount", reflect.TypeOf((*MockStore)(nil).GetTeamBoardCount), arg0)
+}
+
+// GetTeamBySignupToken mocks base method.
+func (m *MockStore) GetTeamBySignupToken(arg0 string) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamBySignupToken", arg0)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamBySignupToken indicates an expected call of GetTeamBySignupToken.
+func (mr *MockStoreMockRecorder) GetTeamBySignupToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamBySignupToken", reflect.TypeOf((*MockStore)(nil).GetTeamBySignupToken), arg0)
+}
+
 // GetTeamCount mocks base method.
 func (m *MockStore) GetTeamCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -1062,6 +1879,66 @@ func (mr *MockStoreMockRecorder) GetTeamCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamCount", reflect.TypeOf((*MockStore)(nil).GetTeamCount))
 }
 
+// GetTeamFileUsageSize mocks base method.
+func (m *MockStore) GetTeamFileUsageSize(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamFileUsageSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamFileUsageSize indicates an expected call of GetTeamFileUsageSize.
+func (mr *MockStoreMockRecorder) GetTeamFileUsageSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamFileUsageSize", reflect.TypeOf((*MockStore)(nil).GetTeamFileUsageSize), arg0)
+}
+
+// GetTeamMember mocks base method.
+func (m *MockStore) GetTeamMember(arg0, arg1 string) (*model.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMember", arg0, arg1)
+	ret0, _ := ret[0].(*model.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamMember indicates an expected call of GetTeamMember.
+func (mr *MockStoreMockRecorder) GetTeamMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMember", reflect.TypeOf((*MockStore)(nil).GetTeamMember), arg0, arg1)
+}
+
+// GetTeamMemberCount mocks base method.
+func (m *MockStore) GetTeamMemberCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMemberCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamMemberCount indicates an expected call of GetTeamMemberCount.
+func (mr *MockStoreMockRecorder) GetTeamMemberCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMemberCount", reflect.TypeOf((*MockStore)(nil).GetTeamMemberCount), arg0)
+}
+
+// GetTeamMembers mocks base method.
+func (m *MockStore) GetTeamMembers(arg0 string) ([]*model.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMembers", arg0)
+	ret0, _ := ret[0].([]*model.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamMembers indicates an expected call of GetTeamMembers.
+func (mr *MockStoreMockRecorder) GetTeamMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMembers", reflect.TypeOf((*MockStore)(nil).GetTeamMembers), arg0)
+}
+
 // GetTeamsForUser mocks base method.
 func (m *MockStore) GetTeamsForUser(arg0 string) ([]*model.Team, error) {
 	m.ctrl.T.Helper()
@@ -1071,25 +1948,55 @@ func (m *MockStore) GetTeamsForUser(arg0 string) ([]*model.Team, error) {
 	return ret0, ret1
 }
 
-// GetTeamsForUser indicates an expected call of GetTeamsForUser.
-func (mr *MockStoreMockRecorder) GetTeamsForUser(arg0 interface{}) *gomock.Call {
+// GetTeamsForUser indicates an expected call of GetTeamsForUser.
+func (mr *MockStoreMockRecorder) GetTeamsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForUser", reflect.TypeOf((*MockStore)(nil).GetTeamsForUser), arg0)
+}
+
+// GetTemplateBoards mocks base method.
+func (m *MockStore) GetTemplateBoards(arg0, arg1 string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBoards", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBoards indicates an expected call of GetTemplateBoards.
+func (mr *MockStoreMockRecorder) GetTemplateBoards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateBoards), arg0, arg1)
+}
+
+// GetUploadSession mocks base method.
+func (m *MockStore) GetUploadSession(arg0 string) (*model.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", arg0)
+	ret0, _ := ret[0].(*model.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockStoreMockRecorder) GetUploadSession(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForUser", reflect.TypeOf((*MockStore)(nil).GetTeamsForUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockStore)(nil).GetUploadSession), arg0)
 }
 
-// GetTemplateBoards mocks base method.
-func (m *MockStore) GetTemplateBoards(arg0, arg1 string) ([]*model.Board, error) {
+// GetUploadSessionsUpdatedBefore mocks base method.
+func (m *MockStore) GetUploadSessionsUpdatedBefore(arg0 int64, arg1 int) ([]*model.UploadSession, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTemplateBoards", arg0, arg1)
-	ret0, _ := ret[0].([]*model.Board)
+	ret := m.ctrl.Call(m, "GetUploadSessionsUpdatedBefore", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UploadSession)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTemplateBoards indicates an expected call of GetTemplateBoards.
-func (mr *MockStoreMockRecorder) GetTemplateBoards(arg0, arg1 interface{}) *gomock.Call {
+// GetUploadSessionsUpdatedBefore indicates an expected call of GetUploadSessionsUpdatedBefore.
+func (mr *MockStoreMockRecorder) GetUploadSessionsUpdatedBefore(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBoards", reflect.TypeOf((*MockStore)(nil).GetTemplateBoards), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSessionsUpdatedBefore", reflect.TypeOf((*MockStore)(nil).GetUploadSessionsUpdatedBefore), arg0, arg1)
 }
 
 // GetUsedCardsCount mocks base method.
@@ -1137,6 +2044,21 @@ func (mr *MockStoreMockRecorder) GetUserByID(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0)
 }
 
+// GetUserByIDIncludingDeactivated mocks base method.
+func (m *MockStore) GetUserByIDIncludingDeactivated(arg0 string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByIDIncludingDeactivated", arg0)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByIDIncludingDeactivated indicates an expected call of GetUserByIDIncludingDeactivated.
+func (mr *MockStoreMockRecorder) GetUserByIDIncludingDeactivated(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIDIncludingDeactivated", reflect.TypeOf((*MockStore)(nil).GetUserByIDIncludingDeactivated), arg0)
+}
+
 // GetUserByUsername mocks base method.
 func (m *MockStore) GetUserByUsername(arg0 string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -1182,6 +2104,66 @@ func (mr *MockStoreMockRecorder) GetUserCategoryBoards(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCategoryBoards", reflect.TypeOf((*MockStore)(nil).GetUserCategoryBoards), arg0, arg1)
 }
 
+// GetUserGroup mocks base method.
+func (m *MockStore) GetUserGroup(arg0 string) (*model.UserGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroup", arg0)
+	ret0, _ := ret[0].(*model.UserGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroup indicates an expected call of GetUserGroup.
+func (mr *MockStoreMockRecorder) GetUserGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroup", reflect.TypeOf((*MockStore)(nil).GetUserGroup), arg0)
+}
+
+// GetUserGroupByName mocks base method.
+func (m *MockStore) GetUserGroupByName(arg0, arg1 string) (*model.UserGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroupByName", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroupByName indicates an expected call of GetUserGroupByName.
+func (mr *MockStoreMockRecorder) GetUserGroupByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroupByName", reflect.TypeOf((*MockStore)(nil).GetUserGroupByName), arg0, arg1)
+}
+
+// GetUserGroupMemberIDs mocks base method.
+func (m *MockStore) GetUserGroupMemberIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroupMemberIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroupMemberIDs indicates an expected call of GetUserGroupMemberIDs.
+func (mr *MockStoreMockRecorder) GetUserGroupMemberIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroupMemberIDs", reflect.TypeOf((*MockStore)(nil).GetUserGroupMemberIDs), arg0)
+}
+
+// GetUserGroupsForTeam mocks base method.
+func (m *MockStore) GetUserGroupsForTeam(arg0 string) ([]*model.UserGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroupsForTeam", arg0)
+	ret0, _ := ret[0].([]*model.UserGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroupsForTeam indicates an expected call of GetUserGroupsForTeam.
+func (mr *MockStoreMockRecorder) GetUserGroupsForTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroupsForTeam", reflect.TypeOf((*MockStore)(nil).GetUserGroupsForTeam), arg0)
+}
+
 // GetUserPreferences mocks base method.
 func (m *MockStore) GetUserPreferences(arg0 string) (model0.Preferences, error) {
 	m.ctrl.T.Helper()
@@ -1242,6 +2224,50 @@ func (mr *MockStoreMockRecorder) GetUsersList(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersList", reflect.TypeOf((*MockStore)(nil).GetUsersList), arg0, arg1, arg2)
 }
 
+// ImportBoardCards mocks base method.
+func (m *MockStore) ImportBoardCards(arg0 string, arg1 *model.BoardPatch, arg2 []*model.Block, arg3 *model.BlockPatchBatch, arg4 string) (*model.BoardsAndBlocks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBoardCards", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.BoardsAndBlocks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBoardCards indicates an expected call of ImportBoardCards.
+func (mr *MockStoreMockRecorder) ImportBoardCards(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBoardCards", reflect.TypeOf((*MockStore)(nil).ImportBoardCards), arg0, arg1, arg2, arg3, arg4)
+}
+
+// IncrementLoginAttempt mocks base method.
+func (m *MockStore) IncrementLoginAttempt(arg0, arg1 string, arg2, arg3 int64) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLoginAttempt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementLoginAttempt indicates an expected call of IncrementLoginAttempt.
+func (mr *MockStoreMockRecorder) IncrementLoginAttempt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLoginAttempt", reflect.TypeOf((*MockStore)(nil).IncrementLoginAttempt), arg0, arg1, arg2, arg3)
+}
+
+// IncrementShareLinkViews mocks base method.
+func (m *MockStore) IncrementShareLinkViews(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementShareLinkViews", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementShareLinkViews indicates an expected call of IncrementShareLinkViews.
+func (mr *MockStoreMockRecorder) IncrementShareLinkViews(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementShareLinkViews", reflect.TypeOf((*MockStore)(nil).IncrementShareLinkViews), arg0)
+}
+
 // InsertBlock mocks base method.
 func (m *MockStore) InsertBlock(arg0 *model.Block, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1301,6 +2327,20 @@ func (mr *MockStoreMockRecorder) InsertBoardWithAdmin(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWithAdmin", reflect.TypeOf((*MockStore)(nil).InsertBoardWithAdmin), arg0, arg1)
 }
 
+// LockLoginAttempt mocks base method.
+func (m *MockStore) LockLoginAttempt(arg0, arg1 string, arg2 int64, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLoginAttempt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLoginAttempt indicates an expected call of LockLoginAttempt.
+func (mr *MockStoreMockRecorder) LockLoginAttempt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLoginAttempt", reflect.TypeOf((*MockStore)(nil).LockLoginAttempt), arg0, arg1, arg2, arg3)
+}
+
 // PatchBlock mocks base method.
 func (m *MockStore) PatchBlock(arg0 string, arg1 *model.BlockPatch, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -1388,6 +2428,49 @@ func (mr *MockStoreMockRecorder) PostMessage(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockStore)(nil).PostMessage), arg0, arg1, arg2)
 }
 
+// PurgeDeletedBlock mocks base method.
+func (m *MockStore) PurgeDeletedBlock(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletedBlock indicates an expected call of PurgeDeletedBlock.
+func (mr *MockStoreMockRecorder) PurgeDeletedBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBlock", reflect.TypeOf((*MockStore)(nil).PurgeDeletedBlock), arg0)
+}
+
+// PurgeDeletedBoard mocks base method.
+func (m *MockStore) PurgeDeletedBoard(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBoard", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletedBoard indicates an expected call of PurgeDeletedBoard.
+func (mr *MockStoreMockRecorder) PurgeDeletedBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBoard", reflect.TypeOf((*MockStore)(nil).PurgeDeletedBoard), arg0)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockStore) PurgeTrash(arg0, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockStoreMockRecorder) PurgeTrash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockStore)(nil).PurgeTrash), arg0, arg1)
+}
+
 // RefreshSession mocks base method.
 func (m *MockStore) RefreshSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1446,6 +2529,36 @@ func (mr *MockStoreMockRecorder) ReorderCategoryBoards(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategoryBoards", reflect.TypeOf((*MockStore)(nil).ReorderCategoryBoards), arg0, arg1)
 }
 
+// RestoreBlockHistory mocks base method.
+func (m *MockStore) RestoreBlockHistory(arg0 string, arg1 int64, arg2 bool, arg3 string) (*model.HistoryRestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBlockHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.HistoryRestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBlockHistory indicates an expected call of RestoreBlockHistory.
+func (mr *MockStoreMockRecorder) RestoreBlockHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBlockHistory", reflect.TypeOf((*MockStore)(nil).RestoreBlockHistory), arg0, arg1, arg2, arg3)
+}
+
+// RestoreBoardHistory mocks base method.
+func (m *MockStore) RestoreBoardHistory(arg0 string, arg1 int64, arg2 string) (*model.HistoryRestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBoardHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.HistoryRestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBoardHistory indicates an expected call of RestoreBoardHistory.
+func (mr *MockStoreMockRecorder) RestoreBoardHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBoardHistory", reflect.TypeOf((*MockStore)(nil).RestoreBoardHistory), arg0, arg1, arg2)
+}
+
 // RunDataRetention mocks base method.
 func (m *MockStore) RunDataRetention(arg0, arg1 int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1461,6 +2574,20 @@ func (mr *MockStoreMockRecorder) RunDataRetention(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDataRetention", reflect.TypeOf((*MockStore)(nil).RunDataRetention), arg0, arg1)
 }
 
+// SaveBoardGroup mocks base method.
+func (m *MockStore) SaveBoardGroup(arg0 *model.BoardGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBoardGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBoardGroup indicates an expected call of SaveBoardGroup.
+func (mr *MockStoreMockRecorder) SaveBoardGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBoardGroup", reflect.TypeOf((*MockStore)(nil).SaveBoardGroup), arg0)
+}
+
 // SaveFileInfo mocks base method.
 func (m *MockStore) SaveFileInfo(arg0 *model0.FileInfo) error {
 	m.ctrl.T.Helper()
@@ -1475,6 +2602,20 @@ func (mr *MockStoreMockRecorder) SaveFileInfo(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFileInfo", reflect.TypeOf((*MockStore)(nil).SaveFileInfo), arg0)
 }
 
+// SaveFileUsage mocks base method.
+func (m *MockStore) SaveFileUsage(arg0 *model.FileUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFileUsage", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFileUsage indicates an expected call of SaveFileUsage.
+func (mr *MockStoreMockRecorder) SaveFileUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFileUsage", reflect.TypeOf((*MockStore)(nil).SaveFileUsage), arg0)
+}
+
 // SaveMember mocks base method.
 func (m *MockStore) SaveMember(arg0 *model.BoardMember) (*model.BoardMember, error) {
 	m.ctrl.T.Helper()
@@ -1490,6 +2631,20 @@ func (mr *MockStoreMockRecorder) SaveMember(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMember", reflect.TypeOf((*MockStore)(nil).SaveMember), arg0)
 }
 
+// SaveTeamMember mocks base method.
+func (m *MockStore) SaveTeamMember(arg0 *model.TeamMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTeamMember", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTeamMember indicates an expected call of SaveTeamMember.
+func (mr *MockStoreMockRecorder) SaveTeamMember(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTeamMember", reflect.TypeOf((*MockStore)(nil).SaveTeamMember), arg0)
+}
+
 // SearchBoardsForUser mocks base method.
 func (m *MockStore) SearchBoardsForUser(arg0 string, arg1 model.BoardSearchField, arg2 string, arg3 bool) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -1606,6 +2761,21 @@ func (mr *MockStoreMockRecorder) Shutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStore)(nil).Shutdown))
 }
 
+// UnarchiveBoard mocks base method.
+func (m *MockStore) UnarchiveBoard(arg0, arg1 string) (*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnarchiveBoard", arg0, arg1)
+	ret0, _ := ret[0].(*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveBoard indicates an expected call of UnarchiveBoard.
+func (mr *MockStoreMockRecorder) UnarchiveBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveBoard", reflect.TypeOf((*MockStore)(nil).UnarchiveBoard), arg0, arg1)
+}
+
 // UndeleteBlock mocks base method.
 func (m *MockStore) UndeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1634,6 +2804,48 @@ func (mr *MockStoreMockRecorder) UndeleteBoard(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteBoard", reflect.TypeOf((*MockStore)(nil).UndeleteBoard), arg0, arg1)
 }
 
+// UpdateArchiveJob mocks base method.
+func (m *MockStore) UpdateArchiveJob(arg0 *model.ArchiveJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateArchiveJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateArchiveJob indicates an expected call of UpdateArchiveJob.
+func (mr *MockStoreMockRecorder) UpdateArchiveJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateArchiveJob", reflect.TypeOf((*MockStore)(nil).UpdateArchiveJob), arg0)
+}
+
+// UpdateBlockTitle mocks base method.
+func (m *MockStore) UpdateBlockTitle(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBlockTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBlockTitle indicates an expected call of UpdateBlockTitle.
+func (mr *MockStoreMockRecorder) UpdateBlockTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBlockTitle", reflect.TypeOf((*MockStore)(nil).UpdateBlockTitle), arg0, arg1, arg2)
+}
+
+// UpdateBoardInvitation mocks base method.
+func (m *MockStore) UpdateBoardInvitation(arg0 *model.BoardInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBoardInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBoardInvitation indicates an expected call of UpdateBoardInvitation.
+func (mr *MockStoreMockRecorder) UpdateBoardInvitation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBoardInvitation", reflect.TypeOf((*MockStore)(nil).UpdateBoardInvitation), arg0)
+}
+
 // UpdateCardLimitTimestamp mocks base method.
 func (m *MockStore) UpdateCardLimitTimestamp(arg0 int) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1663,6 +2875,34 @@ func (mr *MockStoreMockRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockStore)(nil).UpdateCategory), arg0)
 }
 
+// UpdateCustomBoardRole mocks base method.
+func (m *MockStore) UpdateCustomBoardRole(arg0 *model.CustomBoardRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomBoardRole", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCustomBoardRole indicates an expected call of UpdateCustomBoardRole.
+func (mr *MockStoreMockRecorder) UpdateCustomBoardRole(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomBoardRole", reflect.TypeOf((*MockStore)(nil).UpdateCustomBoardRole), arg0)
+}
+
+// UpdateFileInfoPreviews mocks base method.
+func (m *MockStore) UpdateFileInfoPreviews(arg0 *model0.FileInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileInfoPreviews", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileInfoPreviews indicates an expected call of UpdateFileInfoPreviews.
+func (mr *MockStoreMockRecorder) UpdateFileInfoPreviews(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileInfoPreviews", reflect.TypeOf((*MockStore)(nil).UpdateFileInfoPreviews), arg0)
+}
+
 // UpdateSession mocks base method.
 func (m *MockStore) UpdateSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1677,6 +2917,20 @@ func (mr *MockStoreMockRecorder) UpdateSession(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSession", reflect.TypeOf((*MockStore)(nil).UpdateSession), arg0)
 }
 
+// UpdateShareLink mocks base method.
+func (m *MockStore) UpdateShareLink(arg0 *model.ShareLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShareLink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShareLink indicates an expected call of UpdateShareLink.
+func (mr *MockStoreMockRecorder) UpdateShareLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShareLink", reflect.TypeOf((*MockStore)(nil).UpdateShareLink), arg0)
+}
+
 // UpdateSubscribersNotifiedAt mocks base method.
 func (m *MockStore) UpdateSubscribersNotifiedAt(arg0 string, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -1691,6 +2945,34 @@ func (mr *MockStoreMockRecorder) UpdateSubscribersNotifiedAt(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscribersNotifiedAt", reflect.TypeOf((*MockStore)(nil).UpdateSubscribersNotifiedAt), arg0, arg1)
 }
 
+// UpdateTeam mocks base method.
+func (m *MockStore) UpdateTeam(arg0 *model.Team) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeam", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTeam indicates an expected call of UpdateTeam.
+func (mr *MockStoreMockRecorder) UpdateTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeam", reflect.TypeOf((*MockStore)(nil).UpdateTeam), arg0)
+}
+
+// UpdateUploadSession mocks base method.
+func (m *MockStore) UpdateUploadSession(arg0 *model.UploadSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUploadSession", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUploadSession indicates an expected call of UpdateUploadSession.
+func (mr *MockStoreMockRecorder) UpdateUploadSession(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadSession", reflect.TypeOf((*MockStore)(nil).UpdateUploadSession), arg0)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(arg0 *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -1706,6 +2988,48 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0)
 }
 
+// UpdateUserActive mocks base method.
+func (m *MockStore) UpdateUserActive(arg0 string, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserActive", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserActive indicates an expected call of UpdateUserActive.
+func (mr *MockStoreMockRecorder) UpdateUserActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserActive", reflect.TypeOf((*MockStore)(nil).UpdateUserActive), arg0, arg1)
+}
+
+// UpdateUserGroup mocks base method.
+func (m *MockStore) UpdateUserGroup(arg0 *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserGroup indicates an expected call of UpdateUserGroup.
+func (mr *MockStoreMockRecorder) UpdateUserGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserGroup", reflect.TypeOf((*MockStore)(nil).UpdateUserGroup), arg0)
+}
+
+// UpdateUserGuest mocks base method.
+func (m *MockStore) UpdateUserGuest(arg0 string, arg1 bool, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserGuest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserGuest indicates an expected call of UpdateUserGuest.
+func (mr *MockStoreMockRecorder) UpdateUserGuest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserGuest", reflect.TypeOf((*MockStore)(nil).UpdateUserGuest), arg0, arg1, arg2)
+}
+
 // UpdateUserPassword mocks base method.
 func (m *MockStore) UpdateUserPassword(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1734,6 +3058,20 @@ func (mr *MockStoreMockRecorder) UpdateUserPasswordByID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordByID", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordByID), arg0, arg1)
 }
 
+// UpdateUserRoles mocks base method.
+func (m *MockStore) UpdateUserRoles(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRoles", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRoles indicates an expected call of UpdateUserRoles.
+func (mr *MockStoreMockRecorder) UpdateUserRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRoles", reflect.TypeOf((*MockStore)(nil).UpdateUserRoles), arg0, arg1)
+}
+
 // UpsertNotificationHint mocks base method.
 func (m *MockStore) UpsertNotificationHint(arg0 *model.NotificationHint, arg1 time.Duration) (*model.NotificationHint, error) {
 	m.ctrl.T.Helper()
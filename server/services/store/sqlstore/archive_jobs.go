@@ -0,0 +1,226 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func archiveJobFields() []string {
+	return []string{
+		"id",
+		"type",
+		"team_id",
+		"created_by",
+		"status",
+		"progress",
+		"COALESCE(error, '')",
+		"COALESCE(board_ids, '')",
+		"COALESCE(file_path, '')",
+		"COALESCE(import_mode, '')",
+		"COALESCE(import_result, '')",
+		"create_at",
+		"update_at",
+		"expire_at",
+	}
+}
+
+func (s *SQLStore) archiveJobsFromRows(rows *sql.Rows) ([]*model.ArchiveJob, error) {
+	jobs := []*model.ArchiveJob{}
+
+	for rows.Next() {
+		var job model.ArchiveJob
+		var boardIDs string
+		var importResult string
+
+		err := rows.Scan(
+			&job.ID,
+			&job.Type,
+			&job.TeamID,
+			&job.CreatedBy,
+			&job.Status,
+			&job.Progress,
+			&job.Error,
+			&boardIDs,
+			&job.FilePath,
+			&job.ImportMode,
+			&importResult,
+			&job.CreateAt,
+			&job.UpdateAt,
+			&job.ExpireAt,
+		)
+		if err != nil {
+			s.logger.Error("archiveJobsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+
+		job.BoardIDs = []string{}
+		if boardIDs != "" {
+			if err := json.Unmarshal([]byte(boardIDs), &job.BoardIDs); err != nil {
+				s.logger.Error("archiveJobsFromRows board ids unmarshal error", mlog.Err(err))
+				return nil, err
+			}
+		}
+
+		if importResult != "" {
+			if err := json.Unmarshal([]byte(importResult), &job.ImportResult); err != nil {
+				s.logger.Error("archiveJobsFromRows import result unmarshal error", mlog.Err(err))
+				return nil, err
+			}
+		}
+
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+func (s *SQLStore) createArchiveJob(db sq.BaseRunner, job *model.ArchiveJob) error {
+	boardIDs, err := json.Marshal(job.BoardIDs)
+	if err != nil {
+		return err
+	}
+
+	importResult, err := marshalImportResult(job.ImportResult)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "archive_jobs").
+		SetMap(map[string]interface{}{
+			"id":            job.ID,
+			"type":          job.Type,
+			"team_id":       job.TeamID,
+			"created_by":    job.CreatedBy,
+			"status":        job.Status,
+			"progress":      job.Progress,
+			"error":         job.Error,
+			"board_ids":     string(boardIDs),
+			"file_path":     job.FilePath,
+			"import_mode":   job.ImportMode,
+			"import_result": importResult,
+			"create_at":     job.CreateAt,
+			"update_at":     job.UpdateAt,
+			"expire_at":     job.ExpireAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createArchiveJob error", mlog.String("jobID", job.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateArchiveJob saves the state of a job. The job definition, i.e.
+// its type, team, user, boards, import mode and file, can't be changed.
+func (s *SQLStore) updateArchiveJob(db sq.BaseRunner, job *model.ArchiveJob) error {
+	importResult, err := marshalImportResult(job.ImportResult)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"archive_jobs").
+		Set("status", job.Status).
+		Set("progress", job.Progress).
+		Set("error", job.Error).
+		Set("import_result", importResult).
+		Set("update_at", job.UpdateAt).
+		Set("expire_at", job.ExpireAt).
+		Where(sq.Eq{"id": job.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateArchiveJob error", mlog.String("jobID", job.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("archive job ID=" + job.ID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getArchiveJob(db sq.BaseRunner, jobID string) (*model.ArchiveJob, error) {
+	query := s.getQueryBuilder(db).
+		Select(archiveJobFields()...).
+		From(s.tablePrefix + "archive_jobs").
+		Where(sq.Eq{"id": jobID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getArchiveJob error", mlog.String("jobID", jobID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	jobs, err := s.archiveJobsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, model.NewErrNotFound("archive job ID=" + jobID)
+	}
+	return jobs[0], nil
+}
+
+func (s *SQLStore) getArchiveJobs(db sq.BaseRunner, opts model.QueryArchiveJobsOptions) ([]*model.ArchiveJob, error) {
+	query := s.getQueryBuilder(db).
+		Select(archiveJobFields()...).
+		From(s.tablePrefix+"archive_jobs").
+		OrderBy("create_at DESC", "id")
+
+	if opts.TeamID != "" {
+		query = query.Where(sq.Eq{"team_id": opts.TeamID})
+	}
+	if opts.CreatedBy != "" {
+		query = query.Where(sq.Eq{"created_by": opts.CreatedBy})
+	}
+	if len(opts.Statuses) != 0 {
+		query = query.Where(sq.Eq{"status": opts.Statuses})
+	}
+	if opts.ExpiredBefore != 0 {
+		query = query.Where(sq.Gt{"expire_at": 0}).Where(sq.Lt{"expire_at": opts.ExpiredBefore})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getArchiveJobs error", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.archiveJobsFromRows(rows)
+}
+
+func (s *SQLStore) deleteArchiveJob(db sq.BaseRunner, jobID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "archive_jobs").
+		Where(sq.Eq{"id": jobID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteArchiveJob error", mlog.String("jobID", jobID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func marshalImportResult(result *model.ImportArchiveResult) (string, error) {
+	if result == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mattermost/focalboard/server/utils"
 
@@ -179,6 +180,37 @@ func (s *SQLStore) getBlocksForBoard(db sq.BaseRunner, boardID string) ([]*model
 	return s.getBlocks(db, opts)
 }
 
+// getBlocksForUser returns the blocks created by a user and the cards
+// that reference it in their properties, like the cards it's assigned to.
+func (s *SQLStore) getBlocksForUser(db sq.BaseRunner, userID string) ([]*model.Block, error) {
+	fieldsColumn := "fields"
+	if s.dbType == model.PostgresDBType {
+		fieldsColumn = "fields::text"
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Or{
+			sq.Eq{"created_by": userID},
+			sq.And{
+				sq.Eq{"type": model.TypeCard},
+				sq.Like{fieldsColumn: "%" + userID + "%"},
+			},
+		}).
+		OrderBy("create_at")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBlocksForUser ERROR`, mlog.Err(err))
+
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
 func (s *SQLStore) blocksFromRows(rows *sql.Rows) ([]*model.Block, error) {
 	results := []*model.Block{}
 
@@ -324,6 +356,37 @@ func (s *SQLStore) patchBlock(db sq.BaseRunner, blockID string, blockPatch *mode
 	return s.insertBlock(db, block, userID)
 }
 
+// updateBlockTitle updates the title of a block in place, without
+// writing a new entry in the block history.
+func (s *SQLStore) updateBlockTitle(db sq.BaseRunner, blockID, title, modifiedBy string) error {
+	if utf8.RuneCountInString(title) > model.BlockTitleMaxRunes {
+		return model.ErrBlockTitleSizeLimitExceeded
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"blocks").
+		Set("title", title).
+		Set("modified_by", modifiedBy).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": blockID}).
+		Where(sq.Eq{"delete_at": 0})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return model.NewErrNotFound("block ID=" + blockID)
+	}
+
+	return nil
+}
+
 func (s *SQLStore) patchBlocks(db sq.BaseRunner, blockPatches *model.BlockPatchBatch, userID string) error {
 	for i, blockID := range blockPatches.BlockIDs {
 		err := s.patchBlock(db, blockID, &blockPatches.BlockPatches[i], userID)
@@ -41,6 +41,7 @@ func boardFields(tableAlias string) []string {
 		tableAlias + "create_at",
 		tableAlias + "update_at",
 		tableAlias + "delete_at",
+		tableAlias + "archive_at",
 	}
 }
 
@@ -64,6 +65,7 @@ func boardHistoryFields() []string {
 		"COALESCE(create_at, 0)",
 		"COALESCE(update_at, 0)",
 		"COALESCE(delete_at, 0)",
+		"COALESCE(archive_at, 0)",
 	}
 
 	return fields
@@ -78,6 +80,8 @@ var boardMemberFields = []string{
 	"BM.scheme_editor",
 	"BM.scheme_commenter",
 	"BM.scheme_viewer",
+	"COALESCE(BM.custom_role_id, '')",
+	"COALESCE(BM.group_id, '')",
 }
 
 func (s *SQLStore) boardsFromRows(rows *sql.Rows) ([]*model.Board, error) {
@@ -107,6 +111,7 @@ func (s *SQLStore) boardsFromRows(rows *sql.Rows) ([]*model.Board, error) {
 			&board.CreateAt,
 			&board.UpdateAt,
 			&board.DeleteAt,
+			&board.ArchiveAt,
 		)
 		if err != nil {
 			s.logger.Error("boardsFromRows scan error", mlog.Err(err))
@@ -145,6 +150,8 @@ func (s *SQLStore) boardMembersFromRows(rows *sql.Rows) ([]*model.BoardMember, e
 			&boardMember.SchemeEditor,
 			&boardMember.SchemeCommenter,
 			&boardMember.SchemeViewer,
+			&boardMember.CustomRoleID,
+			&boardMember.GroupID,
 		)
 		if err != nil {
 			return nil, err
@@ -355,6 +362,7 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 		"create_at":        board.CreateAt,
 		"update_at":        board.UpdateAt,
 		"delete_at":        board.DeleteAt,
+		"archive_at":       board.ArchiveAt,
 	}
 
 	if existingBoard != nil {
@@ -373,7 +381,8 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 			Set("properties", propertiesBytes).
 			Set("card_properties", cardPropertiesBytes).
 			Set("update_at", board.UpdateAt).
-			Set("delete_at", board.DeleteAt)
+			Set("delete_at", board.DeleteAt).
+			Set("archive_at", board.ArchiveAt)
 
 		if _, err := query.Exec(); err != nil {
 			s.logger.Error(`InsertBoard error occurred while updating existing board`, mlog.String("boardID", board.ID), mlog.Err(err))
@@ -411,6 +420,34 @@ func (s *SQLStore) patchBoard(db sq.BaseRunner, boardID string, boardPatch *mode
 	return s.insertBoard(db, board, userID)
 }
 
+func (s *SQLStore) archiveBoard(db sq.BaseRunner, boardID, userID string) (*model.Board, error) {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if board.IsArchived() {
+		return board, nil
+	}
+
+	board.ArchiveAt = utils.GetMillis()
+	return s.insertBoard(db, board, userID)
+}
+
+func (s *SQLStore) unarchiveBoard(db sq.BaseRunner, boardID, userID string) (*model.Board, error) {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !board.IsArchived() {
+		return board, nil
+	}
+
+	board.ArchiveAt = 0
+	return s.insertBoard(db, board, userID)
+}
+
 func (s *SQLStore) deleteBoard(db sq.BaseRunner, boardID, userID string) error {
 	return s.deleteBoardAndChildren(db, boardID, userID, false)
 }
@@ -451,6 +488,7 @@ func (s *SQLStore) deleteBoardAndChildren(db sq.BaseRunner, boardID, userID stri
 		"create_at":        board.CreateAt,
 		"update_at":        now,
 		"delete_at":        now,
+		"archive_at":       board.ArchiveAt,
 	}
 
 	// writing board history
@@ -508,6 +546,8 @@ func (s *SQLStore) saveMember(db sq.BaseRunner, bm *model.BoardMember) (*model.B
 		"scheme_editor":    bm.SchemeEditor,
 		"scheme_commenter": bm.SchemeCommenter,
 		"scheme_viewer":    bm.SchemeViewer,
+		"custom_role_id":   bm.CustomRoleID,
+		"group_id":         bm.GroupID,
 	}
 
 	oldMember, err := s.getMemberForBoard(db, bm.BoardID, bm.UserID)
@@ -521,13 +561,14 @@ func (s *SQLStore) saveMember(db sq.BaseRunner, bm *model.BoardMember) (*model.B
 
 	if s.dbType == model.MysqlDBType {
 		query = query.Suffix(
-			"ON DUPLICATE KEY UPDATE scheme_admin = ?, scheme_editor = ?, scheme_commenter = ?, scheme_viewer = ?",
-			bm.SchemeAdmin, bm.SchemeEditor, bm.SchemeCommenter, bm.SchemeViewer)
+			"ON DUPLICATE KEY UPDATE scheme_admin = ?, scheme_editor = ?, scheme_commenter = ?, scheme_viewer = ?, custom_role_id = ?, group_id = ?",
+			bm.SchemeAdmin, bm.SchemeEditor, bm.SchemeCommenter, bm.SchemeViewer, bm.CustomRoleID, bm.GroupID)
 	} else {
 		query = query.Suffix(
 			`ON CONFLICT (board_id, user_id)
              DO UPDATE SET scheme_admin = EXCLUDED.scheme_admin, scheme_editor = EXCLUDED.scheme_editor,
-			   scheme_commenter = EXCLUDED.scheme_commenter, scheme_viewer = EXCLUDED.scheme_viewer`,
+			   scheme_commenter = EXCLUDED.scheme_commenter, scheme_viewer = EXCLUDED.scheme_viewer,
+			   custom_role_id = EXCLUDED.custom_role_id, group_id = EXCLUDED.group_id`,
 		)
 	}
 
@@ -833,6 +874,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		"create_at",
 		"update_at",
 		"delete_at",
+		"archive_at",
 	}
 
 	values := []interface{}{
@@ -854,6 +896,7 @@ func (s *SQLStore) undeleteBoard(db sq.BaseRunner, boardID string, modifiedBy st
 		board.CreateAt,
 		now,
 		0,
+		board.ArchiveAt,
 	}
 	insertHistoryQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "boards_history").
 		Columns(columns...).
@@ -899,3 +942,22 @@ func (s *SQLStore) getBoardMemberHistory(db sq.BaseRunner, boardID, userID strin
 
 	return memberHistory, nil
 }
+
+// getBoardMemberHistoryForUser returns the membership history of a user
+// on all the boards, oldest first.
+func (s *SQLStore) getBoardMemberHistoryForUser(db sq.BaseRunner, userID string) ([]*model.BoardMemberHistoryEntry, error) {
+	query := s.getQueryBuilder(db).
+		Select("board_id", "user_id", "action", "insert_at").
+		From(s.tablePrefix + "board_members_history").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("insert_at ASC")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardMemberHistoryForUser ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardMemberHistoryEntriesFromRows(rows)
+}
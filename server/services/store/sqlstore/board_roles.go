@@ -0,0 +1,175 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func customBoardRoleFields() []string {
+	return []string{
+		"id",
+		"team_id",
+		"name",
+		"COALESCE(description, '')",
+		"COALESCE(permissions, '[]')",
+		"created_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) customBoardRolesFromRows(rows *sql.Rows) ([]*model.CustomBoardRole, error) {
+	roles := []*model.CustomBoardRole{}
+
+	for rows.Next() {
+		var role model.CustomBoardRole
+		var permissions []byte
+		err := rows.Scan(
+			&role.ID,
+			&role.TeamID,
+			&role.Name,
+			&role.Description,
+			&permissions,
+			&role.CreatedBy,
+			&role.CreateAt,
+			&role.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("customBoardRolesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+
+		if err = json.Unmarshal(permissions, &role.Permissions); err != nil {
+			s.logger.Error("customBoardRolesFromRows unmarshal permissions error", mlog.Err(err))
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	return roles, rows.Err()
+}
+
+func (s *SQLStore) createCustomBoardRole(db sq.BaseRunner, role *model.CustomBoardRole) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "board_roles").
+		SetMap(map[string]interface{}{
+			"id":          role.ID,
+			"team_id":     role.TeamID,
+			"name":        role.Name,
+			"description": role.Description,
+			"permissions": string(permissions),
+			"created_by":  role.CreatedBy,
+			"create_at":   role.CreateAt,
+			"update_at":   role.UpdateAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createCustomBoardRole error", mlog.String("roleID", role.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) updateCustomBoardRole(db sq.BaseRunner, role *model.CustomBoardRole) error {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"board_roles").
+		Set("name", role.Name).
+		Set("description", role.Description).
+		Set("permissions", string(permissions)).
+		Set("update_at", role.UpdateAt).
+		Where(sq.Eq{"id": role.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateCustomBoardRole error", mlog.String("roleID", role.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("custom board role ID=" + role.ID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getCustomBoardRole(db sq.BaseRunner, id string) (*model.CustomBoardRole, error) {
+	query := s.getQueryBuilder(db).
+		Select(customBoardRoleFields()...).
+		From(s.tablePrefix + "board_roles").
+		Where(sq.Eq{"id": id})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getCustomBoardRole error", mlog.String("roleID", id), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	roles, err := s.customBoardRolesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, model.NewErrNotFound("custom board role ID=" + id)
+	}
+	return roles[0], nil
+}
+
+func (s *SQLStore) getCustomBoardRolesForTeam(db sq.BaseRunner, teamID string) ([]*model.CustomBoardRole, error) {
+	query := s.getQueryBuilder(db).
+		Select(customBoardRoleFields()...).
+		From(s.tablePrefix+"board_roles").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("name", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getCustomBoardRolesForTeam error", mlog.String("teamID", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.customBoardRolesFromRows(rows)
+}
+
+// deleteCustomBoardRole deletes a custom board role, and unassigns it
+// from the board members it was assigned to.
+func (s *SQLStore) deleteCustomBoardRole(db sq.BaseRunner, id string) error {
+	unassignQuery := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"board_members").
+		Set("custom_role_id", "").
+		Where(sq.Eq{"custom_role_id": id})
+
+	if _, err := unassignQuery.Exec(); err != nil {
+		s.logger.Error("deleteCustomBoardRole unassign error", mlog.String("roleID", id), mlog.Err(err))
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_roles").
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteCustomBoardRole error", mlog.String("roleID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
@@ -96,6 +96,44 @@ func (s *SQLStore) patchBoardsAndBlocks(db sq.BaseRunner, pbab *model.PatchBoard
 	return bab, nil
 }
 
+// importBoardCards patches the card properties of a board, inserts the
+// new cards and patches the existing ones, so an import is either fully
+// applied or not at all. It returns the patched board, if any, and the
+// inserted and patched cards.
+func (s *SQLStore) importBoardCards(db sq.BaseRunner, boardID string, boardPatch *model.BoardPatch, newCards []*model.Block, cardPatches *model.BlockPatchBatch, userID string) (*model.BoardsAndBlocks, error) {
+	bab := &model.BoardsAndBlocks{Boards: []*model.Board{}, Blocks: []*model.Block{}}
+
+	if boardPatch != nil {
+		board, err := s.patchBoard(db, boardID, boardPatch, userID)
+		if err != nil {
+			return nil, err
+		}
+		bab.Boards = append(bab.Boards, board)
+	}
+
+	for _, card := range newCards {
+		if err := s.insertBlock(db, card, userID); err != nil {
+			return nil, err
+		}
+		bab.Blocks = append(bab.Blocks, card)
+	}
+
+	if cardPatches != nil {
+		for i, cardID := range cardPatches.BlockIDs {
+			if err := s.patchBlock(db, cardID, &cardPatches.BlockPatches[i], userID); err != nil {
+				return nil, err
+			}
+			card, err := s.getBlock(db, cardID)
+			if err != nil {
+				return nil, err
+			}
+			bab.Blocks = append(bab.Blocks, card)
+		}
+	}
+
+	return bab, nil
+}
+
 // deleteBoardsAndBlocks deletes all the boards and blocks entities of
 // the DeleteBoardsAndBlocks struct, making sure that all the blocks
 // belong to the boards in the struct.
@@ -156,6 +194,7 @@ func (s *SQLStore) duplicateBoard(db sq.BaseRunner, boardID string, userID strin
 	board.IsTemplate = asTemplate
 	board.CreatedBy = userID
 	board.ChannelID = ""
+	board.ArchiveAt = 0
 
 	if toTeam != "" {
 		board.TeamID = toTeam
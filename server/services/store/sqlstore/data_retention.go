@@ -57,11 +57,21 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 			PrimaryKeys:   []string{"board_id"},
 			BoardIDColumn: "board_id",
 		},
+		{
+			Table:         "board_groups",
+			PrimaryKeys:   []string{"board_id"},
+			BoardIDColumn: "board_id",
+		},
 		{
 			Table:         "sharing",
 			PrimaryKeys:   []string{"id"},
 			BoardIDColumn: "id",
 		},
+		{
+			Table:         "share_links",
+			PrimaryKeys:   []string{"id"},
+			BoardIDColumn: "board_id",
+		},
 		{
 			Table:         "category_boards",
 			PrimaryKeys:   []string{"id"},
@@ -82,7 +92,9 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 		LeftJoin("( " + subQuery + " ) As subquery ON (subquery.board_id = id)").
 		Where(sq.Lt{"maxDate": globalRetentionDate}).
 		Where(sq.NotEq{"team_id": "0"}).
-		Where(sq.Eq{"is_template": false})
+		Where(sq.Eq{"is_template": false}).
+		// archived boards are kept regardless of their activity
+		Where(sq.Eq{"archive_at": 0})
 
 	rows, err := builder.Query()
 	if err != nil {
@@ -105,6 +117,15 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 			totalAffected += int(affected)
 		}
 	}
+
+	// boards and blocks that were deleted before the retention date
+	// are only kept in the history tables, so they're purged as well
+	affected, err := s.purgeTrash(db, globalRetentionDate, batchSize)
+	if err != nil {
+		return int64(totalAffected), err
+	}
+	totalAffected += int(affected)
+
 	s.logger.Info("Complete Boards Data Retention",
 		mlog.Int("Total deletion ids", len(deleteIds)),
 		mlog.Int("TotalAffected", totalAffected))
@@ -170,7 +191,7 @@ func (s *SQLStore) genericRetentionPoliciesDeletion(
 			return 0, errors.Wrap(err, "failed to get rows affected for "+info.Table)
 		}
 		totalRowsAffected += batchRowsAffected
-		if batchRowsAffected != batchSize {
+		if batchSize <= 0 || batchRowsAffected != batchSize {
 			break
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -20,9 +21,15 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *mmModel.FileInfo) er
 			"create_at",
 			"name",
 			"extension",
+			"mime_type",
 			"size",
 			"delete_at",
 			"path",
+			"thumbnail_path",
+			"preview_path",
+			"width",
+			"height",
+			"has_preview_image",
 			"archived",
 		).
 		Values(
@@ -30,9 +37,15 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *mmModel.FileInfo) er
 			fileInfo.CreateAt,
 			fileInfo.Name,
 			fileInfo.Extension,
+			fileInfo.MimeType,
 			fileInfo.Size,
 			fileInfo.DeleteAt,
 			fileInfo.Path,
+			fileInfo.ThumbnailPath,
+			fileInfo.PreviewPath,
+			fileInfo.Width,
+			fileInfo.Height,
+			fileInfo.HasPreviewImage,
 			false,
 		)
 
@@ -49,23 +62,39 @@ func (s *SQLStore) saveFileInfo(db sq.BaseRunner, fileInfo *mmModel.FileInfo) er
 	return nil
 }
 
-func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*mmModel.FileInfo, error) {
+func (s *SQLStore) deleteFileInfo(db sq.BaseRunner, id string) error {
 	query := s.getQueryBuilder(db).
-		Select(
-			"id",
-			"create_at",
-			"delete_at",
-			"name",
-			"extension",
-			"size",
-			"archived",
-			"path",
-		).
-		From(s.tablePrefix + "file_info").
+		Update(s.tablePrefix+"file_info").
+		Set("delete_at", utils.GetMillis()).
 		Where(sq.Eq{"id": id})
 
-	row := query.QueryRow()
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to delete fileinfo", mlog.String("id", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func fileInfoFields() []string {
+	return []string{
+		"id",
+		"create_at",
+		"COALESCE(delete_at, 0)",
+		"name",
+		"extension",
+		"COALESCE(mime_type, '')",
+		"size",
+		"COALESCE(archived, false)",
+		"COALESCE(path, '')",
+		"COALESCE(thumbnail_path, '')",
+		"COALESCE(preview_path, '')",
+		"COALESCE(width, 0)",
+		"COALESCE(height, 0)",
+		"COALESCE(has_preview_image, false)",
+	}
+}
 
+func fileInfoFromRow(row sq.RowScanner) (*mmModel.FileInfo, error) {
 	fileInfo := mmModel.FileInfo{}
 
 	err := row.Scan(
@@ -74,11 +103,30 @@ func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*mmModel.FileInfo,
 		&fileInfo.DeleteAt,
 		&fileInfo.Name,
 		&fileInfo.Extension,
+		&fileInfo.MimeType,
 		&fileInfo.Size,
 		&fileInfo.Archived,
 		&fileInfo.Path,
+		&fileInfo.ThumbnailPath,
+		&fileInfo.PreviewPath,
+		&fileInfo.Width,
+		&fileInfo.Height,
+		&fileInfo.HasPreviewImage,
 	)
+	if err != nil {
+		return nil, err
+	}
 
+	return &fileInfo, nil
+}
+
+func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*mmModel.FileInfo, error) {
+	query := s.getQueryBuilder(db).
+		Select(fileInfoFields()...).
+		From(s.tablePrefix + "file_info").
+		Where(sq.Eq{"id": id})
+
+	fileInfo, err := fileInfoFromRow(query.QueryRow())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, model.NewErrNotFound("file info ID=" + id)
@@ -88,5 +136,66 @@ func (s *SQLStore) getFileInfo(db sq.BaseRunner, id string) (*mmModel.FileInfo,
 		return nil, err
 	}
 
-	return &fileInfo, nil
+	return fileInfo, nil
+}
+
+func (s *SQLStore) updateFileInfoPreviews(db sq.BaseRunner, fileInfo *mmModel.FileInfo) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"file_info").
+		Set("thumbnail_path", fileInfo.ThumbnailPath).
+		Set("preview_path", fileInfo.PreviewPath).
+		Set("width", fileInfo.Width).
+		Set("height", fileInfo.Height).
+		Set("has_preview_image", fileInfo.HasPreviewImage).
+		Where(sq.Eq{"id": fileInfo.Id})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("failed to update fileinfo previews", mlog.String("id", fileInfo.Id), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("file info ID=" + fileInfo.Id)
+	}
+
+	return nil
+}
+
+// getFileInfosWithoutPreviews returns the file infos of the existing files
+// with one of the given extensions that don't have previews, ordered by ID
+// and starting after afterID.
+func (s *SQLStore) getFileInfosWithoutPreviews(db sq.BaseRunner, extensions []string, afterID string, limit int) ([]*mmModel.FileInfo, error) {
+	query := s.getQueryBuilder(db).
+		Select(fileInfoFields()...).
+		From(s.tablePrefix + "file_info").
+		Where(sq.Eq{"extension": extensions}).
+		Where(sq.Gt{"id": afterID}).
+		Where(sq.Or{sq.Eq{"delete_at": nil}, sq.Eq{"delete_at": 0}}).
+		Where(sq.Or{sq.Eq{"archived": nil}, sq.Eq{"archived": false}}).
+		Where(sq.Or{sq.Eq{"has_preview_image": nil}, sq.Eq{"has_preview_image": false}}).
+		OrderBy("id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to get fileinfos without previews", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	fileInfos := []*mmModel.FileInfo{}
+	for rows.Next() {
+		fileInfo, err := fileInfoFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		fileInfos = append(fileInfos, fileInfo)
+	}
+
+	return fileInfos, rows.Err()
 }
@@ -0,0 +1,251 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func fileUsageFields() []string {
+	return []string{
+		"file_id",
+		"team_id",
+		"board_id",
+		"file_name",
+		"path",
+		"size",
+		"create_at",
+	}
+}
+
+func (s *SQLStore) saveFileUsage(db sq.BaseRunner, usage *model.FileUsage) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"file_usage").
+		Columns(fileUsageFields()...).
+		Values(
+			usage.FileID,
+			usage.TeamID,
+			usage.BoardID,
+			usage.FileName,
+			usage.Path,
+			usage.Size,
+			usage.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to save file usage", mlog.String("file_id", usage.FileID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteFileUsage(db sq.BaseRunner, fileID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "file_usage").
+		Where(sq.Eq{"file_id": fileID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("failed to delete file usage", mlog.String("file_id", fileID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getFileUsageByFileID(db sq.BaseRunner, fileID string) (*model.FileUsage, error) {
+	query := s.getQueryBuilder(db).
+		Select(fileUsageFields()...).
+		From(s.tablePrefix + "file_usage").
+		Where(sq.Eq{"file_id": fileID})
+
+	var usage model.FileUsage
+	err := query.QueryRow().Scan(
+		&usage.FileID,
+		&usage.TeamID,
+		&usage.BoardID,
+		&usage.FileName,
+		&usage.Path,
+		&usage.Size,
+		&usage.CreateAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewErrNotFound("file usage ID=" + fileID)
+	}
+	if err != nil {
+		s.logger.Error("failed to get file usage", mlog.String("file_id", fileID), mlog.Err(err))
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// getFileUsage returns the storage used by the files of each board of a
+// team, or of all the teams if teamID is empty.
+func (s *SQLStore) getFileUsage(db sq.BaseRunner, teamID string) ([]*model.TeamFileUsage, error) {
+	query := s.getQueryBuilder(db).
+		Select("team_id", "board_id", "COUNT(*)", "SUM(size)").
+		From(s.tablePrefix+"file_usage").
+		GroupBy("team_id", "board_id").
+		OrderBy("team_id", "board_id")
+
+	if teamID != "" {
+		query = query.Where(sq.Eq{"team_id": teamID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to get file usage", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	teams := []*model.TeamFileUsage{}
+	var team *model.TeamFileUsage
+	for rows.Next() {
+		var boardTeamID string
+		board := &model.BoardFileUsage{}
+		if err := rows.Scan(&boardTeamID, &board.BoardID, &board.Files, &board.Size); err != nil {
+			return nil, err
+		}
+
+		if team == nil || team.TeamID != boardTeamID {
+			team = &model.TeamFileUsage{TeamID: boardTeamID, Boards: []*model.BoardFileUsage{}}
+			teams = append(teams, team)
+		}
+		team.Files += board.Files
+		team.Size += board.Size
+		team.Boards = append(team.Boards, board)
+	}
+
+	return teams, rows.Err()
+}
+
+// getTeamFileUsageSize returns the size in bytes of the files of a team.
+func (s *SQLStore) getTeamFileUsageSize(db sq.BaseRunner, teamID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Select("COALESCE(SUM(size), 0)").
+		From(s.tablePrefix + "file_usage").
+		Where(sq.Eq{"team_id": teamID})
+
+	var size int64
+	if err := query.QueryRow().Scan(&size); err != nil {
+		s.logger.Error("failed to get team file usage size", mlog.String("team_id", teamID), mlog.Err(err))
+		return 0, err
+	}
+	return size, nil
+}
+
+// getFileUsagesCreatedBefore returns the usage of the files created before
+// the given time, ordered by file ID and starting after afterID.
+func (s *SQLStore) getFileUsagesCreatedBefore(db sq.BaseRunner, createdBefore int64, afterID string, limit int) ([]*model.FileUsage, error) {
+	query := s.getQueryBuilder(db).
+		Select(fileUsageFields()...).
+		From(s.tablePrefix + "file_usage").
+		Where(sq.Lt{"create_at": createdBefore}).
+		Where(sq.Gt{"file_id": afterID}).
+		OrderBy("file_id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to get file usages", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	usages := []*model.FileUsage{}
+	for rows.Next() {
+		var usage model.FileUsage
+		err := rows.Scan(
+			&usage.FileID,
+			&usage.TeamID,
+			&usage.BoardID,
+			&usage.FileName,
+			&usage.Path,
+			&usage.Size,
+			&usage.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, &usage)
+	}
+
+	return usages, rows.Err()
+}
+
+// getBoardFileReferences returns the names of the files referenced by the
+// image and attachment blocks of a board, including the deleted blocks
+// that can still be restored from the history.
+func (s *SQLStore) getBoardFileReferences(db sq.BaseRunner, boardID string) ([]string, error) {
+	references := map[string]bool{}
+
+	for _, table := range []string{"blocks", "blocks_history"} {
+		query := s.getQueryBuilder(db).
+			Select("fields").
+			From(s.tablePrefix + table).
+			Where(sq.Eq{"board_id": boardID}).
+			Where(sq.Eq{"type": []model.BlockType{model.TypeImage, model.TypeAttachment}})
+
+		rows, err := query.Query()
+		if err != nil {
+			s.logger.Error("failed to get the file blocks of a board", mlog.String("board_id", boardID), mlog.Err(err))
+			return nil, err
+		}
+
+		for rows.Next() {
+			var fieldsJSON string
+			if err := rows.Scan(&fieldsJSON); err != nil {
+				s.CloseRows(rows)
+				return nil, err
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
+				s.logger.Warn("invalid fields of a file block", mlog.String("board_id", boardID), mlog.Err(err))
+				continue
+			}
+			for _, key := range []string{"fileId", "attachmentId"} {
+				if fileName, ok := fields[key].(string); ok && fileName != "" {
+					references[fileName] = true
+				}
+			}
+		}
+		err = rows.Err()
+		s.CloseRows(rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fileNames := make([]string, 0, len(references))
+	for fileName := range references {
+		fileNames = append(fileNames, fileName)
+	}
+	return fileNames, nil
+}
+
+// getFileBlocks returns a batch of the image and attachment blocks of all
+// the boards, ordered by ID and starting after afterID.
+func (s *SQLStore) getFileBlocks(db sq.BaseRunner, afterID string, limit int) ([]*model.Block, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"type": []model.BlockType{model.TypeImage, model.TypeAttachment}}).
+		Where(sq.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to get the file blocks", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
@@ -0,0 +1,179 @@
+package sqlstore
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// restoreBlockHistory restores a block, and optionally its children,
+// to the versions they had at updateAt. The restored versions are
+// written as new revisions, so the restore can be undone as well.
+func (s *SQLStore) restoreBlockHistory(db sq.BaseRunner, blockID string, updateAt int64, includeChildren bool, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	opts := model.QueryBlockHistoryOptions{
+		BeforeUpdateAt: updateAt + 1,
+		Limit:          1,
+		Descending:     true,
+	}
+	history, err := s.getBlockHistory(db, blockID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 || history[0].DeleteAt != 0 {
+		return nil, model.NewErrNotFound(fmt.Sprintf("block ID=%s at updateAt=%d", blockID, updateAt))
+	}
+	versions := []*model.Block{history[0]}
+
+	current := []*model.Block{}
+	block, err := s.getBlock(db, blockID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	if block != nil {
+		current = append(current, block)
+	}
+
+	if includeChildren {
+		children, _, err := s.getBlockHistoryNewestChildren(db, blockID, model.QueryBlockHistoryChildOptions{BeforeUpdateAt: updateAt + 1})
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, children...)
+
+		currentChildren, err := s.getBlocks(db, model.QueryBlocksOptions{BoardID: history[0].BoardID, ParentID: blockID})
+		if err != nil {
+			return nil, err
+		}
+		current = append(current, currentChildren...)
+	}
+
+	return s.restoreBlockVersions(db, versions, current, modifiedBy)
+}
+
+// restoreBoardHistory restores the properties of a board and all its
+// blocks to the versions they had at updateAt.
+func (s *SQLStore) restoreBoardHistory(db sq.BaseRunner, boardID string, updateAt int64, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := model.QueryBoardHistoryOptions{
+		BeforeUpdateAt: updateAt + 1,
+		Limit:          1,
+		Descending:     true,
+	}
+	history, err := s.getBoardHistory(db, boardID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 || history[0].DeleteAt != 0 {
+		return nil, model.NewErrNotFound(fmt.Sprintf("board ID=%s at updateAt=%d", boardID, updateAt))
+	}
+
+	// only the content of the board is restored, its channel link and
+	// permissions are left as they are now
+	oldBoard := history[0]
+	if board.UpdateAt != oldBoard.UpdateAt {
+		board.Title = oldBoard.Title
+		board.Description = oldBoard.Description
+		board.Icon = oldBoard.Icon
+		board.ShowDescription = oldBoard.ShowDescription
+		board.Properties = oldBoard.Properties
+		board.CardProperties = oldBoard.CardProperties
+
+		board, err = s.insertBoard(db, board, modifiedBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the history is sorted from newest to oldest, so the first
+	// entry of each block is its version at updateAt
+	blockHistory, err := s.getBlockHistoryDescendants(db, boardID, model.QueryBlockHistoryOptions{BeforeUpdateAt: updateAt + 1, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	versions := []*model.Block{}
+	for _, block := range blockHistory {
+		if seen[block.ID] {
+			continue
+		}
+		seen[block.ID] = true
+		versions = append(versions, block)
+	}
+
+	current, err := s.getBlocks(db, model.QueryBlocksOptions{BoardID: boardID})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.restoreBlockVersions(db, versions, current, modifiedBy)
+	if err != nil {
+		return nil, err
+	}
+	result.Board = board
+	return result, nil
+}
+
+// restoreBlockVersions writes the given versions of the blocks as
+// their new revisions and deletes the current blocks that have no
+// version, as they didn't exist yet. Comments are never restored nor
+// deleted, as they are part of the conversation rather than the
+// content.
+func (s *SQLStore) restoreBlockVersions(db sq.BaseRunner, versions, current []*model.Block, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	result := &model.HistoryRestoreResult{
+		Blocks:          []*model.Block{},
+		DeletedBlockIDs: []string{},
+	}
+
+	currentByID := map[string]*model.Block{}
+	for _, block := range current {
+		currentByID[block.ID] = block
+	}
+
+	restored := map[string]bool{}
+	for _, version := range versions {
+		if version.Type == model.TypeComment {
+			continue
+		}
+		restored[version.ID] = true
+
+		currentBlock, exists := currentByID[version.ID]
+		if version.DeleteAt != 0 {
+			if exists {
+				if err := s.deleteBlockAndChildren(db, version.ID, modifiedBy, true); err != nil {
+					return nil, err
+				}
+				result.DeletedBlockIDs = append(result.DeletedBlockIDs, version.ID)
+			}
+			continue
+		}
+
+		if exists && currentBlock.UpdateAt == version.UpdateAt {
+			// already at the restored revision
+			continue
+		}
+
+		version.DeleteAt = 0
+		if err := s.insertBlock(db, version, modifiedBy); err != nil {
+			return nil, err
+		}
+		result.Blocks = append(result.Blocks, version)
+	}
+
+	for _, block := range current {
+		if restored[block.ID] || block.Type == model.TypeComment {
+			continue
+		}
+		if err := s.deleteBlockAndChildren(db, block.ID, modifiedBy, true); err != nil {
+			return nil, err
+		}
+		result.DeletedBlockIDs = append(result.DeletedBlockIDs, block.ID)
+	}
+
+	return result, nil
+}
@@ -27,6 +27,8 @@ func (s *SQLStore) CreateBoardInvitation(invitation *model.BoardInvitation) erro
 			"created_by",
 			"created_at",
 			"expires_at",
+			"is_guest",
+			"guest_expire_at",
 		).
 		Values(
 			invitation.ID,
@@ -37,6 +39,8 @@ func (s *SQLStore) CreateBoardInvitation(invitation *model.BoardInvitation) erro
 			invitation.CreatedBy,
 			invitation.CreatedAt,
 			invitation.ExpiresAt,
+			invitation.IsGuest,
+			invitation.GuestExpireAt,
 		)
 
 	if _, err := query.Exec(); err != nil {
@@ -65,6 +69,8 @@ func (s *SQLStore) GetBoardInvitationByID(invitationID string) (*model.BoardInvi
 			"used_at",
 			"used_by",
 			"last_sent_at",
+			"COALESCE(is_guest, false)",
+			"COALESCE(guest_expire_at, 0)",
 		).
 		From(s.tablePrefix + "board_invitations").
 		Where(sq.Eq{"id": invitationID})
@@ -88,6 +94,8 @@ func (s *SQLStore) GetBoardInvitationByID(invitationID string) (*model.BoardInvi
 		&usedAt,
 		&usedBy,
 		&lastSentAt,
+		&invitation.IsGuest,
+		&invitation.GuestExpireAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -125,6 +133,8 @@ func (s *SQLStore) GetBoardInvitationByToken(token string) (*model.BoardInvitati
 			"used_at",
 			"used_by",
 			"last_sent_at",
+			"COALESCE(is_guest, false)",
+			"COALESCE(guest_expire_at, 0)",
 		).
 		From(s.tablePrefix + "board_invitations").
 		Where(sq.Eq{"token": token})
@@ -148,6 +158,8 @@ func (s *SQLStore) GetBoardInvitationByToken(token string) (*model.BoardInvitati
 		&usedAt,
 		&usedBy,
 		&lastSentAt,
+		&invitation.IsGuest,
+		&invitation.GuestExpireAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -185,6 +197,8 @@ func (s *SQLStore) GetBoardInvitationsForBoard(boardID string) ([]*model.BoardIn
 			"used_at",
 			"used_by",
 			"last_sent_at",
+			"COALESCE(is_guest, false)",
+			"COALESCE(guest_expire_at, 0)",
 		).
 		From(s.tablePrefix + "board_invitations").
 		Where(sq.Eq{"board_id": boardID}).
@@ -217,6 +231,8 @@ func (s *SQLStore) GetBoardInvitationsForBoard(boardID string) ([]*model.BoardIn
 			&usedAt,
 			&usedBy,
 			&lastSentAt,
+			&invitation.IsGuest,
+			&invitation.GuestExpireAt,
 		)
 		if err != nil {
 			s.logger.Error("GetBoardInvitationsForBoard scan error", mlog.Err(err))
@@ -0,0 +1,139 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var loginAttemptFields = []string{
+	"kind",
+	"identifier",
+	"failed_count",
+	"last_failure_at",
+	"locked_until",
+	"COALESCE(unlock_token, '')",
+}
+
+func loginAttemptFromRow(row sq.RowScanner) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := row.Scan(
+		&attempt.Kind,
+		&attempt.Identifier,
+		&attempt.FailedCount,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+		&attempt.UnlockToken,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (s *SQLStore) getLoginAttemptByCondition(db sq.BaseRunner, condition sq.Eq) (*model.LoginAttempt, error) {
+	query := s.getQueryBuilder(db).
+		Select(loginAttemptFields...).
+		From(s.tablePrefix + "login_attempts").
+		Where(condition)
+
+	attempt, err := loginAttemptFromRow(query.QueryRow())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewErrNotFound("login attempt")
+	}
+	if err != nil {
+		s.logger.Error("getLoginAttempt error", mlog.Err(err))
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (s *SQLStore) getLoginAttempt(db sq.BaseRunner, kind, identifier string) (*model.LoginAttempt, error) {
+	return s.getLoginAttemptByCondition(db, sq.Eq{"kind": kind, "identifier": identifier})
+}
+
+func (s *SQLStore) getLoginAttemptByUnlockToken(db sq.BaseRunner, token string) (*model.LoginAttempt, error) {
+	if token == "" {
+		return nil, model.NewErrNotFound("login attempt")
+	}
+	return s.getLoginAttemptByCondition(db, sq.Eq{"unlock_token": token})
+}
+
+// incrementLoginAttempt counts a failed login attempt, restarting the
+// count when the previous failure happened before windowStart, and
+// returns the updated attempts.
+func (s *SQLStore) incrementLoginAttempt(db sq.BaseRunner, kind, identifier string, now, windowStart int64) (*model.LoginAttempt, error) {
+	table := s.tablePrefix + "login_attempts"
+
+	query := s.getQueryBuilder(db).
+		Insert(table).
+		Columns("kind", "identifier", "failed_count", "last_failure_at", "locked_until").
+		Values(kind, identifier, 1, now, 0)
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix(
+			"ON DUPLICATE KEY UPDATE failed_count = IF(last_failure_at < ?, 1, failed_count + 1), last_failure_at = ?",
+			windowStart, now)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (kind, identifier)
+			 DO UPDATE SET failed_count = CASE WHEN `+table+`.last_failure_at < ? THEN 1 ELSE `+table+`.failed_count + 1 END,
+			   last_failure_at = EXCLUDED.last_failure_at`,
+			windowStart)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("incrementLoginAttempt error", mlog.String("kind", kind), mlog.Err(err))
+		return nil, err
+	}
+
+	return s.getLoginAttempt(db, kind, identifier)
+}
+
+func (s *SQLStore) lockLoginAttempt(db sq.BaseRunner, kind, identifier string, lockedUntil int64, unlockToken string) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"login_attempts").
+		Set("locked_until", lockedUntil).
+		Set("unlock_token", unlockToken).
+		Where(sq.Eq{"kind": kind, "identifier": identifier})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("lockLoginAttempt error", mlog.String("kind", kind), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteLoginAttempt(db sq.BaseRunner, kind, identifier string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "login_attempts").
+		Where(sq.Eq{"kind": kind, "identifier": identifier})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteLoginAttempt error", mlog.String("kind", kind), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// deleteExpiredLoginAttempts deletes the attempts that failed before
+// failedBefore and aren't locked out anymore, and returns how many were
+// deleted.
+func (s *SQLStore) deleteExpiredLoginAttempts(db sq.BaseRunner, failedBefore, now int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "login_attempts").
+		Where(sq.Lt{"last_failure_at": failedBefore}).
+		Where(sq.LtOrEq{"locked_until": now})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("deleteExpiredLoginAttempts error", mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
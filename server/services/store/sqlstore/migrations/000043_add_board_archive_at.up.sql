@@ -0,0 +1,3 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "boards" "archive_at" "BIGINT" "DEFAULT 0"}}
+{{ addColumnIfNeeded "boards_history" "archive_at" "BIGINT" "DEFAULT 0"}}
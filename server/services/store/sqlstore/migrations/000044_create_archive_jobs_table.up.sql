@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}archive_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(16) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    progress INT NOT NULL DEFAULT 0,
+    error TEXT,
+    board_ids TEXT,
+    file_path TEXT,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    expire_at BIGINT NOT NULL DEFAULT 0
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "archive_jobs" "team_id, created_by" }}
+{{ createIndexIfNeeded "archive_jobs" "status" }}
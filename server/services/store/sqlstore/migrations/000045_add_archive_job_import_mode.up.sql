@@ -0,0 +1,3 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "archive_jobs" "import_mode" "VARCHAR(16)" ""}}
+{{ addColumnIfNeeded "archive_jobs" "import_result" "TEXT" ""}}
@@ -0,0 +1,6 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "file_info" "thumbnail_path" "VARCHAR(512)" ""}}
+{{ addColumnIfNeeded "file_info" "preview_path" "VARCHAR(512)" ""}}
+{{ addColumnIfNeeded "file_info" "width" "INTEGER" ""}}
+{{ addColumnIfNeeded "file_info" "height" "INTEGER" ""}}
+{{ addColumnIfNeeded "file_info" "has_preview_image" "BOOLEAN" ""}}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}file_usage (
+    file_id VARCHAR(36) PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    file_name VARCHAR(512) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    size BIGINT NOT NULL,
+    create_at BIGINT NOT NULL
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "file_usage" "team_id, board_id" }}
+{{ createIndexIfNeeded "file_usage" "create_at" }}
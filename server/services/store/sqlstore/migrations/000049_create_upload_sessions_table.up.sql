@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}upload_sessions (
+    id VARCHAR(36) PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    filename TEXT NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    file_size BIGINT NOT NULL,
+    file_offset BIGINT NOT NULL,
+    checksum VARCHAR(64),
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    claimed_until BIGINT NOT NULL DEFAULT 0
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "upload_sessions" "update_at" }}
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}share_links (
+    id VARCHAR(36) PRIMARY KEY,
+    board_id VARCHAR(36) NOT NULL,
+    token VARCHAR(100) NOT NULL,
+    name VARCHAR(100),
+    view_id VARCHAR(36),
+    hidden_properties TEXT,
+    expires_at BIGINT NOT NULL,
+    password_hash VARCHAR(128),
+    view_count BIGINT NOT NULL,
+    last_viewed_at BIGINT NOT NULL,
+    created_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "share_links" "board_id" }}
+{{ createIndexIfNeeded "share_links" "token" }}
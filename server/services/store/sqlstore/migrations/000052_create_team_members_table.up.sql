@@ -0,0 +1,30 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "teams" "title" "VARCHAR(256)" ""}}
+
+{{- /* Only perform this migration if the team_members table does not already exist */ -}}
+{{if doesTableExist "team_members" }}
+
+SELECT 1;
+
+{{else}}
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}team_members (
+    team_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    scheme_admin BOOLEAN,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (team_id, user_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* for personal server, every existing user is a member of the root team */ -}}
+{{if not .singleUser}}
+INSERT INTO {{.prefix}}team_members (team_id, user_id, scheme_admin, create_at)
+     SELECT '0', U.id, FALSE, U.create_at
+       FROM {{.prefix}}users AS U
+      WHERE U.delete_at = 0;
+{{end}}
+
+{{end}}
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "team_members" "user_id" }}
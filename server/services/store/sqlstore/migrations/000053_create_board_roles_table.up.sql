@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}board_roles (
+    id VARCHAR(36) PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    permissions TEXT,
+    created_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "board_roles" "team_id" }}
+
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "board_members" "custom_role_id" "VARCHAR(36)" ""}}
@@ -0,0 +1,5 @@
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "users" "is_guest" "boolean" "default false"}}
+{{ addColumnIfNeeded "users" "guest_expire_at" "BIGINT" "default 0"}}
+{{ addColumnIfNeeded "board_invitations" "is_guest" "boolean" "default false"}}
+{{ addColumnIfNeeded "board_invitations" "guest_expire_at" "BIGINT" "default 0"}}
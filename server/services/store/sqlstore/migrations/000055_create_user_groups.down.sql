@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS {{.prefix}}board_groups;
+DROP TABLE IF EXISTS {{.prefix}}user_group_members;
+DROP TABLE IF EXISTS {{.prefix}}user_groups;
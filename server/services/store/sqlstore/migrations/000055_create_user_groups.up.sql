@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}user_groups (
+    id VARCHAR(36) PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    description TEXT,
+    created_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}user_group_members (
+    group_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (group_id, user_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}board_groups (
+    board_id VARCHAR(36) NOT NULL,
+    group_id VARCHAR(36) NOT NULL,
+    scheme_admin BOOLEAN,
+    scheme_editor BOOLEAN,
+    scheme_commenter BOOLEAN,
+    scheme_viewer BOOLEAN,
+    custom_role_id VARCHAR(36),
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (board_id, group_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "user_groups" "team_id" }}
+{{ createIndexIfNeeded "user_group_members" "user_id" }}
+{{ createIndexIfNeeded "board_groups" "group_id" }}
+
+{{- /* addColumnIfNeeded tableName columnName datatype constraint */ -}}
+{{ addColumnIfNeeded "board_members" "group_id" "VARCHAR(36)" ""}}
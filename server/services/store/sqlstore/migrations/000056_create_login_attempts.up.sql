@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}login_attempts (
+    kind VARCHAR(10) NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    failed_count INT NOT NULL,
+    last_failure_at BIGINT NOT NULL,
+    locked_until BIGINT NOT NULL,
+    unlock_token VARCHAR(64),
+    PRIMARY KEY (kind, identifier)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "login_attempts" "unlock_token" }}
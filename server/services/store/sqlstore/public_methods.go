@@ -46,16 +46,79 @@ func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boar
 
 }
 
+func (s *SQLStore) AddUserGroupMember(groupID string, userID string, createAt int64) error {
+	return s.addUserGroupMember(s.db, groupID, userID, createAt)
+
+}
+
+func (s *SQLStore) AnonymizeUser(userID string, anonymousUser *model.User) (*model.UserDeletionReport, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.anonymizeUser(s.db, userID, anonymousUser)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.anonymizeUser(tx, userID, anonymousUser)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AnonymizeUser"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) ArchiveBoard(boardID string, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.archiveBoard(s.db, boardID, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.archiveBoard(tx, boardID, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "ArchiveBoard"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) CanSeeUser(seerID string, seenID string) (bool, error) {
 	return s.canSeeUser(s.db, seerID, seenID)
 
 }
 
+func (s *SQLStore) ClaimUploadSession(id string, fileOffset int64, now int64, claimedUntil int64) error {
+	return s.claimUploadSession(s.db, id, fileOffset, now, claimedUntil)
+
+}
+
 func (s *SQLStore) CleanUpSessions(expireTime int64) error {
 	return s.cleanUpSessions(s.db, expireTime)
 
 }
 
+func (s *SQLStore) CreateArchiveJob(job *model.ArchiveJob) error {
+	return s.createArchiveJob(s.db, job)
+
+}
+
 func (s *SQLStore) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.createBoardsAndBlocks(s.db, bab, userID)
@@ -128,21 +191,51 @@ func (s *SQLStore) CreateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) CreateCustomBoardRole(role *model.CustomBoardRole) error {
+	return s.createCustomBoardRole(s.db, role)
+
+}
+
 func (s *SQLStore) CreateSession(session *model.Session) error {
 	return s.createSession(s.db, session)
 
 }
 
+func (s *SQLStore) CreateShareLink(link *model.ShareLink) error {
+	return s.createShareLink(s.db, link)
+
+}
+
 func (s *SQLStore) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
 	return s.createSubscription(s.db, sub)
 
 }
 
+func (s *SQLStore) CreateTeam(team *model.Team) error {
+	return s.createTeam(s.db, team)
+
+}
+
+func (s *SQLStore) CreateUploadSession(session *model.UploadSession) error {
+	return s.createUploadSession(s.db, session)
+
+}
+
 func (s *SQLStore) CreateUser(user *model.User) (*model.User, error) {
 	return s.createUser(s.db, user)
 
 }
 
+func (s *SQLStore) CreateUserGroup(group *model.UserGroup) error {
+	return s.createUserGroup(s.db, group)
+
+}
+
+func (s *SQLStore) DeleteArchiveJob(jobID string) error {
+	return s.deleteArchiveJob(s.db, jobID)
+
+}
+
 func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBlock(s.db, blockID, modifiedBy)
@@ -196,6 +289,11 @@ func (s *SQLStore) DeleteBoard(boardID string, userID string) error {
 
 }
 
+func (s *SQLStore) DeleteBoardGroup(boardID string, groupID string) error {
+	return s.deleteBoardGroup(s.db, boardID, groupID)
+
+}
+
 func (s *SQLStore) DeleteBoardRecord(boardID string, modifiedBy string) error {
 	return s.deleteBoardRecord(s.db, boardID, modifiedBy)
 
@@ -230,6 +328,50 @@ func (s *SQLStore) DeleteCategory(categoryID string, userID string, teamID strin
 
 }
 
+func (s *SQLStore) DeleteCustomBoardRole(id string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteCustomBoardRole(s.db, id)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteCustomBoardRole(tx, id)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteCustomBoardRole"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteExpiredLoginAttempts(failedBefore int64, now int64) (int64, error) {
+	return s.deleteExpiredLoginAttempts(s.db, failedBefore, now)
+
+}
+
+func (s *SQLStore) DeleteFileInfo(id string) error {
+	return s.deleteFileInfo(s.db, id)
+
+}
+
+func (s *SQLStore) DeleteFileUsage(fileID string) error {
+	return s.deleteFileUsage(s.db, fileID)
+
+}
+
+func (s *SQLStore) DeleteLoginAttempt(kind string, identifier string) error {
+	return s.deleteLoginAttempt(s.db, kind, identifier)
+
+}
+
 func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 	return s.deleteMember(s.db, boardID, userID)
 
@@ -245,11 +387,84 @@ func (s *SQLStore) DeleteSession(sessionID string) error {
 
 }
 
+func (s *SQLStore) DeleteSessionsForUser(userID string) error {
+	return s.deleteSessionsForUser(s.db, userID)
+
+}
+
+func (s *SQLStore) DeleteShareLink(id string) error {
+	return s.deleteShareLink(s.db, id)
+
+}
+
 func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error {
 	return s.deleteSubscription(s.db, blockID, subscriberID)
 
 }
 
+func (s *SQLStore) DeleteTeam(id string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteTeam(s.db, id)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteTeam(tx, id)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteTeam"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteTeamMember(teamID string, userID string) error {
+	return s.deleteTeamMember(s.db, teamID, userID)
+
+}
+
+func (s *SQLStore) DeleteUploadSession(id string) error {
+	return s.deleteUploadSession(s.db, id)
+
+}
+
+func (s *SQLStore) DeleteUserGroup(id string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteUserGroup(s.db, id)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteUserGroup(tx, id)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteUserGroup"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteUserGroupMember(groupID string, userID string) error {
+	return s.deleteUserGroupMember(s.db, groupID, userID)
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -308,6 +523,21 @@ func (s *SQLStore) GetAllTeams() ([]*model.Team, error) {
 
 }
 
+func (s *SQLStore) GetAllUsers(opts model.UserQueryOptions) ([]*model.User, error) {
+	return s.getAllUsers(s.db, opts)
+
+}
+
+func (s *SQLStore) GetArchiveJob(jobID string) (*model.ArchiveJob, error) {
+	return s.getArchiveJob(s.db, jobID)
+
+}
+
+func (s *SQLStore) GetArchiveJobs(opts model.QueryArchiveJobsOptions) ([]*model.ArchiveJob, error) {
+	return s.getArchiveJobs(s.db, opts)
+
+}
+
 func (s *SQLStore) GetBlock(blockID string) (*model.Block, error) {
 	return s.getBlock(s.db, blockID)
 
@@ -353,6 +583,11 @@ func (s *SQLStore) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
 
 }
 
+func (s *SQLStore) GetBlocksForUser(userID string) ([]*model.Block, error) {
+	return s.getBlocksForUser(s.db, userID)
+
+}
+
 func (s *SQLStore) GetBlocksWithParent(boardID string, parentID string) ([]*model.Block, error) {
 	return s.getBlocksWithParent(s.db, boardID, parentID)
 
@@ -388,6 +623,26 @@ func (s *SQLStore) GetBoardCount() (int64, error) {
 
 }
 
+func (s *SQLStore) GetBoardFileReferences(boardID string) ([]string, error) {
+	return s.getBoardFileReferences(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetBoardGroup(boardID string, groupID string) (*model.BoardGroup, error) {
+	return s.getBoardGroup(s.db, boardID, groupID)
+
+}
+
+func (s *SQLStore) GetBoardGroupsForBoard(boardID string) ([]*model.BoardGroup, error) {
+	return s.getBoardGroupsForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetBoardGroupsForGroup(groupID string) ([]*model.BoardGroup, error) {
+	return s.getBoardGroupsForGroup(s.db, groupID)
+
+}
+
 func (s *SQLStore) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
 	return s.getBoardHistory(s.db, boardID, opts)
 
@@ -398,6 +653,11 @@ func (s *SQLStore) GetBoardMemberHistory(boardID string, userID string, limit ui
 
 }
 
+func (s *SQLStore) GetBoardMemberHistoryForUser(userID string) ([]*model.BoardMemberHistoryEntry, error) {
+	return s.getBoardMemberHistoryForUser(s.db, userID)
+
+}
+
 func (s *SQLStore) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	return s.getBoardsComplianceHistory(s.db, opts)
 
@@ -433,16 +693,71 @@ func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel
 
 }
 
+func (s *SQLStore) GetCustomBoardRole(id string) (*model.CustomBoardRole, error) {
+	return s.getCustomBoardRole(s.db, id)
+
+}
+
+func (s *SQLStore) GetCustomBoardRolesForTeam(teamID string) ([]*model.CustomBoardRole, error) {
+	return s.getCustomBoardRolesForTeam(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetDeletedBlocks(opts model.QueryTrashOptions) ([]*model.Block, error) {
+	return s.getDeletedBlocks(s.db, opts)
+
+}
+
+func (s *SQLStore) GetDeletedBoards(opts model.QueryTrashOptions) ([]*model.Board, error) {
+	return s.getDeletedBoards(s.db, opts)
+
+}
+
+func (s *SQLStore) GetFileBlocks(afterID string, limit int) ([]*model.Block, error) {
+	return s.getFileBlocks(s.db, afterID, limit)
+
+}
+
 func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
 	return s.getFileInfo(s.db, id)
 
 }
 
+func (s *SQLStore) GetFileInfosWithoutPreviews(extensions []string, afterID string, limit int) ([]*mmModel.FileInfo, error) {
+	return s.getFileInfosWithoutPreviews(s.db, extensions, afterID, limit)
+
+}
+
+func (s *SQLStore) GetFileUsage(teamID string) ([]*model.TeamFileUsage, error) {
+	return s.getFileUsage(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetFileUsageByFileID(fileID string) (*model.FileUsage, error) {
+	return s.getFileUsageByFileID(s.db, fileID)
+
+}
+
+func (s *SQLStore) GetFileUsagesCreatedBefore(createdBefore int64, afterID string, limit int) ([]*model.FileUsage, error) {
+	return s.getFileUsagesCreatedBefore(s.db, createdBefore, afterID, limit)
+
+}
+
 func (s *SQLStore) GetLicense() *mmModel.License {
 	return s.getLicense(s.db)
 
 }
 
+func (s *SQLStore) GetLoginAttempt(kind string, identifier string) (*model.LoginAttempt, error) {
+	return s.getLoginAttempt(s.db, kind, identifier)
+
+}
+
+func (s *SQLStore) GetLoginAttemptByUnlockToken(token string) (*model.LoginAttempt, error) {
+	return s.getLoginAttemptByUnlockToken(s.db, token)
+
+}
+
 func (s *SQLStore) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
 	return s.getMemberForBoard(s.db, boardID, userID)
 
@@ -478,6 +793,26 @@ func (s *SQLStore) GetSession(token string, expireTime int64) (*model.Session, e
 
 }
 
+func (s *SQLStore) GetSessionsForUser(userID string) ([]*model.Session, error) {
+	return s.getSessionsForUser(s.db, userID)
+
+}
+
+func (s *SQLStore) GetShareLink(id string) (*model.ShareLink, error) {
+	return s.getShareLink(s.db, id)
+
+}
+
+func (s *SQLStore) GetShareLinkByToken(token string) (*model.ShareLink, error) {
+	return s.getShareLinkByToken(s.db, token)
+
+}
+
+func (s *SQLStore) GetShareLinksForBoard(boardID string) ([]*model.ShareLink, error) {
+	return s.getShareLinksForBoard(s.db, boardID)
+
+}
+
 func (s *SQLStore) GetSharing(rootID string) (*model.Sharing, error) {
 	return s.getSharing(s.db, rootID)
 
@@ -523,11 +858,41 @@ func (s *SQLStore) GetTeam(ID string) (*model.Team, error) {
 
 }
 
+func (s *SQLStore) GetTeamBoardCount(teamID string) (int64, error) {
+	return s.getTeamBoardCount(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetTeamBySignupToken(signupToken string) (*model.Team, error) {
+	return s.getTeamBySignupToken(s.db, signupToken)
+
+}
+
 func (s *SQLStore) GetTeamCount() (int64, error) {
 	return s.getTeamCount(s.db)
 
 }
 
+func (s *SQLStore) GetTeamFileUsageSize(teamID string) (int64, error) {
+	return s.getTeamFileUsageSize(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetTeamMember(teamID string, userID string) (*model.TeamMember, error) {
+	return s.getTeamMember(s.db, teamID, userID)
+
+}
+
+func (s *SQLStore) GetTeamMemberCount(teamID string) (int64, error) {
+	return s.getTeamMemberCount(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetTeamMembers(teamID string) ([]*model.TeamMember, error) {
+	return s.getTeamMembers(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetTeamsForUser(userID string) ([]*model.Team, error) {
 	return s.getTeamsForUser(s.db, userID)
 
@@ -538,6 +903,16 @@ func (s *SQLStore) GetTemplateBoards(teamID string, userID string) ([]*model.Boa
 
 }
 
+func (s *SQLStore) GetUploadSession(id string) (*model.UploadSession, error) {
+	return s.getUploadSession(s.db, id)
+
+}
+
+func (s *SQLStore) GetUploadSessionsUpdatedBefore(updatedBefore int64, limit int) ([]*model.UploadSession, error) {
+	return s.getUploadSessionsUpdatedBefore(s.db, updatedBefore, limit)
+
+}
+
 func (s *SQLStore) GetUsedCardsCount() (int, error) {
 	return s.getUsedCardsCount(s.db)
 
@@ -553,6 +928,11 @@ func (s *SQLStore) GetUserByID(userID string) (*model.User, error) {
 
 }
 
+func (s *SQLStore) GetUserByIDIncludingDeactivated(userID string) (*model.User, error) {
+	return s.getUserByIDIncludingDeactivated(s.db, userID)
+
+}
+
 func (s *SQLStore) GetUserByUsername(username string) (*model.User, error) {
 	return s.getUserByUsername(s.db, username)
 
@@ -568,6 +948,26 @@ func (s *SQLStore) GetUserCategoryBoards(userID string, teamID string) ([]model.
 
 }
 
+func (s *SQLStore) GetUserGroup(id string) (*model.UserGroup, error) {
+	return s.getUserGroup(s.db, id)
+
+}
+
+func (s *SQLStore) GetUserGroupByName(teamID string, name string) (*model.UserGroup, error) {
+	return s.getUserGroupByName(s.db, teamID, name)
+
+}
+
+func (s *SQLStore) GetUserGroupMemberIDs(groupID string) ([]string, error) {
+	return s.getUserGroupMemberIDs(s.db, groupID)
+
+}
+
+func (s *SQLStore) GetUserGroupsForTeam(teamID string) ([]*model.UserGroup, error) {
+	return s.getUserGroupsForTeam(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetUserPreferences(userID string) (mmModel.Preferences, error) {
 	return s.getUserPreferences(s.db, userID)
 
@@ -588,6 +988,59 @@ func (s *SQLStore) GetUsersList(userIDs []string, showEmail bool, showName bool)
 
 }
 
+func (s *SQLStore) ImportBoardCards(boardID string, boardPatch *model.BoardPatch, newCards []*model.Block, cardPatches *model.BlockPatchBatch, userID string) (*model.BoardsAndBlocks, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.importBoardCards(s.db, boardID, boardPatch, newCards, cardPatches, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.importBoardCards(tx, boardID, boardPatch, newCards, cardPatches, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "ImportBoardCards"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) IncrementLoginAttempt(kind string, identifier string, now int64, windowStart int64) (*model.LoginAttempt, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.incrementLoginAttempt(s.db, kind, identifier, now, windowStart)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.incrementLoginAttempt(tx, kind, identifier, now, windowStart)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "IncrementLoginAttempt"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) IncrementShareLinkViews(id string) error {
+	return s.incrementShareLinkViews(s.db, id)
+
+}
+
 func (s *SQLStore) InsertBlock(block *model.Block, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.insertBlock(s.db, block, userID)
@@ -665,6 +1118,11 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) LockLoginAttempt(kind string, identifier string, lockedUntil int64, unlockToken string) error {
+	return s.lockLoginAttempt(s.db, kind, identifier, lockedUntil, unlockToken)
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -771,6 +1229,78 @@ func (s *SQLStore) PostMessage(message string, postType string, channelID string
 
 }
 
+func (s *SQLStore) PurgeDeletedBlock(blockID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeDeletedBlock(s.db, blockID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeDeletedBlock(tx, blockID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeDeletedBlock"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) PurgeDeletedBoard(boardID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeDeletedBoard(s.db, boardID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeDeletedBoard(tx, boardID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeDeletedBoard"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) PurgeTrash(deletedBefore int64, batchSize int64) (int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeTrash(s.db, deletedBefore, batchSize)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return 0, txErr
+	}
+	result, err := s.purgeTrash(tx, deletedBefore, batchSize)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeTrash"))
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) RefreshSession(session *model.Session) error {
 	return s.refreshSession(s.db, session)
 
@@ -791,6 +1321,54 @@ func (s *SQLStore) ReorderCategoryBoards(categoryID string, newBoardsOrder []str
 
 }
 
+func (s *SQLStore) RestoreBlockHistory(blockID string, updateAt int64, includeChildren bool, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreBlockHistory(s.db, blockID, updateAt, includeChildren, modifiedBy)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreBlockHistory(tx, blockID, updateAt, includeChildren, modifiedBy)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreBlockHistory"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) RestoreBoardHistory(boardID string, updateAt int64, modifiedBy string) (*model.HistoryRestoreResult, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreBoardHistory(s.db, boardID, updateAt, modifiedBy)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.restoreBoardHistory(tx, boardID, updateAt, modifiedBy)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreBoardHistory"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.runDataRetention(s.db, globalRetentionDate, batchSize)
@@ -815,16 +1393,31 @@ func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64)
 
 }
 
+func (s *SQLStore) SaveBoardGroup(boardGroup *model.BoardGroup) error {
+	return s.saveBoardGroup(s.db, boardGroup)
+
+}
+
 func (s *SQLStore) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 	return s.saveFileInfo(s.db, fileInfo)
 
 }
 
+func (s *SQLStore) SaveFileUsage(usage *model.FileUsage) error {
+	return s.saveFileUsage(s.db, usage)
+
+}
+
 func (s *SQLStore) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
 	return s.saveMember(s.db, bm)
 
 }
 
+func (s *SQLStore) SaveTeamMember(member *model.TeamMember) error {
+	return s.saveTeamMember(s.db, member)
+
+}
+
 func (s *SQLStore) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
 	return s.searchBoardsForUser(s.db, term, searchField, userID, includePublicBoards)
 
@@ -860,6 +1453,30 @@ func (s *SQLStore) SetSystemSetting(key string, value string) error {
 
 }
 
+func (s *SQLStore) UnarchiveBoard(boardID string, userID string) (*model.Board, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.unarchiveBoard(s.db, boardID, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.unarchiveBoard(tx, boardID, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "UnarchiveBoard"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) UndeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.undeleteBlock(s.db, blockID, modifiedBy)
@@ -908,6 +1525,16 @@ func (s *SQLStore) UndeleteBoard(boardID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) UpdateArchiveJob(job *model.ArchiveJob) error {
+	return s.updateArchiveJob(s.db, job)
+
+}
+
+func (s *SQLStore) UpdateBlockTitle(blockID string, title string, modifiedBy string) error {
+	return s.updateBlockTitle(s.db, blockID, title, modifiedBy)
+
+}
+
 func (s *SQLStore) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
 	return s.updateCardLimitTimestamp(s.db, cardLimit)
 
@@ -918,21 +1545,61 @@ func (s *SQLStore) UpdateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) UpdateCustomBoardRole(role *model.CustomBoardRole) error {
+	return s.updateCustomBoardRole(s.db, role)
+
+}
+
+func (s *SQLStore) UpdateFileInfoPreviews(fileInfo *mmModel.FileInfo) error {
+	return s.updateFileInfoPreviews(s.db, fileInfo)
+
+}
+
 func (s *SQLStore) UpdateSession(session *model.Session) error {
 	return s.updateSession(s.db, session)
 
 }
 
+func (s *SQLStore) UpdateShareLink(link *model.ShareLink) error {
+	return s.updateShareLink(s.db, link)
+
+}
+
 func (s *SQLStore) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
 	return s.updateSubscribersNotifiedAt(s.db, blockID, notifiedAt)
 
 }
 
+func (s *SQLStore) UpdateTeam(team *model.Team) error {
+	return s.updateTeam(s.db, team)
+
+}
+
+func (s *SQLStore) UpdateUploadSession(session *model.UploadSession) error {
+	return s.updateUploadSession(s.db, session)
+
+}
+
 func (s *SQLStore) UpdateUser(user *model.User) (*model.User, error) {
 	return s.updateUser(s.db, user)
 
 }
 
+func (s *SQLStore) UpdateUserActive(userID string, active bool) error {
+	return s.updateUserActive(s.db, userID, active)
+
+}
+
+func (s *SQLStore) UpdateUserGroup(group *model.UserGroup) error {
+	return s.updateUserGroup(s.db, group)
+
+}
+
+func (s *SQLStore) UpdateUserGuest(userID string, isGuest bool, expireAt int64) error {
+	return s.updateUserGuest(s.db, userID, isGuest, expireAt)
+
+}
+
 func (s *SQLStore) UpdateUserPassword(username string, password string) error {
 	return s.updateUserPassword(s.db, username, password)
 
@@ -943,6 +1610,11 @@ func (s *SQLStore) UpdateUserPasswordByID(userID string, password string) error
 
 }
 
+func (s *SQLStore) UpdateUserRoles(userID string, roles string) error {
+	return s.updateUserRoles(s.db, userID, roles)
+
+}
+
 func (s *SQLStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	return s.upsertNotificationHint(s.db, hint, notificationFreq)
 
@@ -27,17 +27,28 @@ func (s *SQLStore) getActiveUserCount(db sq.BaseRunner, updatedSecondsAgo int64)
 }
 
 func (s *SQLStore) getSession(db sq.BaseRunner, token string, expireTimeSeconds int64) (*model.Session, error) {
+	// the guest fields of the user are read along, so the sessions of the
+	// expired guests are rejected without another query
 	query := s.getQueryBuilder(db).
-		Select("id", "token", "user_id", "auth_service", "props").
-		From(s.tablePrefix + "sessions").
-		Where(sq.Eq{"token": token}).
-		Where(sq.Gt{"update_at": utils.GetMillis() - utils.SecondsToMillis(expireTimeSeconds)})
+		Select(
+			"s.id",
+			"s.token",
+			"s.user_id",
+			"s.auth_service",
+			"s.props",
+			"COALESCE(u.is_guest, false)",
+			"COALESCE(u.guest_expire_at, 0)",
+		).
+		From(s.tablePrefix + "sessions AS s").
+		LeftJoin(s.tablePrefix + "users AS u ON u.id = s.user_id").
+		Where(sq.Eq{"s.token": token}).
+		Where(sq.Gt{"s.update_at": utils.GetMillis() - utils.SecondsToMillis(expireTimeSeconds)})
 
 	row := query.QueryRow()
 	session := model.Session{}
 
 	var propsBytes []byte
-	err := row.Scan(&session.ID, &session.Token, &session.UserID, &session.AuthService, &propsBytes)
+	err := row.Scan(&session.ID, &session.Token, &session.UserID, &session.AuthService, &propsBytes, &session.IsGuest, &session.GuestExpireAt)
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +61,39 @@ func (s *SQLStore) getSession(db sq.BaseRunner, token string, expireTimeSeconds
 	return &session, nil
 }
 
+// getSessionsForUser returns the sessions of a user, most recently
+// active first.
+func (s *SQLStore) getSessionsForUser(db sq.BaseRunner, userID string) ([]*model.Session, error) {
+	query := s.getQueryBuilder(db).
+		Select("id", "token", "user_id", "auth_service", "props", "create_at", "update_at").
+		From(s.tablePrefix + "sessions").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("update_at DESC")
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	sessions := []*model.Session{}
+	for rows.Next() {
+		session := model.Session{}
+
+		var propsBytes []byte
+		err := rows.Scan(&session.ID, &session.Token, &session.UserID, &session.AuthService, &propsBytes, &session.CreateAt, &session.UpdateAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(propsBytes, &session.Props); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
 func (s *SQLStore) createSession(db sq.BaseRunner, session *model.Session) error {
 	now := utils.GetMillis()
 
@@ -102,6 +146,15 @@ func (s *SQLStore) deleteSession(db sq.BaseRunner, sessionID string) error {
 	return err
 }
 
+// deleteSessionsForUser revokes all the sessions of a user.
+func (s *SQLStore) deleteSessionsForUser(db sq.BaseRunner, userID string) error {
+	query := s.getQueryBuilder(db).Delete(s.tablePrefix + "sessions").
+		Where(sq.Eq{"user_id": userID})
+
+	_, err := query.Exec()
+	return err
+}
+
 func (s *SQLStore) cleanUpSessions(db sq.BaseRunner, expireTimeSeconds int64) error {
 	query := s.getQueryBuilder(db).Delete(s.tablePrefix + "sessions").
 		Where(sq.Lt{"update_at": utils.GetMillis() - utils.SecondsToMillis(expireTimeSeconds)})
@@ -0,0 +1,207 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func shareLinkFields() []string {
+	return []string{
+		"id",
+		"board_id",
+		"token",
+		"COALESCE(name, '')",
+		"COALESCE(view_id, '')",
+		"COALESCE(hidden_properties, '[]')",
+		"expires_at",
+		"COALESCE(password_hash, '')",
+		"view_count",
+		"last_viewed_at",
+		"created_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) shareLinksFromRows(rows *sql.Rows) ([]*model.ShareLink, error) {
+	links := []*model.ShareLink{}
+
+	for rows.Next() {
+		var link model.ShareLink
+		var hiddenProperties []byte
+		err := rows.Scan(
+			&link.ID,
+			&link.BoardID,
+			&link.Token,
+			&link.Name,
+			&link.ViewID,
+			&hiddenProperties,
+			&link.ExpiresAt,
+			&link.PasswordHash,
+			&link.ViewCount,
+			&link.LastViewedAt,
+			&link.CreatedBy,
+			&link.CreateAt,
+			&link.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("shareLinksFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+
+		if err = json.Unmarshal(hiddenProperties, &link.HiddenProperties); err != nil {
+			s.logger.Error("shareLinksFromRows unmarshal hidden properties error", mlog.Err(err))
+			return nil, err
+		}
+		link.HasPassword = link.PasswordHash != ""
+		links = append(links, &link)
+	}
+	return links, rows.Err()
+}
+
+func (s *SQLStore) createShareLink(db sq.BaseRunner, link *model.ShareLink) error {
+	hiddenProperties, err := json.Marshal(link.HiddenProperties)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "share_links").
+		SetMap(map[string]interface{}{
+			"id":                link.ID,
+			"board_id":          link.BoardID,
+			"token":             link.Token,
+			"name":              link.Name,
+			"view_id":           link.ViewID,
+			"hidden_properties": string(hiddenProperties),
+			"expires_at":        link.ExpiresAt,
+			"password_hash":     link.PasswordHash,
+			"view_count":        link.ViewCount,
+			"last_viewed_at":    link.LastViewedAt,
+			"created_by":        link.CreatedBy,
+			"create_at":         link.CreateAt,
+			"update_at":         link.UpdateAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createShareLink error", mlog.String("linkID", link.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateShareLink saves the settings of a share link. The token and the
+// view statistics are left untouched.
+func (s *SQLStore) updateShareLink(db sq.BaseRunner, link *model.ShareLink) error {
+	hiddenProperties, err := json.Marshal(link.HiddenProperties)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"share_links").
+		Set("name", link.Name).
+		Set("view_id", link.ViewID).
+		Set("hidden_properties", string(hiddenProperties)).
+		Set("expires_at", link.ExpiresAt).
+		Set("password_hash", link.PasswordHash).
+		Set("update_at", link.UpdateAt).
+		Where(sq.Eq{"id": link.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateShareLink error", mlog.String("linkID", link.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("share link ID=" + link.ID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getShareLinkBy(db sq.BaseRunner, condition sq.Eq, description string) (*model.ShareLink, error) {
+	query := s.getQueryBuilder(db).
+		Select(shareLinkFields()...).
+		From(s.tablePrefix + "share_links").
+		Where(condition)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getShareLink error", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	links, err := s.shareLinksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, model.NewErrNotFound(description)
+	}
+	return links[0], nil
+}
+
+func (s *SQLStore) getShareLink(db sq.BaseRunner, id string) (*model.ShareLink, error) {
+	return s.getShareLinkBy(db, sq.Eq{"id": id}, "share link ID="+id)
+}
+
+func (s *SQLStore) getShareLinkByToken(db sq.BaseRunner, token string) (*model.ShareLink, error) {
+	return s.getShareLinkBy(db, sq.Eq{"token": token}, "share link token")
+}
+
+func (s *SQLStore) getShareLinksForBoard(db sq.BaseRunner, boardID string) ([]*model.ShareLink, error) {
+	query := s.getQueryBuilder(db).
+		Select(shareLinkFields()...).
+		From(s.tablePrefix+"share_links").
+		Where(sq.Eq{"board_id": boardID}).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getShareLinksForBoard error", mlog.String("boardID", boardID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.shareLinksFromRows(rows)
+}
+
+// incrementShareLinkViews counts an opening of a share link.
+func (s *SQLStore) incrementShareLinkViews(db sq.BaseRunner, id string) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"share_links").
+		Set("view_count", sq.Expr("view_count + 1")).
+		Set("last_viewed_at", utils.GetMillis()).
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("incrementShareLinkViews error", mlog.String("linkID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteShareLink(db sq.BaseRunner, id string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "share_links").
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteShareLink error", mlog.String("linkID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
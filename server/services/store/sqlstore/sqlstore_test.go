@@ -18,6 +18,7 @@ func TestSQLStore(t *testing.T) {
 	t.Run("SystemStore", func(t *testing.T) { storetests.StoreTestSystemStore(t, SetupTests) })
 	t.Run("UserStore", func(t *testing.T) { storetests.StoreTestUserStore(t, SetupTests) })
 	t.Run("SessionStore", func(t *testing.T) { storetests.StoreTestSessionStore(t, SetupTests) })
+	t.Run("LoginAttemptsStore", func(t *testing.T) { storetests.StoreTestLoginAttemptsStore(t, SetupTests) })
 	t.Run("TeamStore", func(t *testing.T) { storetests.StoreTestTeamStore(t, SetupTests) })
 	t.Run("BoardStore", func(t *testing.T) { storetests.StoreTestBoardStore(t, SetupTests) })
 	t.Run("BoardsAndBlocksStore", func(t *testing.T) { storetests.StoreTestBoardsAndBlocksStore(t, SetupTests) })
@@ -29,6 +30,12 @@ func TestSQLStore(t *testing.T) {
 	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
 	t.Run("StoreTestCategoryBoardsStore", func(t *testing.T) { storetests.StoreTestCategoryBoardsStore(t, SetupTests) })
 	t.Run("ComplianceHistoryStore", func(t *testing.T) { storetests.StoreTestComplianceHistoryStore(t, SetupTests) })
+	t.Run("TrashStore", func(t *testing.T) { storetests.StoreTestTrashStore(t, SetupTests) })
+	t.Run("ArchiveJobsStore", func(t *testing.T) { storetests.StoreTestArchiveJobsStore(t, SetupTests) })
+	t.Run("UploadSessionsStore", func(t *testing.T) { storetests.StoreTestUploadSessionsStore(t, SetupTests) })
+	t.Run("ShareLinksStore", func(t *testing.T) { storetests.StoreTestShareLinksStore(t, SetupTests) })
+	t.Run("CustomBoardRolesStore", func(t *testing.T) { storetests.StoreTestCustomBoardRolesStore(t, SetupTests) })
+	t.Run("UserGroupsStore", func(t *testing.T) { storetests.StoreTestUserGroupsStore(t, SetupTests) })
 }
 
 //  tests for  utility functions inside sqlstore.go
@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
@@ -15,11 +16,19 @@ import (
 var (
 	teamFields = []string{
 		"id",
+		"COALESCE(title, '')",
 		"signup_token",
 		"COALESCE(settings, '{}')",
 		"modified_by",
 		"update_at",
 	}
+
+	teamMemberFields = []string{
+		"team_id",
+		"user_id",
+		"COALESCE(scheme_admin, false)",
+		"create_at",
+	}
 )
 
 func (s *SQLStore) upsertTeamSignupToken(db sq.BaseRunner, team model.Team) error {
@@ -95,13 +104,7 @@ func (s *SQLStore) getTeam(db sq.BaseRunner, id string) (*model.Team, error) {
 	var settingsJSON string
 
 	query := s.getQueryBuilder(db).
-		Select(
-			"id",
-			"signup_token",
-			"COALESCE(settings, '{}')",
-			"modified_by",
-			"update_at",
-		).
+		Select(teamFields...).
 		From(s.tablePrefix + "teams").
 		Where(sq.Eq{"id": id})
 	row := query.QueryRow()
@@ -109,6 +112,7 @@ func (s *SQLStore) getTeam(db sq.BaseRunner, id string) (*model.Team, error) {
 
 	err := row.Scan(
 		&team.ID,
+		&team.Title,
 		&team.SignupToken,
 		&settingsJSON,
 		&team.ModifiedBy,
@@ -127,8 +131,213 @@ func (s *SQLStore) getTeam(db sq.BaseRunner, id string) (*model.Team, error) {
 	return &team, nil
 }
 
-func (s *SQLStore) getTeamsForUser(db sq.BaseRunner, _ string) ([]*model.Team, error) {
-	return s.getAllTeams(db)
+// getTeamBySignupToken returns the team a signup token lets users join.
+func (s *SQLStore) getTeamBySignupToken(db sq.BaseRunner, signupToken string) (*model.Team, error) {
+	query := s.getQueryBuilder(db).
+		Select(teamFields...).
+		From(s.tablePrefix + "teams").
+		Where(sq.Eq{"signup_token": signupToken})
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("ERROR GetTeamBySignupToken", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	teams, err := s.teamsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, model.NewErrNotFound("team signup token")
+	}
+	return teams[0], nil
+}
+
+func (s *SQLStore) getTeamsForUser(db sq.BaseRunner, userID string) ([]*model.Team, error) {
+	query := s.getQueryBuilder(db).
+		Select(teamFields...).
+		From(s.tablePrefix + "teams").
+		Where(sq.Expr("id IN (SELECT team_id FROM "+s.tablePrefix+"team_members WHERE user_id = ?)", userID)).
+		OrderBy("id")
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("ERROR GetTeamsForUser", mlog.String("userID", userID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.teamsFromRows(rows)
+}
+
+func (s *SQLStore) createTeam(db sq.BaseRunner, team *model.Team) error {
+	settingsJSON, err := json.Marshal(team.Settings)
+	if err != nil {
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "teams").
+		SetMap(map[string]interface{}{
+			"id":           team.ID,
+			"title":        team.Title,
+			"signup_token": team.SignupToken,
+			"settings":     settingsJSON,
+			"modified_by":  team.ModifiedBy,
+			"update_at":    team.UpdateAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createTeam error", mlog.String("teamID", team.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateTeam saves the title of a team. The signup token and the settings
+// have their own upserts.
+func (s *SQLStore) updateTeam(db sq.BaseRunner, team *model.Team) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"teams").
+		Set("title", team.Title).
+		Set("modified_by", team.ModifiedBy).
+		Set("update_at", team.UpdateAt).
+		Where(sq.Eq{"id": team.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateTeam error", mlog.String("teamID", team.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("team ID=" + team.ID)
+	}
+	return nil
+}
+
+// deleteTeam deletes a team and its memberships.
+func (s *SQLStore) deleteTeam(db sq.BaseRunner, id string) error {
+	deleteMembers := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "team_members").
+		Where(sq.Eq{"team_id": id})
+	if _, err := deleteMembers.Exec(); err != nil {
+		s.logger.Error("deleteTeam members error", mlog.String("teamID", id), mlog.Err(err))
+		return err
+	}
+
+	deleteTeam := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "teams").
+		Where(sq.Eq{"id": id})
+	if _, err := deleteTeam.Exec(); err != nil {
+		s.logger.Error("deleteTeam error", mlog.String("teamID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) teamMembersFromRows(rows *sql.Rows) ([]*model.TeamMember, error) {
+	members := []*model.TeamMember{}
+
+	for rows.Next() {
+		var member model.TeamMember
+		err := rows.Scan(
+			&member.TeamID,
+			&member.UserID,
+			&member.SchemeAdmin,
+			&member.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+	return members, rows.Err()
+}
+
+func (s *SQLStore) getTeamMembers(db sq.BaseRunner, teamID string) ([]*model.TeamMember, error) {
+	query := s.getQueryBuilder(db).
+		Select(teamMemberFields...).
+		From(s.tablePrefix+"team_members").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("create_at", "user_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getTeamMembers error", mlog.String("teamID", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.teamMembersFromRows(rows)
+}
+
+func (s *SQLStore) getTeamMember(db sq.BaseRunner, teamID, userID string) (*model.TeamMember, error) {
+	query := s.getQueryBuilder(db).
+		Select(teamMemberFields...).
+		From(s.tablePrefix + "team_members").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.Eq{"user_id": userID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getTeamMember error", mlog.String("teamID", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	members, err := s.teamMembersFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, model.NewErrNotFound(fmt.Sprintf("team member TeamID=%s UserID=%s", teamID, userID))
+	}
+	return members[0], nil
+}
+
+// saveTeamMember adds a user to a team, or updates the role of a member.
+func (s *SQLStore) saveTeamMember(db sq.BaseRunner, member *model.TeamMember) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "team_members").
+		SetMap(map[string]interface{}{
+			"team_id":      member.TeamID,
+			"user_id":      member.UserID,
+			"scheme_admin": member.SchemeAdmin,
+			"create_at":    member.CreateAt,
+		})
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE scheme_admin = ?", member.SchemeAdmin)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (team_id, user_id)
+			 DO UPDATE SET scheme_admin = EXCLUDED.scheme_admin`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("saveTeamMember error", mlog.String("teamID", member.TeamID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteTeamMember(db sq.BaseRunner, teamID, userID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "team_members").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.Eq{"user_id": userID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteTeamMember error", mlog.String("teamID", teamID), mlog.Err(err))
+		return err
+	}
+	return nil
 }
 
 func (s *SQLStore) getTeamCount(db sq.BaseRunner) (int64, error) {
@@ -156,6 +365,37 @@ func (s *SQLStore) getTeamCount(db sq.BaseRunner) (int64, error) {
 	return count, nil
 }
 
+// getTeamBoardCount returns the number of boards and templates of a team,
+// not counting the deleted ones.
+func (s *SQLStore) getTeamBoardCount(db sq.BaseRunner, teamID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Select("COUNT(*) AS count").
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.Eq{"delete_at": 0})
+
+	var count int64
+	if err := query.QueryRow().Scan(&count); err != nil {
+		s.logger.Error("ERROR GetTeamBoardCount", mlog.String("teamID", teamID), mlog.Err(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *SQLStore) getTeamMemberCount(db sq.BaseRunner, teamID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Select("COUNT(*) AS count").
+		From(s.tablePrefix + "team_members").
+		Where(sq.Eq{"team_id": teamID})
+
+	var count int64
+	if err := query.QueryRow().Scan(&count); err != nil {
+		s.logger.Error("ERROR GetTeamMemberCount", mlog.String("teamID", teamID), mlog.Err(err))
+		return 0, err
+	}
+	return count, nil
+}
+
 func (s *SQLStore) teamsFromRows(rows *sql.Rows) ([]*model.Team, error) {
 	teams := []*model.Team{}
 
@@ -165,6 +405,7 @@ func (s *SQLStore) teamsFromRows(rows *sql.Rows) ([]*model.Team, error) {
 
 		err := rows.Scan(
 			&team.ID,
+			&team.Title,
 			&team.SignupToken,
 			&settingsBytes,
 			&team.ModifiedBy,
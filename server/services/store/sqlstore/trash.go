@@ -0,0 +1,278 @@
+package sqlstore
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// trashBoardTables are the tables that hold data of a board that is
+// permanently removed from the trash.
+var trashBoardTables = []RetentionTableDeletionInfo{
+	{
+		Table:         "blocks_history",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "boards_history",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	},
+	{
+		Table:         "board_members",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "board_members_history",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "board_groups",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "sharing",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	},
+	{
+		Table:         "share_links",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "category_boards",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+}
+
+// getDeletedBoards returns the latest history entry of the boards
+// that are deleted. The modified_by and delete_at of each entry are
+// the user that deleted the board and when.
+func (s *SQLStore) getDeletedBoards(db sq.BaseRunner, opts model.QueryTrashOptions) ([]*model.Board, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardHistoryFields()...).
+		From(s.tablePrefix + "boards_history AS bh").
+		Where(sq.Gt{"bh.delete_at": 0}).
+		Where("NOT EXISTS (SELECT 1 FROM " + s.tablePrefix + "boards_history AS bh2 WHERE bh2.id = bh.id AND bh2.insert_at > bh.insert_at)").
+		Where("bh.id NOT IN (SELECT id FROM " + s.tablePrefix + "boards)").
+		OrderBy("bh.delete_at DESC")
+
+	if opts.TeamID != "" {
+		query = query.Where(sq.Eq{"bh.team_id": opts.TeamID})
+	}
+
+	if opts.DeletedBefore != 0 {
+		query = query.Where(sq.Lt{"bh.delete_at": opts.DeletedBefore})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDeletedBoards ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boards, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueBoards(boards), nil
+}
+
+// getDeletedBlocks returns the latest history entry of the blocks
+// that are deleted. The modified_by and delete_at of each entry are
+// the user that deleted the block and when.
+func (s *SQLStore) getDeletedBlocks(db sq.BaseRunner, opts model.QueryTrashOptions) ([]*model.Block, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("bh")...).
+		From(s.tablePrefix + "blocks_history AS bh").
+		Where(sq.Gt{"bh.delete_at": 0}).
+		Where("NOT EXISTS (SELECT 1 FROM " + s.tablePrefix + "blocks_history AS bh2 WHERE bh2.id = bh.id AND bh2.insert_at > bh.insert_at)").
+		Where("bh.id NOT IN (SELECT id FROM " + s.tablePrefix + "blocks)").
+		OrderBy("bh.delete_at DESC")
+
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"bh.board_id": opts.BoardID})
+	}
+
+	if opts.BlockType != "" && opts.BlockType != model.TypeUnknown {
+		query = query.Where(sq.Eq{"bh.type": opts.BlockType})
+	}
+
+	if opts.DeletedBefore != 0 {
+		query = query.Where(sq.Lt{"bh.delete_at": opts.DeletedBefore})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDeletedBlocks ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	blocks, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueBlocks(blocks), nil
+}
+
+// purgeDeletedBoard permanently removes a deleted board, its blocks
+// and its members.
+func (s *SQLStore) purgeDeletedBoard(db sq.BaseRunner, boardID string) error {
+	if _, err := s.getBoard(db, boardID); err == nil {
+		return model.NewErrBadRequest(fmt.Sprintf("board %s is not deleted", boardID))
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	history, err := s.getBoardHistory(db, boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return model.NewErrNotFound("deleted board ID=" + boardID)
+	}
+
+	_, err = s.purgeBoards(db, []string{boardID}, 0)
+	return err
+}
+
+// purgeDeletedBlock permanently removes a deleted block and its
+// deleted children.
+func (s *SQLStore) purgeDeletedBlock(db sq.BaseRunner, blockID string) error {
+	if _, err := s.getBlock(db, blockID); err == nil {
+		return model.NewErrBadRequest(fmt.Sprintf("block %s is not deleted", blockID))
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	history, err := s.getBlockHistory(db, blockID, model.QueryBlockHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return model.NewErrNotFound("deleted block ID=" + blockID)
+	}
+
+	children, _, err := s.getBlockHistoryNewestChildren(db, blockID, model.QueryBlockHistoryChildOptions{})
+	if err != nil {
+		return err
+	}
+
+	blockIDs := []string{blockID}
+	for _, child := range children {
+		// children that were restored on their own are kept
+		if child.DeleteAt != 0 {
+			blockIDs = append(blockIDs, child.ID)
+		}
+	}
+
+	_, err = s.purgeBlocks(db, blockIDs, 0)
+	return err
+}
+
+// purgeTrash permanently removes the boards and blocks that were
+// deleted before a given date.
+func (s *SQLStore) purgeTrash(db sq.BaseRunner, deletedBefore int64, batchSize int64) (int64, error) {
+	boards, err := s.getDeletedBoards(db, model.QueryTrashOptions{DeletedBefore: deletedBefore})
+	if err != nil {
+		return 0, err
+	}
+	boardIDs := make([]string, len(boards))
+	for i, board := range boards {
+		boardIDs[i] = board.ID
+	}
+
+	blocks, err := s.getDeletedBlocks(db, model.QueryTrashOptions{DeletedBefore: deletedBefore})
+	if err != nil {
+		return 0, err
+	}
+	blockIDs := make([]string, len(blocks))
+	for i, block := range blocks {
+		blockIDs[i] = block.ID
+	}
+
+	var totalAffected int64
+	if len(boardIDs) > 0 {
+		affected, err := s.purgeBoards(db, boardIDs, batchSize)
+		if err != nil {
+			return totalAffected, err
+		}
+		totalAffected += affected
+	}
+
+	if len(blockIDs) > 0 {
+		affected, err := s.purgeBlocks(db, blockIDs, batchSize)
+		if err != nil {
+			return totalAffected, err
+		}
+		totalAffected += affected
+	}
+
+	s.logger.Info("Trash purged",
+		mlog.Int("boards", len(boardIDs)),
+		mlog.Int("blocks", len(blockIDs)),
+		mlog.Int("TotalAffected", totalAffected),
+	)
+	return totalAffected, nil
+}
+
+func (s *SQLStore) purgeBoards(db sq.BaseRunner, boardIDs []string, batchSize int64) (int64, error) {
+	var totalAffected int64
+	for _, table := range trashBoardTables {
+		affected, err := s.genericRetentionPoliciesDeletion(db, table, boardIDs, batchSize)
+		if err != nil {
+			return totalAffected, err
+		}
+		totalAffected += affected
+	}
+	return totalAffected, nil
+}
+
+func (s *SQLStore) purgeBlocks(db sq.BaseRunner, blockIDs []string, batchSize int64) (int64, error) {
+	table := RetentionTableDeletionInfo{
+		Table:         "blocks_history",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	}
+	return s.genericRetentionPoliciesDeletion(db, table, blockIDs, batchSize)
+}
+
+// uniqueBoards removes the duplicated entries that history queries
+// may return when two entries share the same insert_at.
+func uniqueBoards(boards []*model.Board) []*model.Board {
+	seen := map[string]bool{}
+	result := make([]*model.Board, 0, len(boards))
+	for _, board := range boards {
+		if !seen[board.ID] {
+			seen[board.ID] = true
+			result = append(result, board)
+		}
+	}
+	return result
+}
+
+// uniqueBlocks removes the duplicated entries that history queries
+// may return when two entries share the same insert_at.
+func uniqueBlocks(blocks []*model.Block) []*model.Block {
+	seen := map[string]bool{}
+	result := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if !seen[block.ID] {
+			seen[block.ID] = true
+			result = append(result, block)
+		}
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func uploadSessionFields() []string {
+	return []string{
+		"id",
+		"team_id",
+		"board_id",
+		"user_id",
+		"filename",
+		"path",
+		"file_size",
+		"file_offset",
+		"COALESCE(checksum, '')",
+		"create_at",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) uploadSessionsFromRows(rows *sql.Rows) ([]*model.UploadSession, error) {
+	sessions := []*model.UploadSession{}
+
+	for rows.Next() {
+		var session model.UploadSession
+		err := rows.Scan(
+			&session.ID,
+			&session.TeamID,
+			&session.BoardID,
+			&session.UserID,
+			&session.Filename,
+			&session.Path,
+			&session.FileSize,
+			&session.FileOffset,
+			&session.Checksum,
+			&session.CreateAt,
+			&session.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("uploadSessionsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *SQLStore) createUploadSession(db sq.BaseRunner, session *model.UploadSession) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "upload_sessions").
+		SetMap(map[string]interface{}{
+			"id":          session.ID,
+			"team_id":     session.TeamID,
+			"board_id":    session.BoardID,
+			"user_id":     session.UserID,
+			"filename":    session.Filename,
+			"path":        session.Path,
+			"file_size":   session.FileSize,
+			"file_offset": session.FileOffset,
+			"checksum":    session.Checksum,
+			"create_at":   session.CreateAt,
+			"update_at":   session.UpdateAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createUploadSession error", mlog.String("sessionID", session.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// updateUploadSession saves the progress of an upload session and
+// releases its claim.
+func (s *SQLStore) updateUploadSession(db sq.BaseRunner, session *model.UploadSession) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"upload_sessions").
+		Set("file_offset", session.FileOffset).
+		Set("update_at", session.UpdateAt).
+		Set("claimed_until", 0).
+		Where(sq.Eq{"id": session.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateUploadSession error", mlog.String("sessionID", session.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("upload session ID=" + session.ID)
+	}
+	return nil
+}
+
+// claimUploadSession claims an upload session at the given offset until
+// claimedUntil, so a single server receives the chunk starting there. The
+// claim fails with a conflict error if the session is at another offset
+// or claimed and the claim hasn't expired.
+func (s *SQLStore) claimUploadSession(db sq.BaseRunner, id string, fileOffset, now, claimedUntil int64) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"upload_sessions").
+		Set("claimed_until", claimedUntil).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"file_offset": fileOffset}).
+		Where(sq.LtOrEq{"claimed_until": now})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("claimUploadSession error", mlog.String("sessionID", id), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrConflict(fmt.Sprintf("upload session ID=%s is not at offset %d or is receiving another chunk", id, fileOffset))
+	}
+	return nil
+}
+
+func (s *SQLStore) getUploadSession(db sq.BaseRunner, id string) (*model.UploadSession, error) {
+	query := s.getQueryBuilder(db).
+		Select(uploadSessionFields()...).
+		From(s.tablePrefix + "upload_sessions").
+		Where(sq.Eq{"id": id})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getUploadSession error", mlog.String("sessionID", id), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	sessions, err := s.uploadSessionsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, model.NewErrNotFound("upload session ID=" + id)
+	}
+	return sessions[0], nil
+}
+
+// getUploadSessionsUpdatedBefore returns the upload sessions without
+// progress since the given time.
+func (s *SQLStore) getUploadSessionsUpdatedBefore(db sq.BaseRunner, updatedBefore int64, limit int) ([]*model.UploadSession, error) {
+	query := s.getQueryBuilder(db).
+		Select(uploadSessionFields()...).
+		From(s.tablePrefix+"upload_sessions").
+		Where(sq.Lt{"update_at": updatedBefore}).
+		OrderBy("update_at", "id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getUploadSessionsUpdatedBefore error", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.uploadSessionsFromRows(rows)
+}
+
+func (s *SQLStore) deleteUploadSession(db sq.BaseRunner, id string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "upload_sessions").
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteUploadSession error", mlog.String("sessionID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
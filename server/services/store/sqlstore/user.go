@@ -57,20 +57,27 @@ func (s *SQLStore) getUserByCondition(db sq.BaseRunner, condition sq.Eq) (*model
 	return users[0], nil
 }
 
+func userFields() []string {
+	return []string{
+		"id",
+		"username",
+		"email",
+		"password",
+		"mfa_secret",
+		"auth_service",
+		"auth_data",
+		"create_at",
+		"update_at",
+		"delete_at",
+		"COALESCE(roles, '')",
+		"COALESCE(is_guest, false)",
+		"COALESCE(guest_expire_at, 0)",
+	}
+}
+
 func (s *SQLStore) getUsersByCondition(db sq.BaseRunner, condition interface{}, limit uint64) ([]*model.User, error) {
 	query := s.getQueryBuilder(db).
-		Select(
-			"id",
-			"username",
-			"email",
-			"password",
-			"mfa_secret",
-			"auth_service",
-			"auth_data",
-			"create_at",
-			"update_at",
-			"delete_at",
-		).
+		Select(userFields()...).
 		From(s.tablePrefix + "users").
 		Where(sq.Eq{"delete_at": 0}).
 		Where(condition)
@@ -127,11 +134,10 @@ func (s *SQLStore) createUser(db sq.BaseRunner, user *model.User) (*model.User,
 	now := utils.GetMillis()
 	user.CreateAt = now
 	user.UpdateAt = now
-	user.DeleteAt = 0
 
 	query := s.getQueryBuilder(db).Insert(s.tablePrefix+"users").
-		Columns("id", "username", "email", "password", "mfa_secret", "auth_service", "auth_data", "create_at", "update_at", "delete_at").
-		Values(user.ID, user.Username, user.Email, user.Password, user.MfaSecret, user.AuthService, user.AuthData, user.CreateAt, user.UpdateAt, user.DeleteAt)
+		Columns("id", "username", "email", "password", "mfa_secret", "auth_service", "auth_data", "create_at", "update_at", "delete_at", "roles", "is_guest", "guest_expire_at").
+		Values(user.ID, user.Username, user.Email, user.Password, user.MfaSecret, user.AuthService, user.AuthData, user.CreateAt, user.UpdateAt, user.DeleteAt, user.Roles, user.IsGuest, user.GuestExpireAt)
 
 	_, err := query.Exec()
 	return user, err
@@ -214,8 +220,149 @@ func (s *SQLStore) updateUserPasswordByID(db sq.BaseRunner, userID, password str
 	return nil
 }
 
-func (s *SQLStore) getUsersByTeam(db sq.BaseRunner, _ string, _ string, _, _ bool) ([]*model.User, error) {
-	users, err := s.getUsersByCondition(db, nil, 0)
+// getAllUsers returns a page of the users of the server, ordered by
+// creation time.
+func (s *SQLStore) getAllUsers(db sq.BaseRunner, opts model.UserQueryOptions) ([]*model.User, error) {
+	query := s.getQueryBuilder(db).
+		Select(userFields()...).
+		From(s.tablePrefix+"users").
+		OrderBy("create_at", "id")
+
+	if !opts.IncludeDeactivated {
+		query = query.Where(sq.Eq{"delete_at": 0})
+	}
+	if opts.PerPage > 0 {
+		query = query.
+			Limit(uint64(opts.PerPage)).
+			Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAllUsers ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.usersFromRows(rows)
+}
+
+// getUserByIDIncludingDeactivated returns a user, even if deactivated.
+func (s *SQLStore) getUserByIDIncludingDeactivated(db sq.BaseRunner, userID string) (*model.User, error) {
+	query := s.getQueryBuilder(db).
+		Select(userFields()...).
+		From(s.tablePrefix + "users").
+		Where(sq.Eq{"id": userID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getUserByIDIncludingDeactivated ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	users, err := s.usersFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, model.NewErrNotFound("user ID=" + userID)
+	}
+	return users[0], nil
+}
+
+func (s *SQLStore) updateUserRoles(db sq.BaseRunner, userID, roles string) error {
+	query := s.getQueryBuilder(db).Update(s.tablePrefix+"users").
+		Set("roles", roles).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": userID})
+
+	return s.execUserUpdate(query, userID)
+}
+
+// updateUserGuest makes a user a guest with an expiration time, or a
+// regular user.
+func (s *SQLStore) updateUserGuest(db sq.BaseRunner, userID string, isGuest bool, expireAt int64) error {
+	if !isGuest {
+		expireAt = 0
+	}
+
+	query := s.getQueryBuilder(db).Update(s.tablePrefix+"users").
+		Set("is_guest", isGuest).
+		Set("guest_expire_at", expireAt).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": userID})
+
+	return s.execUserUpdate(query, userID)
+}
+
+// updateUserActive deactivates a user, or reactivates it.
+func (s *SQLStore) updateUserActive(db sq.BaseRunner, userID string, active bool) error {
+	now := utils.GetMillis()
+	deleteAt := now
+	if active {
+		deleteAt = 0
+	}
+
+	query := s.getQueryBuilder(db).Update(s.tablePrefix+"users").
+		Set("delete_at", deleteAt).
+		Set("update_at", now).
+		Where(sq.Eq{"id": userID})
+
+	return s.execUserUpdate(query, userID)
+}
+
+func (s *SQLStore) execUserUpdate(query sq.UpdateBuilder, userID string) error {
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowCount < 1 {
+		return model.NewErrNotFound("user ID=" + userID)
+	}
+
+	return nil
+}
+
+// teamMembersCondition restricts a users query to the members of a team,
+// or to no condition at all if the team ID is empty or the team has no
+// members, as those teams are open to every user.
+func (s *SQLStore) teamMembersCondition(teamID string) sq.Sqlizer {
+	if teamID == "" {
+		return sq.And{}
+	}
+	return sq.Or{
+		sq.Expr("id IN (SELECT user_id FROM "+s.tablePrefix+"team_members WHERE team_id = ?)", teamID),
+		sq.Expr("NOT EXISTS (SELECT 1 FROM "+s.tablePrefix+"team_members WHERE team_id = ?)", teamID),
+	}
+}
+
+// guestVisibleUsersCondition restricts a users query to the users a
+// guest can see, the members of the boards of the team it belongs to, or
+// to no condition at all if the guest ID is empty.
+func (s *SQLStore) guestVisibleUsersCondition(teamID, guestID string) sq.Sqlizer {
+	if guestID == "" {
+		return sq.And{}
+	}
+
+	return sq.Expr("id IN (SELECT BM.user_id FROM "+s.tablePrefix+"board_members AS BM"+
+		" JOIN "+s.tablePrefix+"board_members AS GBM ON GBM.board_id = BM.board_id"+
+		" JOIN "+s.tablePrefix+"boards AS B ON B.id = BM.board_id"+
+		" WHERE GBM.user_id = ? AND B.team_id = ? AND B.delete_at = 0)", guestID, teamID)
+}
+
+func (s *SQLStore) getUsersByTeam(db sq.BaseRunner, teamID string, asGuestID string, _, _ bool) ([]*model.User, error) {
+	condition := sq.And{
+		s.teamMembersCondition(teamID),
+		s.guestVisibleUsersCondition(teamID, asGuestID),
+	}
+	users, err := s.getUsersByCondition(db, condition, 0)
 	if model.IsErrNotFound(err) {
 		return []*model.User{}, nil
 	}
@@ -223,8 +370,13 @@ func (s *SQLStore) getUsersByTeam(db sq.BaseRunner, _ string, _ string, _, _ boo
 	return users, err
 }
 
-func (s *SQLStore) searchUsersByTeam(db sq.BaseRunner, _ string, searchQuery string, _ string, _, _, _ bool) ([]*model.User, error) {
-	users, err := s.getUsersByCondition(db, &sq.Like{"username": "%" + searchQuery + "%"}, 10)
+func (s *SQLStore) searchUsersByTeam(db sq.BaseRunner, teamID string, searchQuery string, asGuestID string, _, _, _ bool) ([]*model.User, error) {
+	condition := sq.And{
+		s.teamMembersCondition(teamID),
+		s.guestVisibleUsersCondition(teamID, asGuestID),
+		sq.Like{"username": "%" + searchQuery + "%"},
+	}
+	users, err := s.getUsersByCondition(db, condition, 10)
 	if model.IsErrNotFound(err) {
 		return []*model.User{}, nil
 	}
@@ -249,6 +401,9 @@ func (s *SQLStore) usersFromRows(rows *sql.Rows) ([]*model.User, error) {
 			&user.CreateAt,
 			&user.UpdateAt,
 			&user.DeleteAt,
+			&user.Roles,
+			&user.IsGuest,
+			&user.GuestExpireAt,
 		)
 		if err != nil {
 			return nil, err
@@ -353,8 +508,29 @@ func (s *SQLStore) deleteUserPreference(db sq.BaseRunner, preference mmModel.Pre
 	return nil
 }
 
+// canSeeUser returns true if a user can see another one. The guests can
+// only see the users they share a board with.
 func (s *SQLStore) canSeeUser(db sq.BaseRunner, seerID string, seenID string) (bool, error) {
-	return true, nil
+	seer, err := s.getUserByID(db, seerID)
+	if err != nil {
+		return false, err
+	}
+	if !seer.IsGuest || seerID == seenID {
+		return true, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("count(*)").
+		From(s.tablePrefix + "board_members AS BM1").
+		Join(s.tablePrefix + "board_members AS BM2 ON BM1.board_id = BM2.board_id").
+		Where(sq.Eq{"BM1.user_id": seerID}).
+		Where(sq.Eq{"BM2.user_id": seenID})
+
+	var count int
+	if err := query.QueryRow().Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 func (s *SQLStore) sendMessage(db sq.BaseRunner, message, postType string, receipts []string) error {
@@ -0,0 +1,225 @@
+package sqlstore
+
+import (
+	"regexp"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+type userColumn struct {
+	Table  string
+	Column string
+}
+
+// userAuthorColumns are the columns that reference the users as authors
+// of the content. When a user is deleted, the content is kept and
+// attributed to the anonymous user that replaces it.
+var userAuthorColumns = []userColumn{
+	{"blocks", "created_by"},
+	{"blocks", "modified_by"},
+	{"blocks_history", "created_by"},
+	{"blocks_history", "modified_by"},
+	{"boards", "created_by"},
+	{"boards", "modified_by"},
+	{"boards_history", "created_by"},
+	{"boards_history", "modified_by"},
+	{"board_members_history", "user_id"},
+	{"sharing", "modified_by"},
+	{"share_links", "created_by"},
+	{"board_invitations", "created_by"},
+	{"board_invitations", "used_by"},
+	{"board_roles", "created_by"},
+	{"user_groups", "created_by"},
+	{"notification_hints", "modified_by_id"},
+}
+
+// userOwnedColumns are the columns that reference the users as owners
+// of the rows. When a user is deleted, the rows are removed.
+var userOwnedColumns = []userColumn{
+	{"board_members", "user_id"},
+	{"team_members", "user_id"},
+	{"user_group_members", "user_id"},
+	{"subscriptions", "subscriber_id"},
+	{"categories", "user_id"},
+	{"category_boards", "user_id"},
+	{"preferences", "userid"},
+	{"upload_sessions", "user_id"},
+	{"sessions", "user_id"},
+	{"login_attempts", "identifier"},
+}
+
+// anonymizeUser replaces a user by an anonymous deactivated user. The
+// content the user authored, and the history, are attributed to the
+// anonymous user, including its mentions and the card properties
+// referencing it, and the rows owned by the user are removed.
+func (s *SQLStore) anonymizeUser(db sq.BaseRunner, userID string, anonymousUser *model.User) (*model.UserDeletionReport, error) {
+	user, err := s.getUserByIDIncludingDeactivated(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := model.NewUserDeletionReport(userID, anonymousUser)
+
+	for _, uc := range userAuthorColumns {
+		query := s.getQueryBuilder(db).
+			Update(s.tablePrefix+uc.Table).
+			Set(uc.Column, anonymousUser.ID).
+			Where(sq.Eq{uc.Column: userID})
+
+		count, err := s.execCount(query)
+		if err != nil {
+			s.logger.Error("anonymizeUser update error", mlog.String("table", uc.Table), mlog.String("column", uc.Column), mlog.Err(err))
+			return nil, err
+		}
+		report.Anonymized[uc.Table+"."+uc.Column] = count
+	}
+
+	for _, table := range []string{"blocks", "blocks_history"} {
+		count, err := s.anonymizeUserInCardProperties(db, table, userID, anonymousUser.ID)
+		if err != nil {
+			return nil, err
+		}
+		report.Anonymized[table+".fields"] = count
+
+		count, err = s.anonymizeUserMentions(db, table, user.Username, anonymousUser.Username)
+		if err != nil {
+			return nil, err
+		}
+		report.Anonymized[table+".title"] = count
+	}
+
+	for _, uc := range userOwnedColumns {
+		query := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + uc.Table).
+			Where(sq.Eq{uc.Column: userID})
+
+		result, err := query.Exec()
+		if err != nil {
+			s.logger.Error("anonymizeUser delete error", mlog.String("table", uc.Table), mlog.Err(err))
+			return nil, err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		report.Removed[uc.Table] = count
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"users").
+		Set("id", anonymousUser.ID).
+		Set("username", anonymousUser.Username).
+		Set("email", "").
+		Set("password", "").
+		Set("mfa_secret", "").
+		Set("auth_service", "").
+		Set("auth_data", "").
+		Set("props", "{}").
+		Set("roles", model.SystemRoles(false)).
+		Set("is_guest", false).
+		Set("guest_expire_at", 0).
+		Set("update_at", anonymousUser.DeleteAt).
+		Set("delete_at", anonymousUser.DeleteAt).
+		Where(sq.Eq{"id": userID})
+
+	if err := s.execUserUpdate(query, userID); err != nil {
+		s.logger.Error("anonymizeUser error", mlog.String("userID", userID), mlog.Err(err))
+		return nil, err
+	}
+	return report, nil
+}
+
+// anonymizeUserInCardProperties replaces the ID of a user by the ID of
+// its anonymous user in the person properties of the cards.
+func (s *SQLStore) anonymizeUserInCardProperties(db sq.BaseRunner, table, userID, anonymousID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix + table).
+		Where(sq.Eq{"type": model.TypeCard})
+
+	if s.dbType == model.PostgresDBType {
+		query = query.
+			Set("fields", sq.Expr("REPLACE(fields::text, ?, ?)::json", userID, anonymousID)).
+			Where(sq.Like{"fields::text": "%" + userID + "%"})
+	} else {
+		query = query.
+			Set("fields", sq.Expr("REPLACE(fields, ?, ?)", userID, anonymousID)).
+			Where(sq.Like{"fields": "%" + userID + "%"})
+	}
+
+	count, err := s.execCount(query)
+	if err != nil {
+		s.logger.Error("anonymizeUserInCardProperties error", mlog.String("table", table), mlog.Err(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// anonymizeUserMentions replaces the @mentions of a user by mentions of
+// its anonymous user in the titles of the blocks, that hold the text of
+// the comments and of the text blocks.
+func (s *SQLStore) anonymizeUserMentions(db sq.BaseRunner, table, username, anonymousUsername string) (int64, error) {
+	if username == "" {
+		return 0, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("DISTINCT id", "title").
+		From(s.tablePrefix + table).
+		Where(sq.Like{"title": "%@" + username + "%"})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("anonymizeUserMentions error", mlog.String("table", table), mlog.Err(err))
+		return 0, err
+	}
+
+	type blockTitle struct {
+		id    string
+		title string
+	}
+	titles := []blockTitle{}
+	for rows.Next() {
+		var bt blockTitle
+		if err = rows.Scan(&bt.id, &bt.title); err != nil {
+			s.CloseRows(rows)
+			return 0, err
+		}
+		titles = append(titles, bt)
+	}
+	s.CloseRows(rows)
+
+	mention := regexp.MustCompile(`@` + regexp.QuoteMeta(username) + `(\.?(?:[^[:alnum:]._\-:]|$))`)
+
+	var total int64
+	for _, bt := range titles {
+		newTitle := mention.ReplaceAllString(bt.title, "@"+anonymousUsername+"$1")
+		if newTitle == bt.title {
+			continue
+		}
+
+		update := s.getQueryBuilder(db).
+			Update(s.tablePrefix+table).
+			Set("title", newTitle).
+			Where(sq.Eq{"id": bt.id, "title": bt.title})
+
+		count, err := s.execCount(update)
+		if err != nil {
+			s.logger.Error("anonymizeUserMentions update error", mlog.String("table", table), mlog.Err(err))
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (s *SQLStore) execCount(query sq.UpdateBuilder) (int64, error) {
+	result, err := query.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
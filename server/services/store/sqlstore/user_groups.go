@@ -0,0 +1,351 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func userGroupFields() []string {
+	return []string{
+		"id",
+		"team_id",
+		"name",
+		"COALESCE(description, '')",
+		"created_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func boardGroupFields() []string {
+	return []string{
+		"board_id",
+		"group_id",
+		"COALESCE(scheme_admin, false)",
+		"COALESCE(scheme_editor, false)",
+		"COALESCE(scheme_commenter, false)",
+		"COALESCE(scheme_viewer, false)",
+		"COALESCE(custom_role_id, '')",
+		"create_at",
+	}
+}
+
+func (s *SQLStore) userGroupsFromRows(rows *sql.Rows) ([]*model.UserGroup, error) {
+	groups := []*model.UserGroup{}
+
+	for rows.Next() {
+		var group model.UserGroup
+		err := rows.Scan(
+			&group.ID,
+			&group.TeamID,
+			&group.Name,
+			&group.Description,
+			&group.CreatedBy,
+			&group.CreateAt,
+			&group.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("userGroupsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		groups = append(groups, &group)
+	}
+	return groups, rows.Err()
+}
+
+func (s *SQLStore) boardGroupsFromRows(rows *sql.Rows) ([]*model.BoardGroup, error) {
+	boardGroups := []*model.BoardGroup{}
+
+	for rows.Next() {
+		var boardGroup model.BoardGroup
+		err := rows.Scan(
+			&boardGroup.BoardID,
+			&boardGroup.GroupID,
+			&boardGroup.SchemeAdmin,
+			&boardGroup.SchemeEditor,
+			&boardGroup.SchemeCommenter,
+			&boardGroup.SchemeViewer,
+			&boardGroup.CustomRoleID,
+			&boardGroup.CreateAt,
+		)
+		if err != nil {
+			s.logger.Error("boardGroupsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		boardGroups = append(boardGroups, &boardGroup)
+	}
+	return boardGroups, rows.Err()
+}
+
+func (s *SQLStore) createUserGroup(db sq.BaseRunner, group *model.UserGroup) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "user_groups").
+		SetMap(map[string]interface{}{
+			"id":          group.ID,
+			"team_id":     group.TeamID,
+			"name":        group.Name,
+			"description": group.Description,
+			"created_by":  group.CreatedBy,
+			"create_at":   group.CreateAt,
+			"update_at":   group.UpdateAt,
+		})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("createUserGroup error", mlog.String("groupID", group.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) updateUserGroup(db sq.BaseRunner, group *model.UserGroup) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_groups").
+		Set("name", group.Name).
+		Set("description", group.Description).
+		Set("update_at", group.UpdateAt).
+		Where(sq.Eq{"id": group.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("updateUserGroup error", mlog.String("groupID", group.ID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("user group ID=" + group.ID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getUserGroupByCondition(db sq.BaseRunner, condition sq.Eq) (*model.UserGroup, error) {
+	query := s.getQueryBuilder(db).
+		Select(userGroupFields()...).
+		From(s.tablePrefix + "user_groups").
+		Where(condition)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getUserGroupByCondition error", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	groups, err := s.userGroupsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, model.NewErrNotFound("user group")
+	}
+	return groups[0], nil
+}
+
+func (s *SQLStore) getUserGroup(db sq.BaseRunner, id string) (*model.UserGroup, error) {
+	return s.getUserGroupByCondition(db, sq.Eq{"id": id})
+}
+
+func (s *SQLStore) getUserGroupByName(db sq.BaseRunner, teamID, name string) (*model.UserGroup, error) {
+	return s.getUserGroupByCondition(db, sq.Eq{"team_id": teamID, "name": name})
+}
+
+func (s *SQLStore) getUserGroupsForTeam(db sq.BaseRunner, teamID string) ([]*model.UserGroup, error) {
+	query := s.getQueryBuilder(db).
+		Select(userGroupFields()...).
+		From(s.tablePrefix+"user_groups").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("name", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getUserGroupsForTeam error", mlog.String("teamID", teamID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.userGroupsFromRows(rows)
+}
+
+// deleteUserGroup deletes a user group along with its members and its
+// board links. The board memberships the group created are left to the
+// caller.
+func (s *SQLStore) deleteUserGroup(db sq.BaseRunner, id string) error {
+	for _, table := range []string{"user_group_members", "board_groups"} {
+		query := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + table).
+			Where(sq.Eq{"group_id": id})
+
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("deleteUserGroup error", mlog.String("groupID", id), mlog.String("table", table), mlog.Err(err))
+			return err
+		}
+	}
+
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "user_groups").
+		Where(sq.Eq{"id": id})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("deleteUserGroup error", mlog.String("groupID", id), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) addUserGroupMember(db sq.BaseRunner, groupID, userID string, createAt int64) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"user_group_members").
+		Columns("group_id", "user_id", "create_at").
+		Values(groupID, userID, createAt)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("addUserGroupMember error", mlog.String("groupID", groupID), mlog.String("userID", userID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteUserGroupMember(db sq.BaseRunner, groupID, userID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "user_group_members").
+		Where(sq.Eq{"group_id": groupID, "user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("deleteUserGroupMember error", mlog.String("groupID", groupID), mlog.String("userID", userID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("user group member userID=" + userID)
+	}
+	return nil
+}
+
+func (s *SQLStore) getUserGroupMemberIDs(db sq.BaseRunner, groupID string) ([]string, error) {
+	query := s.getQueryBuilder(db).
+		Select("user_id").
+		From(s.tablePrefix+"user_group_members").
+		Where(sq.Eq{"group_id": groupID}).
+		OrderBy("create_at", "user_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getUserGroupMemberIDs error", mlog.String("groupID", groupID), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	userIDs := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+func (s *SQLStore) saveBoardGroup(db sq.BaseRunner, boardGroup *model.BoardGroup) error {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "board_groups").
+		SetMap(map[string]interface{}{
+			"board_id":         boardGroup.BoardID,
+			"group_id":         boardGroup.GroupID,
+			"scheme_admin":     boardGroup.SchemeAdmin,
+			"scheme_editor":    boardGroup.SchemeEditor,
+			"scheme_commenter": boardGroup.SchemeCommenter,
+			"scheme_viewer":    boardGroup.SchemeViewer,
+			"custom_role_id":   boardGroup.CustomRoleID,
+			"create_at":        boardGroup.CreateAt,
+		})
+
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix(
+			"ON DUPLICATE KEY UPDATE scheme_admin = ?, scheme_editor = ?, scheme_commenter = ?, scheme_viewer = ?, custom_role_id = ?",
+			boardGroup.SchemeAdmin, boardGroup.SchemeEditor, boardGroup.SchemeCommenter, boardGroup.SchemeViewer, boardGroup.CustomRoleID)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (board_id, group_id)
+             DO UPDATE SET scheme_admin = EXCLUDED.scheme_admin, scheme_editor = EXCLUDED.scheme_editor,
+			   scheme_commenter = EXCLUDED.scheme_commenter, scheme_viewer = EXCLUDED.scheme_viewer,
+			   custom_role_id = EXCLUDED.custom_role_id`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("saveBoardGroup error", mlog.String("boardID", boardGroup.BoardID), mlog.String("groupID", boardGroup.GroupID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getBoardGroupsByCondition(db sq.BaseRunner, condition sq.Eq) ([]*model.BoardGroup, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardGroupFields()...).
+		From(s.tablePrefix+"board_groups").
+		Where(condition).
+		OrderBy("create_at", "board_id", "group_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("getBoardGroupsByCondition error", mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardGroupsFromRows(rows)
+}
+
+func (s *SQLStore) getBoardGroup(db sq.BaseRunner, boardID, groupID string) (*model.BoardGroup, error) {
+	boardGroups, err := s.getBoardGroupsByCondition(db, sq.Eq{"board_id": boardID, "group_id": groupID})
+	if err != nil {
+		return nil, err
+	}
+	if len(boardGroups) == 0 {
+		return nil, model.NewErrNotFound("board group boardID=" + boardID + ", groupID=" + groupID)
+	}
+	return boardGroups[0], nil
+}
+
+func (s *SQLStore) getBoardGroupsForBoard(db sq.BaseRunner, boardID string) ([]*model.BoardGroup, error) {
+	return s.getBoardGroupsByCondition(db, sq.Eq{"board_id": boardID})
+}
+
+func (s *SQLStore) getBoardGroupsForGroup(db sq.BaseRunner, groupID string) ([]*model.BoardGroup, error) {
+	return s.getBoardGroupsByCondition(db, sq.Eq{"group_id": groupID})
+}
+
+func (s *SQLStore) deleteBoardGroup(db sq.BaseRunner, boardID, groupID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "board_groups").
+		Where(sq.Eq{"board_id": boardID, "group_id": groupID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("deleteBoardGroup error", mlog.String("boardID", boardID), mlog.String("groupID", groupID), mlog.Err(err))
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("board group boardID=" + boardID + ", groupID=" + groupID)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ type Store interface {
 	GetBlocksWithType(boardID, blockType string) ([]*model.Block, error)
 	GetSubTree2(boardID, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error)
 	GetBlocksForBoard(boardID string) ([]*model.Block, error)
+	GetBlocksForUser(userID string) ([]*model.Block, error)
 	// @withTransaction
 	InsertBlock(block *model.Block, userID string) error
 	// @withTransaction
@@ -36,10 +37,15 @@ type Store interface {
 	GetBlock(blockID string) (*model.Block, error)
 	// @withTransaction
 	PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error
+	UpdateBlockTitle(blockID, title, modifiedBy string) error
 	GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
 	GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error)
+	// @withTransaction
+	RestoreBlockHistory(blockID string, updateAt int64, includeChildren bool, modifiedBy string) (*model.HistoryRestoreResult, error)
+	// @withTransaction
+	RestoreBoardHistory(boardID string, updateAt int64, modifiedBy string) (*model.HistoryRestoreResult, error)
 	GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error)
 	GetBoardAndCard(block *model.Block) (board *model.Board, card *model.Block, err error)
 	// @withTransaction
@@ -64,6 +70,13 @@ type Store interface {
 	UpdateUser(user *model.User) (*model.User, error)
 	UpdateUserPassword(username, password string) error
 	UpdateUserPasswordByID(userID, password string) error
+	GetAllUsers(opts model.UserQueryOptions) ([]*model.User, error)
+	GetUserByIDIncludingDeactivated(userID string) (*model.User, error)
+	UpdateUserRoles(userID, roles string) error
+	UpdateUserActive(userID string, active bool) error
+	UpdateUserGuest(userID string, isGuest bool, expireAt int64) error
+	// @withTransaction
+	AnonymizeUser(userID string, anonymousUser *model.User) (*model.UserDeletionReport, error)
 	GetUsersByTeam(teamID string, asGuestID string, showEmail, showName bool) ([]*model.User, error)
 	SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail, showName bool) ([]*model.User, error)
 	PatchUserPreferences(userID string, patch model.UserPreferencesPatch) (mmModel.Preferences, error)
@@ -71,10 +84,12 @@ type Store interface {
 
 	GetActiveUserCount(updatedSecondsAgo int64) (int, error)
 	GetSession(token string, expireTime int64) (*model.Session, error)
+	GetSessionsForUser(userID string) ([]*model.Session, error)
 	CreateSession(session *model.Session) error
 	RefreshSession(session *model.Session) error
 	UpdateSession(session *model.Session) error
 	DeleteSession(sessionID string) error
+	DeleteSessionsForUser(userID string) error
 	CleanUpSessions(expireTime int64) error
 
 	UpsertSharing(sharing model.Sharing) error
@@ -83,9 +98,21 @@ type Store interface {
 	UpsertTeamSignupToken(team model.Team) error
 	UpsertTeamSettings(team model.Team) error
 	GetTeam(ID string) (*model.Team, error)
+	GetTeamBySignupToken(signupToken string) (*model.Team, error)
 	GetTeamsForUser(userID string) ([]*model.Team, error)
 	GetAllTeams() ([]*model.Team, error)
 	GetTeamCount() (int64, error)
+	GetTeamBoardCount(teamID string) (int64, error)
+	CreateTeam(team *model.Team) error
+	UpdateTeam(team *model.Team) error
+	// @withTransaction
+	DeleteTeam(id string) error
+
+	GetTeamMembers(teamID string) ([]*model.TeamMember, error)
+	GetTeamMember(teamID, userID string) (*model.TeamMember, error)
+	GetTeamMemberCount(teamID string) (int64, error)
+	SaveTeamMember(member *model.TeamMember) error
+	DeleteTeamMember(teamID, userID string) error
 
 	InsertBoard(board *model.Board, userID string) (*model.Board, error)
 	// @withTransaction
@@ -97,11 +124,16 @@ type Store interface {
 	GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error)
 	// @withTransaction
 	DeleteBoard(boardID, userID string) error
+	// @withTransaction
+	ArchiveBoard(boardID, userID string) (*model.Board, error)
+	// @withTransaction
+	UnarchiveBoard(boardID, userID string) (*model.Board, error)
 
 	SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
 	DeleteMember(boardID, userID string) error
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
 	GetBoardMemberHistory(boardID, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error)
+	GetBoardMemberHistoryForUser(userID string) ([]*model.BoardMemberHistoryEntry, error)
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 	GetMembersForUser(userID string) ([]*model.BoardMember, error)
 	CanSeeUser(seerID string, seenID string) (bool, error)
@@ -116,6 +148,8 @@ type Store interface {
 	PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
 	DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error
+	// @withTransaction
+	ImportBoardCards(boardID string, boardPatch *model.BoardPatch, newCards []*model.Block, cardPatches *model.BlockPatchBatch, userID string) (*model.BoardsAndBlocks, error)
 
 	GetCategory(id string) (*model.Category, error)
 
@@ -130,6 +164,64 @@ type Store interface {
 
 	GetFileInfo(id string) (*mmModel.FileInfo, error)
 	SaveFileInfo(fileInfo *mmModel.FileInfo) error
+	UpdateFileInfoPreviews(fileInfo *mmModel.FileInfo) error
+	GetFileInfosWithoutPreviews(extensions []string, afterID string, limit int) ([]*mmModel.FileInfo, error)
+	DeleteFileInfo(id string) error
+
+	SaveFileUsage(usage *model.FileUsage) error
+	DeleteFileUsage(fileID string) error
+	GetFileUsageByFileID(fileID string) (*model.FileUsage, error)
+	GetFileUsage(teamID string) ([]*model.TeamFileUsage, error)
+	GetTeamFileUsageSize(teamID string) (int64, error)
+	GetFileUsagesCreatedBefore(createdBefore int64, afterID string, limit int) ([]*model.FileUsage, error)
+	GetBoardFileReferences(boardID string) ([]string, error)
+	GetFileBlocks(afterID string, limit int) ([]*model.Block, error)
+
+	CreateUploadSession(session *model.UploadSession) error
+	UpdateUploadSession(session *model.UploadSession) error
+	ClaimUploadSession(id string, fileOffset, now, claimedUntil int64) error
+	GetUploadSession(id string) (*model.UploadSession, error)
+	GetUploadSessionsUpdatedBefore(updatedBefore int64, limit int) ([]*model.UploadSession, error)
+	DeleteUploadSession(id string) error
+
+	GetLoginAttempt(kind, identifier string) (*model.LoginAttempt, error)
+	GetLoginAttemptByUnlockToken(token string) (*model.LoginAttempt, error)
+	// @withTransaction
+	IncrementLoginAttempt(kind, identifier string, now, windowStart int64) (*model.LoginAttempt, error)
+	LockLoginAttempt(kind, identifier string, lockedUntil int64, unlockToken string) error
+	DeleteLoginAttempt(kind, identifier string) error
+	DeleteExpiredLoginAttempts(failedBefore, now int64) (int64, error)
+
+	CreateShareLink(link *model.ShareLink) error
+	UpdateShareLink(link *model.ShareLink) error
+	GetShareLink(id string) (*model.ShareLink, error)
+	GetShareLinkByToken(token string) (*model.ShareLink, error)
+	GetShareLinksForBoard(boardID string) ([]*model.ShareLink, error)
+	IncrementShareLinkViews(id string) error
+	DeleteShareLink(id string) error
+
+	CreateCustomBoardRole(role *model.CustomBoardRole) error
+	UpdateCustomBoardRole(role *model.CustomBoardRole) error
+	GetCustomBoardRole(id string) (*model.CustomBoardRole, error)
+	GetCustomBoardRolesForTeam(teamID string) ([]*model.CustomBoardRole, error)
+	// @withTransaction
+	DeleteCustomBoardRole(id string) error
+
+	CreateUserGroup(group *model.UserGroup) error
+	UpdateUserGroup(group *model.UserGroup) error
+	GetUserGroup(id string) (*model.UserGroup, error)
+	GetUserGroupByName(teamID, name string) (*model.UserGroup, error)
+	GetUserGroupsForTeam(teamID string) ([]*model.UserGroup, error)
+	// @withTransaction
+	DeleteUserGroup(id string) error
+	AddUserGroupMember(groupID, userID string, createAt int64) error
+	DeleteUserGroupMember(groupID, userID string) error
+	GetUserGroupMemberIDs(groupID string) ([]string, error)
+	SaveBoardGroup(boardGroup *model.BoardGroup) error
+	GetBoardGroup(boardID, groupID string) (*model.BoardGroup, error)
+	GetBoardGroupsForBoard(boardID string) ([]*model.BoardGroup, error)
+	GetBoardGroupsForGroup(groupID string) ([]*model.BoardGroup, error)
+	DeleteBoardGroup(boardID, groupID string) error
 
 	// @withTransaction
 	AddUpdateCategoryBoard(userID, categoryID string, boardIDs []string) error
@@ -155,6 +247,21 @@ type Store interface {
 	// @withTransaction
 	RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error)
 
+	GetDeletedBoards(opts model.QueryTrashOptions) ([]*model.Board, error)
+	GetDeletedBlocks(opts model.QueryTrashOptions) ([]*model.Block, error)
+	// @withTransaction
+	PurgeDeletedBoard(boardID string) error
+	// @withTransaction
+	PurgeDeletedBlock(blockID string) error
+	// @withTransaction
+	PurgeTrash(deletedBefore int64, batchSize int64) (int64, error)
+
+	CreateArchiveJob(job *model.ArchiveJob) error
+	UpdateArchiveJob(job *model.ArchiveJob) error
+	GetArchiveJob(jobID string) (*model.ArchiveJob, error)
+	GetArchiveJobs(opts model.QueryArchiveJobsOptions) ([]*model.ArchiveJob, error)
+	DeleteArchiveJob(jobID string) error
+
 	GetUsedCardsCount() (int, error)
 	GetCardLimitTimestamp() (int64, error)
 	UpdateCardLimitTimestamp(cardLimit int) (int64, error)
@@ -0,0 +1,166 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestArchiveJobsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAndGetArchiveJob", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAndGetArchiveJob(t, store)
+	})
+	t.Run("UpdateArchiveJob", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateArchiveJob(t, store)
+	})
+	t.Run("GetArchiveJobs", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetArchiveJobs(t, store)
+	})
+	t.Run("DeleteArchiveJob", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteArchiveJob(t, store)
+	})
+}
+
+func createTestArchiveJob(t *testing.T, store store.Store, teamID, userID string, createAt int64) *model.ArchiveJob {
+	job := &model.ArchiveJob{
+		ID:        utils.NewID(utils.IDTypeNone),
+		Type:      model.ArchiveJobTypeExport,
+		TeamID:    teamID,
+		CreatedBy: userID,
+		Status:    model.ArchiveJobStatusPending,
+		BoardIDs:  []string{"board-1", "board-2"},
+		FilePath:  "archives/" + teamID + "/job.boardarchive",
+		CreateAt:  createAt,
+		UpdateAt:  createAt,
+	}
+	require.NoError(t, store.CreateArchiveJob(job))
+	return job
+}
+
+func testCreateAndGetArchiveJob(t *testing.T, store store.Store) {
+	job := createTestArchiveJob(t, store, testTeamID, testUserID, 1000)
+
+	t.Run("get an existing job", func(t *testing.T) {
+		rJob, err := store.GetArchiveJob(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, job, rJob)
+	})
+
+	t.Run("get a nonexistent job", func(t *testing.T) {
+		rJob, err := store.GetArchiveJob("nonexistent-id")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, rJob)
+	})
+
+	t.Run("a job without boards", func(t *testing.T) {
+		importJob := &model.ArchiveJob{
+			ID:         utils.NewID(utils.IDTypeNone),
+			Type:       model.ArchiveJobTypeImport,
+			TeamID:     testTeamID,
+			CreatedBy:  testUserID,
+			Status:     model.ArchiveJobStatusPending,
+			ImportMode: model.ImportModeMerge,
+			CreateAt:   1000,
+			UpdateAt:   1000,
+		}
+		require.NoError(t, store.CreateArchiveJob(importJob))
+
+		rJob, err := store.GetArchiveJob(importJob.ID)
+		require.NoError(t, err)
+		require.Empty(t, rJob.BoardIDs)
+		require.Equal(t, model.ImportModeMerge, rJob.ImportMode)
+		require.Nil(t, rJob.ImportResult)
+	})
+}
+
+func testUpdateArchiveJob(t *testing.T, store store.Store) {
+	job := createTestArchiveJob(t, store, testTeamID, testUserID, 1000)
+
+	job.Status = model.ArchiveJobStatusError
+	job.Progress = 50
+	job.Error = "something went wrong"
+	job.UpdateAt = 2000
+	job.ExpireAt = 3000
+	job.ImportResult = model.NewImportArchiveResult(model.ImportModeOverwrite)
+	job.ImportResult.BoardsUpdated = []string{"board-1"}
+	job.ImportResult.Conflicts = []model.ImportConflict{{BoardID: "board-2", Title: "Board 2", Reason: "conflict"}}
+	require.NoError(t, store.UpdateArchiveJob(job))
+
+	rJob, err := store.GetArchiveJob(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, job, rJob)
+
+	t.Run("update a nonexistent job", func(t *testing.T) {
+		err := store.UpdateArchiveJob(&model.ArchiveJob{ID: "nonexistent-id"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testGetArchiveJobs(t *testing.T, store store.Store) {
+	job1 := createTestArchiveJob(t, store, testTeamID, testUserID, 1000)
+	job2 := createTestArchiveJob(t, store, testTeamID, testUserID, 2000)
+	job3 := createTestArchiveJob(t, store, testTeamID, "other-user-id", 3000)
+	job4 := createTestArchiveJob(t, store, "other-team-id", testUserID, 4000)
+
+	job2.Status = model.ArchiveJobStatusSuccess
+	job2.ExpireAt = 5000
+	require.NoError(t, store.UpdateArchiveJob(job2))
+	job3.Status = model.ArchiveJobStatusInProgress
+	require.NoError(t, store.UpdateArchiveJob(job3))
+
+	t.Run("jobs of a user in a team, latest first", func(t *testing.T) {
+		jobs, err := store.GetArchiveJobs(model.QueryArchiveJobsOptions{TeamID: testTeamID, CreatedBy: testUserID})
+		require.NoError(t, err)
+		require.Equal(t, []string{job2.ID, job1.ID}, archiveJobIDs(jobs))
+	})
+
+	t.Run("jobs by status", func(t *testing.T) {
+		jobs, err := store.GetArchiveJobs(model.QueryArchiveJobsOptions{
+			Statuses: []model.ArchiveJobStatus{model.ArchiveJobStatusPending, model.ArchiveJobStatusInProgress},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{job4.ID, job3.ID, job1.ID}, archiveJobIDs(jobs))
+	})
+
+	t.Run("expired jobs", func(t *testing.T) {
+		jobs, err := store.GetArchiveJobs(model.QueryArchiveJobsOptions{ExpiredBefore: 5000})
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+
+		jobs, err = store.GetArchiveJobs(model.QueryArchiveJobsOptions{ExpiredBefore: 5001})
+		require.NoError(t, err)
+		require.Equal(t, []string{job2.ID}, archiveJobIDs(jobs))
+	})
+}
+
+func testDeleteArchiveJob(t *testing.T, store store.Store) {
+	job := createTestArchiveJob(t, store, testTeamID, testUserID, 1000)
+
+	require.NoError(t, store.DeleteArchiveJob(job.ID))
+
+	_, err := store.GetArchiveJob(job.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// deleting a nonexistent job is not an error
+	require.NoError(t, store.DeleteArchiveJob(job.ID))
+}
+
+func archiveJobIDs(jobs []*model.ArchiveJob) []string {
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
@@ -42,6 +42,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testPatchBlocks(t, store)
 	})
+	t.Run("UpdateBlockTitle", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateBlockTitle(t, store)
+	})
 	t.Run("DeleteBlock", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -87,6 +92,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testGetBlockHistoryNewestChildren(t, store)
 	})
+	t.Run("GetBlocksForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBlocksForUser(t, store)
+	})
 }
 
 func testInsertBlock(t *testing.T, store store.Store) {
@@ -454,6 +464,45 @@ func testPatchBlock(t *testing.T, store store.Store) {
 	})
 }
 
+func testUpdateBlockTitle(t *testing.T, store store.Store) {
+	boardID := "board-id-1"
+
+	block := &model.Block{
+		ID:      "id-test",
+		BoardID: boardID,
+		Type:    model.TypeText,
+		Title:   "old text",
+	}
+
+	err := store.InsertBlock(block, "user-id-1")
+	require.NoError(t, err)
+
+	t.Run("not existing block id", func(t *testing.T) {
+		err := store.UpdateBlockTitle("invalid-block-id", "new text", "user-id-1")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("title too long", func(t *testing.T) {
+		err := store.UpdateBlockTitle("id-test", strings.Repeat("a", model.BlockTitleMaxRunes+1), "user-id-1")
+		require.ErrorIs(t, err, model.ErrBlockTitleSizeLimitExceeded)
+	})
+
+	t.Run("update title without history", func(t *testing.T) {
+		err := store.UpdateBlockTitle("id-test", "new text", "user-id-2")
+		require.NoError(t, err)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, "new text", retrievedBlock.Title)
+		require.Equal(t, "user-id-2", retrievedBlock.ModifiedBy)
+
+		history, err := store.GetBlockHistory("id-test", model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, "old text", history[0].Title)
+	})
+}
+
 func testPatchBlocks(t *testing.T, store store.Store) {
 	block := &model.Block{
 		ID:      "id-test",
@@ -1347,3 +1396,60 @@ func testGetBlockHistoryNewestChildren(t *testing.T, store store.Store) {
 		}
 	})
 }
+
+func testGetBlocksForUser(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherUserID := utils.NewID(utils.IDTypeUser)
+
+	blocks := []*model.Block{
+		{
+			ID:       "card-created",
+			BoardID:  testBoardID,
+			ParentID: testBoardID,
+			Type:     model.TypeCard,
+		},
+		{
+			ID:       "comment-created",
+			BoardID:  testBoardID,
+			ParentID: "card-created",
+			Type:     model.TypeComment,
+		},
+	}
+	InsertBlocks(t, store, blocks, userID)
+
+	blocks = []*model.Block{
+		{
+			ID:       "card-assigned",
+			BoardID:  testBoardID,
+			ParentID: testBoardID,
+			Type:     model.TypeCard,
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"assignee": []interface{}{userID}}},
+		},
+		{
+			ID:       "card-other",
+			BoardID:  testBoardID,
+			ParentID: testBoardID,
+			Type:     model.TypeCard,
+		},
+		{
+			ID:       "text-referencing-user",
+			BoardID:  testBoardID,
+			ParentID: "card-other",
+			Type:     model.TypeText,
+			Fields:   map[string]interface{}{"userId": userID},
+		},
+	}
+	InsertBlocks(t, store, blocks, otherUserID)
+
+	got, err := store.GetBlocksForUser(userID)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, block := range got {
+		ids = append(ids, block.ID)
+	}
+	require.ElementsMatch(t, []string{"card-created", "comment-created", "card-assigned"}, ids)
+
+	got, err = store.GetBlocksForUser("nonexistent-user")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
@@ -0,0 +1,106 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestCustomBoardRolesStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAndGetCustomBoardRole", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAndGetCustomBoardRole(t, store)
+	})
+	t.Run("UpdateCustomBoardRole", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateCustomBoardRole(t, store)
+	})
+	t.Run("DeleteCustomBoardRole", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteCustomBoardRole(t, store)
+	})
+}
+
+func createTestCustomBoardRole(t *testing.T, store store.Store, teamID, name string) *model.CustomBoardRole {
+	role := &model.CustomBoardRole{
+		ID:          utils.NewID(utils.IDTypeNone),
+		TeamID:      teamID,
+		Name:        name,
+		Description: "a test role",
+		Permissions: []string{model.PermissionViewBoard.Id, model.PermissionCommentBoardCards.Id},
+		CreatedBy:   "user-id",
+		CreateAt:    utils.GetMillis(),
+		UpdateAt:    utils.GetMillis(),
+	}
+	require.NoError(t, store.CreateCustomBoardRole(role))
+	return role
+}
+
+func testCreateAndGetCustomBoardRole(t *testing.T, store store.Store) {
+	t.Run("nonexistent role", func(t *testing.T) {
+		role, err := store.GetCustomBoardRole("nonexistent-id")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, role)
+	})
+
+	t.Run("roles of a team", func(t *testing.T) {
+		role1 := createTestCustomBoardRole(t, store, "team-1", "Reviewer")
+		role2 := createTestCustomBoardRole(t, store, "team-1", "Mover")
+		createTestCustomBoardRole(t, store, "team-2", "Other")
+
+		got, err := store.GetCustomBoardRole(role1.ID)
+		require.NoError(t, err)
+		require.Equal(t, role1, got)
+
+		roles, err := store.GetCustomBoardRolesForTeam("team-1")
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+		require.Equal(t, role2.ID, roles[0].ID)
+		require.Equal(t, role1.ID, roles[1].ID)
+	})
+}
+
+func testUpdateCustomBoardRole(t *testing.T, store store.Store) {
+	role := createTestCustomBoardRole(t, store, "team-1", "Reviewer")
+
+	role.Name = "Mover"
+	role.Permissions = []string{model.PermissionViewBoard.Id, model.PermissionMoveBoardCards.Id}
+	role.UpdateAt++
+	require.NoError(t, store.UpdateCustomBoardRole(role))
+
+	got, err := store.GetCustomBoardRole(role.ID)
+	require.NoError(t, err)
+	require.Equal(t, role, got)
+
+	err = store.UpdateCustomBoardRole(&model.CustomBoardRole{ID: "nonexistent-id"})
+	require.True(t, model.IsErrNotFound(err))
+}
+
+func testDeleteCustomBoardRole(t *testing.T, store store.Store) {
+	role := createTestCustomBoardRole(t, store, "team-1", "Reviewer")
+
+	member, err := store.SaveMember(&model.BoardMember{BoardID: "board-id", UserID: "user-id", CustomRoleID: role.ID})
+	require.NoError(t, err)
+	require.Equal(t, role.ID, member.CustomRoleID)
+
+	got, err := store.GetMemberForBoard("board-id", "user-id")
+	require.NoError(t, err)
+	require.Equal(t, role.ID, got.CustomRoleID)
+
+	require.NoError(t, store.DeleteCustomBoardRole(role.ID))
+
+	_, err = store.GetCustomBoardRole(role.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// the role is unassigned from the members
+	got, err = store.GetMemberForBoard("board-id", "user-id")
+	require.NoError(t, err)
+	require.Empty(t, got.CustomRoleID)
+}
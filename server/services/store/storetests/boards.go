@@ -97,6 +97,11 @@ func StoreTestBoardStore(t *testing.T, setup func(t *testing.T) (store.Store, fu
 		defer tearDown()
 		testGetBoardCount(t, store)
 	})
+	t.Run("ArchiveBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testArchiveBoard(t, store)
+	})
 }
 
 func testGetBoard(t *testing.T, store store.Store) {
@@ -658,6 +663,21 @@ func testSaveMember(t *testing.T, store store.Store) {
 		require.NoError(t, err)
 		require.Empty(t, memberHistory)
 	})
+
+	t.Run("should return the history of a user on all the boards", func(t *testing.T) {
+		_, err := store.SaveMember(&model.BoardMember{UserID: userID, BoardID: "other-board-id", SchemeViewer: true})
+		require.NoError(t, err)
+
+		memberHistory, err := store.GetBoardMemberHistoryForUser(userID)
+		require.NoError(t, err)
+		require.Len(t, memberHistory, 2)
+		require.Equal(t, boardID, memberHistory[0].BoardID)
+		require.Equal(t, "other-board-id", memberHistory[1].BoardID)
+
+		memberHistory, err = store.GetBoardMemberHistoryForUser("nonexistent-user")
+		require.NoError(t, err)
+		require.Empty(t, memberHistory)
+	})
 }
 
 func testGetMemberForBoard(t *testing.T, store store.Store) {
@@ -1199,3 +1219,60 @@ func testGetBoardCount(t *testing.T, store store.Store) {
 		require.Equal(t, originalCount+1, newCount)
 	})
 }
+
+func testArchiveBoard(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	board := &model.Board{
+		ID:     "id-1",
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+	}
+	_, err := store.InsertBoard(board, userID)
+	require.NoError(t, err)
+
+	t.Run("archive a board", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		archivedBoard, err := store.ArchiveBoard(board.ID, "archiver-id")
+		require.NoError(t, err)
+		require.NotZero(t, archivedBoard.ArchiveAt)
+		require.True(t, archivedBoard.IsArchived())
+
+		rBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, archivedBoard.ArchiveAt, rBoard.ArchiveAt)
+		require.Equal(t, "archiver-id", rBoard.ModifiedBy)
+
+		// the archive is recorded in the board history
+		history, err := store.GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{Descending: true})
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, archivedBoard.ArchiveAt, history[0].ArchiveAt)
+		require.Zero(t, history[1].ArchiveAt)
+	})
+
+	t.Run("archiving an archived board is a noop", func(t *testing.T) {
+		rBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+
+		archivedBoard, err := store.ArchiveBoard(board.ID, userID)
+		require.NoError(t, err)
+		require.Equal(t, rBoard.ArchiveAt, archivedBoard.ArchiveAt)
+	})
+
+	t.Run("unarchive a board", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		unarchivedBoard, err := store.UnarchiveBoard(board.ID, userID)
+		require.NoError(t, err)
+		require.Zero(t, unarchivedBoard.ArchiveAt)
+
+		rBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.False(t, rBoard.IsArchived())
+	})
+
+	t.Run("nonexisting board", func(t *testing.T) {
+		_, err := store.ArchiveBoard("nonexistent-id", userID)
+		require.True(t, model.IsErrNotFound(err), err)
+	})
+}
@@ -29,6 +29,11 @@ func StoreTestBoardsAndBlocksStore(t *testing.T, setup func(t *testing.T) (store
 		defer tearDown()
 		testDeleteBoardsAndBlocks(t, store)
 	})
+	t.Run("importBoardCards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testImportBoardCards(t, store)
+	})
 
 	t.Run("duplicateBoard", func(t *testing.T) {
 		store, tearDown := setup(t)
@@ -334,6 +339,84 @@ func testPatchBoardsAndBlocks(t *testing.T, store store.Store) {
 	})
 }
 
+func testImportBoardCards(t *testing.T, store store.Store) {
+	teamID := testTeamID
+	userID := testUserID
+
+	setupBoard := func(t *testing.T, boardID string) *model.Block {
+		_, err := store.InsertBoard(&model.Board{ID: boardID, TeamID: teamID, Type: model.BoardTypeOpen}, userID)
+		require.NoError(t, err)
+
+		card := &model.Block{ID: boardID + "-card", BoardID: boardID, Type: model.TypeCard, Title: "initial title"}
+		require.NoError(t, store.InsertBlock(card, userID))
+		time.Sleep(10 * time.Millisecond)
+		return card
+	}
+
+	t.Run("on failure, nothing should be saved", func(t *testing.T) {
+		if store.DBType() == model.SqliteDBType {
+			t.Skip("No transactions support int sqlite")
+		}
+
+		card := setupBoard(t, "board-id-1")
+		newTitle := "new title"
+		boardPatch := &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{{"id": "new-property"}}}
+		newCards := []*model.Block{{ID: "new-card-1", BoardID: "board-id-1", Type: model.TypeCard}}
+		cardPatches := &model.BlockPatchBatch{
+			BlockIDs:     []string{card.ID, "missing-card"},
+			BlockPatches: []model.BlockPatch{{Title: &newTitle}, {Title: &newTitle}},
+		}
+
+		bab, err := store.ImportBoardCards("board-id-1", boardPatch, newCards, cardPatches, userID)
+		require.Error(t, err)
+		require.Nil(t, bab)
+
+		rBoard, err := store.GetBoard("board-id-1")
+		require.NoError(t, err)
+		require.Empty(t, rBoard.CardProperties)
+
+		_, err = store.GetBlock("new-card-1")
+		require.True(t, model.IsErrNotFound(err))
+
+		rCard, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, "initial title", rCard.Title)
+	})
+
+	t.Run("import cards", func(t *testing.T) {
+		card := setupBoard(t, "board-id-2")
+		newTitle := "new title"
+		boardPatch := &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{{"id": "new-property"}}}
+		newCards := []*model.Block{{ID: "new-card-2", BoardID: "board-id-2", Type: model.TypeCard, Title: "new card"}}
+		cardPatches := &model.BlockPatchBatch{
+			BlockIDs:     []string{card.ID},
+			BlockPatches: []model.BlockPatch{{Title: &newTitle}},
+		}
+
+		bab, err := store.ImportBoardCards("board-id-2", boardPatch, newCards, cardPatches, userID)
+		require.NoError(t, err)
+		require.Len(t, bab.Boards, 1)
+		require.Len(t, bab.Boards[0].CardProperties, 1)
+		require.Len(t, bab.Blocks, 2)
+		require.Equal(t, "new-card-2", bab.Blocks[0].ID)
+		require.Equal(t, newTitle, bab.Blocks[1].Title)
+
+		rCard, err := store.GetBlock("new-card-2")
+		require.NoError(t, err)
+		require.Equal(t, "new card", rCard.Title)
+	})
+
+	t.Run("import cards without properties changes", func(t *testing.T) {
+		setupBoard(t, "board-id-3")
+		newCards := []*model.Block{{ID: "new-card-3", BoardID: "board-id-3", Type: model.TypeCard}}
+
+		bab, err := store.ImportBoardCards("board-id-3", nil, newCards, &model.BlockPatchBatch{}, userID)
+		require.NoError(t, err)
+		require.Empty(t, bab.Boards)
+		require.Len(t, bab.Blocks, 1)
+	})
+}
+
 func testDeleteBoardsAndBlocks(t *testing.T, store store.Store) {
 	teamID := testTeamID
 	userID := testUserID
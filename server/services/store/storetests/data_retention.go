@@ -36,6 +36,36 @@ func StoreTestDataRetention(t *testing.T, setup func(t *testing.T) (store.Store,
 		testRunDataRetention(t, store, 2)
 		testRunDataRetention(t, store, 10)
 	})
+
+	t.Run("RunDataRetentionArchivedBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+
+		category := model.Category{
+			ID:     categoryID,
+			Name:   "TestCategory",
+			UserID: testUserID,
+			TeamID: testTeamID,
+		}
+		err := store.CreateCategory(category)
+		require.NoError(t, err)
+
+		LoadData(t, store)
+		_, err = store.ArchiveBoard(boardID, testUserID)
+		require.NoError(t, err)
+
+		_, err = store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(time.Hour*1)), 0)
+		require.NoError(t, err)
+
+		// archived boards are exempt from data retention
+		board, err := store.GetBoard(boardID)
+		require.NoError(t, err)
+		require.NotZero(t, board.ArchiveAt)
+
+		blocks, err := store.GetBlocksForBoard(boardID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 4)
+	})
 }
 
 func LoadData(t *testing.T, store store.Store) {
@@ -92,6 +122,8 @@ func LoadData(t *testing.T, store store.Store) {
 	err = store.UpsertSharing(sharing)
 	require.NoError(t, err)
 
+	createTestShareLink(t, store, boardID, utils.GetMillis())
+
 	err = store.AddUpdateCategoryBoard(testUserID, categoryID, []string{boardID})
 	require.NoError(t, err)
 }
@@ -132,6 +164,10 @@ func testRunDataRetention(t *testing.T, store store.Store, batchSize int) {
 		require.True(t, model.IsErrNotFound(err), err)
 		require.Nil(t, sharing)
 
+		links, err := store.GetShareLinksForBoard(boardID)
+		require.NoError(t, err)
+		require.Empty(t, links)
+
 		category, err := store.GetUserCategoryBoards(boardID, testTeamID)
 		require.NoError(t, err)
 		require.Empty(t, category)
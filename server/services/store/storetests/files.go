@@ -22,6 +22,7 @@ func StoreTestFileStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 			Name:      "Dunder Mifflin Sales Report 2022",
 			Extension: ".sales",
 			Size:      112233,
+			MimeType:  "application/vnd.ms-excel",
 			DeleteAt:  0,
 		}
 
@@ -34,6 +35,7 @@ func StoreTestFileStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		require.Equal(t, "Dunder Mifflin Sales Report 2022", retrievedFileInfo.Name)
 		require.Equal(t, ".sales", retrievedFileInfo.Extension)
 		require.Equal(t, int64(112233), retrievedFileInfo.Size)
+		require.Equal(t, "application/vnd.ms-excel", retrievedFileInfo.MimeType)
 		require.Equal(t, int64(0), retrievedFileInfo.DeleteAt)
 		require.False(t, retrievedFileInfo.Archived)
 	})
@@ -45,4 +47,218 @@ func StoreTestFileStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		require.ErrorAs(t, err, &nf)
 		require.Nil(t, fileInfo)
 	})
+
+	t.Run("should update the previews of a fileinfo", func(t *testing.T) {
+		fileInfo := &mmModel.FileInfo{
+			Id:        "file_info_2",
+			CreateAt:  utils.GetMillis(),
+			Name:      "Scranton Branch.png",
+			Extension: ".png",
+			Size:      4242,
+			Path:      "20220101/file_info_2.png",
+		}
+		require.NoError(t, sqlStore.SaveFileInfo(fileInfo))
+
+		fileInfo.ThumbnailPath = "20220101/file_info_2_thumb.jpg"
+		fileInfo.PreviewPath = "20220101/file_info_2_preview.jpg"
+		fileInfo.Width = 640
+		fileInfo.Height = 480
+		fileInfo.HasPreviewImage = true
+		require.NoError(t, sqlStore.UpdateFileInfoPreviews(fileInfo))
+
+		retrievedFileInfo, err := sqlStore.GetFileInfo("file_info_2")
+		require.NoError(t, err)
+		require.Equal(t, fileInfo.ThumbnailPath, retrievedFileInfo.ThumbnailPath)
+		require.Equal(t, fileInfo.PreviewPath, retrievedFileInfo.PreviewPath)
+		require.Equal(t, 640, retrievedFileInfo.Width)
+		require.Equal(t, 480, retrievedFileInfo.Height)
+		require.True(t, retrievedFileInfo.HasPreviewImage)
+
+		err = sqlStore.UpdateFileInfoPreviews(&mmModel.FileInfo{Id: "nonexistent"})
+		var nf *model.ErrNotFound
+		require.ErrorAs(t, err, &nf)
+	})
+
+	t.Run("should get the fileinfos without previews", func(t *testing.T) {
+		for _, id := range []string{"file_info_3", "file_info_4", "file_info_5"} {
+			require.NoError(t, sqlStore.SaveFileInfo(&mmModel.FileInfo{
+				Id:        id,
+				CreateAt:  utils.GetMillis(),
+				Name:      id + ".jpg",
+				Extension: ".jpg",
+				Path:      id + ".jpg",
+			}))
+		}
+		require.NoError(t, sqlStore.SaveFileInfo(&mmModel.FileInfo{
+			Id:        "file_info_6",
+			CreateAt:  utils.GetMillis(),
+			Name:      "Deleted.jpg",
+			Extension: ".jpg",
+			DeleteAt:  utils.GetMillis(),
+		}))
+
+		extensions := []string{".png", ".jpg"}
+		fileInfos, err := sqlStore.GetFileInfosWithoutPreviews(extensions, "", 2)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, 2)
+		require.Equal(t, "file_info_3", fileInfos[0].Id)
+		require.Equal(t, "file_info_4", fileInfos[1].Id)
+
+		fileInfos, err = sqlStore.GetFileInfosWithoutPreviews(extensions, "file_info_4", 2)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, 1)
+		require.Equal(t, "file_info_5", fileInfos[0].Id)
+
+		fileInfos, err = sqlStore.GetFileInfosWithoutPreviews([]string{".gif"}, "", 10)
+		require.NoError(t, err)
+		require.Empty(t, fileInfos)
+	})
+
+	t.Run("should delete a fileinfo", func(t *testing.T) {
+		require.NoError(t, sqlStore.SaveFileInfo(&mmModel.FileInfo{
+			Id:        "file_info_7",
+			CreateAt:  utils.GetMillis(),
+			Name:      "Orphan.txt",
+			Extension: ".txt",
+		}))
+		require.NoError(t, sqlStore.DeleteFileInfo("file_info_7"))
+
+		fileInfo, err := sqlStore.GetFileInfo("file_info_7")
+		require.NoError(t, err)
+		require.NotZero(t, fileInfo.DeleteAt)
+	})
+
+	t.Run("should track the file usage per team and board", func(t *testing.T) {
+		usages := []*model.FileUsage{
+			{FileID: "usage_1", TeamID: "team_1", BoardID: "board_1", FileName: "7usage_1.png", Size: 100, CreateAt: 1000},
+			{FileID: "usage_2", TeamID: "team_1", BoardID: "board_1", FileName: "7usage_2.png", Size: 200, CreateAt: 2000},
+			{FileID: "usage_3", TeamID: "team_1", BoardID: "board_2", FileName: "7usage_3.png", Size: 300, CreateAt: 3000},
+			{FileID: "usage_4", TeamID: "team_2", BoardID: "board_3", FileName: "7usage_4.png", Size: 400, CreateAt: 4000},
+		}
+		for _, usage := range usages {
+			require.NoError(t, sqlStore.SaveFileUsage(usage))
+		}
+
+		size, err := sqlStore.GetTeamFileUsageSize("team_1")
+		require.NoError(t, err)
+		require.Equal(t, int64(600), size)
+
+		size, err = sqlStore.GetTeamFileUsageSize("empty_team")
+		require.NoError(t, err)
+		require.Zero(t, size)
+
+		teams, err := sqlStore.GetFileUsage("team_1")
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		require.Equal(t, int64(3), teams[0].Files)
+		require.Equal(t, int64(600), teams[0].Size)
+		require.Equal(t, []*model.BoardFileUsage{
+			{BoardID: "board_1", Files: 2, Size: 300},
+			{BoardID: "board_2", Files: 1, Size: 300},
+		}, teams[0].Boards)
+
+		teams, err = sqlStore.GetFileUsage("")
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		require.Equal(t, "team_2", teams[1].TeamID)
+		require.Equal(t, int64(400), teams[1].Size)
+
+		created, err := sqlStore.GetFileUsagesCreatedBefore(3000, "", 10)
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+		require.Equal(t, usages[0], created[0])
+
+		created, err = sqlStore.GetFileUsagesCreatedBefore(3000, "usage_1", 10)
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.Equal(t, "usage_2", created[0].FileID)
+
+		usage, err := sqlStore.GetFileUsageByFileID("usage_3")
+		require.NoError(t, err)
+		require.Equal(t, usages[2], usage)
+
+		require.NoError(t, sqlStore.DeleteFileUsage("usage_1"))
+		size, err = sqlStore.GetTeamFileUsageSize("team_1")
+		require.NoError(t, err)
+		require.Equal(t, int64(500), size)
+
+		_, err = sqlStore.GetFileUsageByFileID("usage_1")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("should get the files referenced by the blocks of a board", func(t *testing.T) {
+		boardID := utils.NewID(utils.IDTypeBoard)
+		newBlock := func(blockType model.BlockType, fields map[string]interface{}) *model.Block {
+			return &model.Block{
+				ID:        utils.NewID(utils.IDTypeBlock),
+				BoardID:   boardID,
+				ParentID:  boardID,
+				Type:      blockType,
+				Fields:    fields,
+				CreatedBy: "user-id",
+				CreateAt:  utils.GetMillis(),
+				UpdateAt:  utils.GetMillis(),
+			}
+		}
+
+		image := newBlock(model.TypeImage, map[string]interface{}{"fileId": "7image.png"})
+		attachment := newBlock(model.TypeAttachment, map[string]interface{}{"attachmentId": "7attachment.pdf"})
+		text := newBlock(model.TypeText, map[string]interface{}{"fileId": "7text.png"})
+		require.NoError(t, sqlStore.InsertBlocks([]*model.Block{image, attachment, text}, "user-id"))
+
+		// the previous file of a block can still be restored from the history
+		require.NoError(t, sqlStore.PatchBlock(image.ID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"fileId": "7replaced.png"},
+		}, "user-id"))
+
+		fileNames, err := sqlStore.GetBoardFileReferences(boardID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"7image.png", "7replaced.png", "7attachment.pdf"}, fileNames)
+
+		fileNames, err = sqlStore.GetBoardFileReferences("other-board")
+		require.NoError(t, err)
+		require.Empty(t, fileNames)
+	})
+
+	t.Run("should get the file blocks of all the boards", func(t *testing.T) {
+		newBlock := func(boardID string, blockType model.BlockType) *model.Block {
+			return &model.Block{
+				ID:        utils.NewID(utils.IDTypeBlock),
+				BoardID:   boardID,
+				ParentID:  boardID,
+				Type:      blockType,
+				Fields:    map[string]interface{}{},
+				CreatedBy: "user-id",
+				CreateAt:  utils.GetMillis(),
+				UpdateAt:  utils.GetMillis(),
+			}
+		}
+
+		boardID1 := utils.NewID(utils.IDTypeBoard)
+		boardID2 := utils.NewID(utils.IDTypeBoard)
+		blocks := []*model.Block{
+			newBlock(boardID1, model.TypeImage),
+			newBlock(boardID1, model.TypeText),
+			newBlock(boardID2, model.TypeAttachment),
+			newBlock(boardID2, model.TypeImage),
+		}
+		require.NoError(t, sqlStore.InsertBlocks(blocks, "user-id"))
+
+		var fileBlockIDs []string
+		afterID := ""
+		for {
+			fileBlocks, err := sqlStore.GetFileBlocks(afterID, 2)
+			require.NoError(t, err)
+			for _, block := range fileBlocks {
+				require.Contains(t, []model.BlockType{model.TypeImage, model.TypeAttachment}, block.Type)
+				fileBlockIDs = append(fileBlockIDs, block.ID)
+				afterID = block.ID
+			}
+			if len(fileBlocks) < 2 {
+				break
+			}
+		}
+		require.Subset(t, fileBlockIDs, []string{blocks[0].ID, blocks[2].ID, blocks[3].ID})
+		require.NotContains(t, fileBlockIDs, blocks[1].ID)
+	})
 }
@@ -0,0 +1,144 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestLoginAttemptsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("IncrementLoginAttempt", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testIncrementLoginAttempt(t, store)
+	})
+	t.Run("LockLoginAttempt", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testLockLoginAttempt(t, store)
+	})
+	t.Run("DeleteLoginAttempt", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteLoginAttempt(t, store)
+	})
+	t.Run("DeleteExpiredLoginAttempts", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteExpiredLoginAttempts(t, store)
+	})
+}
+
+func testIncrementLoginAttempt(t *testing.T, store store.Store) {
+	t.Run("count the failed attempts", func(t *testing.T) {
+		userID := utils.NewID(utils.IDTypeUser)
+
+		attempt, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, model.LoginAttemptKindUser, attempt.Kind)
+		require.Equal(t, userID, attempt.Identifier)
+		require.Equal(t, 1, attempt.FailedCount)
+		require.EqualValues(t, 1000, attempt.LastFailureAt)
+		require.Zero(t, attempt.LockedUntil)
+
+		attempt, err = store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 2000, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, attempt.FailedCount)
+		require.EqualValues(t, 2000, attempt.LastFailureAt)
+	})
+
+	t.Run("restart the count after the window", func(t *testing.T) {
+		userID := utils.NewID(utils.IDTypeUser)
+
+		_, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 1000, 0)
+		require.NoError(t, err)
+		_, err = store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 2000, 0)
+		require.NoError(t, err)
+
+		attempt, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 10000, 5000)
+		require.NoError(t, err)
+		require.Equal(t, 1, attempt.FailedCount)
+		require.EqualValues(t, 10000, attempt.LastFailureAt)
+	})
+
+	t.Run("count the kinds separately", func(t *testing.T) {
+		identifier := "127.0.0.1"
+
+		_, err := store.IncrementLoginAttempt(model.LoginAttemptKindIP, identifier, 1000, 0)
+		require.NoError(t, err)
+
+		attempt, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, identifier, 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, attempt.FailedCount)
+	})
+}
+
+func testLockLoginAttempt(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	token := utils.NewID(utils.IDTypeToken)
+
+	_, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 1000, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.LockLoginAttempt(model.LoginAttemptKindUser, userID, 5000, token))
+
+	attempt, err := store.GetLoginAttempt(model.LoginAttemptKindUser, userID)
+	require.NoError(t, err)
+	require.EqualValues(t, 5000, attempt.LockedUntil)
+	require.Equal(t, token, attempt.UnlockToken)
+	require.True(t, attempt.IsLocked(4000))
+	require.False(t, attempt.IsLocked(5000))
+
+	attempt, err = store.GetLoginAttemptByUnlockToken(token)
+	require.NoError(t, err)
+	require.Equal(t, userID, attempt.Identifier)
+
+	_, err = store.GetLoginAttemptByUnlockToken("invalid-token")
+	require.True(t, model.IsErrNotFound(err))
+	_, err = store.GetLoginAttemptByUnlockToken("")
+	require.True(t, model.IsErrNotFound(err))
+}
+
+func testDeleteLoginAttempt(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	_, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, userID, 1000, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteLoginAttempt(model.LoginAttemptKindUser, userID))
+
+	_, err = store.GetLoginAttempt(model.LoginAttemptKindUser, userID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// deleting missing attempts isn't an error
+	require.NoError(t, store.DeleteLoginAttempt(model.LoginAttemptKindUser, userID))
+}
+
+func testDeleteExpiredLoginAttempts(t *testing.T, store store.Store) {
+	oldID := utils.NewID(utils.IDTypeUser)
+	lockedID := utils.NewID(utils.IDTypeUser)
+	recentID := utils.NewID(utils.IDTypeUser)
+
+	_, err := store.IncrementLoginAttempt(model.LoginAttemptKindUser, oldID, 1000, 0)
+	require.NoError(t, err)
+	_, err = store.IncrementLoginAttempt(model.LoginAttemptKindUser, lockedID, 1000, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.LockLoginAttempt(model.LoginAttemptKindUser, lockedID, 10000, ""))
+	_, err = store.IncrementLoginAttempt(model.LoginAttemptKindUser, recentID, 5000, 0)
+	require.NoError(t, err)
+
+	deleted, err := store.DeleteExpiredLoginAttempts(2000, 5000)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, deleted)
+
+	_, err = store.GetLoginAttempt(model.LoginAttemptKindUser, oldID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// the attempts still locked out and the recent ones are kept
+	_, err = store.GetLoginAttempt(model.LoginAttemptKindUser, lockedID)
+	require.NoError(t, err)
+	_, err = store.GetLoginAttempt(model.LoginAttemptKindUser, recentID)
+	require.NoError(t, err)
+}
@@ -28,6 +28,18 @@ func StoreTestSessionStore(t *testing.T, setup func(t *testing.T) (store.Store,
 		defer tearDown()
 		testUpdateSession(t, store)
 	})
+
+	t.Run("GetSessionOfGuest", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetSessionOfGuest(t, store)
+	})
+
+	t.Run("GetSessionsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetSessionsForUser(t, store)
+	})
 }
 
 func testCreateAndGetAndDeleteSession(t *testing.T, store store.Store) {
@@ -105,3 +117,53 @@ func testUpdateSession(t *testing.T, store store.Store) {
 	require.NoError(t, err)
 	require.Equal(t, session, got)
 }
+
+func testGetSessionOfGuest(t *testing.T, store store.Store) {
+	guest, err := store.CreateUser(&model.User{
+		ID:            "guest-id",
+		Username:      "guest",
+		Email:         "guest@example.com",
+		IsGuest:       true,
+		GuestExpireAt: 1000,
+	})
+	require.NoError(t, err)
+
+	session := &model.Session{
+		ID:     "session-id",
+		Token:  "token",
+		UserID: guest.ID,
+	}
+	require.NoError(t, store.CreateSession(session))
+
+	got, err := store.GetSession(session.Token, 60*60)
+	require.NoError(t, err)
+	require.True(t, got.IsGuest)
+	require.Equal(t, int64(1000), got.GuestExpireAt)
+	require.True(t, got.IsExpiredGuest())
+}
+
+func testGetSessionsForUser(t *testing.T, store store.Store) {
+	for i := 0; i < 2; i++ {
+		session := &model.Session{
+			ID:     fmt.Sprintf("session-id-%d", i),
+			Token:  fmt.Sprintf("token-%d", i),
+			UserID: "user-id",
+			Props:  map[string]interface{}{},
+		}
+		require.NoError(t, store.CreateSession(session))
+	}
+	require.NoError(t, store.CreateSession(&model.Session{ID: "other-session-id", Token: "other-token", UserID: "other-user-id"}))
+
+	sessions, err := store.GetSessionsForUser("user-id")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		require.Equal(t, "user-id", session.UserID)
+		require.NotZero(t, session.CreateAt)
+		require.NotZero(t, session.UpdateAt)
+	}
+
+	sessions, err = store.GetSessionsForUser("nonexistent-user")
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -0,0 +1,146 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestShareLinksStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAndGetShareLink", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAndGetShareLink(t, store)
+	})
+	t.Run("UpdateShareLink", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateShareLink(t, store)
+	})
+	t.Run("IncrementShareLinkViews", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testIncrementShareLinkViews(t, store)
+	})
+	t.Run("DeleteShareLink", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteShareLink(t, store)
+	})
+}
+
+func createTestShareLink(t *testing.T, store store.Store, boardID string, createAt int64) *model.ShareLink {
+	link := &model.ShareLink{
+		ID:               utils.NewID(utils.IDTypeNone),
+		BoardID:          boardID,
+		Token:            utils.NewID(utils.IDTypeToken),
+		HiddenProperties: []string{},
+		CreatedBy:        testUserID,
+		CreateAt:         createAt,
+		UpdateAt:         createAt,
+	}
+	require.NoError(t, store.CreateShareLink(link))
+	return link
+}
+
+func testCreateAndGetShareLink(t *testing.T, store store.Store) {
+	t.Run("get an existing link", func(t *testing.T) {
+		link := &model.ShareLink{
+			ID:               utils.NewID(utils.IDTypeNone),
+			BoardID:          testBoardID,
+			Token:            utils.NewID(utils.IDTypeToken),
+			Name:             "Roadmap",
+			ViewID:           "view-id",
+			HiddenProperties: []string{"estimate", "owner"},
+			ExpiresAt:        5000,
+			PasswordHash:     "hash",
+			HasPassword:      true,
+			CreatedBy:        testUserID,
+			CreateAt:         1000,
+			UpdateAt:         1000,
+		}
+		require.NoError(t, store.CreateShareLink(link))
+
+		rLink, err := store.GetShareLink(link.ID)
+		require.NoError(t, err)
+		require.Equal(t, link, rLink)
+
+		rLink, err = store.GetShareLinkByToken(link.Token)
+		require.NoError(t, err)
+		require.Equal(t, link, rLink)
+	})
+
+	t.Run("get the links of a board", func(t *testing.T) {
+		link1 := createTestShareLink(t, store, "board-links", 2000)
+		link2 := createTestShareLink(t, store, "board-links", 3000)
+		createTestShareLink(t, store, "other-board", 2000)
+
+		links, err := store.GetShareLinksForBoard("board-links")
+		require.NoError(t, err)
+		require.Equal(t, []*model.ShareLink{link1, link2}, links)
+	})
+
+	t.Run("get a nonexistent link", func(t *testing.T) {
+		_, err := store.GetShareLink("nonexistent")
+		require.True(t, model.IsErrNotFound(err))
+
+		_, err = store.GetShareLinkByToken("nonexistent")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testUpdateShareLink(t *testing.T, store store.Store) {
+	t.Run("update a link", func(t *testing.T) {
+		link := createTestShareLink(t, store, testBoardID, 1000)
+		token := link.Token
+
+		link.Name = "Public roadmap"
+		link.HiddenProperties = []string{"estimate"}
+		link.ExpiresAt = 9000
+		link.PasswordHash = "hash"
+		link.HasPassword = true
+		link.UpdateAt = 2000
+		link.Token = "ignored"
+		require.NoError(t, store.UpdateShareLink(link))
+
+		rLink, err := store.GetShareLink(link.ID)
+		require.NoError(t, err)
+		link.Token = token
+		require.Equal(t, link, rLink)
+	})
+
+	t.Run("update a nonexistent link", func(t *testing.T) {
+		err := store.UpdateShareLink(&model.ShareLink{ID: "nonexistent"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testIncrementShareLinkViews(t *testing.T, store store.Store) {
+	link := createTestShareLink(t, store, testBoardID, 1000)
+
+	require.NoError(t, store.IncrementShareLinkViews(link.ID))
+	require.NoError(t, store.IncrementShareLinkViews(link.ID))
+
+	rLink, err := store.GetShareLink(link.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rLink.ViewCount)
+	require.Greater(t, rLink.LastViewedAt, int64(0))
+}
+
+func testDeleteShareLink(t *testing.T, store store.Store) {
+	link := createTestShareLink(t, store, testBoardID, 1000)
+	other := createTestShareLink(t, store, testBoardID, 2000)
+
+	require.NoError(t, store.DeleteShareLink(link.ID))
+
+	_, err := store.GetShareLink(link.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	rLink, err := store.GetShareLink(other.ID)
+	require.NoError(t, err)
+	require.Equal(t, other, rLink)
+}
@@ -40,6 +40,18 @@ func StoreTestTeamStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		defer tearDown()
 		testGetAllTeams(t, store)
 	})
+
+	t.Run("CreateUpdateAndDeleteTeam", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateUpdateAndDeleteTeam(t, store)
+	})
+
+	t.Run("TeamMembers", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testTeamMembers(t, store)
+	})
 }
 
 func testGetTeam(t *testing.T, store store.Store) {
@@ -155,3 +167,146 @@ func testGetAllTeams(t *testing.T, store store.Store) {
 		require.Len(t, got, teamCount)
 	})
 }
+
+func testCreateUpdateAndDeleteTeam(t *testing.T, store store.Store) {
+	team := &model.Team{
+		ID:          utils.NewID(utils.IDTypeTeam),
+		Title:       "Team 1",
+		SignupToken: utils.NewID(utils.IDTypeToken),
+		ModifiedBy:  "user-id",
+		UpdateAt:    utils.GetMillis(),
+	}
+
+	t.Run("Create team", func(t *testing.T) {
+		err := store.CreateTeam(team)
+		require.NoError(t, err)
+
+		got, err := store.GetTeam(team.ID)
+		require.NoError(t, err)
+		require.Equal(t, team.Title, got.Title)
+		require.Equal(t, team.SignupToken, got.SignupToken)
+	})
+
+	t.Run("Get team by signup token", func(t *testing.T) {
+		got, err := store.GetTeamBySignupToken(team.SignupToken)
+		require.NoError(t, err)
+		require.Equal(t, team.ID, got.ID)
+
+		got, err = store.GetTeamBySignupToken("nonexistent-token")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, got)
+	})
+
+	t.Run("Update team", func(t *testing.T) {
+		team.Title = "Team 1 renamed"
+		err := store.UpdateTeam(team)
+		require.NoError(t, err)
+
+		got, err := store.GetTeam(team.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Team 1 renamed", got.Title)
+
+		err = store.UpdateTeam(&model.Team{ID: "nonexistent-id", Title: "title"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("Count the boards of the team", func(t *testing.T) {
+		count, err := store.GetTeamBoardCount(team.ID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		_, err = store.InsertBoard(&model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: team.ID, Type: model.BoardTypeOpen}, "user-id")
+		require.NoError(t, err)
+
+		count, err = store.GetTeamBoardCount(team.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("Delete team", func(t *testing.T) {
+		err := store.SaveTeamMember(&model.TeamMember{TeamID: team.ID, UserID: "user-id"})
+		require.NoError(t, err)
+
+		err = store.DeleteTeam(team.ID)
+		require.NoError(t, err)
+
+		got, err := store.GetTeam(team.ID)
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, got)
+
+		members, err := store.GetTeamMembers(team.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+}
+
+func testTeamMembers(t *testing.T, store store.Store) {
+	for _, teamID := range []string{"team-1", "team-2", "team-3"} {
+		err := store.CreateTeam(&model.Team{ID: teamID, Title: teamID})
+		require.NoError(t, err)
+	}
+
+	t.Run("Nonexistent member", func(t *testing.T) {
+		got, err := store.GetTeamMember("team-1", "user-1")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, got)
+	})
+
+	t.Run("Save and update members", func(t *testing.T) {
+		err := store.SaveTeamMember(&model.TeamMember{TeamID: "team-1", UserID: "user-1", CreateAt: 1})
+		require.NoError(t, err)
+		err = store.SaveTeamMember(&model.TeamMember{TeamID: "team-1", UserID: "user-2", CreateAt: 2})
+		require.NoError(t, err)
+		err = store.SaveTeamMember(&model.TeamMember{TeamID: "team-2", UserID: "user-1", CreateAt: 3})
+		require.NoError(t, err)
+
+		members, err := store.GetTeamMembers("team-1")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		require.Equal(t, "user-1", members[0].UserID)
+		require.False(t, members[0].SchemeAdmin)
+
+		err = store.SaveTeamMember(&model.TeamMember{TeamID: "team-1", UserID: "user-1", SchemeAdmin: true, CreateAt: 1})
+		require.NoError(t, err)
+
+		member, err := store.GetTeamMember("team-1", "user-1")
+		require.NoError(t, err)
+		require.True(t, member.SchemeAdmin)
+		require.Equal(t, int64(1), member.CreateAt)
+	})
+
+	t.Run("Count members", func(t *testing.T) {
+		count, err := store.GetTeamMemberCount("team-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+
+		count, err = store.GetTeamMemberCount("team-3")
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("Get teams for user", func(t *testing.T) {
+		teams, err := store.GetTeamsForUser("user-1")
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		require.Equal(t, "team-1", teams[0].ID)
+		require.Equal(t, "team-2", teams[1].ID)
+
+		teams, err = store.GetTeamsForUser("user-3")
+		require.NoError(t, err)
+		require.Empty(t, teams)
+	})
+
+	t.Run("Delete member", func(t *testing.T) {
+		err := store.DeleteTeamMember("team-1", "user-1")
+		require.NoError(t, err)
+
+		_, err = store.GetTeamMember("team-1", "user-1")
+		require.True(t, model.IsErrNotFound(err))
+
+		teams, err := store.GetTeamsForUser("user-1")
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		require.Equal(t, "team-2", teams[0].ID)
+	})
+}
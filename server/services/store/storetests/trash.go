@@ -0,0 +1,232 @@
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestTrashStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetDeletedBoards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDeletedBoards(t, store)
+	})
+	t.Run("GetDeletedBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDeletedBlocks(t, store)
+	})
+	t.Run("PurgeDeletedBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeDeletedBoard(t, store)
+	})
+	t.Run("PurgeDeletedBlock", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeDeletedBlock(t, store)
+	})
+	t.Run("PurgeTrash", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeTrash(t, store)
+	})
+}
+
+func testGetDeletedBoards(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 3)
+	otherTeamBoards := createTestBoards(t, store, "other-team-id", testUserID, 1)
+	time.Sleep(1 * time.Millisecond)
+
+	t.Run("no deleted boards", func(t *testing.T) {
+		deleted, err := store.GetDeletedBoards(model.QueryTrashOptions{TeamID: testTeamID})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	deleteTestBoard(t, store, boards[0].ID, "deleter-id")
+	deleteTestBoard(t, store, boards[1].ID, testUserID)
+	deleteTestBoard(t, store, otherTeamBoards[0].ID, testUserID)
+
+	t.Run("deleted boards of a team", func(t *testing.T) {
+		deleted, err := store.GetDeletedBoards(model.QueryTrashOptions{TeamID: testTeamID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{boards[0].ID, boards[1].ID}, extractIDs(t, deleted))
+
+		for _, board := range deleted {
+			require.NotZero(t, board.DeleteAt)
+			if board.ID == boards[0].ID {
+				require.Equal(t, "deleter-id", board.ModifiedBy)
+			}
+		}
+	})
+
+	t.Run("undeleted boards are not in the trash", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.UndeleteBoard(boards[1].ID, testUserID))
+
+		deleted, err := store.GetDeletedBoards(model.QueryTrashOptions{TeamID: testTeamID})
+		require.NoError(t, err)
+		require.Equal(t, []string{boards[0].ID}, extractIDs(t, deleted))
+	})
+
+	t.Run("deleted boards before a date", func(t *testing.T) {
+		deleted, err := store.GetDeletedBoards(model.QueryTrashOptions{DeletedBefore: utils.GetMillisForTime(time.Now().Add(-time.Hour))})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		deleted, err = store.GetDeletedBoards(model.QueryTrashOptions{DeletedBefore: utils.GetMillisForTime(time.Now().Add(time.Hour))})
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+	})
+}
+
+func testGetDeletedBlocks(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 1)
+	cards := createTestCards(t, store, testUserID, boards[0].ID, 3)
+	contents := createTestBlocksForCard(t, store, cards[0].ID, 2)
+	time.Sleep(1 * time.Millisecond)
+
+	require.NoError(t, store.DeleteBlock(cards[0].ID, "deleter-id"))
+	require.NoError(t, store.DeleteBlock(cards[1].ID, testUserID))
+
+	t.Run("deleted cards of a board", func(t *testing.T) {
+		deleted, err := store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boards[0].ID, BlockType: model.TypeCard})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{cards[0].ID, cards[1].ID}, extractIDs(t, deleted))
+
+		for _, card := range deleted {
+			require.NotZero(t, card.DeleteAt)
+			if card.ID == cards[0].ID {
+				require.Equal(t, "deleter-id", card.ModifiedBy)
+			}
+		}
+	})
+
+	t.Run("deleted blocks of any type", func(t *testing.T) {
+		deleted, err := store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boards[0].ID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{cards[0].ID, cards[1].ID, contents[0].ID, contents[1].ID}, extractIDs(t, deleted))
+	})
+
+	t.Run("undeleted blocks are not in the trash", func(t *testing.T) {
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.UndeleteBlock(cards[1].ID, testUserID))
+
+		deleted, err := store.GetDeletedBlocks(model.QueryTrashOptions{BoardID: boards[0].ID, BlockType: model.TypeCard})
+		require.NoError(t, err)
+		require.Equal(t, []string{cards[0].ID}, extractIDs(t, deleted))
+	})
+}
+
+func testPurgeDeletedBoard(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 2)
+	createTestCards(t, store, testUserID, boards[0].ID, 2)
+	createTestShareLink(t, store, boards[0].ID, 1000)
+	otherLink := createTestShareLink(t, store, boards[1].ID, 1000)
+	time.Sleep(1 * time.Millisecond)
+
+	t.Run("boards that aren't deleted can't be purged", func(t *testing.T) {
+		err := store.PurgeDeletedBoard(boards[0].ID)
+		require.True(t, model.IsErrBadRequest(err), err)
+	})
+
+	t.Run("unknown boards can't be purged", func(t *testing.T) {
+		err := store.PurgeDeletedBoard("unknown-board-id")
+		require.True(t, model.IsErrNotFound(err), err)
+	})
+
+	t.Run("purge a deleted board", func(t *testing.T) {
+		deleteTestBoard(t, store, boards[0].ID, testUserID)
+		require.NoError(t, store.PurgeDeletedBoard(boards[0].ID))
+
+		history, err := store.GetBoardHistory(boards[0].ID, model.QueryBoardHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, history)
+
+		blocks, err := store.GetBlockHistoryDescendants(boards[0].ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+
+		deleted, err := store.GetDeletedBoards(model.QueryTrashOptions{TeamID: testTeamID})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		links, err := store.GetShareLinksForBoard(boards[0].ID)
+		require.NoError(t, err)
+		require.Empty(t, links)
+
+		// other boards are kept
+		_, err = store.GetBoard(boards[1].ID)
+		require.NoError(t, err)
+		_, err = store.GetShareLink(otherLink.ID)
+		require.NoError(t, err)
+	})
+}
+
+func testPurgeDeletedBlock(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 1)
+	cards := createTestCards(t, store, testUserID, boards[0].ID, 2)
+	contents := createTestBlocksForCard(t, store, cards[0].ID, 2)
+	time.Sleep(1 * time.Millisecond)
+
+	t.Run("blocks that aren't deleted can't be purged", func(t *testing.T) {
+		err := store.PurgeDeletedBlock(cards[0].ID)
+		require.True(t, model.IsErrBadRequest(err), err)
+	})
+
+	t.Run("purge a deleted card and its contents", func(t *testing.T) {
+		require.NoError(t, store.DeleteBlock(cards[0].ID, testUserID))
+		require.NoError(t, store.PurgeDeletedBlock(cards[0].ID))
+
+		for _, blockID := range []string{cards[0].ID, contents[0].ID, contents[1].ID} {
+			history, err := store.GetBlockHistory(blockID, model.QueryBlockHistoryOptions{})
+			require.NoError(t, err)
+			require.Empty(t, history)
+		}
+
+		history, err := store.GetBlockHistory(cards[1].ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.NotEmpty(t, history)
+	})
+}
+
+func testPurgeTrash(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 2)
+	cards := createTestCards(t, store, testUserID, boards[1].ID, 2)
+
+	deleteTestBoard(t, store, boards[0].ID, testUserID)
+	require.NoError(t, store.DeleteBlock(cards[0].ID, testUserID))
+
+	t.Run("nothing deleted before the date", func(t *testing.T) {
+		affected, err := store.PurgeTrash(utils.GetMillisForTime(time.Now().Add(-time.Hour)), 0)
+		require.NoError(t, err)
+		require.Zero(t, affected)
+	})
+
+	t.Run("purge everything deleted before the date", func(t *testing.T) {
+		affected, err := store.PurgeTrash(utils.GetMillisForTime(time.Now().Add(time.Hour)), 0)
+		require.NoError(t, err)
+		require.NotZero(t, affected)
+
+		deletedBoards, err := store.GetDeletedBoards(model.QueryTrashOptions{})
+		require.NoError(t, err)
+		require.Empty(t, deletedBoards)
+
+		deletedBlocks, err := store.GetDeletedBlocks(model.QueryTrashOptions{})
+		require.NoError(t, err)
+		require.Empty(t, deletedBlocks)
+
+		// live boards and blocks are kept
+		_, err = store.GetBoard(boards[1].ID)
+		require.NoError(t, err)
+		_, err = store.GetBlock(cards[1].ID)
+		require.NoError(t, err)
+	})
+}
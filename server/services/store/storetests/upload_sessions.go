@@ -0,0 +1,176 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestUploadSessionsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAndGetUploadSession", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAndGetUploadSession(t, store)
+	})
+	t.Run("UpdateUploadSession", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUpdateUploadSession(t, store)
+	})
+	t.Run("ClaimUploadSession", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testClaimUploadSession(t, store)
+	})
+	t.Run("GetUploadSessionsUpdatedBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUploadSessionsUpdatedBefore(t, store)
+	})
+	t.Run("DeleteUploadSession", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteUploadSession(t, store)
+	})
+}
+
+func createTestUploadSession(t *testing.T, store store.Store, updateAt int64) *model.UploadSession {
+	id := utils.NewID(utils.IDTypeNone)
+	session := &model.UploadSession{
+		ID:       id,
+		TeamID:   testTeamID,
+		BoardID:  testBoardID,
+		UserID:   testUserID,
+		Filename: "video.mp4",
+		Path:     "uploads/" + id,
+		FileSize: 1000,
+		CreateAt: updateAt,
+		UpdateAt: updateAt,
+	}
+	require.NoError(t, store.CreateUploadSession(session))
+	return session
+}
+
+func testCreateAndGetUploadSession(t *testing.T, store store.Store) {
+	t.Run("get an existing session", func(t *testing.T) {
+		session := createTestUploadSession(t, store, 1000)
+
+		rSession, err := store.GetUploadSession(session.ID)
+		require.NoError(t, err)
+		require.Equal(t, session, rSession)
+	})
+
+	t.Run("get a session with a checksum", func(t *testing.T) {
+		session := &model.UploadSession{
+			ID:       utils.NewID(utils.IDTypeNone),
+			TeamID:   testTeamID,
+			BoardID:  testBoardID,
+			UserID:   testUserID,
+			Filename: "archive.zip",
+			Path:     "uploads/archive",
+			FileSize: 10,
+			Checksum: "1f5f0c3d3a8ea7f3e0b8bc4c8b6ee3a3e1e0f4b2a4b5f1a9c7d0e8f2b3c4d5e6",
+			CreateAt: 1000,
+			UpdateAt: 1000,
+		}
+		require.NoError(t, store.CreateUploadSession(session))
+
+		rSession, err := store.GetUploadSession(session.ID)
+		require.NoError(t, err)
+		require.Equal(t, session.Checksum, rSession.Checksum)
+	})
+
+	t.Run("get a nonexistent session", func(t *testing.T) {
+		rSession, err := store.GetUploadSession("nonexistent")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, rSession)
+	})
+}
+
+func testUpdateUploadSession(t *testing.T, store store.Store) {
+	t.Run("update the progress of a session", func(t *testing.T) {
+		session := createTestUploadSession(t, store, 1000)
+		session.FileOffset = 500
+		session.UpdateAt = 2000
+		require.NoError(t, store.UpdateUploadSession(session))
+
+		rSession, err := store.GetUploadSession(session.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(500), rSession.FileOffset)
+		require.Equal(t, int64(2000), rSession.UpdateAt)
+		require.Equal(t, int64(1000), rSession.CreateAt)
+	})
+
+	t.Run("update a nonexistent session", func(t *testing.T) {
+		err := store.UpdateUploadSession(&model.UploadSession{ID: "nonexistent", FileOffset: 10})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testClaimUploadSession(t *testing.T, store store.Store) {
+	t.Run("claim a session at its offset", func(t *testing.T) {
+		session := createTestUploadSession(t, store, 1000)
+		require.NoError(t, store.ClaimUploadSession(session.ID, 0, 2000, 3000))
+
+		// the session is claimed until the claim expires
+		err := store.ClaimUploadSession(session.ID, 0, 2500, 3500)
+		require.True(t, model.IsErrConflict(err))
+		require.NoError(t, store.ClaimUploadSession(session.ID, 0, 3000, 4000))
+	})
+
+	t.Run("claim a session at another offset", func(t *testing.T) {
+		session := createTestUploadSession(t, store, 1000)
+		err := store.ClaimUploadSession(session.ID, 500, 2000, 3000)
+		require.True(t, model.IsErrConflict(err))
+	})
+
+	t.Run("the claim is released when the session is updated", func(t *testing.T) {
+		session := createTestUploadSession(t, store, 1000)
+		require.NoError(t, store.ClaimUploadSession(session.ID, 0, 2000, 3000))
+
+		session.FileOffset = 500
+		session.UpdateAt = 2500
+		require.NoError(t, store.UpdateUploadSession(session))
+
+		err := store.ClaimUploadSession(session.ID, 0, 2500, 3500)
+		require.True(t, model.IsErrConflict(err))
+		require.NoError(t, store.ClaimUploadSession(session.ID, 500, 2500, 3500))
+	})
+
+	t.Run("claim a nonexistent session", func(t *testing.T) {
+		err := store.ClaimUploadSession("nonexistent", 0, 2000, 3000)
+		require.True(t, model.IsErrConflict(err))
+	})
+}
+
+func testGetUploadSessionsUpdatedBefore(t *testing.T, store store.Store) {
+	stale1 := createTestUploadSession(t, store, 1000)
+	stale2 := createTestUploadSession(t, store, 2000)
+	createTestUploadSession(t, store, 3000)
+
+	sessions, err := store.GetUploadSessionsUpdatedBefore(3000, 10)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, stale1.ID, sessions[0].ID)
+	require.Equal(t, stale2.ID, sessions[1].ID)
+
+	sessions, err = store.GetUploadSessionsUpdatedBefore(3000, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, stale1.ID, sessions[0].ID)
+}
+
+func testDeleteUploadSession(t *testing.T, store store.Store) {
+	session := createTestUploadSession(t, store, 1000)
+	require.NoError(t, store.DeleteUploadSession(session.ID))
+
+	_, err := store.GetUploadSession(session.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	// deleting a nonexistent session is a no-op
+	require.NoError(t, store.DeleteUploadSession(session.ID))
+}
@@ -0,0 +1,193 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestUserGroupsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAndGetUserGroup", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAndGetUserGroup(t, store)
+	})
+	t.Run("UserGroupMembers", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserGroupMembers(t, store)
+	})
+	t.Run("BoardGroups", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testBoardGroups(t, store)
+	})
+	t.Run("DeleteUserGroup", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteUserGroup(t, store)
+	})
+}
+
+func createTestUserGroup(t *testing.T, store store.Store, teamID, name string) *model.UserGroup {
+	group := &model.UserGroup{
+		ID:          utils.NewID(utils.IDTypeNone),
+		TeamID:      teamID,
+		Name:        name,
+		Description: "a test group",
+		CreatedBy:   "user-id",
+		CreateAt:    utils.GetMillis(),
+		UpdateAt:    utils.GetMillis(),
+	}
+	require.NoError(t, store.CreateUserGroup(group))
+	return group
+}
+
+func testCreateAndGetUserGroup(t *testing.T, store store.Store) {
+	t.Run("nonexistent group", func(t *testing.T) {
+		group, err := store.GetUserGroup("nonexistent-id")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, group)
+	})
+
+	t.Run("groups of a team", func(t *testing.T) {
+		group1 := createTestUserGroup(t, store, "team-1", "reviewers")
+		group2 := createTestUserGroup(t, store, "team-1", "designers")
+		createTestUserGroup(t, store, "team-2", "reviewers")
+
+		got, err := store.GetUserGroup(group1.ID)
+		require.NoError(t, err)
+		require.Equal(t, group1, got)
+
+		got, err = store.GetUserGroupByName("team-1", "reviewers")
+		require.NoError(t, err)
+		require.Equal(t, group1, got)
+
+		_, err = store.GetUserGroupByName("team-3", "reviewers")
+		require.True(t, model.IsErrNotFound(err))
+
+		groups, err := store.GetUserGroupsForTeam("team-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.UserGroup{group2, group1}, groups)
+	})
+
+	t.Run("update a group", func(t *testing.T) {
+		group := createTestUserGroup(t, store, "team-1", "testers")
+		group.Name = "qa"
+		group.Description = ""
+		group.UpdateAt = utils.GetMillis() + 1
+		require.NoError(t, store.UpdateUserGroup(group))
+
+		got, err := store.GetUserGroup(group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group, got)
+
+		err = store.UpdateUserGroup(&model.UserGroup{ID: "nonexistent-id"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testUserGroupMembers(t *testing.T, store store.Store) {
+	group := createTestUserGroup(t, store, "team-1", "reviewers")
+
+	userIDs, err := store.GetUserGroupMemberIDs(group.ID)
+	require.NoError(t, err)
+	require.Empty(t, userIDs)
+
+	require.NoError(t, store.AddUserGroupMember(group.ID, "user-1", 1))
+	require.NoError(t, store.AddUserGroupMember(group.ID, "user-2", 2))
+
+	userIDs, err = store.GetUserGroupMemberIDs(group.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user-1", "user-2"}, userIDs)
+
+	require.NoError(t, store.DeleteUserGroupMember(group.ID, "user-1"))
+	err = store.DeleteUserGroupMember(group.ID, "user-1")
+	require.True(t, model.IsErrNotFound(err))
+
+	userIDs, err = store.GetUserGroupMemberIDs(group.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user-2"}, userIDs)
+}
+
+func testBoardGroups(t *testing.T, store store.Store) {
+	boardGroup := &model.BoardGroup{
+		BoardID:      "board-1",
+		GroupID:      "group-1",
+		SchemeEditor: true,
+		CreateAt:     1,
+	}
+	require.NoError(t, store.SaveBoardGroup(boardGroup))
+	require.NoError(t, store.SaveBoardGroup(&model.BoardGroup{BoardID: "board-2", GroupID: "group-1", SchemeViewer: true, CreateAt: 2}))
+	require.NoError(t, store.SaveBoardGroup(&model.BoardGroup{BoardID: "board-1", GroupID: "group-2", SchemeViewer: true, CreateAt: 3}))
+
+	got, err := store.GetBoardGroup("board-1", "group-1")
+	require.NoError(t, err)
+	require.Equal(t, boardGroup, got)
+
+	// saving the link again updates its roles
+	boardGroup.SchemeEditor = false
+	boardGroup.SchemeCommenter = true
+	boardGroup.CustomRoleID = "role-id"
+	require.NoError(t, store.SaveBoardGroup(boardGroup))
+
+	got, err = store.GetBoardGroup("board-1", "group-1")
+	require.NoError(t, err)
+	require.Equal(t, boardGroup, got)
+
+	boardGroups, err := store.GetBoardGroupsForBoard("board-1")
+	require.NoError(t, err)
+	require.Len(t, boardGroups, 2)
+
+	boardGroups, err = store.GetBoardGroupsForGroup("group-1")
+	require.NoError(t, err)
+	require.Len(t, boardGroups, 2)
+
+	require.NoError(t, store.DeleteBoardGroup("board-1", "group-1"))
+	err = store.DeleteBoardGroup("board-1", "group-1")
+	require.True(t, model.IsErrNotFound(err))
+
+	_, err = store.GetBoardGroup("board-1", "group-1")
+	require.True(t, model.IsErrNotFound(err))
+
+	t.Run("members added by a group", func(t *testing.T) {
+		member, err := store.SaveMember(boardGroup.MemberFor("user-id"))
+		require.NoError(t, err)
+		require.Equal(t, "group-1", member.GroupID)
+
+		got, err := store.GetMemberForBoard(boardGroup.BoardID, "user-id")
+		require.NoError(t, err)
+		require.Equal(t, "group-1", got.GroupID)
+
+		// saving the member directly clears its group
+		_, err = store.SaveMember(&model.BoardMember{BoardID: boardGroup.BoardID, UserID: "user-id", SchemeEditor: true})
+		require.NoError(t, err)
+
+		got, err = store.GetMemberForBoard(boardGroup.BoardID, "user-id")
+		require.NoError(t, err)
+		require.Empty(t, got.GroupID)
+	})
+}
+
+func testDeleteUserGroup(t *testing.T, store store.Store) {
+	group := createTestUserGroup(t, store, "team-1", "reviewers")
+	require.NoError(t, store.AddUserGroupMember(group.ID, "user-1", 1))
+	require.NoError(t, store.SaveBoardGroup(&model.BoardGroup{BoardID: "board-1", GroupID: group.ID, SchemeViewer: true, CreateAt: 1}))
+
+	require.NoError(t, store.DeleteUserGroup(group.ID))
+
+	_, err := store.GetUserGroup(group.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	userIDs, err := store.GetUserGroupMemberIDs(group.ID)
+	require.NoError(t, err)
+	require.Empty(t, userIDs)
+
+	boardGroups, err := store.GetBoardGroupsForGroup(group.ID)
+	require.NoError(t, err)
+	require.Empty(t, boardGroups)
+}
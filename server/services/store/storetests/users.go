@@ -52,6 +52,30 @@ func StoreTestUserStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		defer tearDown()
 		testPatchUserProps(t, store)
 	})
+
+	t.Run("UserRolesAndActivation", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserRolesAndActivation(t, store)
+	})
+
+	t.Run("CreateDeactivatedUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateDeactivatedUser(t, store)
+	})
+
+	t.Run("GuestUsers", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGuestUsers(t, store)
+	})
+
+	t.Run("AnonymizeUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAnonymizeUser(t, store)
+	})
 }
 
 func testGetUsersByTeam(t *testing.T, store store.Store) {
@@ -71,6 +95,21 @@ func testGetUsersByTeam(t *testing.T, store store.Store) {
 		require.Equal(t, userID, user.ID)
 		require.Equal(t, "darth.vader", user.Username)
 
+		// the teams without members are open to every user
+		users, err = store.GetUsersByTeam("team_1", "", false, false)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+
+		// otherwise only the members of the team are returned
+		err = store.SaveTeamMember(&model.TeamMember{TeamID: "team_1", UserID: utils.NewID(utils.IDTypeUser), CreateAt: utils.GetMillis()})
+		require.NoError(t, err)
+		users, err = store.GetUsersByTeam("team_1", "", false, false)
+		require.NoError(t, err)
+		require.Empty(t, users)
+
+		err = store.SaveTeamMember(&model.TeamMember{TeamID: "team_1", UserID: userID, CreateAt: utils.GetMillis()})
+		require.NoError(t, err)
+
 		defer func() {
 			_, _ = store.UpdateUser(&model.User{
 				ID:       userID,
@@ -248,6 +287,147 @@ func testCreateAndUpdateUser(t *testing.T, store store.Store) {
 	})
 }
 
+func testUserRolesAndActivation(t *testing.T, store store.Store) {
+	admin, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "admin",
+		Roles:    model.SystemRoles(true),
+	})
+	require.NoError(t, err)
+	user, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "user",
+		Roles:    model.SystemRoles(false),
+	})
+	require.NoError(t, err)
+
+	t.Run("roles", func(t *testing.T) {
+		got, err := store.GetUserByID(admin.ID)
+		require.NoError(t, err)
+		require.True(t, got.IsSystemAdmin())
+
+		require.NoError(t, store.UpdateUserRoles(user.ID, model.SystemRoles(true)))
+		got, err = store.GetUserByID(user.ID)
+		require.NoError(t, err)
+		require.True(t, got.IsSystemAdmin())
+
+		err = store.UpdateUserRoles("nonexistent", model.SystemRoles(true))
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("pages of users", func(t *testing.T) {
+		page0, err := store.GetAllUsers(model.UserQueryOptions{Page: 0, PerPage: 1})
+		require.NoError(t, err)
+		require.Len(t, page0, 1)
+
+		// both users can be created in the same millisecond, so only
+		// the pages not overlapping is checked
+		page1, err := store.GetAllUsers(model.UserQueryOptions{Page: 1, PerPage: 1})
+		require.NoError(t, err)
+		require.Len(t, page1, 1)
+		require.ElementsMatch(t, []string{admin.ID, user.ID}, []string{page0[0].ID, page1[0].ID})
+	})
+
+	t.Run("deactivation", func(t *testing.T) {
+		require.NoError(t, store.UpdateUserActive(user.ID, false))
+
+		_, err := store.GetUserByID(user.ID)
+		require.True(t, model.IsErrNotFound(err))
+		got, err := store.GetUserByIDIncludingDeactivated(user.ID)
+		require.NoError(t, err)
+		require.NotZero(t, got.DeleteAt)
+
+		users, err := store.GetAllUsers(model.UserQueryOptions{})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		users, err = store.GetAllUsers(model.UserQueryOptions{IncludeDeactivated: true})
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+
+		require.NoError(t, store.UpdateUserActive(user.ID, true))
+		got, err = store.GetUserByID(user.ID)
+		require.NoError(t, err)
+		require.Zero(t, got.DeleteAt)
+	})
+}
+
+func testGuestUsers(t *testing.T, store store.Store) {
+	teamID := "team_1"
+	users := map[string]*model.User{}
+	for _, username := range []string{"guest", "member", "other"} {
+		user, err := store.CreateUser(&model.User{
+			ID:       utils.NewID(utils.IDTypeUser),
+			Username: username,
+			IsGuest:  username == "guest",
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.SaveTeamMember(&model.TeamMember{TeamID: teamID, UserID: user.ID, CreateAt: utils.GetMillis()}))
+		users[username] = user
+	}
+
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: teamID,
+		Type:   model.BoardTypePrivate,
+	}, users["member"].ID)
+	require.NoError(t, err)
+	for _, username := range []string{"guest", "member"} {
+		_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: users[username].ID, SchemeViewer: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("guests only see the members of their boards", func(t *testing.T) {
+		got, err := store.GetUsersByTeam(teamID, users["guest"].ID, false, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"guest", "member"}, usernames(got))
+
+		got, err = store.SearchUsersByTeam(teamID, "", users["guest"].ID, false, false, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"guest", "member"}, usernames(got))
+
+		got, err = store.GetUsersByTeam(teamID, "", false, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"guest", "member", "other"}, usernames(got))
+
+		canSee, err := store.CanSeeUser(users["guest"].ID, users["member"].ID)
+		require.NoError(t, err)
+		require.True(t, canSee)
+		canSee, err = store.CanSeeUser(users["guest"].ID, users["other"].ID)
+		require.NoError(t, err)
+		require.False(t, canSee)
+		canSee, err = store.CanSeeUser(users["other"].ID, users["guest"].ID)
+		require.NoError(t, err)
+		require.True(t, canSee)
+	})
+
+	t.Run("guest expiration", func(t *testing.T) {
+		expireAt := utils.GetMillis() + 1000*60*60
+		require.NoError(t, store.UpdateUserGuest(users["other"].ID, true, expireAt))
+		got, err := store.GetUserByID(users["other"].ID)
+		require.NoError(t, err)
+		require.True(t, got.IsGuest)
+		require.Equal(t, expireAt, got.GuestExpireAt)
+		require.False(t, got.IsExpiredGuest())
+
+		require.NoError(t, store.UpdateUserGuest(users["other"].ID, false, expireAt))
+		got, err = store.GetUserByID(users["other"].ID)
+		require.NoError(t, err)
+		require.False(t, got.IsGuest)
+		require.Zero(t, got.GuestExpireAt)
+
+		err = store.UpdateUserGuest("nonexistent", true, 0)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func usernames(users []*model.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
 func testCreateAndGetRegisteredUserCount(t *testing.T, store store.Store) {
 	randomN := int(time.Now().Unix() % 10)
 	for i := 0; i < randomN; i++ {
@@ -343,3 +523,135 @@ func testPatchUserProps(t *testing.T, store store.Store) {
 		}
 	}
 }
+
+func testCreateDeactivatedUser(t *testing.T, store store.Store) {
+	active, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "active",
+		Email:    "active@example.com",
+	})
+	require.NoError(t, err)
+
+	// the users restored from a backup keep their deactivation
+	deactivated, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "deactivated",
+		Email:    "deactivated@example.com",
+		DeleteAt: 1234,
+	})
+	require.NoError(t, err)
+
+	_, err = store.GetUserByID(deactivated.ID)
+	require.True(t, model.IsErrNotFound(err))
+
+	users, err := store.GetAllUsers(model.UserQueryOptions{IncludeDeactivated: true})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	usersByID := map[string]*model.User{}
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+	require.Zero(t, usersByID[active.ID].DeleteAt)
+	require.Equal(t, int64(1234), usersByID[deactivated.ID].DeleteAt)
+}
+
+func testAnonymizeUser(t *testing.T, store store.Store) {
+	user, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "leaver",
+		Email:    "leaver@sample.com",
+		Password: "password",
+	})
+	require.NoError(t, err)
+	other, err := store.CreateUser(&model.User{ID: utils.NewID(utils.IDTypeUser), Username: "stayer"})
+	require.NoError(t, err)
+
+	board, err := store.InsertBoard(&model.Board{ID: utils.NewID(utils.IDTypeBoard), TeamID: testTeamID, Type: model.BoardTypeOpen}, user.ID)
+	require.NoError(t, err)
+	_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: user.ID, SchemeAdmin: true})
+	require.NoError(t, err)
+
+	now := utils.GetMillis()
+	card := &model.Block{
+		ID:       utils.NewID(utils.IDTypeCard),
+		BoardID:  board.ID,
+		ParentID: board.ID,
+		Type:     model.TypeCard,
+		Fields:   map[string]interface{}{"properties": map[string]interface{}{"owner": user.ID}},
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	require.NoError(t, store.InsertBlock(card, user.ID))
+	comment := &model.Block{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: card.ID,
+		Type:     model.TypeComment,
+		Title:    "thanks @leaver, and @leavers.",
+		CreateAt: now,
+		UpdateAt: now,
+	}
+	require.NoError(t, store.InsertBlock(comment, other.ID))
+
+	require.NoError(t, store.CreateSession(&model.Session{ID: "session-id", Token: "token", UserID: user.ID}))
+	require.NoError(t, store.CreateCategory(model.Category{ID: "category-id", Name: "Category", UserID: user.ID, TeamID: testTeamID, CreateAt: now, UpdateAt: now}))
+	_, err = store.CreateSubscription(&model.Subscription{BlockType: model.TypeCard, BlockID: card.ID, SubscriberType: "user", SubscriberID: user.ID})
+	require.NoError(t, err)
+
+	anonymousUser := &model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: model.DeletedUsernamePrefix + "user",
+		DeleteAt: utils.GetMillis(),
+	}
+	report, err := store.AnonymizeUser(user.ID, anonymousUser)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, report.UserID)
+	require.Equal(t, anonymousUser.ID, report.AnonymousID)
+	require.Equal(t, int64(1), report.Anonymized["boards.created_by"])
+	require.Equal(t, int64(1), report.Anonymized["blocks.created_by"])
+	require.Equal(t, int64(1), report.Anonymized["blocks.fields"])
+	require.Equal(t, int64(1), report.Anonymized["blocks.title"])
+	require.Equal(t, int64(1), report.Removed["board_members"])
+	require.Equal(t, int64(1), report.Removed["sessions"])
+	require.Equal(t, int64(1), report.Removed["categories"])
+	require.Equal(t, int64(1), report.Removed["subscriptions"])
+
+	t.Run("the user is replaced by an anonymous user", func(t *testing.T) {
+		_, err := store.GetUserByIDIncludingDeactivated(user.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		got, err := store.GetUserByIDIncludingDeactivated(anonymousUser.ID)
+		require.NoError(t, err)
+		require.Equal(t, anonymousUser.Username, got.Username)
+		require.Empty(t, got.Email)
+		require.Empty(t, got.Password)
+		require.NotZero(t, got.DeleteAt)
+	})
+
+	t.Run("the content is attributed to the anonymous user", func(t *testing.T) {
+		gotBoard, err := store.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, anonymousUser.ID, gotBoard.CreatedBy)
+
+		gotCard, err := store.GetBlock(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, anonymousUser.ID, gotCard.CreatedBy)
+		require.Equal(t, anonymousUser.ID, gotCard.Fields["properties"].(map[string]interface{})["owner"])
+
+		gotComment, err := store.GetBlock(comment.ID)
+		require.NoError(t, err)
+		require.Equal(t, other.ID, gotComment.CreatedBy)
+		require.Equal(t, "thanks @deleted-user, and @leavers.", gotComment.Title)
+	})
+
+	t.Run("the memberships of the user are removed", func(t *testing.T) {
+		members, err := store.GetMembersForUser(user.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+
+		members, err = store.GetMembersForBoard(board.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+}
@@ -3,6 +3,8 @@ package ws
 
 import (
 	"github.com/mattermost/focalboard/server/model"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 )
 
 const (
@@ -22,6 +24,11 @@ const (
 	websocketActionUpdateCardLimitTimestamp = "UPDATE_CARD_LIMIT_TIMESTAMP"
 	websocketActionReorderCategories        = "REORDER_CATEGORIES"
 	websocketActionReorderCategoryBoards    = "REORDER_CATEGORY_BOARDS"
+	websocketActionSubscribeText            = "SUBSCRIBE_TEXT"
+	websocketActionTextState                = "TEXT_STATE"
+	websocketActionTextOperation            = "TEXT_OPERATION"
+	websocketActionAckTextOperation         = "ACK_TEXT_OPERATION"
+	websocketActionRejectTextOperation      = "REJECT_TEXT_OPERATION"
 )
 
 type Store interface {
@@ -29,6 +36,29 @@ type Store interface {
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 }
 
+// TextEditor applies the collaborative text operations received from
+// the websocket clients.
+type TextEditor interface {
+	HasPermissionToBoard(userID, boardID string, permission *mmModel.Permission) bool
+	CanViewBlock(userID string, block *model.Block) bool
+	GetTextEditState(blockID string) (*model.TextEditState, error)
+	ApplyTextOperation(blockID string, revision int64, op *model.TextOperation, modifiedByID string) (*model.TextChange, error)
+}
+
+// BlockFilter filters the blocks broadcast to each user, hiding the
+// private cards and the restricted card properties it can't view, and
+// what the share links of the anonymous users don't share.
+type BlockFilter interface {
+	// NewBlockFilter returns the function filtering a block for each
+	// user it is broadcast to, nil if the user can't see the block.
+	NewBlockFilter(block *model.Block) func(userID string) *model.Block
+
+	// NewShareLinkBlockFilter returns the function filtering the blocks of
+	// a board for each share link they are broadcast to, nil if the link
+	// doesn't share the block.
+	NewShareLinkBlockFilter(boardID string) func(link *model.ShareLink, block *model.Block) *model.Block
+}
+
 type Adapter interface {
 	BroadcastBlockChange(teamID string, block *model.Block)
 	BroadcastBlockDelete(teamID, blockID, boardID string)
@@ -43,4 +73,6 @@ type Adapter interface {
 	BroadcastSubscriptionChange(teamID string, subscription *model.Subscription)
 	BroadcastCategoryReorder(teamID, userID string, categoryOrder []string)
 	BroadcastCategoryBoardsReorder(teamID, userID, categoryID string, boardsOrder []string)
+	CloseSessions(sessionIDs ...string)
+	CloseSessionsForUser(userID string)
 }
@@ -2,6 +2,7 @@ package ws
 
 import (
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 )
 
 // UpdateCategoryMessage is sent on block updates.
@@ -51,13 +52,42 @@ type UpdateCardLimitTimestamp struct {
 	Timestamp int64  `json:"timestamp"`
 }
 
+// TextStateMsg is sent to a client when it subscribes to the
+// collaborative edition of a text block.
+type TextStateMsg struct {
+	Action string               `json:"action"`
+	TeamID string               `json:"teamId"`
+	State  *model.TextEditState `json:"state"`
+}
+
+// TextOperationMsg is sent to the subscribers of a text block when an
+// operation is applied to it. The client that sent the operation
+// receives it as an acknowledgement instead.
+type TextOperationMsg struct {
+	Action string            `json:"action"`
+	TeamID string            `json:"teamId"`
+	Change *model.TextChange `json:"change"`
+}
+
+// RejectTextOperationMsg is sent to a client when its text operation
+// can't be applied. The client should fetch the text state again.
+type RejectTextOperationMsg struct {
+	Action  string `json:"action"`
+	TeamID  string `json:"teamId"`
+	BlockID string `json:"blockId"`
+	Error   string `json:"error"`
+}
+
 // WebsocketCommand is an incoming command from the client.
 type WebsocketCommand struct {
-	Action    string   `json:"action"`
-	TeamID    string   `json:"teamId"`
-	Token     string   `json:"token"`
-	ReadToken string   `json:"readToken"`
-	BlockIDs  []string `json:"blockIds"`
+	Action    string               `json:"action"`
+	TeamID    string               `json:"teamId"`
+	Token     string               `json:"token"`
+	ReadToken string               `json:"readToken"`
+	BlockIDs  []string             `json:"blockIds"`
+	BlockID   string               `json:"blockId"`
+	Revision  int64                `json:"revision"`
+	Operation *model.TextOperation `json:"operation,omitempty"`
 }
 
 type CategoryReorderMessage struct {
@@ -72,3 +102,42 @@ type CategoryBoardReorderMessage struct {
 	BoardOrder []string `json:"BoardOrder"`
 	TeamID     string   `json:"teamId"`
 }
+
+// blockMessageFilter returns the function giving the version of a block
+// update message each user can see, and the share link it subscribed
+// with, if any. The blocks of the private cards hidden to a user, and
+// the blocks a share link doesn't share, are sent as deleted, so the
+// clients remove the cards that became hidden.
+func blockMessageFilter(filter BlockFilter, message UpdateBlockMsg) func(userID string, link *model.ShareLink) UpdateBlockMsg {
+	if filter == nil {
+		return func(string, *model.ShareLink) UpdateBlockMsg { return message }
+	}
+
+	filterBlock := filter.NewBlockFilter(message.Block)
+	// the share link filter is only created when a block is broadcast to
+	// a share link
+	var filterSharedBlock func(*model.ShareLink, *model.Block) *model.Block
+	return func(userID string, link *model.ShareLink) UpdateBlockMsg {
+		block := filterBlock(userID)
+		if block != nil && link != nil {
+			if filterSharedBlock == nil {
+				filterSharedBlock = filter.NewShareLinkBlockFilter(message.Block.BoardID)
+			}
+			block = filterSharedBlock(link, block)
+		}
+		if block == nil {
+			now := utils.GetMillis()
+			block = &model.Block{
+				ID:       message.Block.ID,
+				ParentID: message.Block.ParentID,
+				BoardID:  message.Block.BoardID,
+				Type:     message.Block.Type,
+				UpdateAt: now,
+				DeleteAt: now,
+			}
+		}
+		userMessage := message
+		userMessage.Block = block
+		return userMessage
+	}
+}
@@ -47,6 +47,8 @@ type PluginAdapter struct {
 	subscriptionsMU  sync.RWMutex
 	listenersByTeam  map[string][]*PluginAdapterClient
 	listenersByBlock map[string][]*PluginAdapterClient
+
+	blockFilter BlockFilter
 }
 
 // servicesAPI is the interface required by the PluginAdapter to interact with
@@ -355,8 +357,11 @@ func (pa *PluginAdapter) WebSocketMessageHasBeenPosted(webConnID, userID string,
 	switch command.Action {
 	// The block-related commands are not implemented in the adapter
 	// as there is no such thing as unauthenticated websocket
-	// connections in plugin mode. Only a debug line is logged
-	case websocketActionSubscribeBlocks, websocketActionUnsubscribeBlocks:
+	// connections in plugin mode. Collaborative text editing relies
+	// on block subscriptions, so it's not available either. Only a
+	// debug line is logged
+	case websocketActionSubscribeBlocks, websocketActionUnsubscribeBlocks,
+		websocketActionSubscribeText, websocketActionTextOperation:
 		pa.logger.Debug(`Command not implemented in plugin mode`,
 			mlog.String("command", command.Action),
 			mlog.String("webConnID", webConnID),
@@ -398,6 +403,14 @@ func (pa *PluginAdapter) BroadcastConfigChange(pluginConfig model.ClientConfig)
 	pa.sendMessageToAll(websocketActionUpdateConfig, utils.StructToMap(pluginConfig))
 }
 
+// CloseSessions does nothing, the sessions and the websocket connections
+// are managed by Mattermost in plugin mode.
+func (pa *PluginAdapter) CloseSessions(sessionIDs ...string) {}
+
+// CloseSessionsForUser does nothing, the sessions and the websocket
+// connections are managed by Mattermost in plugin mode.
+func (pa *PluginAdapter) CloseSessionsForUser(userID string) {}
+
 // sendUserMessageSkipCluster sends the message to specific users.
 func (pa *PluginAdapter) sendUserMessageSkipCluster(event string, payload map[string]interface{}, userIDs ...string) {
 	for _, userID := range userIDs {
@@ -466,7 +479,48 @@ func (pa *PluginAdapter) BroadcastBlockChange(teamID string, block *model.Block)
 		Block:  block,
 	}
 
-	pa.sendBoardMessage(teamID, block.BoardID, utils.StructToMap(message))
+	if pa.blockFilter == nil {
+		pa.sendBoardMessage(teamID, block.BoardID, utils.StructToMap(message))
+		return
+	}
+
+	// the block is sent to the other nodes of the cluster as is, and
+	// each node filters it for the users connected to it
+	go func() {
+		clusterMessage := &ClusterMessage{
+			TeamID:  teamID,
+			BoardID: block.BoardID,
+			Block:   block,
+		}
+
+		pa.sendMessageToCluster(clusterMessage)
+	}()
+
+	pa.sendBlockChangeSkipCluster(teamID, block)
+}
+
+// sendBlockChangeSkipCluster sends a block change to all the users
+// subscribed to the team of the block that belong to its board, each
+// one receiving the version of the block it can see.
+func (pa *PluginAdapter) sendBlockChangeSkipCluster(teamID string, block *model.Block) {
+	message := UpdateBlockMsg{
+		Action: websocketActionUpdateBlock,
+		TeamID: teamID,
+		Block:  block,
+	}
+
+	// there are no share link subscriptions in plugin mode
+	messageForUser := blockMessageFilter(pa.blockFilter, message)
+	for _, userID := range pa.getUserIDsForTeamAndBoard(teamID, block.BoardID) {
+		payload := utils.StructToMap(messageForUser(userID, nil))
+		pa.sendUserMessageSkipCluster(websocketActionUpdateBoard, payload, userID)
+	}
+}
+
+// SetBlockFilter sets the filter of the blocks broadcast to each user. It
+// must be set before the adapter starts broadcasting.
+func (pa *PluginAdapter) SetBlockFilter(blockFilter BlockFilter) {
+	pa.blockFilter = blockFilter
 }
 
 func (pa *PluginAdapter) BroadcastCategoryChange(category model.Category) {
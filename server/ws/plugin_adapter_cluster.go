@@ -3,6 +3,8 @@ package ws
 import (
 	"encoding/json"
 
+	"github.com/mattermost/focalboard/server/model"
+
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -13,6 +15,10 @@ type ClusterMessage struct {
 	UserID      string
 	Payload     map[string]interface{}
 	EnsureUsers []string
+
+	// Block is set for the block changes, which each node filters for
+	// the users connected to it
+	Block *model.Block `json:",omitempty"`
 }
 
 func (pa *PluginAdapter) sendMessageToCluster(clusterMessage *ClusterMessage) {
@@ -51,6 +57,11 @@ func (pa *PluginAdapter) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
 		return
 	}
 
+	if clusterMessage.Block != nil {
+		pa.sendBlockChangeSkipCluster(clusterMessage.TeamID, clusterMessage.Block)
+		return
+	}
+
 	if clusterMessage.BoardID != "" {
 		pa.sendBoardMessageSkipCluster(clusterMessage.TeamID, clusterMessage.BoardID, clusterMessage.Payload, clusterMessage.EnsureUsers...)
 		return
@@ -53,14 +53,23 @@ type Server struct {
 	isMattermostAuth bool
 	logger           mlog.LoggerIFace
 	store            Store
+	textEditor       TextEditor
+	textEditorMu     sync.RWMutex
+	textBlockLocks   map[string]*textBlockLock
+	textBlockLocksMu sync.Mutex
+	blockFilter      BlockFilter
 }
 
 type websocketSession struct {
-	conn   *websocket.Conn
-	userID string
-	mu     sync.Mutex
-	teams  []string
-	blocks []string
+	conn      *websocket.Conn
+	userID    string
+	sessionID string
+	mu        sync.Mutex
+	teams     []string
+	blocks    []string
+	// shareLinks are the share links the blocks of each board were
+	// subscribed with, by board ID
+	shareLinks map[string]*model.ShareLink
 }
 
 func (wss *websocketSession) isAuthenticated() bool {
@@ -73,6 +82,7 @@ func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, l
 		listeners:        make(map[*websocketSession]bool),
 		listenersByTeam:  make(map[string][]*websocketSession),
 		listenersByBlock: make(map[string][]*websocketSession),
+		textBlockLocks:   make(map[string]*textBlockLock),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true
@@ -86,6 +96,12 @@ func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, l
 	}
 }
 
+// SetBlockFilter sets the filter of the blocks broadcast to each user. It
+// must be set before the server starts broadcasting.
+func (ws *Server) SetBlockFilter(blockFilter BlockFilter) {
+	ws.blockFilter = blockFilter
+}
+
 // RegisterRoutes registers routes.
 func (ws *Server) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/ws", ws.handleWebSocket)
@@ -161,7 +177,8 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
 			)
 
-			if !ws.isCommandReadTokenValid(command) {
+			boardID, shareLink, ok := ws.checkCommandReadToken(command)
+			if !ok {
 				ws.logger.Error(`Rejected invalid read token`,
 					mlog.Stringer("client", wsSession.conn.RemoteAddr()),
 					mlog.String("action", command.Action),
@@ -172,6 +189,7 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			}
 
 			ws.subscribeListenerToBlocks(wsSession, command.BlockIDs)
+			ws.setListenerShareLink(wsSession, boardID, shareLink)
 			continue
 		}
 
@@ -181,7 +199,7 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
 			)
 
-			if !ws.isCommandReadTokenValid(command) {
+			if _, _, ok := ws.checkCommandReadToken(command); !ok {
 				ws.logger.Error(`Rejected invalid read token`,
 					mlog.Stringer("client", wsSession.conn.RemoteAddr()),
 					mlog.String("action", command.Action),
@@ -238,17 +256,34 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			)
 
 			ws.unsubscribeListenerFromTeam(wsSession, command.TeamID)
+		case websocketActionSubscribeText:
+			ws.logger.Debug(`Command: SUBSCRIBE_TEXT`,
+				mlog.String("teamID", command.TeamID),
+				mlog.String("blockID", command.BlockID),
+				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
+			)
+
+			ws.subscribeListenerToText(wsSession, command)
+		case websocketActionTextOperation:
+			ws.logger.Trace(`Command: TEXT_OPERATION`,
+				mlog.String("teamID", command.TeamID),
+				mlog.String("blockID", command.BlockID),
+				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
+			)
+
+			ws.applyTextOperation(wsSession, command)
 		default:
 			ws.logger.Error(`ERROR webSocket command, invalid action`, mlog.String("action", command.Action))
 		}
 	}
 }
 
-// isCommandReadTokenValid ensures that a command contains a read
-// token and a set of block ids that said token is valid for.
-func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
+// checkCommandReadToken ensures that a command contains a read token
+// and a set of block ids that said token is valid for. It returns the
+// board of the blocks, and the share link the token opens, if any.
+func (ws *Server) checkCommandReadToken(command WebsocketCommand) (string, *model.ShareLink, bool) {
 	if len(command.TeamID) == 0 {
-		return false
+		return "", nil, false
 	}
 
 	boardID := ""
@@ -256,7 +291,7 @@ func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
 	for _, blockID := range command.BlockIDs {
 		block, err := ws.store.GetBlock(blockID)
 		if err != nil {
-			return false
+			return "", nil, false
 		}
 
 		if boardID == "" {
@@ -265,10 +300,24 @@ func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
 		}
 
 		if boardID != block.BoardID {
-			return false
+			return "", nil, false
 		}
 	}
 
+	// the share links are resolved, as the blocks broadcast to their
+	// subscribers are limited to what they share
+	link, err := ws.auth.GetShareLinkForReadToken(boardID, command.ReadToken)
+	if err != nil {
+		ws.logger.Error(`ERROR when checking token validity`,
+			mlog.String("teamID", command.TeamID),
+			mlog.Err(err),
+		)
+		return "", nil, false
+	}
+	if link != nil {
+		return boardID, link, true
+	}
+
 	// the read token must be valid for the board
 	isValid, err := ws.auth.IsValidReadToken(boardID, command.ReadToken)
 	if err != nil {
@@ -276,10 +325,10 @@ func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
 			mlog.String("teamID", command.TeamID),
 			mlog.Err(err),
 		)
-		return false
+		return "", nil, false
 	}
 
-	return isValid
+	return boardID, nil, isValid
 }
 
 // addListener adds a listener to the websocket server. The listener
@@ -357,6 +406,30 @@ func (ws *Server) subscribeListenerToBlocks(listener *websocketSession, blockIDs
 	}
 }
 
+// setListenerShareLink safely sets the share link a listener subscribed
+// to the blocks of a board with, nil if it used another read token.
+func (ws *Server) setListenerShareLink(listener *websocketSession, boardID string, link *model.ShareLink) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if link == nil {
+		delete(listener.shareLinks, boardID)
+		return
+	}
+	if listener.shareLinks == nil {
+		listener.shareLinks = map[string]*model.ShareLink{}
+	}
+	listener.shareLinks[boardID] = link
+}
+
+// getListenerShareLink returns the share link a listener subscribed to
+// the blocks of a board with, if any.
+func (ws *Server) getListenerShareLink(listener *websocketSession, boardID string) *model.ShareLink {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return listener.shareLinks[boardID]
+}
+
 // unsubscribeListenerFromBlocks safely modifies the listener and the
 // server data structures to remove the link between the listener and
 // a given set of block IDs.
@@ -416,20 +489,27 @@ func (ws *Server) removeListenerFromBlock(listener *websocketSession, blockID st
 }
 
 func (ws *Server) getUserIDForToken(token string) string {
+	userID, _ := ws.getSessionForToken(token)
+	return userID
+}
+
+// getSessionForToken returns the user ID and the session ID of a token.
+// The single user has no session.
+func (ws *Server) getSessionForToken(token string) (string, string) {
 	if len(ws.singleUserToken) > 0 {
 		if token == ws.singleUserToken {
-			return model.SingleUser
+			return model.SingleUser, ""
 		} else {
-			return ""
+			return "", ""
 		}
 	}
 
 	session, err := ws.auth.GetSession(token)
 	if session == nil || err != nil {
-		return ""
+		return "", ""
 	}
 
-	return session.UserID
+	return session.UserID, session.ID
 }
 
 func (ws *Server) authenticateListener(wsSession *websocketSession, token string) {
@@ -448,17 +528,65 @@ func (ws *Server) authenticateListener(wsSession *websocketSession, token string
 	}
 
 	// Authenticate session
-	userID := ws.getUserIDForToken(token)
+	userID, sessionID := ws.getSessionForToken(token)
 	if userID == "" {
 		wsSession.conn.Close()
 		return
 	}
 
 	// Authenticated
+	ws.mu.Lock()
 	wsSession.userID = userID
+	wsSession.sessionID = sessionID
+	ws.mu.Unlock()
 	ws.logger.Debug("authenticateListener: Authenticated", mlog.String("userID", userID), mlog.Stringer("client", wsSession.conn.RemoteAddr()))
 }
 
+// CloseSessions closes the connections authenticated with the given
+// sessions, e.g. when they are revoked. The clients have to authenticate
+// again to reconnect.
+func (ws *Server) CloseSessions(sessionIDs ...string) {
+	closed := make(map[string]bool, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		if sessionID != "" {
+			closed[sessionID] = true
+		}
+	}
+
+	ws.closeListeners(func(listener *websocketSession) bool {
+		return closed[listener.sessionID]
+	})
+}
+
+// CloseSessionsForUser closes all the connections of a user, e.g. when
+// its sessions are revoked or it is deactivated.
+func (ws *Server) CloseSessionsForUser(userID string) {
+	ws.closeListeners(func(listener *websocketSession) bool {
+		return listener.userID == userID
+	})
+}
+
+// closeListeners closes the connections of the listeners matching a
+// condition. The listeners are removed once their connection ends.
+func (ws *Server) closeListeners(matches func(listener *websocketSession) bool) {
+	ws.mu.RLock()
+	listeners := []*websocketSession{}
+	for listener := range ws.listeners {
+		if matches(listener) {
+			listeners = append(listeners, listener)
+		}
+	}
+	ws.mu.RUnlock()
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Closing the connection of a revoked session",
+			mlog.String("userID", listener.userID),
+			mlog.Stringer("client", listener.conn.RemoteAddr()),
+		)
+		listener.conn.Close()
+	}
+}
+
 // getListenersForBlock returns the listeners subscribed to a
 // block changes.
 func (ws *Server) getListenersForBlock(blockID string) []*websocketSession {
@@ -550,14 +678,23 @@ func (ws *Server) BroadcastBlockChange(teamID string, block *model.Block) {
 		)
 	}
 
+	now := utils.GetMillis()
+	messageForUser := blockMessageFilter(ws.blockFilter, message)
 	for _, listener := range listeners {
+		// the listeners subscribed with a share link only receive what
+		// it shares, until it expires
+		shareLink := ws.getListenerShareLink(listener, block.BoardID)
+		if shareLink != nil && shareLink.IsExpired(now) {
+			continue
+		}
+
 		ws.logger.Debug("Broadcast block change",
 			mlog.String("teamID", teamID),
 			mlog.String("blockID", block.ID),
 			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
 		)
 
-		err := listener.WriteJSON(message)
+		err := listener.WriteJSON(messageForUser(listener.userID, shareLink))
 		if err != nil {
 			ws.logger.Error("broadcast error", mlog.Err(err))
 			listener.conn.Close()
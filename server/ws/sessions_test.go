@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/auth"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/services/store/mockstore"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockstore.NewMockStore(ctrl)
+	for _, session := range []*model.Session{
+		{ID: "session-1", Token: "token-1", UserID: "user-1", UpdateAt: model.GetMillis()},
+		{ID: "session-2", Token: "token-2", UserID: "user-1", UpdateAt: model.GetMillis()},
+		{ID: "session-3", Token: "token-3", UserID: "user-2", UpdateAt: model.GetMillis()},
+	} {
+		store.EXPECT().GetSession(session.Token, gomock.Any()).Return(session, nil).AnyTimes()
+		store.EXPECT().GetUserByID(session.UserID).Return(&model.User{ID: session.UserID}, nil).AnyTimes()
+	}
+
+	cfg := &config.Configuration{SessionExpireTime: 3600, SessionRefreshTime: 3600}
+	server := NewServer(auth.New(cfg, store, nil), "", false, mlog.CreateConsoleTestLogger(t), nil)
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	defer httpServer.Close()
+
+	connect := func(token string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteJSON(WebsocketCommand{Action: websocketActionAuth, Token: token}))
+		return conn
+	}
+	isClosed := func(conn *websocket.Conn) bool {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+		_, _, err := conn.ReadMessage()
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return false
+		}
+		return err != nil
+	}
+	countAuthenticated := func() int {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+		count := 0
+		for listener := range server.listeners {
+			if listener.isAuthenticated() {
+				count++
+			}
+		}
+		return count
+	}
+
+	conn1 := connect("token-1")
+	defer conn1.Close()
+	conn2 := connect("token-2")
+	defer conn2.Close()
+	conn3 := connect("token-3")
+	defer conn3.Close()
+	require.Eventually(t, func() bool { return countAuthenticated() == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("the connections of a revoked session are closed", func(t *testing.T) {
+		server.CloseSessions("session-1")
+
+		require.True(t, isClosed(conn1))
+		require.Eventually(t, func() bool { return countAuthenticated() == 2 }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("the connections of a user are closed", func(t *testing.T) {
+		server.CloseSessionsForUser("user-1")
+
+		require.True(t, isClosed(conn2))
+		require.False(t, isClosed(conn3))
+	})
+}
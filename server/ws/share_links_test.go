@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/auth"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/services/store/mockstore"
+	wsMocks "github.com/mattermost/focalboard/server/ws/mocks"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeShareLinkFilter shares the blocks of the views of the share links,
+// and every block with the links that aren't restricted to a view.
+type fakeShareLinkFilter struct {
+	viewBlockIDs map[string]map[string]bool
+}
+
+func (f *fakeShareLinkFilter) NewBlockFilter(block *model.Block) func(userID string) *model.Block {
+	return func(string) *model.Block { return block }
+}
+
+func (f *fakeShareLinkFilter) NewShareLinkBlockFilter(boardID string) func(link *model.ShareLink, block *model.Block) *model.Block {
+	return func(link *model.ShareLink, block *model.Block) *model.Block {
+		if link.ViewID != "" && !f.viewBlockIDs[link.ViewID][block.ID] {
+			return nil
+		}
+		return block
+	}
+}
+
+func TestShareLinkBlocksSubscription(t *testing.T) {
+	const (
+		teamID    = "team-id"
+		boardID   = "board-id"
+		readToken = "link-token"
+	)
+	cardInView := &model.Block{ID: "card-in-view", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, Title: "in view"}
+	cardOutOfView := &model.Block{ID: "card-out-of-view", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, Title: "out of view"}
+
+	ctrl := gomock.NewController(t)
+	store := mockstore.NewMockStore(ctrl)
+	store.EXPECT().GetShareLinkByToken(readToken).Return(&model.ShareLink{ID: "link-id", BoardID: boardID, Token: readToken, ViewID: "view-id"}, nil).AnyTimes()
+	wsStore := wsMocks.NewMockStore(ctrl)
+	for _, card := range []*model.Block{cardInView, cardOutOfView} {
+		wsStore.EXPECT().GetBlock(card.ID).Return(card, nil).AnyTimes()
+	}
+	wsStore.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+	cfg := &config.Configuration{EnablePublicSharedBoards: true}
+	server := NewServer(auth.New(cfg, store, nil), "", false, mlog.CreateConsoleTestLogger(t), wsStore)
+	server.SetBlockFilter(&fakeShareLinkFilter{
+		viewBlockIDs: map[string]map[string]bool{"view-id": {cardInView.ID: true}},
+	})
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(WebsocketCommand{
+		Action:    websocketActionSubscribeBlocks,
+		TeamID:    teamID,
+		ReadToken: readToken,
+		BlockIDs:  []string{cardInView.ID, cardOutOfView.ID},
+	}))
+	require.Eventually(t, func() bool {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+		return len(server.listenersByBlock[cardOutOfView.ID]) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	receive := func() *model.Block {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var message UpdateBlockMsg
+		require.NoError(t, conn.ReadJSON(&message))
+		require.Equal(t, websocketActionUpdateBlock, message.Action)
+		return message.Block
+	}
+
+	t.Run("a view limited link doesn't receive the cards out of its view", func(t *testing.T) {
+		server.BroadcastBlockChange(teamID, cardOutOfView)
+
+		block := receive()
+		require.Equal(t, cardOutOfView.ID, block.ID)
+		require.NotZero(t, block.DeleteAt)
+		require.Empty(t, block.Title)
+	})
+
+	t.Run("a view limited link receives the cards of its view", func(t *testing.T) {
+		server.BroadcastBlockChange(teamID, cardInView)
+
+		block := receive()
+		require.Equal(t, cardInView.ID, block.ID)
+		require.Zero(t, block.DeleteAt)
+		require.Equal(t, cardInView.Title, block.Title)
+	})
+}
@@ -0,0 +1,204 @@
+package ws
+
+import (
+	"sync"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// SetTextEditor sets the editor that applies the collaborative text
+// operations received by the server.
+func (ws *Server) SetTextEditor(textEditor TextEditor) {
+	ws.textEditorMu.Lock()
+	defer ws.textEditorMu.Unlock()
+	ws.textEditor = textEditor
+}
+
+func (ws *Server) getTextEditor() TextEditor {
+	ws.textEditorMu.RLock()
+	defer ws.textEditorMu.RUnlock()
+	return ws.textEditor
+}
+
+// textBlockLock serialises the subscriptions and the operations of a
+// text block, so the subscribers receive every operation applied after
+// the state they were sent, in order.
+type textBlockLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockTextBlock locks a text block and returns the function that
+// unlocks it. The lock is discarded once nobody holds or waits for it.
+func (ws *Server) lockTextBlock(blockID string) func() {
+	ws.textBlockLocksMu.Lock()
+	lock, ok := ws.textBlockLocks[blockID]
+	if !ok {
+		lock = &textBlockLock{}
+		ws.textBlockLocks[blockID] = lock
+	}
+	lock.refs++
+	ws.textBlockLocksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		ws.textBlockLocksMu.Lock()
+		defer ws.textBlockLocksMu.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(ws.textBlockLocks, blockID)
+		}
+	}
+}
+
+// subscribeListenerToText subscribes the listener to the changes of a
+// text block and sends it the current state of the text, so it can
+// start sending operations based on its revision.
+func (ws *Server) subscribeListenerToText(listener *websocketSession, command WebsocketCommand) {
+	textEditor := ws.getTextEditor()
+	if textEditor == nil {
+		ws.logger.Error("Text editing is not available", mlog.String("blockID", command.BlockID))
+		return
+	}
+
+	block, err := ws.store.GetBlock(command.BlockID)
+	if err != nil {
+		ws.rejectTextOperation(listener, command, err)
+		return
+	}
+
+	if !textEditor.HasPermissionToBoard(listener.userID, block.BoardID, model.PermissionViewBoard) {
+		ws.rejectTextOperation(listener, command, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	if !textEditor.CanViewBlock(listener.userID, block) {
+		ws.rejectTextOperation(listener, command, model.NewErrPermission("access denied to private card"))
+		return
+	}
+
+	// text operations are applied and broadcast holding this lock, so
+	// the listener will receive all the operations after the state
+	unlock := ws.lockTextBlock(command.BlockID)
+	defer unlock()
+
+	state, err := textEditor.GetTextEditState(command.BlockID)
+	if err != nil {
+		ws.rejectTextOperation(listener, command, err)
+		return
+	}
+
+	ws.subscribeListenerToBlocks(listener, []string{command.BlockID})
+
+	message := TextStateMsg{
+		Action: websocketActionTextState,
+		TeamID: command.TeamID,
+		State:  state,
+	}
+	if err := listener.WriteJSON(message); err != nil {
+		ws.logger.Error("send text state error", mlog.Err(err))
+		listener.conn.Close()
+	}
+}
+
+// applyTextOperation applies a text operation sent by the listener,
+// acknowledges it and broadcasts the transformed operation to the
+// rest of the block subscribers.
+func (ws *Server) applyTextOperation(listener *websocketSession, command WebsocketCommand) {
+	textEditor := ws.getTextEditor()
+	if textEditor == nil {
+		ws.logger.Error("Text editing is not available", mlog.String("blockID", command.BlockID))
+		return
+	}
+
+	if command.Operation == nil {
+		ws.rejectTextOperation(listener, command, model.NewErrBadRequest("missing text operation"))
+		return
+	}
+
+	block, err := ws.store.GetBlock(command.BlockID)
+	if err != nil {
+		ws.rejectTextOperation(listener, command, err)
+		return
+	}
+
+	if !textEditor.HasPermissionToBoard(listener.userID, block.BoardID, model.PermissionManageBoardCards) {
+		ws.rejectTextOperation(listener, command, model.NewErrPermission("access denied to make board changes"))
+		return
+	}
+
+	if !textEditor.CanViewBlock(listener.userID, block) {
+		ws.rejectTextOperation(listener, command, model.NewErrPermission("access denied to private card"))
+		return
+	}
+
+	unlock := ws.lockTextBlock(command.BlockID)
+	defer unlock()
+
+	change, err := textEditor.ApplyTextOperation(command.BlockID, command.Revision, command.Operation, listener.userID)
+	if err != nil {
+		ws.rejectTextOperation(listener, command, err)
+		return
+	}
+
+	ack := TextOperationMsg{
+		Action: websocketActionAckTextOperation,
+		TeamID: command.TeamID,
+		Change: change,
+	}
+	if err := listener.WriteJSON(ack); err != nil {
+		ws.logger.Error("text operation ack error", mlog.Err(err))
+		listener.conn.Close()
+	}
+
+	message := TextOperationMsg{
+		Action: websocketActionTextOperation,
+		TeamID: command.TeamID,
+		Change: change,
+	}
+
+	ws.mu.RLock()
+	listeners := append([]*websocketSession{}, ws.getListenersForBlock(command.BlockID)...)
+	ws.mu.RUnlock()
+
+	// the subscribers that can't see the block anymore, as its card
+	// became private, don't receive its changes
+	filterBlock := func(string) *model.Block { return block }
+	if ws.blockFilter != nil {
+		filterBlock = ws.blockFilter.NewBlockFilter(block)
+	}
+
+	for _, l := range listeners {
+		if l == listener || filterBlock(l.userID) == nil {
+			continue
+		}
+
+		if err := l.WriteJSON(message); err != nil {
+			ws.logger.Error("broadcast text operation error", mlog.Err(err))
+			l.conn.Close()
+		}
+	}
+}
+
+func (ws *Server) rejectTextOperation(listener *websocketSession, command WebsocketCommand, reason error) {
+	ws.logger.Debug("Rejected text operation",
+		mlog.String("blockID", command.BlockID),
+		mlog.String("userID", listener.userID),
+		mlog.Err(reason),
+	)
+
+	message := RejectTextOperationMsg{
+		Action:  websocketActionRejectTextOperation,
+		TeamID:  command.TeamID,
+		BlockID: command.BlockID,
+		Error:   reason.Error(),
+	}
+	if err := listener.WriteJSON(message); err != nil {
+		ws.logger.Error("reject text operation error", mlog.Err(err))
+		listener.conn.Close()
+	}
+}
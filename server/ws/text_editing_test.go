@@ -0,0 +1,148 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/auth"
+	"github.com/mattermost/focalboard/server/model"
+	wsMocks "github.com/mattermost/focalboard/server/ws/mocks"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTextEditor struct {
+	mu             sync.Mutex
+	hiddenBlockIDs map[string]bool
+	appliedCount   int
+}
+
+func (e *fakeTextEditor) HasPermissionToBoard(userID, boardID string, permission *mmModel.Permission) bool {
+	return true
+}
+
+func (e *fakeTextEditor) CanViewBlock(userID string, block *model.Block) bool {
+	return !e.hiddenBlockIDs[block.ID]
+}
+
+func (e *fakeTextEditor) GetTextEditState(blockID string) (*model.TextEditState, error) {
+	return &model.TextEditState{BlockID: blockID, BoardID: "board-id", Revision: 1, Text: "text"}, nil
+}
+
+func (e *fakeTextEditor) ApplyTextOperation(blockID string, revision int64, op *model.TextOperation, modifiedByID string) (*model.TextChange, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.appliedCount++
+	return &model.TextChange{BlockID: blockID, BoardID: "board-id", Revision: revision + 1, Operation: op, ModifiedBy: modifiedByID}, nil
+}
+
+func TestTextEditingAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := wsMocks.NewMockStore(ctrl)
+	store.EXPECT().GetBlock("private-block").Return(&model.Block{ID: "private-block", BoardID: "board-id", Type: model.TypeText}, nil).AnyTimes()
+	store.EXPECT().GetBlock("public-block").Return(&model.Block{ID: "public-block", BoardID: "board-id", Type: model.TypeText}, nil).AnyTimes()
+
+	textEditor := &fakeTextEditor{hiddenBlockIDs: map[string]bool{"private-block": true}}
+	server := NewServer(&auth.Auth{}, "token", false, mlog.CreateConsoleTestLogger(t), store)
+	server.SetTextEditor(textEditor)
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.handleWebSocket))
+	defer httpServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(command WebsocketCommand) {
+		require.NoError(t, conn.WriteJSON(command))
+	}
+	receive := func() map[string]interface{} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var message map[string]interface{}
+		require.NoError(t, conn.ReadJSON(&message))
+		return message
+	}
+
+	send(WebsocketCommand{Action: websocketActionAuth, Token: "token"})
+
+	t.Run("the state of a hidden block isn't sent", func(t *testing.T) {
+		send(WebsocketCommand{Action: websocketActionSubscribeText, TeamID: "team-id", BlockID: "private-block"})
+
+		message := receive()
+		require.Equal(t, websocketActionRejectTextOperation, message["action"])
+		require.Equal(t, "private-block", message["blockId"])
+		require.Empty(t, server.getListenersForBlock("private-block"))
+	})
+
+	t.Run("the operations on a hidden block are rejected", func(t *testing.T) {
+		send(WebsocketCommand{
+			Action:    websocketActionTextOperation,
+			TeamID:    "team-id",
+			BlockID:   "private-block",
+			Revision:  1,
+			Operation: model.NewTextOperation().Retain(4).Insert("!"),
+		})
+
+		message := receive()
+		require.Equal(t, websocketActionRejectTextOperation, message["action"])
+		require.Zero(t, textEditor.appliedCount)
+	})
+
+	t.Run("the state of a visible block is sent", func(t *testing.T) {
+		send(WebsocketCommand{Action: websocketActionSubscribeText, TeamID: "team-id", BlockID: "public-block"})
+
+		message := receive()
+		require.Equal(t, websocketActionTextState, message["action"])
+		state, _ := message["state"].(map[string]interface{})
+		require.Equal(t, "public-block", state["blockId"])
+	})
+}
+
+func TestLockTextBlock(t *testing.T) {
+	server := NewServer(&auth.Auth{}, "token", false, mlog.CreateConsoleTestLogger(t), nil)
+
+	t.Run("different blocks don't block each other", func(t *testing.T) {
+		unlock1 := server.lockTextBlock("block-1")
+		unlock2 := server.lockTextBlock("block-2")
+		unlock2()
+		unlock1()
+	})
+
+	t.Run("the same block is serialised", func(t *testing.T) {
+		unlock := server.lockTextBlock("block-1")
+
+		locked := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			unlock := server.lockTextBlock("block-1")
+			close(locked)
+			unlock()
+			close(done)
+		}()
+
+		select {
+		case <-locked:
+			require.Fail(t, "the block was locked twice")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		unlock()
+		<-locked
+		<-done
+	})
+
+	t.Run("the locks are discarded once released", func(t *testing.T) {
+		server.textBlockLocksMu.Lock()
+		defer server.textBlockLocksMu.Unlock()
+		require.Empty(t, server.textBlockLocks)
+	})
+}
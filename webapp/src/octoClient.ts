@@ -99,6 +99,17 @@ class OctoClient {
         return true
     }
 
+    async unlockAccount(token: string): Promise<boolean> {
+        const path = '/api/v2/login/unlock'
+        const body = JSON.stringify({token})
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+            body,
+        })
+        return response.status === 200
+    }
+
     async getClientConfig(): Promise<ClientConfig | null> {
         const path = '/api/v2/clientConfig'
         const response = await fetch(this.getBaseURL() + path, {
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+import React, {useState} from 'react'
+import {Link, useParams} from 'react-router-dom'
+import {FormattedMessage} from 'react-intl'
+
+import Button from '../widgets/buttons/button'
+import client from '../octoClient'
+import './unlockAccountPage.scss'
+
+// UnlockAccountPage is opened by the link of the lockout emails. The account
+// is only unlocked once the user confirms it, so opening the link, like the
+// mail scanners do, doesn't unlock it.
+const UnlockAccountPage = () => {
+    const {token} = useParams<{token: string}>()
+    const [errorMessage, setErrorMessage] = useState('')
+    const [unlocking, setUnlocking] = useState(false)
+    const [succeeded, setSucceeded] = useState(false)
+
+    const handleUnlock = async (): Promise<void> => {
+        setUnlocking(true)
+        const unlocked = await client.unlockAccount(token)
+        setUnlocking(false)
+        if (unlocked) {
+            setErrorMessage('')
+            setSucceeded(true)
+        } else {
+            setErrorMessage('Invalid or already used unlock link')
+        }
+    }
+
+    return (
+        <div className='UnlockAccountPage'>
+            <form
+                onSubmit={(e: React.FormEvent) => {
+                    e.preventDefault()
+                    handleUnlock()
+                }}
+            >
+                <div className='title'>
+                    <FormattedMessage
+                        id='UnlockAccountPage.title'
+                        defaultMessage='Unlock your account'
+                    />
+                </div>
+                {!succeeded &&
+                    <Button
+                        filled={true}
+                        submit={true}
+                        disabled={unlocking}
+                    >
+                        <FormattedMessage
+                            id='UnlockAccountPage.unlock-button'
+                            defaultMessage='Unlock account'
+                        />
+                    </Button>
+                }
+            </form>
+            {errorMessage &&
+                <div className='error'>
+                    {errorMessage}
+                </div>
+            }
+            {succeeded &&
+                <Link
+                    className='succeeded'
+                    to='/login'
+                >
+                    <FormattedMessage
+                        id='UnlockAccountPage.unlocked'
+                        defaultMessage='Account unlocked, click to log in.'
+                    />
+                </Link>
+            }
+        </div>
+    )
+}
+
+export default React.memo(UnlockAccountPage)
@@ -17,6 +17,7 @@ import ErrorPage from './pages/errorPage'
 import InvitationPage from './pages/invitationPage'
 import LoginPage from './pages/loginPage'
 import RegisterPage from './pages/registerPage'
+import UnlockAccountPage from './pages/unlockAccountPage'
 import {Utils} from './utils'
 import {sendFlashMessage, clearFlashMessages} from './components/flashMessages'
 import octoClient from './octoClient'
@@ -130,6 +131,10 @@ const FocalboardRouter = (props: Props): JSX.Element => {
                     <InvitationPage/>
                 </FBRoute>
 
+                <FBRoute path='/unlock/:token'>
+                    <UnlockAccountPage/>
+                </FBRoute>
+
                 <FBRoute path={['/team/:teamId/new/:channelId']}>
                     <BoardPage new={true}/>
                 </FBRoute>